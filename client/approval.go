@@ -0,0 +1,133 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Approver lets the user review requests before the client acts on them.
+// Each method receives the full request and returns the request to go ahead
+// with, possibly modified, or an error to reject it.
+type Approver interface {
+	// ApproveSampling is called for each sampling request of the server
+	// before it reaches the sampling handler. A rejection is answered with
+	// a USER_REJECTED error.
+	ApproveSampling(ctx context.Context, request mcp.CreateMessageRequest) (mcp.CreateMessageRequest, error)
+	// ApproveToolCall is called before CallTool calls a destructive tool.
+	// tool is the tool as last listed by the server, or only its name if the
+	// client has not listed it. A rejection is returned by CallTool without
+	// contacting the server.
+	ApproveToolCall(ctx context.Context, tool mcp.Tool, request mcp.CallToolRequest) (mcp.CallToolRequest, error)
+}
+
+// ApprovalFuncs adapts functions to the Approver interface. A nil function
+// approves the requests it would review unchanged.
+type ApprovalFuncs struct {
+	Sampling func(ctx context.Context, request mcp.CreateMessageRequest) (mcp.CreateMessageRequest, error)
+	ToolCall func(ctx context.Context, tool mcp.Tool, request mcp.CallToolRequest) (mcp.CallToolRequest, error)
+}
+
+// ApproveSampling implements Approver.
+func (f ApprovalFuncs) ApproveSampling(ctx context.Context, request mcp.CreateMessageRequest) (mcp.CreateMessageRequest, error) {
+	if f.Sampling == nil {
+		return request, nil
+	}
+	return f.Sampling(ctx, request)
+}
+
+// ApproveToolCall implements Approver.
+func (f ApprovalFuncs) ApproveToolCall(ctx context.Context, tool mcp.Tool, request mcp.CallToolRequest) (mcp.CallToolRequest, error) {
+	if f.ToolCall == nil {
+		return request, nil
+	}
+	return f.ToolCall(ctx, tool, request)
+}
+
+// WithApprover makes the client ask approver before running sampling
+// requests and before calling destructive tools. Errors returned by
+// approver are rejections: they match mcp.ErrUserRejected.
+func WithApprover(approver Approver) ClientOption {
+	return func(c *Client) {
+		c.approver = approver
+	}
+}
+
+// IsDestructiveTool reports whether calls to tool need approval. Following
+// the defaults of the tool annotations, a tool is destructive unless it is
+// marked read-only or explicitly not destructive.
+func IsDestructiveTool(tool mcp.Tool) bool {
+	annotations := tool.Annotations
+	if annotations.ReadOnlyHint != nil && *annotations.ReadOnlyHint {
+		return false
+	}
+	return annotations.DestructiveHint == nil || *annotations.DestructiveHint
+}
+
+// knownTools remembers the tools listed by the server, so that tool calls
+// can be reviewed with their annotations.
+type knownTools struct {
+	mu    sync.RWMutex
+	tools map[string]mcp.Tool
+}
+
+func (k *knownTools) add(tools []mcp.Tool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.tools == nil {
+		k.tools = make(map[string]mcp.Tool, len(tools))
+	}
+	for _, tool := range tools {
+		k.tools[tool.Name] = tool
+	}
+}
+
+func (k *knownTools) get(name string) mcp.Tool {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if tool, ok := k.tools[name]; ok {
+		return tool
+	}
+	return mcp.Tool{Name: name}
+}
+
+// userRejection returns err as a rejection matching mcp.ErrUserRejected.
+func userRejection(err error) error {
+	if errors.Is(err, mcp.ErrUserRejected) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", mcp.ErrUserRejected, err)
+}
+
+// approveSampling asks the approver about a sampling request. If the user
+// rejects it, it returns the response to send instead.
+func (c *Client) approveSampling(ctx context.Context, id mcp.RequestID, request mcp.CreateMessageRequest) (mcp.CreateMessageRequest, *transport.JSONRPCResponse) {
+	if c.approver == nil {
+		return request, nil
+	}
+	approved, err := c.approver.ApproveSampling(ctx, request)
+	if err != nil {
+		return request, transport.NewJSONRPCErrorResponse(id, mcp.USER_REJECTED, userRejection(err).Error(), nil)
+	}
+	return approved, nil
+}
+
+// approveToolCall asks the approver about a call to a destructive tool.
+func (c *Client) approveToolCall(ctx context.Context, request mcp.CallToolRequest) (mcp.CallToolRequest, error) {
+	if c.approver == nil {
+		return request, nil
+	}
+	tool := c.knownTools.get(request.Params.Name)
+	if !IsDestructiveTool(tool) {
+		return request, nil
+	}
+	approved, err := c.approver.ApproveToolCall(ctx, tool, request)
+	if err != nil {
+		return request, userRejection(err)
+	}
+	return approved, nil
+}
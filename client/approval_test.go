@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestIsDestructiveTool(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations mcp.ToolAnnotation
+		want        bool
+	}{
+		{name: "no annotations", want: true},
+		{name: "read-only", annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)}, want: false},
+		{name: "not read-only", annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(false)}, want: true},
+		{name: "not destructive", annotations: mcp.ToolAnnotation{DestructiveHint: mcp.ToBoolPtr(false)}, want: false},
+		{name: "read-only wins", annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true), DestructiveHint: mcp.ToBoolPtr(true)}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsDestructiveTool(mcp.Tool{Name: "tool", Annotations: tt.annotations}))
+		})
+	}
+}
+
+func TestClient_WithApprover_Sampling(t *testing.T) {
+	handler := &recordingSamplingHandler{}
+	approved := 0
+	c := NewClient(nil, WithSamplingHandler(handler), WithApprover(ApprovalFuncs{
+		Sampling: func(ctx context.Context, request mcp.CreateMessageRequest) (mcp.CreateMessageRequest, error) {
+			if approved > 0 {
+				return request, errors.New("too many requests")
+			}
+			approved++
+			request.MaxTokens = 5
+			return request, nil
+		},
+	}))
+
+	response, err := c.handleIncomingRequest(context.Background(), samplingRequest(1))
+	require.NoError(t, err)
+	assert.Nil(t, response.Error)
+	require.Len(t, handler.requests, 1)
+	assert.Equal(t, 5, handler.requests[0].MaxTokens, "the handler receives the modified request")
+
+	response, err = c.handleIncomingRequest(context.Background(), samplingRequest(2))
+	require.NoError(t, err)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, mcp.USER_REJECTED, response.Error.Code)
+	assert.Equal(t, "user rejected request: too many requests", response.Error.Message)
+	assert.Len(t, handler.requests, 1)
+}
+
+// recordingSamplingHandler records the sampling requests it answers.
+type recordingSamplingHandler struct {
+	requests []mcp.CreateMessageRequest
+}
+
+func (h *recordingSamplingHandler) CreateMessage(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	h.requests = append(h.requests, request)
+	return &mcp.CreateMessageResult{
+		SamplingMessage: mcp.SamplingMessage{Role: mcp.RoleAssistant, Content: mcp.NewTextContent("ok")},
+		Model:           "test-model",
+	}, nil
+}
+
+func TestClient_WithApprover_ToolCalls(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0", server.WithToolCapabilities(false))
+	var called []string
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = append(called, request.Params.Name+":"+request.GetString("path", ""))
+		return mcp.NewToolResultText("done"), nil
+	}
+	mcpServer.AddTool(mcp.NewTool("read", mcp.WithReadOnlyHintAnnotation(true)), handler)
+	mcpServer.AddTool(mcp.NewTool("delete"), handler)
+
+	var reviewed []mcp.Tool
+	approver := ApprovalFuncs{
+		ToolCall: func(ctx context.Context, tool mcp.Tool, request mcp.CallToolRequest) (mcp.CallToolRequest, error) {
+			reviewed = append(reviewed, tool)
+			if request.GetString("path", "") == "/" {
+				return request, errors.New("refusing to delete /")
+			}
+			request.Params.Arguments = map[string]any{"path": "/tmp/safe"}
+			return request, nil
+		},
+	}
+	client, _ := newListCacheClient(t, mcpServer, WithApprover(approver))
+
+	call := func(name, path string) error {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = name
+		request.Params.Arguments = map[string]any{"path": path}
+		_, err := client.CallTool(context.Background(), request)
+		return err
+	}
+
+	// Before listing, every tool is treated as destructive.
+	require.NoError(t, call("read", "/a"))
+	require.Len(t, reviewed, 1)
+	assert.Equal(t, mcp.Tool{Name: "read"}, reviewed[0])
+
+	_, err := client.ListTools(context.Background(), mcp.ListToolsRequest{})
+	require.NoError(t, err)
+	reviewed = nil
+	called = nil
+
+	require.NoError(t, call("read", "/a"))
+	assert.Empty(t, reviewed, "read-only tools are not reviewed")
+
+	require.NoError(t, call("delete", "/tmp/x"))
+	require.Len(t, reviewed, 1)
+	assert.Equal(t, "delete", reviewed[0].Name)
+	assert.True(t, *reviewed[0].Annotations.DestructiveHint)
+
+	err = call("delete", "/")
+	assert.ErrorIs(t, err, mcp.ErrUserRejected)
+	assert.EqualError(t, err, "user rejected request: refusing to delete /")
+	assert.Equal(t, []string{"read:/a", "delete:/tmp/safe"}, called)
+}
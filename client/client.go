@@ -11,6 +11,7 @@ import (
 
 	"github.com/mark3labs/mcp-go/client/transport"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/tracing"
 )
 
 // Client implements the MCP client.
@@ -18,7 +19,6 @@ type Client struct {
 	transport transport.Interface
 
 	initialized        bool
-	notifications      []func(mcp.JSONRPCNotification)
 	notifyMu           sync.RWMutex
 	requestID          atomic.Int64
 	clientCapabilities mcp.ClientCapabilities
@@ -27,6 +27,40 @@ type Client struct {
 	samplingHandler    SamplingHandler
 	rootsHandler       RootsHandler
 	elicitationHandler ElicitationHandler
+
+	// samplingLimiter bounds the sampling requests of the server and
+	// sharedSamplingLimiter those of all the servers sharing it.
+	samplingLimiter       *SamplingLimiter
+	sharedSamplingLimiter *SamplingLimiter
+
+	// approver reviews sampling requests and calls to the destructive
+	// tools found in knownTools, and toolRetryPolicy retries calls to the
+	// idempotent ones.
+	approver        Approver
+	toolRetryPolicy *ToolRetryPolicy
+	knownTools      knownTools
+
+	// outputSchemas validate the structured content of tool results.
+	outputSchemas outputSchemas
+
+	// notifications are registered by OnNotification and
+	// OnNotificationMethod, guarded by notifyMu.
+	notifications            []notificationHandler
+	notificationHandlerID    uint64
+	notificationErrorHandler func(notification mcp.JSONRPCNotification, err error)
+	tracer                   tracing.Tracer
+	tracePropagator          tracing.Propagator
+	requestInterceptors      []RequestInterceptor
+	health                   *healthMonitor
+	rootsWatcher             *rootsWatcher
+	requestTimeouts          *requestTimeouts
+	listCache                *listCache
+	resourceCache            *resourceCache
+
+	// requestNotifications maps progress tokens to calls that stream their
+	// notifications to a WithRequestNotifications handler.
+	requestNotifications sync.Map
+	progressTokenID      atomic.Int64
 }
 
 type ClientOption func(*Client)
@@ -104,11 +138,9 @@ func (c *Client) Start(ctx context.Context) error {
 	}
 
 	c.transport.SetNotificationHandler(func(notification mcp.JSONRPCNotification) {
-		c.notifyMu.RLock()
-		defer c.notifyMu.RUnlock()
-		for _, handler := range c.notifications {
-			handler(notification)
-		}
+		c.routeRequestNotification(notification)
+		c.listCache.handleNotification(notification)
+		c.dispatchNotification(notification)
 	})
 
 	// Set up request handler for bidirectional communication (e.g., sampling)
@@ -121,17 +153,19 @@ func (c *Client) Start(ctx context.Context) error {
 
 // Close shuts down the client and closes the transport.
 func (c *Client) Close() error {
+	c.health.close()
+	c.rootsWatcher.close()
 	return c.transport.Close()
 }
 
 // OnNotification registers a handler function to be called when notifications are received.
 // Multiple handlers can be registered and will be called in the order they were added.
+// It is equivalent to OnNotificationMethod("*", handler); use OnNotificationMethod to
+// handle a single method.
 func (c *Client) OnNotification(
 	handler func(notification mcp.JSONRPCNotification),
 ) {
-	c.notifyMu.Lock()
-	defer c.notifyMu.Unlock()
-	c.notifications = append(c.notifications, handler)
+	c.OnNotificationMethod("*", handler)
 }
 
 // OnConnectionLost registers a handler function to be called when the connection is lost.
@@ -152,28 +186,34 @@ func (c *Client) sendRequest(
 	method string,
 	params any,
 	header http.Header,
-) (*json.RawMessage, error) {
+) (_ *json.RawMessage, err error) {
 	if !c.initialized && method != "initialize" {
 		return nil, fmt.Errorf("client not initialized")
 	}
 
 	id := c.requestID.Add(1)
 
+	ctx, params, endSpan := c.startRequestSpan(ctx, method, id, params)
+	defer func() { endSpan(err) }()
+
+	ctx, cancel := c.withRequestTimeout(ctx, method)
+	defer cancel()
+
 	request := transport.JSONRPCRequest{
 		JSONRPC: mcp.JSONRPC_VERSION,
-		ID:      mcp.NewRequestId(id),
+		ID:      mcp.NewRequestID(id),
 		Method:  method,
 		Params:  params,
 		Header:  header,
 	}
 
-	response, err := c.transport.SendRequest(ctx, request)
+	response, err := c.invoker()(ctx, request)
 	if err != nil {
-		return nil, transport.NewError(err)
+		return nil, transport.NewError(requestTimeoutError(ctx, err))
 	}
 
 	if response.Error != nil {
-		return nil, response.Error.AsError()
+		return nil, response.Error.ToError()
 	}
 
 	return &response.Result, nil
@@ -207,10 +247,12 @@ func (c *Client) Initialize(
 		ProtocolVersion string                 `json:"protocolVersion"`
 		ClientInfo      mcp.Implementation     `json:"clientInfo"`
 		Capabilities    mcp.ClientCapabilities `json:"capabilities"`
+		Meta            *mcp.Meta              `json:"_meta,omitempty"`
 	}{
 		ProtocolVersion: request.Params.ProtocolVersion,
 		ClientInfo:      request.Params.ClientInfo,
 		Capabilities:    capabilities,
+		Meta:            request.Params.Meta,
 	}
 
 	response, err := c.sendRequest(ctx, "initialize", params, request.Header)
@@ -231,6 +273,7 @@ func (c *Client) Initialize(
 	// Store serverCapabilities and protocol version
 	c.serverCapabilities = result.Capabilities
 	c.protocolVersion = result.ProtocolVersion
+	c.listCache.invalidateAll()
 
 	// Set protocol version on HTTP transports
 	if httpConn, ok := c.transport.(transport.HTTPConnection); ok {
@@ -238,14 +281,7 @@ func (c *Client) Initialize(
 	}
 
 	// Send initialized notification
-	notification := mcp.JSONRPCNotification{
-		JSONRPC: mcp.JSONRPC_VERSION,
-		Notification: mcp.Notification{
-			Method: "notifications/initialized",
-		},
-	}
-
-	err = c.transport.SendNotification(ctx, notification)
+	err = c.transport.SendNotification(ctx, mcp.NewInitializedNotification().ToJSONRPCNotification())
 	if err != nil {
 		return nil, fmt.Errorf(
 			"failed to send initialized notification: %w",
@@ -254,6 +290,8 @@ func (c *Client) Initialize(
 	}
 
 	c.initialized = true
+	c.health.start(c)
+	c.rootsWatcher.start(c)
 	return &result, nil
 }
 
@@ -338,6 +376,16 @@ func (c *Client) ListResourceTemplates(
 func (c *Client) ReadResource(
 	ctx context.Context,
 	request mcp.ReadResourceRequest,
+) (*mcp.ReadResourceResult, error) {
+	if c.resourceCache != nil && mcp.ConditionalReadValidators(request).IsZero() {
+		return c.readCachedResource(ctx, request)
+	}
+	return c.readResource(ctx, request)
+}
+
+func (c *Client) readResource(
+	ctx context.Context,
+	request mcp.ReadResourceRequest,
 ) (*mcp.ReadResourceResult, error) {
 	response, err := c.sendRequest(ctx, "resources/read", request.Params, request.Header)
 	if err != nil {
@@ -419,6 +467,10 @@ func (c *Client) ListToolsByPage(
 	if err != nil {
 		return nil, err
 	}
+	if c.approver != nil || c.toolRetryPolicy != nil {
+		c.knownTools.add(result.Tools)
+	}
+	c.outputSchemas.addListed(result.Tools)
 	return result, nil
 }
 
@@ -447,16 +499,58 @@ func (c *Client) ListTools(
 	return result, nil
 }
 
+// CallTool invokes a tool on the server and waits for its result.
+// Structured content not conforming to the output schema of the tool is
+// reported as an *OutputSchemaError; see WithOutputSchemaValidation.
+// Failed calls are retried according to WithToolRetryPolicy.
+// Use WithRequestNotifications on ctx to observe the call's progress and
+// log notifications while it runs.
 func (c *Client) CallTool(
 	ctx context.Context,
 	request mcp.CallToolRequest,
 ) (*mcp.CallToolResult, error) {
-	response, err := c.sendRequest(ctx, "tools/call", request.Params, request.Header)
+	request, err := c.approveToolCall(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, meta, done := c.trackRequestNotifications(ctx, request.Params.Meta)
+	defer done()
+	request.Params.Meta = meta
+
+	response, err := c.sendToolCall(ctx, request)
 	if err != nil {
 		return nil, err
 	}
 
-	return mcp.ParseCallToolResult(response)
+	result, err := mcp.ParseCallToolResult(response)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.outputSchemas.validate(request.Params.Name, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ValidateToolCall asks the server whether the given tool call would be
+// accepted, without running the tool. It uses the mcp-go tools/validate
+// extension, so servers built with other SDKs answer with a method not
+// found error.
+func (c *Client) ValidateToolCall(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.ValidateToolResult, error) {
+	response, err := c.sendRequest(ctx, string(mcp.MethodToolsValidate), request.Params, request.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	var result mcp.ValidateToolResult
+	if err := json.Unmarshal(*response, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return &result, nil
 }
 
 func (c *Client) SetLevel(
@@ -488,15 +582,7 @@ func (c *Client) Complete(
 func (c *Client) RootListChanges(
 	ctx context.Context,
 ) error {
-	// Send root list changes notification
-	notification := mcp.JSONRPCNotification{
-		JSONRPC: mcp.JSONRPC_VERSION,
-		Notification: mcp.Notification{
-			Method: mcp.MethodNotificationRootsListChanged,
-		},
-	}
-
-	err := c.transport.SendNotification(ctx, notification)
+	err := c.transport.SendNotification(ctx, mcp.NewRootsListChangedNotification().ToJSONRPCNotification())
 	if err != nil {
 		return fmt.Errorf(
 			"failed to send root list change notification: %w",
@@ -562,6 +648,20 @@ func (c *Client) handleSamplingRequestTransport(ctx context.Context, request tra
 		CreateMessageParams: params,
 	}
 
+	release, rejected, err := c.acquireSampling(ctx, request.ID)
+	if err != nil {
+		return nil, err
+	}
+	if rejected != nil {
+		return rejected, nil
+	}
+	defer release()
+
+	mcpRequest, rejected = c.approveSampling(ctx, request.ID, mcpRequest)
+	if rejected != nil {
+		return rejected, nil
+	}
+
 	// Call the sampling handler
 	result, err := c.samplingHandler.CreateMessage(ctx, mcpRequest)
 	if err != nil {
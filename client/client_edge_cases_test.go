@@ -114,15 +114,7 @@ func TestClient_NotificationHandlers(t *testing.T) {
 		}
 
 		// Manually trigger the handlers we registered on the client
-		// Access them through the read lock
-		client.notifyMu.RLock()
-		handlers := make([]func(mcp.JSONRPCNotification), len(client.notifications))
-		copy(handlers, client.notifications)
-		client.notifyMu.RUnlock()
-
-		for _, h := range handlers {
-			h(notif)
-		}
+		client.dispatchNotification(notif)
 
 		// Wait a bit for handlers to execute
 		time.Sleep(50 * time.Millisecond)
@@ -0,0 +1,204 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/util"
+)
+
+// FSRoots is a RootsHandler serving local directories as the roots of a
+// client. A directory is listed while it exists, so that roots appear and
+// disappear as their directories are created and deleted.
+//
+// Used with WithFSRoots, the client watches the directories once it is
+// initialized and sends notifications/roots/list_changed whenever the roots
+// change, whether through Add and Remove or on disk. An FSRoots may be
+// shared by several clients, and is safe for concurrent use. The zero value
+// serves no roots.
+type FSRoots struct {
+	mu sync.Mutex
+	// dirs are the absolute paths of the directories, in the order they
+	// were added, and roots the roots of those that existed when they were
+	// last checked.
+	dirs  []string
+	roots []mcp.Root
+	// changed is closed and replaced whenever roots change.
+	changed chan struct{}
+}
+
+// NewFSRoots returns an FSRoots serving dirs, which are made absolute.
+func NewFSRoots(dirs ...string) (*FSRoots, error) {
+	r := &FSRoots{}
+	for _, dir := range dirs {
+		if err := r.Add(dir); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Add serves dir as a root, named after its base name. Adding a directory
+// that is already served has no effect.
+func (r *FSRoots) Add(dir string) error {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("root %s: %w", dir, err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if slices.Contains(r.dirs, abs) {
+		return nil
+	}
+	r.dirs = append(r.dirs, abs)
+	r.refreshLocked()
+	return nil
+}
+
+// Remove stops serving dir as a root, and reports whether it was served.
+func (r *FSRoots) Remove(dir string) bool {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := slices.Index(r.dirs, abs)
+	if i < 0 {
+		return false
+	}
+	r.dirs = slices.Delete(r.dirs, i, i+1)
+	r.refreshLocked()
+	return true
+}
+
+// Roots returns the roots of the directories that exist.
+func (r *FSRoots) Roots() []mcp.Root {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.refreshLocked()
+	return slices.Clone(r.roots)
+}
+
+// ListRoots implements RootsHandler.
+func (r *FSRoots) ListRoots(ctx context.Context, request mcp.ListRootsRequest) (*mcp.ListRootsResult, error) {
+	return &mcp.ListRootsResult{Roots: r.Roots()}, nil
+}
+
+// Refresh checks which directories exist, and reports whether the roots
+// changed since they were last checked. Clients created with WithFSRoots
+// call it periodically.
+func (r *FSRoots) Refresh() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.refreshLocked()
+}
+
+func (r *FSRoots) refreshLocked() bool {
+	roots := make([]mcp.Root, 0, len(r.dirs))
+	for _, dir := range r.dirs {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			roots = append(roots, mcp.Root{URI: util.FileURI(dir), Name: filepath.Base(dir)})
+		}
+	}
+	if slices.EqualFunc(roots, r.roots, func(a, b mcp.Root) bool { return a.URI == b.URI }) {
+		return false
+	}
+	r.roots = roots
+	if r.changed != nil {
+		close(r.changed)
+	}
+	r.changed = make(chan struct{})
+	return true
+}
+
+// changes returns a channel closed at the next change of the roots.
+func (r *FSRoots) changes() <-chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.changed == nil {
+		r.changed = make(chan struct{})
+	}
+	return r.changed
+}
+
+// rootsNotifyTimeout bounds the sending of a roots list changed
+// notification.
+const rootsNotifyTimeout = 10 * time.Second
+
+// WithFSRoots serves roots as the roots of the client, declaring the roots
+// capability like WithRootsHandler. Once the client is initialized, it
+// checks the directories every interval and sends
+// notifications/roots/list_changed to the server when the roots change. An
+// interval of zero or less turns the checks off: only the changes made
+// through Add, Remove and Refresh are then notified.
+func WithFSRoots(roots *FSRoots, interval time.Duration) ClientOption {
+	return func(c *Client) {
+		c.rootsHandler = roots
+		c.rootsWatcher = &rootsWatcher{
+			roots:    roots,
+			interval: interval,
+			stop:     make(chan struct{}),
+		}
+	}
+}
+
+// rootsWatcher tells the server about changes of the roots of WithFSRoots.
+type rootsWatcher struct {
+	roots    *FSRoots
+	interval time.Duration
+
+	started  sync.Once
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// start starts watching the roots, as the server now knows them.
+func (w *rootsWatcher) start(c *Client) {
+	if w == nil {
+		return
+	}
+	w.started.Do(func() {
+		go w.run(c, w.roots.changes())
+	})
+}
+
+func (w *rootsWatcher) run(c *Client, changed <-chan struct{}) {
+	var tick <-chan time.Time
+	if w.interval > 0 {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-tick:
+			w.roots.Refresh()
+		case <-changed:
+			// Changes made while the notification is sent are reported
+			// by the next one
+			changed = w.roots.changes()
+			ctx, cancel := context.WithTimeout(context.Background(), rootsNotifyTimeout)
+			_ = c.RootListChanges(ctx)
+			cancel()
+		}
+	}
+}
+
+// close stops watching the roots.
+func (w *rootsWatcher) close() {
+	if w == nil {
+		return
+	}
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+}
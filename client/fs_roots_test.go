@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mark3labs/mcp-go/util"
+)
+
+func TestFSRoots(t *testing.T) {
+	base := t.TempDir()
+	project := filepath.Join(base, "project")
+	docs := filepath.Join(base, "docs")
+	require.NoError(t, os.Mkdir(project, 0o755))
+
+	roots, err := NewFSRoots(project, docs)
+	require.NoError(t, err)
+	assert.Equal(t, []mcp.Root{{URI: util.FileURI(project), Name: "project"}}, roots.Roots(),
+		"directories that do not exist are not listed")
+	assert.Equal(t, "file://"+filepath.ToSlash(project), util.FileURI(project))
+
+	require.NoError(t, os.Mkdir(docs, 0o755))
+	assert.True(t, roots.Refresh(), "created directories are listed")
+	assert.False(t, roots.Refresh())
+	result, err := roots.ListRoots(context.Background(), mcp.ListRootsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, []mcp.Root{
+		{URI: util.FileURI(project), Name: "project"},
+		{URI: util.FileURI(docs), Name: "docs"},
+	}, result.Roots)
+
+	require.NoError(t, os.Remove(project))
+	assert.True(t, roots.Refresh(), "deleted directories are no longer listed")
+	assert.Equal(t, []mcp.Root{{URI: util.FileURI(docs), Name: "docs"}}, roots.Roots())
+
+	require.NoError(t, roots.Add(docs))
+	assert.Len(t, roots.Roots(), 1, "adding a directory again has no effect")
+	assert.True(t, roots.Remove(docs))
+	assert.False(t, roots.Remove(docs))
+	assert.Empty(t, roots.Roots())
+
+	var zero FSRoots
+	assert.Empty(t, zero.Roots())
+}
+
+// startFSRootsClient initializes a client serving roots and returns the
+// channel receiving the roots the server lists on every roots/list_changed
+// notification.
+func startFSRootsClient(t *testing.T, roots *FSRoots, interval time.Duration) <-chan []mcp.Root {
+	t.Helper()
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+	listed := make(chan []mcp.Root, 10)
+	mcpServer.AddNotificationHandler(mcp.MethodNotificationRootsListChanged, func(ctx context.Context, notification mcp.JSONRPCNotification) {
+		result, err := mcpServer.RequestRoots(ctx, mcp.ListRootsRequest{})
+		if err != nil {
+			t.Errorf("listing roots: %v", err)
+			return
+		}
+		listed <- result.Roots
+	})
+
+	inProcessTransport := transport.NewInProcessTransportWithOptions(mcpServer, transport.WithRootsHandler(roots))
+	client := NewClient(inProcessTransport, WithFSRoots(roots, interval))
+	require.NoError(t, client.Start(context.Background()))
+	t.Cleanup(func() { client.Close() })
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	_, err := client.Initialize(context.Background(), initRequest)
+	require.NoError(t, err)
+	return listed
+}
+
+// nextRoots returns the next roots received from listed.
+func nextRoots(t *testing.T, listed <-chan []mcp.Root) []mcp.Root {
+	t.Helper()
+	select {
+	case roots := <-listed:
+		return roots
+	case <-time.After(2 * time.Second):
+		t.Fatal("no roots/list_changed notification")
+		return nil
+	}
+}
+
+func TestClient_WithFSRoots(t *testing.T) {
+	base := t.TempDir()
+	project := filepath.Join(base, "project")
+	require.NoError(t, os.Mkdir(project, 0o755))
+	roots, err := NewFSRoots(project)
+	require.NoError(t, err)
+
+	listed := startFSRootsClient(t, roots, 10*time.Millisecond)
+	next := func() []mcp.Root {
+		t.Helper()
+		return nextRoots(t, listed)
+	}
+
+	docs := filepath.Join(base, "docs")
+	require.NoError(t, roots.Add(docs))
+	require.NoError(t, os.Mkdir(docs, 0o755))
+	assert.Equal(t, []mcp.Root{
+		{URI: util.FileURI(project), Name: "project"},
+		{URI: util.FileURI(docs), Name: "docs"},
+	}, next(), "directories created on disk are reported")
+
+	roots.Remove(project)
+	assert.Equal(t, []mcp.Root{{URI: util.FileURI(docs), Name: "docs"}}, next(), "removed directories are reported")
+
+	select {
+	case roots := <-listed:
+		t.Fatalf("unexpected notification, roots %v", roots)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestClient_WithFSRoots_NoPolling(t *testing.T) {
+	base := t.TempDir()
+	project := filepath.Join(base, "project")
+	require.NoError(t, os.Mkdir(project, 0o755))
+	roots, err := NewFSRoots(project)
+	require.NoError(t, err)
+
+	listed := startFSRootsClient(t, roots, 0)
+
+	docs := filepath.Join(base, "docs")
+	require.NoError(t, os.Mkdir(docs, 0o755))
+	require.NoError(t, roots.Add(docs))
+	assert.Len(t, nextRoots(t, listed), 2, "added directories are reported")
+
+	require.NoError(t, os.Remove(project))
+	select {
+	case roots := <-listed:
+		t.Fatalf("unexpected notification, roots %v", roots)
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.True(t, roots.Refresh())
+	assert.Equal(t, []mcp.Root{{URI: util.FileURI(docs), Name: "docs"}}, nextRoots(t, listed),
+		"changes found by Refresh are reported")
+}
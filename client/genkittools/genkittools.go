@@ -0,0 +1,141 @@
+// Package genkittools wraps the tools of an MCP server for Firebase Genkit,
+// so that Genkit flows can call them:
+//
+//	mcpTools, err := genkittools.New(ctx, c)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	var refs []ai.ToolRef
+//	for _, tool := range mcpTools {
+//	    refs = append(refs, genkit.DefineToolWithInputSchema(g, tool.Name(), tool.Description(), tool.InputSchema(),
+//	        func(ctx *ai.ToolContext, input any) (any, error) {
+//	            return tool.Run(ctx, input)
+//	        }))
+//	}
+//	resp, err := genkit.Generate(ctx, g, ai.WithPrompt("..."), ai.WithTools(refs...))
+//
+// The package has no dependency on Genkit. InputSchema returns the
+// *jsonschema.Schema of github.com/invopop/jsonschema used by Genkit, and
+// Definition mirrors ai.ToolDefinition for the registries taking one.
+package genkittools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/invopop/jsonschema"
+
+	"github.com/mark3labs/mcp-go/client/llmtools"
+)
+
+// Definition describes a tool to Genkit, like ai.ToolDefinition.
+type Definition struct {
+	Name         string         `json:"name"`
+	Description  string         `json:"description"`
+	InputSchema  map[string]any `json:"inputSchema,omitempty"`
+	OutputSchema map[string]any `json:"outputSchema,omitempty"`
+}
+
+// Tool is an MCP tool to register as a Genkit tool action.
+type Tool struct {
+	tool *llmtools.Tool
+}
+
+// New returns the tools of the server of provider. Options such as
+// llmtools.WithNotifications configure the calls of the tools.
+func New(ctx context.Context, provider llmtools.ToolProvider, opts ...llmtools.Option) ([]*Tool, error) {
+	tools, err := llmtools.Tools(ctx, provider, opts...)
+	if err != nil {
+		return nil, err
+	}
+	wrapped := make([]*Tool, len(tools))
+	for i, tool := range tools {
+		wrapped[i] = NewTool(tool)
+	}
+	return wrapped, nil
+}
+
+// NewTool returns the Genkit tool calling tool.
+func NewTool(tool *llmtools.Tool) *Tool {
+	return &Tool{tool: tool}
+}
+
+// Name returns the name of the MCP tool.
+func (t *Tool) Name() string {
+	return t.tool.MCPTool().Name
+}
+
+// Description returns the description of the MCP tool.
+func (t *Tool) Description() string {
+	return t.tool.MCPTool().Description
+}
+
+// InputSchema returns the JSON Schema of the arguments of the tool.
+func (t *Tool) InputSchema() *jsonschema.Schema {
+	schema := &jsonschema.Schema{}
+	_ = json.Unmarshal(t.tool.InputSchema(), schema)
+	return schema
+}
+
+// Definition returns the definition of the tool, with the output schema of
+// the MCP tool if it has one.
+func (t *Tool) Definition() Definition {
+	definition := Definition{Name: t.Name(), Description: t.Description()}
+	_ = json.Unmarshal(t.tool.InputSchema(), &definition.InputSchema)
+
+	data, err := json.Marshal(t.tool.MCPTool())
+	if err == nil {
+		var encoded struct {
+			OutputSchema map[string]any `json:"outputSchema"`
+		}
+		if json.Unmarshal(data, &encoded) == nil {
+			definition.OutputSchema = encoded.OutputSchema
+		}
+	}
+	return definition
+}
+
+// Run calls the MCP tool with input, the arguments decoded by Genkit, and
+// returns the structured content of its result or else its text. Tool
+// errors are returned as text prefixed with "Error: ", so that the model
+// can recover from them; the returned error is that of the request.
+func (t *Tool) Run(ctx context.Context, input any) (any, error) {
+	arguments, err := t.arguments(input)
+	if err != nil {
+		return nil, err
+	}
+	result, err := t.tool.Call(ctx, arguments)
+	if err != nil {
+		return nil, err
+	}
+	if result.IsError {
+		return "Error: " + llmtools.ResultText(result), nil
+	}
+	if result.StructuredContent != nil {
+		return result.StructuredContent, nil
+	}
+	return llmtools.ResultText(result), nil
+}
+
+// arguments returns the arguments of a call with input.
+func (t *Tool) arguments(input any) (map[string]any, error) {
+	switch input := input.(type) {
+	case nil:
+		return map[string]any{}, nil
+	case map[string]any:
+		return input, nil
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("tool %s: %w", t.Name(), err)
+	}
+	var arguments map[string]any
+	if err := json.Unmarshal(data, &arguments); err != nil {
+		return nil, fmt.Errorf("tool %s: input is not an object of arguments: %w", t.Name(), err)
+	}
+	if arguments == nil {
+		arguments = map[string]any{}
+	}
+	return arguments, nil
+}
@@ -0,0 +1,115 @@
+package genkittools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// fakeProvider lists tools and answers calls with result.
+type fakeProvider struct {
+	tools   []mcp.Tool
+	result  *mcp.CallToolResult
+	request mcp.CallToolRequest
+}
+
+func (p *fakeProvider) ListTools(ctx context.Context, request mcp.ListToolsRequest) (*mcp.ListToolsResult, error) {
+	return &mcp.ListToolsResult{Tools: p.tools}, nil
+}
+
+func (p *fakeProvider) CallTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	p.request = request
+	return p.result, nil
+}
+
+type weatherOutput struct {
+	Temperature float64 `json:"temperature"`
+}
+
+func newWeatherTool(t *testing.T, provider *fakeProvider) *Tool {
+	t.Helper()
+	provider.tools = []mcp.Tool{mcp.NewTool("get_weather",
+		mcp.WithDescription("Get the weather of a city"),
+		mcp.WithString("city", mcp.Required()),
+		mcp.WithOutputSchema[weatherOutput](),
+	)}
+	tools, err := New(context.Background(), provider)
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	return tools[0]
+}
+
+func TestTool_Schemas(t *testing.T) {
+	tool := newWeatherTool(t, &fakeProvider{})
+
+	assert.Equal(t, "get_weather", tool.Name())
+	assert.Equal(t, "Get the weather of a city", tool.Description())
+
+	schema := tool.InputSchema()
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, []string{"city"}, schema.Required)
+	city, ok := schema.Properties.Get("city")
+	require.True(t, ok)
+	assert.Equal(t, "string", city.Type)
+
+	definition := tool.Definition()
+	assert.Equal(t, "get_weather", definition.Name)
+	assert.Equal(t, map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"city": map[string]any{"type": "string"}},
+		"required":   []any{"city"},
+	}, definition.InputSchema)
+	assert.Equal(t, "object", definition.OutputSchema["type"])
+	assert.Contains(t, definition.OutputSchema["properties"], "temperature")
+}
+
+func TestTool_Run(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     any
+		result    *mcp.CallToolResult
+		expected  any
+		arguments map[string]any
+	}{
+		{
+			name:      "map input and text result",
+			input:     map[string]any{"city": "Paris"},
+			result:    mcp.NewToolResultText("sunny"),
+			expected:  "sunny",
+			arguments: map[string]any{"city": "Paris"},
+		},
+		{
+			name:      "struct input and structured result",
+			input:     struct{ City string }{City: "Oslo"},
+			result:    mcp.NewToolResultStructured(map[string]any{"temperature": 4}, `{"temperature":4}`),
+			expected:  map[string]any{"temperature": 4},
+			arguments: map[string]any{"City": "Oslo"},
+		},
+		{
+			name:      "tool error",
+			input:     nil,
+			result:    mcp.NewToolResultError("city is required"),
+			expected:  "Error: city is required",
+			arguments: map[string]any{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &fakeProvider{result: tt.result}
+			tool := newWeatherTool(t, provider)
+
+			output, err := tool.Run(context.Background(), tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, output)
+			assert.Equal(t, "get_weather", provider.request.Params.Name)
+			assert.Equal(t, tt.arguments, provider.request.Params.Arguments)
+		})
+	}
+
+	_, err := newWeatherTool(t, &fakeProvider{}).Run(context.Background(), []string{"Paris"})
+	assert.Error(t, err)
+}
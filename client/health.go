@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// HealthState is the health of the connection to the server as observed by
+// the pings of WithHealthCheck.
+type HealthState int32
+
+const (
+	// HealthUnknown is the state before the client is initialized, or of a
+	// client without WithHealthCheck.
+	HealthUnknown HealthState = iota
+	// HealthHealthy means the server answered the last ping.
+	HealthHealthy
+	// HealthUnhealthy means the server missed the configured number of
+	// consecutive pings, which usually indicates a half-open connection.
+	HealthUnhealthy
+)
+
+// String returns the name of the state.
+func (h HealthState) String() string {
+	switch h {
+	case HealthHealthy:
+		return "healthy"
+	case HealthUnhealthy:
+		return "unhealthy"
+	default:
+		return "unknown"
+	}
+}
+
+// WithHealthCheck pings the server every interval once the client is
+// initialized. The client becomes unhealthy when maxMissed consecutive pings
+// fail or are not answered within interval, and healthy again as soon as a
+// ping is answered. The state is reported by Health and OnHealthChange.
+func WithHealthCheck(interval time.Duration, maxMissed int) ClientOption {
+	return func(c *Client) {
+		c.health = &healthMonitor{
+			interval:  interval,
+			maxMissed: max(maxMissed, 1),
+			stop:      make(chan struct{}),
+		}
+	}
+}
+
+// Health returns the health of the connection to the server. It is
+// HealthUnknown unless WithHealthCheck is used.
+func (c *Client) Health() HealthState {
+	if c.health == nil {
+		return HealthUnknown
+	}
+	return HealthState(c.health.state.Load())
+}
+
+// OnHealthChange registers a handler called with the new state whenever the
+// health of the connection changes. It has no effect without WithHealthCheck.
+func (c *Client) OnHealthChange(handler func(HealthState)) {
+	if c.health == nil {
+		return
+	}
+	c.health.mu.Lock()
+	defer c.health.mu.Unlock()
+	c.health.handlers = append(c.health.handlers, handler)
+}
+
+// healthMonitor pings the server and tracks the health of the connection.
+type healthMonitor struct {
+	interval  time.Duration
+	maxMissed int
+
+	state    atomic.Int32
+	mu       sync.Mutex
+	handlers []func(HealthState)
+
+	started  sync.Once
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// start marks the connection healthy, as the server just answered the
+// initialize request, and starts pinging it.
+func (h *healthMonitor) start(c *Client) {
+	if h == nil {
+		return
+	}
+	h.started.Do(func() {
+		h.set(HealthHealthy)
+		go h.run(c)
+	})
+}
+
+func (h *healthMonitor) run(c *Client) {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+	missed := 0
+	for {
+		select {
+		case <-h.stop:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), h.interval)
+		err := c.Ping(ctx)
+		cancel()
+		select {
+		case <-h.stop:
+			return
+		default:
+		}
+
+		if err == nil {
+			missed = 0
+			h.set(HealthHealthy)
+			continue
+		}
+		missed++
+		if missed >= h.maxMissed {
+			h.set(HealthUnhealthy)
+		}
+	}
+}
+
+// set changes the state and calls the handlers if it differs.
+func (h *healthMonitor) set(state HealthState) {
+	if HealthState(h.state.Swap(int32(state))) == state {
+		return
+	}
+	h.mu.Lock()
+	handlers := append([]func(HealthState){}, h.handlers...)
+	h.mu.Unlock()
+	for _, handler := range handlers {
+		handler(state)
+	}
+}
+
+// close stops the pings.
+func (h *healthMonitor) close() {
+	if h == nil {
+		return
+	}
+	h.stopOnce.Do(func() {
+		close(h.stop)
+	})
+}
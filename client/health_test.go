@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestClient_WithHealthCheck(t *testing.T) {
+	var halfOpen atomic.Bool
+	dropPings := func(next RequestInvoker) RequestInvoker {
+		return func(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+			if halfOpen.Load() && request.Method == string(mcp.MethodPing) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return next(ctx, request)
+		}
+	}
+
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+	client := NewClient(transport.NewInProcessTransport(mcpServer),
+		WithHealthCheck(20*time.Millisecond, 2), WithRequestInterceptor(dropPings))
+	defer client.Close()
+
+	var mu sync.Mutex
+	var changes []HealthState
+	client.OnHealthChange(func(state HealthState) {
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, state)
+	})
+	assert.Equal(t, HealthUnknown, client.Health())
+
+	require.NoError(t, client.Start(context.Background()))
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	_, err := client.Initialize(context.Background(), initRequest)
+	require.NoError(t, err)
+	assert.Equal(t, HealthHealthy, client.Health())
+
+	halfOpen.Store(true)
+	require.Eventually(t, func() bool { return client.Health() == HealthUnhealthy }, time.Second, 5*time.Millisecond)
+	halfOpen.Store(false)
+	require.Eventually(t, func() bool { return client.Health() == HealthHealthy }, time.Second, 5*time.Millisecond)
+
+	mu.Lock()
+	assert.Equal(t, []HealthState{HealthHealthy, HealthUnhealthy, HealthHealthy}, changes)
+	mu.Unlock()
+}
+
+func TestClient_HealthWithoutCheck(t *testing.T) {
+	client := NewClient(transport.NewInProcessTransport(server.NewMCPServer("test-server", "1.0.0")))
+	client.OnHealthChange(func(HealthState) {})
+	assert.Equal(t, HealthUnknown, client.Health())
+	assert.NoError(t, client.Close())
+}
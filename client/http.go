@@ -20,3 +20,15 @@ func NewStreamableHttpClient(baseURL string, options ...transport.StreamableHTTP
 	}
 	return NewClient(trans, clientOptions...), nil
 }
+
+// NewAutoClient creates an MCP client for the server at baseURL that uses the
+// streamable HTTP transport, or falls back to the SSE transport for servers
+// that only support it. The transport is selected by Initialize; see
+// transport.Auto.
+func NewAutoClient(baseURL string, options ...transport.AutoOption) (*Client, error) {
+	trans, err := transport.NewAuto(baseURL, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create auto transport: %w", err)
+	}
+	return NewClient(trans), nil
+}
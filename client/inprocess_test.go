@@ -4,6 +4,9 @@ import (
 	"context"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -419,3 +422,121 @@ func TestInProcessMCPClient(t *testing.T) {
 		}
 	})
 }
+
+func TestInProcessMCPClient_ValidateToolCall(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("echo", mcp.WithString("message", mcp.Required())),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			t.Error("handler must not run during validation")
+			return mcp.NewToolResultText("unexpected"), nil
+		})
+
+	client, err := NewInProcessClient(mcpServer)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "test-client", Version: "1.0.0"}
+	if _, err := client.Initialize(context.Background(), initRequest); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "echo"
+	result, err := client.ValidateToolCall(context.Background(), request)
+	if err != nil {
+		t.Fatalf("ValidateToolCall failed: %v", err)
+	}
+	if result.Valid {
+		t.Errorf("Expected call without required argument to be invalid")
+	}
+	if len(result.Diagnostics) != 1 || result.Diagnostics[0].Path != "/message" {
+		t.Errorf("Expected a single diagnostic for /message, got %+v", result.Diagnostics)
+	}
+
+	request.Params.Arguments = map[string]any{"message": "hi"}
+	result, err = client.ValidateToolCall(context.Background(), request)
+	if err != nil {
+		t.Fatalf("ValidateToolCall failed: %v", err)
+	}
+	if !result.Valid {
+		t.Errorf("Expected valid call, got diagnostics %+v", result.Diagnostics)
+	}
+}
+
+func TestInProcessMCPClient_StructuredErrors(t *testing.T) {
+	type quota struct {
+		Limit int `json:"limit"`
+	}
+
+	mcpServer := server.NewMCPServer("test-server", "1.0.0", server.WithToolCapabilities(true))
+	mcpServer.AddTool(mcp.NewTool("limited"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, mcp.NewError(-31000, "quota exceeded", quota{Limit: 10})
+	})
+
+	client, err := NewInProcessClient(mcpServer)
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Start(context.Background()))
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	_, err = client.Initialize(context.Background(), initRequest)
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "limited"
+	_, err = client.CallTool(context.Background(), request)
+
+	var mcpErr *mcp.Error
+	require.ErrorAs(t, err, &mcpErr)
+	assert.Equal(t, -31000, mcpErr.Code)
+	assert.Equal(t, "quota exceeded", mcpErr.Message)
+	data, ok := mcp.ErrorData[quota](err)
+	require.True(t, ok)
+	assert.Equal(t, quota{Limit: 10}, data)
+
+	request.Params.Name = "missing"
+	_, err = client.CallTool(context.Background(), request)
+	assert.ErrorIs(t, err, mcp.ErrInvalidParams, "sentinel errors still match")
+}
+
+func TestInProcessMCPClient_NotFoundErrors(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0",
+		server.WithToolCapabilities(true), server.WithPromptCapabilities(true))
+
+	client, err := NewInProcessClient(mcpServer)
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Start(context.Background()))
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	_, err = client.Initialize(context.Background(), initRequest)
+	require.NoError(t, err)
+
+	callRequest := mcp.CallToolRequest{}
+	callRequest.Params.Name = "missing"
+	_, err = client.CallTool(context.Background(), callRequest)
+	assert.ErrorIs(t, err, mcp.ErrToolNotFound)
+	assert.NotErrorIs(t, err, mcp.ErrPromptNotFound)
+	data, ok := mcp.ErrorData[mcp.NotFoundErrorData](err)
+	require.True(t, ok)
+	assert.Equal(t, mcp.NotFoundErrorData{Type: "tool", Name: "missing"}, data)
+
+	promptRequest := mcp.GetPromptRequest{}
+	promptRequest.Params.Name = "missing"
+	_, err = client.GetPrompt(context.Background(), promptRequest)
+	assert.ErrorIs(t, err, mcp.ErrPromptNotFound)
+
+	readRequest := mcp.ReadResourceRequest{}
+	readRequest.Params.URI = "file:///missing"
+	_, err = client.ReadResource(context.Background(), readRequest)
+	assert.ErrorIs(t, err, mcp.ErrCapabilityNotNegotiated)
+	assert.ErrorIs(t, err, mcp.ErrMethodNotFound, "the code of the error still matches")
+	assert.NotErrorIs(t, err, mcp.ErrUnknownMethod)
+}
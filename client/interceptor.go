@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+)
+
+// RequestInvoker sends a request to the server and returns its response.
+// Errors are those of the transport; JSON-RPC errors returned by the server
+// are carried by the response.
+type RequestInvoker func(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error)
+
+// RequestInterceptor wraps the sending of requests. An interceptor can
+// change the request before calling next, for example to add headers or
+// _meta fields with SetRequestMeta, call next again to retry transient
+// transport errors, or time the call.
+type RequestInterceptor func(next RequestInvoker) RequestInvoker
+
+// WithRequestInterceptor adds interceptors wrapping every request the client
+// sends, including initialize. The interceptor added first is the outermost
+// one. Interceptors run inside the span started by WithTracing, after the
+// trace context has been added to _meta.
+func WithRequestInterceptor(interceptors ...RequestInterceptor) ClientOption {
+	return func(c *Client) {
+		c.requestInterceptors = append(c.requestInterceptors, interceptors...)
+	}
+}
+
+// invoker returns the transport's SendRequest wrapped by the request
+// interceptors.
+func (c *Client) invoker() RequestInvoker {
+	invoke := RequestInvoker(c.transport.SendRequest)
+	for i := len(c.requestInterceptors) - 1; i >= 0; i-- {
+		invoke = c.requestInterceptors[i](invoke)
+	}
+	return invoke
+}
+
+// SetRequestMeta sets the _meta field key of request to value. The params
+// are replaced by a map copy so the caller's params are left untouched. Like
+// the params of any request, value must marshal to JSON.
+func SetRequestMeta(request *transport.JSONRPCRequest, key string, value any) error {
+	fields := make(map[string]any)
+	if params, ok := request.Params.(map[string]any); ok {
+		maps.Copy(fields, params)
+	} else if request.Params != nil {
+		raw, err := json.Marshal(request.Params)
+		if err != nil {
+			return fmt.Errorf("failed to marshal params: %w", err)
+		}
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return fmt.Errorf("params are not an object: %w", err)
+		}
+	}
+
+	meta := make(map[string]any)
+	if existing, ok := fields["_meta"].(map[string]any); ok {
+		maps.Copy(meta, existing)
+	}
+	meta[key] = value
+	fields["_meta"] = meta
+	request.Params = fields
+	return nil
+}
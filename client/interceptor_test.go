@@ -0,0 +1,159 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestClient_WithRequestInterceptor(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("whoami"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var tenant any
+		if request.Params.Meta != nil {
+			tenant = request.Params.Meta.AdditionalFields["tenant"]
+		}
+		return mcp.NewToolResultText(request.Header.Get("X-Tenant") + " " + tenant.(string)), nil
+	})
+	httpServer := server.NewTestStreamableHTTPServer(mcpServer)
+	defer httpServer.Close()
+
+	var mu sync.Mutex
+	var order []string
+	var latencies []time.Duration
+	record := func(name string) RequestInterceptor {
+		return func(next RequestInvoker) RequestInvoker {
+			return func(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+				mu.Lock()
+				order = append(order, name+" "+request.Method)
+				mu.Unlock()
+				return next(ctx, request)
+			}
+		}
+	}
+	timing := func(next RequestInvoker) RequestInvoker {
+		return func(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+			start := time.Now()
+			defer func() {
+				mu.Lock()
+				latencies = append(latencies, time.Since(start))
+				mu.Unlock()
+			}()
+			return next(ctx, request)
+		}
+	}
+	tenant := func(next RequestInvoker) RequestInvoker {
+		return func(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+			request.Header = request.Header.Clone()
+			if request.Header == nil {
+				request.Header = make(http.Header)
+			}
+			request.Header.Set("X-Tenant", "acme")
+			if err := SetRequestMeta(&request, "tenant", "acme-meta"); err != nil {
+				return nil, err
+			}
+			return next(ctx, request)
+		}
+	}
+
+	trans, err := transport.NewStreamableHTTP(httpServer.URL)
+	require.NoError(t, err)
+	client := NewClient(trans, WithRequestInterceptor(record("outer"), record("inner")), WithRequestInterceptor(timing, tenant))
+	defer client.Close()
+	require.NoError(t, client.Start(context.Background()))
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	_, err = client.Initialize(context.Background(), initRequest)
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "whoami"
+	result, err := client.CallTool(context.Background(), request)
+	require.NoError(t, err)
+	require.Len(t, result.Content, 1)
+	assert.Equal(t, "acme acme-meta", result.Content[0].(mcp.TextContent).Text)
+
+	assert.Equal(t, []string{"outer initialize", "inner initialize", "outer tools/call", "inner tools/call"}, order)
+	assert.Len(t, latencies, 2)
+}
+
+func TestClient_WithRequestInterceptor_Retry(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+	errTransient := errors.New("connection reset")
+
+	failures := 2
+	flaky := func(next RequestInvoker) RequestInvoker {
+		return func(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+			if request.Method == string(mcp.MethodPing) && failures > 0 {
+				failures--
+				return nil, errTransient
+			}
+			return next(ctx, request)
+		}
+	}
+	attempts := 0
+	retry := func(next RequestInvoker) RequestInvoker {
+		return func(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+			for {
+				attempts++
+				response, err := next(ctx, request)
+				if !errors.Is(err, errTransient) || attempts == 5 {
+					return response, err
+				}
+			}
+		}
+	}
+
+	client := NewClient(transport.NewInProcessTransport(mcpServer), WithRequestInterceptor(retry, flaky))
+	defer client.Close()
+	require.NoError(t, client.Start(context.Background()))
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	_, err := client.Initialize(context.Background(), initRequest)
+	require.NoError(t, err)
+
+	attempts = 0
+	require.NoError(t, client.Ping(context.Background()))
+	assert.Equal(t, 3, attempts)
+
+	failures, attempts = 10, 0
+	err = client.Ping(context.Background())
+	assert.ErrorIs(t, err, errTransient, "transport errors are still wrapped by the client")
+	var transportErr *transport.Error
+	assert.ErrorAs(t, err, &transportErr)
+}
+
+func TestSetRequestMeta(t *testing.T) {
+	params := map[string]any{"name": "echo", "_meta": map[string]any{"progressToken": 1}}
+	request := transport.JSONRPCRequest{Params: params}
+	require.NoError(t, SetRequestMeta(&request, "traceparent", "00-abc"))
+	assert.Equal(t, map[string]any{
+		"name":  "echo",
+		"_meta": map[string]any{"progressToken": 1, "traceparent": "00-abc"},
+	}, request.Params)
+	assert.NotContains(t, params["_meta"], "traceparent", "the caller's params are not modified")
+
+	request = transport.JSONRPCRequest{Params: struct {
+		Name string `json:"name"`
+	}{Name: "echo"}}
+	require.NoError(t, SetRequestMeta(&request, "key", "value"))
+	assert.Equal(t, map[string]any{"name": "echo", "_meta": map[string]any{"key": "value"}}, request.Params)
+
+	request = transport.JSONRPCRequest{}
+	require.NoError(t, SetRequestMeta(&request, "key", "value"))
+	assert.Equal(t, map[string]any{"_meta": map[string]any{"key": "value"}}, request.Params)
+
+	request = transport.JSONRPCRequest{Params: []string{"not", "an", "object"}}
+	assert.Error(t, SetRequestMeta(&request, "key", "value"))
+}
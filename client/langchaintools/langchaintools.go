@@ -0,0 +1,133 @@
+// Package langchaintools wraps the tools of an MCP server as LangChainGo
+// tools, so that LangChainGo agents can use them:
+//
+//	mcpTools, err := langchaintools.New(ctx, c)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	agentTools := make([]tools.Tool, len(mcpTools))
+//	for i, tool := range mcpTools {
+//	    agentTools[i] = tool
+//	}
+//	agent := agents.NewOneShotAgent(llm, agentTools)
+//
+// A *Tool implements the tools.Tool interface of
+// github.com/tmc/langchaingo/tools, and the package has no dependency on
+// LangChainGo. Agents pass the input of a tool as a string: it is parsed as
+// the JSON object of the arguments, or given as the single argument of tools
+// taking one string.
+package langchaintools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/client/llmtools"
+)
+
+// Tool is an MCP tool implementing the LangChainGo tools.Tool interface.
+type Tool struct {
+	tool       *llmtools.Tool
+	hideSchema bool
+}
+
+// New returns the tools of the server of provider. Options such as
+// llmtools.WithNotifications configure the calls of the tools.
+func New(ctx context.Context, provider llmtools.ToolProvider, opts ...llmtools.Option) ([]*Tool, error) {
+	tools, err := llmtools.Tools(ctx, provider, opts...)
+	if err != nil {
+		return nil, err
+	}
+	wrapped := make([]*Tool, len(tools))
+	for i, tool := range tools {
+		wrapped[i] = NewTool(tool)
+	}
+	return wrapped, nil
+}
+
+// NewTool returns the LangChainGo tool calling tool.
+func NewTool(tool *llmtools.Tool) *Tool {
+	return &Tool{tool: tool}
+}
+
+// WithoutSchemaInDescription returns a copy of t keeping the input schema
+// out of its description, for agents that get it from InputSchema.
+func (t *Tool) WithoutSchemaInDescription() *Tool {
+	return &Tool{tool: t.tool, hideSchema: true}
+}
+
+// Name returns the name of the MCP tool.
+func (t *Tool) Name() string {
+	return t.tool.MCPTool().Name
+}
+
+// Description returns the description of the MCP tool followed, unless
+// WithoutSchemaInDescription is given, by its input schema.
+func (t *Tool) Description() string {
+	description := t.tool.MCPTool().Description
+	if t.hideSchema {
+		return description
+	}
+	return strings.TrimSpace(description + "\nThe input must be a JSON object matching this schema: " + string(t.tool.InputSchema()))
+}
+
+// InputSchema returns the JSON Schema of the arguments of the tool, to use
+// as the Parameters of a LangChainGo llms.FunctionDefinition.
+func (t *Tool) InputSchema() map[string]any {
+	var schema map[string]any
+	_ = json.Unmarshal(t.tool.InputSchema(), &schema)
+	return schema
+}
+
+// Call calls the MCP tool with input and returns the text of its result.
+// Tool errors are returned as text prefixed with "Error: ", so that the
+// agent can recover from them; the returned error is that of the request.
+func (t *Tool) Call(ctx context.Context, input string) (string, error) {
+	arguments, err := t.arguments(input)
+	if err != nil {
+		return "", err
+	}
+	result, err := t.tool.Call(ctx, arguments)
+	if err != nil {
+		return "", err
+	}
+	text := llmtools.ResultText(result)
+	if result.IsError {
+		text = "Error: " + text
+	}
+	return text, nil
+}
+
+// arguments returns the arguments of a call with input.
+func (t *Tool) arguments(input string) (map[string]any, error) {
+	trimmed := strings.TrimSpace(input)
+	if trimmed == "" {
+		return map[string]any{}, nil
+	}
+	var arguments map[string]any
+	if err := json.Unmarshal([]byte(trimmed), &arguments); err == nil && arguments != nil {
+		return arguments, nil
+	}
+	if name, ok := t.stringArgument(); ok {
+		return map[string]any{name: input}, nil
+	}
+	return nil, fmt.Errorf("tool %s: input is not a JSON object of arguments", t.Name())
+}
+
+// stringArgument returns the name of the only argument of the tool if it
+// takes a single string.
+func (t *Tool) stringArgument() (string, bool) {
+	properties, _ := t.InputSchema()["properties"].(map[string]any)
+	if len(properties) != 1 {
+		return "", false
+	}
+	for name, property := range properties {
+		schema, _ := property.(map[string]any)
+		if schema["type"] == "string" {
+			return name, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,135 @@
+package langchaintools
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/llmtools"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func newTestClient(t *testing.T) *client.Client {
+	t.Helper()
+	hooks := &server.Hooks{}
+	hooks.AddAfterCallTool(func(ctx context.Context, id any, message *mcp.CallToolRequest, result *mcp.CallToolResult) {
+		// let the notifications be written before the response
+		time.Sleep(50 * time.Millisecond)
+	})
+	mcpServer := server.NewMCPServer("test-server", "1.0.0", server.WithToolCapabilities(true), server.WithHooks(hooks))
+	mcpServer.AddTool(mcp.NewTool("echo",
+		mcp.WithDescription("Echo a message"),
+		mcp.WithString("message", mcp.Required()),
+	), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := server.ServerFromContext(ctx).SendNotificationToClient(ctx, mcp.MethodNotificationProgress, map[string]any{
+			"progressToken": request.Params.Meta.ProgressToken,
+			"progress":      1,
+		}); err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText(request.GetString("message", "")), nil
+	})
+	mcpServer.AddTool(mcp.NewTool("add",
+		mcp.WithNumber("a", mcp.Required()),
+		mcp.WithNumber("b", mcp.Required()),
+	), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		a, err := request.RequireFloat("a")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return mcp.FormatNumberResult(a + request.GetFloat("b", 0)), nil
+	})
+
+	testServer := server.NewTestStreamableHTTPServer(mcpServer)
+	t.Cleanup(testServer.Close)
+	c, err := client.NewStreamableHttpClient(testServer.URL)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+
+	ctx := context.Background()
+	require.NoError(t, c.Start(ctx))
+	request := mcp.InitializeRequest{}
+	request.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	request.Params.ClientInfo = mcp.Implementation{Name: "test-client", Version: "1.0.0"}
+	_, err = c.Initialize(ctx, request)
+	require.NoError(t, err)
+	return c
+}
+
+func TestNew(t *testing.T) {
+	tools, err := New(context.Background(), newTestClient(t))
+	require.NoError(t, err)
+	require.Len(t, tools, 2)
+
+	byName := map[string]*Tool{}
+	for _, tool := range tools {
+		byName[tool.Name()] = tool
+	}
+	echo := byName["echo"]
+	require.NotNil(t, echo)
+	assert.Equal(t,
+		`Echo a message`+"\n"+`The input must be a JSON object matching this schema: {"properties":{"message":{"type":"string"}},"required":["message"],"type":"object"}`,
+		echo.Description())
+	assert.Equal(t, map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"message": map[string]any{"type": "string"}},
+		"required":   []any{"message"},
+	}, echo.InputSchema())
+}
+
+func TestTool_Call(t *testing.T) {
+	c := newTestClient(t)
+	var mu sync.Mutex
+	var methods []string
+	tools, err := New(context.Background(), c, llmtools.WithNotifications(func(notification mcp.JSONRPCNotification) {
+		mu.Lock()
+		defer mu.Unlock()
+		methods = append(methods, notification.Method)
+	}))
+	require.NoError(t, err)
+	byName := map[string]*Tool{}
+	for _, tool := range tools {
+		byName[tool.Name()] = tool
+	}
+
+	tests := []struct {
+		name     string
+		tool     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "JSON arguments", tool: "add", input: `{"a":1,"b":2}`, expected: "3.00"},
+		{name: "single string argument", tool: "echo", input: "hello", expected: "hello"},
+		{name: "JSON input of a string tool", tool: "echo", input: `{"message":"hi"}`, expected: "hi"},
+		{name: "tool error", tool: "add", input: `{"b":2}`, expected: `Error: required argument "a" not found`},
+		{name: "invalid input", tool: "add", input: "one and two", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := byName[tt.tool].Call(context.Background(), tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, output)
+		})
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Contains(t, methods, mcp.MethodNotificationProgress)
+}
+
+func TestWithoutSchemaInDescription(t *testing.T) {
+	tool, err := llmtools.NewTool(nil, mcp.NewTool("ping", mcp.WithDescription("Ping the server")))
+	require.NoError(t, err)
+	assert.Equal(t, "Ping the server", NewTool(tool).WithoutSchemaInDescription().Description())
+}
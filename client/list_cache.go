@@ -0,0 +1,180 @@
+package client
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// WithListCache caches the tools, resources, resource templates and prompts
+// returned by CachedTools, CachedResources, CachedResourceTemplates and
+// CachedPrompts, so host applications can look them up on every turn
+// without listing them again. A list is dropped from the cache when the
+// server sends the matching list_changed notification, when the client is
+// initialized again, and after maxAge if it is positive; a maxAge bounds
+// how stale lists can get on servers that do not send list_changed
+// notifications.
+func WithListCache(maxAge time.Duration) ClientOption {
+	return func(c *Client) {
+		c.listCache = &listCache{maxAge: maxAge, entries: make(map[string]*listCacheEntry)}
+	}
+}
+
+// listCache holds full list results by list method.
+type listCache struct {
+	maxAge  time.Duration
+	mu      sync.Mutex
+	entries map[string]*listCacheEntry
+}
+
+type listCacheEntry struct {
+	// generation is incremented for each invalidation, so that a list
+	// fetched while the entry was invalidated is not stored.
+	generation uint64
+	value      any
+	valid      bool
+	fetched    time.Time
+}
+
+// listChangedMethods maps list_changed notifications to the list methods
+// whose results they invalidate.
+var listChangedMethods = map[string][]string{
+	mcp.MethodNotificationToolsListChanged:     {string(mcp.MethodToolsList)},
+	mcp.MethodNotificationResourcesListChanged: {string(mcp.MethodResourcesList), string(mcp.MethodResourcesTemplatesList)},
+	mcp.MethodNotificationPromptsListChanged:   {string(mcp.MethodPromptsList)},
+}
+
+// entry returns the entry of method, creating it. The cache must be locked.
+func (lc *listCache) entry(method string) *listCacheEntry {
+	e, ok := lc.entries[method]
+	if !ok {
+		e = &listCacheEntry{}
+		lc.entries[method] = e
+	}
+	return e
+}
+
+// invalidate drops the cached lists of methods.
+func (lc *listCache) invalidate(methods ...string) {
+	if lc == nil {
+		return
+	}
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	for _, method := range methods {
+		e := lc.entry(method)
+		e.generation++
+		e.valid, e.value = false, nil
+	}
+}
+
+// invalidateAll drops every cached list.
+func (lc *listCache) invalidateAll() {
+	if lc == nil {
+		return
+	}
+	lc.mu.Lock()
+	methods := make([]string, 0, len(lc.entries))
+	for method := range lc.entries {
+		methods = append(methods, method)
+	}
+	lc.mu.Unlock()
+	lc.invalidate(methods...)
+}
+
+// handleNotification invalidates the lists a list_changed notification
+// reports as changed.
+func (lc *listCache) handleNotification(notification mcp.JSONRPCNotification) {
+	if methods, ok := listChangedMethods[notification.Method]; ok {
+		lc.invalidate(methods...)
+	}
+}
+
+// cachedList returns the cached list of method, or fetches and caches it.
+// Without WithListCache the list is fetched on every call. The returned
+// slice is a copy the caller may modify.
+func cachedList[T any](ctx context.Context, c *Client, method string, fetch func(ctx context.Context) ([]T, error)) ([]T, error) {
+	lc := c.listCache
+	if lc == nil {
+		return fetch(ctx)
+	}
+
+	lc.mu.Lock()
+	e := lc.entry(method)
+	if e.valid && (lc.maxAge <= 0 || time.Since(e.fetched) < lc.maxAge) {
+		value := e.value.([]T)
+		lc.mu.Unlock()
+		return slices.Clone(value), nil
+	}
+	generation := e.generation
+	lc.mu.Unlock()
+
+	value, err := fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lc.mu.Lock()
+	if e.generation == generation {
+		e.value, e.valid, e.fetched = slices.Clone(value), true, time.Now()
+	}
+	lc.mu.Unlock()
+	return value, nil
+}
+
+// CachedTools returns the tools of the server, listing them only if they
+// are not cached by WithListCache.
+func (c *Client) CachedTools(ctx context.Context) ([]mcp.Tool, error) {
+	return cachedList(ctx, c, string(mcp.MethodToolsList), func(ctx context.Context) ([]mcp.Tool, error) {
+		result, err := c.ListTools(ctx, mcp.ListToolsRequest{})
+		if err != nil {
+			return nil, err
+		}
+		return result.Tools, nil
+	})
+}
+
+// CachedResources returns the resources of the server, listing them only
+// if they are not cached by WithListCache.
+func (c *Client) CachedResources(ctx context.Context) ([]mcp.Resource, error) {
+	return cachedList(ctx, c, string(mcp.MethodResourcesList), func(ctx context.Context) ([]mcp.Resource, error) {
+		result, err := c.ListResources(ctx, mcp.ListResourcesRequest{})
+		if err != nil {
+			return nil, err
+		}
+		return result.Resources, nil
+	})
+}
+
+// CachedResourceTemplates returns the resource templates of the server,
+// listing them only if they are not cached by WithListCache.
+func (c *Client) CachedResourceTemplates(ctx context.Context) ([]mcp.ResourceTemplate, error) {
+	return cachedList(ctx, c, string(mcp.MethodResourcesTemplatesList), func(ctx context.Context) ([]mcp.ResourceTemplate, error) {
+		result, err := c.ListResourceTemplates(ctx, mcp.ListResourceTemplatesRequest{})
+		if err != nil {
+			return nil, err
+		}
+		return result.ResourceTemplates, nil
+	})
+}
+
+// CachedPrompts returns the prompts of the server, listing them only if
+// they are not cached by WithListCache.
+func (c *Client) CachedPrompts(ctx context.Context) ([]mcp.Prompt, error) {
+	return cachedList(ctx, c, string(mcp.MethodPromptsList), func(ctx context.Context) ([]mcp.Prompt, error) {
+		result, err := c.ListPrompts(ctx, mcp.ListPromptsRequest{})
+		if err != nil {
+			return nil, err
+		}
+		return result.Prompts, nil
+	})
+}
+
+// InvalidateListCache drops the lists cached by WithListCache, so that the
+// Cached methods list them again.
+func (c *Client) InvalidateListCache() {
+	c.listCache.invalidateAll()
+}
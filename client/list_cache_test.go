@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// listCounter counts the requests the client sends by method.
+type listCounter struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (lc *listCounter) interceptor(next RequestInvoker) RequestInvoker {
+	return func(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+		lc.mu.Lock()
+		lc.counts[request.Method]++
+		lc.mu.Unlock()
+		return next(ctx, request)
+	}
+}
+
+func (lc *listCounter) count(method string) int {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	return lc.counts[method]
+}
+
+func newListCacheClient(t *testing.T, mcpServer *server.MCPServer, opts ...ClientOption) (*Client, *listCounter) {
+	t.Helper()
+	// The streamable HTTP transport delivers the list_changed notifications
+	// the server sends outside of requests.
+	httpServer := server.NewTestStreamableHTTPServer(mcpServer)
+	t.Cleanup(httpServer.Close)
+	trans, err := transport.NewStreamableHTTP(httpServer.URL, transport.WithContinuousListening())
+	require.NoError(t, err)
+
+	counter := &listCounter{counts: make(map[string]int)}
+	client := NewClient(trans, append(opts, WithRequestInterceptor(counter.interceptor))...)
+	t.Cleanup(func() { _ = client.Close() })
+	require.NoError(t, client.Start(context.Background()))
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	_, err = client.Initialize(context.Background(), initRequest)
+	require.NoError(t, err)
+	return client, counter
+}
+
+func TestClient_WithListCache(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0",
+		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(false, true),
+		server.WithPromptCapabilities(true),
+	)
+	mcpServer.AddTool(mcp.NewTool("first"), nil)
+	mcpServer.AddResource(mcp.NewResource("test://a", "a"), nil)
+	mcpServer.AddResourceTemplate(mcp.NewResourceTemplate("test://items/{id}", "items"), nil)
+	mcpServer.AddPrompt(mcp.NewPrompt("greet"), nil)
+	client, counter := newListCacheClient(t, mcpServer, WithListCache(0))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		tools, err := client.CachedTools(ctx)
+		require.NoError(t, err)
+		require.Len(t, tools, 1)
+		resources, err := client.CachedResources(ctx)
+		require.NoError(t, err)
+		require.Len(t, resources, 1)
+		templates, err := client.CachedResourceTemplates(ctx)
+		require.NoError(t, err)
+		require.Len(t, templates, 1)
+		prompts, err := client.CachedPrompts(ctx)
+		require.NoError(t, err)
+		require.Len(t, prompts, 1)
+	}
+	assert.Equal(t, 1, counter.count("tools/list"))
+	assert.Equal(t, 1, counter.count("resources/list"))
+	assert.Equal(t, 1, counter.count("resources/templates/list"))
+	assert.Equal(t, 1, counter.count("prompts/list"))
+
+	tools, err := client.CachedTools(ctx)
+	require.NoError(t, err)
+	tools[0].Name = "modified"
+	tools, err = client.CachedTools(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "first", tools[0].Name, "callers get copies of the cached list")
+
+	mcpServer.AddTool(mcp.NewTool("second"), nil)
+	require.Eventually(t, func() bool {
+		tools, err := client.CachedTools(ctx)
+		return err == nil && len(tools) == 2
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, 2, counter.count("tools/list"))
+	assert.Equal(t, 1, counter.count("prompts/list"), "other lists stay cached")
+
+	mcpServer.AddResource(mcp.NewResource("test://b", "b"), nil)
+	require.Eventually(t, func() bool {
+		resources, err := client.CachedResources(ctx)
+		return err == nil && len(resources) == 2
+	}, time.Second, 5*time.Millisecond)
+	_, err = client.CachedResourceTemplates(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, counter.count("resources/templates/list"), "resource list changes invalidate templates too")
+
+	client.InvalidateListCache()
+	_, err = client.CachedPrompts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, counter.count("prompts/list"))
+}
+
+func TestClient_WithListCache_MaxAge(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("first"), nil)
+	client, counter := newListCacheClient(t, mcpServer, WithListCache(20*time.Millisecond))
+
+	_, err := client.CachedTools(context.Background())
+	require.NoError(t, err)
+	_, err = client.CachedTools(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, counter.count("tools/list"))
+
+	time.Sleep(30 * time.Millisecond)
+	_, err = client.CachedTools(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, counter.count("tools/list"))
+}
+
+func TestClient_CachedToolsWithoutCache(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("first"), nil)
+	client, counter := newListCacheClient(t, mcpServer)
+
+	for i := 0; i < 2; i++ {
+		tools, err := client.CachedTools(context.Background())
+		require.NoError(t, err)
+		assert.Len(t, tools, 1)
+	}
+	assert.Equal(t, 2, counter.count("tools/list"))
+}
@@ -0,0 +1,123 @@
+package llmtools
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// AnthropicTool is an entry of the tools of an Anthropic messages request.
+type AnthropicTool struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// InputSchema is the JSON Schema of the input of the tool.
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+// AnthropicToolUse is a tool_use content block of an assistant message.
+type AnthropicToolUse struct {
+	// Type is always "tool_use".
+	Type string `json:"type"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Input is the JSON object of the arguments.
+	Input json.RawMessage `json:"input"`
+}
+
+// AnthropicToolResult is the tool_result content block answering an
+// AnthropicToolUse.
+type AnthropicToolResult struct {
+	// Type is always "tool_result".
+	Type      string             `json:"type"`
+	ToolUseID string             `json:"tool_use_id"`
+	Content   []AnthropicContent `json:"content,omitempty"`
+	IsError   bool               `json:"is_error,omitempty"`
+}
+
+// AnthropicContent is a text or image content block of a tool result.
+type AnthropicContent struct {
+	// Type is "text" or "image".
+	Type   string                `json:"type"`
+	Text   string                `json:"text,omitempty"`
+	Source *AnthropicImageSource `json:"source,omitempty"`
+}
+
+// AnthropicImageSource is the base64 data of an image content block.
+type AnthropicImageSource struct {
+	// Type is always "base64".
+	Type      string `json:"type"`
+	MediaType string `json:"media_type"`
+	Data      string `json:"data"`
+}
+
+// ToAnthropicTools returns the Anthropic tool definitions of tools.
+func ToAnthropicTools(tools []mcp.Tool) ([]AnthropicTool, error) {
+	specs := make([]AnthropicTool, 0, len(tools))
+	for _, tool := range tools {
+		schema, err := InputSchema(tool)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, AnthropicTool{
+			Name:        tool.Name,
+			Description: tool.Description,
+			InputSchema: schema,
+		})
+	}
+	return specs, nil
+}
+
+// FromAnthropicToolUse returns the request calling the tool of use.
+func FromAnthropicToolUse(use AnthropicToolUse) (mcp.CallToolRequest, error) {
+	return newCallToolRequest(use.Name, use.Input)
+}
+
+// NewAnthropicToolResult returns the block answering the tool use with id
+// toolUseID with result. Text and image contents are kept as such, other
+// contents are sent as the text of their JSON encoding, and results without
+// content as the JSON encoding of their structured content.
+func NewAnthropicToolResult(toolUseID string, result *mcp.CallToolResult) AnthropicToolResult {
+	block := AnthropicToolResult{Type: "tool_result", ToolUseID: toolUseID}
+	if result == nil {
+		return block
+	}
+	block.IsError = result.IsError
+	for _, content := range result.Content {
+		if text, ok := mcp.AsTextContent(content); ok {
+			block.Content = append(block.Content, AnthropicContent{Type: "text", Text: text.Text})
+			continue
+		}
+		if image, ok := mcp.AsImageContent(content); ok {
+			block.Content = append(block.Content, AnthropicContent{
+				Type:   "image",
+				Source: &AnthropicImageSource{Type: "base64", MediaType: image.MIMEType, Data: image.Data},
+			})
+			continue
+		}
+		if data, err := json.Marshal(content); err == nil {
+			block.Content = append(block.Content, AnthropicContent{Type: "text", Text: string(data)})
+		}
+	}
+	if len(block.Content) == 0 && result.StructuredContent != nil {
+		if data, err := json.Marshal(result.StructuredContent); err == nil {
+			block.Content = append(block.Content, AnthropicContent{Type: "text", Text: string(data)})
+		}
+	}
+	return block
+}
+
+// CallAnthropicTool calls the tool of use with caller and returns the block
+// answering it. Tool errors are reported to the model in the block; the
+// returned error is that of the request.
+func CallAnthropicTool(ctx context.Context, caller ToolCaller, use AnthropicToolUse) (AnthropicToolResult, error) {
+	request, err := FromAnthropicToolUse(use)
+	if err != nil {
+		return AnthropicToolResult{}, err
+	}
+	result, err := caller.CallTool(ctx, request)
+	if err != nil {
+		return AnthropicToolResult{}, err
+	}
+	return NewAnthropicToolResult(use.ID, result), nil
+}
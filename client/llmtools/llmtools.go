@@ -0,0 +1,113 @@
+// Package llmtools bridges MCP tools to the function calling APIs of
+// language models.
+//
+// ToOpenAITools and ToAnthropicTools turn the tools listed by an MCP server
+// into the tool definitions of the OpenAI and Anthropic APIs. The tool calls
+// the model answers with are mapped back to CallToolRequests by
+// FromOpenAIToolCall and FromAnthropicToolUse, and the results of the calls
+// are turned into the messages returned to the model by NewOpenAIToolMessage
+// and NewAnthropicToolResult:
+//
+//	tools, err := c.ListTools(ctx, mcp.ListToolsRequest{})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	specs, err := llmtools.ToOpenAITools(tools.Tools)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	// Send specs with the chat completion request, then for each tool call
+//	// of the response:
+//	message, err := llmtools.CallOpenAITool(ctx, c, call)
+//
+// The types of the package only hold the fields of the APIs relevant to
+// tools and encode to their JSON, so that they can be used with any HTTP
+// client or SDK accepting raw JSON.
+//
+// Tools lists the tools of a server as *Tool values bound to the client
+// calling them, which the adapters of agent frameworks, such as
+// langchaintools and genkittools, wrap.
+package llmtools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// InputSchema returns the JSON Schema of the arguments of tool, as sent by
+// MCP servers. Schemas without properties are given an empty properties
+// object, which the model APIs require of object schemas.
+func InputSchema(tool mcp.Tool) (json.RawMessage, error) {
+	data, err := json.Marshal(tool)
+	if err != nil {
+		return nil, err
+	}
+	var encoded struct {
+		InputSchema map[string]any `json:"inputSchema"`
+	}
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, fmt.Errorf("tool %s: invalid input schema: %w", tool.Name, err)
+	}
+	schema := encoded.InputSchema
+	if schema == nil {
+		schema = map[string]any{}
+	}
+	if _, ok := schema["type"]; !ok {
+		schema["type"] = "object"
+	}
+	if _, ok := schema["properties"]; !ok && schema["type"] == "object" {
+		schema["properties"] = map[string]any{}
+	}
+	return json.Marshal(schema)
+}
+
+// newCallToolRequest returns the request calling the named tool with the
+// JSON object arguments. Empty arguments are sent as an empty object.
+func newCallToolRequest(name string, arguments []byte) (mcp.CallToolRequest, error) {
+	request := mcp.CallToolRequest{}
+	request.Method = string(mcp.MethodToolsCall)
+	request.Params.Name = name
+
+	if len(strings.TrimSpace(string(arguments))) == 0 {
+		request.Params.Arguments = map[string]any{}
+		return request, nil
+	}
+	var args map[string]any
+	if err := json.Unmarshal(arguments, &args); err != nil {
+		return mcp.CallToolRequest{}, fmt.Errorf("tool %s: arguments are not a JSON object: %w", name, err)
+	}
+	if args == nil {
+		args = map[string]any{}
+	}
+	request.Params.Arguments = args
+	return request, nil
+}
+
+// ResultText returns the text of a tool result for models that only accept
+// text: its text contents, one per line, followed by the JSON encoding of
+// its other contents. Results without content are represented by their
+// structured content.
+func ResultText(result *mcp.CallToolResult) string {
+	if result == nil {
+		return ""
+	}
+	var parts []string
+	for _, content := range result.Content {
+		if text, ok := mcp.AsTextContent(content); ok {
+			parts = append(parts, text.Text)
+			continue
+		}
+		if data, err := json.Marshal(content); err == nil {
+			parts = append(parts, string(data))
+		}
+	}
+	if len(parts) == 0 && result.StructuredContent != nil {
+		if data, err := json.Marshal(result.StructuredContent); err == nil {
+			parts = append(parts, string(data))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
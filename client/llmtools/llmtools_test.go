@@ -0,0 +1,233 @@
+package llmtools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// fakeCaller answers tool calls with result and keeps the last request.
+type fakeCaller struct {
+	result  *mcp.CallToolResult
+	request mcp.CallToolRequest
+}
+
+func (c *fakeCaller) CallTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	c.request = request
+	return c.result, nil
+}
+
+func testTools() []mcp.Tool {
+	return []mcp.Tool{
+		mcp.NewTool("get_weather",
+			mcp.WithDescription("Get the weather of a city"),
+			mcp.WithString("city", mcp.Required()),
+		),
+		mcp.NewTool("ping"),
+		mcp.NewToolWithRawSchema("search", "Search documents",
+			json.RawMessage(`{"type":"object","properties":{"q":{"type":"string"}},"required":["q"]}`)),
+	}
+}
+
+func TestInputSchema(t *testing.T) {
+	tests := []struct {
+		name     string
+		tool     mcp.Tool
+		expected string
+	}{
+		{
+			name:     "structured schema",
+			tool:     testTools()[0],
+			expected: `{"type":"object","properties":{"city":{"type":"string"}},"required":["city"]}`,
+		},
+		{
+			name:     "no properties",
+			tool:     testTools()[1],
+			expected: `{"type":"object","properties":{}}`,
+		},
+		{
+			name:     "raw schema",
+			tool:     testTools()[2],
+			expected: `{"type":"object","properties":{"q":{"type":"string"}},"required":["q"]}`,
+		},
+		{
+			name:     "raw schema without type",
+			tool:     mcp.NewToolWithRawSchema("any", "", json.RawMessage(`{}`)),
+			expected: `{"type":"object","properties":{}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := InputSchema(tt.tool)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(schema))
+		})
+	}
+}
+
+func TestToOpenAITools(t *testing.T) {
+	specs, err := ToOpenAITools(testTools()[:2])
+	require.NoError(t, err)
+
+	data, err := json.Marshal(specs)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"type":"function","function":{
+			"name":"get_weather",
+			"description":"Get the weather of a city",
+			"parameters":{"type":"object","properties":{"city":{"type":"string"}},"required":["city"]}
+		}},
+		{"type":"function","function":{"name":"ping","parameters":{"type":"object","properties":{}}}}
+	]`, string(data))
+}
+
+func TestToAnthropicTools(t *testing.T) {
+	specs, err := ToAnthropicTools(testTools()[2:])
+	require.NoError(t, err)
+
+	data, err := json.Marshal(specs)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{
+		"name":"search",
+		"description":"Search documents",
+		"input_schema":{"type":"object","properties":{"q":{"type":"string"}},"required":["q"]}
+	}]`, string(data))
+}
+
+func TestFromToolCalls(t *testing.T) {
+	tests := []struct {
+		name      string
+		arguments string
+		expected  map[string]any
+		wantErr   bool
+	}{
+		{name: "object", arguments: `{"city":"Paris"}`, expected: map[string]any{"city": "Paris"}},
+		{name: "empty", arguments: ``, expected: map[string]any{}},
+		{name: "null", arguments: `null`, expected: map[string]any{}},
+		{name: "not an object", arguments: `["Paris"]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			openAI, openAIErr := FromOpenAIToolCall(OpenAIToolCall{
+				ID:       "call_1",
+				Type:     "function",
+				Function: OpenAIFunctionCall{Name: "get_weather", Arguments: tt.arguments},
+			})
+			anthropic, anthropicErr := FromAnthropicToolUse(AnthropicToolUse{
+				Type:  "tool_use",
+				ID:    "toolu_1",
+				Name:  "get_weather",
+				Input: json.RawMessage(tt.arguments),
+			})
+			if tt.wantErr {
+				assert.Error(t, openAIErr)
+				assert.Error(t, anthropicErr)
+				return
+			}
+			require.NoError(t, openAIErr)
+			require.NoError(t, anthropicErr)
+			for _, request := range []mcp.CallToolRequest{openAI, anthropic} {
+				assert.Equal(t, string(mcp.MethodToolsCall), request.Method)
+				assert.Equal(t, "get_weather", request.Params.Name)
+				assert.Equal(t, tt.expected, request.Params.Arguments)
+			}
+		})
+	}
+}
+
+func TestResults(t *testing.T) {
+	tests := []struct {
+		name              string
+		result            *mcp.CallToolResult
+		expectedOpenAI    string
+		expectedAnthropic string
+	}{
+		{
+			name:              "text",
+			result:            mcp.NewToolResultText("sunny"),
+			expectedOpenAI:    `{"role":"tool","tool_call_id":"call_1","content":"sunny"}`,
+			expectedAnthropic: `{"type":"tool_result","tool_use_id":"toolu_1","content":[{"type":"text","text":"sunny"}]}`,
+		},
+		{
+			name:              "error",
+			result:            mcp.NewToolResultError("unknown city"),
+			expectedOpenAI:    `{"role":"tool","tool_call_id":"call_1","content":"Error: unknown city"}`,
+			expectedAnthropic: `{"type":"tool_result","tool_use_id":"toolu_1","content":[{"type":"text","text":"unknown city"}],"is_error":true}`,
+		},
+		{
+			name:           "image",
+			result:         mcp.NewToolResultImage("map", "aGVsbG8=", "image/png"),
+			expectedOpenAI: `{"role":"tool","tool_call_id":"call_1","content":"map\n{\"type\":\"image\",\"data\":\"aGVsbG8=\",\"mimeType\":\"image/png\"}"}`,
+			expectedAnthropic: `{"type":"tool_result","tool_use_id":"toolu_1","content":[
+				{"type":"text","text":"map"},
+				{"type":"image","source":{"type":"base64","media_type":"image/png","data":"aGVsbG8="}}
+			]}`,
+		},
+		{
+			name:              "structured content only",
+			result:            &mcp.CallToolResult{StructuredContent: map[string]any{"temp": 21}},
+			expectedOpenAI:    `{"role":"tool","tool_call_id":"call_1","content":"{\"temp\":21}"}`,
+			expectedAnthropic: `{"type":"tool_result","tool_use_id":"toolu_1","content":[{"type":"text","text":"{\"temp\":21}"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caller := &fakeCaller{result: tt.result}
+
+			message, err := CallOpenAITool(context.Background(), caller, OpenAIToolCall{
+				ID:       "call_1",
+				Type:     "function",
+				Function: OpenAIFunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`},
+			})
+			require.NoError(t, err)
+			data, err := json.Marshal(message)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expectedOpenAI, string(data))
+
+			block, err := CallAnthropicTool(context.Background(), caller, AnthropicToolUse{
+				Type:  "tool_use",
+				ID:    "toolu_1",
+				Name:  "get_weather",
+				Input: json.RawMessage(`{"city":"Paris"}`),
+			})
+			require.NoError(t, err)
+			data, err = json.Marshal(block)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expectedAnthropic, string(data))
+			assert.Equal(t, map[string]any{"city": "Paris"}, caller.request.Params.Arguments)
+		})
+	}
+}
+
+// fakeProvider lists tools and answers calls like fakeCaller.
+type fakeProvider struct {
+	fakeCaller
+	tools []mcp.Tool
+}
+
+func (p *fakeProvider) ListTools(ctx context.Context, request mcp.ListToolsRequest) (*mcp.ListToolsResult, error) {
+	return &mcp.ListToolsResult{Tools: p.tools}, nil
+}
+
+func TestTools(t *testing.T) {
+	provider := &fakeProvider{fakeCaller: fakeCaller{result: mcp.NewToolResultText("sunny")}, tools: testTools()}
+	tools, err := Tools(context.Background(), provider)
+	require.NoError(t, err)
+	require.Len(t, tools, 3)
+
+	weather := tools[0]
+	assert.Equal(t, "get_weather", weather.MCPTool().Name)
+	assert.JSONEq(t, `{"type":"object","properties":{"city":{"type":"string"}},"required":["city"]}`, string(weather.InputSchema()))
+	assert.JSONEq(t, `{"type":"object","properties":{}}`, string(tools[1].InputSchema()))
+
+	result, err := weather.Call(context.Background(), map[string]any{"city": "Paris"})
+	require.NoError(t, err)
+	assert.Equal(t, "sunny", ResultText(result))
+	assert.Equal(t, "get_weather", provider.request.Params.Name)
+	assert.Equal(t, map[string]any{"city": "Paris"}, provider.request.Params.Arguments)
+}
@@ -0,0 +1,103 @@
+package llmtools
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ToolCaller calls MCP tools. It is implemented by client.Client.
+type ToolCaller interface {
+	CallTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+}
+
+// OpenAITool is an entry of the tools of an OpenAI chat completion request.
+type OpenAITool struct {
+	// Type is always "function".
+	Type     string         `json:"type"`
+	Function OpenAIFunction `json:"function"`
+}
+
+// OpenAIFunction is the definition of a function the model may call.
+type OpenAIFunction struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Parameters is the JSON Schema of the arguments of the function.
+	Parameters json.RawMessage `json:"parameters"`
+}
+
+// OpenAIToolCall is a tool call of an assistant message.
+type OpenAIToolCall struct {
+	ID string `json:"id"`
+	// Type is always "function".
+	Type     string             `json:"type"`
+	Function OpenAIFunctionCall `json:"function"`
+}
+
+// OpenAIFunctionCall is the function called by an OpenAIToolCall.
+type OpenAIFunctionCall struct {
+	Name string `json:"name"`
+	// Arguments is the JSON object of the arguments, encoded as a string.
+	Arguments string `json:"arguments"`
+}
+
+// OpenAIToolMessage is the message answering an OpenAIToolCall.
+type OpenAIToolMessage struct {
+	// Role is always "tool".
+	Role       string `json:"role"`
+	ToolCallID string `json:"tool_call_id"`
+	Content    string `json:"content"`
+}
+
+// ToOpenAITools returns the OpenAI tool definitions of tools.
+func ToOpenAITools(tools []mcp.Tool) ([]OpenAITool, error) {
+	specs := make([]OpenAITool, 0, len(tools))
+	for _, tool := range tools {
+		schema, err := InputSchema(tool)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, OpenAITool{
+			Type: "function",
+			Function: OpenAIFunction{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  schema,
+			},
+		})
+	}
+	return specs, nil
+}
+
+// FromOpenAIToolCall returns the request calling the tool of call.
+func FromOpenAIToolCall(call OpenAIToolCall) (mcp.CallToolRequest, error) {
+	return newCallToolRequest(call.Function.Name, []byte(call.Function.Arguments))
+}
+
+// NewOpenAIToolMessage returns the message answering the tool call with id
+// callID with result. The content is the ResultText of the result, prefixed
+// with "Error: " if the tool call failed since OpenAI messages have no
+// error flag.
+func NewOpenAIToolMessage(callID string, result *mcp.CallToolResult) OpenAIToolMessage {
+	content := ResultText(result)
+	if result != nil && result.IsError {
+		content = "Error: " + content
+	}
+	return OpenAIToolMessage{Role: "tool", ToolCallID: callID, Content: content}
+}
+
+// CallOpenAITool calls the tool of call with caller and returns the message
+// answering it. Tool errors are reported to the model in the message;
+// the returned error is that of the request.
+func CallOpenAITool(ctx context.Context, caller ToolCaller, call OpenAIToolCall) (OpenAIToolMessage, error) {
+	request, err := FromOpenAIToolCall(call)
+	if err != nil {
+		return OpenAIToolMessage{}, err
+	}
+	result, err := caller.CallTool(ctx, request)
+	if err != nil {
+		return OpenAIToolMessage{}, err
+	}
+	return NewOpenAIToolMessage(call.ID, result), nil
+}
@@ -0,0 +1,92 @@
+package llmtools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ToolProvider lists and calls MCP tools. It is implemented by
+// client.Client.
+type ToolProvider interface {
+	ToolCaller
+	ListTools(ctx context.Context, request mcp.ListToolsRequest) (*mcp.ListToolsResult, error)
+}
+
+// Option configures the tools returned by Tools and NewTool.
+type Option func(*Tool)
+
+// WithNotifications streams the notifications of each call, such as its
+// progress, to handler while the tool runs. See
+// client.WithRequestNotifications.
+func WithNotifications(handler func(notification mcp.JSONRPCNotification)) Option {
+	return func(t *Tool) {
+		t.notifications = handler
+	}
+}
+
+// Tool is an MCP tool bound to the caller of its calls. The adapters of
+// agent frameworks, such as langchaintools and genkittools, wrap it.
+type Tool struct {
+	caller        ToolCaller
+	tool          mcp.Tool
+	schema        json.RawMessage
+	notifications func(notification mcp.JSONRPCNotification)
+}
+
+// Tools returns the tools of the server of provider.
+func Tools(ctx context.Context, provider ToolProvider, opts ...Option) ([]*Tool, error) {
+	result, err := provider.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("list tools: %w", err)
+	}
+	tools := make([]*Tool, 0, len(result.Tools))
+	for _, tool := range result.Tools {
+		t, err := NewTool(provider, tool, opts...)
+		if err != nil {
+			return nil, err
+		}
+		tools = append(tools, t)
+	}
+	return tools, nil
+}
+
+// NewTool returns the tool calling tool with caller.
+func NewTool(caller ToolCaller, tool mcp.Tool, opts ...Option) (*Tool, error) {
+	schema, err := InputSchema(tool)
+	if err != nil {
+		return nil, err
+	}
+	t := &Tool{caller: caller, tool: tool, schema: schema}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t, nil
+}
+
+// MCPTool returns the MCP tool.
+func (t *Tool) MCPTool() mcp.Tool {
+	return t.tool
+}
+
+// InputSchema returns the JSON Schema of the arguments of the tool, as
+// returned by the InputSchema function.
+func (t *Tool) InputSchema() json.RawMessage {
+	return t.schema
+}
+
+// Call calls the MCP tool with arguments. Tool errors are reported by the
+// IsError field of the result; the returned error is that of the request.
+func (t *Tool) Call(ctx context.Context, arguments map[string]any) (*mcp.CallToolResult, error) {
+	if t.notifications != nil {
+		ctx = client.WithRequestNotifications(ctx, t.notifications)
+	}
+	request := mcp.CallToolRequest{}
+	request.Method = string(mcp.MethodToolsCall)
+	request.Params.Name = t.tool.Name
+	request.Params.Arguments = arguments
+	return t.caller.CallTool(ctx, request)
+}
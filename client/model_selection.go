@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ErrNoModelAvailable is returned by a ModelSamplingHandler that has no
+// models to choose from.
+var ErrNoModelAvailable = errors.New("no model available for sampling")
+
+// ModelInfo describes a model a client can sample from. Cost, Speed and
+// Intelligence rate the model from 0 to 1, higher meaning cheaper, faster
+// and more capable respectively.
+type ModelInfo struct {
+	Name string
+	// Aliases are other names the model is matched by, such as a model
+	// family or a comparable model of another provider.
+	Aliases      []string
+	Cost         float64
+	Speed        float64
+	Intelligence float64
+}
+
+// matches reports whether the model matches a hint, which is treated as a
+// case-insensitive substring of the model name or one of its aliases.
+func (m ModelInfo) matches(hint string) bool {
+	hint = strings.ToLower(hint)
+	if hint == "" {
+		return false
+	}
+	if strings.Contains(strings.ToLower(m.Name), hint) {
+		return true
+	}
+	for _, alias := range m.Aliases {
+		if strings.Contains(strings.ToLower(alias), hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScoreModel rates how well model suits preferences by weighting its cost,
+// speed and intelligence ratings with the corresponding priorities.
+func ScoreModel(model ModelInfo, preferences *mcp.ModelPreferences) float64 {
+	if preferences == nil {
+		return 0
+	}
+	return model.Cost*preferences.CostPriority +
+		model.Speed*preferences.SpeedPriority +
+		model.Intelligence*preferences.IntelligencePriority
+}
+
+// SelectModel picks the model of models that best matches the model
+// preferences of a sampling request. Following the specification, hints are
+// evaluated in order and the first hint matching any model narrows the
+// choice to the matching models; the priorities then decide between the
+// remaining candidates. Ties go to the model listed first. The second return
+// value is false if models is empty.
+func SelectModel(models []ModelInfo, preferences *mcp.ModelPreferences) (ModelInfo, bool) {
+	if len(models) == 0 {
+		return ModelInfo{}, false
+	}
+
+	candidates := models
+	if preferences != nil {
+		for _, hint := range preferences.Hints {
+			var matching []ModelInfo
+			for _, model := range models {
+				if model.matches(hint.Name) {
+					matching = append(matching, model)
+				}
+			}
+			if len(matching) > 0 {
+				candidates = matching
+				break
+			}
+		}
+	}
+
+	best := candidates[0]
+	bestScore := ScoreModel(best, preferences)
+	for _, model := range candidates[1:] {
+		if score := ScoreModel(model, preferences); score > bestScore {
+			best, bestScore = model, score
+		}
+	}
+	return best, true
+}
+
+// ModelSamplingFunc generates a message for a sampling request with the
+// given model.
+type ModelSamplingFunc func(ctx context.Context, model ModelInfo, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error)
+
+// ModelSamplingHandler is a SamplingHandler that selects one of its models
+// for each request with SelectModel and generates the message with Sample.
+type ModelSamplingHandler struct {
+	Models []ModelInfo
+	Sample ModelSamplingFunc
+}
+
+var _ SamplingHandler = (*ModelSamplingHandler)(nil)
+
+// NewModelSamplingHandler returns a ModelSamplingHandler sampling from
+// models with sample.
+func NewModelSamplingHandler(sample ModelSamplingFunc, models ...ModelInfo) *ModelSamplingHandler {
+	return &ModelSamplingHandler{Models: models, Sample: sample}
+}
+
+// CreateMessage implements SamplingHandler. The model of the result defaults
+// to the name of the selected model.
+func (h *ModelSamplingHandler) CreateMessage(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	model, ok := SelectModel(h.Models, request.ModelPreferences)
+	if !ok {
+		return nil, ErrNoModelAvailable
+	}
+	result, err := h.Sample(ctx, model, request)
+	if err != nil {
+		return nil, err
+	}
+	if result != nil && result.Model == "" {
+		result.Model = model.Name
+	}
+	return result, nil
+}
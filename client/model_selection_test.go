@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+var testModels = []ModelInfo{
+	{Name: "claude-3-haiku-20240307", Aliases: []string{"gemini-1.5-flash"}, Cost: 0.9, Speed: 0.9, Intelligence: 0.4},
+	{Name: "claude-3-5-sonnet-20241022", Cost: 0.5, Speed: 0.6, Intelligence: 0.8},
+	{Name: "claude-3-opus-20240229", Cost: 0.1, Speed: 0.2, Intelligence: 0.9},
+}
+
+func TestSelectModel(t *testing.T) {
+	tests := []struct {
+		name        string
+		preferences *mcp.ModelPreferences
+		expected    string
+	}{
+		{name: "no preferences picks the first model", expected: "claude-3-haiku-20240307"},
+		{name: "cost", preferences: &mcp.ModelPreferences{CostPriority: 1}, expected: "claude-3-haiku-20240307"},
+		{name: "intelligence", preferences: &mcp.ModelPreferences{IntelligencePriority: 1}, expected: "claude-3-opus-20240229"},
+		{name: "weighted", preferences: &mcp.ModelPreferences{CostPriority: 0.4, IntelligencePriority: 0.8}, expected: "claude-3-5-sonnet-20241022"},
+		{name: "hint", preferences: &mcp.ModelPreferences{Hints: []mcp.ModelHint{{Name: "sonnet"}}, CostPriority: 1}, expected: "claude-3-5-sonnet-20241022"},
+		{name: "hint by alias", preferences: &mcp.ModelPreferences{Hints: []mcp.ModelHint{{Name: "Gemini-1.5"}}}, expected: "claude-3-haiku-20240307"},
+		{name: "first matching hint wins", preferences: &mcp.ModelPreferences{Hints: []mcp.ModelHint{{Name: "gpt-4o"}, {Name: "opus"}, {Name: "haiku"}}}, expected: "claude-3-opus-20240229"},
+		{name: "priorities break hint ties", preferences: &mcp.ModelPreferences{Hints: []mcp.ModelHint{{Name: "claude"}}, SpeedPriority: 0.2, IntelligencePriority: 1}, expected: "claude-3-opus-20240229"},
+		{name: "unmatched hints fall back to priorities", preferences: &mcp.ModelPreferences{Hints: []mcp.ModelHint{{Name: "gpt-4o"}}, IntelligencePriority: 1}, expected: "claude-3-opus-20240229"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, ok := SelectModel(testModels, tt.preferences)
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, model.Name)
+		})
+	}
+
+	_, ok := SelectModel(nil, &mcp.ModelPreferences{CostPriority: 1})
+	assert.False(t, ok)
+}
+
+func TestModelSamplingHandler(t *testing.T) {
+	var sampledWith string
+	handler := NewModelSamplingHandler(func(ctx context.Context, model ModelInfo, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+		sampledWith = model.Name
+		return &mcp.CreateMessageResult{
+			SamplingMessage: mcp.SamplingMessage{Role: mcp.RoleAssistant, Content: mcp.NewTextContent("hi")},
+		}, nil
+	}, testModels...)
+
+	request := mcp.CreateMessageRequest{}
+	request.ModelPreferences = &mcp.ModelPreferences{Hints: []mcp.ModelHint{{Name: "opus"}}}
+	result, err := handler.CreateMessage(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, "claude-3-opus-20240229", sampledWith)
+	assert.Equal(t, "claude-3-opus-20240229", result.Model, "the model defaults to the selected one")
+
+	_, err = NewModelSamplingHandler(nil).CreateMessage(context.Background(), request)
+	assert.ErrorIs(t, err, ErrNoModelAvailable)
+}
@@ -0,0 +1,25 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+)
+
+// NewNamedPipeMCPClient creates a client connected to an MCP server serving
+// the Windows named pipe name, see transport.DialNamedPipe.
+//
+// NOTICE: Like NewStdioMCPClient, NewNamedPipeMCPClient starts the
+// connection automatically.
+func NewNamedPipeMCPClient(name string, opts ...ClientOption) (*Client, error) {
+	pipeTransport, err := transport.DialNamedPipe(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := pipeTransport.Start(context.Background()); err != nil {
+		_ = pipeTransport.Close()
+		return nil, fmt.Errorf("failed to start named pipe transport: %w", err)
+	}
+	return NewClient(pipeTransport, opts...), nil
+}
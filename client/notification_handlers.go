@@ -0,0 +1,142 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/util"
+)
+
+// notificationHandler is a handler registered for a method pattern.
+type notificationHandler struct {
+	id      uint64
+	pattern string
+	handler func(notification mcp.JSONRPCNotification)
+}
+
+// matches reports whether the handler is registered for method. A pattern
+// of "*" matches every method, and a pattern ending in "*" matches methods
+// with that prefix.
+func (h notificationHandler) matches(method string) bool {
+	if prefix, ok := strings.CutSuffix(h.pattern, "*"); ok {
+		return strings.HasPrefix(method, prefix)
+	}
+	return h.pattern == method
+}
+
+// WithNotificationErrorHandler sets a function called when a notification
+// handler panics or the params of a notification cannot be decoded for a
+// typed handler. The other handlers of the notification still run. By
+// default such errors are logged.
+func WithNotificationErrorHandler(handler func(notification mcp.JSONRPCNotification, err error)) ClientOption {
+	return func(c *Client) {
+		c.notificationErrorHandler = handler
+	}
+}
+
+// OnNotificationMethod registers a handler for notifications with the given
+// method, such as mcp.MethodNotificationProgress. A method ending in "*"
+// registers a wildcard handler: "notifications/resources/*" matches all
+// resource notifications and "*" matches every notification.
+//
+// Handlers run in the order they were registered. A panicking handler is
+// isolated from the others and reported to the WithNotificationErrorHandler
+// handler. The returned function removes the handler.
+func (c *Client) OnNotificationMethod(
+	method string,
+	handler func(notification mcp.JSONRPCNotification),
+) (remove func()) {
+	c.notifyMu.Lock()
+	defer c.notifyMu.Unlock()
+	c.notificationHandlerID++
+	id := c.notificationHandlerID
+	c.notifications = append(c.notifications, notificationHandler{id: id, pattern: method, handler: handler})
+
+	return func() {
+		c.notifyMu.Lock()
+		defer c.notifyMu.Unlock()
+		for i, h := range c.notifications {
+			if h.id == id {
+				c.notifications = append(c.notifications[:i:i], c.notifications[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// OnNotificationParams registers a handler that receives the params of
+// notifications with the given method decoded as T, for example:
+//
+//	client.OnNotificationParams(c, "notifications/resources/updated",
+//	    func(params mcp.ResourceUpdatedNotificationParams) { refresh(params.URI) })
+//
+// Notifications whose params cannot be decoded are reported to the
+// WithNotificationErrorHandler handler. The returned function removes the
+// handler.
+func OnNotificationParams[T any](c *Client, method string, handler func(params T)) (remove func()) {
+	return c.OnNotificationMethod(method, func(notification mcp.JSONRPCNotification) {
+		var params T
+		raw, err := json.Marshal(notification.Params)
+		if err == nil {
+			err = json.Unmarshal(raw, &params)
+		}
+		if err != nil {
+			c.reportNotificationError(notification, fmt.Errorf("failed to decode %s params: %w", notification.Method, err))
+			return
+		}
+		handler(params)
+	})
+}
+
+// OnResourceUpdated registers a handler for notifications/resources/updated.
+func (c *Client) OnResourceUpdated(handler func(params mcp.ResourceUpdatedNotificationParams)) (remove func()) {
+	return OnNotificationParams(c, mcp.MethodNotificationResourceUpdated, handler)
+}
+
+// OnProgress registers a handler for notifications/progress.
+func (c *Client) OnProgress(handler func(params mcp.ProgressNotificationParams)) (remove func()) {
+	return OnNotificationParams(c, mcp.MethodNotificationProgress, handler)
+}
+
+// OnLoggingMessage registers a handler for notifications/message.
+func (c *Client) OnLoggingMessage(handler func(params mcp.LoggingMessageNotificationParams)) (remove func()) {
+	return OnNotificationParams(c, mcp.MethodNotificationMessage, handler)
+}
+
+// dispatchNotification runs the handlers registered for the method of
+// notification.
+func (c *Client) dispatchNotification(notification mcp.JSONRPCNotification) {
+	c.notifyMu.RLock()
+	handlers := make([]notificationHandler, 0, len(c.notifications))
+	for _, h := range c.notifications {
+		if h.matches(notification.Method) {
+			handlers = append(handlers, h)
+		}
+	}
+	c.notifyMu.RUnlock()
+
+	for _, h := range handlers {
+		c.runNotificationHandler(h, notification)
+	}
+}
+
+// runNotificationHandler calls a handler, recovering from a panic so the
+// remaining handlers still run.
+func (c *Client) runNotificationHandler(h notificationHandler, notification mcp.JSONRPCNotification) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.reportNotificationError(notification, fmt.Errorf("panic in %q notification handler: %v", h.pattern, r))
+		}
+	}()
+	h.handler(notification)
+}
+
+func (c *Client) reportNotificationError(notification mcp.JSONRPCNotification, err error) {
+	if c.notificationErrorHandler != nil {
+		c.notificationErrorHandler(notification, err)
+		return
+	}
+	util.DefaultLogger().Errorf("%s notification: %v", notification.Method, err)
+}
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func notification(method string, params map[string]any) mcp.JSONRPCNotification {
+	return mcp.JSONRPCNotification{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Notification: mcp.Notification{
+			Method: method,
+			Params: mcp.NotificationParams{AdditionalFields: params},
+		},
+	}
+}
+
+func TestClient_OnNotificationMethod(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		method   string
+		expected bool
+	}{
+		{name: "exact method", pattern: mcp.MethodNotificationResourceUpdated, method: mcp.MethodNotificationResourceUpdated, expected: true},
+		{name: "other method", pattern: mcp.MethodNotificationResourceUpdated, method: mcp.MethodNotificationProgress},
+		{name: "prefix wildcard", pattern: "notifications/resources/*", method: mcp.MethodNotificationResourcesListChanged, expected: true},
+		{name: "prefix wildcard mismatch", pattern: "notifications/resources/*", method: mcp.MethodNotificationToolsListChanged},
+		{name: "match all", pattern: "*", method: "custom/event", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(nil)
+			called := false
+			client.OnNotificationMethod(tt.pattern, func(mcp.JSONRPCNotification) { called = true })
+			client.dispatchNotification(notification(tt.method, nil))
+			assert.Equal(t, tt.expected, called)
+		})
+	}
+
+	t.Run("remove", func(t *testing.T) {
+		client := NewClient(nil)
+		var calls []string
+		removeFirst := client.OnNotificationMethod("*", func(mcp.JSONRPCNotification) { calls = append(calls, "first") })
+		client.OnNotificationMethod("*", func(mcp.JSONRPCNotification) { calls = append(calls, "second") })
+		removeFirst()
+		removeFirst()
+		client.dispatchNotification(notification("custom/event", nil))
+		assert.Equal(t, []string{"second"}, calls)
+	})
+}
+
+func TestClient_NotificationHandlerPanics(t *testing.T) {
+	var reported []error
+	client := NewClient(nil, WithNotificationErrorHandler(func(notification mcp.JSONRPCNotification, err error) {
+		reported = append(reported, err)
+	}))
+
+	var calls []string
+	client.OnNotificationMethod("custom/event", func(mcp.JSONRPCNotification) { panic("boom") })
+	client.OnNotification(func(mcp.JSONRPCNotification) { calls = append(calls, "after") })
+
+	assert.NotPanics(t, func() { client.dispatchNotification(notification("custom/event", nil)) })
+	assert.Equal(t, []string{"after"}, calls, "later handlers still run")
+	require.Len(t, reported, 1)
+	assert.Contains(t, reported[0].Error(), "boom")
+}
+
+func TestOnNotificationParams(t *testing.T) {
+	var reported []error
+	client := NewClient(nil, WithNotificationErrorHandler(func(notification mcp.JSONRPCNotification, err error) {
+		reported = append(reported, err)
+	}))
+
+	var updated []string
+	client.OnResourceUpdated(func(params mcp.ResourceUpdatedNotificationParams) {
+		updated = append(updated, params.URI)
+	})
+	var progress []float64
+	client.OnProgress(func(params mcp.ProgressNotificationParams) {
+		progress = append(progress, params.Progress)
+	})
+	var logged []mcp.LoggingLevel
+	client.OnLoggingMessage(func(params mcp.LoggingMessageNotificationParams) {
+		logged = append(logged, params.Level)
+	})
+
+	client.dispatchNotification(notification(mcp.MethodNotificationResourceUpdated, map[string]any{"uri": "file:///a"}))
+	client.dispatchNotification(notification(mcp.MethodNotificationProgress, map[string]any{"progressToken": "t", "progress": 0.5}))
+	client.dispatchNotification(notification(mcp.MethodNotificationMessage, map[string]any{"level": "warning", "data": "disk"}))
+	assert.Equal(t, []string{"file:///a"}, updated)
+	assert.Equal(t, []float64{0.5}, progress)
+	assert.Equal(t, []mcp.LoggingLevel{mcp.LoggingLevelWarning}, logged)
+	assert.Empty(t, reported)
+
+	client.dispatchNotification(notification(mcp.MethodNotificationResourceUpdated, map[string]any{"uri": 42}))
+	assert.Len(t, updated, 1)
+	require.Len(t, reported, 1)
+	assert.Contains(t, reported[0].Error(), "failed to decode")
+}
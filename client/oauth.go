@@ -19,9 +19,33 @@ type TokenStore = transport.TokenStore
 // MemoryTokenStore is a convenience type that wraps transport.MemoryTokenStore
 type MemoryTokenStore = transport.MemoryTokenStore
 
+// AuthorizationRequest is a convenience type that wraps transport.AuthorizationRequest
+type AuthorizationRequest = transport.AuthorizationRequest
+
 // NewMemoryTokenStore is a convenience function that wraps transport.NewMemoryTokenStore
 var NewMemoryTokenStore = transport.NewMemoryTokenStore
 
+// FileTokenStore is a convenience type that wraps transport.FileTokenStore
+type FileTokenStore = transport.FileTokenStore
+
+// NewFileTokenStore is a convenience function that wraps transport.NewFileTokenStore
+var NewFileTokenStore = transport.NewFileTokenStore
+
+// GenerateTokenKey is a convenience function that wraps transport.GenerateTokenKey
+var GenerateTokenKey = transport.GenerateTokenKey
+
+// Keyring is a convenience type that wraps transport.Keyring
+type Keyring = transport.Keyring
+
+// KeyringTokenStore is a convenience type that wraps transport.KeyringTokenStore
+type KeyringTokenStore = transport.KeyringTokenStore
+
+// NewKeyringTokenStore is a convenience function that wraps transport.NewKeyringTokenStore
+var NewKeyringTokenStore = transport.NewKeyringTokenStore
+
+// SystemKeyring is a convenience function that wraps transport.SystemKeyring
+var SystemKeyring = transport.SystemKeyring
+
 // NewOAuthStreamableHttpClient creates a new streamable-http-based MCP client with OAuth support.
 // Returns an error if the URL is invalid.
 func NewOAuthStreamableHttpClient(baseURL string, oauthConfig OAuthConfig, options ...transport.StreamableHTTPCOption) (*Client, error) {
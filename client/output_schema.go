@@ -0,0 +1,153 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ErrInvalidStructuredContent is matched by the errors CallTool returns
+// for results whose structured content does not conform to the output
+// schema of the tool.
+var ErrInvalidStructuredContent = errors.New("structured content does not match the output schema")
+
+// OutputSchemaError is returned by CallTool when the structured content of
+// a tool result does not conform to the output schema of the tool, or is
+// missing although the tool declares one.
+type OutputSchemaError struct {
+	// Tool is the name of the tool that was called.
+	Tool string
+	// Result is the result returned by the server.
+	Result *mcp.CallToolResult
+	// Errors lists every place where the structured content does not
+	// conform to the schema.
+	Errors []mcp.SchemaError
+}
+
+func (e *OutputSchemaError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("tool %q: %v: %s", e.Tool, ErrInvalidStructuredContent, strings.Join(messages, "; "))
+}
+
+// Is makes the error match ErrInvalidStructuredContent.
+func (e *OutputSchemaError) Is(target error) bool {
+	return target == ErrInvalidStructuredContent
+}
+
+// WithOutputSchemaValidation makes CallTool validate the structured content
+// of tool results against the output schemas the server lists for its
+// tools, as seen by the last ListTools or ListToolsByPage call. Schemas
+// registered with RegisterOutputSchema are used even without this option,
+// and take precedence over the listed ones. Results marked as errors are
+// not validated.
+func WithOutputSchemaValidation() ClientOption {
+	return func(c *Client) {
+		c.outputSchemas.useListed = true
+	}
+}
+
+// RegisterOutputSchema makes CallTool validate the structured content of
+// the results of the named tool against schema, whatever the server lists.
+// The schema may be any form mcp.ValidateAgainstSchema accepts; a nil
+// schema removes the registration. An error wrapping mcp.ErrInvalidSchema
+// is returned if schema is not well-formed.
+func (c *Client) RegisterOutputSchema(tool string, schema any) error {
+	if schema == nil {
+		c.outputSchemas.register(tool, nil)
+		return nil
+	}
+	if err := mcp.ValidateSchema(schema); err != nil {
+		return fmt.Errorf("output schema of tool %q: %w", tool, err)
+	}
+	c.outputSchemas.register(tool, schema)
+	return nil
+}
+
+// outputSchemas holds the output schemas tool results are validated
+// against: those registered by the host and, if useListed is set, those
+// listed by the server.
+type outputSchemas struct {
+	useListed bool
+
+	mu         sync.RWMutex
+	registered map[string]any
+	listed     map[string]mcp.ToolOutputSchema
+}
+
+func (o *outputSchemas) register(tool string, schema any) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if schema == nil {
+		delete(o.registered, tool)
+		return
+	}
+	if o.registered == nil {
+		o.registered = make(map[string]any)
+	}
+	o.registered[tool] = schema
+}
+
+// addListed remembers the output schemas of tools listed by the server.
+func (o *outputSchemas) addListed(tools []mcp.Tool) {
+	if !o.useListed {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.listed == nil {
+		o.listed = make(map[string]mcp.ToolOutputSchema, len(tools))
+	}
+	for _, tool := range tools {
+		if tool.OutputSchema.Type == "" {
+			delete(o.listed, tool.Name)
+			continue
+		}
+		o.listed[tool.Name] = tool.OutputSchema
+	}
+}
+
+// get returns the schema results of the named tool are validated against.
+func (o *outputSchemas) get(tool string) (any, bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	if schema, ok := o.registered[tool]; ok {
+		return schema, true
+	}
+	if schema, ok := o.listed[tool]; ok {
+		return schema, true
+	}
+	return nil, false
+}
+
+// validate checks the structured content of a result of the named tool
+// against its output schema, if it has one.
+func (o *outputSchemas) validate(tool string, result *mcp.CallToolResult) error {
+	if result.IsError {
+		return nil
+	}
+	schema, ok := o.get(tool)
+	if !ok {
+		return nil
+	}
+	if result.StructuredContent == nil {
+		return &OutputSchemaError{
+			Tool:   tool,
+			Result: result,
+			Errors: []mcp.SchemaError{{Message: "result has no structured content"}},
+		}
+	}
+	schemaErrors, err := mcp.ValidateAgainstSchema(schema, result.StructuredContent)
+	if err != nil {
+		return fmt.Errorf("validating structured content of tool %q: %w", tool, err)
+	}
+	if len(schemaErrors) > 0 {
+		return &OutputSchemaError{Tool: tool, Result: result, Errors: schemaErrors}
+	}
+	return nil
+}
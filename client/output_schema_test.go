@@ -0,0 +1,122 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type weather struct {
+	City        string  `json:"city"`
+	Temperature float64 `json:"temperature"`
+}
+
+// newOutputSchemaServer serves tools declaring a weather output schema;
+// "broken" returns structured content that does not conform to it.
+func newOutputSchemaServer() *server.MCPServer {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0", server.WithToolCapabilities(false))
+	structured := func(content any) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultStructured(content, "result"), nil
+		}
+	}
+	mcpServer.AddTool(mcp.NewTool("weather", mcp.WithOutputSchema[weather]()),
+		structured(weather{City: "Paris", Temperature: 21}))
+	mcpServer.AddTool(mcp.NewTool("broken", mcp.WithOutputSchema[weather]()),
+		structured(map[string]any{"city": 42}))
+	mcpServer.AddTool(mcp.NewTool("text", mcp.WithOutputSchema[weather]()),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("sunny"), nil
+		})
+	mcpServer.AddTool(mcp.NewTool("failing", mcp.WithOutputSchema[weather]()),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultError("no weather today"), nil
+		})
+	mcpServer.AddTool(mcp.NewTool("untyped"), structured(map[string]any{"anything": true}))
+	return mcpServer
+}
+
+func callNamedTool(client *Client, name string) (*mcp.CallToolResult, error) {
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	return client.CallTool(context.Background(), request)
+}
+
+func TestClient_OutputSchemaValidation(t *testing.T) {
+	client, _ := newListCacheClient(t, newOutputSchemaServer(), WithOutputSchemaValidation())
+
+	// Before listing, no schema is known.
+	_, err := callNamedTool(client, "broken")
+	require.NoError(t, err)
+
+	_, err = client.ListTools(context.Background(), mcp.ListToolsRequest{})
+	require.NoError(t, err)
+
+	tests := []struct {
+		tool       string
+		wantErrors []string
+	}{
+		{tool: "weather"},
+		{tool: "untyped"},
+		{tool: "failing"},
+		{tool: "broken", wantErrors: []string{"/city", "/temperature"}},
+		{tool: "text", wantErrors: []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			result, err := callNamedTool(client, tt.tool)
+			if tt.wantErrors == nil {
+				require.NoError(t, err)
+				assert.NotNil(t, result)
+				return
+			}
+			assert.Nil(t, result)
+			require.ErrorIs(t, err, ErrInvalidStructuredContent)
+			var schemaErr *OutputSchemaError
+			require.ErrorAs(t, err, &schemaErr)
+			assert.Equal(t, tt.tool, schemaErr.Tool)
+			require.NotNil(t, schemaErr.Result)
+			paths := make([]string, len(schemaErr.Errors))
+			for i, e := range schemaErr.Errors {
+				paths[i] = e.Path
+			}
+			assert.ElementsMatch(t, tt.wantErrors, paths)
+		})
+	}
+}
+
+func TestClient_RegisterOutputSchema(t *testing.T) {
+	client, _ := newListCacheClient(t, newOutputSchemaServer())
+
+	// Listed schemas are not used without WithOutputSchemaValidation.
+	_, err := client.ListTools(context.Background(), mcp.ListToolsRequest{})
+	require.NoError(t, err)
+	_, err = callNamedTool(client, "broken")
+	require.NoError(t, err)
+
+	// Registered schemas are used whatever the server lists.
+	require.NoError(t, client.RegisterOutputSchema("untyped", json.RawMessage(`{
+		"type": "object",
+		"properties": {"anything": {"type": "string"}},
+		"required": ["anything"]
+	}`)))
+	_, err = callNamedTool(client, "untyped")
+	var schemaErr *OutputSchemaError
+	require.ErrorAs(t, err, &schemaErr)
+	require.Len(t, schemaErr.Errors, 1)
+	assert.Equal(t, "/anything", schemaErr.Errors[0].Path)
+	assert.Contains(t, err.Error(), `tool "untyped": structured content does not match the output schema: /anything:`)
+
+	require.NoError(t, client.RegisterOutputSchema("untyped", nil))
+	_, err = callNamedTool(client, "untyped")
+	require.NoError(t, err)
+
+	err = client.RegisterOutputSchema("untyped", map[string]any{"type": 42})
+	assert.ErrorIs(t, err, mcp.ErrInvalidSchema)
+}
@@ -0,0 +1,178 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync/atomic"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// PoolStrategy selects the session of a Pool serving a tool call.
+type PoolStrategy int
+
+const (
+	// PoolRoundRobin uses the sessions in turn.
+	PoolRoundRobin PoolStrategy = iota
+	// PoolLeastBusy uses the session with the fewest calls in flight.
+	PoolLeastBusy
+)
+
+// PoolOption configures NewStreamableHTTPPool.
+type PoolOption func(*poolConfig)
+
+type poolConfig struct {
+	size             int
+	strategy         PoolStrategy
+	clientOptions    []ClientOption
+	transportOptions []transport.StreamableHTTPCOption
+}
+
+// WithPoolSize sets the number of sessions of the pool, 4 by default.
+func WithPoolSize(size int) PoolOption {
+	return func(c *poolConfig) {
+		c.size = size
+	}
+}
+
+// WithPoolStrategy sets how tool calls are distributed across the sessions,
+// PoolRoundRobin by default.
+func WithPoolStrategy(strategy PoolStrategy) PoolOption {
+	return func(c *poolConfig) {
+		c.strategy = strategy
+	}
+}
+
+// WithPoolClientOptions sets the options of the client of every session.
+// Use WithHealthCheck to have the pool skip sessions whose server stopped
+// answering.
+func WithPoolClientOptions(opts ...ClientOption) PoolOption {
+	return func(c *poolConfig) {
+		c.clientOptions = append(c.clientOptions, opts...)
+	}
+}
+
+// WithPoolTransportOptions sets the options of the transport of every
+// session.
+func WithPoolTransportOptions(opts ...transport.StreamableHTTPCOption) PoolOption {
+	return func(c *poolConfig) {
+		c.transportOptions = append(c.transportOptions, opts...)
+	}
+}
+
+// Pool maintains several StreamableHTTP sessions to the same server and
+// distributes tool calls across them, spreading the load of stateless tools
+// over the server's session handling. Operations relying on session state,
+// such as subscriptions or per-session tools, should use the client
+// returned by Client for a key, which always maps to the same session.
+type Pool struct {
+	clients  []*Client
+	inFlight []atomic.Int64
+	strategy PoolStrategy
+	next     atomic.Uint64
+}
+
+// NewStreamableHTTPPool creates a pool of StreamableHTTP clients of the
+// server at baseURL. Call Initialize to start and initialize its sessions.
+func NewStreamableHTTPPool(baseURL string, opts ...PoolOption) (*Pool, error) {
+	config := poolConfig{size: 4}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.size < 1 {
+		return nil, fmt.Errorf("pool size must be positive, got %d", config.size)
+	}
+
+	p := &Pool{
+		clients:  make([]*Client, config.size),
+		inFlight: make([]atomic.Int64, config.size),
+		strategy: config.strategy,
+	}
+	for i := range p.clients {
+		trans, err := transport.NewStreamableHTTP(baseURL, config.transportOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create transport: %w", err)
+		}
+		p.clients[i] = NewClient(trans, config.clientOptions...)
+	}
+	return p, nil
+}
+
+// Initialize starts every session of the pool and initializes it with
+// request, returning the result of the first session.
+func (p *Pool) Initialize(ctx context.Context, request mcp.InitializeRequest) (*mcp.InitializeResult, error) {
+	var first *mcp.InitializeResult
+	for i, c := range p.clients {
+		if err := c.Start(ctx); err != nil {
+			return nil, fmt.Errorf("failed to start session %d: %w", i, err)
+		}
+		result, err := c.Initialize(ctx, request)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize session %d: %w", i, err)
+		}
+		if first == nil {
+			first = result
+		}
+	}
+	return first, nil
+}
+
+// CallTool calls a tool on one of the sessions of the pool, chosen by the
+// pool strategy among the healthy ones.
+func (p *Pool) CallTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	i := p.pick()
+	p.inFlight[i].Add(1)
+	defer p.inFlight[i].Add(-1)
+	return p.clients[i].CallTool(ctx, request)
+}
+
+// Client returns the client of the session key is bound to. A key always
+// maps to the same session, whatever its health, so that stateful
+// operations see a consistent session.
+func (p *Pool) Client(key string) *Client {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return p.clients[int(h.Sum32()%uint32(len(p.clients)))]
+}
+
+// Clients returns the clients of all the sessions of the pool.
+func (p *Pool) Clients() []*Client {
+	return append([]*Client(nil), p.clients...)
+}
+
+// Close closes every session of the pool.
+func (p *Pool) Close() error {
+	var errs []error
+	for _, c := range p.clients {
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pick returns the index of the session serving the next call. Unhealthy
+// sessions are skipped unless they all are.
+func (p *Pool) pick() int {
+	start := int(p.next.Add(1)-1) % len(p.clients)
+	best := -1
+	for offset := range p.clients {
+		i := (start + offset) % len(p.clients)
+		if p.clients[i].Health() == HealthUnhealthy {
+			continue
+		}
+		if p.strategy == PoolRoundRobin {
+			return i
+		}
+		if best < 0 || p.inFlight[i].Load() < p.inFlight[best].Load() {
+			best = i
+		}
+	}
+	if best < 0 {
+		return start
+	}
+	return best
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// newPoolTestServer serves a tool answering with the session serving the
+// call, which blocks while release is open.
+func newPoolTestServer(t *testing.T, release <-chan struct{}) string {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("session"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if request.GetBool("block", false) {
+			<-release
+		}
+		return mcp.NewToolResultText(server.ClientSessionFromContext(ctx).SessionID()), nil
+	})
+	httpServer := server.NewTestStreamableHTTPServer(mcpServer)
+	t.Cleanup(httpServer.Close)
+	return httpServer.URL
+}
+
+func startPool(t *testing.T, url string, opts ...PoolOption) *Pool {
+	pool, err := NewStreamableHTTPPool(url, opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { pool.Close() })
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	_, err = pool.Initialize(context.Background(), initRequest)
+	require.NoError(t, err)
+	return pool
+}
+
+func callSession(t *testing.T, pool *Pool, block bool) string {
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "session"
+	request.Params.Arguments = map[string]any{"block": block}
+	result, err := pool.CallTool(context.Background(), request)
+	require.NoError(t, err)
+	return result.Content[0].(mcp.TextContent).Text
+}
+
+func TestPool_RoundRobin(t *testing.T) {
+	pool := startPool(t, newPoolTestServer(t, nil), WithPoolSize(3))
+
+	counts := make(map[string]int)
+	for range 6 {
+		counts[callSession(t, pool, false)]++
+	}
+	assert.Len(t, counts, 3, "every session is used")
+	for session, count := range counts {
+		assert.Equal(t, 2, count, session)
+	}
+}
+
+func TestPool_SkipsUnhealthySessions(t *testing.T) {
+	pool := startPool(t, newPoolTestServer(t, nil), WithPoolSize(2),
+		WithPoolClientOptions(WithHealthCheck(time.Hour, 1)))
+
+	unhealthy := pool.Clients()[0]
+	unhealthy.health.set(HealthUnhealthy)
+	for range 4 {
+		assert.NotEqual(t, unhealthy.GetSessionId(), callSession(t, pool, false))
+	}
+}
+
+func TestPool_LeastBusy(t *testing.T) {
+	release := make(chan struct{})
+	pool := startPool(t, newPoolTestServer(t, release), WithPoolSize(2), WithPoolStrategy(PoolLeastBusy))
+
+	var wg sync.WaitGroup
+	blocked := make(chan string, 1)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		blocked <- callSession(t, pool, true)
+	}()
+	require.Eventually(t, func() bool {
+		return pool.inFlight[0].Load()+pool.inFlight[1].Load() == 1
+	}, time.Second, time.Millisecond)
+
+	busy := pool.Clients()[0].GetSessionId()
+	if pool.inFlight[1].Load() == 1 {
+		busy = pool.Clients()[1].GetSessionId()
+	}
+	for range 3 {
+		assert.NotEqual(t, busy, callSession(t, pool, false), "calls avoid the busy session")
+	}
+	close(release)
+	wg.Wait()
+	assert.Equal(t, busy, <-blocked)
+}
+
+func TestPool_Affinity(t *testing.T) {
+	pool := startPool(t, newPoolTestServer(t, nil), WithPoolSize(4))
+	for _, key := range []string{"alice", "bob"} {
+		c := pool.Client(key)
+		for range 3 {
+			assert.Same(t, c, pool.Client(key))
+		}
+	}
+	assert.Len(t, pool.Clients(), 4)
+
+	_, err := NewStreamableHTTPPool("http://localhost", WithPoolSize(0))
+	assert.Error(t, err)
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// requestNotificationsKey is the context key for WithRequestNotifications.
+type requestNotificationsKey struct{}
+
+// WithRequestNotifications returns a copy of ctx that streams the
+// notifications belonging to a single in-flight call to handler while the
+// call is running, so hosts can render live activity instead of waiting for
+// the assembled result:
+//
+//	ctx := client.WithRequestNotifications(ctx, func(n mcp.JSONRPCNotification) {
+//	    if n.Method == "notifications/progress" {
+//	        fmt.Println(n.Params.AdditionalFields["progress"])
+//	    }
+//	})
+//	result, err := c.CallTool(ctx, request)
+//
+// CallTool requests a progress token when the request does not carry one,
+// so progress notifications reach handler on every transport. Over
+// StreamableHTTP, handler additionally receives everything the server sends
+// on the call's response stream, such as log messages. Handlers registered
+// with OnNotification are still called for every notification.
+//
+// handler runs on the transport's read loop and must not block.
+func WithRequestNotifications(ctx context.Context, handler func(notification mcp.JSONRPCNotification)) context.Context {
+	return context.WithValue(ctx, requestNotificationsKey{}, handler)
+}
+
+func requestNotificationsFromContext(ctx context.Context) func(notification mcp.JSONRPCNotification) {
+	handler, _ := ctx.Value(requestNotificationsKey{}).(func(notification mcp.JSONRPCNotification))
+	return handler
+}
+
+// requestNotifications tracks a call that has a WithRequestNotifications
+// handler.
+type requestNotifications struct {
+	handler func(notification mcp.JSONRPCNotification)
+	// streamed is set once the transport has delivered a notification from
+	// the call's own response stream. From then on progress notifications
+	// arrive that way, and routing them by token would deliver them twice.
+	streamed atomic.Bool
+}
+
+// trackRequestNotifications prepares ctx and meta for a call whose context
+// carries a WithRequestNotifications handler. It returns the context to send
+// the request with, the meta to send (a copy with a progress token added if
+// needed) and a function that stops tracking once the call returns.
+func (c *Client) trackRequestNotifications(ctx context.Context, meta *mcp.Meta) (context.Context, *mcp.Meta, func()) {
+	handler := requestNotificationsFromContext(ctx)
+	if handler == nil {
+		return ctx, meta, func() {}
+	}
+
+	tracked := &requestNotifications{handler: handler}
+	ctx = transport.WithRequestNotificationHandler(ctx, func(notification mcp.JSONRPCNotification) {
+		tracked.streamed.Store(true)
+		handler(notification)
+	})
+
+	if meta != nil && meta.ProgressToken != nil {
+		token := fmt.Sprint(meta.ProgressToken)
+		c.requestNotifications.Store(token, tracked)
+		return ctx, meta, func() { c.requestNotifications.Delete(token) }
+	}
+
+	token := fmt.Sprintf("mcp-go-progress-%d", c.progressTokenID.Add(1))
+	withToken := &mcp.Meta{ProgressToken: token}
+	if meta != nil {
+		withToken.AdditionalFields = meta.AdditionalFields
+	}
+	c.requestNotifications.Store(token, tracked)
+	return ctx, withToken, func() { c.requestNotifications.Delete(token) }
+}
+
+// routeRequestNotification delivers a progress notification to the call
+// that owns its progress token, unless that call already receives its
+// notifications from a per-request stream.
+func (c *Client) routeRequestNotification(notification mcp.JSONRPCNotification) {
+	if notification.Method != mcp.MethodNotificationProgress {
+		return
+	}
+	token, ok := notification.Params.AdditionalFields["progressToken"]
+	if !ok || token == nil {
+		return
+	}
+	value, ok := c.requestNotifications.Load(fmt.Sprint(token))
+	if !ok {
+		return
+	}
+	tracked := value.(*requestNotifications)
+	if tracked.streamed.Load() {
+		return
+	}
+	tracked.handler(notification)
+}
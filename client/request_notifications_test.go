@@ -0,0 +1,145 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// newProgressServer returns a server with a "work" tool that reports two
+// progress steps and a log message before returning.
+func newProgressServer() *server.MCPServer {
+	hooks := &server.Hooks{}
+	hooks.AddAfterCallTool(func(ctx context.Context, id any, message *mcp.CallToolRequest, result *mcp.CallToolResult) {
+		// wait until all the notifications are written before the response
+		clientSession := server.ClientSessionFromContext(ctx)
+		for len(clientSession.NotificationChannel()) > 0 {
+		}
+		time.Sleep(50 * time.Millisecond)
+	})
+
+	mcpServer := server.NewMCPServer("test-server", "1.0.0",
+		server.WithToolCapabilities(true),
+		server.WithHooks(hooks),
+	)
+	mcpServer.AddTool(mcp.NewTool("work"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		srv := server.ServerFromContext(ctx)
+		var token mcp.ProgressToken
+		if request.Params.Meta != nil {
+			token = request.Params.Meta.ProgressToken
+		}
+		for step := 1; step <= 2; step++ {
+			if err := srv.SendNotificationToClient(ctx, mcp.MethodNotificationProgress, map[string]any{
+				"progressToken": token,
+				"progress":      step,
+				"total":         2,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		if err := srv.SendNotificationToClient(ctx, "notifications/message", map[string]any{
+			"level": "info",
+			"data":  "halfway there",
+		}); err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText("done"), nil
+	})
+	return mcpServer
+}
+
+type notificationRecorder struct {
+	mu            sync.Mutex
+	notifications []mcp.JSONRPCNotification
+}
+
+func (r *notificationRecorder) record(notification mcp.JSONRPCNotification) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.notifications = append(r.notifications, notification)
+}
+
+func (r *notificationRecorder) methods() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var methods []string
+	for _, n := range r.notifications {
+		methods = append(methods, n.Method)
+	}
+	return methods
+}
+
+func initializeTestClient(t *testing.T, c *Client) {
+	t.Helper()
+	ctx := context.Background()
+	require.NoError(t, c.Start(ctx))
+	request := mcp.InitializeRequest{}
+	request.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	request.Params.ClientInfo = mcp.Implementation{Name: "test-client", Version: "1.0.0"}
+	_, err := c.Initialize(ctx, request)
+	require.NoError(t, err)
+}
+
+func TestClient_WithRequestNotifications_StreamableHTTP(t *testing.T) {
+	testServer := server.NewTestStreamableHTTPServer(newProgressServer())
+	defer testServer.Close()
+
+	c, err := NewStreamableHttpClient(testServer.URL)
+	require.NoError(t, err)
+	defer c.Close()
+
+	var global notificationRecorder
+	c.OnNotification(global.record)
+	initializeTestClient(t, c)
+
+	var events notificationRecorder
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "work"
+	result, err := c.CallTool(WithRequestNotifications(context.Background(), events.record), request)
+	require.NoError(t, err)
+	require.Len(t, result.Content, 1)
+
+	expected := []string{mcp.MethodNotificationProgress, mcp.MethodNotificationProgress, "notifications/message"}
+	assert.Equal(t, expected, events.methods(), "stream notifications are delivered once each")
+	assert.Equal(t, expected, global.methods(), "global handlers still see every notification")
+
+	events.mu.Lock()
+	defer events.mu.Unlock()
+	assert.NotNil(t, events.notifications[0].Params.AdditionalFields["progressToken"], "a progress token is requested")
+	assert.EqualValues(t, 2, events.notifications[1].Params.AdditionalFields["progress"])
+}
+
+func TestClient_WithRequestNotifications_SSE(t *testing.T) {
+	testServer := server.NewTestServer(newProgressServer())
+	defer testServer.Close()
+
+	c, err := NewSSEMCPClient(testServer.URL + "/sse")
+	require.NoError(t, err)
+	defer c.Close()
+	initializeTestClient(t, c)
+
+	var events notificationRecorder
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "work"
+	request.Params.Meta = &mcp.Meta{ProgressToken: "my-token"}
+	_, err = c.CallTool(WithRequestNotifications(context.Background(), events.record), request)
+	require.NoError(t, err)
+
+	// Without a per-request stream only progress can be attributed to the call.
+	require.Eventually(t, func() bool { return len(events.methods()) == 2 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{mcp.MethodNotificationProgress, mcp.MethodNotificationProgress}, events.methods())
+	events.mu.Lock()
+	assert.Equal(t, "my-token", events.notifications[0].Params.AdditionalFields["progressToken"])
+	events.mu.Unlock()
+
+	// Once the call has returned its token is no longer routed.
+	c.routeRequestNotification(events.notifications[0])
+	assert.Len(t, events.methods(), 2)
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"maps"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// WithResourceCache keeps the last contents read for each resource URI
+// along with their validators, and makes ReadResource send conditional
+// reads for the URIs it holds. When the server answers that the contents
+// did not change, ReadResource returns the cached contents with the _meta
+// of the answer, for which mcp.IsNotModified reports true.
+//
+// Servers declare validators with server.WithResourceCaching; contents
+// read from other servers are not cached. Reads that are already
+// conditional, see mcp.SetConditionalRead, bypass the cache.
+func WithResourceCache() ClientOption {
+	return func(c *Client) {
+		c.resourceCache = &resourceCache{entries: make(map[string]resourceCacheEntry)}
+	}
+}
+
+// resourceCache holds the last contents read by URI.
+type resourceCache struct {
+	mu      sync.Mutex
+	entries map[string]resourceCacheEntry
+}
+
+type resourceCacheEntry struct {
+	contents   []mcp.ResourceContents
+	validators mcp.ResourceValidators
+}
+
+func (rc *resourceCache) get(uri string) (resourceCacheEntry, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	entry, ok := rc.entries[uri]
+	return entry, ok
+}
+
+func (rc *resourceCache) set(uri string, entry resourceCacheEntry) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.entries[uri] = entry
+}
+
+// readCachedResource reads a resource conditionally on the contents of the
+// cache having changed.
+func (c *Client) readCachedResource(ctx context.Context, request mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := request.Params.URI
+	cached, ok := c.resourceCache.get(uri)
+	if ok {
+		if request.Params.Meta != nil {
+			meta := *request.Params.Meta
+			meta.AdditionalFields = maps.Clone(meta.AdditionalFields)
+			request.Params.Meta = &meta
+		}
+		mcp.SetConditionalRead(&request, cached.validators)
+	}
+
+	result, err := c.readResource(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if mcp.IsNotModified(result) {
+		if ok {
+			result.Contents = cached.contents
+		}
+		return result, nil
+	}
+	if validators := mcp.ResourceValidatorsFromResult(result); !validators.IsZero() {
+		c.resourceCache.set(uri, resourceCacheEntry{contents: result.Contents, validators: validators})
+	}
+	return result, nil
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestClient_WithResourceCache(t *testing.T) {
+	version := "v1"
+	handlerCalls := 0
+	mcpServer := server.NewMCPServer("test-server", "1.0.0", server.WithResourceCaching())
+	mcpServer.AddResource(mcp.NewResource("docs://manual", "Manual"), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		handlerCalls++
+		server.SetResourceValidators(ctx, mcp.ResourceValidators{ETag: `"` + version + `"`})
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: "manual " + version}}, nil
+	})
+
+	client, counter := newListCacheClient(t, mcpServer, WithResourceCache())
+	read := func() *mcp.ReadResourceResult {
+		t.Helper()
+		request := mcp.ReadResourceRequest{}
+		request.Params.URI = "docs://manual"
+		result, err := client.ReadResource(context.Background(), request)
+		require.NoError(t, err)
+		return result
+	}
+	text := func(result *mcp.ReadResourceResult) string {
+		t.Helper()
+		require.Len(t, result.Contents, 1)
+		content, ok := mcp.AsTextResourceContents(result.Contents[0])
+		require.True(t, ok)
+		return content.Text
+	}
+
+	first := read()
+	assert.Equal(t, "manual v1", text(first))
+	assert.False(t, mcp.IsNotModified(first))
+
+	second := read()
+	assert.Equal(t, "manual v1", text(second), "cached contents are returned")
+	assert.True(t, mcp.IsNotModified(second))
+	assert.Equal(t, 2, counter.count(string(mcp.MethodResourcesRead)))
+	assert.Equal(t, 1, handlerCalls)
+
+	version = "v2"
+	mcpServer.NotifyResourceUpdated("docs://manual")
+	third := read()
+	assert.Equal(t, "manual v2", text(third))
+	assert.False(t, mcp.IsNotModified(third))
+	assert.Equal(t, mcp.ResourceValidators{ETag: `"v2"`}, mcp.ResourceValidatorsFromResult(third))
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+
+	"github.com/yosida95/uritemplate/v3"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ResourceTemplateInfo is a resource template of the server together with
+// the variables its URI template expands.
+type ResourceTemplateInfo struct {
+	mcp.ResourceTemplate
+	// Variables are the names of the variables of the URI template, in the
+	// order they first appear.
+	Variables []string
+}
+
+// Expand expands the URI template with vars, see ExpandResourceTemplate.
+func (t ResourceTemplateInfo) Expand(vars map[string]any) (string, error) {
+	if t.URITemplate == nil || t.URITemplate.Template == nil {
+		return "", fmt.Errorf("resource template %q has no URI template", t.Name)
+	}
+	return expandTemplate(t.URITemplate.Template, vars)
+}
+
+// ResourceTemplateInfos returns the resource templates of the server with
+// their variables. The templates come from CachedResourceTemplates, so they
+// are only listed again when WithListCache is not used or the list changed.
+func (c *Client) ResourceTemplateInfos(ctx context.Context) ([]ResourceTemplateInfo, error) {
+	templates, err := c.CachedResourceTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]ResourceTemplateInfo, len(templates))
+	for i, template := range templates {
+		infos[i] = ResourceTemplateInfo{ResourceTemplate: template}
+		if template.URITemplate != nil && template.URITemplate.Template != nil {
+			infos[i].Variables = template.URITemplate.Varnames()
+		}
+	}
+	return infos, nil
+}
+
+// ReadTemplatedResource expands templateURI with vars, see
+// ExpandResourceTemplate, and reads the resulting resource.
+func (c *Client) ReadTemplatedResource(
+	ctx context.Context,
+	templateURI string,
+	vars map[string]any,
+) (*mcp.ReadResourceResult, error) {
+	uri, err := ExpandResourceTemplate(templateURI, vars)
+	if err != nil {
+		return nil, err
+	}
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = uri
+	return c.ReadResource(ctx, request)
+}
+
+// ExpandResourceTemplate expands an RFC 6570 URI template with vars.
+// Strings, booleans, numbers and fmt.Stringers are expanded as strings,
+// slices and arrays as lists and maps with string keys as associative
+// arrays, in key order. Variables that are missing or nil are undefined and
+// expand to nothing. An error is returned for values of other types and for
+// variables the template does not declare, which are most likely typos.
+func ExpandResourceTemplate(templateURI string, vars map[string]any) (string, error) {
+	template, err := uritemplate.New(templateURI)
+	if err != nil {
+		return "", fmt.Errorf("invalid URI template %q: %w", templateURI, err)
+	}
+	return expandTemplate(template, vars)
+}
+
+func expandTemplate(template *uritemplate.Template, vars map[string]any) (string, error) {
+	names := template.Varnames()
+	values := uritemplate.Values{}
+	for name, v := range vars {
+		if !slices.Contains(names, name) {
+			return "", fmt.Errorf("URI template %q has no variable %q", template.Raw(), name)
+		}
+		if v == nil {
+			continue
+		}
+		value, err := templateValue(v)
+		if err != nil {
+			return "", fmt.Errorf("variable %q: %w", name, err)
+		}
+		values.Set(name, value)
+	}
+	return template.Expand(values)
+}
+
+// templateValue converts v to a URI template value.
+func templateValue(v any) (uritemplate.Value, error) {
+	if s, ok := templateString(v); ok {
+		return uritemplate.String(s), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		list := make([]string, rv.Len())
+		for i := range list {
+			s, ok := templateString(rv.Index(i).Interface())
+			if !ok {
+				return uritemplate.Value{}, fmt.Errorf("unsupported list element of type %T", rv.Index(i).Interface())
+			}
+			list[i] = s
+		}
+		return uritemplate.List(list...), nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return uritemplate.Value{}, fmt.Errorf("unsupported map key type %s", rv.Type().Key())
+		}
+		keys := make([]string, 0, rv.Len())
+		for _, key := range rv.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+		kv := make([]string, 0, 2*len(keys))
+		for _, key := range keys {
+			element := rv.MapIndex(reflect.ValueOf(key).Convert(rv.Type().Key())).Interface()
+			s, ok := templateString(element)
+			if !ok {
+				return uritemplate.Value{}, fmt.Errorf("unsupported value of type %T for key %q", element, key)
+			}
+			kv = append(kv, key, s)
+		}
+		return uritemplate.KV(kv...), nil
+	}
+	return uritemplate.Value{}, fmt.Errorf("unsupported value of type %T", v)
+}
+
+// templateString formats scalar values expanded as strings.
+func templateString(v any) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case fmt.Stringer:
+		return v.String(), true
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v), true
+	}
+	return "", false
+}
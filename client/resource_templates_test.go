@@ -0,0 +1,132 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestExpandResourceTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		vars     map[string]any
+		expected string
+		wantErr  string
+	}{
+		{
+			name:     "simple string",
+			template: "users://{id}/profile",
+			vars:     map[string]any{"id": "a b"},
+			expected: "users://a%20b/profile",
+		},
+		{
+			name:     "numbers and booleans",
+			template: "items://{id}{?page,draft}",
+			vars:     map[string]any{"id": 42, "page": 1.5, "draft": true},
+			expected: "items://42?page=1.5&draft=true",
+		},
+		{
+			name:     "exploded path list",
+			template: "file:///root{/path*}",
+			vars:     map[string]any{"path": []string{"docs", "read me.md"}},
+			expected: "file:///root/docs/read%20me.md",
+		},
+		{
+			name:     "reserved expansion",
+			template: "file:///{+path}",
+			vars:     map[string]any{"path": "docs/readme.md"},
+			expected: "file:///docs/readme.md",
+		},
+		{
+			name:     "associative array in key order",
+			template: "search://q{?filters*}",
+			vars:     map[string]any{"filters": map[string]any{"lang": "go", "author": "me"}},
+			expected: "search://q?author=me&lang=go",
+		},
+		{
+			name:     "missing and nil variables are undefined",
+			template: "items://{id}{?page}",
+			vars:     map[string]any{"page": nil},
+			expected: "items://",
+		},
+		{
+			name:     "duration as stringer",
+			template: "metrics://cpu{?window}",
+			vars:     map[string]any{"window": time.Minute},
+			expected: "metrics://cpu?window=1m0s",
+		},
+		{
+			name:     "unknown variable",
+			template: "users://{id}",
+			vars:     map[string]any{"ID": "1"},
+			wantErr:  `has no variable "ID"`,
+		},
+		{
+			name:     "unsupported value",
+			template: "users://{id}",
+			vars:     map[string]any{"id": struct{}{}},
+			wantErr:  `variable "id": unsupported value`,
+		},
+		{
+			name:     "invalid template",
+			template: "users://{id",
+			wantErr:  "invalid URI template",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uri, err := ExpandResourceTemplate(tt.template, tt.vars)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, uri)
+		})
+	}
+}
+
+func TestClient_ReadTemplatedResource(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0", server.WithResourceCapabilities(false, false))
+	mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate("users://{id}/posts{?limit}", "posts", mcp.WithTemplateMIMEType("text/plain")),
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: "posts"}}, nil
+		})
+
+	client, err := NewInProcessClient(mcpServer)
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Start(context.Background()))
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	_, err = client.Initialize(context.Background(), initRequest)
+	require.NoError(t, err)
+
+	infos, err := client.ResourceTemplateInfos(context.Background())
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	assert.Equal(t, "posts", infos[0].Name)
+	assert.Equal(t, "text/plain", infos[0].MIMEType)
+	assert.Equal(t, []string{"id", "limit"}, infos[0].Variables)
+
+	uri, err := infos[0].Expand(map[string]any{"id": 7, "limit": 10})
+	require.NoError(t, err)
+	assert.Equal(t, "users://7/posts?limit=10", uri)
+
+	result, err := client.ReadTemplatedResource(context.Background(), infos[0].URITemplate.Raw(), map[string]any{"id": 7})
+	require.NoError(t, err)
+	require.Len(t, result.Contents, 1)
+	assert.Equal(t, "users://7/posts", result.Contents[0].(mcp.TextResourceContents).URI)
+
+	_, err = client.ReadTemplatedResource(context.Background(), "users://{id}", map[string]any{"name": "x"})
+	assert.Error(t, err)
+}
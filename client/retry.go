@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ToolRetryPolicy controls how CallTool retries failed calls. Only calls to
+// tools that are safe to repeat are retried: tools the server annotates as
+// idempotent or read-only, as seen by the last ListTools or ListToolsByPage
+// call, and tools named in AllowedTools. Retries are delayed with
+// exponential backoff, doubling from InitialBackoff up to MaxBackoff.
+type ToolRetryPolicy struct {
+	// MaxAttempts is the number of attempts made, including the first.
+	// Defaults to 3.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry. Defaults to
+	// 100ms.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between retries. Defaults to 5s.
+	MaxBackoff time.Duration
+	// MaxElapsed bounds the time spent on a call, retries and delays
+	// included: no retry is attempted that would start after it. Zero
+	// means no bound other than MaxAttempts and the context of the call.
+	MaxElapsed time.Duration
+	// AllowedTools are retried whatever their annotations.
+	AllowedTools []string
+	// Retryable reports whether a failed attempt is retried. Defaults to
+	// retrying transport errors, including request timeouts, but not the
+	// errors returned by the server.
+	Retryable func(err error) bool
+	// OnRetry, if set, is called before each retry.
+	OnRetry func(ctx context.Context, event RetryEvent)
+}
+
+// RetryEvent describes a retry of a tool call.
+type RetryEvent struct {
+	// Tool is the name of the tool being called.
+	Tool string
+	// Attempt is the number of the attempt about to be made, starting at 2.
+	Attempt int
+	// Delay is the backoff before the attempt.
+	Delay time.Duration
+	// Err is the error of the previous attempt.
+	Err error
+}
+
+// WithToolRetryPolicy makes CallTool retry calls to idempotent and
+// read-only tools according to policy. Each attempt is a new request that
+// passes through the request interceptors, which can tell retries apart
+// with RetryAttempt.
+func WithToolRetryPolicy(policy ToolRetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.toolRetryPolicy = &policy
+	}
+}
+
+// IsRetryableTool reports whether calls to tool are safe to repeat: it is
+// annotated as read-only or idempotent.
+func IsRetryableTool(tool mcp.Tool) bool {
+	annotations := tool.Annotations
+	return (annotations.ReadOnlyHint != nil && *annotations.ReadOnlyHint) ||
+		(annotations.IdempotentHint != nil && *annotations.IdempotentHint)
+}
+
+type retryAttemptKey struct{}
+
+// RetryAttempt returns the number of the attempt of the tool call a request
+// belongs to, starting at 1, for request interceptors to record retries. It
+// is 0 for requests that are not retried tool calls.
+func RetryAttempt(ctx context.Context) int {
+	attempt, _ := ctx.Value(retryAttemptKey{}).(int)
+	return attempt
+}
+
+func (p *ToolRetryPolicy) maxAttempts() int {
+	if p.MaxAttempts <= 0 {
+		return 3
+	}
+	return p.MaxAttempts
+}
+
+// backoff returns the delay before the given retry, starting at 1.
+func (p *ToolRetryPolicy) backoff(retry int) time.Duration {
+	delay := p.InitialBackoff
+	if delay <= 0 {
+		delay = 100 * time.Millisecond
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = 5 * time.Second
+	}
+	for i := 1; i < retry && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
+
+func (p *ToolRetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	var transportErr *transport.Error
+	return errors.As(err, &transportErr)
+}
+
+// retriesTool reports whether calls to the named tool are retried.
+func (c *Client) retriesTool(name string) bool {
+	if c.toolRetryPolicy == nil {
+		return false
+	}
+	return slices.Contains(c.toolRetryPolicy.AllowedTools, name) || IsRetryableTool(c.knownTools.get(name))
+}
+
+// sendToolCall sends a tools/call request, retrying it according to the
+// retry policy of the client.
+func (c *Client) sendToolCall(ctx context.Context, request mcp.CallToolRequest) (*json.RawMessage, error) {
+	name := request.Params.Name
+	if !c.retriesTool(name) {
+		return c.sendRequest(ctx, "tools/call", request.Params, request.Header)
+	}
+
+	policy := c.toolRetryPolicy
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		response, err := c.sendRequest(context.WithValue(ctx, retryAttemptKey{}, attempt), "tools/call", request.Params, request.Header)
+		if err == nil || !policy.retryable(err) || ctx.Err() != nil {
+			return response, err
+		}
+		if attempt >= policy.maxAttempts() {
+			return nil, retriesExhausted(name, attempt, err)
+		}
+		delay := policy.backoff(attempt)
+		if policy.MaxElapsed > 0 && time.Since(start)+delay > policy.MaxElapsed {
+			return nil, retriesExhausted(name, attempt, err)
+		}
+		if policy.OnRetry != nil {
+			policy.OnRetry(ctx, RetryEvent{Tool: name, Attempt: attempt + 1, Delay: delay, Err: err})
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+func retriesExhausted(tool string, attempts int, err error) error {
+	if attempts == 1 {
+		return err
+	}
+	return fmt.Errorf("tool %q failed after %d attempts: %w", tool, attempts, err)
+}
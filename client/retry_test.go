@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestIsRetryableTool(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations mcp.ToolAnnotation
+		want        bool
+	}{
+		{name: "no annotations", want: false},
+		{name: "read-only", annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)}, want: true},
+		{name: "idempotent", annotations: mcp.ToolAnnotation{IdempotentHint: mcp.ToBoolPtr(true)}, want: true},
+		{name: "not idempotent", annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(false), IdempotentHint: mcp.ToBoolPtr(false)}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsRetryableTool(mcp.Tool{Name: "tool", Annotations: tt.annotations}))
+		})
+	}
+}
+
+func TestToolRetryPolicy_Backoff(t *testing.T) {
+	policy := ToolRetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	var delays []time.Duration
+	for retry := 1; retry <= 5; retry++ {
+		delays = append(delays, policy.backoff(retry))
+	}
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}, delays)
+	assert.Equal(t, 100*time.Millisecond, (&ToolRetryPolicy{}).backoff(1))
+	assert.Equal(t, 3, (&ToolRetryPolicy{}).maxAttempts())
+}
+
+// flakyToolCalls fails the first failures attempts of every tools/call
+// with a transport error, recording the attempt of each call it sees.
+type flakyToolCalls struct {
+	mu       sync.Mutex
+	failures int
+	attempts map[string][]int
+}
+
+var errConnectionReset = errors.New("connection reset")
+
+func (f *flakyToolCalls) interceptor(next RequestInvoker) RequestInvoker {
+	return func(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+		if request.Method != string(mcp.MethodToolsCall) {
+			return next(ctx, request)
+		}
+		name := request.Params.(mcp.CallToolParams).Name
+		f.mu.Lock()
+		f.attempts[name] = append(f.attempts[name], RetryAttempt(ctx))
+		fail := len(f.attempts[name]) <= f.failures
+		f.mu.Unlock()
+		if fail {
+			return nil, errConnectionReset
+		}
+		return next(ctx, request)
+	}
+}
+
+func newRetryTestClient(t *testing.T, failures int, policy ToolRetryPolicy) (*Client, *flakyToolCalls) {
+	t.Helper()
+	mcpServer := server.NewMCPServer("test-server", "1.0.0", server.WithToolCapabilities(false))
+	done := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("done"), nil
+	}
+	mcpServer.AddTool(mcp.NewTool("read", mcp.WithReadOnlyHintAnnotation(true)), done)
+	mcpServer.AddTool(mcp.NewTool("put", mcp.WithIdempotentHintAnnotation(true), mcp.WithDestructiveHintAnnotation(true)), done)
+	mcpServer.AddTool(mcp.NewTool("append", mcp.WithIdempotentHintAnnotation(false)), done)
+	mcpServer.AddTool(mcp.NewTool("allowed"), done)
+
+	flaky := &flakyToolCalls{failures: failures, attempts: make(map[string][]int)}
+	client := NewClient(transport.NewInProcessTransport(mcpServer),
+		WithToolRetryPolicy(policy), WithRequestInterceptor(flaky.interceptor))
+	t.Cleanup(func() { _ = client.Close() })
+	require.NoError(t, client.Start(context.Background()))
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	_, err := client.Initialize(context.Background(), initRequest)
+	require.NoError(t, err)
+	_, err = client.ListTools(context.Background(), mcp.ListToolsRequest{})
+	require.NoError(t, err)
+	return client, flaky
+}
+
+func TestClient_WithToolRetryPolicy(t *testing.T) {
+	var events []RetryEvent
+	policy := ToolRetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		AllowedTools:   []string{"allowed"},
+		OnRetry: func(ctx context.Context, event RetryEvent) {
+			events = append(events, event)
+		},
+	}
+
+	tests := []struct {
+		tool         string
+		failures     int
+		wantAttempts []int
+		wantErr      bool
+	}{
+		{tool: "read", failures: 2, wantAttempts: []int{1, 2, 3}},
+		{tool: "put", failures: 1, wantAttempts: []int{1, 2}},
+		{tool: "allowed", failures: 1, wantAttempts: []int{1, 2}},
+		{tool: "append", failures: 1, wantAttempts: []int{0}, wantErr: true},
+		{tool: "read", failures: 5, wantAttempts: []int{1, 2, 3}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			events = nil
+			client, flaky := newRetryTestClient(t, tt.failures, policy)
+			_, err := callNamedTool(client, tt.tool)
+			assert.Equal(t, tt.wantAttempts, flaky.attempts[tt.tool])
+			if !tt.wantErr {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, errConnectionReset)
+				var transportErr *transport.Error
+				assert.ErrorAs(t, err, &transportErr)
+			}
+			require.Len(t, events, len(tt.wantAttempts)-1)
+			for i, event := range events {
+				assert.Equal(t, tt.tool, event.Tool)
+				assert.Equal(t, i+2, event.Attempt)
+				assert.ErrorIs(t, event.Err, errConnectionReset)
+			}
+		})
+	}
+}
+
+func TestClient_WithToolRetryPolicy_Budget(t *testing.T) {
+	client, flaky := newRetryTestClient(t, 10, ToolRetryPolicy{
+		MaxAttempts:    10,
+		InitialBackoff: 20 * time.Millisecond,
+		MaxElapsed:     50 * time.Millisecond,
+	})
+	_, err := callNamedTool(client, "read")
+	assert.EqualError(t, err, `tool "read" failed after 2 attempts: transport error: connection reset`)
+	assert.Equal(t, []int{1, 2}, flaky.attempts["read"], "the third attempt would start after the budget")
+}
+
+func TestClient_WithToolRetryPolicy_ServerErrors(t *testing.T) {
+	client, flaky := newRetryTestClient(t, 0, ToolRetryPolicy{AllowedTools: []string{"missing"}})
+	_, err := callNamedTool(client, "missing")
+	require.Error(t, err)
+	assert.Equal(t, []int{1}, flaky.attempts["missing"], "errors answered by the server are not retried")
+}
+
+func TestClient_WithToolRetryPolicy_Cancellation(t *testing.T) {
+	client, flaky := newRetryTestClient(t, 10, ToolRetryPolicy{InitialBackoff: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "read"
+	_, err := client.CallTool(ctx, request)
+	assert.ErrorIs(t, err, errConnectionReset, "cancellation during backoff returns the last error")
+	assert.Equal(t, []int{1}, flaky.attempts["read"])
+}
@@ -0,0 +1,164 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ErrSamplingRejected is returned by SamplingLimiter.Acquire when a
+// sampling request cannot run within the limits. The server is answered with
+// a RATE_LIMITED error.
+var ErrSamplingRejected = errors.New("sampling request rejected")
+
+// SamplingLimits bound the sampling requests a host runs for servers.
+type SamplingLimits struct {
+	// MaxConcurrent is the number of sampling requests running at once.
+	// Zero or less means no limit.
+	MaxConcurrent int
+	// MaxQueued is the number of sampling requests waiting for one of the
+	// MaxConcurrent slots. Requests beyond it are rejected at once.
+	MaxQueued int
+	// MaxWait is the longest a request waits in the queue before it is
+	// rejected. Zero means waiting until the request is cancelled.
+	MaxWait time.Duration
+}
+
+// SamplingStats counts the sampling requests of a SamplingLimiter.
+type SamplingStats struct {
+	Running  int
+	Queued   int
+	Rejected uint64
+}
+
+// SamplingLimiter queues sampling requests within SamplingLimits. Share one
+// between the clients of a host with WithSamplingLimiter to bound the
+// sampling of all servers together.
+type SamplingLimiter struct {
+	limits SamplingLimits
+	slots  chan struct{}
+
+	mu       sync.Mutex
+	queued   int
+	rejected uint64
+}
+
+// NewSamplingLimiter returns a limiter enforcing limits.
+func NewSamplingLimiter(limits SamplingLimits) *SamplingLimiter {
+	l := &SamplingLimiter{limits: limits}
+	if limits.MaxConcurrent > 0 {
+		l.slots = make(chan struct{}, limits.MaxConcurrent)
+	}
+	return l
+}
+
+// WithSamplingLimits bounds the sampling requests of the server the client
+// is connected to. Requests over the limits are answered with a
+// RATE_LIMITED error instead of reaching the sampling handler.
+func WithSamplingLimits(limits SamplingLimits) ClientOption {
+	return func(c *Client) {
+		c.samplingLimiter = NewSamplingLimiter(limits)
+	}
+}
+
+// WithSamplingLimiter bounds the sampling requests of the server the client
+// is connected to with limiter, which may be shared with other clients. It
+// applies in addition to WithSamplingLimits.
+func WithSamplingLimiter(limiter *SamplingLimiter) ClientOption {
+	return func(c *Client) {
+		c.sharedSamplingLimiter = limiter
+	}
+}
+
+// Acquire waits for a slot to run a sampling request and returns the
+// function releasing it. It fails with ErrSamplingRejected if the queue is
+// full or the request waited for MaxWait, and with the error of ctx if it is
+// done first.
+func (l *SamplingLimiter) Acquire(ctx context.Context) (func(), error) {
+	if l == nil || l.slots == nil {
+		return func() {}, nil
+	}
+	release := func() { <-l.slots }
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	l.mu.Lock()
+	if l.queued >= l.limits.MaxQueued {
+		l.rejected++
+		l.mu.Unlock()
+		return nil, fmt.Errorf("%w: %d requests running and %d queued", ErrSamplingRejected, l.limits.MaxConcurrent, l.limits.MaxQueued)
+	}
+	l.queued++
+	l.mu.Unlock()
+	defer func() {
+		l.mu.Lock()
+		l.queued--
+		l.mu.Unlock()
+	}()
+
+	var timeout <-chan time.Time
+	if l.limits.MaxWait > 0 {
+		timer := time.NewTimer(l.limits.MaxWait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	case <-timeout:
+		l.mu.Lock()
+		l.rejected++
+		l.mu.Unlock()
+		return nil, fmt.Errorf("%w: queued for %s", ErrSamplingRejected, l.limits.MaxWait)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Stats returns the current counts of the limiter.
+func (l *SamplingLimiter) Stats() SamplingStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return SamplingStats{Running: len(l.slots), Queued: l.queued, Rejected: l.rejected}
+}
+
+// acquireSampling acquires the per-server and shared sampling slots of a
+// request. If a limiter rejects the request, it returns the response to
+// send instead.
+func (c *Client) acquireSampling(ctx context.Context, id mcp.RequestID) (func(), *transport.JSONRPCResponse, error) {
+	limiters := []struct {
+		limiter *SamplingLimiter
+		scope   string
+	}{
+		{c.samplingLimiter, "server"},
+		{c.sharedSamplingLimiter, "global"},
+	}
+
+	var releases []func()
+	releaseAll := func() {
+		for i := len(releases) - 1; i >= 0; i-- {
+			releases[i]()
+		}
+	}
+	for _, l := range limiters {
+		release, err := l.limiter.Acquire(ctx)
+		if err != nil {
+			releaseAll()
+			if errors.Is(err, ErrSamplingRejected) {
+				return nil, transport.NewJSONRPCErrorResponse(id, mcp.RATE_LIMITED, err.Error(),
+					mcp.RateLimitErrorData{Scope: l.scope}), nil
+			}
+			return nil, nil, err
+		}
+		releases = append(releases, release)
+	}
+	return releaseAll, nil, nil
+}
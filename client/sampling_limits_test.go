@@ -0,0 +1,153 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// blockingSamplingHandler answers sampling requests once unblocked.
+type blockingSamplingHandler struct {
+	started chan struct{}
+	unblock chan struct{}
+}
+
+func newBlockingSamplingHandler() *blockingSamplingHandler {
+	return &blockingSamplingHandler{started: make(chan struct{}, 10), unblock: make(chan struct{})}
+}
+
+func (h *blockingSamplingHandler) CreateMessage(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	h.started <- struct{}{}
+	<-h.unblock
+	return &mcp.CreateMessageResult{
+		SamplingMessage: mcp.SamplingMessage{Role: mcp.RoleAssistant, Content: mcp.NewTextContent("ok")},
+		Model:           "test-model",
+	}, nil
+}
+
+func samplingRequest(id int64) transport.JSONRPCRequest {
+	request := mockJSONRPCRequest(mcp.CreateMessageRequest{
+		CreateMessageParams: mcp.CreateMessageParams{
+			Messages:  []mcp.SamplingMessage{{Role: mcp.RoleUser, Content: mcp.NewTextContent("Hello")}},
+			MaxTokens: 10,
+		},
+	})
+	request.ID = mcp.NewRequestId(id)
+	return request
+}
+
+// sample sends a sampling request to c in the background.
+func sample(c *Client, id int64, wg *sync.WaitGroup, responses chan<- *transport.JSONRPCResponse) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		response, err := c.handleIncomingRequest(context.Background(), samplingRequest(id))
+		if err == nil {
+			responses <- response
+		}
+	}()
+}
+
+func TestClient_WithSamplingLimits(t *testing.T) {
+	handler := newBlockingSamplingHandler()
+	c := NewClient(nil, WithSamplingHandler(handler), WithSamplingLimits(SamplingLimits{MaxConcurrent: 1, MaxQueued: 1}))
+
+	var wg sync.WaitGroup
+	responses := make(chan *transport.JSONRPCResponse, 3)
+	sample(c, 1, &wg, responses)
+	<-handler.started
+	sample(c, 2, &wg, responses)
+	require.Eventually(t, func() bool { return c.samplingLimiter.Stats().Queued == 1 }, time.Second, 5*time.Millisecond)
+
+	// The third request finds the queue full.
+	response, err := c.handleIncomingRequest(context.Background(), samplingRequest(3))
+	require.NoError(t, err)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, mcp.RATE_LIMITED, response.Error.Code)
+	assert.Equal(t, mcp.RateLimitErrorData{Scope: "server"}, response.Error.Data)
+	assert.Equal(t, SamplingStats{Running: 1, Queued: 1, Rejected: 1}, c.samplingLimiter.Stats())
+
+	close(handler.unblock)
+	wg.Wait()
+	close(responses)
+	for response := range responses {
+		assert.Nil(t, response.Error)
+	}
+	assert.Equal(t, SamplingStats{Rejected: 1}, c.samplingLimiter.Stats())
+}
+
+func TestClient_WithSamplingLimiter(t *testing.T) {
+	handler := newBlockingSamplingHandler()
+	limiter := NewSamplingLimiter(SamplingLimits{MaxConcurrent: 1})
+	first := NewClient(nil, WithSamplingHandler(handler), WithSamplingLimiter(limiter))
+	second := NewClient(nil, WithSamplingHandler(handler), WithSamplingLimiter(limiter))
+
+	var wg sync.WaitGroup
+	responses := make(chan *transport.JSONRPCResponse, 1)
+	sample(first, 1, &wg, responses)
+	<-handler.started
+
+	response, err := second.handleIncomingRequest(context.Background(), samplingRequest(2))
+	require.NoError(t, err)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, mcp.RATE_LIMITED, response.Error.Code)
+	assert.Equal(t, mcp.RateLimitErrorData{Scope: "global"}, response.Error.Data)
+
+	close(handler.unblock)
+	wg.Wait()
+	assert.Nil(t, (<-responses).Error)
+}
+
+func TestSamplingLimiter_Acquire(t *testing.T) {
+	t.Run("no limit", func(t *testing.T) {
+		limiter := NewSamplingLimiter(SamplingLimits{})
+		for i := 0; i < 3; i++ {
+			_, err := limiter.Acquire(context.Background())
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("max wait", func(t *testing.T) {
+		limiter := NewSamplingLimiter(SamplingLimits{MaxConcurrent: 1, MaxQueued: 1, MaxWait: 20 * time.Millisecond})
+		release, err := limiter.Acquire(context.Background())
+		require.NoError(t, err)
+		defer release()
+
+		_, err = limiter.Acquire(context.Background())
+		assert.ErrorIs(t, err, ErrSamplingRejected)
+		assert.Equal(t, SamplingStats{Running: 1, Rejected: 1}, limiter.Stats())
+	})
+
+	t.Run("released slots are handed to the queue", func(t *testing.T) {
+		limiter := NewSamplingLimiter(SamplingLimits{MaxConcurrent: 1, MaxQueued: 1})
+		release, err := limiter.Acquire(context.Background())
+		require.NoError(t, err)
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			release()
+		}()
+		next, err := limiter.Acquire(context.Background())
+		require.NoError(t, err)
+		next()
+	})
+
+	t.Run("cancelled while queued", func(t *testing.T) {
+		limiter := NewSamplingLimiter(SamplingLimits{MaxConcurrent: 1, MaxQueued: 1})
+		release, err := limiter.Acquire(context.Background())
+		require.NoError(t, err)
+		defer release()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err = limiter.Acquire(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, uint64(0), limiter.Stats().Rejected)
+	})
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ErrRequestTimeout is matched by the error of a request that did not
+// complete within the timeout set by WithRequestTimeout or
+// WithMethodTimeout.
+var ErrRequestTimeout = errors.New("request timed out")
+
+// WithRequestTimeout sets a deadline on every request the client sends,
+// unless the context of the call has an earlier one. WithMethodTimeout
+// overrides it, and a zero timeout disables it.
+func WithRequestTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.requestTimeoutConfig().fallback = timeout
+	}
+}
+
+// WithMethodTimeout overrides the request timeout for the requests of
+// method. A zero timeout disables the timeout for the method.
+func WithMethodTimeout(method mcp.MCPMethod, timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.requestTimeoutConfig().methods[method] = timeout
+	}
+}
+
+// requestTimeouts holds the timeouts set by WithRequestTimeout and
+// WithMethodTimeout.
+type requestTimeouts struct {
+	fallback time.Duration
+	methods  map[mcp.MCPMethod]time.Duration
+}
+
+func (c *Client) requestTimeoutConfig() *requestTimeouts {
+	if c.requestTimeouts == nil {
+		c.requestTimeouts = &requestTimeouts{methods: make(map[mcp.MCPMethod]time.Duration)}
+	}
+	return c.requestTimeouts
+}
+
+// withRequestTimeout returns the context of a request, cancelled with an
+// ErrRequestTimeout cause once the timeout of its method elapses.
+func (c *Client) withRequestTimeout(ctx context.Context, method string) (context.Context, context.CancelFunc) {
+	if c.requestTimeouts == nil {
+		return ctx, func() {}
+	}
+	timeout, ok := c.requestTimeouts.methods[mcp.MCPMethod(method)]
+	if !ok {
+		timeout = c.requestTimeouts.fallback
+	}
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeoutCause(ctx, timeout, fmt.Errorf("%w after %s", ErrRequestTimeout, timeout))
+}
+
+// requestTimeoutError adds the ErrRequestTimeout cause of ctx, if any, to
+// the error of a request.
+func requestTimeoutError(ctx context.Context, err error) error {
+	if cause := context.Cause(ctx); errors.Is(cause, ErrRequestTimeout) && !errors.Is(err, ErrRequestTimeout) {
+		return fmt.Errorf("%w: %w", cause, err)
+	}
+	return err
+}
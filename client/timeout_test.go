@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestClient_WithRequestTimeout(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("slow"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+			return mcp.NewToolResultText("done"), nil
+		}
+	})
+
+	httpServer := server.NewTestStreamableHTTPServer(mcpServer)
+	defer httpServer.Close()
+
+	trans, err := transport.NewStreamableHTTP(httpServer.URL)
+	require.NoError(t, err)
+	client := NewClient(trans,
+		WithRequestTimeout(time.Second), WithMethodTimeout(mcp.MethodToolsCall, 20*time.Millisecond))
+	defer client.Close()
+	require.NoError(t, client.Start(context.Background()))
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	_, err = client.Initialize(context.Background(), initRequest)
+	require.NoError(t, err)
+
+	require.NoError(t, client.Ping(context.Background()))
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "slow"
+	start := time.Now()
+	_, err = client.CallTool(context.Background(), request)
+	assert.ErrorIs(t, err, ErrRequestTimeout)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	// The deadline of the caller's context is kept when it is earlier.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	_, err = client.CallTool(ctx, request)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NotErrorIs(t, err, ErrRequestTimeout)
+}
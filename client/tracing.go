@@ -0,0 +1,89 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/tracing"
+)
+
+// WithTracing creates a client span for every request the client sends,
+// recording the method, request ID, session ID and, for tool calls, the
+// tool name. If propagator is not nil, the trace context is sent to the
+// server in the request's _meta field so server spans join the trace.
+func WithTracing(tracer tracing.Tracer, propagator tracing.Propagator) ClientOption {
+	return func(c *Client) {
+		c.tracer = tracer
+		c.tracePropagator = propagator
+	}
+}
+
+// startRequestSpan starts the span of a request. It returns the params to
+// send, with the trace context injected, and a function that ends the span
+// with the outcome of the request.
+func (c *Client) startRequestSpan(
+	ctx context.Context,
+	method string,
+	id int64,
+	params any,
+) (context.Context, any, func(err error)) {
+	if c.tracer == nil {
+		return ctx, params, func(error) {}
+	}
+
+	// Params are sent as a map of raw fields so the trace context can be
+	// added to _meta whatever their type, leaving the other fields as they
+	// were encoded.
+	fields := make(map[string]json.RawMessage)
+	if params != nil {
+		if raw, err := json.Marshal(params); err == nil {
+			_ = json.Unmarshal(raw, &fields)
+		}
+	}
+
+	name := method
+	attrs := []tracing.Attribute{
+		{Key: tracing.AttrMethodName, Value: method},
+		{Key: tracing.AttrRequestID, Value: mcp.NewRequestID(id).String()},
+	}
+	var toolName string
+	if method == string(mcp.MethodToolsCall) && json.Unmarshal(fields["name"], &toolName) == nil && toolName != "" {
+		name += " " + toolName
+		attrs = append(attrs, tracing.Attribute{Key: tracing.AttrToolName, Value: toolName})
+	}
+	if sessionID := c.transport.GetSessionId(); sessionID != "" {
+		attrs = append(attrs, tracing.Attribute{Key: tracing.AttrSessionID, Value: sessionID})
+	}
+
+	ctx, span := c.tracer.Start(ctx, name, tracing.SpanKindClient, attrs...)
+	if c.tracePropagator != nil && (params == nil || len(fields) > 0) {
+		meta := make(map[string]any)
+		if raw, ok := fields["_meta"]; ok {
+			decoder := json.NewDecoder(bytes.NewReader(raw))
+			decoder.UseNumber()
+			_ = decoder.Decode(&meta)
+			if meta == nil {
+				meta = make(map[string]any)
+			}
+		}
+		tracing.InjectMeta(ctx, c.tracePropagator, meta)
+		if raw, err := json.Marshal(meta); err == nil {
+			fields["_meta"] = raw
+			params = fields
+		}
+	}
+
+	return ctx, params, func(err error) {
+		if err != nil {
+			var mcpErr *mcp.Error
+			if errors.As(err, &mcpErr) {
+				span.SetAttributes(tracing.Attribute{Key: tracing.AttrErrorCode, Value: mcpErr.Code})
+			}
+			span.RecordError(err)
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mark3labs/mcp-go/tracing"
+)
+
+type traceIDKey struct{}
+
+// recordedSpan is a span captured by recordingTracer.
+type recordedSpan struct {
+	name    string
+	kind    tracing.SpanKind
+	traceID string
+	attrs   map[string]any
+	err     error
+	ended   bool
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...tracing.Attribute) {
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+func (s *recordedSpan) RecordError(err error) { s.err = err }
+func (s *recordedSpan) End()                  { s.ended = true }
+
+// recordingTracer records spans and starts a new trace for spans without a
+// parent.
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, kind tracing.SpanKind, attrs ...tracing.Attribute) (context.Context, tracing.Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	traceID, ok := ctx.Value(traceIDKey{}).(string)
+	if !ok {
+		traceID = fmt.Sprintf("trace-%d", len(t.spans)+1)
+		ctx = context.WithValue(ctx, traceIDKey{}, traceID)
+	}
+	span := &recordedSpan{name: name, kind: kind, traceID: traceID, attrs: map[string]any{}}
+	span.SetAttributes(attrs...)
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func (t *recordingTracer) last() *recordedSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.spans[len(t.spans)-1]
+}
+
+// traceIDPropagator carries the trace ID set by recordingTracer.
+type traceIDPropagator struct{}
+
+func (traceIDPropagator) Inject(ctx context.Context, carrier map[string]string) {
+	if traceID, ok := ctx.Value(traceIDKey{}).(string); ok {
+		carrier["traceparent"] = traceID
+	}
+}
+
+func (traceIDPropagator) Extract(ctx context.Context, carrier map[string]string) context.Context {
+	if traceID, ok := carrier["traceparent"]; ok {
+		return context.WithValue(ctx, traceIDKey{}, traceID)
+	}
+	return ctx
+}
+
+func TestClient_WithTracing(t *testing.T) {
+	serverTracer := &recordingTracer{}
+	mcpServer := server.NewMCPServer("test-server", "1.0.0",
+		server.WithToolCapabilities(true),
+		server.WithTracing(serverTracer, traceIDPropagator{}),
+	)
+	mcpServer.AddTool(mcp.NewTool("echo"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("echo"), nil
+	})
+	mcpServer.AddTool(mcp.NewTool("fail"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, errors.New("boom")
+	})
+
+	clientTracer := &recordingTracer{}
+	client := NewClient(transport.NewInProcessTransport(mcpServer), WithTracing(clientTracer, traceIDPropagator{}))
+	defer client.Close()
+	require.NoError(t, client.Start(context.Background()))
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	_, err := client.Initialize(context.Background(), initRequest)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		tool         string
+		expectedCode int
+	}{
+		{name: "successful call", tool: "echo"},
+		{name: "failed call", tool: "fail", expectedCode: mcp.INTERNAL_ERROR},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := mcp.CallToolRequest{}
+			request.Params.Name = tt.tool
+			_, err := client.CallTool(context.Background(), request)
+
+			clientSpan, serverSpan := clientTracer.last(), serverTracer.last()
+			for _, span := range []*recordedSpan{clientSpan, serverSpan} {
+				assert.Equal(t, "tools/call "+tt.tool, span.name)
+				assert.Equal(t, "tools/call", span.attrs[tracing.AttrMethodName])
+				assert.Equal(t, tt.tool, span.attrs[tracing.AttrToolName])
+				assert.True(t, span.ended)
+			}
+			assert.Equal(t, tracing.SpanKindClient, clientSpan.kind)
+			assert.Equal(t, tracing.SpanKindServer, serverSpan.kind)
+			assert.Equal(t, clientSpan.traceID, serverSpan.traceID, "the trace is propagated through _meta")
+
+			if tt.expectedCode == 0 {
+				require.NoError(t, err)
+				assert.NoError(t, clientSpan.err)
+				assert.NoError(t, serverSpan.err)
+				return
+			}
+			require.Error(t, err)
+			for _, span := range []*recordedSpan{clientSpan, serverSpan} {
+				assert.Error(t, span.err)
+				assert.Equal(t, tt.expectedCode, span.attrs[tracing.AttrErrorCode])
+			}
+		})
+	}
+}
+
+func TestClient_WithTracing_KeepsNumbers(t *testing.T) {
+	client := NewClient(transport.NewInProcessTransport(server.NewMCPServer("test-server", "1.0.0")),
+		WithTracing(&recordingTracer{}, traceIDPropagator{}))
+
+	params := json.RawMessage(`{"name":"lookup","arguments":{"id":9007199254740993},"_meta":{"progressToken":9007199254740995}}`)
+	_, sent, end := client.startRequestSpan(context.Background(), string(mcp.MethodToolsCall), 1, params)
+	end(nil)
+
+	raw, err := json.Marshal(sent)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"name": "lookup",
+		"arguments": {"id": 9007199254740993},
+		"_meta": {"progressToken": 9007199254740995, "traceparent": "trace-1"}
+	}`, string(raw))
+	assert.Contains(t, string(raw), "9007199254740993")
+	assert.Contains(t, string(raw), "9007199254740995")
+}
@@ -0,0 +1,229 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TransportKind names the transport an Auto transport selected.
+type TransportKind string
+
+// Transports an Auto transport can select.
+const (
+	TransportKindStreamableHTTP TransportKind = "streamable-http"
+	TransportKindSSE            TransportKind = "sse"
+)
+
+// AutoOption configures an Auto transport.
+type AutoOption func(*Auto)
+
+// WithAutoStreamableHTTPOptions sets the options of the streamable HTTP
+// transport Auto tries first.
+func WithAutoStreamableHTTPOptions(options ...StreamableHTTPCOption) AutoOption {
+	return func(a *Auto) {
+		a.streamableOptions = append(a.streamableOptions, options...)
+	}
+}
+
+// WithAutoSSEOptions sets the options of the SSE transport Auto falls back
+// to.
+func WithAutoSSEOptions(options ...ClientOption) AutoOption {
+	return func(a *Auto) {
+		a.sseOptions = append(a.sseOptions, options...)
+	}
+}
+
+// Auto connects to servers whose transport is not known in advance, as the
+// backwards compatibility section of the specification recommends. It
+// sends the initialize request with the streamable HTTP transport and, if
+// the server answers with 400, 404 or 405, as servers of the older HTTP+SSE
+// transport do, connects with the SSE transport instead. Kind reports the
+// transport selected.
+type Auto struct {
+	baseURL           string
+	streamableOptions []StreamableHTTPCOption
+	sseOptions        []ClientOption
+	streamable        *StreamableHTTP
+
+	mu                    sync.RWMutex
+	startCtx              context.Context
+	selected              Interface
+	kind                  TransportKind
+	notificationHandler   func(mcp.JSONRPCNotification)
+	connectionLostHandler func(error)
+}
+
+// NewAuto creates a transport for the server at baseURL, which is both the
+// streamable HTTP endpoint and the SSE endpoint.
+func NewAuto(baseURL string, options ...AutoOption) (*Auto, error) {
+	a := &Auto{baseURL: baseURL}
+	for _, opt := range options {
+		opt(a)
+	}
+	streamable, err := NewStreamableHTTP(baseURL, a.streamableOptions...)
+	if err != nil {
+		return nil, err
+	}
+	a.streamable = streamable
+	a.selected = streamable
+	return a, nil
+}
+
+// Kind returns the transport selected by the initialize request, or ""
+// before it succeeded.
+func (a *Auto) Kind() TransportKind {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.kind
+}
+
+// Transport returns the underlying transport: the streamable HTTP transport
+// until the client falls back to SSE.
+func (a *Auto) Transport() Interface {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.selected
+}
+
+func (a *Auto) Start(ctx context.Context) error {
+	a.mu.Lock()
+	a.startCtx = ctx
+	a.mu.Unlock()
+	return a.streamable.Start(ctx)
+}
+
+func (a *Auto) SendRequest(ctx context.Context, request JSONRPCRequest) (*JSONRPCResponse, error) {
+	a.mu.RLock()
+	selected, kind := a.selected, a.kind
+	a.mu.RUnlock()
+	if request.Method != string(mcp.MethodInitialize) || kind != "" {
+		return selected.SendRequest(ctx, request)
+	}
+
+	response, err := a.streamable.SendRequest(ctx, request)
+	if err == nil {
+		a.mu.Lock()
+		a.kind = TransportKindStreamableHTTP
+		a.mu.Unlock()
+		return response, nil
+	}
+	if !isLegacyTransportError(err) {
+		return nil, err
+	}
+
+	sse, err := a.startSSE()
+	if err != nil {
+		return nil, fmt.Errorf("streamable HTTP rejected, SSE fallback failed: %w", err)
+	}
+	response, err = sse.SendRequest(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	a.mu.Lock()
+	a.kind = TransportKindSSE
+	a.mu.Unlock()
+	return response, nil
+}
+
+// startSSE connects with the SSE transport and makes it the selected one.
+func (a *Auto) startSSE() (*SSE, error) {
+	sse, err := NewSSE(a.baseURL, a.sseOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	ctx := a.startCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := sse.Start(ctx); err != nil {
+		return nil, err
+	}
+	if a.notificationHandler != nil {
+		sse.SetNotificationHandler(a.notificationHandler)
+	}
+	if a.connectionLostHandler != nil {
+		sse.SetConnectionLostHandler(a.connectionLostHandler)
+	}
+	_ = a.streamable.Close()
+	a.selected = sse
+	return sse, nil
+}
+
+// isLegacyTransportError reports whether err is the answer of a server of
+// the HTTP+SSE transport to a streamable HTTP initialize request.
+func isLegacyTransportError(err error) bool {
+	if errors.Is(err, ErrSessionTerminated) {
+		// The streamable HTTP transport reports a 404 this way.
+		return true
+	}
+	var statusErr *HTTPStatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	switch statusErr.StatusCode {
+	case http.StatusBadRequest, http.StatusNotFound, http.StatusMethodNotAllowed:
+		return true
+	}
+	return false
+}
+
+func (a *Auto) SendNotification(ctx context.Context, notification mcp.JSONRPCNotification) error {
+	return a.Transport().SendNotification(ctx, notification)
+}
+
+func (a *Auto) SetNotificationHandler(handler func(notification mcp.JSONRPCNotification)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.notificationHandler = handler
+	a.selected.SetNotificationHandler(handler)
+}
+
+// SetRequestHandler sets the handler for requests of the server. Only the
+// streamable HTTP transport delivers them.
+func (a *Auto) SetRequestHandler(handler RequestHandler) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if bidirectional, ok := a.selected.(BidirectionalInterface); ok {
+		bidirectional.SetRequestHandler(handler)
+	}
+}
+
+// SetConnectionLostHandler sets the handler called when the SSE transport
+// loses its connection.
+func (a *Auto) SetConnectionLostHandler(handler func(error)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.connectionLostHandler = handler
+	if sse, ok := a.selected.(*SSE); ok {
+		sse.SetConnectionLostHandler(handler)
+	}
+}
+
+// SetProtocolVersion sets the negotiated protocol version of the selected
+// transport.
+func (a *Auto) SetProtocolVersion(version string) {
+	if conn, ok := a.Transport().(HTTPConnection); ok {
+		conn.SetProtocolVersion(version)
+	}
+}
+
+func (a *Auto) Close() error {
+	return a.Transport().Close()
+}
+
+func (a *Auto) GetSessionId() string {
+	return a.Transport().GetSessionId()
+}
+
+var (
+	_ BidirectionalInterface = (*Auto)(nil)
+	_ HTTPConnection         = (*Auto)(nil)
+)
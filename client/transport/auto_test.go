@@ -0,0 +1,133 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func autoInitializeRequest() JSONRPCRequest {
+	return JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestId(int64(1)),
+		Method:  string(mcp.MethodInitialize),
+		Params: map[string]any{
+			"protocolVersion": mcp.LATEST_PROTOCOL_VERSION,
+			"clientInfo":      map[string]any{"name": "auto", "version": "1.0.0"},
+		},
+	}
+}
+
+func TestAuto(t *testing.T) {
+	mcpServer := server.NewMCPServer("auto", "1.0.0", server.WithToolCapabilities(false))
+	mcpServer.AddTool(mcp.NewTool("ping"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("pong"), nil
+	})
+
+	tests := []struct {
+		name     string
+		url      func(t *testing.T) string
+		wantKind TransportKind
+	}{
+		{
+			name: "streamable HTTP server",
+			url: func(t *testing.T) string {
+				ts := server.NewTestStreamableHTTPServer(mcpServer)
+				t.Cleanup(ts.Close)
+				return ts.URL
+			},
+			wantKind: TransportKindStreamableHTTP,
+		},
+		{
+			name: "SSE server",
+			url: func(t *testing.T) string {
+				ts := server.NewTestServer(mcpServer)
+				t.Cleanup(ts.Close)
+				return ts.URL + "/sse"
+			},
+			wantKind: TransportKindSSE,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trans, err := NewAuto(tt.url(t))
+			require.NoError(t, err)
+			defer trans.Close()
+			require.NoError(t, trans.Start(context.Background()))
+			assert.Empty(t, trans.Kind())
+
+			response, err := trans.SendRequest(context.Background(), autoInitializeRequest())
+			require.NoError(t, err)
+			require.Nil(t, response.Error)
+			assert.Equal(t, tt.wantKind, trans.Kind())
+			require.NoError(t, trans.SendNotification(context.Background(), mcp.JSONRPCNotification{
+				JSONRPC:      mcp.JSONRPC_VERSION,
+				Notification: mcp.Notification{Method: "notifications/initialized"},
+			}))
+
+			response, err = trans.SendRequest(context.Background(), JSONRPCRequest{
+				JSONRPC: mcp.JSONRPC_VERSION,
+				ID:      mcp.NewRequestId(int64(2)),
+				Method:  string(mcp.MethodToolsCall),
+				Params:  map[string]any{"name": "ping"},
+			})
+			require.NoError(t, err)
+			require.Nil(t, response.Error)
+			var result map[string]any
+			require.NoError(t, json.Unmarshal(response.Result, &result))
+			assert.Equal(t, "pong", result["content"].([]any)[0].(map[string]any)["text"])
+
+			if tt.wantKind == TransportKindSSE {
+				_, ok := trans.Transport().(*SSE)
+				assert.True(t, ok)
+			}
+		})
+	}
+}
+
+func TestAuto_NoFallbackOnOtherErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	trans, err := NewAuto(ts.URL)
+	require.NoError(t, err)
+	defer trans.Close()
+	require.NoError(t, trans.Start(context.Background()))
+
+	_, err = trans.SendRequest(context.Background(), autoInitializeRequest())
+	var statusErr *HTTPStatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusInternalServerError, statusErr.StatusCode)
+	assert.Empty(t, trans.Kind())
+	_, ok := trans.Transport().(*StreamableHTTP)
+	assert.True(t, ok)
+}
+
+func TestIsLegacyTransportError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "bad request", err: &HTTPStatusError{StatusCode: http.StatusBadRequest}, want: true},
+		{name: "not found", err: ErrSessionTerminated, want: true},
+		{name: "method not allowed", err: &HTTPStatusError{StatusCode: http.StatusMethodNotAllowed}, want: true},
+		{name: "unauthorized", err: &HTTPStatusError{StatusCode: http.StatusUnauthorized}, want: false},
+		{name: "other error", err: assert.AnError, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isLegacyTransportError(tt.err))
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package transport
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/util"
+)
+
+// WithHTTPCompression makes the client accept responses compressed with the
+// codings of compressors, in order of preference, and compress request
+// bodies of at least minSize bytes with the first of them. Without
+// compressors, gzip is used. If the server answers a compressed request
+// with 415 Unsupported Media Type, the request is sent again uncompressed
+// and request compression is turned off for the transport. A negative
+// minSize only turns on response compression.
+func WithHTTPCompression(minSize int, compressors ...util.Compressor) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		if len(compressors) == 0 {
+			compressors = []util.Compressor{util.GzipCompressor()}
+		}
+		sc.compressors = compressors
+		sc.compressMinSize = minSize
+		sc.compressRequests.Store(minSize >= 0)
+	}
+}
+
+// compressRequestBody returns body compressed along with its coding if
+// request compression is on and body is large enough, or as is with an
+// empty coding otherwise.
+func (c *StreamableHTTP) compressRequestBody(body []byte) ([]byte, string, error) {
+	if len(c.compressors) == 0 || !c.compressRequests.Load() || len(body) < c.compressMinSize {
+		return body, "", nil
+	}
+	compressor := c.compressors[0]
+	var buf bytes.Buffer
+	w, err := compressor.NewWriter(&buf)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := w.Write(body); err != nil {
+		_ = w.Close()
+		return nil, "", err
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), compressor.Encoding(), nil
+}
+
+// decompressResponseBody replaces the body of resp with its content decoded
+// from the Content-Encoding of the response, if it is one of the
+// compressors.
+func (c *StreamableHTTP) decompressResponseBody(resp *http.Response) error {
+	encoding := resp.Header.Get("Content-Encoding")
+	if len(c.compressors) == 0 || encoding == "" {
+		return nil
+	}
+	compressor := util.FindCompressor(encoding, c.compressors)
+	if compressor == nil {
+		return nil
+	}
+	reader, err := compressor.NewReader(resp.Body)
+	if err != nil {
+		return err
+	}
+	resp.Body = &decompressedBody{Reader: reader, decompressor: reader, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
+
+// decompressedBody closes both the decompressor and the response body.
+type decompressedBody struct {
+	io.Reader
+	decompressor io.Closer
+	body         io.Closer
+}
+
+func (b *decompressedBody) Close() error {
+	err := b.decompressor.Close()
+	if closeErr := b.body.Close(); closeErr != nil {
+		return closeErr
+	}
+	return err
+}
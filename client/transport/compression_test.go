@@ -0,0 +1,118 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// encodingRecorder records the Content-Encoding of the requests and
+// responses of a handler.
+type encodingRecorder struct {
+	handler     http.Handler
+	rejectCoded bool
+
+	mu        sync.Mutex
+	requests  []string
+	responses []string
+}
+
+func (e *encodingRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	e.requests = append(e.requests, r.Header.Get("Content-Encoding"))
+	e.mu.Unlock()
+	if e.rejectCoded && r.Header.Get("Content-Encoding") != "" {
+		http.Error(w, "unsupported", http.StatusUnsupportedMediaType)
+		return
+	}
+	e.handler.ServeHTTP(w, r)
+	e.mu.Lock()
+	e.responses = append(e.responses, w.Header().Get("Content-Encoding"))
+	e.mu.Unlock()
+}
+
+func (e *encodingRecorder) recorded() ([]string, []string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.requests...), append([]string(nil), e.responses...)
+}
+
+func TestStreamableHTTP_Compression(t *testing.T) {
+	mcpServer := server.NewMCPServer("compression", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("echo"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(request.GetString("text", "")), nil
+	})
+	large := strings.Repeat("large argument ", 200)
+
+	tests := []struct {
+		name          string
+		rejectCoded   bool
+		wantRequests  []string
+		wantResponses []string
+		wantCompress  bool
+	}{
+		{
+			name:          "compressed both ways",
+			wantRequests:  []string{"", "gzip", ""},
+			wantResponses: []string{"", "gzip", ""},
+			wantCompress:  true,
+		},
+		{
+			// The large call is sent again uncompressed and the transport
+			// stops compressing.
+			name:          "server rejecting compressed requests",
+			rejectCoded:   true,
+			wantRequests:  []string{"", "gzip", "", ""},
+			wantResponses: []string{"", "gzip", ""},
+			wantCompress:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &encodingRecorder{
+				handler:     server.NewStreamableHTTPServer(mcpServer, server.WithStateLess(true), server.WithCompression(1024)),
+				rejectCoded: tt.rejectCoded,
+			}
+			ts := httptest.NewServer(recorder)
+			defer ts.Close()
+
+			trans, err := NewStreamableHTTP(ts.URL, WithHTTPCompression(1024))
+			require.NoError(t, err)
+			defer trans.Close()
+			require.NoError(t, trans.Start(context.Background()))
+
+			call := func(id int64, text string) string {
+				response, err := trans.SendRequest(context.Background(), JSONRPCRequest{
+					JSONRPC: mcp.JSONRPC_VERSION,
+					ID:      mcp.NewRequestId(id),
+					Method:  string(mcp.MethodToolsCall),
+					Params:  map[string]any{"name": "echo", "arguments": map[string]any{"text": text}},
+				})
+				require.NoError(t, err)
+				require.Nil(t, response.Error)
+				var result mcp.CallToolResult
+				require.NoError(t, json.Unmarshal(response.Result, &result))
+				require.Len(t, result.Content, 1)
+				return result.Content[0].(mcp.TextContent).Text
+			}
+			assert.Equal(t, "small", call(1, "small"))
+			assert.Equal(t, large, call(2, large))
+			assert.Equal(t, "small", call(3, "small"))
+
+			requests, responses := recorder.recorded()
+			assert.Equal(t, tt.wantRequests, requests)
+			assert.Equal(t, tt.wantResponses, responses)
+			assert.Equal(t, tt.wantCompress, trans.compressRequests.Load())
+		})
+	}
+}
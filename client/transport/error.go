@@ -20,3 +20,14 @@ func NewError(err error) *Error {
 		Err: err,
 	}
 }
+
+// HTTPStatusError is returned when an HTTP server answers a request with a
+// status code the transport does not handle.
+type HTTPStatusError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("request failed with status %d: %s", e.StatusCode, e.Body)
+}
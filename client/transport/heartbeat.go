@@ -0,0 +1,52 @@
+package transport
+
+import (
+	"errors"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// ErrHeartbeatTimeout is reported when an SSE stream receives nothing from
+// the server, not even a heartbeat, within the configured heartbeat timeout.
+var ErrHeartbeatTimeout = errors.New("no heartbeat received from server")
+
+// livenessReader closes the stream it wraps when no data is read from it
+// within timeout, turning a connection silently dropped by a proxy into a
+// read error. Any data resets the timer, including SSE comments and pings.
+type livenessReader struct {
+	io.ReadCloser
+	timeout time.Duration
+	timer   *time.Timer
+	expired atomic.Bool
+}
+
+// newLivenessReader wraps stream in a livenessReader, or returns it as is if
+// timeout is not positive.
+func newLivenessReader(stream io.ReadCloser, timeout time.Duration) io.ReadCloser {
+	if timeout <= 0 {
+		return stream
+	}
+	l := &livenessReader{ReadCloser: stream, timeout: timeout}
+	l.timer = time.AfterFunc(timeout, func() {
+		l.expired.Store(true)
+		_ = stream.Close()
+	})
+	return l
+}
+
+func (l *livenessReader) Read(p []byte) (int, error) {
+	n, err := l.ReadCloser.Read(p)
+	if n > 0 {
+		l.timer.Reset(l.timeout)
+	}
+	if err != nil && l.expired.Load() {
+		return n, ErrHeartbeatTimeout
+	}
+	return n, err
+}
+
+func (l *livenessReader) Close() error {
+	l.timer.Stop()
+	return l.ReadCloser.Close()
+}
@@ -0,0 +1,108 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// HTTPTransportOption customizes the *http.Transport of the SSE and
+// StreamableHTTP clients. See WithHTTPTransport and WithSSETransport.
+type HTTPTransportOption func(*http.Transport)
+
+// WithProxy sends the requests through the HTTP or SOCKS5 proxy at proxyURL,
+// instead of the one of the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment
+// variables. A nil proxyURL connects directly.
+func WithProxy(proxyURL *url.URL) HTTPTransportOption {
+	return func(t *http.Transport) {
+		if proxyURL == nil {
+			t.Proxy = nil
+			return
+		}
+		t.Proxy = http.ProxyURL(proxyURL)
+	}
+}
+
+// WithRootCAs verifies the certificate of the server with pool instead of
+// the system's certificate authorities.
+func WithRootCAs(pool *x509.CertPool) HTTPTransportOption {
+	return func(t *http.Transport) {
+		tlsConfig(t).RootCAs = pool
+	}
+}
+
+// WithClientCertificates presents certs to servers requesting a client
+// certificate, for mutual TLS.
+func WithClientCertificates(certs ...tls.Certificate) HTTPTransportOption {
+	return func(t *http.Transport) {
+		tlsConfig(t).Certificates = certs
+	}
+}
+
+// WithTLSMinVersion sets the minimum TLS version, such as tls.VersionTLS13.
+func WithTLSMinVersion(version uint16) HTTPTransportOption {
+	return func(t *http.Transport) {
+		tlsConfig(t).MinVersion = version
+	}
+}
+
+// WithTLSConfig replaces the TLS configuration, for settings without an
+// option of their own. Options given after it adjust config.
+func WithTLSConfig(config *tls.Config) HTTPTransportOption {
+	return func(t *http.Transport) {
+		t.TLSClientConfig = config.Clone()
+	}
+}
+
+// WithDialTimeout limits how long establishing a TCP connection may take.
+func WithDialTimeout(timeout time.Duration) HTTPTransportOption {
+	return func(t *http.Transport) {
+		t.DialContext = (&net.Dialer{
+			Timeout:   timeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext
+	}
+}
+
+// NewHTTPTransport returns a copy of http.DefaultTransport customized with
+// opts, for use in a custom *http.Client.
+func NewHTTPTransport(opts ...HTTPTransportOption) *http.Transport {
+	return customizeTransport(nil, opts)
+}
+
+// customizeTransport returns a copy of base, or of http.DefaultTransport if
+// base is not an *http.Transport, customized with opts.
+func customizeTransport(base http.RoundTripper, opts []HTTPTransportOption) *http.Transport {
+	t, ok := base.(*http.Transport)
+	if !ok {
+		t, ok = http.DefaultTransport.(*http.Transport)
+	}
+	if ok {
+		t = t.Clone()
+	} else {
+		t = &http.Transport{}
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// withCustomTransport returns a copy of client using a transport customized
+// with opts, leaving client itself unchanged as it may be shared.
+func withCustomTransport(client *http.Client, opts []HTTPTransportOption) *http.Client {
+	customized := *client
+	customized.Transport = customizeTransport(client.Transport, opts)
+	return &customized
+}
+
+// tlsConfig returns the TLS configuration of t, creating it if needed.
+func tlsConfig(t *http.Transport) *tls.Config {
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{}
+	}
+	return t.TLSClientConfig
+}
@@ -0,0 +1,208 @@
+package transport
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCertificate returns a self-signed certificate usable by clients.
+func newTestCertificate(t *testing.T, commonName string) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+// pingHandler answers JSON-RPC requests with an empty result.
+func pingHandler(w http.ResponseWriter, r *http.Request) {
+	var request JSONRPCRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      request.ID,
+		"result":  map[string]any{},
+	})
+}
+
+func ping(t *testing.T, trans *StreamableHTTP) error {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := trans.SendRequest(ctx, JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestId(int64(1)),
+		Method:  string(mcp.MethodPing),
+	})
+	return err
+}
+
+func TestNewHTTPTransport(t *testing.T) {
+	proxyURL, err := url.Parse("http://proxy.example.com:3128")
+	require.NoError(t, err)
+	cert := newTestCertificate(t, "client")
+	pool := x509.NewCertPool()
+	pool.AddCert(cert.Leaf)
+
+	tr := NewHTTPTransport(
+		WithTLSConfig(&tls.Config{ServerName: "mcp.example.com"}),
+		WithProxy(proxyURL),
+		WithRootCAs(pool),
+		WithClientCertificates(cert),
+		WithTLSMinVersion(tls.VersionTLS13),
+		WithDialTimeout(time.Second),
+	)
+
+	request, err := http.NewRequest(http.MethodGet, "https://mcp.example.com", nil)
+	require.NoError(t, err)
+	proxy, err := tr.Proxy(request)
+	require.NoError(t, err)
+	assert.Equal(t, proxyURL, proxy)
+	assert.Equal(t, "mcp.example.com", tr.TLSClientConfig.ServerName)
+	assert.Same(t, pool, tr.TLSClientConfig.RootCAs)
+	assert.Len(t, tr.TLSClientConfig.Certificates, 1)
+	assert.Equal(t, uint16(tls.VersionTLS13), tr.TLSClientConfig.MinVersion)
+	assert.NotNil(t, tr.DialContext)
+
+	assert.Nil(t, NewHTTPTransport(WithProxy(nil)).Proxy)
+	if config := http.DefaultTransport.(*http.Transport).TLSClientConfig; config != nil {
+		assert.Nil(t, config.RootCAs, "the default transport must not change")
+	}
+}
+
+func TestWithHTTPTransport_Proxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.Host
+		pingHandler(w, r)
+	}))
+	defer proxy.Close()
+	proxyURL, err := url.Parse(proxy.URL)
+	require.NoError(t, err)
+
+	trans, err := NewStreamableHTTP("http://mcp.invalid/mcp", WithHTTPTransport(WithProxy(proxyURL)))
+	require.NoError(t, err)
+	defer trans.Close()
+
+	require.NoError(t, ping(t, trans))
+	assert.Equal(t, "mcp.invalid", proxied)
+}
+
+func TestWithHTTPTransport_TLS(t *testing.T) {
+	clientCert := newTestCertificate(t, "client")
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert.Leaf)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(pingHandler))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+		MaxVersion: tls.VersionTLS12,
+	}
+	server.StartTLS()
+	defer server.Close()
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+
+	tests := []struct {
+		name    string
+		opts    []HTTPTransportOption
+		wantErr bool
+	}{
+		{
+			name: "trusted server and client certificate",
+			opts: []HTTPTransportOption{WithRootCAs(rootCAs), WithClientCertificates(clientCert)},
+		},
+		{
+			name:    "untrusted server",
+			opts:    []HTTPTransportOption{WithClientCertificates(clientCert)},
+			wantErr: true,
+		},
+		{
+			name:    "no client certificate",
+			opts:    []HTTPTransportOption{WithRootCAs(rootCAs)},
+			wantErr: true,
+		},
+		{
+			name: "minimum version above the server's",
+			opts: []HTTPTransportOption{
+				WithRootCAs(rootCAs),
+				WithClientCertificates(clientCert),
+				WithTLSMinVersion(tls.VersionTLS13),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trans, err := NewStreamableHTTP(server.URL, WithHTTPTransport(tt.opts...))
+			require.NoError(t, err)
+			defer trans.Close()
+
+			err = ping(t, trans)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWithHTTPTransport_KeepsClient(t *testing.T) {
+	base := &http.Client{Timeout: 5 * time.Second}
+
+	trans, err := NewStreamableHTTP("http://localhost/mcp",
+		WithHTTPBasicClient(base),
+		WithHTTPTransport(WithTLSMinVersion(tls.VersionTLS13)),
+	)
+	require.NoError(t, err)
+	assert.Nil(t, base.Transport, "the given client must not change")
+	assert.Equal(t, 5*time.Second, trans.httpClient.Timeout)
+	assert.Equal(t, uint16(tls.VersionTLS13), trans.httpClient.Transport.(*http.Transport).TLSClientConfig.MinVersion)
+
+	sse, err := NewSSE("http://localhost/sse",
+		WithHTTPClient(base),
+		WithSSETransport(WithTLSMinVersion(tls.VersionTLS13)),
+		WithSSETransport(WithDialTimeout(time.Second)),
+	)
+	require.NoError(t, err)
+	assert.Nil(t, base.Transport, "the given client must not change")
+	assert.Equal(t, 5*time.Second, sse.httpClient.Timeout)
+	tr := sse.httpClient.Transport.(*http.Transport)
+	assert.Equal(t, uint16(tls.VersionTLS13), tr.TLSClientConfig.MinVersion, "later options must add to earlier ones")
+	assert.NotNil(t, tr.DialContext)
+}
@@ -117,6 +117,12 @@ func (c *InProcessTransport) SendNotification(ctx context.Context, notification
 		return fmt.Errorf("failed to marshal notification: %w", err)
 	}
 	notificationBytes = append(notificationBytes, '\n')
+
+	// Add session to context if available, so that notification handlers
+	// can send requests to the client
+	if c.session != nil {
+		ctx = c.server.WithContext(ctx, c.session)
+	}
 	c.server.HandleMessage(ctx, notificationBytes)
 
 	return nil
@@ -48,6 +48,28 @@ type BidirectionalInterface interface {
 	SetRequestHandler(handler RequestHandler)
 }
 
+// requestNotificationHandlerKey is the context key for the handler set by
+// WithRequestNotificationHandler.
+type requestNotificationHandlerKey struct{}
+
+// WithRequestNotificationHandler returns a copy of ctx that makes SendRequest
+// deliver notifications streamed in reply to that specific request, such as
+// progress updates or log messages, to handler as soon as they are parsed.
+// The transport-wide notification handler still receives them as well.
+//
+// Only transports with per-request response streams (StreamableHTTP) can
+// attribute notifications to a request; others ignore the handler.
+func WithRequestNotificationHandler(ctx context.Context, handler func(notification mcp.JSONRPCNotification)) context.Context {
+	return context.WithValue(ctx, requestNotificationHandlerKey{}, handler)
+}
+
+// requestNotificationHandlerFromContext returns the handler set by
+// WithRequestNotificationHandler, or nil.
+func requestNotificationHandlerFromContext(ctx context.Context) func(notification mcp.JSONRPCNotification) {
+	handler, _ := ctx.Value(requestNotificationHandlerKey{}).(func(notification mcp.JSONRPCNotification))
+	return handler
+}
+
 // HTTPConnection is a Transport that runs over HTTP and supports
 // protocol version headers.
 type HTTPConnection interface {
@@ -57,7 +79,7 @@ type HTTPConnection interface {
 
 type JSONRPCRequest struct {
 	JSONRPC string        `json:"jsonrpc"`
-	ID      mcp.RequestId `json:"id"`
+	ID      mcp.RequestID `json:"id"`
 	Method  string        `json:"method"`
 	Params  any           `json:"params,omitempty"`
 	Header  http.Header   `json:"-"`
@@ -68,7 +90,7 @@ type JSONRPCRequest struct {
 // Use NewJSONRPCErrorResponse to create a JSONRPCResponse with an error.
 type JSONRPCResponse struct {
 	JSONRPC string                   `json:"jsonrpc"`
-	ID      mcp.RequestId            `json:"id"`
+	ID      mcp.RequestID            `json:"id"`
 	Result  json.RawMessage          `json:"result,omitempty"`
 	Error   *mcp.JSONRPCErrorDetails `json:"error,omitempty"`
 }
@@ -0,0 +1,30 @@
+package transport
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NamedPipeDialTimeout is how long DialNamedPipe waits for a busy pipe to
+// become available.
+var NamedPipeDialTimeout = 5 * time.Second
+
+// DialNamedPipe connects to an MCP server serving the Windows named pipe
+// name, for example with server.ServeNamedPipe. The name is either a full
+// pipe path such as \\.\pipe\my-server or just the final component of one.
+// Messages are exchanged with the stdio framing, so the returned transport
+// is a Stdio one; closing it closes the pipe.
+//
+// On other platforms DialNamedPipe returns errors.ErrUnsupported.
+func DialNamedPipe(name string) (*Stdio, error) {
+	path := name
+	if !strings.HasPrefix(path, `\\`) {
+		path = `\\.\pipe\` + name
+	}
+	conn, err := dialNamedPipe(path, NamedPipeDialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to named pipe %s: %w", path, err)
+	}
+	return NewIO(conn, conn, nil), nil
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package transport
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+func dialNamedPipe(path string, timeout time.Duration) (io.ReadWriteCloser, error) {
+	return nil, errors.ErrUnsupported
+}
@@ -0,0 +1,16 @@
+package transport
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+)
+
+func TestDialNamedPipe_Unsupported(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("named pipes are supported on Windows")
+	}
+	if _, err := DialNamedPipe("mcp-test"); !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("Expected errors.ErrUnsupported, got %v", err)
+	}
+}
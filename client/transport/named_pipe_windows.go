@@ -0,0 +1,36 @@
+//go:build windows
+
+package transport
+
+import (
+	"io"
+	"os"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var procWaitNamedPipeW = syscall.NewLazyDLL("kernel32.dll").NewProc("WaitNamedPipeW")
+
+const errorPipeBusy syscall.Errno = 231
+
+func dialNamedPipe(path string, timeout time.Duration) (io.ReadWriteCloser, error) {
+	name, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		h, err := syscall.CreateFile(name, syscall.GENERIC_READ|syscall.GENERIC_WRITE, 0, nil, syscall.OPEN_EXISTING, 0, 0)
+		if err == nil {
+			return os.NewFile(uintptr(h), path), nil
+		}
+		remaining := time.Until(deadline)
+		if err != errorPipeBusy || remaining <= 0 {
+			return nil, err
+		}
+		// All instances are in use: wait for one to be freed, then race the
+		// other waiting clients for it.
+		_, _, _ = procWaitNamedPipeW.Call(uintptr(unsafe.Pointer(name)), uintptr(remaining.Milliseconds()))
+	}
+}
@@ -37,8 +37,20 @@ type OAuthConfig struct {
 	// HTTPClient is an optional HTTP client to use for requests.
 	// If nil, a default HTTP client with a 30 second timeout will be used.
 	HTTPClient *http.Client
+	// Resource is the RFC 8707 resource indicator sent with authorization
+	// and token requests. If empty, the resource advertised in the
+	// server's protected resource metadata is used, when available.
+	Resource string
+	// RefreshBefore is how long before its expiry a token with a refresh
+	// token is refreshed, so that requests don't race the expiry. Zero
+	// means DefaultTokenRefreshBefore; a negative value refreshes tokens
+	// only once they have expired.
+	RefreshBefore time.Duration
 }
 
+// DefaultTokenRefreshBefore is the default OAuthConfig.RefreshBefore.
+const DefaultTokenRefreshBefore = 30 * time.Second
+
 // TokenStore is an interface for storing and retrieving OAuth tokens.
 //
 // Implementations must:
@@ -138,15 +150,30 @@ type AuthServerMetadata struct {
 
 // OAuthHandler handles OAuth authentication for HTTP requests
 type OAuthHandler struct {
-	config           OAuthConfig
-	httpClient       *http.Client
+	config     OAuthConfig
+	httpClient *http.Client
+	baseURL    string
+
+	// serverMetadata and metadataFetchErr are the result of metadata
+	// discovery, which ran if metadataFetched is set. metadataSource is the
+	// resourceMetadataURL discovery used: a challenge advertising another
+	// one invalidates the result. All are guarded by metadataMu.
+	metadataMu       sync.Mutex
 	serverMetadata   *AuthServerMetadata
 	metadataFetchErr error
-	metadataOnce     sync.Once
-	baseURL          string
+	metadataFetched  bool
+	metadataSource   string
+
+	// resourceMetadataURL is the protected resource metadata URL taken
+	// from a WWW-Authenticate challenge, and resource the resource it
+	// advertises. Both are guarded by mu.
+	resourceMetadataURL string
+	resource            string
 
-	mu            sync.RWMutex // Protects expectedState
+	mu            sync.RWMutex // Protects expectedState, resourceMetadataURL and resource
 	expectedState string       // Expected state value for CSRF protection
+
+	refreshMu sync.Mutex // Serializes token refreshes
 }
 
 // NewOAuthHandler creates a new OAuth handler
@@ -186,16 +213,21 @@ func (h *OAuthHandler) getValidToken(ctx context.Context) (*Token, error) {
 	if err != nil && !errors.Is(err, ErrNoToken) {
 		return nil, err
 	}
-	if err == nil && !token.IsExpired() && token.AccessToken != "" {
+	usable := err == nil && !token.IsExpired() && token.AccessToken != ""
+	if usable && (token.RefreshToken == "" || !h.expiresSoon(token)) {
 		return token, nil
 	}
 
 	// If we have a refresh token, try to use it
 	if err == nil && token.RefreshToken != "" {
-		newToken, err := h.refreshToken(ctx, token.RefreshToken)
+		newToken, err := h.refreshStoredToken(ctx, token)
 		if err == nil {
 			return newToken, nil
 		}
+		// A token refreshed ahead of its expiry is still valid
+		if usable {
+			return token, nil
+		}
 		// If refresh fails, continue to authorization flow
 	}
 
@@ -203,6 +235,32 @@ func (h *OAuthHandler) getValidToken(ctx context.Context) (*Token, error) {
 	return nil, ErrOAuthAuthorizationRequired
 }
 
+// expiresSoon reports whether token expires within the refresh window.
+func (h *OAuthHandler) expiresSoon(token *Token) bool {
+	window := h.config.RefreshBefore
+	if window == 0 {
+		window = DefaultTokenRefreshBefore
+	}
+	if window < 0 || token.ExpiresAt.IsZero() {
+		return false
+	}
+	return time.Until(token.ExpiresAt) < window
+}
+
+// refreshStoredToken refreshes token, the token read from the store, unless
+// a concurrent refresh already replaced it.
+func (h *OAuthHandler) refreshStoredToken(ctx context.Context, token *Token) (*Token, error) {
+	h.refreshMu.Lock()
+	defer h.refreshMu.Unlock()
+
+	current, err := h.config.TokenStore.GetToken(ctx)
+	if err == nil && current.AccessToken != token.AccessToken &&
+		current.AccessToken != "" && !current.IsExpired() && !h.expiresSoon(current) {
+		return current, nil
+	}
+	return h.refreshToken(ctx, token.RefreshToken)
+}
+
 // refreshToken refreshes an OAuth token
 func (h *OAuthHandler) refreshToken(ctx context.Context, refreshToken string) (*Token, error) {
 	metadata, err := h.getServerMetadata(ctx)
@@ -217,6 +275,9 @@ func (h *OAuthHandler) refreshToken(ctx context.Context, refreshToken string) (*
 	if h.config.ClientSecret != "" {
 		data.Set("client_secret", h.config.ClientSecret)
 	}
+	if resource := h.Resource(); resource != "" {
+		data.Set("resource", resource)
+	}
 
 	req, err := http.NewRequestWithContext(
 		ctx,
@@ -342,104 +403,129 @@ type OAuthProtectedResource struct {
 	ResourceName         string   `json:"resource_name,omitempty"`
 }
 
-// getServerMetadata fetches the OAuth server metadata
+// getServerMetadata fetches the OAuth server metadata. The result is
+// cached until a WWW-Authenticate challenge advertises another protected
+// resource metadata URL.
 func (h *OAuthHandler) getServerMetadata(ctx context.Context) (*AuthServerMetadata, error) {
-	h.metadataOnce.Do(func() {
-		// If AuthServerMetadataURL is explicitly provided, use it directly
-		if h.config.AuthServerMetadataURL != "" {
-			h.fetchMetadataFromURL(ctx, h.config.AuthServerMetadataURL)
-			return
-		}
+	h.mu.RLock()
+	resourceMetadataURL := h.resourceMetadataURL
+	h.mu.RUnlock()
 
-		// Try to discover the authorization server via OAuth Protected Resource
-		// as per RFC 9728 (https://datatracker.ietf.org/doc/html/rfc9728)
-		baseURL, err := h.extractBaseURL()
-		if err != nil {
-			h.metadataFetchErr = fmt.Errorf("failed to extract base URL: %w", err)
-			return
-		}
+	h.metadataMu.Lock()
+	defer h.metadataMu.Unlock()
+	if !h.metadataFetched || h.metadataSource != resourceMetadataURL {
+		h.serverMetadata, h.metadataFetchErr = nil, nil
+		h.discoverServerMetadata(ctx, resourceMetadataURL)
+		h.metadataFetched, h.metadataSource = true, resourceMetadataURL
+	}
 
-		// Try to fetch the OAuth Protected Resource metadata
-		protectedResourceURL := baseURL + "/.well-known/oauth-protected-resource"
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, protectedResourceURL, nil)
-		if err != nil {
-			h.metadataFetchErr = fmt.Errorf("failed to create protected resource request: %w", err)
-			return
-		}
+	if h.metadataFetchErr != nil {
+		return nil, h.metadataFetchErr
+	}
 
-		req.Header.Set("Accept", "application/json")
-		req.Header.Set("MCP-Protocol-Version", "2025-03-26")
+	return h.serverMetadata, nil
+}
 
-		resp, err := h.httpClient.Do(req)
-		if err != nil {
-			h.metadataFetchErr = fmt.Errorf("failed to send protected resource request: %w", err)
-			return
-		}
-		defer resp.Body.Close()
+// discoverServerMetadata sets serverMetadata or metadataFetchErr, using the
+// protected resource metadata at resourceMetadataURL if it is not empty.
+func (h *OAuthHandler) discoverServerMetadata(ctx context.Context, resourceMetadataURL string) {
+	// If AuthServerMetadataURL is explicitly provided, use it directly
+	if h.config.AuthServerMetadataURL != "" {
+		h.fetchMetadataFromURL(ctx, h.config.AuthServerMetadataURL)
+		return
+	}
 
-		// If we can't get the protected resource metadata, try OAuth Authorization Server discovery
-		if resp.StatusCode != http.StatusOK {
-			h.fetchMetadataFromURL(ctx, baseURL+"/.well-known/oauth-authorization-server")
-			if h.serverMetadata != nil {
-				return
-			}
-			// If that also fails, fall back to default endpoints
-			metadata, err := h.getDefaultEndpoints(baseURL)
-			if err != nil {
-				h.metadataFetchErr = fmt.Errorf("failed to get default endpoints: %w", err)
-				return
-			}
-			h.serverMetadata = metadata
-			return
-		}
+	// Try to discover the authorization server via OAuth Protected Resource
+	// as per RFC 9728 (https://datatracker.ietf.org/doc/html/rfc9728)
+	baseURL, err := h.extractBaseURL()
+	if err != nil {
+		h.metadataFetchErr = fmt.Errorf("failed to extract base URL: %w", err)
+		return
+	}
 
-		// Parse the protected resource metadata
-		var protectedResource OAuthProtectedResource
-		if err := json.NewDecoder(resp.Body).Decode(&protectedResource); err != nil {
-			h.metadataFetchErr = fmt.Errorf("failed to decode protected resource response: %w", err)
-			return
-		}
+	// Try to fetch the OAuth Protected Resource metadata, preferring the
+	// URL advertised in a WWW-Authenticate challenge (RFC 9728 section 5.1)
+	protectedResourceURL := baseURL + "/.well-known/oauth-protected-resource"
+	if resourceMetadataURL != "" {
+		protectedResourceURL = resourceMetadataURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, protectedResourceURL, nil)
+	if err != nil {
+		h.metadataFetchErr = fmt.Errorf("failed to create protected resource request: %w", err)
+		return
+	}
 
-		// If no authorization servers are specified, fall back to default endpoints
-		if len(protectedResource.AuthorizationServers) == 0 {
-			metadata, err := h.getDefaultEndpoints(baseURL)
-			if err != nil {
-				h.metadataFetchErr = fmt.Errorf("failed to get default endpoints: %w", err)
-				return
-			}
-			h.serverMetadata = metadata
-			return
-		}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("MCP-Protocol-Version", "2025-03-26")
 
-		// Use the first authorization server
-		authServerURL := protectedResource.AuthorizationServers[0]
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		h.metadataFetchErr = fmt.Errorf("failed to send protected resource request: %w", err)
+		return
+	}
+	defer resp.Body.Close()
 
-		// Try OpenID Connect discovery first
-		h.fetchMetadataFromURL(ctx, authServerURL+"/.well-known/openid-configuration")
+	// If we can't get the protected resource metadata, try OAuth Authorization Server discovery
+	if resp.StatusCode != http.StatusOK {
+		h.fetchMetadataFromURL(ctx, baseURL+"/.well-known/oauth-authorization-server")
 		if h.serverMetadata != nil {
 			return
 		}
-
-		// If OpenID Connect discovery fails, try OAuth Authorization Server Metadata
-		h.fetchMetadataFromURL(ctx, authServerURL+"/.well-known/oauth-authorization-server")
-		if h.serverMetadata != nil {
+		// If that also fails, fall back to default endpoints
+		metadata, err := h.getDefaultEndpoints(baseURL)
+		if err != nil {
+			h.metadataFetchErr = fmt.Errorf("failed to get default endpoints: %w", err)
 			return
 		}
+		h.serverMetadata = metadata
+		return
+	}
 
-		// If both discovery methods fail, use default endpoints based on the authorization server URL
-		metadata, err := h.getDefaultEndpoints(authServerURL)
+	// Parse the protected resource metadata
+	var protectedResource OAuthProtectedResource
+	if err := json.NewDecoder(resp.Body).Decode(&protectedResource); err != nil {
+		h.metadataFetchErr = fmt.Errorf("failed to decode protected resource response: %w", err)
+		return
+	}
+	if protectedResource.Resource != "" {
+		h.mu.Lock()
+		h.resource = protectedResource.Resource
+		h.mu.Unlock()
+	}
+
+	// If no authorization servers are specified, fall back to default endpoints
+	if len(protectedResource.AuthorizationServers) == 0 {
+		metadata, err := h.getDefaultEndpoints(baseURL)
 		if err != nil {
 			h.metadataFetchErr = fmt.Errorf("failed to get default endpoints: %w", err)
 			return
 		}
 		h.serverMetadata = metadata
-	})
+		return
+	}
 
-	if h.metadataFetchErr != nil {
-		return nil, h.metadataFetchErr
+	// Use the first authorization server
+	authServerURL := protectedResource.AuthorizationServers[0]
+
+	// Try OpenID Connect discovery first
+	h.fetchMetadataFromURL(ctx, authServerURL+"/.well-known/openid-configuration")
+	if h.serverMetadata != nil {
+		return
 	}
 
-	return h.serverMetadata, nil
+	// If OpenID Connect discovery fails, try OAuth Authorization Server Metadata
+	h.fetchMetadataFromURL(ctx, authServerURL+"/.well-known/oauth-authorization-server")
+	if h.serverMetadata != nil {
+		return
+	}
+
+	// If both discovery methods fail, use default endpoints based on the authorization server URL
+	metadata, err := h.getDefaultEndpoints(authServerURL)
+	if err != nil {
+		h.metadataFetchErr = fmt.Errorf("failed to get default endpoints: %w", err)
+		return
+	}
+	h.serverMetadata = metadata
 }
 
 // fetchMetadataFromURL fetches and parses OAuth server metadata from a URL
@@ -641,6 +727,10 @@ func (h *OAuthHandler) ProcessAuthorizationResponse(ctx context.Context, code, s
 		data.Set("code_verifier", codeVerifier)
 	}
 
+	if resource := h.Resource(); resource != "" {
+		data.Set("resource", resource)
+	}
+
 	req, err := http.NewRequestWithContext(
 		ctx,
 		http.MethodPost,
@@ -685,14 +775,14 @@ func (h *OAuthHandler) ProcessAuthorizationResponse(ctx context.Context, code, s
 
 // GetAuthorizationURL returns the URL for the authorization endpoint
 func (h *OAuthHandler) GetAuthorizationURL(ctx context.Context, state, codeChallenge string) (string, error) {
+	// Store the state for later validation
+	h.SetExpectedState(state)
+
 	metadata, err := h.getServerMetadata(ctx)
 	if err != nil {
 		return "", fmt.Errorf("failed to get server metadata: %w", err)
 	}
 
-	// Store the state for later validation
-	h.SetExpectedState(state)
-
 	params := url.Values{}
 	params.Set("response_type", "code")
 	params.Set("client_id", h.config.ClientID)
@@ -708,5 +798,137 @@ func (h *OAuthHandler) GetAuthorizationURL(ctx context.Context, state, codeChall
 		params.Set("code_challenge_method", "S256")
 	}
 
+	if resource := h.Resource(); resource != "" {
+		params.Set("resource", resource)
+	}
+
 	return metadata.AuthorizationEndpoint + "?" + params.Encode(), nil
 }
+
+// AuthorizationRequest holds the values generated to start an
+// authorization code flow. State and CodeVerifier must be kept until the
+// callback is handled and passed to ProcessAuthorizationResponse.
+type AuthorizationRequest struct {
+	// URL is the authorization endpoint URL to open in the user's browser.
+	URL string
+	// State is the CSRF protection value included in URL.
+	State string
+	// CodeVerifier is the PKCE code verifier. It is empty when PKCE is disabled.
+	CodeVerifier string
+}
+
+// StartAuthorization generates a state value and, when PKCE is enabled, a
+// code verifier and challenge, and returns the authorization URL for them.
+func (h *OAuthHandler) StartAuthorization(ctx context.Context) (*AuthorizationRequest, error) {
+	state, err := GenerateState()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate state: %w", err)
+	}
+
+	var codeVerifier, codeChallenge string
+	if h.config.PKCEEnabled {
+		codeVerifier, err = GenerateCodeVerifier()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate code verifier: %w", err)
+		}
+		codeChallenge = GenerateCodeChallenge(codeVerifier)
+	}
+
+	authURL, err := h.GetAuthorizationURL(ctx, state, codeChallenge)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthorizationRequest{
+		URL:          authURL,
+		State:        state,
+		CodeVerifier: codeVerifier,
+	}, nil
+}
+
+// Resource returns the RFC 8707 resource indicator sent with authorization
+// and token requests, or an empty string if none is known.
+func (h *OAuthHandler) Resource() string {
+	if h.config.Resource != "" {
+		return h.config.Resource
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.resource
+}
+
+// GetResourceMetadataURL returns the protected resource metadata URL taken
+// from the server's WWW-Authenticate challenge, if any.
+func (h *OAuthHandler) GetResourceMetadataURL() string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.resourceMetadataURL
+}
+
+// handleUnauthorized records the resource_metadata parameter of a
+// WWW-Authenticate challenge so metadata discovery can use it.
+func (h *OAuthHandler) handleUnauthorized(header http.Header) {
+	for _, challenge := range header.Values("WWW-Authenticate") {
+		params := parseAuthChallengeParams(challenge)
+		if resourceMetadata := params["resource_metadata"]; resourceMetadata != "" {
+			h.mu.Lock()
+			h.resourceMetadataURL = resourceMetadata
+			h.mu.Unlock()
+			return
+		}
+	}
+}
+
+// parseAuthChallengeParams parses the auth-param list of a WWW-Authenticate
+// challenge such as `Bearer realm="mcp", resource_metadata="https://..."`.
+// Parameter names are lower-cased; the scheme is ignored.
+func parseAuthChallengeParams(challenge string) map[string]string {
+	params := make(map[string]string)
+	// Skip the auth scheme
+	if i := strings.IndexByte(challenge, ' '); i >= 0 {
+		challenge = challenge[i+1:]
+	} else {
+		return params
+	}
+
+	for {
+		challenge = strings.TrimLeft(challenge, " ,")
+		eq := strings.IndexByte(challenge, '=')
+		if eq <= 0 {
+			return params
+		}
+		name := strings.ToLower(strings.TrimSpace(challenge[:eq]))
+		challenge = strings.TrimLeft(challenge[eq+1:], " ")
+
+		var value string
+		if strings.HasPrefix(challenge, `"`) {
+			var b strings.Builder
+			i := 1
+			for ; i < len(challenge); i++ {
+				c := challenge[i]
+				if c == '\\' && i+1 < len(challenge) {
+					i++
+					b.WriteByte(challenge[i])
+					continue
+				}
+				if c == '"' {
+					break
+				}
+				b.WriteByte(c)
+			}
+			value = b.String()
+			if i < len(challenge) {
+				i++
+			}
+			challenge = challenge[i:]
+		} else {
+			end := strings.IndexByte(challenge, ',')
+			if end < 0 {
+				end = len(challenge)
+			}
+			value = strings.TrimSpace(challenge[:end])
+			challenge = challenge[end:]
+		}
+		params[name] = value
+	}
+}
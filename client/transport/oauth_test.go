@@ -940,3 +940,219 @@ func TestOAuthHandler_GetServerMetadata_FallbackToDefaultEndpoints(t *testing.T)
 		t.Errorf("Expected token endpoint to be %s/token, got %s", server.URL, metadata.TokenEndpoint)
 	}
 }
+
+func TestParseAuthChallengeParams(t *testing.T) {
+	testCases := []struct {
+		name      string
+		challenge string
+		expected  map[string]string
+	}{
+		{
+			name:      "quoted resource metadata",
+			challenge: `Bearer resource_metadata="https://example.com/.well-known/oauth-protected-resource"`,
+			expected:  map[string]string{"resource_metadata": "https://example.com/.well-known/oauth-protected-resource"},
+		},
+		{
+			name:      "multiple params with mixed quoting",
+			challenge: `Bearer realm="mcp", error=invalid_token, Resource_Metadata="https://example.com/meta"`,
+			expected: map[string]string{
+				"realm":             "mcp",
+				"error":             "invalid_token",
+				"resource_metadata": "https://example.com/meta",
+			},
+		},
+		{
+			name:      "escaped quote",
+			challenge: `Bearer error_description="token \"abc\" expired"`,
+			expected:  map[string]string{"error_description": `token "abc" expired`},
+		},
+		{
+			name:      "scheme only",
+			challenge: `Bearer`,
+			expected:  map[string]string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			params := parseAuthChallengeParams(tc.challenge)
+			if len(params) != len(tc.expected) {
+				t.Fatalf("Expected %d params, got %d: %v", len(tc.expected), len(params), params)
+			}
+			for k, v := range tc.expected {
+				if params[k] != v {
+					t.Errorf("Expected %s=%q, got %q", k, v, params[k])
+				}
+			}
+		})
+	}
+}
+
+func TestOAuthHandler_ResourceMetadataFromChallenge(t *testing.T) {
+	var tokenForm map[string][]string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/custom/resource-metadata":
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(OAuthProtectedResource{
+				AuthorizationServers: []string{server.URL + "/auth"},
+				Resource:             server.URL + "/mcp",
+			})
+		case "/auth/.well-known/openid-configuration":
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(AuthServerMetadata{
+				Issuer:                server.URL + "/auth",
+				AuthorizationEndpoint: server.URL + "/auth/authorize",
+				TokenEndpoint:         server.URL + "/auth/token",
+			})
+		case "/auth/token":
+			_ = r.ParseForm()
+			tokenForm = r.PostForm
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(Token{AccessToken: "access", TokenType: "Bearer", ExpiresIn: 3600})
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	handler := NewOAuthHandler(OAuthConfig{
+		ClientID:    "client",
+		RedirectURI: "http://localhost:8085/callback",
+		PKCEEnabled: true,
+	})
+	handler.SetBaseURL(server.URL)
+
+	header := http.Header{}
+	header.Add("WWW-Authenticate", `Bearer resource_metadata="`+server.URL+`/custom/resource-metadata"`)
+	handler.handleUnauthorized(header)
+	if got := handler.GetResourceMetadataURL(); got != server.URL+"/custom/resource-metadata" {
+		t.Fatalf("Expected resource metadata URL from challenge, got %q", got)
+	}
+
+	authRequest, err := handler.StartAuthorization(context.Background())
+	if err != nil {
+		t.Fatalf("StartAuthorization failed: %v", err)
+	}
+	if authRequest.State == "" || authRequest.CodeVerifier == "" {
+		t.Fatalf("Expected state and code verifier, got %+v", authRequest)
+	}
+	if handler.GetExpectedState() != authRequest.State {
+		t.Errorf("Expected state to be stored for validation")
+	}
+	if handler.Resource() != server.URL+"/mcp" {
+		t.Errorf("Expected resource from protected resource metadata, got %q", handler.Resource())
+	}
+
+	for key, expected := range map[string]string{
+		"resource":              server.URL + "/mcp",
+		"state":                 authRequest.State,
+		"code_challenge":        GenerateCodeChallenge(authRequest.CodeVerifier),
+		"code_challenge_method": "S256",
+	} {
+		if !strings.Contains(authRequest.URL, key+"=") {
+			t.Errorf("Expected authorization URL to contain %s, got %s", key, authRequest.URL)
+			continue
+		}
+		parsed, _ := http.NewRequest(http.MethodGet, authRequest.URL, nil)
+		if got := parsed.URL.Query().Get(key); got != expected {
+			t.Errorf("Expected %s=%q, got %q", key, expected, got)
+		}
+	}
+	if !strings.HasPrefix(authRequest.URL, server.URL+"/auth/authorize?") {
+		t.Errorf("Expected discovered authorization endpoint, got %s", authRequest.URL)
+	}
+
+	err = handler.ProcessAuthorizationResponse(context.Background(), "code", authRequest.State, authRequest.CodeVerifier)
+	if err != nil {
+		t.Fatalf("ProcessAuthorizationResponse failed: %v", err)
+	}
+	if got := tokenForm["resource"]; len(got) != 1 || got[0] != server.URL+"/mcp" {
+		t.Errorf("Expected resource in token request, got %v", got)
+	}
+	if got := tokenForm["code_verifier"]; len(got) != 1 || got[0] != authRequest.CodeVerifier {
+		t.Errorf("Expected code_verifier in token request, got %v", got)
+	}
+}
+
+func TestOAuthHandler_ServerMetadataFollowsChallenge(t *testing.T) {
+	var discoveries int
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/first/resource-metadata", "/second/resource-metadata":
+			discoveries++
+			authServer := server.URL + "/" + strings.Split(r.URL.Path, "/")[1] + "-auth"
+			_ = json.NewEncoder(w).Encode(OAuthProtectedResource{AuthorizationServers: []string{authServer}})
+		case "/first-auth/.well-known/openid-configuration", "/second-auth/.well-known/openid-configuration":
+			authServer := server.URL + strings.TrimSuffix(r.URL.Path, "/.well-known/openid-configuration")
+			_ = json.NewEncoder(w).Encode(AuthServerMetadata{
+				Issuer:                authServer,
+				AuthorizationEndpoint: authServer + "/authorize",
+				TokenEndpoint:         authServer + "/token",
+			})
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	handler := NewOAuthHandler(OAuthConfig{ClientID: "client", RedirectURI: "http://localhost:8085/callback"})
+	handler.SetBaseURL(server.URL)
+	challenge := func(path string) {
+		header := http.Header{}
+		header.Add("WWW-Authenticate", `Bearer resource_metadata="`+server.URL+path+`"`)
+		handler.handleUnauthorized(header)
+	}
+
+	tokenEndpoint := func() string {
+		t.Helper()
+		metadata, err := handler.GetServerMetadata(context.Background())
+		if err != nil {
+			t.Fatalf("GetServerMetadata failed: %v", err)
+		}
+		return metadata.TokenEndpoint
+	}
+
+	challenge("/first/resource-metadata")
+	if got := tokenEndpoint(); got != server.URL+"/first-auth/token" {
+		t.Errorf("Expected the first authorization server, got %s", got)
+	}
+	challenge("/first/resource-metadata")
+	tokenEndpoint()
+	if discoveries != 1 {
+		t.Errorf("Expected metadata to be cached for the same challenge, discovered %d times", discoveries)
+	}
+
+	challenge("/second/resource-metadata")
+	if got := tokenEndpoint(); got != server.URL+"/second-auth/token" {
+		t.Errorf("Expected the authorization server of the new challenge, got %s", got)
+	}
+	if discoveries != 2 {
+		t.Errorf("Expected metadata to be discovered again for a new challenge, discovered %d times", discoveries)
+	}
+}
+
+func TestOAuthHandler_ConfiguredResourceTakesPrecedence(t *testing.T) {
+	handler := NewOAuthHandler(OAuthConfig{
+		ClientID:    "client",
+		RedirectURI: "http://localhost:8085/callback",
+		Resource:    "https://mcp.example.com",
+	})
+	handler.serverMetadata = &AuthServerMetadata{AuthorizationEndpoint: "https://auth.example.com/authorize"}
+	handler.metadataFetched = true
+	handler.resource = "https://other.example.com"
+
+	authURL, err := handler.GetAuthorizationURL(context.Background(), "state", "")
+	if err != nil {
+		t.Fatalf("GetAuthorizationURL failed: %v", err)
+	}
+	parsed, _ := http.NewRequest(http.MethodGet, authURL, nil)
+	if got := parsed.URL.Query().Get("resource"); got != "https://mcp.example.com" {
+		t.Errorf("Expected configured resource, got %q", got)
+	}
+}
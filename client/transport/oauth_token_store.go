@@ -0,0 +1,286 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrKeyringItemNotFound is returned by a Keyring without a secret for
+	// the given service and account.
+	ErrKeyringItemNotFound = errors.New("keyring item not found")
+
+	// ErrKeyringUnsupported is returned by the system keyring on platforms
+	// it does not support.
+	ErrKeyringUnsupported = errors.New("no system keyring available")
+)
+
+// FileTokenStore is a TokenStore keeping the token in a file encrypted with
+// AES-GCM, so that CLI hosts keep their authorization across restarts. The
+// file is only readable by its owner. It is safe for concurrent use, and its
+// methods return ctx.Err() if ctx is done before they start.
+type FileTokenStore struct {
+	path string
+	aead cipher.AEAD
+	mu   sync.Mutex
+}
+
+// NewFileTokenStore returns a store keeping the token in the file at path,
+// encrypted with key, which must be 16, 24 or 32 bytes long. The key must
+// be kept elsewhere, such as in the system keyring; GenerateTokenKey
+// creates one.
+func NewFileTokenStore(path string, key []byte) (*FileTokenStore, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token encryption key: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &FileTokenStore{path: path, aead: aead}, nil
+}
+
+// GenerateTokenKey returns a random key for NewFileTokenStore.
+func GenerateTokenKey() ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// GetToken returns the stored token.
+// Returns ErrNoToken if the file does not exist.
+func (s *FileTokenStore) GetToken(ctx context.Context) (*Token, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token file: %w", err)
+	}
+	nonceSize := s.aead.NonceSize()
+	if len(data) < nonceSize {
+		return nil, errors.New("failed to decrypt token file: file too short")
+	}
+	plaintext, err := s.aead.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt token file: %w", err)
+	}
+	var token Token
+	if err := json.Unmarshal(plaintext, &token); err != nil {
+		return nil, fmt.Errorf("failed to decode token file: %w", err)
+	}
+	return &token, nil
+}
+
+// SaveToken encrypts token and replaces the file with it.
+func (s *FileTokenStore) SaveToken(ctx context.Context, token *Token) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	plaintext, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	data := s.aead.Seal(nonce, nonce, plaintext, nil)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create token directory: %w", err)
+	}
+	// Write a temporary file first so that a crash never leaves a
+	// truncated token behind.
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+	return nil
+}
+
+// Keyring stores secrets in a credential store, such as the one of the
+// operating system.
+type Keyring interface {
+	// Get returns the secret of service and account.
+	// Returns ErrKeyringItemNotFound if there is none.
+	Get(ctx context.Context, service, account string) (string, error)
+	// Set stores the secret of service and account, replacing any previous
+	// one.
+	Set(ctx context.Context, service, account, secret string) error
+}
+
+// KeyringTokenStore is a TokenStore keeping the token in a Keyring. Its
+// methods return ctx.Err() if ctx is done before they start.
+type KeyringTokenStore struct {
+	keyring Keyring
+	service string
+	account string
+}
+
+// NewKeyringTokenStore returns a store keeping the token as the secret of
+// service and account in keyring. A nil keyring means SystemKeyring().
+func NewKeyringTokenStore(service, account string, keyring Keyring) *KeyringTokenStore {
+	if keyring == nil {
+		keyring = SystemKeyring()
+	}
+	return &KeyringTokenStore{keyring: keyring, service: service, account: account}
+}
+
+// GetToken returns the stored token.
+// Returns ErrNoToken if the keyring has none.
+func (s *KeyringTokenStore) GetToken(ctx context.Context) (*Token, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	secret, err := s.keyring.Get(ctx, s.service, s.account)
+	if errors.Is(err, ErrKeyringItemNotFound) {
+		return nil, ErrNoToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token from keyring: %w", err)
+	}
+	var token Token
+	if err := json.Unmarshal([]byte(secret), &token); err != nil {
+		return nil, fmt.Errorf("failed to decode token from keyring: %w", err)
+	}
+	return &token, nil
+}
+
+// SaveToken stores token in the keyring.
+func (s *KeyringTokenStore) SaveToken(ctx context.Context, token *Token) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	secret, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	if err := s.keyring.Set(ctx, s.service, s.account, string(secret)); err != nil {
+		return fmt.Errorf("failed to save token to keyring: %w", err)
+	}
+	return nil
+}
+
+// SystemKeyring returns the keyring of the operating system: the login
+// keychain on macOS, through the security command, and the Secret Service
+// on Linux, through the secret-tool command of libsecret. On other
+// platforms its methods return ErrKeyringUnsupported.
+func SystemKeyring() Keyring {
+	return &commandKeyring{goos: runtime.GOOS, run: runCommand}
+}
+
+// commandKeyring is a Keyring using the command line tools of the
+// operating system.
+type commandKeyring struct {
+	goos string
+	// run runs a command with stdin as its input and returns its output.
+	run func(ctx context.Context, stdin string, name string, args ...string) ([]byte, error)
+}
+
+func runCommand(ctx context.Context, stdin string, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil && stderr.Len() > 0 {
+		err = fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return out, err
+}
+
+// quoteArg quotes s as an argument of a command read by security -i, which
+// splits its input as a shell does.
+func quoteArg(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+// exitCode returns the exit code of a command that failed with err, or -1.
+func exitCode(err error) int {
+	var exitErr interface{ ExitCode() int }
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+func (k *commandKeyring) Get(ctx context.Context, service, account string) (string, error) {
+	switch k.goos {
+	case "darwin":
+		out, err := k.run(ctx, "", "security", "find-generic-password", "-s", service, "-a", account, "-w")
+		if exitCode(err) == 44 {
+			return "", ErrKeyringItemNotFound
+		}
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSuffix(string(out), "\n"), nil
+	case "linux":
+		out, err := k.run(ctx, "", "secret-tool", "lookup", "service", service, "account", account)
+		if exitCode(err) == 1 && len(out) == 0 {
+			return "", ErrKeyringItemNotFound
+		}
+		if err != nil {
+			return "", err
+		}
+		return string(out), nil
+	}
+	return "", ErrKeyringUnsupported
+}
+
+func (k *commandKeyring) Set(ctx context.Context, service, account, secret string) error {
+	switch k.goos {
+	case "darwin":
+		// security only reads the password from its arguments. Run the
+		// command from the standard input of security -i so that it does
+		// not show in the process list, with the password hex encoded by
+		// -X. -U updates an existing item.
+		if strings.ContainsAny(service+account, "\r\n") {
+			return fmt.Errorf("invalid keyring service %q or account %q", service, account)
+		}
+		command := fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n", quoteArg(service), quoteArg(account), hex.EncodeToString([]byte(secret)))
+		_, err := k.run(ctx, command, "security", "-i")
+		return err
+	case "linux":
+		_, err := k.run(ctx, secret, "secret-tool", "store", "--label="+service, "service", service, "account", account)
+		return err
+	}
+	return ErrKeyringUnsupported
+}
@@ -0,0 +1,267 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileTokenStore(t *testing.T) {
+	ctx := context.Background()
+	key, err := GenerateTokenKey()
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "nested", "token")
+
+	store, err := NewFileTokenStore(path, key)
+	require.NoError(t, err)
+	_, err = store.GetToken(ctx)
+	assert.ErrorIs(t, err, ErrNoToken)
+
+	token := &Token{
+		AccessToken:  "secret-access-token",
+		TokenType:    "Bearer",
+		RefreshToken: "secret-refresh-token",
+		ExpiresAt:    time.Now().Add(time.Hour).Round(0),
+	}
+	require.NoError(t, store.SaveToken(ctx, token))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret")
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	}
+
+	// A new store, as after a restart, reads the saved token.
+	reopened, err := NewFileTokenStore(path, key)
+	require.NoError(t, err)
+	got, err := reopened.GetToken(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, token.AccessToken, got.AccessToken)
+	assert.Equal(t, token.RefreshToken, got.RefreshToken)
+	assert.True(t, token.ExpiresAt.Equal(got.ExpiresAt))
+
+	otherKey, err := GenerateTokenKey()
+	require.NoError(t, err)
+	wrongKey, err := NewFileTokenStore(path, otherKey)
+	require.NoError(t, err)
+	_, err = wrongKey.GetToken(ctx)
+	assert.ErrorContains(t, err, "failed to decrypt token file")
+
+	_, err = NewFileTokenStore(path, []byte("short"))
+	assert.ErrorContains(t, err, "invalid token encryption key")
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = store.GetToken(canceled)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, store.SaveToken(canceled, token), context.Canceled)
+}
+
+// mapKeyring is a Keyring keeping secrets in memory.
+type mapKeyring map[string]string
+
+func (k mapKeyring) Get(ctx context.Context, service, account string) (string, error) {
+	secret, ok := k[service+"/"+account]
+	if !ok {
+		return "", ErrKeyringItemNotFound
+	}
+	return secret, nil
+}
+
+func (k mapKeyring) Set(ctx context.Context, service, account, secret string) error {
+	k[service+"/"+account] = secret
+	return nil
+}
+
+func TestKeyringTokenStore(t *testing.T) {
+	ctx := context.Background()
+	keyring := mapKeyring{}
+	store := NewKeyringTokenStore("mcp-test", "user", keyring)
+
+	_, err := store.GetToken(ctx)
+	assert.ErrorIs(t, err, ErrNoToken)
+
+	require.NoError(t, store.SaveToken(ctx, &Token{AccessToken: "access", TokenType: "Bearer"}))
+	assert.Contains(t, keyring["mcp-test/user"], `"access_token":"access"`)
+
+	got, err := NewKeyringTokenStore("mcp-test", "user", keyring).GetToken(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "access", got.AccessToken)
+}
+
+// exitError is a command failure with an exit code.
+type exitError int
+
+func (e exitError) Error() string { return "exit status" }
+func (e exitError) ExitCode() int { return int(e) }
+
+func TestSystemKeyring(t *testing.T) {
+	type call struct {
+		stdin string
+		args  []string
+	}
+	tests := []struct {
+		goos     string
+		notFound error
+		get      []string
+		set      call
+	}{
+		{
+			goos:     "darwin",
+			notFound: exitError(44),
+			get:      []string{"security", "find-generic-password", "-s", "svc", "-a", "acct", "-w"},
+			set:      call{stdin: "add-generic-password -U -s 'svc' -a 'acct' -X 736563726574\n", args: []string{"security", "-i"}},
+		},
+		{
+			goos:     "linux",
+			notFound: exitError(1),
+			get:      []string{"secret-tool", "lookup", "service", "svc", "account", "acct"},
+			set:      call{stdin: "secret", args: []string{"secret-tool", "store", "--label=svc", "service", "svc", "account", "acct"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			ctx := context.Background()
+			var calls []call
+			var result error
+			keyring := &commandKeyring{goos: tt.goos, run: func(ctx context.Context, stdin string, name string, args ...string) ([]byte, error) {
+				calls = append(calls, call{stdin: stdin, args: append([]string{name}, args...)})
+				if result != nil {
+					return nil, result
+				}
+				return []byte("secret\n"), nil
+			}}
+
+			require.NoError(t, keyring.Set(ctx, "svc", "acct", "secret"))
+			secret, err := keyring.Get(ctx, "svc", "acct")
+			require.NoError(t, err)
+			assert.Equal(t, []call{tt.set, {args: tt.get}}, calls)
+			if tt.goos == "darwin" {
+				assert.Equal(t, "secret", secret)
+			}
+
+			result = tt.notFound
+			_, err = keyring.Get(ctx, "svc", "acct")
+			assert.ErrorIs(t, err, ErrKeyringItemNotFound)
+
+			result = errors.New("locked")
+			_, err = keyring.Get(ctx, "svc", "acct")
+			assert.EqualError(t, err, "locked")
+		})
+	}
+
+	t.Run("darwin quoting", func(t *testing.T) {
+		var stdin string
+		keyring := &commandKeyring{goos: "darwin", run: func(ctx context.Context, input string, name string, args ...string) ([]byte, error) {
+			stdin = input
+			return nil, nil
+		}}
+		require.NoError(t, keyring.Set(context.Background(), "my svc", "it's me", "x"))
+		assert.Equal(t, "add-generic-password -U -s 'my svc' -a 'it'\"'\"'s me' -X 78\n", stdin)
+		assert.Error(t, keyring.Set(context.Background(), "svc\ndelete-keychain", "acct", "x"))
+	})
+
+	unsupported := &commandKeyring{goos: "plan9"}
+	_, err := unsupported.Get(context.Background(), "svc", "acct")
+	assert.ErrorIs(t, err, ErrKeyringUnsupported)
+	assert.ErrorIs(t, unsupported.Set(context.Background(), "svc", "acct", "x"), ErrKeyringUnsupported)
+}
+
+func TestOAuthHandler_RefreshBeforeExpiry(t *testing.T) {
+	var refreshes atomic.Int32
+	var failRefresh atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/oauth-authorization-server":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(AuthServerMetadata{
+				Issuer:        "http://" + r.Host,
+				TokenEndpoint: "http://" + r.Host + "/token",
+			})
+		case "/token":
+			if failRefresh.Load() {
+				http.Error(w, `{"error":"server_error"}`, http.StatusInternalServerError)
+				return
+			}
+			refreshes.Add(1)
+			time.Sleep(10 * time.Millisecond)
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(Token{AccessToken: "refreshed", TokenType: "Bearer", ExpiresIn: 3600})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	newHandler := func(expiresIn time.Duration, refreshBefore time.Duration) *OAuthHandler {
+		store := NewMemoryTokenStore()
+		require.NoError(t, store.SaveToken(context.Background(), &Token{
+			AccessToken:  "current",
+			TokenType:    "Bearer",
+			RefreshToken: "refresh",
+			ExpiresAt:    time.Now().Add(expiresIn),
+		}))
+		return NewOAuthHandler(OAuthConfig{
+			ClientID:              "client",
+			TokenStore:            store,
+			AuthServerMetadataURL: server.URL + "/.well-known/oauth-authorization-server",
+			RefreshBefore:         refreshBefore,
+		})
+	}
+
+	t.Run("refreshes a token about to expire once", func(t *testing.T) {
+		refreshes.Store(0)
+		handler := newHandler(10*time.Second, 0)
+		var wg sync.WaitGroup
+		for range 5 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				header, err := handler.GetAuthorizationHeader(context.Background())
+				assert.NoError(t, err)
+				assert.Equal(t, "Bearer refreshed", header)
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(1), refreshes.Load())
+	})
+
+	t.Run("keeps a token outside the window", func(t *testing.T) {
+		refreshes.Store(0)
+		header, err := newHandler(time.Hour, 0).GetAuthorizationHeader(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer current", header)
+		assert.Zero(t, refreshes.Load())
+	})
+
+	t.Run("negative window", func(t *testing.T) {
+		refreshes.Store(0)
+		header, err := newHandler(10*time.Second, -1).GetAuthorizationHeader(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer current", header)
+		assert.Zero(t, refreshes.Load())
+	})
+
+	t.Run("failed refresh uses the valid token", func(t *testing.T) {
+		failRefresh.Store(true)
+		defer failRefresh.Store(false)
+		header, err := newHandler(10*time.Second, 0).GetAuthorizationHeader(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer current", header)
+	})
+}
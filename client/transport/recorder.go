@@ -0,0 +1,157 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Direction tells whether a recorded frame was sent or received by the
+// client.
+type Direction string
+
+const (
+	// DirectionSent marks frames the client sent to the server.
+	DirectionSent Direction = "sent"
+	// DirectionReceived marks frames the client received from the server.
+	DirectionReceived Direction = "received"
+)
+
+// Frame is a JSON-RPC message recorded by a Recorder.
+type Frame struct {
+	Time      time.Time       `json:"time"`
+	Direction Direction       `json:"direction"`
+	Message   json.RawMessage `json:"message"`
+}
+
+// frameMessage holds the fields telling apart requests, notifications and
+// responses.
+type frameMessage struct {
+	ID     *mcp.RequestID `json:"id,omitempty"`
+	Method string         `json:"method,omitempty"`
+}
+
+func (f Frame) message() frameMessage {
+	var m frameMessage
+	_ = json.Unmarshal(f.Message, &m)
+	return m
+}
+
+// Recorder is a transport that records every JSON-RPC message exchanged
+// through the transport it wraps, one Frame per line of JSON, for
+// debugging and for replaying with ReplayTransport or ReplayToServer.
+// Server requests reach the request handler only if the wrapped transport
+// is a BidirectionalInterface.
+type Recorder struct {
+	Interface
+
+	mu  sync.Mutex
+	enc *json.Encoder
+	err error
+}
+
+// NewRecorder returns a transport recording the messages of t to w.
+func NewRecorder(t Interface, w io.Writer) *Recorder {
+	return &Recorder{Interface: t, enc: json.NewEncoder(w)}
+}
+
+// Err returns the first error writing a frame, if any. Failing to record a
+// frame does not fail the message.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+func (r *Recorder) record(direction Direction, message any) {
+	data, err := json.Marshal(message)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err == nil {
+		err = r.enc.Encode(Frame{Time: time.Now(), Direction: direction, Message: data})
+	}
+	if err != nil && r.err == nil {
+		r.err = err
+	}
+}
+
+// SendRequest records request and its response.
+func (r *Recorder) SendRequest(ctx context.Context, request JSONRPCRequest) (*JSONRPCResponse, error) {
+	r.record(DirectionSent, request)
+	response, err := r.Interface.SendRequest(ctx, request)
+	if response != nil {
+		r.record(DirectionReceived, response)
+	}
+	return response, err
+}
+
+// SendNotification records notification.
+func (r *Recorder) SendNotification(ctx context.Context, notification mcp.JSONRPCNotification) error {
+	r.record(DirectionSent, notification)
+	return r.Interface.SendNotification(ctx, notification)
+}
+
+// SetNotificationHandler records notifications before handler receives
+// them.
+func (r *Recorder) SetNotificationHandler(handler func(notification mcp.JSONRPCNotification)) {
+	r.Interface.SetNotificationHandler(func(notification mcp.JSONRPCNotification) {
+		r.record(DirectionReceived, notification)
+		handler(notification)
+	})
+}
+
+// SetRequestHandler records server requests and the responses of handler.
+func (r *Recorder) SetRequestHandler(handler RequestHandler) {
+	bidirectional, ok := r.Interface.(BidirectionalInterface)
+	if !ok {
+		return
+	}
+	bidirectional.SetRequestHandler(func(ctx context.Context, request JSONRPCRequest) (*JSONRPCResponse, error) {
+		r.record(DirectionReceived, request)
+		response, err := handler(ctx, request)
+		if response != nil {
+			r.record(DirectionSent, response)
+		}
+		return response, err
+	})
+}
+
+// SetProtocolVersion passes the negotiated version to the wrapped
+// transport if it is an HTTPConnection.
+func (r *Recorder) SetProtocolVersion(version string) {
+	if httpConn, ok := r.Interface.(HTTPConnection); ok {
+		httpConn.SetProtocolVersion(version)
+	}
+}
+
+// SetConnectionLostHandler passes handler to the wrapped transport if it
+// reports lost connections.
+func (r *Recorder) SetConnectionLostHandler(handler func(error)) {
+	if setter, ok := r.Interface.(interface{ SetConnectionLostHandler(func(error)) }); ok {
+		setter.SetConnectionLostHandler(handler)
+	}
+}
+
+// ReadRecording reads the frames written by a Recorder.
+func ReadRecording(r io.Reader) ([]Frame, error) {
+	var frames []Frame
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var frame Frame
+		if err := json.Unmarshal(scanner.Bytes(), &frame); err != nil {
+			return nil, fmt.Errorf("invalid frame on line %d: %w", line, err)
+		}
+		frames = append(frames, frame)
+	}
+	return frames, scanner.Err()
+}
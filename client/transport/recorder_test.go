@@ -0,0 +1,193 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// newRecordedServer returns a server whose greet tool notifies progress
+// before answering with greeting.
+func newRecordedServer(greeting string) *server.MCPServer {
+	s := server.NewMCPServer("recorded", "1.0.0", server.WithToolCapabilities(false))
+	s.AddTool(mcp.NewTool("greet"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		_ = server.ServerFromContext(ctx).SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+			"progressToken": "greet",
+			"progress":      1,
+		})
+		return mcp.NewToolResultText(greeting), nil
+	})
+	return s
+}
+
+// serverTransport hands messages to a server in process and delivers the
+// notifications sent while handling a request before its response.
+type serverTransport struct {
+	server  *server.MCPServer
+	session *recordedSession
+	handler func(notification mcp.JSONRPCNotification)
+}
+
+type recordedSession struct {
+	notifications chan mcp.JSONRPCNotification
+	initialized   atomic.Bool
+}
+
+func (s *recordedSession) Initialize()       { s.initialized.Store(true) }
+func (s *recordedSession) Initialized() bool { return s.initialized.Load() }
+func (s *recordedSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+func (s *recordedSession) SessionID() string { return "recorded" }
+
+func (t *serverTransport) Start(ctx context.Context) error {
+	t.session = &recordedSession{notifications: make(chan mcp.JSONRPCNotification, 10)}
+	return t.server.RegisterSession(ctx, t.session)
+}
+
+func (t *serverTransport) SendRequest(ctx context.Context, request JSONRPCRequest) (*JSONRPCResponse, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	data, err = json.Marshal(t.server.HandleMessage(t.server.WithContext(ctx, t.session), data))
+	if err != nil {
+		return nil, err
+	}
+	for len(t.session.notifications) > 0 {
+		t.handler(<-t.session.notifications)
+	}
+	var response JSONRPCResponse
+	return &response, json.Unmarshal(data, &response)
+}
+
+func (t *serverTransport) SendNotification(ctx context.Context, notification mcp.JSONRPCNotification) error {
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+	t.server.HandleMessage(t.server.WithContext(ctx, t.session), data)
+	return nil
+}
+
+func (t *serverTransport) SetNotificationHandler(handler func(notification mcp.JSONRPCNotification)) {
+	t.handler = handler
+}
+
+func (t *serverTransport) Close() error {
+	t.server.UnregisterSession(context.Background(), t.session.SessionID())
+	return nil
+}
+
+func (t *serverTransport) GetSessionId() string {
+	return t.session.SessionID()
+}
+
+// runSession initializes t and calls the greet tool.
+func runSession(t *testing.T, tr Interface) *JSONRPCResponse {
+	t.Helper()
+	ctx := context.Background()
+	require.NoError(t, tr.Start(ctx))
+	_, err := tr.SendRequest(ctx, JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestId(int64(1)),
+		Method:  string(mcp.MethodInitialize),
+		Params: map[string]any{
+			"protocolVersion": mcp.LATEST_PROTOCOL_VERSION,
+			"clientInfo":      map[string]any{"name": "test", "version": "1.0.0"},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, tr.SendNotification(ctx, mcp.JSONRPCNotification{
+		JSONRPC:      mcp.JSONRPC_VERSION,
+		Notification: mcp.Notification{Method: "notifications/initialized"},
+	}))
+	response, err := tr.SendRequest(ctx, JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestId(int64(2)),
+		Method:  string(mcp.MethodToolsCall),
+		Params:  map[string]any{"name": "greet"},
+	})
+	require.NoError(t, err)
+	return response
+}
+
+func TestRecorder(t *testing.T) {
+	var recording bytes.Buffer
+	recorder := NewRecorder(&serverTransport{server: newRecordedServer("hello")}, &recording)
+	notifications := make(chan mcp.JSONRPCNotification, 1)
+	recorder.SetNotificationHandler(func(notification mcp.JSONRPCNotification) {
+		notifications <- notification
+	})
+	runSession(t, recorder)
+	require.NoError(t, recorder.Close())
+	require.NoError(t, recorder.Err())
+
+	require.Len(t, notifications, 1, "notifications are passed on")
+	assert.Equal(t, "notifications/progress", (<-notifications).Method)
+
+	frames, err := ReadRecording(&recording)
+	require.NoError(t, err)
+	var summary []string
+	for _, frame := range frames {
+		assert.False(t, frame.Time.IsZero())
+		m := frame.message()
+		method := m.Method
+		if method == "" {
+			method = "response"
+		}
+		summary = append(summary, string(frame.Direction)+" "+method)
+	}
+	assert.Equal(t, []string{
+		"sent initialize",
+		"received response",
+		"sent notifications/initialized",
+		"sent tools/call",
+		"received notifications/progress",
+		"received response",
+	}, summary)
+
+	t.Run("replay to the client", func(t *testing.T) {
+		replay := NewReplayTransport(frames)
+		var replayed []string
+		replay.SetNotificationHandler(func(notification mcp.JSONRPCNotification) {
+			replayed = append(replayed, notification.Method)
+		})
+		response := runSession(t, replay)
+		assert.Equal(t, mcp.NewRequestId(int64(2)), response.ID)
+		assert.Contains(t, string(response.Result), "hello")
+		assert.Equal(t, []string{"notifications/progress"}, replayed)
+
+		_, err := replay.SendRequest(context.Background(), JSONRPCRequest{ID: mcp.NewRequestId(int64(3)), Method: string(mcp.MethodToolsCall)})
+		assert.True(t, errors.Is(err, ErrNoRecordedResponse), "every recorded response is replayed once")
+	})
+
+	t.Run("replay to a server", func(t *testing.T) {
+		mismatches, err := ReplayToServer(context.Background(), newRecordedServer("hello"), frames)
+		require.NoError(t, err)
+		assert.Empty(t, mismatches)
+
+		mismatches, err = ReplayToServer(context.Background(), newRecordedServer("goodbye"), frames)
+		require.NoError(t, err)
+		require.Len(t, mismatches, 1)
+		assert.Contains(t, string(mismatches[0].Request), `"tools/call"`)
+		assert.Contains(t, string(mismatches[0].Recorded), "hello")
+		assert.Contains(t, string(mismatches[0].Actual), "goodbye")
+	})
+}
+
+func TestReadRecording_Invalid(t *testing.T) {
+	frame, err := json.Marshal(Frame{Direction: DirectionSent, Message: json.RawMessage(`{}`)})
+	require.NoError(t, err)
+	_, err = ReadRecording(bytes.NewReader(append(append(frame, '\n'), []byte("not json\n")...)))
+	assert.ErrorContains(t, err, "invalid frame on line 2")
+}
@@ -0,0 +1,188 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ErrNoRecordedResponse is returned by ReplayTransport for requests the
+// recording has no response to.
+var ErrNoRecordedResponse = errors.New("no recorded response")
+
+// ReplayTransport is a transport that plays the server side of a
+// recording, so that a client can be tested without the server. Each
+// request is answered with the response to the first request of the same
+// method not yet replayed, and the notifications the client received after
+// that request and before its next message are delivered before the
+// response. Notifications sent by the client are ignored.
+type ReplayTransport struct {
+	mu       sync.Mutex
+	frames   []Frame
+	replayed []bool
+	handler  func(notification mcp.JSONRPCNotification)
+}
+
+// NewReplayTransport returns a transport replaying frames.
+func NewReplayTransport(frames []Frame) *ReplayTransport {
+	return &ReplayTransport{frames: frames, replayed: make([]bool, len(frames))}
+}
+
+func (t *ReplayTransport) Start(ctx context.Context) error {
+	return nil
+}
+
+// SendRequest answers request with its recorded response, with the ID of
+// request.
+func (t *ReplayTransport) SendRequest(ctx context.Context, request JSONRPCRequest) (*JSONRPCResponse, error) {
+	t.mu.Lock()
+	response, notifications, err := t.replay(request.Method)
+	handler := t.handler
+	t.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if handler != nil {
+		for _, notification := range notifications {
+			handler(notification)
+		}
+	}
+	response.ID = request.ID
+	return response, nil
+}
+
+// replay finds the next recorded request of method and returns its
+// response and the notifications following it.
+func (t *ReplayTransport) replay(method string) (*JSONRPCResponse, []mcp.JSONRPCNotification, error) {
+	start := -1
+	var id *mcp.RequestID
+	for i, frame := range t.frames {
+		if t.replayed[i] || frame.Direction != DirectionSent {
+			continue
+		}
+		if m := frame.message(); m.Method == method && m.ID != nil {
+			start, id = i, m.ID
+			break
+		}
+	}
+	if start < 0 {
+		return nil, nil, fmt.Errorf("%w to %s", ErrNoRecordedResponse, method)
+	}
+	t.replayed[start] = true
+
+	var response *JSONRPCResponse
+	var notifications []mcp.JSONRPCNotification
+	for i := start + 1; i < len(t.frames); i++ {
+		frame := t.frames[i]
+		if frame.Direction == DirectionSent {
+			if response != nil {
+				break
+			}
+			continue
+		}
+		m := frame.message()
+		switch {
+		case m.Method == "" && m.ID != nil && m.ID.Equal(*id) && response == nil:
+			response = &JSONRPCResponse{}
+			if err := json.Unmarshal(frame.Message, response); err != nil {
+				return nil, nil, fmt.Errorf("invalid recorded response to %s: %w", method, err)
+			}
+			t.replayed[i] = true
+		case m.Method != "" && m.ID == nil && !t.replayed[i]:
+			var notification mcp.JSONRPCNotification
+			if err := json.Unmarshal(frame.Message, &notification); err == nil {
+				notifications = append(notifications, notification)
+			}
+			t.replayed[i] = true
+		}
+	}
+	if response == nil {
+		return nil, nil, fmt.Errorf("%w to %s", ErrNoRecordedResponse, method)
+	}
+	return response, notifications, nil
+}
+
+func (t *ReplayTransport) SendNotification(ctx context.Context, notification mcp.JSONRPCNotification) error {
+	return nil
+}
+
+func (t *ReplayTransport) SetNotificationHandler(handler func(notification mcp.JSONRPCNotification)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.handler = handler
+}
+
+func (t *ReplayTransport) Close() error {
+	return nil
+}
+
+func (t *ReplayTransport) GetSessionId() string {
+	return ""
+}
+
+// ReplayMismatch is a recorded request to which a server responded
+// differently than in the recording.
+type ReplayMismatch struct {
+	Request  json.RawMessage
+	Recorded json.RawMessage
+	Actual   json.RawMessage
+}
+
+// ReplayToServer sends the requests and notifications the client sent in
+// frames to s, in order and within a single session, and returns the
+// requests whose response differs from the recorded one. Responses are
+// compared as JSON values. Responses the client sent to server requests are
+// not replayed.
+func ReplayToServer(ctx context.Context, s *server.MCPServer, frames []Frame) ([]ReplayMismatch, error) {
+	session := server.NewInProcessSession(s.GenerateInProcessSessionID(), nil)
+	if err := s.RegisterSession(ctx, session); err != nil {
+		return nil, err
+	}
+	defer s.UnregisterSession(ctx, session.SessionID())
+	ctx = s.WithContext(ctx, session)
+
+	var mismatches []ReplayMismatch
+	for i, frame := range frames {
+		m := frame.message()
+		if frame.Direction != DirectionSent || m.Method == "" {
+			continue
+		}
+		response := s.HandleMessage(ctx, frame.Message)
+		if m.ID == nil {
+			continue
+		}
+		actual, err := json.Marshal(response)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode response to %s: %w", m.Method, err)
+		}
+		recorded := recordedResponse(frames[i+1:], *m.ID)
+		if recorded == nil || !equalJSON(recorded, actual) {
+			mismatches = append(mismatches, ReplayMismatch{Request: frame.Message, Recorded: recorded, Actual: actual})
+		}
+	}
+	return mismatches, nil
+}
+
+// recordedResponse returns the first response with id received in frames.
+func recordedResponse(frames []Frame, id mcp.RequestID) json.RawMessage {
+	for _, frame := range frames {
+		if m := frame.message(); frame.Direction == DirectionReceived && m.Method == "" && m.ID != nil && m.ID.Equal(id) {
+			return frame.Message
+		}
+	}
+	return nil
+}
+
+func equalJSON(a, b json.RawMessage) bool {
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
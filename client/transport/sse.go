@@ -27,7 +27,7 @@ type SSE struct {
 	baseURL        *url.URL
 	endpoint       *url.URL
 	httpClient     *http.Client
-	responses      map[string]chan *JSONRPCResponse
+	responses      map[mcp.RequestID]chan *JSONRPCResponse
 	mu             sync.RWMutex
 	onNotification func(mcp.JSONRPCNotification)
 	notifyMu       sync.RWMutex
@@ -42,9 +42,20 @@ type SSE struct {
 	protocolVersion  atomic.Value // string
 	onConnectionLost func(error)
 	connectionLostMu sync.RWMutex
+	heartbeatTimeout time.Duration
 
 	// OAuth support
 	oauthHandler *OAuthHandler
+
+	// Backpressure, set by WithSSEEventQueue, WithSSEMaxEventSize and
+	// WithSSEDropHandler.
+	eventQueueSize int
+	overflowPolicy OverflowPolicy
+	maxEventSize   int
+	onDrop         func(event DroppedEvent)
+	queue          *notificationQueue
+	// failed is set when the stream is failed by the OverflowError policy.
+	failed atomic.Bool
 }
 
 type ClientOption func(*SSE)
@@ -74,6 +85,26 @@ func WithHTTPClient(httpClient *http.Client) ClientOption {
 	}
 }
 
+// WithSSETransport customizes the transport of the HTTP client with opts,
+// such as a proxy, certificate authorities or client certificates. It
+// applies to the client of an earlier WithHTTPClient without modifying it.
+func WithSSETransport(opts ...HTTPTransportOption) ClientOption {
+	return func(sc *SSE) {
+		sc.httpClient = withCustomTransport(sc.httpClient, opts)
+	}
+}
+
+// WithHeartbeatTimeout makes the client treat the SSE stream as dead when
+// nothing, not even a keep alive from the server, is received within timeout.
+// The stream is then closed and the connection lost handler is called with
+// ErrHeartbeatTimeout so that it can reconnect. The timeout should be longer
+// than the keep alive interval of the server.
+func WithHeartbeatTimeout(timeout time.Duration) ClientOption {
+	return func(sc *SSE) {
+		sc.heartbeatTimeout = timeout
+	}
+}
+
 func WithOAuth(config OAuthConfig) ClientOption {
 	return func(sc *SSE) {
 		sc.oauthHandler = NewOAuthHandler(config)
@@ -91,7 +122,7 @@ func NewSSE(baseURL string, options ...ClientOption) (*SSE, error) {
 	smc := &SSE{
 		baseURL:      parsedURL,
 		httpClient:   &http.Client{},
-		responses:    make(map[string]chan *JSONRPCResponse),
+		responses:    make(map[mcp.RequestID]chan *JSONRPCResponse),
 		endpointChan: make(chan struct{}),
 		headers:      make(map[string]string),
 		logger:       util.DefaultLogger(),
@@ -164,6 +195,7 @@ func (c *SSE) Start(ctx context.Context) error {
 		resp.Body.Close()
 		// Handle OAuth unauthorized error
 		if resp.StatusCode == http.StatusUnauthorized && c.oauthHandler != nil {
+			c.oauthHandler.handleUnauthorized(resp.Header)
 			return &OAuthAuthorizationRequiredError{
 				Handler: c.oauthHandler,
 			}
@@ -171,7 +203,10 @@ func (c *SSE) Start(ctx context.Context) error {
 		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
-	go c.readSSE(resp.Body)
+	if c.eventQueueSize > 0 {
+		c.queue = newNotificationQueue(c.eventQueueSize, c.dispatchNotification)
+	}
+	go c.readSSE(newLivenessReader(resp.Body, c.heartbeatTimeout))
 
 	// Wait for the endpoint to be received
 	timeout := time.NewTimer(30 * time.Second)
@@ -197,20 +232,31 @@ func (c *SSE) readSSE(reader io.ReadCloser) {
 
 	br := bufio.NewReader(reader)
 	var event, data string
+	// size is the size of the lines of the pending event, and oversized
+	// whether one of them exceeded the maximum event size.
+	size, oversized := 0, false
+	finishEvent := func() {
+		// If no event type is specified, use empty string (default event type)
+		if event == "" {
+			event = "message"
+		}
+		if oversized {
+			c.reportDrop(DroppedEvent{Event: event, Size: size, Err: ErrEventTooLarge})
+		} else {
+			c.handleSSEEvent(event, data)
+		}
+		event, data, size, oversized = "", "", 0, false
+	}
 
-	for {
+	for !c.failed.Load() {
 		// when close or start's ctx cancel, the reader will be closed
 		// and the for loop will break.
-		line, err := br.ReadString('\n')
+		line, lineSize, err := readLine(br, c.maxEventSize)
 		if err != nil {
 			if err == io.EOF {
 				// Process any pending event before exit
-				if data != "" {
-					// If no event type is specified, use empty string (default event type)
-					if event == "" {
-						event = "message"
-					}
-					c.handleSSEEvent(event, data)
+				if data != "" || oversized {
+					finishEvent()
 				}
 				break
 			}
@@ -227,6 +273,18 @@ func (c *SSE) readSSE(reader io.ReadCloser) {
 					return
 				}
 			}
+			// A missed heartbeat means the server is unreachable even though the
+			// connection was not closed, so give the handler a chance to reconnect.
+			if errors.Is(err, ErrHeartbeatTimeout) && !c.closed.Load() {
+				c.connectionLostMu.RLock()
+				handler := c.onConnectionLost
+				c.connectionLostMu.RUnlock()
+
+				if handler != nil {
+					handler(err)
+					return
+				}
+			}
 			if !c.closed.Load() {
 				c.logger.Errorf("SSE stream error: %v", err)
 			}
@@ -235,19 +293,18 @@ func (c *SSE) readSSE(reader io.ReadCloser) {
 
 		// Remove only newline markers
 		line = strings.TrimRight(line, "\r\n")
-		if line == "" {
+		if line == "" && lineSize <= 2 {
 			// Empty line means end of event
-			if data != "" {
-				// If no event type is specified, use empty string (default event type)
-				if event == "" {
-					event = "message"
-				}
-				c.handleSSEEvent(event, data)
-				event = ""
-				data = ""
+			if data != "" || oversized {
+				finishEvent()
 			}
 			continue
 		}
+		size += lineSize
+		if c.maxEventSize > 0 && size > c.maxEventSize {
+			oversized = true
+			continue
+		}
 
 		if strings.HasPrefix(line, "event:") {
 			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
@@ -287,25 +344,18 @@ func (c *SSE) handleSSEEvent(event, data string) {
 			if err := json.Unmarshal([]byte(data), &notification); err != nil {
 				return
 			}
-			c.notifyMu.RLock()
-			if c.onNotification != nil {
-				c.onNotification(notification)
-			}
-			c.notifyMu.RUnlock()
+			c.deliverNotification(notification, len(data))
 			return
 		}
 
-		// Create string key for map lookup
-		idKey := baseMessage.ID.String()
-
 		c.mu.RLock()
-		ch, exists := c.responses[idKey]
+		ch, exists := c.responses[baseMessage.ID]
 		c.mu.RUnlock()
 
 		if exists {
 			ch <- &baseMessage
 			c.mu.Lock()
-			delete(c.responses, idKey)
+			delete(c.responses, baseMessage.ID)
 			c.mu.Unlock()
 		}
 	}
@@ -390,17 +440,14 @@ func (c *SSE) SendRequest(
 		}
 	}
 
-	// Create string key for map lookup
-	idKey := request.ID.String()
-
 	// Register response channel
 	responseChan := make(chan *JSONRPCResponse, 1)
 	c.mu.Lock()
-	c.responses[idKey] = responseChan
+	c.responses[request.ID] = responseChan
 	c.mu.Unlock()
 	deleteResponseChan := func() {
 		c.mu.Lock()
-		delete(c.responses, idKey)
+		delete(c.responses, request.ID)
 		c.mu.Unlock()
 	}
 
@@ -425,6 +472,7 @@ func (c *SSE) SendRequest(
 
 		// Handle OAuth unauthorized error
 		if resp.StatusCode == http.StatusUnauthorized && c.oauthHandler != nil {
+			c.oauthHandler.handleUnauthorized(resp.Header)
 			return nil, &OAuthAuthorizationRequiredError{
 				Handler: c.oauthHandler,
 			}
@@ -457,13 +505,16 @@ func (c *SSE) Close() error {
 		// Also, it could quit start() immediately if not receiving the endpoint
 		c.cancelSSEStream()
 	}
+	if c.queue != nil {
+		c.queue.close()
+	}
 
 	// Clean up any pending responses
 	c.mu.Lock()
 	for _, ch := range c.responses {
 		close(ch)
 	}
-	c.responses = make(map[string]chan *JSONRPCResponse)
+	c.responses = make(map[mcp.RequestID]chan *JSONRPCResponse)
 	c.mu.Unlock()
 
 	return nil
@@ -543,6 +594,7 @@ func (c *SSE) SendNotification(ctx context.Context, notification mcp.JSONRPCNoti
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
 		// Handle OAuth unauthorized error
 		if resp.StatusCode == http.StatusUnauthorized && c.oauthHandler != nil {
+			c.oauthHandler.handleUnauthorized(resp.Header)
 			return &OAuthAuthorizationRequiredError{
 				Handler: c.oauthHandler,
 			}
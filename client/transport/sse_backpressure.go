@@ -0,0 +1,221 @@
+package transport
+
+import (
+	"bufio"
+	"errors"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+var (
+	// ErrEventQueueFull is reported when a notification arrives while the
+	// queue set by WithSSEEventQueue is full.
+	ErrEventQueueFull = errors.New("SSE event queue is full")
+	// ErrEventTooLarge is reported when an SSE event exceeds the size set
+	// by WithSSEMaxEventSize.
+	ErrEventTooLarge = errors.New("SSE event exceeds the maximum size")
+)
+
+// OverflowPolicy decides what the SSE client does with a notification that
+// arrives while its queue is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock stops reading the stream until the queue has room,
+	// pushing back on the server. It loses nothing but delays responses
+	// behind the queued notifications.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest drops the oldest queued notification to make room.
+	OverflowDropOldest
+	// OverflowError drops the notification and fails the stream: it is
+	// closed and the connection lost handler is called with
+	// ErrEventQueueFull, so that the host can reconnect and resynchronize.
+	OverflowError
+)
+
+// DroppedEvent describes an SSE event the client did not deliver.
+type DroppedEvent struct {
+	// Event is the SSE event type, "message" for JSON-RPC messages.
+	Event string
+	// Size is the size in bytes of the event data, or of the whole event if
+	// it was too large.
+	Size int
+	// Notification is the dropped notification, nil if the event was too
+	// large to be decoded.
+	Notification *mcp.JSONRPCNotification
+	// Err is ErrEventQueueFull or ErrEventTooLarge.
+	Err error
+}
+
+// WithSSEEventQueue delivers notifications to the notification handler from
+// a queue of size notifications, on a goroutine of their own, so that a
+// slow handler does not hold up the reading of responses. policy applies
+// when the queue is full. Without this option, or with a size of zero,
+// notifications are handled on the reading goroutine, in order with
+// responses.
+func WithSSEEventQueue(size int, policy OverflowPolicy) ClientOption {
+	return func(sc *SSE) {
+		sc.eventQueueSize = size
+		sc.overflowPolicy = policy
+	}
+}
+
+// WithSSEMaxEventSize drops SSE events larger than size bytes, counting
+// their field names and line endings, without holding them in memory. Dropped responses leave their request
+// waiting until its context is done. There is no limit by default.
+func WithSSEMaxEventSize(size int) ClientOption {
+	return func(sc *SSE) {
+		sc.maxEventSize = size
+	}
+}
+
+// WithSSEDropHandler sets a function called for every event the client
+// drops, because it was too large or the event queue was full. By default
+// drops are logged.
+func WithSSEDropHandler(handler func(event DroppedEvent)) ClientOption {
+	return func(sc *SSE) {
+		sc.onDrop = handler
+	}
+}
+
+// queuedNotification is a notification waiting in a notificationQueue.
+type queuedNotification struct {
+	notification mcp.JSONRPCNotification
+	size         int
+}
+
+// notificationQueue hands notifications from the reading goroutine to a
+// dispatching one.
+type notificationQueue struct {
+	ch        chan queuedNotification
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newNotificationQueue(size int, dispatch func(mcp.JSONRPCNotification)) *notificationQueue {
+	q := &notificationQueue{ch: make(chan queuedNotification, size), done: make(chan struct{})}
+	go func() {
+		for {
+			select {
+			case queued := <-q.ch:
+				dispatch(queued.notification)
+			case <-q.done:
+				return
+			}
+		}
+	}()
+	return q
+}
+
+// push queues a notification according to policy. It returns the
+// notification dropped to make room, if any, and ErrEventQueueFull if the
+// notification itself could not be queued.
+func (q *notificationQueue) push(queued queuedNotification, policy OverflowPolicy) (*queuedNotification, error) {
+	switch policy {
+	case OverflowDropOldest:
+		var dropped *queuedNotification
+		for {
+			select {
+			case q.ch <- queued:
+				return dropped, nil
+			default:
+			}
+			select {
+			case oldest := <-q.ch:
+				dropped = &oldest
+			default:
+			}
+		}
+	case OverflowError:
+		select {
+		case q.ch <- queued:
+			return nil, nil
+		default:
+			return nil, ErrEventQueueFull
+		}
+	default:
+		select {
+		case q.ch <- queued:
+		case <-q.done:
+		}
+		return nil, nil
+	}
+}
+
+func (q *notificationQueue) close() {
+	q.closeOnce.Do(func() { close(q.done) })
+}
+
+// deliverNotification passes notification to the notification handler,
+// directly or through the event queue.
+func (c *SSE) deliverNotification(notification mcp.JSONRPCNotification, size int) {
+	if c.queue == nil {
+		c.dispatchNotification(notification)
+		return
+	}
+	dropped, err := c.queue.push(queuedNotification{notification: notification, size: size}, c.overflowPolicy)
+	if dropped != nil {
+		c.reportDrop(DroppedEvent{Event: "message", Size: dropped.size, Notification: &dropped.notification, Err: ErrEventQueueFull})
+	}
+	if err != nil {
+		c.reportDrop(DroppedEvent{Event: "message", Size: size, Notification: &notification, Err: err})
+		c.failStream(err)
+	}
+}
+
+func (c *SSE) dispatchNotification(notification mcp.JSONRPCNotification) {
+	c.notifyMu.RLock()
+	defer c.notifyMu.RUnlock()
+	if c.onNotification != nil {
+		c.onNotification(notification)
+	}
+}
+
+func (c *SSE) reportDrop(event DroppedEvent) {
+	if c.onDrop != nil {
+		c.onDrop(event)
+		return
+	}
+	c.logger.Errorf("dropped SSE %s event: %v", event.Event, event.Err)
+}
+
+// failStream closes the stream after an error, reporting it to the
+// connection lost handler if one is set.
+func (c *SSE) failStream(err error) {
+	if c.closed.Load() || !c.failed.CompareAndSwap(false, true) {
+		return
+	}
+	if c.cancelSSEStream != nil {
+		c.cancelSSEStream()
+	}
+	c.connectionLostMu.RLock()
+	handler := c.onConnectionLost
+	c.connectionLostMu.RUnlock()
+	if handler != nil {
+		handler(err)
+		return
+	}
+	c.logger.Errorf("SSE stream closed: %v", err)
+}
+
+// readLine reads a line of the stream. Lines longer than limit, if it is
+// positive, are consumed without being held in memory: their first limit
+// bytes are returned along with their full size.
+func readLine(br *bufio.Reader, limit int) (line string, size int, err error) {
+	var buf []byte
+	for {
+		chunk, err := br.ReadSlice('\n')
+		size += len(chunk)
+		if limit <= 0 || len(buf) < limit {
+			room := len(chunk)
+			if limit > 0 {
+				room = min(room, limit-len(buf))
+			}
+			buf = append(buf, chunk[:room]...)
+		}
+		if !errors.Is(err, bufio.ErrBufferFull) {
+			return string(buf), size, err
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// startBurstSSEServer starts an SSE server sending the endpoint event and
+// the first of events, then the others once burst is closed.
+func startBurstSSEServer(t *testing.T, events []string, burst <-chan struct{}) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+		fmt.Fprintf(w, "event: endpoint\ndata: /message\n\n")
+		for i, event := range events {
+			if i == 1 {
+				flusher.Flush()
+				select {
+				case <-burst:
+				case <-r.Context().Done():
+					return
+				}
+			}
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", event)
+		}
+		flusher.Flush()
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func notificationEvent(method string) string {
+	return fmt.Sprintf(`{"jsonrpc":"2.0","method":%q}`, method)
+}
+
+// notificationRecorder records the notifications handed to it. The first
+// one blocks until release is closed.
+type notificationRecorder struct {
+	mu       sync.Mutex
+	methods  []string
+	first    chan struct{}
+	release  chan struct{}
+	received chan struct{}
+}
+
+func newNotificationRecorder() *notificationRecorder {
+	return &notificationRecorder{first: make(chan struct{}), release: make(chan struct{}), received: make(chan struct{}, 100)}
+}
+
+func (r *notificationRecorder) handle(notification mcp.JSONRPCNotification) {
+	r.mu.Lock()
+	r.methods = append(r.methods, notification.Method)
+	first := len(r.methods) == 1
+	r.mu.Unlock()
+	if first {
+		close(r.first)
+		<-r.release
+	}
+	r.received <- struct{}{}
+}
+
+func (r *notificationRecorder) recorded() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.methods...)
+}
+
+func TestSSEEventQueue(t *testing.T) {
+	var events []string
+	for i := 1; i <= 5; i++ {
+		events = append(events, notificationEvent(fmt.Sprint("n", i)))
+	}
+
+	tests := []struct {
+		name      string
+		policy    OverflowPolicy
+		delivered []string
+		dropped   []string
+		lost      bool
+	}{
+		{name: "block", policy: OverflowBlock, delivered: []string{"n1", "n2", "n3", "n4", "n5"}},
+		{name: "drop oldest", policy: OverflowDropOldest, delivered: []string{"n1", "n4", "n5"}, dropped: []string{"n2", "n3"}},
+		{name: "error", policy: OverflowError, delivered: []string{"n1", "n2", "n3"}, dropped: []string{"n4"}, lost: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			burst := make(chan struct{})
+			url := startBurstSSEServer(t, events, burst)
+
+			var mu sync.Mutex
+			var dropped []string
+			trans, err := NewSSE(url, WithSSEEventQueue(2, tt.policy), WithSSEDropHandler(func(event DroppedEvent) {
+				assert.ErrorIs(t, event.Err, ErrEventQueueFull)
+				assert.Positive(t, event.Size)
+				mu.Lock()
+				dropped = append(dropped, event.Notification.Method)
+				mu.Unlock()
+			}))
+			require.NoError(t, err)
+			defer trans.Close()
+			lost := make(chan error, 1)
+			trans.SetConnectionLostHandler(func(err error) { lost <- err })
+			recorder := newNotificationRecorder()
+			trans.SetNotificationHandler(recorder.handle)
+			require.NoError(t, trans.Start(context.Background()))
+
+			<-recorder.first
+			close(burst)
+			if tt.lost {
+				select {
+				case err := <-lost:
+					assert.ErrorIs(t, err, ErrEventQueueFull)
+				case <-time.After(2 * time.Second):
+					t.Fatal("the stream did not fail")
+				}
+			} else if len(tt.dropped) > 0 {
+				require.Eventually(t, func() bool {
+					mu.Lock()
+					defer mu.Unlock()
+					return len(dropped) == len(tt.dropped)
+				}, 2*time.Second, time.Millisecond)
+			} else {
+				// Let the reader block on the full queue.
+				time.Sleep(20 * time.Millisecond)
+			}
+			close(recorder.release)
+
+			for range tt.delivered {
+				select {
+				case <-recorder.received:
+				case <-time.After(2 * time.Second):
+					t.Fatalf("received only %v", recorder.recorded())
+				}
+			}
+			assert.Equal(t, tt.delivered, recorder.recorded())
+			mu.Lock()
+			assert.Equal(t, tt.dropped, dropped)
+			mu.Unlock()
+		})
+	}
+}
+
+func TestSSEMaxEventSize(t *testing.T) {
+	burst := make(chan struct{})
+	close(burst)
+	large := fmt.Sprintf(`{"jsonrpc":"2.0","method":"large","params":{"data":%q}}`, strings.Repeat("x", 10000))
+	url := startBurstSSEServer(t, []string{notificationEvent("small1"), large, notificationEvent("small2")}, burst)
+
+	drops := make(chan DroppedEvent, 1)
+	trans, err := NewSSE(url, WithSSEMaxEventSize(1024), WithSSEDropHandler(func(event DroppedEvent) { drops <- event }))
+	require.NoError(t, err)
+	defer trans.Close()
+	received := make(chan string, 3)
+	trans.SetNotificationHandler(func(notification mcp.JSONRPCNotification) { received <- notification.Method })
+	require.NoError(t, trans.Start(context.Background()))
+
+	for _, expected := range []string{"small1", "small2"} {
+		select {
+		case method := <-received:
+			assert.Equal(t, expected, method)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("no %s notification", expected)
+		}
+	}
+	drop := <-drops
+	assert.ErrorIs(t, drop.Err, ErrEventTooLarge)
+	assert.Equal(t, "message", drop.Event)
+	assert.Greater(t, drop.Size, 10000)
+	assert.Nil(t, drop.Notification)
+}
+
+func TestReadLine(t *testing.T) {
+	long := strings.Repeat("a", 5000)
+	tests := []struct {
+		name     string
+		input    string
+		limit    int
+		line     string
+		size     int
+		lastLine string
+	}{
+		{name: "no limit", input: long + "\nnext\n", line: long + "\n", size: 5001},
+		{name: "under the limit", input: "data: x\nnext\n", limit: 100, line: "data: x\n", size: 8},
+		{name: "over the limit", input: long + "\nnext\n", limit: 10, line: long[:10], size: 5001},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A small buffer makes long lines span several reads.
+			br := bufio.NewReaderSize(strings.NewReader(tt.input), 16)
+			line, size, err := readLine(br, tt.limit)
+			require.NoError(t, err)
+			assert.Equal(t, tt.line, line)
+			assert.Equal(t, tt.size, size)
+
+			line, _, err = readLine(br, tt.limit)
+			require.NoError(t, err)
+			assert.Equal(t, "next\n", line)
+		})
+	}
+}
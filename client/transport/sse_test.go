@@ -751,6 +751,81 @@ func TestSSE(t *testing.T) {
 	})
 }
 
+// startSilentSSEServer starts an SSE server that sends the endpoint event and
+// then nothing but a comment every commentInterval, or nothing at all if
+// commentInterval is zero.
+func startSilentSSEServer(commentInterval time.Duration) (string, func()) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+		fmt.Fprintf(w, "event: endpoint\ndata: /message\n\n")
+		flusher.Flush()
+
+		var tick <-chan time.Time
+		if commentInterval > 0 {
+			ticker := time.NewTicker(commentInterval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+		for {
+			select {
+			case <-tick:
+				fmt.Fprintf(w, ": ping\n\n")
+				flusher.Flush()
+			case <-r.Context().Done():
+				return
+			}
+		}
+	})
+	server := httptest.NewServer(handler)
+	return server.URL, server.Close
+}
+
+func TestSSEHeartbeatTimeout(t *testing.T) {
+	t.Run("MissedHeartbeatTriggersConnectionLost", func(t *testing.T) {
+		url, closeF := startSilentSSEServer(0)
+		defer closeF()
+
+		trans, err := NewSSE(url, WithHeartbeatTimeout(50*time.Millisecond))
+		require.NoError(t, err)
+		defer trans.Close()
+
+		lost := make(chan error, 1)
+		trans.SetConnectionLostHandler(func(err error) {
+			lost <- err
+		})
+		require.NoError(t, trans.Start(context.Background()))
+
+		select {
+		case err := <-lost:
+			require.ErrorIs(t, err, ErrHeartbeatTimeout)
+		case <-time.After(2 * time.Second):
+			t.Fatal("Timeout waiting for the connection lost handler")
+		}
+	})
+
+	t.Run("CommentsKeepConnectionAlive", func(t *testing.T) {
+		url, closeF := startSilentSSEServer(10 * time.Millisecond)
+		defer closeF()
+
+		trans, err := NewSSE(url, WithHeartbeatTimeout(50*time.Millisecond))
+		require.NoError(t, err)
+		defer trans.Close()
+
+		lost := make(chan error, 1)
+		trans.SetConnectionLostHandler(func(err error) {
+			lost <- err
+		})
+		require.NoError(t, trans.Start(context.Background()))
+
+		select {
+		case err := <-lost:
+			t.Fatalf("Connection lost despite heartbeats: %v", err)
+		case <-time.After(200 * time.Millisecond):
+		}
+	})
+}
+
 func TestSSEErrors(t *testing.T) {
 	t.Run("InvalidURL", func(t *testing.T) {
 		// Create a new SSE transport with an invalid URL
@@ -9,8 +9,8 @@ import (
 	"io"
 	"os"
 	"os/exec"
-	"strings"
 	"sync"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/util"
@@ -30,7 +30,7 @@ type Stdio struct {
 	stdin          io.WriteCloser
 	stdout         *bufio.Reader
 	stderr         io.ReadCloser
-	responses      map[string]chan *JSONRPCResponse
+	responses      map[mcp.RequestID]chan *JSONRPCResponse
 	mu             sync.RWMutex
 	done           chan struct{}
 	onNotification func(mcp.JSONRPCNotification)
@@ -42,6 +42,22 @@ type Stdio struct {
 	logger         util.Logger
 	started        bool
 	startedMu      sync.Mutex
+
+	// Framing of the stdout and stderr of the server.
+	maxMessageSize int
+	stderrOutput   io.Writer
+	onNoise        func(line string)
+
+	// procMu guards the subprocess and its pipes, which are replaced when
+	// the process is restarted, along with the supervision state below.
+	procMu      sync.RWMutex
+	proc        *stdioProcess
+	ready       chan struct{}
+	stopped     bool
+	initRequest *JSONRPCRequest
+	restart     *RestartPolicy
+	onProcess   func(ProcessEvent)
+	restarts    int
 }
 
 // StdioOption defines a function that configures a Stdio transport instance.
@@ -78,7 +94,7 @@ func NewIO(input io.Reader, output io.WriteCloser, logging io.ReadCloser) *Stdio
 		stdout: bufio.NewReader(input),
 		stderr: logging,
 
-		responses: make(map[string]chan *JSONRPCResponse),
+		responses: make(map[mcp.RequestID]chan *JSONRPCResponse),
 		done:      make(chan struct{}),
 		ctx:       context.Background(),
 		logger:    util.DefaultLogger(),
@@ -112,7 +128,7 @@ func NewStdioWithOptions(
 		args:    args,
 		env:     env,
 
-		responses: make(map[string]chan *JSONRPCResponse),
+		responses: make(map[mcp.RequestID]chan *JSONRPCResponse),
 		done:      make(chan struct{}),
 		ctx:       context.Background(),
 		logger:    util.DefaultLogger(),
@@ -191,6 +207,8 @@ func (c *Stdio) spawnCommand(ctx context.Context) error {
 		return fmt.Errorf("failed to create stderr pipe: %w", err)
 	}
 
+	c.procMu.Lock()
+	defer c.procMu.Unlock()
 	c.cmd = cmd
 	c.stdin = stdin
 	c.stderr = stderr
@@ -199,6 +217,10 @@ func (c *Stdio) spawnCommand(ctx context.Context) error {
 	if err := cmd.Start(); err != nil {
 		return fmt.Errorf("failed to start command: %w", err)
 	}
+	c.proc = &stdioProcess{exited: make(chan struct{}), startedAt: time.Now()}
+	if c.stderrOutput != nil {
+		go func() { _, _ = io.Copy(c.stderrOutput, stderr) }()
+	}
 
 	return nil
 }
@@ -214,19 +236,23 @@ func (c *Stdio) Close() error {
 	// cancel all in-flight request
 	close(c.done)
 
-	if c.stdin != nil {
-		if err := c.stdin.Close(); err != nil {
+	c.procMu.RLock()
+	stdin, stderr, cmd := c.stdin, c.stderr, c.cmd
+	c.procMu.RUnlock()
+
+	if stdin != nil {
+		if err := stdin.Close(); err != nil {
 			return fmt.Errorf("failed to close stdin: %w", err)
 		}
 	}
-	if c.stderr != nil {
-		if err := c.stderr.Close(); err != nil {
+	if stderr != nil {
+		if err := stderr.Close(); err != nil {
 			return fmt.Errorf("failed to close stderr: %w", err)
 		}
 	}
 
-	if c.cmd != nil {
-		return c.cmd.Wait()
+	if cmd != nil {
+		return c.waitProcess()
 	}
 
 	return nil
@@ -259,35 +285,52 @@ func (c *Stdio) SetRequestHandler(handler RequestHandler) {
 // readResponses continuously reads and processes responses from the server's stdout.
 // It handles both responses to requests and notifications, routing them appropriately.
 // Runs until the done channel is closed or an error occurs reading from stdout.
+// When the subprocess exits and a restart policy is configured, reading
+// continues from the restarted process.
 func (c *Stdio) readResponses() {
 	for {
 		select {
 		case <-c.done:
 			return
 		default:
-			line, err := c.stdout.ReadString('\n')
+			c.procMu.RLock()
+			stdout := c.stdout
+			c.procMu.RUnlock()
+
+			line, size, err := readMessageLine(stdout, c.maxMessageSize)
+			if errors.Is(err, errLineTooLong) {
+				c.logger.Errorf("Discarded message of %d bytes from stdout, over the limit of %d bytes", size, c.maxMessageSize)
+				continue
+			}
 			if err != nil {
 				if err != io.EOF && !errors.Is(err, context.Canceled) {
 					c.logger.Errorf("Error reading from stdout: %v", err)
 				}
+				if c.handleProcessExit() {
+					continue
+				}
 				return
 			}
+			if len(line) == 0 {
+				continue
+			}
 
-			line = strings.TrimRight(line, "\r\n")
 			// First try to parse as a generic message to check for ID field
 			var baseMessage struct {
 				JSONRPC string         `json:"jsonrpc"`
-				ID      *mcp.RequestId `json:"id,omitempty"`
+				ID      *mcp.RequestID `json:"id,omitempty"`
 				Method  string         `json:"method,omitempty"`
 			}
-			if err := json.Unmarshal([]byte(line), &baseMessage); err != nil {
+			if err := json.Unmarshal(line, &baseMessage); err != nil ||
+				!isJSONRPCMessage(baseMessage.JSONRPC, baseMessage.Method, baseMessage.ID != nil) {
+				c.handleNoise(line)
 				continue
 			}
 
 			// If it has a method but no ID, it's a notification
 			if baseMessage.Method != "" && baseMessage.ID == nil {
 				var notification mcp.JSONRPCNotification
-				if err := json.Unmarshal([]byte(line), &notification); err != nil {
+				if err := json.Unmarshal(line, &notification); err != nil {
 					continue
 				}
 				c.notifyMu.RLock()
@@ -301,7 +344,7 @@ func (c *Stdio) readResponses() {
 			// If it has a method and an ID, it's an incoming request
 			if baseMessage.Method != "" && baseMessage.ID != nil {
 				var request JSONRPCRequest
-				if err := json.Unmarshal([]byte(line), &request); err == nil {
+				if err := json.Unmarshal(line, &request); err == nil {
 					c.handleIncomingRequest(request)
 					continue
 				}
@@ -309,31 +352,38 @@ func (c *Stdio) readResponses() {
 
 			// Otherwise, it's a response to our request
 			var response JSONRPCResponse
-			if err := json.Unmarshal([]byte(line), &response); err != nil {
+			if err := json.Unmarshal(line, &response); err != nil {
 				continue
 			}
 
-			// Create string key for map lookup
-			idKey := response.ID.String()
-
 			c.mu.RLock()
-			ch, exists := c.responses[idKey]
+			ch, exists := c.responses[response.ID]
 			c.mu.RUnlock()
 
 			if exists {
 				ch <- &response
 				c.mu.Lock()
-				delete(c.responses, idKey)
+				delete(c.responses, response.ID)
 				c.mu.Unlock()
 			}
 		}
 	}
 }
 
+// handleNoise reports a line of stdout that is not a JSON-RPC message.
+func (c *Stdio) handleNoise(line []byte) {
+	if c.onNoise != nil {
+		c.onNoise(string(line))
+	}
+}
+
 // SendRequest sends a JSON-RPC request to the server and waits for a response.
 // It creates a unique request ID, sends the request over stdin, and waits for
 // the corresponding response or context cancellation.
 // Returns the raw JSON response message or an error if the request fails.
+// While the subprocess is being restarted, SendRequest waits until it has
+// been initialized again; requests in flight when it exits fail with
+// ErrProcessExited.
 func (c *Stdio) SendRequest(
 	ctx context.Context,
 	request JSONRPCRequest,
@@ -345,9 +395,38 @@ func (c *Stdio) SendRequest(
 	default:
 	}
 
-	if c.stdin == nil {
+	if err := c.awaitReady(ctx); err != nil {
+		return nil, err
+	}
+	if request.Method == string(mcp.MethodInitialize) {
+		c.procMu.Lock()
+		c.initRequest = &request
+		c.procMu.Unlock()
+	}
+	return c.sendRequest(ctx, request)
+}
+
+// sendRequest writes the request to the current subprocess and waits for
+// its response without waiting for a restart to complete.
+func (c *Stdio) sendRequest(
+	ctx context.Context,
+	request JSONRPCRequest,
+) (*JSONRPCResponse, error) {
+	c.procMu.RLock()
+	stdin, proc, stopped := c.stdin, c.proc, c.stopped
+	c.procMu.RUnlock()
+
+	if stdin == nil {
 		return nil, fmt.Errorf("stdio client not started")
 	}
+	if stopped {
+		return nil, ErrProcessExited
+	}
+	// exited stays nil, and so never fires, for transports created by NewIO.
+	var exited chan struct{}
+	if proc != nil {
+		exited = proc.exited
+	}
 
 	// Marshal request
 	requestBytes, err := json.Marshal(request)
@@ -356,22 +435,19 @@ func (c *Stdio) SendRequest(
 	}
 	requestBytes = append(requestBytes, '\n')
 
-	// Create string key for map lookup
-	idKey := request.ID.String()
-
 	// Register response channel
 	responseChan := make(chan *JSONRPCResponse, 1)
 	c.mu.Lock()
-	c.responses[idKey] = responseChan
+	c.responses[request.ID] = responseChan
 	c.mu.Unlock()
 	deleteResponseChan := func() {
 		c.mu.Lock()
-		delete(c.responses, idKey)
+		delete(c.responses, request.ID)
 		c.mu.Unlock()
 	}
 
 	// Send request
-	if _, err := c.stdin.Write(requestBytes); err != nil {
+	if _, err := stdin.Write(requestBytes); err != nil {
 		deleteResponseChan()
 		return nil, fmt.Errorf("failed to write request: %w", err)
 	}
@@ -382,6 +458,15 @@ func (c *Stdio) SendRequest(
 		return nil, ctx.Err()
 	case response := <-responseChan:
 		return response, nil
+	case <-exited:
+		deleteResponseChan()
+		// The response may have been read just before the process exited.
+		select {
+		case response := <-responseChan:
+			return response, nil
+		default:
+		}
+		return nil, ErrProcessExited
 	}
 }
 
@@ -390,9 +475,24 @@ func (c *Stdio) SendNotification(
 	ctx context.Context,
 	notification mcp.JSONRPCNotification,
 ) error {
-	if c.stdin == nil {
+	if err := c.awaitReady(ctx); err != nil {
+		return err
+	}
+	return c.sendNotification(notification)
+}
+
+// sendNotification writes the notification to the current subprocess.
+func (c *Stdio) sendNotification(notification mcp.JSONRPCNotification) error {
+	c.procMu.RLock()
+	stdin, stopped := c.stdin, c.stopped
+	c.procMu.RUnlock()
+
+	if stdin == nil {
 		return fmt.Errorf("stdio client not started")
 	}
+	if stopped {
+		return ErrProcessExited
+	}
 
 	notificationBytes, err := json.Marshal(notification)
 	if err != nil {
@@ -400,7 +500,7 @@ func (c *Stdio) SendNotification(
 	}
 	notificationBytes = append(notificationBytes, '\n')
 
-	if _, err := c.stdin.Write(notificationBytes); err != nil {
+	if _, err := stdin.Write(notificationBytes); err != nil {
 		return fmt.Errorf("failed to write notification: %w", err)
 	}
 
@@ -463,13 +563,20 @@ func (c *Stdio) sendResponse(response JSONRPCResponse) {
 	}
 	responseBytes = append(responseBytes, '\n')
 
-	if _, err := c.stdin.Write(responseBytes); err != nil {
+	c.procMu.RLock()
+	stdin := c.stdin
+	c.procMu.RUnlock()
+
+	if _, err := stdin.Write(responseBytes); err != nil {
 		c.logger.Errorf("Error writing response: %v", err)
 	}
 }
 
 // Stderr returns a reader for the stderr output of the subprocess.
 // This can be used to capture error messages or logs from the subprocess.
+// After a restart, the reader of the new subprocess is returned.
 func (c *Stdio) Stderr() io.Reader {
+	c.procMu.RLock()
+	defer c.procMu.RUnlock()
 	return c.stderr
 }
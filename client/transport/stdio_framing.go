@@ -0,0 +1,72 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+)
+
+// utf8BOM is the byte order mark some servers print before their first
+// message.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// WithStdioMaxMessageSize limits the size of the lines read from the stdout
+// of the server. Longer lines are discarded as they are read, without
+// buffering them, and logged as errors. Zero, the default, means no limit.
+func WithStdioMaxMessageSize(size int) StdioOption {
+	return func(s *Stdio) {
+		s.maxMessageSize = size
+	}
+}
+
+// WithStdioStderr copies the stderr output of the server to w as it is
+// written, for example to os.Stderr, including after restarts. Stderr
+// should not be read when it is set.
+func WithStdioStderr(w io.Writer) StdioOption {
+	return func(s *Stdio) {
+		s.stderrOutput = w
+	}
+}
+
+// WithStdioNoiseHandler sets a handler called with each line of the stdout
+// of the server that is not a JSON-RPC message, such as a banner printed at
+// startup, before it is skipped. Without a handler, such lines are skipped
+// silently.
+func WithStdioNoiseHandler(handler func(line string)) StdioOption {
+	return func(s *Stdio) {
+		s.onNoise = handler
+	}
+}
+
+// errLineTooLong is returned by readMessageLine for lines over the size
+// limit.
+var errLineTooLong = errors.New("line too long")
+
+// readMessageLine reads a line of the stdout of the server, without its line
+// ending, carriage return included, and surrounding whitespace. A last line
+// without line ending is returned too. If maxSize is positive and the line
+// is longer, it is consumed without being held in memory and
+// errLineTooLong returned along with its size.
+func readMessageLine(r *bufio.Reader, maxSize int) ([]byte, int, error) {
+	limit := 0
+	if maxSize > 0 {
+		limit = maxSize + len("\r\n")
+	}
+	raw, size, err := readLine(r, limit)
+	if err != nil && (err != io.EOF || size == 0) {
+		return nil, size, err
+	}
+	line := bytes.TrimRight([]byte(raw), "\r\n")
+	if maxSize > 0 && (size > len(raw) || len(line) > maxSize) {
+		return nil, size, errLineTooLong
+	}
+	line = bytes.TrimPrefix(line, utf8BOM)
+	return bytes.TrimSpace(line), size, nil
+}
+
+// isJSONRPCMessage reports whether a parsed line looks like a JSON-RPC
+// message rather than other JSON printed by the server, such as a log line.
+func isJSONRPCMessage(jsonrpc, method string, hasID bool) bool {
+	return jsonrpc != "" || method != "" || hasID
+}
@@ -0,0 +1,154 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestReadMessageLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		maxSize int
+		want    []string
+		wantErr []error
+	}{
+		{name: "LF", input: "a\nb\n", want: []string{"a", "b"}, wantErr: []error{nil, nil}},
+		{name: "CRLF", input: "a\r\nb\r\n", want: []string{"a", "b"}, wantErr: []error{nil, nil}},
+		{name: "byte order mark and whitespace", input: "\xEF\xBB\xBF  {}  \n", want: []string{"{}"}, wantErr: []error{nil}},
+		{name: "last line without line ending", input: "a\nb", want: []string{"a", "b"}, wantErr: []error{nil, nil}},
+		{name: "end of input", input: "", want: []string{""}, wantErr: []error{io.EOF}},
+		{name: "within limit", input: "abcd\r\n", maxSize: 4, want: []string{"abcd"}, wantErr: []error{nil}},
+		{name: "over limit", input: "abcde\nabc\n", maxSize: 4, want: []string{"", "abc"}, wantErr: []error{errLineTooLong, nil}},
+		{name: "larger than the buffer", input: strings.Repeat("x", 100000) + "\n", want: []string{strings.Repeat("x", 100000)}, wantErr: []error{nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := bufio.NewReaderSize(strings.NewReader(tt.input), 16)
+			for i, want := range tt.want {
+				line, _, err := readMessageLine(r, tt.maxSize)
+				assert.ErrorIs(t, err, tt.wantErr[i])
+				assert.Equal(t, want, string(line))
+			}
+		})
+	}
+}
+
+func TestStdio_SkipsNoise(t *testing.T) {
+	stdout, serverOut := io.Pipe()
+	trans := NewIO(stdout, nopWriteCloser{io.Discard}, io.NopCloser(strings.NewReader("")))
+	var mu sync.Mutex
+	var noise []string
+	WithStdioNoiseHandler(func(line string) {
+		mu.Lock()
+		defer mu.Unlock()
+		noise = append(noise, line)
+	})(trans)
+	WithStdioMaxMessageSize(128)(trans)
+
+	notifications := make(chan mcp.JSONRPCNotification, 10)
+	trans.SetNotificationHandler(func(notification mcp.JSONRPCNotification) {
+		notifications <- notification
+	})
+	require.NoError(t, trans.Start(context.Background()))
+	defer trans.Close()
+
+	go func() {
+		_, _ = io.WriteString(serverOut, strings.Join([]string{
+			"Weather server v1.2 starting...",
+			`{"level":"info","msg":"listening"}`,
+			"",
+			`{"jsonrpc":"2.0","method":"notifications/message","params":{"data":"` + strings.Repeat("x", 200) + `"}}`,
+			`{"jsonrpc":"2.0","method":"notifications/ready"}`,
+		}, "\r\n")+"\r\n")
+	}()
+
+	select {
+	case notification := <-notifications:
+		assert.Equal(t, "notifications/ready", notification.Method, "the oversized notification is dropped")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the notification")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"Weather server v1.2 starting...", `{"level":"info","msg":"listening"}`}, noise)
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestStdio_BannerAndStderr(t *testing.T) {
+	tempFile, err := os.CreateTemp("", "mockstdio_server")
+	require.NoError(t, err)
+	tempFile.Close()
+	mockServerPath := tempFile.Name()
+	if runtime.GOOS == "windows" {
+		os.Remove(mockServerPath)
+		mockServerPath += ".exe"
+	}
+	require.NoError(t, compileTestServer(mockServerPath))
+	defer os.Remove(mockServerPath)
+
+	stderr := &syncBuffer{}
+	var banner []string
+	var mu sync.Mutex
+	stdio := NewStdioWithOptions(mockServerPath, []string{"MOCK_STDIO_BANNER=Mock server 1.0 ready"}, nil,
+		WithStdioStderr(stderr),
+		WithStdioNoiseHandler(func(line string) {
+			mu.Lock()
+			defer mu.Unlock()
+			banner = append(banner, line)
+		}),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, stdio.Start(ctx))
+	defer stdio.Close()
+
+	response, err := stdio.SendRequest(ctx, JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestId(int64(1)),
+		Method:  "ping",
+	})
+	require.NoError(t, err)
+	assert.Nil(t, response.Error)
+
+	mu.Lock()
+	assert.Equal(t, []string{"Mock server 1.0 ready"}, banner)
+	mu.Unlock()
+	assert.Eventually(t, func() bool {
+		return strings.Contains(stderr.String(), "launch successful")
+	}, 2*time.Second, 10*time.Millisecond)
+}
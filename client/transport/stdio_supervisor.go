@@ -0,0 +1,293 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ErrProcessExited is returned for requests that were in flight when the
+// stdio subprocess exited, and for requests sent after it exited for good.
+var ErrProcessExited = errors.New("stdio subprocess exited")
+
+// reinitializeTimeout bounds how long a restarted subprocess may take to
+// answer the replayed initialize request.
+const reinitializeTimeout = 30 * time.Second
+
+// ProcessEventType identifies a stage in the lifecycle of a stdio subprocess.
+type ProcessEventType string
+
+const (
+	// ProcessExited reports that the subprocess exited unexpectedly. Err
+	// holds the exit error, if any.
+	ProcessExited ProcessEventType = "exited"
+	// ProcessRestarting reports that a restart is scheduled after Delay.
+	ProcessRestarting ProcessEventType = "restarting"
+	// ProcessRestartFailed reports that the subprocess could not be started
+	// again. Err holds the cause; another attempt follows if the policy
+	// allows it.
+	ProcessRestartFailed ProcessEventType = "restart_failed"
+	// ProcessRestarted reports that a new subprocess is running.
+	ProcessRestarted ProcessEventType = "restarted"
+	// ProcessReinitialized reports that the initialize handshake was replayed
+	// to the restarted subprocess. Err is set if the handshake failed.
+	ProcessReinitialized ProcessEventType = "reinitialized"
+	// ProcessGaveUp reports that the restart policy is exhausted. Requests
+	// fail with ErrProcessExited from then on.
+	ProcessGaveUp ProcessEventType = "gave_up"
+)
+
+// ProcessEvent describes a lifecycle change of the stdio subprocess.
+type ProcessEvent struct {
+	Type ProcessEventType
+	// PID is the process ID of the subprocess the event refers to.
+	PID int
+	// Attempt is the number of the current restart attempt, starting at 1.
+	Attempt int
+	// Delay is the backoff before the restart, set for ProcessRestarting.
+	Delay time.Duration
+	// Err is the exit, start, or handshake error, if any.
+	Err error
+}
+
+// RestartPolicy controls how the stdio transport restarts a subprocess that
+// exits unexpectedly. Restarts are delayed with exponential backoff, doubling
+// from InitialBackoff up to MaxBackoff.
+type RestartPolicy struct {
+	// MaxRestarts is the number of consecutive restarts attempted before the
+	// transport gives up. Zero means restarts are attempted forever.
+	MaxRestarts int
+	// InitialBackoff is the delay before the first restart. Defaults to
+	// 500ms.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between restarts. Defaults to 30s.
+	MaxBackoff time.Duration
+	// ResetAfter is how long a subprocess must run before its exit is no
+	// longer counted as consecutive with earlier restarts. Defaults to one
+	// minute.
+	ResetAfter time.Duration
+}
+
+// backoff returns the delay before the given restart attempt.
+func (p RestartPolicy) backoff(attempt int) time.Duration {
+	delay := p.InitialBackoff
+	if delay <= 0 {
+		delay = 500 * time.Millisecond
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = 30 * time.Second
+	}
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
+
+func (p RestartPolicy) resetAfter() time.Duration {
+	if p.ResetAfter <= 0 {
+		return time.Minute
+	}
+	return p.ResetAfter
+}
+
+// WithRestartPolicy makes the stdio transport supervise its subprocess and
+// restart it when it exits unexpectedly. After a restart the transport
+// replays the last initialize request and the initialized notification, so
+// the client keeps working without initializing again. State the server kept
+// for the old process, such as resource subscriptions or the log level, is
+// not restored.
+//
+// Requests sent while a restart is in progress wait for it to complete.
+func WithRestartPolicy(policy RestartPolicy) StdioOption {
+	return func(s *Stdio) {
+		s.restart = &policy
+	}
+}
+
+// WithProcessEventHandler sets a function called on lifecycle changes of the
+// subprocess, such as an unexpected exit or a restart. The handler is called
+// synchronously from the goroutine supervising the process and should not
+// block.
+func WithProcessEventHandler(handler func(ProcessEvent)) StdioOption {
+	return func(s *Stdio) {
+		s.onProcess = handler
+	}
+}
+
+// stdioProcess tracks a running subprocess.
+type stdioProcess struct {
+	// exited is closed once the process has exited.
+	exited    chan struct{}
+	startedAt time.Time
+	waitOnce  sync.Once
+	waitErr   error
+}
+
+// waitProcess waits for the current subprocess to exit and releases its
+// resources. It may be called more than once.
+func (c *Stdio) waitProcess() error {
+	c.procMu.RLock()
+	cmd, proc := c.cmd, c.proc
+	c.procMu.RUnlock()
+	if proc == nil {
+		return nil
+	}
+	proc.waitOnce.Do(func() {
+		proc.waitErr = cmd.Wait()
+	})
+	return proc.waitErr
+}
+
+// awaitReady blocks while the subprocess is being restarted.
+func (c *Stdio) awaitReady(ctx context.Context) error {
+	c.procMu.RLock()
+	ready := c.ready
+	c.procMu.RUnlock()
+	if ready == nil {
+		return nil
+	}
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.done:
+		return ErrProcessExited
+	}
+}
+
+func (c *Stdio) emitProcessEvent(event ProcessEvent) {
+	if c.onProcess != nil {
+		c.onProcess(event)
+	}
+}
+
+// handleProcessExit is called by readResponses once stdout of the
+// subprocess is closed. It fails the requests in flight and, if a restart
+// policy is configured, starts a new subprocess. It reports whether reading
+// should continue from a new process.
+func (c *Stdio) handleProcessExit() bool {
+	c.procMu.RLock()
+	cmd, proc := c.cmd, c.proc
+	c.procMu.RUnlock()
+	if proc == nil {
+		return false
+	}
+	select {
+	case <-c.done:
+		// Closed by the client, not a crash.
+		return false
+	default:
+	}
+
+	err := c.waitProcess()
+	pid := cmd.Process.Pid
+
+	// Gate new requests before failing the ones in flight, so callers that
+	// retry immediately wait for the restart.
+	var ready chan struct{}
+	c.procMu.Lock()
+	if c.restart != nil {
+		ready = make(chan struct{})
+		c.ready = ready
+	} else {
+		c.stopped = true
+	}
+	c.procMu.Unlock()
+	close(proc.exited)
+	c.emitProcessEvent(ProcessEvent{Type: ProcessExited, PID: pid, Err: err})
+
+	if c.restart == nil {
+		return false
+	}
+	if time.Since(proc.startedAt) >= c.restart.resetAfter() {
+		c.restarts = 0
+	}
+
+	c.ctxMu.RLock()
+	ctx := c.ctx
+	c.ctxMu.RUnlock()
+
+	for {
+		if c.restart.MaxRestarts > 0 && c.restarts >= c.restart.MaxRestarts {
+			c.procMu.Lock()
+			c.stopped = true
+			c.procMu.Unlock()
+			close(ready)
+			c.emitProcessEvent(ProcessEvent{Type: ProcessGaveUp, PID: pid, Attempt: c.restarts})
+			return false
+		}
+		c.restarts++
+		attempt := c.restarts
+
+		delay := c.restart.backoff(attempt)
+		c.emitProcessEvent(ProcessEvent{Type: ProcessRestarting, PID: pid, Attempt: attempt, Delay: delay})
+		timer := time.NewTimer(delay)
+		select {
+		case <-c.done:
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+
+		if err := c.spawnCommand(ctx); err != nil {
+			c.emitProcessEvent(ProcessEvent{Type: ProcessRestartFailed, PID: pid, Attempt: attempt, Err: err})
+			continue
+		}
+
+		c.procMu.RLock()
+		pid = c.cmd.Process.Pid
+		c.procMu.RUnlock()
+		select {
+		case <-c.done:
+			// Close raced with the restart and may have missed the new
+			// process.
+			c.procMu.RLock()
+			_ = c.cmd.Process.Kill()
+			c.procMu.RUnlock()
+			_ = c.waitProcess()
+			return false
+		default:
+		}
+
+		c.emitProcessEvent(ProcessEvent{Type: ProcessRestarted, PID: pid, Attempt: attempt})
+		go c.reinitialize(ctx, ready, pid, attempt)
+		return true
+	}
+}
+
+// reinitialize replays the initialize handshake to a restarted subprocess
+// and then releases the requests waiting on ready.
+func (c *Stdio) reinitialize(ctx context.Context, ready chan struct{}, pid, attempt int) {
+	defer close(ready)
+
+	c.procMu.RLock()
+	initRequest := c.initRequest
+	c.procMu.RUnlock()
+	if initRequest == nil {
+		// The client had not initialized the old process either.
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reinitializeTimeout)
+	defer cancel()
+
+	request := *initRequest
+	request.ID = mcp.NewRequestID(fmt.Sprintf("mcp-go-reinitialize-%d", attempt))
+	response, err := c.sendRequest(ctx, request)
+	if err == nil && response.Error != nil {
+		err = response.Error.AsError()
+	}
+	if err == nil {
+		err = c.sendNotification(mcp.NewInitializedNotification().ToJSONRPCNotification())
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to reinitialize: %w", err)
+	}
+	c.emitProcessEvent(ProcessEvent{Type: ProcessReinitialized, PID: pid, Attempt: attempt, Err: err})
+}
@@ -0,0 +1,148 @@
+package transport
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// processEvents records the lifecycle events of a stdio transport.
+type processEvents struct {
+	mu     sync.Mutex
+	events []ProcessEvent
+}
+
+func (p *processEvents) handle(event ProcessEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+}
+
+func (p *processEvents) types() []ProcessEventType {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	types := make([]ProcessEventType, 0, len(p.events))
+	for _, event := range p.events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func (p *processEvents) last() ProcessEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.events[len(p.events)-1]
+}
+
+func request(id int64, method string) JSONRPCRequest {
+	return JSONRPCRequest{JSONRPC: mcp.JSONRPC_VERSION, ID: mcp.NewRequestId(id), Method: method}
+}
+
+func TestStdio_Supervision(t *testing.T) {
+	tempFile, err := os.CreateTemp("", "mockstdio_server")
+	require.NoError(t, err)
+	tempFile.Close()
+	mockServerPath := tempFile.Name()
+	if runtime.GOOS == "windows" {
+		os.Remove(mockServerPath)
+		mockServerPath += ".exe"
+	}
+	require.NoError(t, compileTestServer(mockServerPath))
+	defer os.Remove(mockServerPath)
+
+	start := func(t *testing.T, opts ...StdioOption) (*Stdio, *processEvents) {
+		t.Helper()
+		events := &processEvents{}
+		opts = append(opts, WithProcessEventHandler(events.handle))
+		stdio := NewStdioWithOptions(mockServerPath, nil, nil, opts...)
+		require.NoError(t, stdio.Start(context.Background()))
+		t.Cleanup(func() { _ = stdio.Close() })
+		return stdio, events
+	}
+
+	t.Run("restarts and reinitializes the subprocess", func(t *testing.T) {
+		stdio, events := start(t, WithRestartPolicy(RestartPolicy{InitialBackoff: 10 * time.Millisecond}))
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		response, err := stdio.SendRequest(ctx, request(1, "initialize"))
+		require.NoError(t, err)
+		require.Nil(t, response.Error)
+		firstPID := stdio.cmd.Process.Pid
+
+		_, err = stdio.SendRequest(ctx, request(2, "debug/exit"))
+		assert.ErrorIs(t, err, ErrProcessExited)
+
+		// The next request waits for the restart to complete.
+		response, err = stdio.SendRequest(ctx, request(3, "ping"))
+		require.NoError(t, err)
+		assert.Nil(t, response.Error)
+
+		// The handshake completes before waiting requests are released.
+		assert.Equal(t, []ProcessEventType{ProcessExited, ProcessRestarting, ProcessRestarted, ProcessReinitialized}, events.types())
+		reinitialized := events.last()
+		assert.NoError(t, reinitialized.Err)
+		assert.Equal(t, 1, reinitialized.Attempt)
+		assert.NotEqual(t, firstPID, reinitialized.PID)
+	})
+
+	t.Run("without a restart policy the transport stops", func(t *testing.T) {
+		stdio, events := start(t)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		_, err := stdio.SendRequest(ctx, request(1, "debug/exit"))
+		assert.ErrorIs(t, err, ErrProcessExited)
+		require.Eventually(t, func() bool {
+			return len(events.types()) == 1
+		}, time.Second, 10*time.Millisecond)
+		assert.Equal(t, ProcessExited, events.last().Type)
+		assert.Error(t, events.last().Err, "the exit status is reported")
+
+		_, err = stdio.SendRequest(ctx, request(2, "ping"))
+		assert.ErrorIs(t, err, ErrProcessExited)
+	})
+
+	t.Run("gives up once the policy is exhausted", func(t *testing.T) {
+		stdio, events := start(t, WithRestartPolicy(RestartPolicy{MaxRestarts: 1, InitialBackoff: 10 * time.Millisecond}))
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		_, err := stdio.SendRequest(ctx, request(1, "debug/exit"))
+		assert.ErrorIs(t, err, ErrProcessExited)
+		_, err = stdio.SendRequest(ctx, request(2, "debug/exit"))
+		assert.ErrorIs(t, err, ErrProcessExited)
+
+		require.Eventually(t, func() bool {
+			return events.last().Type == ProcessGaveUp
+		}, time.Second, 10*time.Millisecond)
+		_, err = stdio.SendRequest(ctx, request(3, "ping"))
+		assert.ErrorIs(t, err, ErrProcessExited)
+	})
+}
+
+func TestRestartPolicy_Backoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   RestartPolicy
+		attempt  int
+		expected time.Duration
+	}{
+		{name: "defaults", attempt: 1, expected: 500 * time.Millisecond},
+		{name: "doubles", policy: RestartPolicy{InitialBackoff: time.Second}, attempt: 3, expected: 4 * time.Second},
+		{name: "capped", policy: RestartPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}, attempt: 10, expected: 5 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.policy.backoff(tt.attempt))
+		})
+	}
+}
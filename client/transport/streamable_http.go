@@ -54,6 +54,16 @@ func WithHTTPHeaderFunc(headerFunc HTTPHeaderFunc) StreamableHTTPCOption {
 	}
 }
 
+// WithHTTPTransport customizes the transport of the HTTP client with opts,
+// such as a proxy, certificate authorities or client certificates. It
+// applies to the client of an earlier WithHTTPBasicClient without modifying
+// it.
+func WithHTTPTransport(opts ...HTTPTransportOption) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		sc.httpClient = withCustomTransport(sc.httpClient, opts)
+	}
+}
+
 // WithHTTPTimeout sets the timeout for a HTTP request and stream.
 func WithHTTPTimeout(timeout time.Duration) StreamableHTTPCOption {
 	return func(sc *StreamableHTTP) {
@@ -61,6 +71,17 @@ func WithHTTPTimeout(timeout time.Duration) StreamableHTTPCOption {
 	}
 }
 
+// WithHTTPHeartbeatTimeout makes the client treat the continuous listening
+// connection as dead when nothing, not even a heartbeat from the server, is
+// received on it within timeout. The connection is then closed and a new one
+// is established. The timeout should be longer than the heartbeat interval of
+// the server. It has no effect without WithContinuousListening.
+func WithHTTPHeartbeatTimeout(timeout time.Duration) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		sc.heartbeatTimeout = timeout
+	}
+}
+
 // WithHTTPOAuth enables OAuth authentication for the client.
 func WithHTTPOAuth(config OAuthConfig) StreamableHTTPCOption {
 	return func(sc *StreamableHTTP) {
@@ -105,6 +126,12 @@ type StreamableHTTP struct {
 	headerFunc          HTTPHeaderFunc
 	logger              util.Logger
 	getListeningEnabled bool
+	heartbeatTimeout    time.Duration
+
+	// Compression, set by WithHTTPCompression
+	compressors      []util.Compressor
+	compressMinSize  int
+	compressRequests atomic.Bool
 
 	sessionID       atomic.Value // string
 	protocolVersion atomic.Value // string
@@ -289,6 +316,7 @@ func (c *StreamableHTTP) SendRequest(
 
 		// Handle OAuth unauthorized error
 		if resp.StatusCode == http.StatusUnauthorized && c.oauthHandler != nil {
+			c.oauthHandler.handleUnauthorized(resp.Header)
 			return nil, &OAuthAuthorizationRequiredError{
 				Handler: c.oauthHandler,
 			}
@@ -300,7 +328,7 @@ func (c *StreamableHTTP) SendRequest(
 		if err := json.Unmarshal(body, &errResponse); err == nil {
 			return &errResponse, nil
 		}
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, body)
+		return nil, &HTTPStatusError{StatusCode: resp.StatusCode, Body: body}
 	}
 
 	if request.Method == string(mcp.MethodInitialize) {
@@ -348,6 +376,20 @@ func (c *StreamableHTTP) sendHTTP(
 	acceptType string,
 	header http.Header,
 ) (resp *http.Response, err error) {
+	// Compress the body if it is large enough
+	var rawBody []byte
+	contentEncoding := ""
+	if body != nil && len(c.compressors) > 0 {
+		if rawBody, err = io.ReadAll(body); err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		compressed, encoding, err := c.compressRequestBody(rawBody)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compress request body: %w", err)
+		}
+		body, contentEncoding = bytes.NewReader(compressed), encoding
+	}
+
 	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, method, c.serverURL.String(), body)
 	if err != nil {
@@ -362,6 +404,14 @@ func (c *StreamableHTTP) sendHTTP(
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", acceptType)
+	if len(c.compressors) > 0 {
+		req.Header.Set("Accept-Encoding", util.AcceptEncoding(c.compressors))
+		if contentEncoding != "" {
+			req.Header.Set("Content-Encoding", contentEncoding)
+		} else {
+			req.Header.Del("Content-Encoding")
+		}
+	}
 	sessionID := c.sessionID.Load().(string)
 	if sessionID != "" {
 		req.Header.Set(HeaderKeySessionID, sessionID)
@@ -403,12 +453,24 @@ func (c *StreamableHTTP) sendHTTP(
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 
+	// the server does not accept compressed requests
+	if resp.StatusCode == http.StatusUnsupportedMediaType && contentEncoding != "" {
+		resp.Body.Close()
+		c.compressRequests.Store(false)
+		return c.sendHTTP(ctx, method, bytes.NewReader(rawBody), acceptType, header)
+	}
+
 	// universal handling for session terminated
 	if resp.StatusCode == http.StatusNotFound {
 		c.sessionID.CompareAndSwap(sessionID, "")
 		return nil, ErrSessionTerminated
 	}
 
+	if err := c.decompressResponseBody(resp); err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to decompress response: %w", err)
+	}
+
 	return resp, nil
 }
 
@@ -419,6 +481,11 @@ func (c *StreamableHTTP) handleSSEResponse(ctx context.Context, reader io.ReadCl
 	// Create a channel for this specific request
 	responseChan := make(chan *JSONRPCResponse, 1)
 
+	var requestHandler func(mcp.JSONRPCNotification)
+	if !ignoreResponse {
+		requestHandler = requestNotificationHandlerFromContext(ctx)
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
@@ -442,6 +509,9 @@ func (c *StreamableHTTP) handleSSEResponse(ctx context.Context, reader io.ReadCl
 					c.logger.Errorf("failed to unmarshal notification: %v", err)
 					return
 				}
+				if requestHandler != nil {
+					requestHandler(notification)
+				}
 				c.notifyMu.RLock()
 				if c.notificationHandler != nil {
 					c.notificationHandler(notification)
@@ -561,6 +631,7 @@ func (c *StreamableHTTP) SendNotification(ctx context.Context, notification mcp.
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
 		// Handle OAuth unauthorized error
 		if resp.StatusCode == http.StatusUnauthorized && c.oauthHandler != nil {
+			c.oauthHandler.handleUnauthorized(resp.Header)
 			return &OAuthAuthorizationRequiredError{
 				Handler: c.oauthHandler,
 			}
@@ -675,7 +746,7 @@ func (c *StreamableHTTP) createGETConnectionToServer(ctx context.Context) error
 	// messages. To be more compatible, we should handle this response, however, as the transport layer is message-based,
 	// currently, there is no convenient way to handle this response.
 	// So we ignore the response here. It's not a bug, but may be not compatible with other SDKs.
-	_, err = c.handleSSEResponse(ctx, resp.Body, true)
+	_, err = c.handleSSEResponse(ctx, newLivenessReader(resp.Body, c.heartbeatTimeout), true)
 	if err != nil {
 		return fmt.Errorf("failed to handle SSE response: %w", err)
 	}
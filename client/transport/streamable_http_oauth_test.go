@@ -218,3 +218,44 @@ func TestStreamableHTTP_IsOAuthEnabled(t *testing.T) {
 		t.Errorf("Expected IsOAuthEnabled() to return true")
 	}
 }
+
+func TestStreamableHTTP_OAuthRecordsResourceMetadataChallenge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("WWW-Authenticate", `Bearer resource_metadata="https://example.com/.well-known/oauth-protected-resource/mcp"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	tokenStore := NewMemoryTokenStore()
+	if err := tokenStore.SaveToken(context.Background(), &Token{
+		AccessToken: "stale-token",
+		TokenType:   "Bearer",
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}); err != nil {
+		t.Fatalf("Failed to save token: %v", err)
+	}
+
+	transport, err := NewStreamableHTTP(server.URL, WithHTTPOAuth(OAuthConfig{
+		ClientID:    "test-client",
+		RedirectURI: "http://localhost:8085/callback",
+		TokenStore:  tokenStore,
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create StreamableHTTP: %v", err)
+	}
+
+	_, err = transport.SendRequest(context.Background(), JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      mcp.NewRequestId(1),
+		Method:  "test",
+	})
+	var oauthErr *OAuthAuthorizationRequiredError
+	if !errors.As(err, &oauthErr) {
+		t.Fatalf("Expected OAuthAuthorizationRequiredError, got %T: %v", err, err)
+	}
+
+	expected := "https://example.com/.well-known/oauth-protected-resource/mcp"
+	if got := oauthErr.Handler.GetResourceMetadataURL(); got != expected {
+		t.Errorf("Expected resource metadata URL %q, got %q", expected, got)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -841,6 +842,51 @@ func TestContinuousListening(t *testing.T) {
 	}
 }
 
+func TestContinuousListeningHeartbeatTimeout(t *testing.T) {
+	retryInterval = 10 * time.Millisecond
+
+	var connections atomic.Int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Mcp-Session-Id", "test-session")
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":0,"result":{}}`))
+			return
+		}
+		// Accept the listening connection, then go silent.
+		connections.Add(1)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+	server := httptest.NewServer(handler)
+
+	trans, err := NewStreamableHTTP(server.URL, WithContinuousListening(), WithHTTPHeartbeatTimeout(30*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		trans.Close()
+		server.Close()
+	}()
+
+	if err := trans.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	_, err = trans.SendRequest(context.Background(), JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      mcp.NewRequestId(int64(0)),
+		Method:  "initialize",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	require.Eventually(t, func() bool { return connections.Load() >= 3 }, 2*time.Second, 10*time.Millisecond,
+		"the client reconnects when heartbeats are missed")
+}
+
 func TestContinuousListeningMethodNotAllowed(t *testing.T) {
 	// Start a server that doesn't support GET
 	url, closeServer, _, _ := startMockStreamableWithGETSupport(false)
@@ -7,7 +7,7 @@ import (
 )
 
 // NewJSONRPCErrorResponse creates a new JSONRPCResponse with an error.
-func NewJSONRPCErrorResponse(id mcp.RequestId, code int, message string, data any) *JSONRPCResponse {
+func NewJSONRPCErrorResponse(id mcp.RequestID, code int, message string, data any) *JSONRPCResponse {
 	details := mcp.NewJSONRPCErrorDetails(code, message, data)
 	return &JSONRPCResponse{
 		JSONRPC: mcp.JSONRPC_VERSION,
@@ -17,7 +17,7 @@ func NewJSONRPCErrorResponse(id mcp.RequestId, code int, message string, data an
 }
 
 // NewJSONRPCResultResponse creates a new JSONRPCResponse with a result.
-func NewJSONRPCResultResponse(id mcp.RequestId, result json.RawMessage) *JSONRPCResponse {
+func NewJSONRPCResultResponse(id mcp.RequestID, result json.RawMessage) *JSONRPCResponse {
 	return &JSONRPCResponse{
 		JSONRPC: mcp.JSONRPC_VERSION,
 		ID:      id,
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ErrNoToolResultContent is returned when a tool result carries neither
+// structured content nor text content that could be decoded.
+var ErrNoToolResultContent = errors.New("tool result has no decodable content")
+
+// ToolCallError is returned by CallToolTyped when the server reports that
+// the tool call failed (IsError is set on the result).
+type ToolCallError struct {
+	// Tool is the name of the tool that was called.
+	Tool string
+	// Result is the raw result returned by the server.
+	Result *mcp.CallToolResult
+}
+
+func (e *ToolCallError) Error() string {
+	var texts []string
+	for _, content := range e.Result.Content {
+		if text, ok := mcp.AsTextContent(content); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	if len(texts) == 0 {
+		return fmt.Sprintf("tool %q returned an error", e.Tool)
+	}
+	return fmt.Sprintf("tool %q returned an error: %s", e.Tool, strings.Join(texts, "\n"))
+}
+
+// CallToolTyped calls the named tool with args and decodes its result into
+// TResult. Args are sent as the JSON encoding of TArgs, so struct tags
+// control the argument names.
+//
+// The result is decoded with DecodeToolResult. If the server marks the
+// result as an error, a *ToolCallError is returned.
+func CallToolTyped[TArgs any, TResult any](
+	ctx context.Context,
+	c MCPClient,
+	name string,
+	args TArgs,
+) (TResult, error) {
+	var zero TResult
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = args
+
+	result, err := c.CallTool(ctx, request)
+	if err != nil {
+		return zero, err
+	}
+	if result.IsError {
+		return zero, &ToolCallError{Tool: name, Result: result}
+	}
+	return DecodeToolResult[TResult](result)
+}
+
+// DecodeToolResult decodes a tool result into T.
+//
+// Structured content is preferred when present. Otherwise the first text
+// content is parsed as JSON; if T is string and the text is not JSON, the
+// text is returned as-is.
+func DecodeToolResult[T any](result *mcp.CallToolResult) (T, error) {
+	var out T
+	if result == nil {
+		return out, ErrNoToolResultContent
+	}
+
+	if result.StructuredContent != nil {
+		data, err := json.Marshal(result.StructuredContent)
+		if err != nil {
+			return out, fmt.Errorf("failed to marshal structured content: %w", err)
+		}
+		if err := json.Unmarshal(data, &out); err != nil {
+			return out, fmt.Errorf("failed to unmarshal structured content: %w", err)
+		}
+		return out, nil
+	}
+
+	for _, content := range result.Content {
+		text, ok := mcp.AsTextContent(content)
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal([]byte(text.Text), &out); err != nil {
+			if s, ok := any(&out).(*string); ok {
+				*s = text.Text
+				return out, nil
+			}
+			return out, fmt.Errorf("failed to unmarshal text content: %w", err)
+		}
+		return out, nil
+	}
+
+	return out, ErrNoToolResultContent
+}
@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type addArgs struct {
+	A int `json:"a"`
+	B int `json:"b"`
+}
+
+type addResult struct {
+	Sum int `json:"sum"`
+}
+
+func newTypedToolsClient(t *testing.T) *Client {
+	t.Helper()
+
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("add"), mcp.NewStructuredToolHandler(
+		func(ctx context.Context, request mcp.CallToolRequest, args addArgs) (addResult, error) {
+			return addResult{Sum: args.A + args.B}, nil
+		},
+	))
+	mcpServer.AddTool(mcp.NewTool("add_json_text"), mcp.NewTypedToolHandler(
+		func(ctx context.Context, request mcp.CallToolRequest, args addArgs) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText(fmt.Sprintf(`{"sum":%d}`, args.A+args.B)), nil
+		},
+	))
+	mcpServer.AddTool(mcp.NewTool("greet"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("hello " + request.GetString("name", "")), nil
+	})
+	mcpServer.AddTool(mcp.NewTool("fail"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("division by zero"), nil
+	})
+
+	client, err := NewInProcessClient(mcpServer)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	ctx := context.Background()
+	require.NoError(t, client.Start(ctx))
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "test-client", Version: "1.0.0"}
+	_, err = client.Initialize(ctx, initRequest)
+	require.NoError(t, err)
+
+	return client
+}
+
+func TestCallToolTyped(t *testing.T) {
+	client := newTypedToolsClient(t)
+	ctx := context.Background()
+
+	t.Run("structured content", func(t *testing.T) {
+		result, err := CallToolTyped[addArgs, addResult](ctx, client, "add", addArgs{A: 2, B: 3})
+		require.NoError(t, err)
+		assert.Equal(t, 5, result.Sum)
+	})
+
+	t.Run("JSON text content", func(t *testing.T) {
+		result, err := CallToolTyped[addArgs, addResult](ctx, client, "add_json_text", addArgs{A: 4, B: 5})
+		require.NoError(t, err)
+		assert.Equal(t, 9, result.Sum)
+	})
+
+	t.Run("plain text into string", func(t *testing.T) {
+		result, err := CallToolTyped[map[string]any, string](ctx, client, "greet", map[string]any{"name": "gopher"})
+		require.NoError(t, err)
+		assert.Equal(t, "hello gopher", result)
+	})
+
+	t.Run("plain text into struct", func(t *testing.T) {
+		_, err := CallToolTyped[map[string]any, addResult](ctx, client, "greet", map[string]any{"name": "gopher"})
+		require.Error(t, err)
+	})
+
+	t.Run("tool error", func(t *testing.T) {
+		_, err := CallToolTyped[addArgs, addResult](ctx, client, "fail", addArgs{})
+		var toolErr *ToolCallError
+		require.ErrorAs(t, err, &toolErr)
+		assert.Equal(t, "fail", toolErr.Tool)
+		assert.Contains(t, err.Error(), "division by zero")
+	})
+}
+
+func TestDecodeToolResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  *mcp.CallToolResult
+		want    addResult
+		wantErr error
+	}{
+		{
+			name:   "structured content preferred over text",
+			result: mcp.NewToolResultStructured(map[string]any{"sum": 1}, `{"sum":2}`),
+			want:   addResult{Sum: 1},
+		},
+		{
+			name:   "skips non-text content",
+			result: &mcp.CallToolResult{Content: []mcp.Content{mcp.NewImageContent("data", "image/png"), mcp.NewTextContent(`{"sum":7}`)}},
+			want:   addResult{Sum: 7},
+		},
+		{
+			name:    "no content",
+			result:  &mcp.CallToolResult{},
+			wantErr: ErrNoToolResultContent,
+		},
+		{
+			name:    "nil result",
+			wantErr: ErrNoToolResultContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeToolResult[addResult](tt.result)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
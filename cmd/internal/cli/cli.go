@@ -0,0 +1,80 @@
+// Package cli holds what the commands talking to an MCP server share: the
+// -header flag and the connection to the server selected by their -stdio,
+// -sse and -http flags.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+)
+
+// HeaderFlag collects repeated -header flags.
+type HeaderFlag map[string]string
+
+func (h HeaderFlag) String() string {
+	headers := make([]string, 0, len(h))
+	for name, value := range h {
+		headers = append(headers, name+": "+value)
+	}
+	return strings.Join(headers, ", ")
+}
+
+func (h HeaderFlag) Set(s string) error {
+	name, value, ok := strings.Cut(s, ":")
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("header must be of the form 'Name: value'")
+	}
+	h[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	return nil
+}
+
+// Connect creates and starts a client of the server selected by exactly one
+// of stdioCmd, sseURL and httpURL. The logs of a stdio server are copied to
+// stderr, and httpOpts configure the streamable HTTP transport.
+func Connect(ctx context.Context, stdioCmd, sseURL, httpURL string, headers map[string]string, stderr io.Writer, httpOpts ...transport.StreamableHTTPCOption) (*client.Client, error) {
+	var t transport.Interface
+	var stdio *transport.Stdio
+	var err error
+	switch {
+	case countSet(stdioCmd, sseURL, httpURL) != 1:
+		return nil, errors.New("exactly one of -stdio, -sse and -http is required")
+	case stdioCmd != "":
+		args := strings.Fields(stdioCmd)
+		stdio = transport.NewStdio(args[0], nil, args[1:]...)
+		t = stdio
+	case sseURL != "":
+		t, err = transport.NewSSE(sseURL, transport.WithHeaders(headers))
+	default:
+		opts := append([]transport.StreamableHTTPCOption{transport.WithHTTPHeaders(headers)}, httpOpts...)
+		t, err = transport.NewStreamableHTTP(httpURL, opts...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := client.NewClient(t)
+	if err := c.Start(ctx); err != nil {
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+	if stdio != nil {
+		// Forward the logs of the server.
+		go io.Copy(stderr, stdio.Stderr())
+	}
+	return c, nil
+}
+
+func countSet(values ...string) int {
+	n := 0
+	for _, value := range values {
+		if value != "" {
+			n++
+		}
+	}
+	return n
+}
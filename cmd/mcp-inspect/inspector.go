@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const usage = `Commands:
+  info                      show the server, its version and capabilities
+  tools                     list the tools
+  resources                 list the resources
+  templates                 list the resource templates
+  prompts                   list the prompts
+  call <tool> [json]        call a tool with a JSON object of arguments
+  read <uri>                read a resource
+  prompt <name> [json]      get a prompt with a JSON object of arguments
+  tail                      print notifications until interrupted
+  help                      show this help
+  quit                      leave the interactive shell
+`
+
+// errQuit ends the interactive shell.
+var errQuit = errors.New("quit")
+
+// inspector runs commands against a connected client and prints their
+// results to out. Notifications are printed as they arrive.
+type inspector struct {
+	client     *client.Client
+	initResult *mcp.InitializeResult
+	timeout    time.Duration
+
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// newInspector returns an inspector of c, which must be started but not
+// yet initialized, and initializes it.
+func newInspector(ctx context.Context, c *client.Client, out io.Writer, timeout time.Duration) (*inspector, error) {
+	i := &inspector{client: c, out: out, timeout: timeout}
+	c.OnNotification(i.printNotification)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	request := mcp.InitializeRequest{}
+	request.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	request.Params.ClientInfo = mcp.Implementation{Name: "mcp-inspect", Version: version}
+	result, err := c.Initialize(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize: %w", err)
+	}
+	i.initResult = result
+	return i, nil
+}
+
+// printf writes to out, serialized with the printing of notifications.
+func (i *inspector) printf(format string, args ...any) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	fmt.Fprintf(i.out, format, args...)
+}
+
+func (i *inspector) printJSON(v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	i.printf("%s\n", data)
+	return nil
+}
+
+// printTable prints rows of tab-separated columns aligned.
+func (i *inspector) printTable(rows []string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	w := tabwriter.NewWriter(i.out, 0, 4, 2, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintln(w, row)
+	}
+	w.Flush()
+}
+
+func (i *inspector) printNotification(notification mcp.JSONRPCNotification) {
+	params, _ := json.Marshal(notification.Params)
+	if string(params) == "{}" || string(params) == "null" {
+		i.printf("<- %s\n", notification.Method)
+		return
+	}
+	i.printf("<- %s %s\n", notification.Method, params)
+}
+
+// shell reads commands from in, one per line, until it ends or quit is
+// entered. Failing commands are reported without ending the shell.
+func (i *inspector) shell(ctx context.Context, in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for {
+		i.printf("mcp> ")
+		if !scanner.Scan() {
+			i.printf("\n")
+			return scanner.Err()
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		err := i.run(ctx, line)
+		if errors.Is(err, errQuit) {
+			return nil
+		}
+		if err != nil {
+			i.printf("error: %v\n", err)
+		}
+	}
+}
+
+// run runs a command line: a command name followed by its arguments.
+func (i *inspector) run(ctx context.Context, line string) error {
+	command, rest, _ := strings.Cut(strings.TrimSpace(line), " ")
+	rest = strings.TrimSpace(rest)
+
+	if command == "tail" {
+		i.printf("waiting for notifications, interrupt to stop\n")
+		<-ctx.Done()
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, i.timeout)
+	defer cancel()
+
+	switch command {
+	case "info":
+		return i.info()
+	case "tools":
+		return i.tools(ctx)
+	case "resources":
+		return i.resources(ctx)
+	case "templates":
+		return i.templates(ctx)
+	case "prompts":
+		return i.prompts(ctx)
+	case "call":
+		name, args, _ := strings.Cut(rest, " ")
+		return i.call(ctx, name, strings.TrimSpace(args))
+	case "read":
+		return i.read(ctx, rest)
+	case "prompt":
+		name, args, _ := strings.Cut(rest, " ")
+		return i.prompt(ctx, name, strings.TrimSpace(args))
+	case "help":
+		i.printf("%s", usage)
+		return nil
+	case "quit", "exit":
+		return errQuit
+	default:
+		return fmt.Errorf("unknown command %q, try help", command)
+	}
+}
+
+func (i *inspector) info() error {
+	return i.printJSON(i.initResult)
+}
+
+func (i *inspector) tools(ctx context.Context) error {
+	result, err := i.client.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		return err
+	}
+	rows := make([]string, 0, len(result.Tools))
+	for _, tool := range result.Tools {
+		rows = append(rows, tool.Name+"\t"+firstLine(tool.Description))
+	}
+	i.printTable(rows)
+	return nil
+}
+
+func (i *inspector) resources(ctx context.Context) error {
+	result, err := i.client.ListResources(ctx, mcp.ListResourcesRequest{})
+	if err != nil {
+		return err
+	}
+	rows := make([]string, 0, len(result.Resources))
+	for _, resource := range result.Resources {
+		rows = append(rows, resource.URI+"\t"+resource.Name+"\t"+resource.MIMEType)
+	}
+	i.printTable(rows)
+	return nil
+}
+
+func (i *inspector) templates(ctx context.Context) error {
+	result, err := i.client.ListResourceTemplates(ctx, mcp.ListResourceTemplatesRequest{})
+	if err != nil {
+		return err
+	}
+	rows := make([]string, 0, len(result.ResourceTemplates))
+	for _, template := range result.ResourceTemplates {
+		var raw string
+		if template.URITemplate != nil {
+			raw = template.URITemplate.Raw()
+		}
+		rows = append(rows, raw+"\t"+template.Name+"\t"+template.MIMEType)
+	}
+	i.printTable(rows)
+	return nil
+}
+
+func (i *inspector) prompts(ctx context.Context) error {
+	result, err := i.client.ListPrompts(ctx, mcp.ListPromptsRequest{})
+	if err != nil {
+		return err
+	}
+	rows := make([]string, 0, len(result.Prompts))
+	for _, prompt := range result.Prompts {
+		args := make([]string, 0, len(prompt.Arguments))
+		for _, arg := range prompt.Arguments {
+			if arg.Required {
+				args = append(args, arg.Name)
+			} else {
+				args = append(args, "["+arg.Name+"]")
+			}
+		}
+		rows = append(rows, prompt.Name+"\t"+strings.Join(args, " ")+"\t"+firstLine(prompt.Description))
+	}
+	i.printTable(rows)
+	return nil
+}
+
+func (i *inspector) call(ctx context.Context, name, args string) error {
+	if name == "" {
+		return errors.New("usage: call <tool> [json]")
+	}
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	if args != "" {
+		var arguments map[string]any
+		if err := json.Unmarshal([]byte(args), &arguments); err != nil {
+			return fmt.Errorf("arguments must be a JSON object: %w", err)
+		}
+		request.Params.Arguments = arguments
+	}
+	result, err := i.client.CallTool(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	if result.IsError {
+		i.printf("tool error:\n")
+	}
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			i.printf("%s\n", text.Text)
+			continue
+		}
+		if err := i.printJSON(content); err != nil {
+			return err
+		}
+	}
+	if result.StructuredContent != nil {
+		i.printf("structured content:\n")
+		return i.printJSON(result.StructuredContent)
+	}
+	return nil
+}
+
+func (i *inspector) read(ctx context.Context, uri string) error {
+	if uri == "" {
+		return errors.New("usage: read <uri>")
+	}
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = uri
+	result, err := i.client.ReadResource(ctx, request)
+	if err != nil {
+		return err
+	}
+	for _, contents := range result.Contents {
+		if text, ok := contents.(mcp.TextResourceContents); ok {
+			i.printf("%s\n", text.Text)
+			continue
+		}
+		if err := i.printJSON(contents); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *inspector) prompt(ctx context.Context, name, args string) error {
+	if name == "" {
+		return errors.New("usage: prompt <name> [json]")
+	}
+	request := mcp.GetPromptRequest{}
+	request.Params.Name = name
+	if args != "" {
+		if err := json.Unmarshal([]byte(args), &request.Params.Arguments); err != nil {
+			return fmt.Errorf("arguments must be a JSON object of strings: %w", err)
+		}
+	}
+	result, err := i.client.GetPrompt(ctx, request)
+	if err != nil {
+		return err
+	}
+	return i.printJSON(result)
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
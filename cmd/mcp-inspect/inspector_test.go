@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func newTestServer() *server.MCPServer {
+	s := server.NewMCPServer("inspected", "1.2.3",
+		server.WithToolCapabilities(false),
+		server.WithResourceCapabilities(false, false),
+		server.WithPromptCapabilities(false),
+	)
+	s.AddTool(mcp.NewTool("greet", mcp.WithDescription("Greet someone\nat length"), mcp.WithString("name")),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("hello " + request.GetString("name", "world")), nil
+		})
+	s.AddResource(mcp.NewResource("docs://readme", "readme", mcp.WithMIMEType("text/plain")),
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: "read me"}}, nil
+		})
+	s.AddResourceTemplate(mcp.NewResourceTemplate("docs://{page}", "page"),
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return nil, nil
+		})
+	s.AddPrompt(mcp.NewPrompt("review", mcp.WithPromptDescription("Review code"), mcp.WithArgument("lang", mcp.RequiredArgument()), mcp.WithArgument("style")),
+		func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return mcp.NewGetPromptResult("review", []mcp.PromptMessage{
+				mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent("review this "+request.Params.Arguments["lang"])),
+			}), nil
+		})
+	return s
+}
+
+func TestRun(t *testing.T) {
+	ts := server.NewTestStreamableHTTPServer(newTestServer())
+	defer ts.Close()
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{name: "info", args: []string{"info"}, expected: []string{`"name": "inspected"`, `"version": "1.2.3"`}},
+		{name: "tools", args: []string{"tools"}, expected: []string{"greet  Greet someone\n"}},
+		{name: "resources", args: []string{"resources"}, expected: []string{"docs://readme  readme  text/plain"}},
+		{name: "templates", args: []string{"templates"}, expected: []string{"docs://{page}  page"}},
+		{name: "prompts", args: []string{"prompts"}, expected: []string{"review  lang [style]  Review code"}},
+		{name: "call", args: []string{"call", "greet", `{"name": "gopher"}`}, expected: []string{"hello gopher"}},
+		{name: "read", args: []string{"read", "docs://readme"}, expected: []string{"read me"}},
+		{name: "prompt", args: []string{"prompt", "review", `{"lang": "go"}`}, expected: []string{"review this go"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			err := run(append([]string{"-http", ts.URL + "/mcp"}, tt.args...), strings.NewReader(""), &stdout, &stderr)
+			require.NoError(t, err, stderr.String())
+			for _, expected := range tt.expected {
+				assert.Contains(t, stdout.String(), expected)
+			}
+		})
+	}
+
+	t.Run("unknown command", func(t *testing.T) {
+		err := run([]string{"-http", ts.URL + "/mcp", "list"}, strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{})
+		assert.ErrorContains(t, err, `unknown command "list"`)
+	})
+
+	t.Run("shell", func(t *testing.T) {
+		var stdout bytes.Buffer
+		input := "tools\ncall greet {\"name\": 1}\ncall greet not-json\nquit\ntools\n"
+		err := run([]string{"-http", ts.URL + "/mcp"}, strings.NewReader(input), &stdout, &bytes.Buffer{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, strings.Count(stdout.String(), "greet  Greet someone"), "commands after quit are not run")
+		assert.Contains(t, stdout.String(), "hello world")
+		assert.Contains(t, stdout.String(), "error: arguments must be a JSON object")
+	})
+}
+
+func TestRun_Flags(t *testing.T) {
+	err := run([]string{"tools"}, strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{})
+	assert.ErrorContains(t, err, "exactly one of -stdio, -sse and -http is required")
+
+	err = run([]string{"-header", "invalid", "-http", "http://localhost"}, strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{})
+	assert.ErrorContains(t, err, "header must be of the form")
+}
+
+func TestInspector_PrintNotification(t *testing.T) {
+	var out bytes.Buffer
+	i := &inspector{out: &out}
+	i.printNotification(mcp.JSONRPCNotification{Notification: mcp.Notification{Method: "notifications/tools/list_changed"}})
+
+	progress := mcp.JSONRPCNotification{Notification: mcp.Notification{Method: "notifications/progress"}}
+	progress.Params.AdditionalFields = map[string]any{"progress": 1}
+	i.printNotification(progress)
+
+	assert.Equal(t, "<- notifications/tools/list_changed\n<- notifications/progress {\"progress\":1}\n", out.String())
+}
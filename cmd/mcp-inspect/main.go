@@ -0,0 +1,81 @@
+// Command mcp-inspect connects to an MCP server over stdio, SSE or
+// streamable HTTP to explore and exercise it from a terminal.
+//
+// Usage:
+//
+//	mcp-inspect [flags] [command [arguments]]
+//
+// Exactly one of the -stdio, -sse and -http flags selects the server:
+//
+//	mcp-inspect -stdio "python server.py" tools
+//	mcp-inspect -http http://localhost:8080/mcp call add '{"a": 1, "b": 2}'
+//	mcp-inspect -sse http://localhost:8080/sse -header "Authorization: Bearer token"
+//
+// Without a command mcp-inspect starts an interactive shell reading one
+// command per line. Notifications from the server are printed as they
+// arrive. Run the help command for the list of commands.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/cmd/internal/cli"
+)
+
+const version = "0.1.0"
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "mcp-inspect: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("mcp-inspect", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	stdioCmd := flags.String("stdio", "", "command starting a server to talk to over stdio")
+	sseURL := flags.String("sse", "", "URL of the SSE endpoint of a server")
+	httpURL := flags.String("http", "", "URL of the streamable HTTP endpoint of a server")
+	timeout := flags.Duration("timeout", 30*time.Second, "timeout of each request")
+	headers := cli.HeaderFlag{}
+	flags.Var(headers, "header", "HTTP header 'Name: value' sent to SSE and HTTP servers, repeatable")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: mcp-inspect [flags] [command [arguments]]\n\nFlags:\n")
+		flags.PrintDefaults()
+		fmt.Fprintf(stderr, "\n%s", usage)
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	c, err := cli.Connect(ctx, *stdioCmd, *sseURL, *httpURL, headers, stderr, transport.WithContinuousListening())
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	i, err := newInspector(ctx, c, stdout, *timeout)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return i.shell(ctx, stdin)
+	}
+	return i.run(ctx, strings.Join(flags.Args(), " "))
+}
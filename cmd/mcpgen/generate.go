@@ -0,0 +1,491 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// initialisms are the name parts written in upper case in identifiers.
+var initialisms = map[string]bool{
+	"API": true, "CPU": true, "CSS": true, "DB": true, "DNS": true, "HTML": true,
+	"HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true, "SQL": true,
+	"TCP": true, "TTL": true, "UDP": true, "UI": true, "URI": true, "URL": true,
+	"UUID": true, "XML": true,
+}
+
+// generator emits a typed client package for the tools and prompts of a
+// server.
+type generator struct {
+	pkg    string
+	server mcp.Implementation
+
+	// decls holds the type declarations, in order.
+	decls []string
+	// names holds the package-level identifiers in use.
+	names map[string]bool
+	// refs maps the $ref pointers of the schema being generated to their
+	// type names.
+	refs map[string]string
+	// root is the schema $ref pointers are resolved in.
+	root map[string]any
+
+	structured bool
+}
+
+// generate returns the source of a package named pkg with a Client type
+// calling tools and prompts.
+func generate(pkg string, server mcp.Implementation, tools []mcp.Tool, prompts []mcp.Prompt) ([]byte, error) {
+	g := &generator{
+		pkg:    pkg,
+		server: server,
+		names:  map[string]bool{"Client": true, "New": true, "ToolError": true},
+	}
+
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	sort.Slice(prompts, func(i, j int) bool { return prompts[i].Name < prompts[j].Name })
+
+	var methods bytes.Buffer
+	methodNames := map[string]bool{"callTool": true, "getPrompt": true}
+	for _, tool := range tools {
+		if err := g.tool(&methods, methodNames, tool); err != nil {
+			return nil, err
+		}
+	}
+	for _, prompt := range prompts {
+		g.prompt(&methods, methodNames, prompt)
+	}
+
+	var b bytes.Buffer
+	g.header(&b, len(tools) > 0)
+	for _, decl := range g.decls {
+		b.WriteString(decl)
+	}
+	b.Write(methods.Bytes())
+	g.helpers(&b, len(tools) > 0, len(prompts) > 0)
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return src, nil
+}
+
+func (g *generator) header(b *bytes.Buffer, tools bool) {
+	fmt.Fprintf(b, "// Code generated by mcpgen from %s %s. DO NOT EDIT.\n\n", g.server.Name, g.server.Version)
+	fmt.Fprintf(b, "// Package %s is a typed client of the %s MCP server.\n", g.pkg, g.server.Name)
+	fmt.Fprintf(b, "package %s\n\nimport (\n\t\"context\"\n", g.pkg)
+	if g.structured {
+		b.WriteString("\t\"encoding/json\"\n\t\"errors\"\n")
+	}
+	if tools {
+		b.WriteString("\t\"strings\"\n")
+	}
+	b.WriteString("\n\t\"github.com/mark3labs/mcp-go/client\"\n\t\"github.com/mark3labs/mcp-go/mcp\"\n)\n\n")
+	fmt.Fprintf(b, `// Client calls the tools and prompts of the %s server.
+type Client struct {
+	client *client.Client
+}
+
+// New returns a Client calling the server through c, which must be
+// initialized.
+func New(c *client.Client) *Client {
+	return &Client{client: c}
+}
+
+`, g.server.Name)
+	if tools {
+		b.WriteString(`// ToolError is returned when a tool reports an error in its result.
+type ToolError struct {
+	// Tool is the name of the tool.
+	Tool string
+	// Result is the result of the call.
+	Result *mcp.CallToolResult
+}
+
+func (e *ToolError) Error() string {
+	var texts []string
+	for _, content := range e.Result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	if len(texts) == 0 {
+		return "tool " + e.Tool + " failed"
+	}
+	return "tool " + e.Tool + " failed: " + strings.Join(texts, "\n")
+}
+
+`)
+	}
+}
+
+func (g *generator) helpers(b *bytes.Buffer, tools, prompts bool) {
+	if tools {
+		b.WriteString(`func (c *Client) callTool(ctx context.Context, name string, args any) (*mcp.CallToolResult, error) {
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = args
+	result, err := c.client.CallTool(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if result.IsError {
+		return nil, &ToolError{Tool: name, Result: result}
+	}
+	return result, nil
+}
+
+`)
+	}
+	if g.structured {
+		b.WriteString(`func decodeStructured(result *mcp.CallToolResult, out any) error {
+	if result.StructuredContent == nil {
+		return errors.New("tool result has no structured content")
+	}
+	data, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+`)
+	}
+	if prompts {
+		b.WriteString(`func (c *Client) getPrompt(ctx context.Context, name string, args map[string]string) (*mcp.GetPromptResult, error) {
+	request := mcp.GetPromptRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = args
+	return c.client.GetPrompt(ctx, request)
+}
+`)
+	}
+}
+
+// tool writes the method calling tool and declares its types.
+func (g *generator) tool(b *bytes.Buffer, methodNames map[string]bool, tool mcp.Tool) error {
+	input, output, err := toolSchemas(tool)
+	if err != nil {
+		return err
+	}
+	method := uniqueName(methodNames, identifier(tool.Name))
+
+	argsType := ""
+	if props, _ := input["properties"].(map[string]any); len(props) > 0 {
+		argsType = g.declare(method+"Args", fmt.Sprintf("are the arguments of the %s tool.", tool.Name), input)
+	}
+	resultType := ""
+	if output != nil {
+		resultType = g.declare(method+"Result", fmt.Sprintf("is the structured result of the %s tool.", tool.Name), output)
+		g.structured = true
+	}
+
+	fmt.Fprintf(b, "// %s calls the %s tool.\n", method, tool.Name)
+	writeDoc(b, "", tool.Description, true)
+	fmt.Fprintf(b, "func (c *Client) %s(ctx context.Context", method)
+	args := "nil"
+	if argsType != "" {
+		fmt.Fprintf(b, ", args %s", argsType)
+		args = "args"
+	}
+	if resultType == "" {
+		fmt.Fprintf(b, ") (*mcp.CallToolResult, error) {\n\treturn c.callTool(ctx, %q, %s)\n}\n\n", tool.Name, args)
+		return nil
+	}
+	fmt.Fprintf(b, `) (*%[1]s, error) {
+	result, err := c.callTool(ctx, %[2]q, %[3]s)
+	if err != nil {
+		return nil, err
+	}
+	var out %[1]s
+	if err := decodeStructured(result, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+`, resultType, tool.Name, args)
+	return nil
+}
+
+// toolSchemas returns the input and output schemas of tool as decoded JSON.
+// The output schema is nil if the tool has none.
+func toolSchemas(tool mcp.Tool) (map[string]any, map[string]any, error) {
+	data, err := json.Marshal(tool)
+	if err != nil {
+		return nil, nil, fmt.Errorf("tool %s: %w", tool.Name, err)
+	}
+	var schemas struct {
+		InputSchema  map[string]any `json:"inputSchema"`
+		OutputSchema map[string]any `json:"outputSchema"`
+	}
+	if err := json.Unmarshal(data, &schemas); err != nil {
+		return nil, nil, fmt.Errorf("tool %s: %w", tool.Name, err)
+	}
+	return schemas.InputSchema, schemas.OutputSchema, nil
+}
+
+// prompt writes the method getting prompt and declares its arguments type.
+func (g *generator) prompt(b *bytes.Buffer, methodNames map[string]bool, prompt mcp.Prompt) {
+	method := uniqueName(methodNames, identifier(prompt.Name)+"Prompt")
+
+	var fields []string
+	if len(prompt.Arguments) > 0 {
+		argsType := uniqueName(g.names, method+"Args")
+		var decl bytes.Buffer
+		fmt.Fprintf(&decl, "// %s are the arguments of the %s prompt.\ntype %s struct {\n", argsType, prompt.Name, argsType)
+		fieldNames := map[string]bool{}
+		for _, arg := range prompt.Arguments {
+			field := uniqueName(fieldNames, identifier(arg.Name))
+			fields = append(fields, field)
+			writeDoc(&decl, "\t", arg.Description, false)
+			if !arg.Required {
+				writeDoc(&decl, "\t", "Optional.", false)
+			}
+			fmt.Fprintf(&decl, "\t%s string\n", field)
+		}
+		decl.WriteString("}\n\n")
+		g.decls = append(g.decls, decl.String())
+
+		fmt.Fprintf(b, "// %s gets the %s prompt.\n", method, prompt.Name)
+		writeDoc(b, "", prompt.Description, true)
+		fmt.Fprintf(b, "func (c *Client) %s(ctx context.Context, args %s) (*mcp.GetPromptResult, error) {\n", method, argsType)
+	} else {
+		fmt.Fprintf(b, "// %s gets the %s prompt.\n", method, prompt.Name)
+		writeDoc(b, "", prompt.Description, true)
+		fmt.Fprintf(b, "func (c *Client) %s(ctx context.Context) (*mcp.GetPromptResult, error) {\n", method)
+	}
+
+	b.WriteString("\targuments := map[string]string{}\n")
+	for i, arg := range prompt.Arguments {
+		if arg.Required {
+			fmt.Fprintf(b, "\targuments[%q] = args.%s\n", arg.Name, fields[i])
+		} else {
+			fmt.Fprintf(b, "\tif args.%[2]s != \"\" {\n\t\targuments[%[1]q] = args.%[2]s\n\t}\n", arg.Name, fields[i])
+		}
+	}
+	fmt.Fprintf(b, "\treturn c.getPrompt(ctx, %q, arguments)\n}\n\n", prompt.Name)
+}
+
+// declare declares a named type for the root schema of a tool and returns
+// its name. The doc comment follows the name.
+func (g *generator) declare(name, doc string, schema map[string]any) string {
+	g.root = schema
+	g.refs = map[string]string{}
+	name = uniqueName(g.names, name)
+	g.declareType(name, doc, schema)
+	return name
+}
+
+// declareType declares the named type name for schema.
+func (g *generator) declareType(name, doc string, schema map[string]any) {
+	var decl bytes.Buffer
+	fmt.Fprintf(&decl, "// %s %s\n", name, doc)
+	props, _ := schema["properties"].(map[string]any)
+	if len(props) == 0 {
+		fmt.Fprintf(&decl, "type %s %s\n\n", name, g.goType(name, doc, schema))
+		g.decls = append(g.decls, decl.String())
+		return
+	}
+
+	// Reserve the position of the declaration before the nested types.
+	i := len(g.decls)
+	g.decls = append(g.decls, "")
+
+	required := map[string]bool{}
+	if list, ok := schema["required"].([]any); ok {
+		for _, item := range list {
+			if s, ok := item.(string); ok {
+				required[s] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(props))
+	for prop := range props {
+		names = append(names, prop)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(&decl, "type %s struct {\n", name)
+	fieldNames := map[string]bool{}
+	for _, prop := range names {
+		field := uniqueName(fieldNames, identifier(prop))
+		propSchema, _ := props[prop].(map[string]any)
+		typ := g.goType(name+field, fmt.Sprintf("is the %s property of %s.", prop, name), propSchema)
+		if !required[prop] || nullable(propSchema) {
+			typ = optional(typ)
+		}
+		tag := prop
+		if !required[prop] {
+			tag += ",omitempty"
+		}
+		description, _ := propSchema["description"].(string)
+		writeDoc(&decl, "\t", description, false)
+		if enum, ok := propSchema["enum"].([]any); ok {
+			values := make([]string, len(enum))
+			for j, value := range enum {
+				data, _ := json.Marshal(value)
+				values[j] = string(data)
+			}
+			writeDoc(&decl, "\t", "One of "+strings.Join(values, ", ")+".", description != "")
+		}
+		fmt.Fprintf(&decl, "\t%s %s `json:%s`\n", field, typ, strconv.Quote(tag))
+	}
+	decl.WriteString("}\n\n")
+	g.decls[i] = decl.String()
+}
+
+// goType returns the Go type of values of schema. Nested object types are
+// declared with names starting with name and the given doc comment.
+func (g *generator) goType(name, doc string, schema map[string]any) string {
+	if ref, ok := schema["$ref"].(string); ok {
+		return g.refType(ref)
+	}
+	types := schemaTypes(schema)
+	delete(types, "null")
+	if len(types) != 1 {
+		return "any"
+	}
+	switch {
+	case types["string"]:
+		return "string"
+	case types["integer"]:
+		return "int"
+	case types["number"]:
+		return "float64"
+	case types["boolean"]:
+		return "bool"
+	case types["array"]:
+		items, _ := schema["items"].(map[string]any)
+		if items == nil {
+			return "[]any"
+		}
+		return "[]" + g.goType(name+"Item", "is an item of "+strings.TrimPrefix(doc, "is "), items)
+	}
+	if props, _ := schema["properties"].(map[string]any); len(props) > 0 {
+		typeName := uniqueName(g.names, name)
+		g.declareType(typeName, doc, schema)
+		return typeName
+	}
+	if additional, ok := schema["additionalProperties"].(map[string]any); ok {
+		return "map[string]" + g.goType(name+"Value", "is a value of "+strings.TrimPrefix(doc, "is "), additional)
+	}
+	return "map[string]any"
+}
+
+// refType returns the type of a local $ref pointer, declaring it on first
+// use.
+func (g *generator) refType(ref string) string {
+	if typeName, ok := g.refs[ref]; ok {
+		return typeName
+	}
+	var target any = g.root
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		m, _ := target.(map[string]any)
+		target = m[token]
+	}
+	schema, ok := target.(map[string]any)
+	if !ok || !strings.HasPrefix(ref, "#/") {
+		return "any"
+	}
+	typeName := uniqueName(g.names, identifier(ref[strings.LastIndex(ref, "/")+1:]))
+	g.refs[ref] = typeName
+	g.declareType(typeName, "is defined by the schema at "+ref+".", schema)
+	return typeName
+}
+
+// schemaTypes returns the set of types allowed by schema.
+func schemaTypes(schema map[string]any) map[string]bool {
+	types := map[string]bool{}
+	switch t := schema["type"].(type) {
+	case string:
+		types[t] = true
+	case []any:
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				types[s] = true
+			}
+		}
+	}
+	if len(types) == 0 {
+		if _, ok := schema["properties"]; ok {
+			types["object"] = true
+		}
+	}
+	return types
+}
+
+func nullable(schema map[string]any) bool {
+	return schemaTypes(schema)["null"]
+}
+
+// optional returns the type of an optional field of type typ: a pointer
+// unless nil already means absent.
+func optional(typ string) string {
+	if typ == "any" || strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[") || strings.HasPrefix(typ, "*") {
+		return typ
+	}
+	return "*" + typ
+}
+
+// identifier returns an exported Go identifier for name, such as SearchDocs
+// for "search_docs".
+func identifier(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for _, part := range parts {
+		if upper := strings.ToUpper(part); initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	id := b.String()
+	if id == "" || !unicode.IsLetter([]rune(id)[0]) {
+		id = "X" + id
+	}
+	return id
+}
+
+// uniqueName returns name, or name with a number appended if it is in
+// used, and adds the result to used.
+func uniqueName(used map[string]bool, name string) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// writeDoc writes text as comment lines with the given indent, separated
+// from a previous paragraph by an empty comment line if separate is set.
+func writeDoc(b *bytes.Buffer, indent, text string, separate bool) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	if separate {
+		fmt.Fprintf(b, "%s//\n", indent)
+	}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRightFunc(line, unicode.IsSpace)
+		if line == "" {
+			fmt.Fprintf(b, "%s//\n", indent)
+			continue
+		}
+		fmt.Fprintf(b, "%s// %s\n", indent, line)
+	}
+}
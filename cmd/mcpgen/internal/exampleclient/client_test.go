@@ -0,0 +1,69 @@
+package exampleclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func newClient(t *testing.T) *Client {
+	t.Helper()
+	c, err := client.NewInProcessClient(NewServer())
+	require.NoError(t, err)
+	t.Cleanup(func() { c.Close() })
+	ctx := context.Background()
+	require.NoError(t, c.Start(ctx))
+	request := mcp.InitializeRequest{}
+	request.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	_, err = c.Initialize(ctx, request)
+	require.NoError(t, err)
+	return New(c)
+}
+
+func TestClient(t *testing.T) {
+	c := newClient(t)
+	ctx := context.Background()
+
+	t.Run("structured result", func(t *testing.T) {
+		result, err := c.SearchDocs(ctx, SearchDocsArgs{
+			Query:  "hooks",
+			Tags:   []string{"server"},
+			Filter: &SearchDocsArgsFilter{Section: "guide"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &SearchDocsResult{
+			Total: 1,
+			Hits:  []SearchDocsResultHitsItem{{Title: "hooks [server] in guide", Score: 0.5}},
+		}, result)
+	})
+
+	t.Run("no arguments", func(t *testing.T) {
+		result, err := c.Ping(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "pong", result.Content[0].(mcp.TextContent).Text)
+	})
+
+	t.Run("tool error", func(t *testing.T) {
+		reason := "disk full"
+		_, err := c.Fail(ctx, FailArgs{Reason: &reason})
+		var toolErr *ToolError
+		require.ErrorAs(t, err, &toolErr)
+		assert.Equal(t, "fail", toolErr.Tool)
+		assert.EqualError(t, err, "tool fail failed: failed: disk full")
+	})
+
+	t.Run("prompt", func(t *testing.T) {
+		result, err := c.CodeReviewPrompt(ctx, CodeReviewPromptArgs{Code: "x := 1"})
+		require.NoError(t, err)
+		assert.Equal(t, "review x := 1", result.Messages[0].Content.(mcp.TextContent).Text)
+
+		result, err = c.CodeReviewPrompt(ctx, CodeReviewPromptArgs{Code: "x := 1", Language: "go"})
+		require.NoError(t, err)
+		assert.Equal(t, "review x := 1 as go", result.Messages[0].Content.(mcp.TextContent).Text)
+	})
+}
@@ -0,0 +1,87 @@
+// Package exampleclient is generated by mcpgen for the server returned by
+// NewServer, keeping the generated code compiled and tested. Regenerate
+// client.go with:
+//
+//	go test ./cmd/mcpgen -update
+package exampleclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// SearchResults is the output of the search_docs tool.
+type SearchResults struct {
+	Total int    `json:"total"`
+	Hits  []Hit  `json:"hits"`
+	Next  string `json:"next,omitempty"`
+}
+
+// Hit is a document found by the search_docs tool.
+type Hit struct {
+	Title string  `json:"title"`
+	Score float64 `json:"score"`
+}
+
+// NewServer returns the server client.go is generated from.
+func NewServer() *server.MCPServer {
+	s := server.NewMCPServer("docs", "1.0.0",
+		server.WithToolCapabilities(false),
+		server.WithPromptCapabilities(false),
+	)
+	s.AddTool(mcp.NewTool("search_docs",
+		mcp.WithDescription("Search the documentation."),
+		mcp.WithString("query", mcp.Required(), mcp.Description("Text to search for")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of hits")),
+		mcp.WithArray("tags", mcp.WithStringItems()),
+		mcp.WithObject("filter",
+			mcp.WithStringProperty("section", mcp.Required(), mcp.Enum("guide", "reference")),
+			mcp.WithBooleanProperty("recent"),
+		),
+		mcp.WithOutputSchema[SearchResults](),
+	), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args struct {
+			Query  string   `json:"query"`
+			Tags   []string `json:"tags"`
+			Filter *struct {
+				Section string `json:"section"`
+			} `json:"filter"`
+		}
+		if err := request.BindArguments(&args); err != nil {
+			return nil, err
+		}
+		title := args.Query
+		if len(args.Tags) > 0 {
+			title += " [" + strings.Join(args.Tags, ",") + "]"
+		}
+		if args.Filter != nil {
+			title += " in " + args.Filter.Section
+		}
+		results := SearchResults{Total: 1, Hits: []Hit{{Title: title, Score: 0.5}}}
+		return mcp.NewToolResultStructuredOnly(results), nil
+	})
+	s.AddTool(mcp.NewTool("ping"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("pong"), nil
+	})
+	s.AddTool(mcp.NewTool("fail", mcp.WithString("reason")), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultErrorFromErr("failed", errors.New(request.GetString("reason", "unknown"))), nil
+	})
+	s.AddPrompt(mcp.NewPrompt("code_review",
+		mcp.WithPromptDescription("Review a piece of code."),
+		mcp.WithArgument("code", mcp.RequiredArgument()),
+		mcp.WithArgument("language", mcp.ArgumentDescription("Programming language of the code")),
+	), func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		text := "review " + request.Params.Arguments["code"]
+		if language, ok := request.Params.Arguments["language"]; ok {
+			text += " as " + language
+		}
+		return mcp.NewGetPromptResult("review", []mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+		}), nil
+	})
+	return s
+}
@@ -0,0 +1,119 @@
+// Command mcpgen generates a typed Go client package for the tools and
+// prompts of a live MCP server.
+//
+// Usage:
+//
+//	mcpgen [flags]
+//
+// Exactly one of the -stdio, -sse and -http flags selects the server:
+//
+//	mcpgen -stdio "go run ./server" -package weather -o weather/client.go
+//	mcpgen -http http://localhost:8080/mcp -header "Authorization: Bearer token"
+//
+// The generated package has a Client type wrapping a *client.Client, with
+// one method per tool taking a struct of arguments derived from the tool's
+// input schema. Tools with an output schema return a struct decoded from
+// their structured content; others return the *mcp.CallToolResult. Prompts
+// get one method each, named after the prompt with a Prompt suffix.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/cmd/internal/cli"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const version = "0.1.0"
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "mcpgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("mcpgen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	stdioCmd := flags.String("stdio", "", "command starting a server to talk to over stdio")
+	sseURL := flags.String("sse", "", "URL of the SSE endpoint of a server")
+	httpURL := flags.String("http", "", "URL of the streamable HTTP endpoint of a server")
+	pkg := flags.String("package", "mcpclient", "name of the generated package")
+	output := flags.String("o", "", "file to write the generated code to instead of stdout")
+	timeout := flags.Duration("timeout", 30*time.Second, "timeout of talking to the server")
+	headers := cli.HeaderFlag{}
+	flags.Var(headers, "header", "HTTP header 'Name: value' sent to SSE and HTTP servers, repeatable")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: mcpgen [flags]\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments %q", flags.Args())
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+
+	c, err := cli.Connect(ctx, *stdioCmd, *sseURL, *httpURL, headers, stderr)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	src, err := generateFrom(ctx, c, *pkg)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*output, src, 0o644)
+}
+
+// generateFrom initializes c, which must be started, and generates a
+// package named pkg for the tools and prompts of its server.
+func generateFrom(ctx context.Context, c *client.Client, pkg string) ([]byte, error) {
+	request := mcp.InitializeRequest{}
+	request.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	request.Params.ClientInfo = mcp.Implementation{Name: "mcpgen", Version: version}
+	initResult, err := c.Initialize(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize: %w", err)
+	}
+
+	var tools []mcp.Tool
+	if initResult.Capabilities.Tools != nil {
+		result, err := c.ListTools(ctx, mcp.ListToolsRequest{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tools: %w", err)
+		}
+		tools = result.Tools
+	}
+	var prompts []mcp.Prompt
+	if initResult.Capabilities.Prompts != nil {
+		result, err := c.ListPrompts(ctx, mcp.ListPromptsRequest{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list prompts: %w", err)
+		}
+		prompts = result.Prompts
+	}
+	return generate(pkg, initResult.ServerInfo, tools, prompts)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/cmd/mcpgen/internal/exampleclient"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+var update = flag.Bool("update", false, "update internal/exampleclient/client.go")
+
+func TestRun(t *testing.T) {
+	ts := server.NewTestStreamableHTTPServer(exampleclient.NewServer())
+	defer ts.Close()
+
+	output := filepath.Join(t.TempDir(), "client.go")
+	var stdout, stderr bytes.Buffer
+	err := run([]string{"-http", ts.URL, "-package", "exampleclient", "-o", output}, &stdout, &stderr)
+	require.NoError(t, err, stderr.String())
+	generated, err := os.ReadFile(output)
+	require.NoError(t, err)
+
+	golden := filepath.Join("internal", "exampleclient", "client.go")
+	if *update {
+		require.NoError(t, os.WriteFile(golden, generated, 0o644))
+	}
+	want, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(generated), "run go test ./cmd/mcpgen -update to regenerate")
+}
+
+func TestRun_Errors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.EqualError(t, run(nil, &stdout, &stderr), "exactly one of -stdio, -sse and -http is required")
+	assert.EqualError(t, run([]string{"-http", "http://localhost", "extra"}, &stdout, &stderr), `unexpected arguments ["extra"]`)
+}
+
+func TestGenerate(t *testing.T) {
+	tools := []mcp.Tool{
+		mcp.NewToolWithRawSchema("get-user", "", json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"user_id": {"type": "integer"},
+				"address": {"$ref": "#/$defs/address"},
+				"labels": {"type": "object", "additionalProperties": {"type": "string"}},
+				"nickname": {"type": ["string", "null"]},
+				"any": {"oneOf": [{"type": "string"}, {"type": "number"}]}
+			},
+			"required": ["user_id", "nickname"],
+			"$defs": {"address": {"type": "object", "properties": {"city": {"type": "string"}}}}
+		}`)),
+	}
+	src, err := generate("users", mcp.Implementation{Name: "users", Version: "2.0.0"}, tools, nil)
+	require.NoError(t, err)
+
+	for _, want := range []string{
+		"// Code generated by mcpgen from users 2.0.0. DO NOT EDIT.",
+		"func (c *Client) GetUser(ctx context.Context, args GetUserArgs) (*mcp.CallToolResult, error) {",
+		"\tAddress *Address `json:\"address,omitempty\"`",
+		"\tAny any `json:\"any,omitempty\"`",
+		"\tLabels map[string]string `json:\"labels,omitempty\"`",
+		"\tNickname *string `json:\"nickname\"`",
+		"\tUserID int `json:\"user_id\"`",
+		"// Address is defined by the schema at #/$defs/address.",
+		"\tCity *string `json:\"city,omitempty\"`",
+	} {
+		assert.Contains(t, collapseSpace(string(src)), collapseSpace(want))
+	}
+	assert.NotContains(t, string(src), "getPrompt")
+	assert.NotContains(t, string(src), "encoding/json")
+}
+
+// collapseSpace replaces runs of white space with a single space, ignoring
+// the alignment of gofmt.
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func TestIdentifier(t *testing.T) {
+	tests := map[string]string{
+		"search_docs": "SearchDocs",
+		"get-user-id": "GetUserID",
+		"fetchURL":    "FetchURL",
+		"2fa":         "X2fa",
+		"":            "X",
+		"http.get":    "HTTPGet",
+	}
+	for name, want := range tests {
+		assert.Equal(t, want, identifier(name), name)
+	}
+}
@@ -0,0 +1,165 @@
+// Command mcpstub serves a stub MCP server answering with the responses of
+// a session recorded with transport.Recorder, or generates Go code creating
+// that stub, so that host applications can be tested without the services
+// behind the recorded server.
+//
+// Usage:
+//
+//	mcpstub -recording session.jsonl [flags]
+//
+// By default the stub is served over stdio; -http serves it over streamable
+// HTTP instead:
+//
+//	mcpstub -recording session.jsonl
+//	mcpstub -recording session.jsonl -http :8080
+//
+// With -gen, a Go file is written instead, embedding the recording and
+// defining a NewStubServer function for tests to serve the stub in
+// process:
+//
+//	mcpstub -recording session.jsonl -gen -package weatherstub -o weatherstub/stub.go
+//
+// See mcptest.NewStubServer for how requests are answered.
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcptest"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "mcpstub: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("mcpstub", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	recordingPath := flags.String("recording", "", "file of frames written by transport.Recorder")
+	httpAddr := flags.String("http", "", "address to serve the stub on over streamable HTTP instead of stdio")
+	loose := flags.Bool("loose", false, "answer calls with unrecorded arguments with responses recorded for other arguments")
+	gen := flags.Bool("gen", false, "generate Go code creating the stub instead of serving it")
+	pkg := flags.String("package", "mcpstub", "name of the generated package")
+	output := flags.String("o", "", "file to write the generated code to instead of stdout")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: mcpstub -recording file [flags]\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments %q", flags.Args())
+	}
+	if *recordingPath == "" {
+		return errors.New("-recording is required")
+	}
+
+	recording, err := os.ReadFile(*recordingPath)
+	if err != nil {
+		return err
+	}
+	frames, err := transport.ReadRecording(bytes.NewReader(recording))
+	if err != nil {
+		return fmt.Errorf("failed to read recording: %w", err)
+	}
+	var opts []mcptest.StubOption
+	if *loose {
+		opts = append(opts, mcptest.WithLooseMatching())
+	}
+	// Build the stub even when generating code, to report invalid
+	// recordings now rather than when the generated code runs.
+	stub, err := mcptest.NewStubServer(frames, opts...)
+	if err != nil {
+		return err
+	}
+
+	if *gen {
+		src, err := generate(*pkg, filepath.Base(*recordingPath), recording, *loose)
+		if err != nil {
+			return err
+		}
+		if *output == "" {
+			_, err = stdout.Write(src)
+			return err
+		}
+		return os.WriteFile(*output, src, 0o644)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if *httpAddr != "" {
+		httpServer := server.NewStreamableHTTPServer(stub)
+		errs := make(chan error, 1)
+		go func() { errs <- httpServer.Start(*httpAddr) }()
+		fmt.Fprintf(stderr, "mcpstub: serving %s on %s\n", *recordingPath, *httpAddr)
+		select {
+		case err := <-errs:
+			return err
+		case <-ctx.Done():
+			return httpServer.Shutdown(context.Background())
+		}
+	}
+	return server.NewStdioServer(stub).Listen(ctx, stdin, stdout)
+}
+
+// generate returns the source of a package named pkg defining a
+// NewStubServer function creating the stub of recording, read from the
+// file named source.
+func generate(pkg, source string, recording []byte, loose bool) ([]byte, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Code generated by mcpstub from %s. DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	b.WriteString(`import (
+	"strings"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcptest"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+`)
+	b.WriteString("// recording holds the frames of the recorded session, one per line.\n")
+	b.WriteString("const recording = \"\" +\n")
+	lines := strings.Split(strings.TrimRight(string(recording), "\n"), "\n")
+	for i, line := range lines {
+		suffix := " +"
+		if i == len(lines)-1 {
+			suffix = ""
+		}
+		fmt.Fprintf(&b, "\t%s%s\n", strconv.Quote(line+"\n"), suffix)
+	}
+	b.WriteString(`
+// NewStubServer returns a server answering with the responses of the
+// recorded session, as mcptest.NewStubServer does.
+func NewStubServer(opts ...mcptest.StubOption) (*server.MCPServer, error) {
+	frames, err := transport.ReadRecording(strings.NewReader(recording))
+	if err != nil {
+		return nil, err
+	}
+`)
+	if loose {
+		b.WriteString("\topts = append([]mcptest.StubOption{mcptest.WithLooseMatching()}, opts...)\n")
+	}
+	b.WriteString("\treturn mcptest.NewStubServer(frames, opts...)\n}\n")
+	return format.Source([]byte(b.String()))
+}
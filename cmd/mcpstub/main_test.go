@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// writeRecording records a session calling the echo tool of a server and
+// returns the file it is written to.
+func writeRecording(t *testing.T) string {
+	t.Helper()
+	s := server.NewMCPServer("echo", "1.0.0")
+	s.AddTool(mcp.NewTool("echo", mcp.WithString("text")), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(request.GetString("text", "")), nil
+	})
+
+	var recording bytes.Buffer
+	c := client.NewClient(transport.NewRecorder(transport.NewInProcessTransport(s), &recording))
+	defer c.Close()
+	ctx := context.Background()
+	require.NoError(t, c.Start(ctx))
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	_, err := c.Initialize(ctx, initRequest)
+	require.NoError(t, err)
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "echo"
+	request.Params.Arguments = map[string]any{"text": "hello `quoted` \"text\""}
+	_, err = c.CallTool(ctx, request)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	require.NoError(t, os.WriteFile(path, recording.Bytes(), 0o644))
+	return path
+}
+
+func TestRun_Stdio(t *testing.T) {
+	recording := writeRecording(t)
+	stdin := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1.0.0"}}}
+{"jsonrpc":"2.0","method":"notifications/initialized"}
+{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo","arguments":{"text":"hello ` + "`quoted`" + ` \"text\""}}}
+`)
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, run([]string{"-recording", recording}, stdin, &stdout, &stderr), stderr.String())
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 2)
+	var response struct {
+		Result mcp.CallToolResult `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &response))
+	assert.Contains(t, lines[1], `hello `+"`quoted`")
+	assert.Contains(t, lines[0], `"name":"echo"`, "the stub takes the server info from the recording")
+}
+
+func TestRun_Gen(t *testing.T) {
+	recording := writeRecording(t)
+	output := filepath.Join(t.TempDir(), "stub.go")
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, run([]string{"-recording", recording, "-gen", "-loose", "-package", "echostub", "-o", output}, nil, &stdout, &stderr))
+
+	src, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(src), "// Code generated by mcpstub from session.jsonl. DO NOT EDIT.\n\npackage echostub\n"))
+	assert.Contains(t, string(src), "func NewStubServer(opts ...mcptest.StubOption) (*server.MCPServer, error) {")
+	assert.Contains(t, string(src), "mcptest.WithLooseMatching()")
+}
+
+func TestRun_Errors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.EqualError(t, run(nil, nil, &stdout, &stderr), "-recording is required")
+	assert.EqualError(t, run([]string{"-recording", "x", "extra"}, nil, &stdout, &stderr), `unexpected arguments ["extra"]`)
+
+	invalid := filepath.Join(t.TempDir(), "invalid.jsonl")
+	require.NoError(t, os.WriteFile(invalid, []byte("not json\n"), 0o644))
+	assert.ErrorContains(t, run([]string{"-recording", invalid}, nil, &stdout, &stderr), "failed to read recording: invalid frame on line 1")
+}
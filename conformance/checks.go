@@ -0,0 +1,272 @@
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Names of the checks, which identify them in reports and in WithSkip.
+const (
+	checkInitialize   = "initialize"
+	checkNegotiation  = "initialize/version-negotiation"
+	checkPing         = "ping"
+	checkNotFound     = "errors/method-not-found"
+	checkUnknownTool  = "errors/unknown-tool"
+	checkBadCursor    = "errors/invalid-cursor"
+	checkCapabilities = "capabilities"
+	checkPagination   = "pagination"
+	checkCancellation = "cancellation"
+)
+
+type check struct {
+	name        string
+	description string
+	run         func(ctx context.Context, r *runner) (Status, string)
+}
+
+var checks = []check{
+	{checkInitialize, "The server completes the initialize handshake with the latest protocol version", runInitialize},
+	{checkNegotiation, "The server answers an unsupported protocol version with one it supports", runNegotiation},
+	{checkPing, "The server answers pings with an empty result", runPing},
+	{checkNotFound, "Unknown methods fail with METHOD_NOT_FOUND", runNotFound},
+	{checkUnknownTool, "Calls of unknown tools fail with INVALID_PARAMS", runUnknownTool},
+	{checkBadCursor, "Invalid pagination cursors fail with INVALID_PARAMS", runBadCursor},
+	{checkCapabilities, "Advertised capabilities are served and others are not", runCapabilities},
+	{checkPagination, "List results can be paged through without loops or duplicates", runPagination},
+	{checkCancellation, "Cancelling an unknown request is ignored and the server stays responsive", runCancellation},
+}
+
+func runInitialize(ctx context.Context, r *runner) (Status, string) {
+	result, err := r.main.initialize(ctx, mcp.LATEST_PROTOCOL_VERSION)
+	if err != nil {
+		return StatusFail, err.Error()
+	}
+	if !slices.Contains(mcp.ValidProtocolVersions, result.ProtocolVersion) {
+		return StatusFail, fmt.Sprintf("unknown protocol version %q", result.ProtocolVersion)
+	}
+	r.initialized = result
+	if result.ServerInfo.Name == "" {
+		return StatusWarn, "serverInfo has no name"
+	}
+	if result.ProtocolVersion != mcp.LATEST_PROTOCOL_VERSION {
+		return StatusWarn, fmt.Sprintf("negotiated %s rather than the latest version %s", result.ProtocolVersion, mcp.LATEST_PROTOCOL_VERSION)
+	}
+	return StatusPass, ""
+}
+
+func runNegotiation(ctx context.Context, r *runner) (Status, string) {
+	c, err := r.connect(ctx)
+	if err != nil {
+		return StatusSkip, err.Error()
+	}
+	defer c.close()
+
+	result, err := c.initialize(ctx, "1999-01-01")
+	if err != nil {
+		return StatusFail, err.Error()
+	}
+	if !slices.Contains(mcp.ValidProtocolVersions, result.ProtocolVersion) {
+		return StatusFail, fmt.Sprintf("answered with unknown protocol version %q", result.ProtocolVersion)
+	}
+	return StatusPass, ""
+}
+
+func runPing(ctx context.Context, r *runner) (Status, string) {
+	raw, rpcErr, err := r.main.call(ctx, string(mcp.MethodPing), nil)
+	if status, message, ok := callFailed(rpcErr, err); ok {
+		return status, message
+	}
+	var result map[string]any
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return StatusFail, fmt.Sprintf("result is not an object: %v", err)
+	}
+	return StatusPass, ""
+}
+
+func runNotFound(ctx context.Context, r *runner) (Status, string) {
+	_, rpcErr, err := r.main.call(ctx, "conformance/does-not-exist", nil)
+	return expectCode(rpcErr, err, mcp.METHOD_NOT_FOUND, StatusFail)
+}
+
+func runUnknownTool(ctx context.Context, r *runner) (Status, string) {
+	if r.initialized.Capabilities.Tools == nil {
+		return StatusSkip, "the server does not advertise tools"
+	}
+	_, rpcErr, err := r.main.call(ctx, string(mcp.MethodToolsCall), map[string]any{
+		"name":      "conformance-tool-that-does-not-exist",
+		"arguments": map[string]any{},
+	})
+	return expectCode(rpcErr, err, mcp.INVALID_PARAMS, StatusFail)
+}
+
+func runBadCursor(ctx context.Context, r *runner) (Status, string) {
+	lists := advertisedLists(r.initialized.Capabilities)
+	if len(lists) == 0 {
+		return StatusSkip, "the server advertises no paginated lists"
+	}
+	_, rpcErr, err := r.main.call(ctx, string(lists[0].method), map[string]any{"cursor": "!not a cursor!"})
+	return expectCode(rpcErr, err, mcp.INVALID_PARAMS, StatusWarn)
+}
+
+func runCapabilities(ctx context.Context, r *runner) (Status, string) {
+	var problems, warnings []string
+	for _, list := range allLists(r.initialized.Capabilities) {
+		_, rpcErr, err := r.main.call(ctx, string(list.method), nil)
+		if err != nil {
+			return StatusFail, fmt.Sprintf("%s: %v", list.method, err)
+		}
+		switch {
+		case list.advertised && rpcErr != nil:
+			problems = append(problems, fmt.Sprintf("%s failed with error %d although advertised", list.method, rpcErr.Code))
+		case !list.advertised && rpcErr == nil:
+			warnings = append(warnings, fmt.Sprintf("%s succeeded although not advertised", list.method))
+		}
+	}
+	if r.initialized.Capabilities.Logging != nil {
+		_, rpcErr, err := r.main.call(ctx, string(mcp.MethodSetLogLevel), map[string]any{"level": "info"})
+		if _, message, ok := callFailed(rpcErr, err); ok {
+			problems = append(problems, fmt.Sprintf("%s failed although logging is advertised: %s", mcp.MethodSetLogLevel, message))
+		}
+	}
+	if len(problems) > 0 {
+		return StatusFail, strings.Join(problems, "; ")
+	}
+	if len(warnings) > 0 {
+		return StatusWarn, strings.Join(warnings, "; ")
+	}
+	return StatusPass, ""
+}
+
+func runPagination(ctx context.Context, r *runner) (Status, string) {
+	lists := advertisedLists(r.initialized.Capabilities)
+	if len(lists) == 0 {
+		return StatusSkip, "the server advertises no paginated lists"
+	}
+	for _, list := range lists {
+		if message := r.pageThrough(ctx, list); message != "" {
+			return StatusFail, fmt.Sprintf("%s: %s", list.method, message)
+		}
+	}
+	return StatusPass, ""
+}
+
+// pageThrough follows the cursors of a list and describes the first problem
+// found, if any.
+func (r *runner) pageThrough(ctx context.Context, list listMethod) string {
+	seenItems := make(map[string]bool)
+	seenCursors := make(map[string]bool)
+	var cursor string
+	for page := 0; page < r.maxPages; page++ {
+		var params map[string]any
+		if cursor != "" {
+			params = map[string]any{"cursor": cursor}
+		}
+		raw, rpcErr, err := r.main.call(ctx, string(list.method), params)
+		if _, message, ok := callFailed(rpcErr, err); ok {
+			return fmt.Sprintf("page %d: %s", page+1, message)
+		}
+
+		var result map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &result); err != nil {
+			return fmt.Sprintf("page %d is not an object: %v", page+1, err)
+		}
+		var items []map[string]any
+		if err := json.Unmarshal(result[list.field], &items); err != nil {
+			return fmt.Sprintf("page %d has no %s array", page+1, list.field)
+		}
+		for _, item := range items {
+			key, _ := item[list.key].(string)
+			if seenItems[key] {
+				return fmt.Sprintf("%s %q is listed twice", list.key, key)
+			}
+			seenItems[key] = true
+		}
+
+		cursor = ""
+		if next, ok := result["nextCursor"]; ok {
+			_ = json.Unmarshal(next, &cursor)
+		}
+		if cursor == "" {
+			return ""
+		}
+		if seenCursors[cursor] {
+			return fmt.Sprintf("cursor %q is returned twice", cursor)
+		}
+		seenCursors[cursor] = true
+	}
+	return fmt.Sprintf("still paging after %d pages", r.maxPages)
+}
+
+func runCancellation(ctx context.Context, r *runner) (Status, string) {
+	err := r.main.notify(ctx, "notifications/cancelled", map[string]any{
+		"requestId": "conformance-unknown-request",
+		"reason":    "conformance check",
+	})
+	if err != nil {
+		return StatusFail, fmt.Sprintf("failed to send cancellation: %v", err)
+	}
+	_, rpcErr, err := r.main.call(ctx, string(mcp.MethodPing), nil)
+	if _, message, ok := callFailed(rpcErr, err); ok {
+		return StatusFail, "the server stopped answering after the cancellation: " + message
+	}
+	return StatusPass, ""
+}
+
+// callFailed describes a failed call, which fails its check.
+func callFailed(rpcErr *mcp.JSONRPCErrorDetails, err error) (Status, string, bool) {
+	if err != nil {
+		return StatusFail, fmt.Sprintf("transport error: %v", err), true
+	}
+	if rpcErr != nil {
+		return StatusFail, fmt.Sprintf("error %d: %s", rpcErr.Code, rpcErr.Message), true
+	}
+	return "", "", false
+}
+
+// expectCode checks that a call failed with code, reporting status if it
+// did not.
+func expectCode(rpcErr *mcp.JSONRPCErrorDetails, err error, code int, status Status) (Status, string) {
+	if err != nil {
+		return StatusFail, fmt.Sprintf("transport error: %v", err)
+	}
+	if rpcErr == nil {
+		return status, fmt.Sprintf("succeeded, expected error %d", code)
+	}
+	if rpcErr.Code != code {
+		return status, fmt.Sprintf("failed with error %d, expected %d", rpcErr.Code, code)
+	}
+	return StatusPass, ""
+}
+
+// listMethod is a paginated list method of the protocol.
+type listMethod struct {
+	method mcp.MCPMethod
+	// field is the result field holding the items, and key the field
+	// identifying an item.
+	field, key string
+	advertised bool
+}
+
+func allLists(capabilities mcp.ServerCapabilities) []listMethod {
+	return []listMethod{
+		{mcp.MethodToolsList, "tools", "name", capabilities.Tools != nil},
+		{mcp.MethodPromptsList, "prompts", "name", capabilities.Prompts != nil},
+		{mcp.MethodResourcesList, "resources", "uri", capabilities.Resources != nil},
+		{mcp.MethodResourcesTemplatesList, "resourceTemplates", "uriTemplate", capabilities.Resources != nil},
+	}
+}
+
+func advertisedLists(capabilities mcp.ServerCapabilities) []listMethod {
+	var lists []listMethod
+	for _, list := range allLists(capabilities) {
+		if list.advertised {
+			lists = append(lists, list)
+		}
+	}
+	return lists
+}
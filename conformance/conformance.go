@@ -0,0 +1,268 @@
+// Package conformance checks that an MCP server follows the protocol. Run
+// connects to a server through any client transport, runs a battery of
+// checks of the initialize handshake, error codes, capability
+// advertisement, pagination and cancellation, and returns a structured
+// report. It is meant for validating third-party servers from Go, for
+// example in the test suite of an application depending on them.
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	// StatusPass means the server behaved as the specification requires.
+	StatusPass Status = "pass"
+	// StatusFail means the server violated a requirement of the
+	// specification.
+	StatusFail Status = "fail"
+	// StatusWarn means the server did not follow a recommendation of the
+	// specification.
+	StatusWarn Status = "warn"
+	// StatusSkip means the check does not apply to the server, for example
+	// because it lacks the capability checked, or could not run.
+	StatusSkip Status = "skip"
+)
+
+// Result is the outcome of one check.
+type Result struct {
+	Check       string        `json:"check"`
+	Description string        `json:"description"`
+	Status      Status        `json:"status"`
+	Message     string        `json:"message,omitempty"`
+	Duration    time.Duration `json:"duration"`
+}
+
+// Report is the outcome of a conformance run.
+type Report struct {
+	ServerInfo      mcp.Implementation     `json:"serverInfo"`
+	ProtocolVersion string                 `json:"protocolVersion"`
+	Capabilities    mcp.ServerCapabilities `json:"capabilities"`
+	Results         []Result               `json:"results"`
+}
+
+// Passed reports whether no check failed. Warnings and skipped checks do
+// not count as failures.
+func (r *Report) Passed() bool {
+	return len(r.Failures()) == 0
+}
+
+// Failures returns the results of the failed checks.
+func (r *Report) Failures() []Result {
+	var failures []Result
+	for _, result := range r.Results {
+		if result.Status == StatusFail {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
+// Result returns the result of the named check.
+func (r *Report) Result(check string) (Result, bool) {
+	for _, result := range r.Results {
+		if result.Check == check {
+			return result, true
+		}
+	}
+	return Result{}, false
+}
+
+// WriteText writes a human readable summary of the report to w, one line
+// per check.
+func (r *Report) WriteText(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "%s %s (protocol %s)\n", r.ServerInfo.Name, r.ServerInfo.Version, r.ProtocolVersion); err != nil {
+		return err
+	}
+	for _, result := range r.Results {
+		line := fmt.Sprintf("%-4s  %s", result.Status, result.Check)
+		if result.Message != "" {
+			line += ": " + result.Message
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Dialer opens a new, unstarted connection to the server under test. Most
+// checks share one connection; those checking the initialize handshake open
+// their own.
+type Dialer func(ctx context.Context) (transport.Interface, error)
+
+// Option configures Run.
+type Option func(*runner)
+
+// WithCheckTimeout sets how long each check may take, 10 seconds by
+// default.
+func WithCheckTimeout(timeout time.Duration) Option {
+	return func(r *runner) {
+		r.timeout = timeout
+	}
+}
+
+// WithMaxPages sets how many pages the pagination check follows before
+// reporting a cursor loop, 100 by default.
+func WithMaxPages(n int) Option {
+	return func(r *runner) {
+		r.maxPages = n
+	}
+}
+
+// WithSkip skips the named checks.
+func WithSkip(checks ...string) Option {
+	return func(r *runner) {
+		r.skip = append(r.skip, checks...)
+	}
+}
+
+// Checks returns the names of the checks Run runs, in order.
+func Checks() []string {
+	names := make([]string, len(checks))
+	for i, c := range checks {
+		names[i] = c.name
+	}
+	return names
+}
+
+// Run runs the conformance checks against the server reached by dial and
+// returns their report. It only returns an error if the first connection
+// cannot be opened; a server failing the initialize handshake gets a report
+// with every other check skipped.
+func Run(ctx context.Context, dial Dialer, opts ...Option) (*Report, error) {
+	r := &runner{
+		dial:     dial,
+		timeout:  10 * time.Second,
+		maxPages: 100,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	main, err := r.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer main.close()
+	r.main = main
+
+	report := &Report{}
+	for _, c := range checks {
+		result := Result{Check: c.name, Description: c.description}
+		start := time.Now()
+		switch {
+		case slices.Contains(r.skip, c.name):
+			result.Status, result.Message = StatusSkip, "skipped by WithSkip"
+		case c.name != checkInitialize && r.initialized == nil:
+			result.Status, result.Message = StatusSkip, "the initialize handshake failed"
+		default:
+			checkCtx, cancel := context.WithTimeout(ctx, r.timeout)
+			result.Status, result.Message = c.run(checkCtx, r)
+			cancel()
+		}
+		result.Duration = time.Since(start)
+		report.Results = append(report.Results, result)
+	}
+	if r.initialized != nil {
+		report.ServerInfo = r.initialized.ServerInfo
+		report.ProtocolVersion = r.initialized.ProtocolVersion
+		report.Capabilities = r.initialized.Capabilities
+	}
+	return report, nil
+}
+
+// runner holds the state shared by the checks of a run.
+type runner struct {
+	dial     Dialer
+	timeout  time.Duration
+	maxPages int
+	skip     []string
+
+	main        *conn
+	initialized *mcp.InitializeResult
+}
+
+func (r *runner) connect(ctx context.Context) (*conn, error) {
+	trans, err := r.dial(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to server: %w", err)
+	}
+	if err := trans.Start(ctx); err != nil {
+		_ = trans.Close()
+		return nil, fmt.Errorf("failed to start transport: %w", err)
+	}
+	return &conn{transport: trans}, nil
+}
+
+// conn sends raw JSON-RPC messages, so that checks can send requests a
+// client.Client would refuse to.
+type conn struct {
+	transport transport.Interface
+	id        atomic.Int64
+}
+
+// call sends a request. A JSON-RPC error is returned as rpcErr, and err is
+// only set if the transport failed.
+func (c *conn) call(ctx context.Context, method string, params any) (result json.RawMessage, rpcErr *mcp.JSONRPCErrorDetails, err error) {
+	response, err := c.transport.SendRequest(ctx, transport.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestID(c.id.Add(1)),
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return response.Result, response.Error, nil
+}
+
+func (c *conn) notify(ctx context.Context, method string, params map[string]any) error {
+	return c.transport.SendNotification(ctx, mcp.JSONRPCNotification{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Notification: mcp.Notification{
+			Method: method,
+			Params: mcp.NotificationParams{AdditionalFields: params},
+		},
+	})
+}
+
+// initialize performs the initialize handshake with the given protocol
+// version.
+func (c *conn) initialize(ctx context.Context, version string) (*mcp.InitializeResult, error) {
+	raw, rpcErr, err := c.call(ctx, string(mcp.MethodInitialize), map[string]any{
+		"protocolVersion": version,
+		"capabilities":    map[string]any{},
+		"clientInfo":      mcp.Implementation{Name: "mcp-go-conformance", Version: "1.0.0"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if rpcErr != nil {
+		return nil, fmt.Errorf("initialize failed with error %d: %s", rpcErr.Code, rpcErr.Message)
+	}
+	var result mcp.InitializeResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("invalid initialize result: %w", err)
+	}
+	if err := c.notify(ctx, "notifications/initialized", nil); err != nil {
+		return nil, fmt.Errorf("failed to send initialized notification: %w", err)
+	}
+	return &result, nil
+}
+
+func (c *conn) close() {
+	_ = c.transport.Close()
+}
@@ -0,0 +1,130 @@
+package conformance_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/conformance"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func newServer() *server.MCPServer {
+	s := server.NewMCPServer("conformance-test", "1.0.0",
+		server.WithToolCapabilities(true),
+		server.WithPromptCapabilities(false),
+		server.WithLogging(),
+		server.WithPaginationLimit(2),
+	)
+	for i := range 5 {
+		s.AddTool(mcp.NewTool(fmt.Sprintf("tool-%d", i)), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("ok"), nil
+		})
+	}
+	return s
+}
+
+func TestRun(t *testing.T) {
+	httpServer := server.NewTestStreamableHTTPServer(newServer())
+	defer httpServer.Close()
+
+	report, err := conformance.Run(context.Background(), func(ctx context.Context) (transport.Interface, error) {
+		return transport.NewStreamableHTTP(httpServer.URL)
+	})
+	require.NoError(t, err)
+
+	var text bytes.Buffer
+	require.NoError(t, report.WriteText(&text))
+	assert.True(t, report.Passed(), "failures: %+v\n%s", report.Failures(), text.String())
+	assert.Equal(t, "conformance-test", report.ServerInfo.Name)
+	assert.Equal(t, mcp.LATEST_PROTOCOL_VERSION, report.ProtocolVersion)
+	require.Len(t, report.Results, len(conformance.Checks()))
+
+	pagination, ok := report.Result("pagination")
+	require.True(t, ok)
+	assert.Equal(t, conformance.StatusPass, pagination.Status)
+	capabilities, _ := report.Result("capabilities")
+	assert.Equal(t, conformance.StatusPass, capabilities.Status, capabilities.Message)
+}
+
+func TestRun_WithSkip(t *testing.T) {
+	s := newServer()
+	report, err := conformance.Run(context.Background(), func(ctx context.Context) (transport.Interface, error) {
+		return transport.NewInProcessTransport(s), nil
+	}, conformance.WithSkip("ping"))
+	require.NoError(t, err)
+	result, _ := report.Result("ping")
+	assert.Equal(t, conformance.StatusSkip, result.Status)
+}
+
+// brokenTransport answers like a server with wrong error codes and a
+// pagination cursor that loops.
+type brokenTransport struct{}
+
+func (brokenTransport) Start(ctx context.Context) error { return nil }
+func (brokenTransport) Close() error                    { return nil }
+func (brokenTransport) GetSessionId() string            { return "" }
+func (brokenTransport) SetNotificationHandler(handler func(notification mcp.JSONRPCNotification)) {
+}
+
+func (brokenTransport) SendNotification(ctx context.Context, notification mcp.JSONRPCNotification) error {
+	return nil
+}
+
+func (brokenTransport) SendRequest(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	response := &transport.JSONRPCResponse{JSONRPC: mcp.JSONRPC_VERSION, ID: request.ID}
+	var result any
+	switch request.Method {
+	case "initialize":
+		result = mcp.InitializeResult{
+			ProtocolVersion: mcp.LATEST_PROTOCOL_VERSION,
+			ServerInfo:      mcp.Implementation{Name: "broken", Version: "0.0.1"},
+			Capabilities: mcp.ServerCapabilities{Tools: &struct {
+				ListChanged bool `json:"listChanged,omitempty"`
+			}{}},
+		}
+	case "ping":
+		result = map[string]any{}
+	case "tools/list":
+		result = map[string]any{"tools": []any{}, "nextCursor": base64.StdEncoding.EncodeToString([]byte("again"))}
+	default:
+		response.Error = &mcp.JSONRPCErrorDetails{Code: mcp.INTERNAL_ERROR, Message: "something went wrong"}
+		return response, nil
+	}
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	response.Result = raw
+	return response, nil
+}
+
+func TestRun_ReportsViolations(t *testing.T) {
+	report, err := conformance.Run(context.Background(), func(ctx context.Context) (transport.Interface, error) {
+		return brokenTransport{}, nil
+	})
+	require.NoError(t, err)
+	assert.False(t, report.Passed())
+
+	expected := map[string]conformance.Status{
+		"initialize":              conformance.StatusPass,
+		"errors/method-not-found": conformance.StatusFail,
+		"errors/unknown-tool":     conformance.StatusFail,
+		"errors/invalid-cursor":   conformance.StatusWarn,
+		"pagination":              conformance.StatusFail,
+		"cancellation":            conformance.StatusPass,
+	}
+	for check, status := range expected {
+		result, ok := report.Result(check)
+		require.True(t, ok, check)
+		assert.Equal(t, status, result.Status, "%s: %s", check, result.Message)
+	}
+}
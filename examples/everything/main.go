@@ -387,15 +387,8 @@ func handleSendNotification(
 
 	server := server.ServerFromContext(ctx)
 
-	err := server.SendNotificationToClient(
-		ctx,
-		"notifications/progress",
-		map[string]any{
-			"progress":      10,
-			"total":         10,
-			"progressToken": 0,
-		},
-	)
+	total := 10.0
+	err := server.SendTypedNotificationToClient(ctx, mcp.NewProgressNotification(0, 10, &total, nil))
 	if err != nil {
 		return nil, fmt.Errorf("failed to send notification: %w", err)
 	}
@@ -424,16 +417,8 @@ func handleLongRunningOperationTool(
 	for i := 1; i < int(steps)+1; i++ {
 		time.Sleep(time.Duration(stepDuration * float64(time.Second)))
 		if progressToken != nil {
-			err := server.SendNotificationToClient(
-				ctx,
-				"notifications/progress",
-				map[string]any{
-					"progress":      i,
-					"total":         int(steps),
-					"progressToken": progressToken,
-					"message":       fmt.Sprintf("Server progress %v%%", int(float64(i)*100/steps)),
-				},
-			)
+			message := fmt.Sprintf("Server progress %v%%", int(float64(i)*100/steps))
+			err := server.SendTypedNotificationToClient(ctx, mcp.NewProgressNotification(progressToken, float64(i), &steps, &message))
 			if err != nil {
 				return nil, fmt.Errorf("failed to send notification: %w", err)
 			}
@@ -153,9 +153,21 @@ func main() {
 // Helper function to print tool results
 func printToolResult(result *mcp.CallToolResult) {
 	for _, content := range result.Content {
-		if textContent, ok := content.(mcp.TextContent); ok {
-			fmt.Println(textContent.Text)
-		} else {
+		switch c := content.(type) {
+		case mcp.TextContent:
+			fmt.Println(c.Text)
+		case mcp.ImageContent:
+			fmt.Printf("[image %s, %d bytes base64]\n", c.MIMEType, len(c.Data))
+		case mcp.AudioContent:
+			fmt.Printf("[audio %s, %d bytes base64]\n", c.MIMEType, len(c.Data))
+		case mcp.EmbeddedResource:
+			switch r := c.Resource.(type) {
+			case mcp.TextResourceContents:
+				fmt.Printf("[resource %s]\n%s\n", r.URI, r.Text)
+			case mcp.BlobResourceContents:
+				fmt.Printf("[resource %s, %s]\n", r.URI, r.MIMEType)
+			}
+		default:
 			jsonBytes, _ := json.MarshalIndent(content, "", "  ")
 			fmt.Println(string(jsonBytes))
 		}
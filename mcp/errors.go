@@ -1,6 +1,7 @@
 package mcp
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 )
@@ -27,8 +28,81 @@ var (
 
 	// ErrResourceNotFound indicates a requested resource was not found (code: RESOURCE_NOT_FOUND).
 	ErrResourceNotFound = errors.New("resource not found")
+
+	// ErrRateLimited indicates a rate limit was exceeded (code: RATE_LIMITED).
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrLimitExceeded indicates a limit of the server was exceeded (code: LIMIT_EXCEEDED).
+	ErrLimitExceeded = errors.New("limit exceeded")
+
+	// ErrUserRejected indicates the user rejected a request. Clients answer
+	// rejected server requests with USER_REJECTED; being a generic code, it
+	// is not in DefaultErrorCodes.
+	ErrUserRejected = errors.New("user rejected request")
+
+	// ErrUnknownMethod indicates the server does not support the requested
+	// method at all (code: METHOD_NOT_FOUND, with a MethodNotFoundErrorData
+	// without a capability).
+	ErrUnknownMethod = errors.New("unknown method")
+
+	// ErrCapabilityNotNegotiated indicates the requested method belongs to
+	// a capability the server did not negotiate (code: METHOD_NOT_FOUND,
+	// with a MethodNotFoundErrorData naming the capability).
+	ErrCapabilityNotNegotiated = errors.New("capability not negotiated")
+
+	// ErrToolNotFound indicates the requested tool does not exist (code:
+	// INVALID_PARAMS, with a NotFoundErrorData of type "tool").
+	ErrToolNotFound = errors.New("tool not found")
+
+	// ErrPromptNotFound indicates the requested prompt does not exist
+	// (code: INVALID_PARAMS, with a NotFoundErrorData of type "prompt").
+	ErrPromptNotFound = errors.New("prompt not found")
 )
 
+// MethodNotFoundErrorData is the data sent with a METHOD_NOT_FOUND error.
+type MethodNotFoundErrorData struct {
+	// Method is the method of the request.
+	Method string `json:"method"`
+	// Capability names the capability of the server the method belongs to,
+	// such as "tools", when the server knows the method but did not
+	// negotiate the capability. It is empty for unknown methods.
+	Capability string `json:"capability,omitempty"`
+}
+
+// NotFoundErrorData is the data sent with the error for a request naming a
+// tool, prompt or resource the server does not have: an INVALID_PARAMS
+// error for tools and prompts, and a RESOURCE_NOT_FOUND error for
+// resources.
+type NotFoundErrorData struct {
+	// Type is "tool", "prompt" or "resource".
+	Type string `json:"type"`
+	// Name is the name of the tool or prompt.
+	Name string `json:"name,omitempty"`
+	// URI is the URI of the resource.
+	URI string `json:"uri,omitempty"`
+}
+
+// RateLimitErrorData is the data sent with a RATE_LIMITED error.
+type RateLimitErrorData struct {
+	// Scope names the limit that was exceeded: "global", "session" or
+	// "tool" for servers, and "server" or "global" for the sampling limits
+	// of clients.
+	Scope string `json:"scope"`
+	// RetryAfter is the number of seconds after which the request may
+	// succeed.
+	RetryAfter float64 `json:"retryAfter"`
+}
+
+// LimitExceededErrorData is the data sent with a LIMIT_EXCEEDED error.
+type LimitExceededErrorData struct {
+	// Limit names the limit that was exceeded: "request_size",
+	// "params_depth" or "duration".
+	Limit string `json:"limit"`
+	// Max is the value of the limit: a number of bytes for request_size,
+	// of nesting levels for params_depth, and of milliseconds for duration.
+	Max int64 `json:"max"`
+}
+
 // UnsupportedProtocolVersionError is returned when the server responds with
 // a protocol version that the client doesn't support.
 type UnsupportedProtocolVersionError struct {
@@ -55,24 +129,8 @@ func IsUnsupportedProtocolVersion(err error) bool {
 // Returns sentinel errors wrapped with custom messages for known codes.
 // Defaults to a generic error with the original message when the code is not mapped.
 func (e *JSONRPCErrorDetails) AsError() error {
-	var err error
-
-	switch e.Code {
-	case PARSE_ERROR:
-		err = ErrParseError
-	case INVALID_REQUEST:
-		err = ErrInvalidRequest
-	case METHOD_NOT_FOUND:
-		err = ErrMethodNotFound
-	case INVALID_PARAMS:
-		err = ErrInvalidParams
-	case INTERNAL_ERROR:
-		err = ErrInternalError
-	case REQUEST_INTERRUPTED:
-		err = ErrRequestInterrupted
-	case RESOURCE_NOT_FOUND:
-		err = ErrResourceNotFound
-	default:
+	err := ErrorForCode(e.Code)
+	if err == nil {
 		return errors.New(e.Message)
 	}
 
@@ -83,3 +141,230 @@ func (e *JSONRPCErrorDetails) AsError() error {
 
 	return err
 }
+
+// ToError converts JSONRPCErrorDetails into an *Error, keeping the code and
+// data payload. Unlike AsError, the result is always an *Error, so callers
+// can inspect it with errors.As; it still matches the sentinel error for
+// its code with errors.Is.
+func (e *JSONRPCErrorDetails) ToError() *Error {
+	return &Error{Code: e.Code, Message: e.Message, Data: e.Data}
+}
+
+// Error is a JSON-RPC error with a code, message and optional data payload.
+// Handlers can return an *Error, possibly wrapped, to control the error
+// response sent to the client:
+//
+//	return nil, mcp.NewError(-32001, "quota exceeded", QuotaDetails{Limit: 100})
+//
+// Clients receive errors from the server as *Error, and can read the
+// payload back with DecodeData or ErrorData.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	// Data is the payload sent with the error. On the receiving side it is
+	// the decoded JSON value; use DecodeData to read it into a typed value.
+	Data any `json:"data,omitempty"`
+}
+
+// NewError creates an *Error with the given code, message and data.
+func NewError(code int, message string, data any) *Error {
+	return &Error{Code: code, Message: message, Data: data}
+}
+
+// Error formats the error like AsError does, prefixing the message with the
+// sentinel error of known codes.
+func (e *Error) Error() string {
+	sentinel := ErrorForCode(e.Code)
+	switch {
+	case sentinel == nil:
+		return e.Message
+	case e.Message == "" || e.Message == sentinel.Error():
+		return sentinel.Error()
+	default:
+		return fmt.Sprintf("%s: %s", sentinel, e.Message)
+	}
+}
+
+// Is reports whether target is the sentinel error for the error's code, or
+// an *Error with the same code. The sentinel errors telling errors with the
+// same code apart, such as ErrCapabilityNotNegotiated and ErrToolNotFound,
+// are matched by the data of the error.
+func (e *Error) Is(target error) bool {
+	if t, ok := target.(*Error); ok {
+		return t.Code == e.Code
+	}
+	switch target {
+	case ErrUnknownMethod, ErrCapabilityNotNegotiated, ErrToolNotFound, ErrPromptNotFound:
+		return e.detail() == target
+	}
+	sentinel := ErrorForCode(e.Code)
+	return sentinel != nil && target == sentinel
+}
+
+// detail returns the sentinel error the data of e refines its code into,
+// or nil.
+func (e *Error) detail() error {
+	var data struct {
+		Method     string `json:"method"`
+		Capability string `json:"capability"`
+		Type       string `json:"type"`
+	}
+	if e.Data == nil || e.DecodeData(&data) != nil {
+		return nil
+	}
+	switch {
+	case e.Code == METHOD_NOT_FOUND && data.Capability != "":
+		return ErrCapabilityNotNegotiated
+	case e.Code == METHOD_NOT_FOUND && data.Method != "":
+		return ErrUnknownMethod
+	case e.Code == INVALID_PARAMS && data.Type == "tool":
+		return ErrToolNotFound
+	case e.Code == INVALID_PARAMS && data.Type == "prompt":
+		return ErrPromptNotFound
+	}
+	return nil
+}
+
+// JSONRPCErrorDetails returns the error details sent in a JSON-RPC error
+// response.
+func (e *Error) JSONRPCErrorDetails() JSONRPCErrorDetails {
+	return NewJSONRPCErrorDetails(e.Code, e.Message, e.Data)
+}
+
+// DecodeData decodes the data payload into v, which must be a pointer.
+func (e *Error) DecodeData(v any) error {
+	if e.Data == nil {
+		return errors.New("error has no data")
+	}
+	raw, ok := e.Data.(json.RawMessage)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(e.Data); err != nil {
+			return fmt.Errorf("failed to marshal error data: %w", err)
+		}
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("failed to decode error data: %w", err)
+	}
+	return nil
+}
+
+// ErrorData returns the data payload of the *Error in err's chain decoded
+// as T. The second return value is false if err has no *Error or its data
+// cannot be decoded as T.
+func ErrorData[T any](err error) (T, bool) {
+	var data T
+	var mcpErr *Error
+	if !errors.As(err, &mcpErr) {
+		return data, false
+	}
+	if err := mcpErr.DecodeData(&data); err != nil {
+		return data, false
+	}
+	return data, true
+}
+
+// ErrorCodeMapping associates a sentinel error with the JSON-RPC error code
+// it should be reported as.
+type ErrorCodeMapping struct {
+	Err  error
+	Code int
+}
+
+// ErrorCodeTable is an ordered list of error-to-code mappings.
+// Entries are matched with errors.Is, so wrapped errors are recognized,
+// and the first matching entry wins.
+type ErrorCodeTable []ErrorCodeMapping
+
+// Code returns the code of the first entry matching err.
+// The second return value is false when no entry matches.
+func (t ErrorCodeTable) Code(err error) (int, bool) {
+	if err == nil {
+		return 0, false
+	}
+	for _, m := range t {
+		if errors.Is(err, m.Err) {
+			return m.Code, true
+		}
+	}
+	return 0, false
+}
+
+// Err returns the sentinel error of the first entry with the given code,
+// or nil if the table has no entry for it.
+func (t ErrorCodeTable) Err(code int) error {
+	for _, m := range t {
+		if m.Code == code {
+			return m.Err
+		}
+	}
+	return nil
+}
+
+// ErrorCodeMapper translates an error into a JSON-RPC error code.
+// It returns false when it has no opinion about err, letting the caller
+// fall back to another mapper or a default code.
+type ErrorCodeMapper func(err error) (code int, ok bool)
+
+// DefaultErrorCodes maps the sentinel errors of this package to their codes.
+var DefaultErrorCodes = ErrorCodeTable{
+	{Err: ErrParseError, Code: PARSE_ERROR},
+	{Err: ErrInvalidRequest, Code: INVALID_REQUEST},
+	{Err: ErrMethodNotFound, Code: METHOD_NOT_FOUND},
+	{Err: ErrInvalidParams, Code: INVALID_PARAMS},
+	{Err: ErrInternalError, Code: INTERNAL_ERROR},
+	{Err: ErrRequestInterrupted, Code: REQUEST_INTERRUPTED},
+	{Err: ErrResourceNotFound, Code: RESOURCE_NOT_FOUND},
+	{Err: ErrRateLimited, Code: RATE_LIMITED},
+	{Err: ErrLimitExceeded, Code: LIMIT_EXCEEDED},
+}
+
+// ErrorForCode returns the sentinel error for a known code, or nil.
+func ErrorForCode(code int) error {
+	return DefaultErrorCodes.Err(code)
+}
+
+// CodeForError returns the code of the sentinel error err wraps.
+// The second return value is false when err does not wrap any sentinel.
+func CodeForError(err error) (int, bool) {
+	return DefaultErrorCodes.Code(err)
+}
+
+// ChainErrorCodeMappers returns a mapper that consults each mapper in turn
+// and reports the first code found.
+func ChainErrorCodeMappers(mappers ...ErrorCodeMapper) ErrorCodeMapper {
+	return func(err error) (int, bool) {
+		for _, mapper := range mappers {
+			if mapper == nil {
+				continue
+			}
+			if code, ok := mapper(err); ok {
+				return code, true
+			}
+		}
+		return 0, false
+	}
+}
+
+// IsReservedErrorCode reports whether code lies in the range JSON-RPC
+// reserves for pre-defined errors. Application-specific codes should be
+// chosen outside of it.
+func IsReservedErrorCode(code int) bool {
+	return code >= RESERVED_ERROR_MIN && code <= RESERVED_ERROR_MAX
+}
+
+// IsServerErrorCode reports whether code lies in the range JSON-RPC leaves
+// for implementation-defined server errors, such as RESOURCE_NOT_FOUND.
+func IsServerErrorCode(code int) bool {
+	return code >= SERVER_ERROR_MIN && code <= SERVER_ERROR_MAX
+}
+
+// IsStandardErrorCode reports whether code is one of the errors defined by
+// the JSON-RPC 2.0 specification.
+func IsStandardErrorCode(code int) bool {
+	switch code {
+	case PARSE_ERROR, INVALID_REQUEST, METHOD_NOT_FOUND, INVALID_PARAMS, INTERNAL_ERROR:
+		return true
+	}
+	return false
+}
@@ -1,7 +1,10 @@
 package mcp
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -168,3 +171,238 @@ func TestErrorChaining(t *testing.T) {
 	// But the original error should
 	require.True(t, errors.Is(err, ErrMethodNotFound))
 }
+
+func TestErrorCodeTable(t *testing.T) {
+	t.Parallel()
+
+	errQuotaExceeded := errors.New("quota exceeded")
+	table := ErrorCodeTable{
+		{Err: errQuotaExceeded, Code: -31000},
+		{Err: ErrInvalidParams, Code: INVALID_PARAMS},
+	}
+
+	code, ok := table.Code(fmt.Errorf("user 42: %w", errQuotaExceeded))
+	require.True(t, ok)
+	require.Equal(t, -31000, code)
+
+	code, ok = table.Code(ErrInvalidParams)
+	require.True(t, ok)
+	require.Equal(t, INVALID_PARAMS, code)
+
+	_, ok = table.Code(errors.New("unrelated"))
+	require.False(t, ok)
+
+	_, ok = table.Code(nil)
+	require.False(t, ok)
+
+	require.Equal(t, errQuotaExceeded, table.Err(-31000))
+	require.Nil(t, table.Err(INTERNAL_ERROR))
+}
+
+func TestDefaultErrorCodes_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, m := range DefaultErrorCodes {
+		code, ok := CodeForError(fmt.Errorf("wrapped: %w", m.Err))
+		require.True(t, ok, "code for %v", m.Err)
+		require.Equal(t, m.Code, code)
+		require.Equal(t, m.Err, ErrorForCode(m.Code))
+	}
+	require.Nil(t, ErrorForCode(-1))
+}
+
+func TestChainErrorCodeMappers(t *testing.T) {
+	t.Parallel()
+
+	errDomain := errors.New("domain")
+	domain := ErrorCodeTable{{Err: errDomain, Code: -31001}}
+	mapper := ChainErrorCodeMappers(nil, domain.Code, CodeForError)
+
+	code, ok := mapper(errDomain)
+	require.True(t, ok)
+	require.Equal(t, -31001, code)
+
+	code, ok = mapper(ErrResourceNotFound)
+	require.True(t, ok)
+	require.Equal(t, RESOURCE_NOT_FOUND, code)
+
+	_, ok = mapper(errors.New("other"))
+	require.False(t, ok)
+}
+
+func TestErrorCodeRanges(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		code     int
+		reserved bool
+		server   bool
+		standard bool
+	}{
+		{code: PARSE_ERROR, reserved: true, standard: true},
+		{code: INVALID_REQUEST, reserved: true, standard: true},
+		{code: METHOD_NOT_FOUND, reserved: true, standard: true},
+		{code: INVALID_PARAMS, reserved: true, standard: true},
+		{code: INTERNAL_ERROR, reserved: true, standard: true},
+		{code: REQUEST_INTERRUPTED},
+		{code: RESOURCE_NOT_FOUND, reserved: true, server: true},
+		{code: SERVER_ERROR_MIN, reserved: true, server: true},
+		{code: SERVER_ERROR_MAX, reserved: true, server: true},
+		{code: RESERVED_ERROR_MIN, reserved: true},
+		{code: RESERVED_ERROR_MIN - 1},
+		{code: -31000},
+		{code: 1},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.reserved, IsReservedErrorCode(tt.code), "reserved %d", tt.code)
+		require.Equal(t, tt.server, IsServerErrorCode(tt.code), "server %d", tt.code)
+		require.Equal(t, tt.standard, IsStandardErrorCode(tt.code), "standard %d", tt.code)
+	}
+}
+
+func TestError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		err             *Error
+		expectedMessage string
+		matches         []error
+	}{
+		{
+			name:            "known code with custom message",
+			err:             NewError(INVALID_PARAMS, "missing city", nil),
+			expectedMessage: "invalid params: missing city",
+			matches:         []error{ErrInvalidParams, &Error{Code: INVALID_PARAMS}},
+		},
+		{
+			name:            "known code with standard message",
+			err:             NewError(METHOD_NOT_FOUND, "method not found", nil),
+			expectedMessage: "method not found",
+			matches:         []error{ErrMethodNotFound},
+		},
+		{
+			name:            "application code",
+			err:             NewError(-31000, "quota exceeded", map[string]any{"limit": 10}),
+			expectedMessage: "quota exceeded",
+			matches:         []error{&Error{Code: -31000}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.EqualError(t, tc.err, tc.expectedMessage)
+			for _, target := range tc.matches {
+				require.ErrorIs(t, tc.err, target)
+			}
+			require.NotErrorIs(t, tc.err, ErrInternalError)
+			require.NotErrorIs(t, tc.err, &Error{Code: -1})
+		})
+	}
+}
+
+func TestError_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	type quota struct {
+		Limit int    `json:"limit"`
+		Scope string `json:"scope"`
+	}
+
+	sent := NewError(-31000, "quota exceeded", quota{Limit: 10, Scope: "daily"})
+	raw, err := json.Marshal(sent.JSONRPCErrorDetails())
+	require.NoError(t, err)
+
+	var details JSONRPCErrorDetails
+	require.NoError(t, json.Unmarshal(raw, &details))
+	received := details.ToError()
+	require.Equal(t, -31000, received.Code)
+	require.Equal(t, "quota exceeded", received.Message)
+
+	data, ok := ErrorData[quota](fmt.Errorf("calling tool: %w", received))
+	require.True(t, ok)
+	require.Equal(t, quota{Limit: 10, Scope: "daily"}, data)
+
+	_, ok = ErrorData[quota](errors.New("plain"))
+	require.False(t, ok)
+	require.Error(t, NewError(INTERNAL_ERROR, "no data", nil).DecodeData(&data))
+}
+
+func TestError_DetailSentinels(t *testing.T) {
+	t.Parallel()
+
+	detailed := []error{ErrUnknownMethod, ErrCapabilityNotNegotiated, ErrToolNotFound, ErrPromptNotFound}
+	tests := []struct {
+		name    string
+		err     *Error
+		matches []error
+	}{
+		{
+			name:    "unknown method",
+			err:     NewError(METHOD_NOT_FOUND, "Method foo not found", MethodNotFoundErrorData{Method: "foo"}),
+			matches: []error{ErrMethodNotFound, ErrUnknownMethod},
+		},
+		{
+			name: "capability not negotiated",
+			err: NewError(METHOD_NOT_FOUND, "tools not supported",
+				MethodNotFoundErrorData{Method: "tools/list", Capability: "tools"}),
+			matches: []error{ErrMethodNotFound, ErrCapabilityNotNegotiated},
+		},
+		{
+			name:    "method not found without data",
+			err:     NewError(METHOD_NOT_FOUND, "Method foo not found", nil),
+			matches: []error{ErrMethodNotFound},
+		},
+		{
+			name:    "tool not found",
+			err:     NewError(INVALID_PARAMS, "tool 'x' not found", NotFoundErrorData{Type: "tool", Name: "x"}),
+			matches: []error{ErrInvalidParams, ErrToolNotFound},
+		},
+		{
+			name:    "prompt not found",
+			err:     NewError(INVALID_PARAMS, "prompt 'x' not found", NotFoundErrorData{Type: "prompt", Name: "x"}),
+			matches: []error{ErrInvalidParams, ErrPromptNotFound},
+		},
+		{
+			name:    "resource not found",
+			err:     NewError(RESOURCE_NOT_FOUND, "no such resource", NotFoundErrorData{Type: "resource", URI: "file:///x"}),
+			matches: []error{ErrResourceNotFound},
+		},
+		{
+			name:    "not found data with another code",
+			err:     NewError(INTERNAL_ERROR, "failed", NotFoundErrorData{Type: "tool", Name: "x"}),
+			matches: []error{ErrInternalError},
+		},
+		{
+			name:    "data of another shape",
+			err:     NewError(METHOD_NOT_FOUND, "Method foo not found", "foo"),
+			matches: []error{ErrMethodNotFound},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Errors are decoded the way clients receive them.
+			raw, err := json.Marshal(tc.err.JSONRPCErrorDetails())
+			require.NoError(t, err)
+			var details JSONRPCErrorDetails
+			require.NoError(t, json.Unmarshal(raw, &details))
+
+			for _, received := range []*Error{tc.err, details.ToError()} {
+				for _, target := range tc.matches {
+					require.ErrorIs(t, fmt.Errorf("calling: %w", received), target)
+				}
+				for _, target := range detailed {
+					if !slices.Contains(tc.matches, target) {
+						require.NotErrorIs(t, received, target)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package mcp
+
+import "maps"
+
+const progressTokenMetaKey = "progressToken"
+
+// Get returns the value of the _meta field key, or nil if m is nil or the
+// field is not set. The progressToken key reads ProgressToken.
+func (m *Meta) Get(key string) any {
+	if m == nil {
+		return nil
+	}
+	if key == progressTokenMetaKey {
+		return m.ProgressToken
+	}
+	return m.AdditionalFields[key]
+}
+
+// Set sets the _meta field key to value. The progressToken key sets
+// ProgressToken. Like any metadata, value must marshal to JSON.
+func (m *Meta) Set(key string, value any) {
+	if key == progressTokenMetaKey {
+		m.ProgressToken = value
+		return
+	}
+	if m.AdditionalFields == nil {
+		m.AdditionalFields = make(map[string]any)
+	}
+	m.AdditionalFields[key] = value
+}
+
+// Delete removes the _meta field key.
+func (m *Meta) Delete(key string) {
+	if m == nil {
+		return
+	}
+	if key == progressTokenMetaKey {
+		m.ProgressToken = nil
+		return
+	}
+	delete(m.AdditionalFields, key)
+}
+
+// Clone returns a copy of m that can be changed without affecting m. Field
+// values are not copied deeply.
+func (m *Meta) Clone() *Meta {
+	if m == nil {
+		return nil
+	}
+	return &Meta{ProgressToken: m.ProgressToken, AdditionalFields: maps.Clone(m.AdditionalFields)}
+}
+
+// ToMap returns the fields of m as a map, as they are marshaled to JSON,
+// or nil if m is nil.
+func (m *Meta) ToMap() map[string]any {
+	if m == nil {
+		return nil
+	}
+	fields := make(map[string]any, len(m.AdditionalFields)+1)
+	maps.Copy(fields, m.AdditionalFields)
+	if m.ProgressToken != nil {
+		fields[progressTokenMetaKey] = m.ProgressToken
+	}
+	return fields
+}
+
+// MetaValue returns the _meta field key of m as a T. It reports false if
+// the field is not set or holds another type. Fields decoded from JSON hold
+// the types encoding/json decodes into any, such as float64 for numbers.
+func MetaValue[T any](m *Meta, key string) (T, bool) {
+	value, ok := m.Get(key).(T)
+	return value, ok
+}
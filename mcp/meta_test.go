@@ -0,0 +1,119 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeta_GetSet(t *testing.T) {
+	var nilMeta *Meta
+	assert.Nil(t, nilMeta.Get("key"))
+	assert.Nil(t, nilMeta.Clone())
+	assert.Nil(t, nilMeta.ToMap())
+	nilMeta.Delete("key")
+
+	meta := &Meta{}
+	meta.Set("correlationId", "abc")
+	meta.Set("progressToken", 7)
+	assert.Equal(t, "abc", meta.Get("correlationId"))
+	assert.Equal(t, 7, meta.ProgressToken)
+	assert.Equal(t, map[string]any{"correlationId": "abc", "progressToken": 7}, meta.ToMap())
+
+	id, ok := MetaValue[string](meta, "correlationId")
+	assert.True(t, ok)
+	assert.Equal(t, "abc", id)
+	_, ok = MetaValue[int](meta, "correlationId")
+	assert.False(t, ok, "wrong type")
+	_, ok = MetaValue[string](nilMeta, "correlationId")
+	assert.False(t, ok, "nil meta")
+
+	clone := meta.Clone()
+	clone.Set("correlationId", "def")
+	clone.Delete("progressToken")
+	assert.Equal(t, "abc", meta.Get("correlationId"), "clones are independent")
+	assert.Equal(t, 7, meta.Get("progressToken"))
+	assert.Nil(t, clone.Get("progressToken"))
+}
+
+func TestParams_MetaRoundTrip(t *testing.T) {
+	const meta = `{"correlationId":"abc","host":{"trace":[1,2]},"progressToken":"p"}`
+	tests := []struct {
+		name   string
+		params string
+		target any
+		meta   func(v any) *Meta
+	}{
+		{
+			name:   "initialize",
+			params: `{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"c","version":"1"},"_meta":` + meta + `}`,
+			target: &InitializeParams{},
+			meta:   func(v any) *Meta { return v.(*InitializeParams).Meta },
+		},
+		{
+			name:   "paginated",
+			params: `{"cursor":"c","_meta":` + meta + `}`,
+			target: &PaginatedParams{},
+			meta:   func(v any) *Meta { return v.(*PaginatedParams).Meta },
+		},
+		{
+			name:   "get prompt",
+			params: `{"name":"p","_meta":` + meta + `}`,
+			target: &GetPromptParams{},
+			meta:   func(v any) *Meta { return v.(*GetPromptParams).Meta },
+		},
+		{
+			name:   "subscribe",
+			params: `{"uri":"file:///a","_meta":` + meta + `}`,
+			target: &SubscribeParams{},
+			meta:   func(v any) *Meta { return v.(*SubscribeParams).Meta },
+		},
+		{
+			name:   "complete",
+			params: `{"ref":{"type":"ref/prompt","name":"p"},"argument":{"name":"a","value":"b"},"_meta":` + meta + `}`,
+			target: &CompleteParams{},
+			meta:   func(v any) *Meta { return v.(*CompleteParams).Meta },
+		},
+		{
+			name:   "progress notification",
+			params: `{"progressToken":1,"progress":0.5,"_meta":` + meta + `}`,
+			target: &ProgressNotificationParams{},
+			meta:   func(v any) *Meta { return v.(*ProgressNotificationParams).Meta },
+		},
+		{
+			name:   "result",
+			params: `{"_meta":` + meta + `}`,
+			target: &EmptyResult{},
+			meta:   func(v any) *Meta { return v.(*EmptyResult).Meta },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, json.Unmarshal([]byte(tt.params), tt.target))
+			decoded := tt.meta(tt.target)
+			require.NotNil(t, decoded)
+			assert.Equal(t, "p", decoded.ProgressToken)
+			assert.Equal(t, "abc", decoded.Get("correlationId"))
+
+			data, err := json.Marshal(tt.target)
+			require.NoError(t, err)
+			var fields struct {
+				Meta json.RawMessage `json:"_meta"`
+			}
+			require.NoError(t, json.Unmarshal(data, &fields))
+			assert.JSONEq(t, meta, string(fields.Meta), "unknown meta keys are preserved")
+		})
+	}
+}
+
+func TestNewProgressNotification_Meta(t *testing.T) {
+	notification := NewProgressNotification("token", 1, nil, nil)
+	notification.Params.Meta = &Meta{}
+	notification.Params.Meta.Set("correlationId", "abc")
+	data, err := json.Marshal(notification.Params)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"progressToken":"token","progress":1,"_meta":{"correlationId":"abc"}}`, string(data))
+}
@@ -0,0 +1,348 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+)
+
+// TypedNotification is a notification defined by the protocol, such as
+// ProgressNotification, that can be sent as a JSONRPCNotification.
+type TypedNotification interface {
+	// ToJSONRPCNotification returns the notification as it is sent over
+	// the wire.
+	ToJSONRPCNotification() JSONRPCNotification
+}
+
+var (
+	_ TypedNotification = ProgressNotification{}
+	_ TypedNotification = CancelledNotification{}
+	_ TypedNotification = LoggingMessageNotification{}
+	_ TypedNotification = ResourceUpdatedNotification{}
+	_ TypedNotification = ResourceListChangedNotification{}
+	_ TypedNotification = ToolListChangedNotification{}
+	_ TypedNotification = PromptListChangedNotification{}
+	_ TypedNotification = RootsListChangedNotification{}
+	_ TypedNotification = InitializedNotification{}
+)
+
+// NewCancelledNotification
+// Helper function for creating a notification cancelling the request with
+// the given ID. The reason is optional.
+func NewCancelledNotification(requestID RequestID, reason string) CancelledNotification {
+	return CancelledNotification{
+		Notification: Notification{Method: MethodNotificationCancelled},
+		Params: CancelledNotificationParams{
+			RequestId: requestID,
+			Reason:    reason,
+		},
+	}
+}
+
+// NewResourceUpdatedNotification
+// Helper function for creating a notification telling subscribers that the
+// resource at uri changed.
+func NewResourceUpdatedNotification(uri string) ResourceUpdatedNotification {
+	return ResourceUpdatedNotification{
+		Notification: Notification{Method: MethodNotificationResourceUpdated},
+		Params:       ResourceUpdatedNotificationParams{URI: uri},
+	}
+}
+
+// NewResourceListChangedNotification
+// Helper function for creating a notifications/resources/list_changed notification
+func NewResourceListChangedNotification() ResourceListChangedNotification {
+	return ResourceListChangedNotification{Notification: Notification{Method: MethodNotificationResourcesListChanged}}
+}
+
+// NewToolListChangedNotification
+// Helper function for creating a notifications/tools/list_changed notification
+func NewToolListChangedNotification() ToolListChangedNotification {
+	return ToolListChangedNotification{Notification: Notification{Method: MethodNotificationToolsListChanged}}
+}
+
+// NewPromptListChangedNotification
+// Helper function for creating a notifications/prompts/list_changed notification
+func NewPromptListChangedNotification() PromptListChangedNotification {
+	return PromptListChangedNotification{Notification: Notification{Method: MethodNotificationPromptsListChanged}}
+}
+
+// NewRootsListChangedNotification
+// Helper function for creating a notifications/roots/list_changed notification
+func NewRootsListChangedNotification() RootsListChangedNotification {
+	return RootsListChangedNotification{Notification: Notification{Method: MethodNotificationRootsListChanged}}
+}
+
+// NewInitializedNotification
+// Helper function for creating a notifications/initialized notification
+func NewInitializedNotification() InitializedNotification {
+	return InitializedNotification{Notification: Notification{Method: MethodNotificationInitialized}}
+}
+
+// newJSONRPCNotification returns the notification of method with the given
+// params, and the fields of meta as its _meta.
+func newJSONRPCNotification(method string, meta *Meta, params map[string]any) JSONRPCNotification {
+	return JSONRPCNotification{
+		JSONRPC: JSONRPC_VERSION,
+		Notification: Notification{
+			Method: method,
+			Params: NotificationParams{
+				Meta:             meta.ToMap(),
+				AdditionalFields: params,
+			},
+		},
+	}
+}
+
+func (n ProgressNotification) ToJSONRPCNotification() JSONRPCNotification {
+	params := map[string]any{
+		"progressToken": n.Params.ProgressToken,
+		"progress":      n.Params.Progress,
+	}
+	if n.Params.Total != 0 {
+		params["total"] = n.Params.Total
+	}
+	if n.Params.Message != "" {
+		params["message"] = n.Params.Message
+	}
+	return newJSONRPCNotification(MethodNotificationProgress, n.Params.Meta, params)
+}
+
+func (n CancelledNotification) ToJSONRPCNotification() JSONRPCNotification {
+	params := map[string]any{"requestId": n.Params.RequestId.Value()}
+	if n.Params.Reason != "" {
+		params["reason"] = n.Params.Reason
+	}
+	return newJSONRPCNotification(MethodNotificationCancelled, n.Params.Meta, params)
+}
+
+func (n LoggingMessageNotification) ToJSONRPCNotification() JSONRPCNotification {
+	params := map[string]any{
+		"level": n.Params.Level,
+		"data":  n.Params.Data,
+	}
+	if n.Params.Logger != "" {
+		params["logger"] = n.Params.Logger
+	}
+	return newJSONRPCNotification(MethodNotificationMessage, n.Params.Meta, params)
+}
+
+func (n ResourceUpdatedNotification) ToJSONRPCNotification() JSONRPCNotification {
+	return newJSONRPCNotification(MethodNotificationResourceUpdated, n.Params.Meta, map[string]any{"uri": n.Params.URI})
+}
+
+func (n ResourceListChangedNotification) ToJSONRPCNotification() JSONRPCNotification {
+	return paramlessJSONRPCNotification(MethodNotificationResourcesListChanged, n.Notification)
+}
+
+func (n ToolListChangedNotification) ToJSONRPCNotification() JSONRPCNotification {
+	return paramlessJSONRPCNotification(MethodNotificationToolsListChanged, n.Notification)
+}
+
+func (n PromptListChangedNotification) ToJSONRPCNotification() JSONRPCNotification {
+	return paramlessJSONRPCNotification(MethodNotificationPromptsListChanged, n.Notification)
+}
+
+func (n RootsListChangedNotification) ToJSONRPCNotification() JSONRPCNotification {
+	return paramlessJSONRPCNotification(MethodNotificationRootsListChanged, n.Notification)
+}
+
+func (n InitializedNotification) ToJSONRPCNotification() JSONRPCNotification {
+	return paramlessJSONRPCNotification(MethodNotificationInitialized, n.Notification)
+}
+
+// paramlessJSONRPCNotification returns a notification of a method defining
+// no params, keeping the _meta and any extra params of n.
+func paramlessJSONRPCNotification(method string, n Notification) JSONRPCNotification {
+	return JSONRPCNotification{
+		JSONRPC:      JSONRPC_VERSION,
+		Notification: Notification{Method: method, Params: n.Params},
+	}
+}
+
+// ParseNotification decodes a notification defined by the protocol into
+// its typed form: a *ProgressNotification, *CancelledNotification,
+// *LoggingMessageNotification, *ResourceUpdatedNotification,
+// *ResourceListChangedNotification, *ToolListChangedNotification,
+// *PromptListChangedNotification, *RootsListChangedNotification or
+// *InitializedNotification. Other methods return an error wrapping
+// ErrMethodNotFound.
+func ParseNotification(n JSONRPCNotification) (TypedNotification, error) {
+	switch n.Method {
+	case MethodNotificationProgress:
+		return asTypedNotification(ParseProgressNotification(n))
+	case MethodNotificationCancelled:
+		return asTypedNotification(ParseCancelledNotification(n))
+	case MethodNotificationMessage:
+		return asTypedNotification(ParseLoggingMessageNotification(n))
+	case MethodNotificationResourceUpdated:
+		return asTypedNotification(ParseResourceUpdatedNotification(n))
+	case MethodNotificationResourcesListChanged:
+		return asTypedNotification(ParseResourceListChangedNotification(n))
+	case MethodNotificationToolsListChanged:
+		return asTypedNotification(ParseToolListChangedNotification(n))
+	case MethodNotificationPromptsListChanged:
+		return asTypedNotification(ParsePromptListChangedNotification(n))
+	case MethodNotificationRootsListChanged:
+		return asTypedNotification(ParseRootsListChangedNotification(n))
+	case MethodNotificationInitialized:
+		return asTypedNotification(ParseInitializedNotification(n))
+	default:
+		return nil, fmt.Errorf("%w: %q is not a notification of the protocol", ErrMethodNotFound, n.Method)
+	}
+}
+
+// asTypedNotification keeps a failed parse from returning a non-nil
+// TypedNotification holding a nil pointer.
+func asTypedNotification[T TypedNotification](n T, err error) (TypedNotification, error) {
+	if err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// ParseProgressNotification decodes a notifications/progress notification.
+// It fails if the notification has another method or no progress token.
+func ParseProgressNotification(n JSONRPCNotification) (*ProgressNotification, error) {
+	result := &ProgressNotification{Notification: Notification{Method: n.Method}}
+	if err := decodeNotificationParams(n, MethodNotificationProgress, &result.Params); err != nil {
+		return nil, err
+	}
+	if result.Params.ProgressToken == nil {
+		return nil, missingNotificationParam(n.Method, "progressToken")
+	}
+	return result, nil
+}
+
+// ParseCancelledNotification decodes a notifications/cancelled
+// notification. It fails if the notification has another method or no
+// request ID.
+func ParseCancelledNotification(n JSONRPCNotification) (*CancelledNotification, error) {
+	result := &CancelledNotification{Notification: Notification{Method: n.Method}}
+	if err := decodeNotificationParams(n, MethodNotificationCancelled, &result.Params); err != nil {
+		return nil, err
+	}
+	if result.Params.RequestId.IsNil() {
+		return nil, missingNotificationParam(n.Method, "requestId")
+	}
+	return result, nil
+}
+
+// ParseLoggingMessageNotification decodes a notifications/message
+// notification. It fails if the notification has another method or no
+// level.
+func ParseLoggingMessageNotification(n JSONRPCNotification) (*LoggingMessageNotification, error) {
+	result := &LoggingMessageNotification{Notification: Notification{Method: n.Method}}
+	if err := decodeNotificationParams(n, MethodNotificationMessage, &result.Params); err != nil {
+		return nil, err
+	}
+	if result.Params.Level == "" {
+		return nil, missingNotificationParam(n.Method, "level")
+	}
+	return result, nil
+}
+
+// ParseResourceUpdatedNotification decodes a notifications/resources/updated
+// notification. It fails if the notification has another method or no URI.
+func ParseResourceUpdatedNotification(n JSONRPCNotification) (*ResourceUpdatedNotification, error) {
+	result := &ResourceUpdatedNotification{Notification: Notification{Method: n.Method}}
+	if err := decodeNotificationParams(n, MethodNotificationResourceUpdated, &result.Params); err != nil {
+		return nil, err
+	}
+	if result.Params.URI == "" {
+		return nil, missingNotificationParam(n.Method, "uri")
+	}
+	return result, nil
+}
+
+// ParseResourceListChangedNotification decodes a
+// notifications/resources/list_changed notification.
+func ParseResourceListChangedNotification(n JSONRPCNotification) (*ResourceListChangedNotification, error) {
+	if err := checkNotificationMethod(n, MethodNotificationResourcesListChanged); err != nil {
+		return nil, err
+	}
+	return &ResourceListChangedNotification{Notification: paramlessNotification(n)}, nil
+}
+
+// ParseToolListChangedNotification decodes a
+// notifications/tools/list_changed notification.
+func ParseToolListChangedNotification(n JSONRPCNotification) (*ToolListChangedNotification, error) {
+	if err := checkNotificationMethod(n, MethodNotificationToolsListChanged); err != nil {
+		return nil, err
+	}
+	return &ToolListChangedNotification{Notification: paramlessNotification(n)}, nil
+}
+
+// ParsePromptListChangedNotification decodes a
+// notifications/prompts/list_changed notification.
+func ParsePromptListChangedNotification(n JSONRPCNotification) (*PromptListChangedNotification, error) {
+	if err := checkNotificationMethod(n, MethodNotificationPromptsListChanged); err != nil {
+		return nil, err
+	}
+	return &PromptListChangedNotification{Notification: paramlessNotification(n)}, nil
+}
+
+// ParseRootsListChangedNotification decodes a
+// notifications/roots/list_changed notification.
+func ParseRootsListChangedNotification(n JSONRPCNotification) (*RootsListChangedNotification, error) {
+	if err := checkNotificationMethod(n, MethodNotificationRootsListChanged); err != nil {
+		return nil, err
+	}
+	return &RootsListChangedNotification{Notification: paramlessNotification(n)}, nil
+}
+
+// ParseInitializedNotification decodes a notifications/initialized
+// notification.
+func ParseInitializedNotification(n JSONRPCNotification) (*InitializedNotification, error) {
+	if err := checkNotificationMethod(n, MethodNotificationInitialized); err != nil {
+		return nil, err
+	}
+	return &InitializedNotification{Notification: paramlessNotification(n)}, nil
+}
+
+// paramlessNotification returns the Notification of n, leaving out the
+// empty _meta and params that decoding creates.
+func paramlessNotification(n JSONRPCNotification) Notification {
+	notification := n.Notification
+	if len(notification.Params.Meta) == 0 {
+		notification.Params.Meta = nil
+	}
+	if len(notification.Params.AdditionalFields) == 0 {
+		notification.Params.AdditionalFields = nil
+	}
+	return notification
+}
+
+func checkNotificationMethod(n JSONRPCNotification, method string) error {
+	if n.Method != method {
+		return fmt.Errorf("notification method is %q, not %q", n.Method, method)
+	}
+	return nil
+}
+
+// decodeNotificationParams checks that n has the given method and decodes
+// its params into params.
+func decodeNotificationParams(n JSONRPCNotification, method string, params any) error {
+	if err := checkNotificationMethod(n, method); err != nil {
+		return err
+	}
+	fields := make(map[string]any, len(n.Params.AdditionalFields)+1)
+	maps.Copy(fields, n.Params.AdditionalFields)
+	// Decoded notifications carry an empty rather than a nil _meta, which
+	// must not become a non-nil Meta.
+	if len(n.Params.Meta) > 0 {
+		fields["_meta"] = n.Params.Meta
+	}
+	data, err := json.Marshal(fields)
+	if err == nil {
+		err = json.Unmarshal(data, params)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s params: %v", ErrInvalidParams, method, err)
+	}
+	return nil
+}
+
+func missingNotificationParam(method, name string) error {
+	return fmt.Errorf("%w: %s notification has no %s", ErrInvalidParams, method, name)
+}
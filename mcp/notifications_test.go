@@ -0,0 +1,214 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypedNotifications_RoundTrip(t *testing.T) {
+	total := 10.0
+	message := "halfway"
+	progressWithMeta := NewProgressNotification("token-1", 5, nil, nil)
+	progressWithMeta.Params.Meta = &Meta{AdditionalFields: map[string]any{"trace": "abc"}}
+
+	tests := []struct {
+		name         string
+		notification TypedNotification
+		wantJSON     string
+	}{
+		{
+			name:         "progress",
+			notification: NewProgressNotification("token-1", 5, &total, &message),
+			wantJSON:     `{"jsonrpc":"2.0","method":"notifications/progress","params":{"message":"halfway","progress":5,"progressToken":"token-1","total":10}}`,
+		},
+		{
+			name:         "progress with meta",
+			notification: progressWithMeta,
+			wantJSON:     `{"jsonrpc":"2.0","method":"notifications/progress","params":{"_meta":{"trace":"abc"},"progress":5,"progressToken":"token-1"}}`,
+		},
+		{
+			name:         "cancelled",
+			notification: NewCancelledNotification(NewRequestId(int64(7)), "user aborted"),
+			wantJSON:     `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"reason":"user aborted","requestId":7}}`,
+		},
+		{
+			name:         "logging message",
+			notification: NewLoggingMessageNotification(LoggingLevelWarning, "db", "slow query"),
+			wantJSON:     `{"jsonrpc":"2.0","method":"notifications/message","params":{"data":"slow query","level":"warning","logger":"db"}}`,
+		},
+		{
+			name:         "resource updated",
+			notification: NewResourceUpdatedNotification("file:///notes.txt"),
+			wantJSON:     `{"jsonrpc":"2.0","method":"notifications/resources/updated","params":{"uri":"file:///notes.txt"}}`,
+		},
+		{
+			name:         "resources list changed",
+			notification: NewResourceListChangedNotification(),
+			wantJSON:     `{"jsonrpc":"2.0","method":"notifications/resources/list_changed","params":{}}`,
+		},
+		{
+			name:         "tools list changed",
+			notification: NewToolListChangedNotification(),
+			wantJSON:     `{"jsonrpc":"2.0","method":"notifications/tools/list_changed","params":{}}`,
+		},
+		{
+			name:         "prompts list changed",
+			notification: NewPromptListChangedNotification(),
+			wantJSON:     `{"jsonrpc":"2.0","method":"notifications/prompts/list_changed","params":{}}`,
+		},
+		{
+			name:         "roots list changed",
+			notification: NewRootsListChangedNotification(),
+			wantJSON:     `{"jsonrpc":"2.0","method":"notifications/roots/list_changed","params":{}}`,
+		},
+		{
+			name:         "initialized",
+			notification: NewInitializedNotification(),
+			wantJSON:     `{"jsonrpc":"2.0","method":"notifications/initialized","params":{}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.notification.ToJSONRPCNotification())
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.wantJSON, string(data))
+
+			var decoded JSONRPCNotification
+			require.NoError(t, json.Unmarshal(data, &decoded))
+			parsed, err := ParseNotification(decoded)
+			require.NoError(t, err)
+			reencoded, err := json.Marshal(parsed.ToJSONRPCNotification())
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.wantJSON, string(reencoded))
+		})
+	}
+}
+
+func TestParseNotification_Typed(t *testing.T) {
+	decode := func(t *testing.T, data string) JSONRPCNotification {
+		t.Helper()
+		var n JSONRPCNotification
+		require.NoError(t, json.Unmarshal([]byte(data), &n))
+		return n
+	}
+
+	parsed, err := ParseNotification(decode(t, `{"jsonrpc":"2.0","method":"notifications/progress","params":{"progressToken":3,"progress":1.5,"_meta":{"trace":"abc"}}}`))
+	require.NoError(t, err)
+	progress, ok := parsed.(*ProgressNotification)
+	require.True(t, ok)
+	assert.Equal(t, MethodNotificationProgress, progress.Method)
+	assert.Equal(t, float64(3), progress.Params.ProgressToken)
+	assert.Equal(t, 1.5, progress.Params.Progress)
+	assert.Equal(t, "abc", progress.Params.Meta.Get("trace"))
+
+	parsed, err = ParseNotification(decode(t, `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":"req-1"}}`))
+	require.NoError(t, err)
+	cancelled, ok := parsed.(*CancelledNotification)
+	require.True(t, ok)
+	assert.Equal(t, NewRequestId("req-1"), cancelled.Params.RequestId)
+	assert.Nil(t, cancelled.Params.Meta, "an empty _meta is not decoded into a Meta")
+
+	// Notifications built in process parse without going through JSON.
+	updated, err := ParseResourceUpdatedNotification(NewResourceUpdatedNotification("file:///a").ToJSONRPCNotification())
+	require.NoError(t, err)
+	assert.Equal(t, "file:///a", updated.Params.URI)
+}
+
+func TestParseNotification_Errors(t *testing.T) {
+	notification := func(method string, params map[string]any) JSONRPCNotification {
+		return JSONRPCNotification{
+			JSONRPC:      JSONRPC_VERSION,
+			Notification: Notification{Method: method, Params: NotificationParams{AdditionalFields: params}},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		parse   func() error
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name: "unknown method",
+			parse: func() error {
+				_, err := ParseNotification(notification("notifications/custom", nil))
+				return err
+			},
+			wantErr: ErrMethodNotFound,
+			wantMsg: `method not found: "notifications/custom" is not a notification of the protocol`,
+		},
+		{
+			name: "other method",
+			parse: func() error {
+				_, err := ParseProgressNotification(notification(MethodNotificationCancelled, nil))
+				return err
+			},
+			wantMsg: `notification method is "notifications/cancelled", not "notifications/progress"`,
+		},
+		{
+			name: "missing progress token",
+			parse: func() error {
+				_, err := ParseProgressNotification(notification(MethodNotificationProgress, map[string]any{"progress": 1}))
+				return err
+			},
+			wantErr: ErrInvalidParams,
+			wantMsg: "invalid params: notifications/progress notification has no progressToken",
+		},
+		{
+			name: "missing request ID",
+			parse: func() error {
+				_, err := ParseCancelledNotification(notification(MethodNotificationCancelled, map[string]any{"reason": "none"}))
+				return err
+			},
+			wantErr: ErrInvalidParams,
+			wantMsg: "invalid params: notifications/cancelled notification has no requestId",
+		},
+		{
+			name: "missing level",
+			parse: func() error {
+				_, err := ParseLoggingMessageNotification(notification(MethodNotificationMessage, map[string]any{"data": "x"}))
+				return err
+			},
+			wantErr: ErrInvalidParams,
+			wantMsg: "invalid params: notifications/message notification has no level",
+		},
+		{
+			name: "missing URI",
+			parse: func() error {
+				_, err := ParseNotification(notification(MethodNotificationResourceUpdated, nil))
+				return err
+			},
+			wantErr: ErrInvalidParams,
+			wantMsg: "invalid params: notifications/resources/updated notification has no uri",
+		},
+		{
+			name: "wrong param type",
+			parse: func() error {
+				_, err := ParseResourceUpdatedNotification(notification(MethodNotificationResourceUpdated, map[string]any{"uri": 1}))
+				return err
+			},
+			wantErr: ErrInvalidParams,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.parse()
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			if tt.wantMsg != "" {
+				assert.EqualError(t, err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestParseNotification_FailureIsNil(t *testing.T) {
+	parsed, err := ParseNotification(JSONRPCNotification{Notification: Notification{Method: MethodNotificationProgress}})
+	require.Error(t, err)
+	assert.Nil(t, parsed)
+}
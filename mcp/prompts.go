@@ -1,6 +1,12 @@
 package mcp
 
-import "net/http"
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+)
 
 /* Prompts */
 
@@ -27,6 +33,8 @@ type GetPromptRequest struct {
 }
 
 type GetPromptParams struct {
+	// Meta is metadata attached to the request.
+	Meta *Meta `json:"_meta,omitempty"`
 	// The name of the prompt or prompt template.
 	Name string `json:"name"`
 	// Arguments to use for templating the prompt.
@@ -56,6 +64,11 @@ type Prompt struct {
 	// A list of arguments to use for templating the prompt.
 	// The presence of arguments indicates this is a template prompt.
 	Arguments []PromptArgument `json:"arguments,omitempty"`
+	// RawArgumentsSchema is an optional JSON Schema the arguments of
+	// prompts/get requests are validated against. Prompt arguments are
+	// strings, so it should describe an object of string properties. It is
+	// not sent to clients, which see Arguments instead.
+	RawArgumentsSchema json.RawMessage `json:"-"`
 }
 
 // GetName returns the name of the prompt.
@@ -74,6 +87,9 @@ type PromptArgument struct {
 	// Whether this argument must be provided.
 	// If true, clients must include this argument when calling prompts/get.
 	Required bool `json:"required,omitempty"`
+	// Completion completes the values of the argument for completion/complete
+	// requests. It is not sent to clients.
+	Completion CompletionFunc `json:"-"`
 }
 
 // Role represents the sender or recipient of messages and data in a
@@ -94,6 +110,25 @@ type PromptMessage struct {
 	Content Content `json:"content"` // Can be TextContent, ImageContent, AudioContent or EmbeddedResource
 }
 
+// UnmarshalJSON implements custom JSON unmarshaling for PromptMessage,
+// decoding the content into its concrete type.
+func (m *PromptMessage) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Role    Role            `json:"role"`
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	content, err := UnmarshalContent(raw.Content)
+	if err != nil {
+		return err
+	}
+	m.Role = raw.Role
+	m.Content = content
+	return nil
+}
+
 // PromptListChangedNotification is an optional notification from the server
 // to the client, informing it that the list of prompts it offers has changed. This
 // may be issued by servers without any previous subscription from the client.
@@ -155,6 +190,64 @@ func WithArgument(name string, opts ...ArgumentOption) PromptOption {
 	}
 }
 
+// WithRawArgumentsSchema sets a hand-written JSON Schema for the arguments of
+// the prompt, such as one produced by an external generator, in place of
+// WithArgument. The listed arguments are derived from its top-level
+// properties, their descriptions and its required list; servers validate
+// prompts/get arguments against it.
+func WithRawArgumentsSchema(schema json.RawMessage) PromptOption {
+	return func(p *Prompt) {
+		p.RawArgumentsSchema = schema
+
+		var parsed struct {
+			Properties map[string]struct {
+				Description string `json:"description"`
+			} `json:"properties"`
+			Required []string `json:"required"`
+		}
+		if err := json.Unmarshal(schema, &parsed); err != nil {
+			return
+		}
+		names := make([]string, 0, len(parsed.Properties))
+		for name := range parsed.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		p.Arguments = make([]PromptArgument, 0, len(names))
+		for _, name := range names {
+			p.Arguments = append(p.Arguments, PromptArgument{
+				Name:        name,
+				Description: parsed.Properties[name].Description,
+				Required:    slices.Contains(parsed.Required, name),
+			})
+		}
+	}
+}
+
+// ValidateSchema checks that the raw arguments schema of the prompt, if any,
+// is a well-formed JSON Schema.
+func (p Prompt) ValidateSchema() error {
+	if p.RawArgumentsSchema == nil {
+		return nil
+	}
+	if err := ValidateSchema(p.RawArgumentsSchema); err != nil {
+		return fmt.Errorf("arguments schema of prompt %q: %w", p.Name, err)
+	}
+	return nil
+}
+
+// ValidateArguments checks args against the raw arguments schema of the
+// prompt. Prompts without one accept any arguments.
+func (p Prompt) ValidateArguments(args map[string]string) ([]SchemaError, error) {
+	if p.RawArgumentsSchema == nil {
+		return nil, nil
+	}
+	if args == nil {
+		args = map[string]string{}
+	}
+	return ValidateAgainstSchema(p.RawArgumentsSchema, args)
+}
+
 //
 // Argument Options
 //
@@ -174,3 +267,13 @@ func RequiredArgument() ArgumentOption {
 		arg.Required = true
 	}
 }
+
+// WithArgumentCompletion sets the function completing the values of the
+// argument. Servers call it for the completion/complete requests of the
+// argument, and advertise the completions capability once a prompt with a
+// completed argument is registered.
+func WithArgumentCompletion(complete CompletionFunc) ArgumentOption {
+	return func(arg *PromptArgument) {
+		arg.Completion = complete
+	}
+}
@@ -285,3 +285,80 @@ func TestPromptJSONStructure(t *testing.T) {
 	assert.Equal(t, "Second argument", arg2["description"])
 	// Optional arguments may not have "required" field or it's false
 }
+
+func TestPromptMessageUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  PromptMessage
+		expected Content
+	}{
+		{
+			name:     "text",
+			message:  NewPromptMessage(RoleUser, NewTextContent("hello")),
+			expected: NewTextContent("hello"),
+		},
+		{
+			name:     "audio",
+			message:  NewPromptMessage(RoleUser, NewAudioContent("UklGRg==", "audio/wav")),
+			expected: NewAudioContent("UklGRg==", "audio/wav"),
+		},
+		{
+			name:     "embedded resource",
+			message:  NewPromptMessage(RoleAssistant, NewEmbeddedResource(TextResourceContents{URI: "file:///a.txt", Text: "a"})),
+			expected: NewEmbeddedResource(TextResourceContents{URI: "file:///a.txt", Text: "a"}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.message)
+			require.NoError(t, err)
+
+			var message PromptMessage
+			require.NoError(t, json.Unmarshal(data, &message))
+			assert.Equal(t, tt.message.Role, message.Role)
+			assert.Equal(t, tt.expected, message.Content)
+		})
+	}
+
+	var message PromptMessage
+	assert.Error(t, json.Unmarshal([]byte(`{"role":"user","content":{"type":"video"}}`), &message))
+}
+
+func TestWithRawArgumentsSchema(t *testing.T) {
+	prompt := NewPrompt("review", WithRawArgumentsSchema(json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"language": {"type": "string", "description": "Programming language", "enum": ["go", "rust"]},
+			"code": {"type": "string", "minLength": 1}
+		},
+		"required": ["code"]
+	}`)))
+	assert.Equal(t, []PromptArgument{
+		{Name: "code", Required: true},
+		{Name: "language", Description: "Programming language"},
+	}, prompt.Arguments)
+	require.NoError(t, prompt.ValidateSchema())
+
+	data, err := json.Marshal(prompt)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "minLength")
+
+	schemaErrs, err := prompt.ValidateArguments(map[string]string{"code": "x", "language": "go"})
+	require.NoError(t, err)
+	assert.Empty(t, schemaErrs)
+
+	schemaErrs, err = prompt.ValidateArguments(map[string]string{"language": "java"})
+	require.NoError(t, err)
+	assert.Equal(t, []SchemaError{
+		{Path: "/code", Message: "required property is missing"},
+		{Path: "/language", Message: `value "java" is not one of the allowed values`},
+	}, schemaErrs)
+
+	invalid := NewPrompt("bad", WithRawArgumentsSchema(json.RawMessage(`{"type":"map"}`)))
+	assert.ErrorContains(t, invalid.ValidateSchema(), `arguments schema of prompt "bad": invalid schema`)
+
+	schemaErrs, err = NewPrompt("plain").ValidateArguments(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, schemaErrs)
+}
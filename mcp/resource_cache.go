@@ -0,0 +1,119 @@
+package mcp
+
+import "time"
+
+// The _meta keys of conditional resource reads. These are mcp-go
+// extensions and not part of the MCP specification.
+const (
+	// ETagMetaKey is the _meta key of a ReadResourceResult holding the
+	// entity tag of the contents.
+	ETagMetaKey = "etag"
+	// LastModifiedMetaKey is the _meta key of a ReadResourceResult holding
+	// the RFC 3339 time the contents last changed.
+	LastModifiedMetaKey = "lastModified"
+	// IfNoneMatchMetaKey is the _meta key of a ReadResourceRequest holding
+	// the entity tag of the contents the client has.
+	IfNoneMatchMetaKey = "ifNoneMatch"
+	// IfModifiedSinceMetaKey is the _meta key of a ReadResourceRequest
+	// holding the RFC 3339 last modified time of the contents the client
+	// has.
+	IfModifiedSinceMetaKey = "ifModifiedSince"
+	// NotModifiedMetaKey is the _meta key of a ReadResourceResult set to
+	// true when the contents did not change since the validators of the
+	// request. The result then has no contents.
+	NotModifiedMetaKey = "notModified"
+)
+
+// ResourceValidators identify a version of the contents of a resource.
+type ResourceValidators struct {
+	// ETag is an opaque tag of the contents, changing whenever they do.
+	ETag string
+	// LastModified is the time the contents last changed.
+	LastModified time.Time
+}
+
+// IsZero reports whether v holds no validator.
+func (v ResourceValidators) IsZero() bool {
+	return v.ETag == "" && v.LastModified.IsZero()
+}
+
+// Matches reports whether contents with the validators v are unchanged
+// since those of a conditional request. As in HTTP, the entity tag is
+// compared when the request has one, and the last modified time, with a
+// precision of a second, otherwise.
+func (v ResourceValidators) Matches(request ResourceValidators) bool {
+	if request.ETag != "" {
+		return v.ETag != "" && v.ETag == request.ETag
+	}
+	if request.LastModified.IsZero() || v.LastModified.IsZero() {
+		return false
+	}
+	return !v.LastModified.Truncate(time.Second).After(request.LastModified.Truncate(time.Second))
+}
+
+// ResourceValidatorsFromResult returns the validators of the contents of
+// result.
+func ResourceValidatorsFromResult(result *ReadResourceResult) ResourceValidators {
+	if result == nil {
+		return ResourceValidators{}
+	}
+	return validatorsFromMeta(result.Meta, ETagMetaKey, LastModifiedMetaKey)
+}
+
+// SetResourceValidators sets the validators of the contents of result.
+func SetResourceValidators(result *ReadResourceResult, validators ResourceValidators) {
+	setValidatorsMeta(&result.Meta, validators, ETagMetaKey, LastModifiedMetaKey)
+}
+
+// ConditionalReadValidators returns the validators of the contents a
+// client sent a conditional read for.
+func ConditionalReadValidators(request ReadResourceRequest) ResourceValidators {
+	return validatorsFromMeta(request.Params.Meta, IfNoneMatchMetaKey, IfModifiedSinceMetaKey)
+}
+
+// SetConditionalRead makes request conditional on the contents having
+// changed since validators. Servers supporting conditional reads answer
+// it with a result for which IsNotModified is true if they did not.
+func SetConditionalRead(request *ReadResourceRequest, validators ResourceValidators) {
+	setValidatorsMeta(&request.Params.Meta, validators, IfNoneMatchMetaKey, IfModifiedSinceMetaKey)
+}
+
+// IsNotModified reports whether result answers a conditional read of
+// contents that did not change.
+func IsNotModified(result *ReadResourceResult) bool {
+	if result == nil || result.Meta == nil {
+		return false
+	}
+	notModified, _ := result.Meta.AdditionalFields[NotModifiedMetaKey].(bool)
+	return notModified
+}
+
+func validatorsFromMeta(meta *Meta, etagKey, timeKey string) ResourceValidators {
+	var validators ResourceValidators
+	if meta == nil {
+		return validators
+	}
+	validators.ETag, _ = meta.AdditionalFields[etagKey].(string)
+	if value, ok := meta.AdditionalFields[timeKey].(string); ok {
+		validators.LastModified, _ = time.Parse(time.RFC3339, value)
+	}
+	return validators
+}
+
+func setValidatorsMeta(meta **Meta, validators ResourceValidators, etagKey, timeKey string) {
+	if validators.IsZero() {
+		return
+	}
+	if *meta == nil {
+		*meta = &Meta{}
+	}
+	if (*meta).AdditionalFields == nil {
+		(*meta).AdditionalFields = make(map[string]any)
+	}
+	if validators.ETag != "" {
+		(*meta).AdditionalFields[etagKey] = validators.ETag
+	}
+	if !validators.LastModified.IsZero() {
+		(*meta).AdditionalFields[timeKey] = validators.LastModified.UTC().Format(time.RFC3339)
+	}
+}
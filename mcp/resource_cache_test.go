@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceValidators_Matches(t *testing.T) {
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		validators ResourceValidators
+		request    ResourceValidators
+		expected   bool
+	}{
+		{name: "same etag", validators: ResourceValidators{ETag: `"a"`}, request: ResourceValidators{ETag: `"a"`}, expected: true},
+		{name: "other etag", validators: ResourceValidators{ETag: `"a"`}, request: ResourceValidators{ETag: `"b"`}},
+		{
+			name:       "etag takes precedence",
+			validators: ResourceValidators{ETag: `"a"`, LastModified: modified},
+			request:    ResourceValidators{ETag: `"b"`, LastModified: modified},
+		},
+		{name: "not modified since", validators: ResourceValidators{LastModified: modified}, request: ResourceValidators{LastModified: modified.Add(500 * time.Millisecond)}, expected: true},
+		{name: "modified since", validators: ResourceValidators{LastModified: modified}, request: ResourceValidators{LastModified: modified.Add(-time.Second)}},
+		{name: "no validators", validators: ResourceValidators{}, request: ResourceValidators{LastModified: modified}},
+		{name: "unconditional", validators: ResourceValidators{ETag: `"a"`}, request: ResourceValidators{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.validators.Matches(tt.request))
+		})
+	}
+}
+
+func TestResourceValidators_Meta(t *testing.T) {
+	validators := ResourceValidators{ETag: `"v1"`, LastModified: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+
+	request := ReadResourceRequest{}
+	SetConditionalRead(&request, validators)
+	assert.Equal(t, map[string]any{
+		IfNoneMatchMetaKey:     `"v1"`,
+		IfModifiedSinceMetaKey: "2024-05-01T12:00:00Z",
+	}, request.Params.Meta.AdditionalFields)
+	assert.Equal(t, validators, ConditionalReadValidators(request))
+
+	result := &ReadResourceResult{}
+	SetResourceValidators(result, validators)
+	assert.Equal(t, validators, ResourceValidatorsFromResult(result))
+	assert.False(t, IsNotModified(result))
+
+	result.Meta.AdditionalFields[NotModifiedMetaKey] = true
+	assert.True(t, IsNotModified(result))
+}
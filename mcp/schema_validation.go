@@ -0,0 +1,636 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SchemaError describes a single place where a value does not conform to
+// a JSON Schema.
+type SchemaError struct {
+	// Path is a JSON Pointer to the offending value; empty for the root.
+	Path string `json:"path,omitempty"`
+	// Message describes the violation.
+	Message string `json:"message"`
+}
+
+func (e SchemaError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// ValidateAgainstSchema checks value against a JSON Schema and returns every
+// violation found. The schema may be a json.RawMessage, []byte, a decoded
+// map or any value that marshals to a JSON Schema object, such as
+// ToolInputSchema. Value should be JSON-shaped (as produced by
+// encoding/json); other Go values are round-tripped through JSON first.
+//
+// The supported keywords are type, enum, const, properties, required,
+// additionalProperties, items, minItems, maxItems, minLength, maxLength,
+// pattern, minimum, maximum, anyOf, oneOf, allOf and local $ref pointers
+// into $defs or definitions. Unknown keywords are ignored. A pattern that
+// Go's regexp package cannot compile, such as an ECMA-262 lookahead, is an
+// error once a string is checked against it.
+func ValidateAgainstSchema(schema any, value any) ([]SchemaError, error) {
+	root, err := toJSONValue(schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	rootSchema, ok := root.(map[string]any)
+	if !ok {
+		if b, isBool := root.(bool); isBool {
+			if b {
+				return nil, nil
+			}
+			return []SchemaError{{Message: "no value is allowed"}}, nil
+		}
+		return nil, fmt.Errorf("invalid schema: expected object, got %T", root)
+	}
+	v, err := toJSONValue(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value: %w", err)
+	}
+	validator := &schemaValidator{root: rootSchema}
+	validator.validate("", rootSchema, v, 0)
+	if validator.err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", validator.err)
+	}
+	return validator.errs, nil
+}
+
+// ValidateArguments checks args against the tool's input schema. A nil args
+// value is validated as an empty object.
+func (t Tool) ValidateArguments(args any) ([]SchemaError, error) {
+	if args == nil {
+		args = map[string]any{}
+	}
+	if t.RawInputSchema != nil {
+		return ValidateAgainstSchema(t.RawInputSchema, args)
+	}
+	return ValidateAgainstSchema(t.InputSchema, args)
+}
+
+// ErrInvalidSchema is wrapped by the errors of ValidateSchema.
+var ErrInvalidSchema = errors.New("invalid schema")
+
+// ValidateSchema checks that schema is a well-formed JSON Schema, accepting
+// the same forms as ValidateAgainstSchema. Keywords known to
+// ValidateAgainstSchema must have values of the right shape and local $ref
+// pointers must resolve. Patterns are not compiled, since Go's regexp
+// package does not accept every ECMA-262 pattern. Unknown keywords are
+// ignored. The returned error wraps ErrInvalidSchema and lists every problem
+// found.
+func ValidateSchema(schema any) error {
+	root, err := toJSONValue(schema)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSchema, err)
+	}
+	rootSchema, _ := root.(map[string]any)
+	checker := &schemaChecker{schemaValidator{root: rootSchema}}
+	checker.check("", root, 0)
+	if len(checker.errs) == 0 {
+		return nil
+	}
+	messages := make([]string, len(checker.errs))
+	for i, e := range checker.errs {
+		messages[i] = e.Error()
+	}
+	return fmt.Errorf("%w: %s", ErrInvalidSchema, strings.Join(messages, "; "))
+}
+
+// ValidateSchema checks that the raw input and output schemas of the tool,
+// if any, are well-formed JSON Schemas. Schemas built with ToolOptions are
+// not checked.
+func (t Tool) ValidateSchema() error {
+	if t.RawInputSchema != nil {
+		if err := ValidateSchema(t.RawInputSchema); err != nil {
+			return fmt.Errorf("input schema of tool %q: %w", t.Name, err)
+		}
+	}
+	if t.RawOutputSchema != nil {
+		if err := ValidateSchema(t.RawOutputSchema); err != nil {
+			return fmt.Errorf("output schema of tool %q: %w", t.Name, err)
+		}
+	}
+	return nil
+}
+
+// maxSchemaDepth bounds $ref resolution so recursive schemas cannot loop forever.
+const maxSchemaDepth = 64
+
+type schemaValidator struct {
+	root map[string]any
+	errs []SchemaError
+	// err is the first problem of the schema itself that was found.
+	err error
+}
+
+func (v *schemaValidator) fail(path, format string, args ...any) {
+	v.errs = append(v.errs, SchemaError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *schemaValidator) validate(path string, schema map[string]any, value any, depth int) {
+	if depth > maxSchemaDepth {
+		v.fail(path, "schema nesting too deep")
+		return
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		target, found := v.resolveRef(ref)
+		if !found {
+			v.fail(path, "unresolvable $ref %q", ref)
+			return
+		}
+		v.validateSub(path, target, value, depth+1)
+	}
+
+	if !v.checkType(path, schema, value) {
+		// Further keyword checks would only repeat the type violation
+		return
+	}
+
+	if enum, ok := schema["enum"].([]any); ok {
+		if !containsJSONValue(enum, value) {
+			v.fail(path, "value %s is not one of the allowed values", formatJSONValue(value))
+		}
+	}
+	if c, ok := schema["const"]; ok && !reflect.DeepEqual(c, value) {
+		v.fail(path, "value %s does not equal the constant %s", formatJSONValue(value), formatJSONValue(c))
+	}
+
+	switch val := value.(type) {
+	case map[string]any:
+		v.validateObject(path, schema, val, depth)
+	case []any:
+		v.validateArray(path, schema, val, depth)
+	case string:
+		v.validateString(path, schema, val)
+	case float64:
+		v.validateNumber(path, schema, val)
+	}
+
+	v.validateCombinators(path, schema, value, depth)
+}
+
+func (v *schemaValidator) validateSub(path string, schema any, value any, depth int) {
+	switch s := schema.(type) {
+	case map[string]any:
+		v.validate(path, s, value, depth)
+	case bool:
+		if !s {
+			v.fail(path, "no value is allowed")
+		}
+	}
+}
+
+func (v *schemaValidator) resolveRef(ref string) (any, bool) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, false
+	}
+	var current any = v.root
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[token]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func (v *schemaValidator) checkType(path string, schema map[string]any, value any) bool {
+	var types []string
+	switch t := schema["type"].(type) {
+	case string:
+		types = []string{t}
+	case []any:
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+	default:
+		return true
+	}
+	for _, t := range types {
+		if jsonTypeMatches(t, value) {
+			return true
+		}
+	}
+	v.fail(path, "expected %s, got %s", strings.Join(types, " or "), jsonTypeName(value))
+	return false
+}
+
+func (v *schemaValidator) validateObject(path string, schema map[string]any, value map[string]any, depth int) {
+	if required, ok := schema["required"].([]any); ok {
+		for _, name := range required {
+			if s, ok := name.(string); ok {
+				if _, present := value[s]; !present {
+					v.fail(joinPointer(path, s), "required property is missing")
+				}
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	additional, hasAdditional := schema["additionalProperties"]
+
+	keys := make([]string, 0, len(value))
+	for k := range value {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		childPath := joinPointer(path, k)
+		if propSchema, ok := properties[k]; ok {
+			v.validateSub(childPath, propSchema, value[k], depth+1)
+			continue
+		}
+		if hasAdditional {
+			if allowed, ok := additional.(bool); ok && !allowed {
+				v.fail(childPath, "additional property is not allowed")
+				continue
+			}
+			v.validateSub(childPath, additional, value[k], depth+1)
+		}
+	}
+}
+
+func (v *schemaValidator) validateArray(path string, schema map[string]any, value []any, depth int) {
+	if minItems, ok := schema["minItems"].(float64); ok && float64(len(value)) < minItems {
+		v.fail(path, "expected at least %v items, got %d", minItems, len(value))
+	}
+	if maxItems, ok := schema["maxItems"].(float64); ok && float64(len(value)) > maxItems {
+		v.fail(path, "expected at most %v items, got %d", maxItems, len(value))
+	}
+	if items, ok := schema["items"]; ok {
+		for i, item := range value {
+			v.validateSub(joinPointer(path, fmt.Sprint(i)), items, item, depth+1)
+		}
+	}
+}
+
+func (v *schemaValidator) validateString(path string, schema map[string]any, value string) {
+	length := float64(len([]rune(value)))
+	if minLength, ok := schema["minLength"].(float64); ok && length < minLength {
+		v.fail(path, "expected at least %v characters, got %v", minLength, length)
+	}
+	if maxLength, ok := schema["maxLength"].(float64); ok && length > maxLength {
+		v.fail(path, "expected at most %v characters, got %v", maxLength, length)
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		re, err := compilePattern(pattern)
+		if err != nil {
+			if v.err == nil {
+				v.err = fmt.Errorf("%s: invalid pattern %q: %w", joinPointer(path, "pattern"), pattern, err)
+			}
+		} else if !re.MatchString(value) {
+			v.fail(path, "value does not match pattern %q", pattern)
+		}
+	}
+}
+
+// patterns caches the compiled patterns of schemas, keyed by pattern.
+var patterns sync.Map
+
+type compiledPattern struct {
+	re  *regexp.Regexp
+	err error
+}
+
+// compilePattern compiles a schema pattern once. Patterns use ECMA-262
+// syntax; regexp cannot compile some of them, such as lookaheads and
+// backreferences.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := patterns.Load(pattern); ok {
+		return cached.(compiledPattern).re, cached.(compiledPattern).err
+	}
+	re, err := regexp.Compile(pattern)
+	patterns.Store(pattern, compiledPattern{re: re, err: err})
+	return re, err
+}
+
+func (v *schemaValidator) validateNumber(path string, schema map[string]any, value float64) {
+	if minimum, ok := schema["minimum"].(float64); ok && value < minimum {
+		v.fail(path, "value %v is less than the minimum %v", value, minimum)
+	}
+	if maximum, ok := schema["maximum"].(float64); ok && value > maximum {
+		v.fail(path, "value %v is greater than the maximum %v", value, maximum)
+	}
+}
+
+func (v *schemaValidator) validateCombinators(path string, schema map[string]any, value any, depth int) {
+	if allOf, ok := schema["allOf"].([]any); ok {
+		for _, sub := range allOf {
+			v.validateSub(path, sub, value, depth+1)
+		}
+	}
+	if anyOf, ok := schema["anyOf"].([]any); ok {
+		if v.countMatches(path, anyOf, value, depth) == 0 {
+			v.fail(path, "value does not match any of the allowed schemas")
+		}
+	}
+	if oneOf, ok := schema["oneOf"].([]any); ok {
+		if n := v.countMatches(path, oneOf, value, depth); n != 1 {
+			v.fail(path, "value must match exactly one schema, matched %d", n)
+		}
+	}
+}
+
+func (v *schemaValidator) countMatches(path string, schemas []any, value any, depth int) int {
+	matches := 0
+	for _, sub := range schemas {
+		probe := &schemaValidator{root: v.root}
+		probe.validateSub(path, sub, value, depth+1)
+		if probe.err != nil && v.err == nil {
+			v.err = probe.err
+		}
+		if len(probe.errs) == 0 {
+			matches++
+		}
+	}
+	return matches
+}
+
+func toJSONValue(v any) (any, error) {
+	var data []byte
+	switch val := v.(type) {
+	case nil:
+		return nil, nil
+	case json.RawMessage:
+		data = val
+	case []byte:
+		data = val
+	case string, float64, bool:
+		return val, nil
+	default:
+		var err error
+		if data, err = json.Marshal(val); err != nil {
+			return nil, err
+		}
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func jsonTypeMatches(t string, value any) bool {
+	switch t {
+	case "null":
+		return value == nil
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	}
+	return false
+}
+
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func containsJSONValue(values []any, value any) bool {
+	for _, candidate := range values {
+		if reflect.DeepEqual(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatJSONValue(value any) string {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(b)
+}
+
+func joinPointer(path, token string) string {
+	token = strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+	return path + "/" + token
+}
+
+// schemaChecker checks the well-formedness of a schema.
+type schemaChecker struct {
+	schemaValidator
+}
+
+var (
+	// schemaKeywords are the keywords whose value is a schema.
+	schemaKeywords = []string{"additionalProperties", "additionalItems", "contains", "propertyNames", "not", "if", "then", "else"}
+	// schemaMapKeywords are the keywords whose value maps names to schemas.
+	schemaMapKeywords = []string{"properties", "patternProperties", "$defs", "definitions"}
+	// schemaListKeywords are the keywords whose value is a non-empty list of
+	// schemas.
+	schemaListKeywords = []string{"allOf", "anyOf", "oneOf"}
+	// countKeywords are the keywords whose value is a non-negative integer.
+	countKeywords = []string{"minLength", "maxLength", "minItems", "maxItems", "minProperties", "maxProperties"}
+	// numberKeywords are the keywords whose value is a number.
+	numberKeywords = []string{"minimum", "maximum", "multipleOf"}
+	// exclusiveKeywords are the keywords whose value is a number, or a
+	// boolean in draft 4 schemas.
+	exclusiveKeywords = []string{"exclusiveMinimum", "exclusiveMaximum"}
+	// stringKeywords are the keywords whose value is a string.
+	stringKeywords = []string{"title", "description", "$schema", "$id", "format"}
+)
+
+func (c *schemaChecker) check(path string, node any, depth int) {
+	if depth > maxSchemaDepth {
+		c.fail(path, "schema nesting too deep")
+		return
+	}
+	schema, ok := node.(map[string]any)
+	if !ok {
+		if _, isBool := node.(bool); !isBool {
+			c.fail(path, "expected a schema object or boolean, got %s", jsonTypeName(node))
+		}
+		return
+	}
+
+	if t, ok := schema["type"]; ok {
+		c.checkTypeKeyword(joinPointer(path, "type"), t)
+	}
+	if required, ok := schema["required"]; ok {
+		c.checkStrings(joinPointer(path, "required"), required)
+	}
+	if enum, ok := schema["enum"]; ok {
+		if _, isArray := enum.([]any); !isArray {
+			c.fail(joinPointer(path, "enum"), "expected an array, got %s", jsonTypeName(enum))
+		}
+	}
+	if pattern, ok := schema["pattern"]; ok {
+		if _, isString := pattern.(string); !isString {
+			c.fail(joinPointer(path, "pattern"), "expected a string, got %s", jsonTypeName(pattern))
+		}
+	}
+	if ref, ok := schema["$ref"]; ok {
+		if s, isString := ref.(string); !isString {
+			c.fail(joinPointer(path, "$ref"), "expected a string, got %s", jsonTypeName(ref))
+		} else if strings.HasPrefix(s, "#") {
+			if _, found := c.resolveRef(s); !found {
+				c.fail(joinPointer(path, "$ref"), "unresolvable $ref %q", s)
+			}
+		}
+	}
+	for _, keyword := range countKeywords {
+		if value, ok := schema[keyword]; ok {
+			if n, isNumber := value.(float64); !isNumber || n < 0 || n != math.Trunc(n) {
+				c.fail(joinPointer(path, keyword), "expected a non-negative integer, got %s", formatJSONValue(value))
+			}
+		}
+	}
+	for _, keyword := range numberKeywords {
+		if value, ok := schema[keyword]; ok {
+			if _, isNumber := value.(float64); !isNumber {
+				c.fail(joinPointer(path, keyword), "expected a number, got %s", jsonTypeName(value))
+			}
+		}
+	}
+	for _, keyword := range exclusiveKeywords {
+		if value, ok := schema[keyword]; ok {
+			switch value.(type) {
+			case float64, bool:
+			default:
+				c.fail(joinPointer(path, keyword), "expected a number or boolean, got %s", jsonTypeName(value))
+			}
+		}
+	}
+	if n, ok := schema["multipleOf"].(float64); ok && n <= 0 {
+		c.fail(joinPointer(path, "multipleOf"), "expected a positive number, got %v", n)
+	}
+	for _, keyword := range stringKeywords {
+		if value, ok := schema[keyword]; ok {
+			if _, isString := value.(string); !isString {
+				c.fail(joinPointer(path, keyword), "expected a string, got %s", jsonTypeName(value))
+			}
+		}
+	}
+
+	for _, keyword := range schemaKeywords {
+		if sub, ok := schema[keyword]; ok {
+			c.check(joinPointer(path, keyword), sub, depth+1)
+		}
+	}
+	if items, ok := schema["items"]; ok {
+		if list, isList := items.([]any); isList {
+			c.checkList(joinPointer(path, "items"), list, depth)
+		} else {
+			c.check(joinPointer(path, "items"), items, depth+1)
+		}
+	}
+	for _, keyword := range schemaMapKeywords {
+		value, ok := schema[keyword]
+		if !ok {
+			continue
+		}
+		subs, isMap := value.(map[string]any)
+		if !isMap {
+			c.fail(joinPointer(path, keyword), "expected an object, got %s", jsonTypeName(value))
+			continue
+		}
+		names := make([]string, 0, len(subs))
+		for name := range subs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			c.check(joinPointer(joinPointer(path, keyword), name), subs[name], depth+1)
+		}
+	}
+	for _, keyword := range schemaListKeywords {
+		value, ok := schema[keyword]
+		if !ok {
+			continue
+		}
+		list, isList := value.([]any)
+		if !isList || len(list) == 0 {
+			c.fail(joinPointer(path, keyword), "expected a non-empty array of schemas")
+			continue
+		}
+		c.checkList(joinPointer(path, keyword), list, depth)
+	}
+}
+
+func (c *schemaChecker) checkList(path string, list []any, depth int) {
+	for i, sub := range list {
+		c.check(joinPointer(path, fmt.Sprint(i)), sub, depth+1)
+	}
+}
+
+func (c *schemaChecker) checkTypeKeyword(path string, value any) {
+	types, isList := value.([]any)
+	if !isList {
+		types = []any{value}
+	} else if len(types) == 0 {
+		c.fail(path, "expected at least one type")
+	}
+	for _, t := range types {
+		name, _ := t.(string)
+		switch name {
+		case "null", "boolean", "string", "number", "integer", "array", "object":
+		default:
+			c.fail(path, "unknown type %s", formatJSONValue(t))
+		}
+	}
+}
+
+func (c *schemaChecker) checkStrings(path string, value any) {
+	list, ok := value.([]any)
+	if !ok {
+		c.fail(path, "expected an array of strings, got %s", jsonTypeName(value))
+		return
+	}
+	for i, item := range list {
+		if _, isString := item.(string); !isString {
+			c.fail(joinPointer(path, fmt.Sprint(i)), "expected a string, got %s", jsonTypeName(item))
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAgainstSchema(t *testing.T) {
+	schema := json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 2, "pattern": "^[a-z]+$"},
+			"age": {"type": "integer", "minimum": 0, "maximum": 150},
+			"color": {"enum": ["red", "green"]},
+			"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2},
+			"address": {"$ref": "#/$defs/address"},
+			"id": {"oneOf": [{"type": "string"}, {"type": "integer"}]}
+		},
+		"required": ["name"],
+		"additionalProperties": false,
+		"$defs": {
+			"address": {
+				"type": "object",
+				"properties": {"city": {"type": "string"}},
+				"required": ["city"]
+			}
+		}
+	}`)
+
+	tests := []struct {
+		name     string
+		value    any
+		expected []SchemaError
+	}{
+		{
+			name:  "valid",
+			value: map[string]any{"name": "alice", "age": 30, "color": "red", "tags": []string{"a"}, "address": map[string]any{"city": "Paris"}, "id": 7},
+		},
+		{
+			name:     "missing required",
+			value:    map[string]any{},
+			expected: []SchemaError{{Path: "/name", Message: "required property is missing"}},
+		},
+		{
+			name:  "type and range violations",
+			value: map[string]any{"name": "a", "age": 1.5, "tags": []any{"a", 1, "c"}},
+			expected: []SchemaError{
+				{Path: "/age", Message: "expected integer, got number"},
+				{Path: "/name", Message: "expected at least 2 characters, got 1"},
+				{Path: "/tags", Message: "expected at most 2 items, got 3"},
+				{Path: "/tags/1", Message: "expected string, got number"},
+			},
+		},
+		{
+			name:  "enum, pattern, ref and additional properties",
+			value: map[string]any{"name": "Bob", "color": "blue", "address": map[string]any{}, "extra": true},
+			expected: []SchemaError{
+				{Path: "/address/city", Message: "required property is missing"},
+				{Path: "/color", Message: `value "blue" is not one of the allowed values`},
+				{Path: "/extra", Message: "additional property is not allowed"},
+				{Path: "/name", Message: `value does not match pattern "^[a-z]+$"`},
+			},
+		},
+		{
+			name:     "oneOf mismatch",
+			value:    map[string]any{"name": "bob", "id": true},
+			expected: []SchemaError{{Path: "/id", Message: "value must match exactly one schema, matched 0"}},
+		},
+		{
+			name:     "wrong root type",
+			value:    []any{},
+			expected: []SchemaError{{Message: "expected object, got array"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs, err := ValidateAgainstSchema(schema, tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, errs)
+		})
+	}
+}
+
+func TestValidateAgainstSchema_InvalidSchema(t *testing.T) {
+	_, err := ValidateAgainstSchema(json.RawMessage(`[1, 2]`), nil)
+	assert.Error(t, err)
+
+	_, err = ValidateAgainstSchema(json.RawMessage(`{`), nil)
+	assert.Error(t, err)
+
+	errs, err := ValidateAgainstSchema(json.RawMessage(`false`), "x")
+	require.NoError(t, err)
+	assert.Len(t, errs, 1)
+}
+
+func TestTool_ValidateArguments(t *testing.T) {
+	tool := NewTool("search",
+		WithString("query", Required()),
+		WithNumber("limit", Min(1)),
+		WithString("mode", Enum("fast", "exact")),
+	)
+
+	errs, err := tool.ValidateArguments(map[string]any{"query": "go", "limit": 10, "mode": "fast"})
+	require.NoError(t, err)
+	assert.Empty(t, errs)
+
+	errs, err = tool.ValidateArguments(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []SchemaError{{Path: "/query", Message: "required property is missing"}}, errs)
+
+	errs, err = tool.ValidateArguments(map[string]any{"query": "go", "limit": 0, "mode": "slow"})
+	require.NoError(t, err)
+	assert.Len(t, errs, 2)
+
+	raw := NewToolWithRawSchema("raw", "", json.RawMessage(`{"type":"object","required":["id"]}`))
+	errs, err = raw.ValidateArguments(map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, []SchemaError{{Path: "/id", Message: "required property is missing"}}, errs)
+}
+
+func TestValidateSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			schema: `{
+				"type": "object",
+				"properties": {
+					"name": {"type": ["string", "null"], "minLength": 1, "pattern": "^[a-z]+$"},
+					"tags": {"type": "array", "items": {"type": "string"}},
+					"address": {"$ref": "#/$defs/address"},
+					"limit": {"type": "integer", "exclusiveMinimum": 0}
+				},
+				"required": ["name"],
+				"additionalProperties": false,
+				"$defs": {"address": {"type": "object", "x-custom": 1}}
+			}`,
+		},
+		{name: "boolean", schema: `true`},
+		{name: "not json", schema: `{`, wantErr: "invalid schema: unexpected end of JSON input"},
+		{name: "not an object", schema: `[]`, wantErr: "invalid schema: expected a schema object or boolean, got array"},
+		{name: "unknown type", schema: `{"type":"text"}`, wantErr: `invalid schema: /type: unknown type "text"`},
+		{name: "required flag", schema: `{"properties":{"a":{"required":true}}}`, wantErr: "invalid schema: /properties/a/required: expected an array of strings, got boolean"},
+		{name: "negative count", schema: `{"minLength":-1}`, wantErr: "invalid schema: /minLength: expected a non-negative integer, got -1"},
+		{name: "bad pattern", schema: `{"pattern":1}`, wantErr: `invalid schema: /pattern: expected a string, got number`},
+		{name: "ECMA-262 pattern", schema: `{"pattern":"^(?=.*[0-9])\\w+$"}`},
+		{name: "unresolvable ref", schema: `{"$ref":"#/$defs/missing"}`, wantErr: `invalid schema: /$ref: unresolvable $ref "#/$defs/missing"`},
+		{name: "empty oneOf", schema: `{"oneOf":[]}`, wantErr: "invalid schema: /oneOf: expected a non-empty array of schemas"},
+		{name: "bad items", schema: `{"items":[{"type":1}]}`, wantErr: `invalid schema: /items/0/type: unknown type 1`},
+		{
+			name:    "every problem",
+			schema:  `{"type":"obj","properties":"x"}`,
+			wantErr: `invalid schema: /type: unknown type "obj"; /properties: expected an object, got string`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSchema(json.RawMessage(tt.schema))
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrInvalidSchema)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestTool_ValidateSchema(t *testing.T) {
+	assert.NoError(t, NewTool("built", WithString("a", Required())).ValidateSchema())
+	assert.NoError(t, NewToolWithRawSchema("raw", "", json.RawMessage(`{"type":"object"}`)).ValidateSchema())
+
+	err := NewToolWithRawSchema("raw", "", json.RawMessage(`{"type":"thing"}`)).ValidateSchema()
+	assert.ErrorContains(t, err, `input schema of tool "raw": invalid schema: /type: unknown type "thing"`)
+
+	tool := NewTool("out", WithRawOutputSchema(json.RawMessage(`{"required":"a"}`)))
+	assert.ErrorContains(t, tool.ValidateSchema(), `output schema of tool "out"`)
+}
+
+func TestValidateAgainstSchema_UnsupportedPattern(t *testing.T) {
+	schema := map[string]any{"type": "string", "pattern": `^(\w)\1$`}
+
+	errs, err := ValidateAgainstSchema(schema, "ab")
+	assert.ErrorContains(t, err, "invalid schema: /pattern: invalid pattern")
+	assert.Empty(t, errs)
+
+	errs, err = ValidateAgainstSchema(map[string]any{"anyOf": []any{schema}}, "ab")
+	assert.ErrorContains(t, err, "invalid pattern", "patterns of subschemas are reported too")
+	assert.Empty(t, errs)
+}
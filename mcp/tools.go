@@ -50,6 +50,36 @@ type CallToolResult struct {
 	IsError bool `json:"isError,omitempty"`
 }
 
+// ValidateToolRequest asks the server whether a tools/call request with the
+// same params would be accepted, without running the tool. The server checks
+// the arguments against the tool's input schema and runs its registered
+// tool call checks, such as authorization or rate limits.
+//
+// This is an mcp-go extension and not part of the MCP specification.
+type ValidateToolRequest struct {
+	Request
+	Header http.Header    `json:"-"` // HTTP headers from the original request
+	Params CallToolParams `json:"params"`
+}
+
+// ValidateToolResult is the server's response to a tools/validate request.
+type ValidateToolResult struct {
+	Result
+	// Valid reports whether the call would be accepted.
+	Valid bool `json:"valid"`
+	// Diagnostics lists the problems found; it is empty when Valid is true.
+	Diagnostics []ToolCallDiagnostic `json:"diagnostics,omitempty"`
+}
+
+// ToolCallDiagnostic describes a single problem with a tool call.
+type ToolCallDiagnostic struct {
+	// Path is a JSON Pointer into the arguments; empty for problems that
+	// concern the call as a whole.
+	Path string `json:"path,omitempty"`
+	// Message describes the problem.
+	Message string `json:"message"`
+}
+
 // CallToolRequest is used by the client to invoke a tool provided by the server.
 type CallToolRequest struct {
 	Request
@@ -64,10 +94,16 @@ type CallToolParams struct {
 }
 
 // GetArguments returns the Arguments as map[string]any for backward compatibility
-// If Arguments is not a map, it returns an empty map
+// If Arguments is not a map, or raw JSON holding an object, it returns an empty map
 func (r CallToolRequest) GetArguments() map[string]any {
-	if args, ok := r.Params.Arguments.(map[string]any); ok {
+	switch args := r.Params.Arguments.(type) {
+	case map[string]any:
 		return args
+	case json.RawMessage:
+		var decoded map[string]any
+		if err := json.Unmarshal(args, &decoded); err == nil {
+			return decoded
+		}
 	}
 	return nil
 }
@@ -125,15 +161,12 @@ func (r CallToolRequest) RequireString(key string) (string, error) {
 func (r CallToolRequest) GetInt(key string, defaultValue int) int {
 	args := r.GetArguments()
 	if val, ok := args[key]; ok {
-		switch v := val.(type) {
-		case int:
-			return v
-		case float64:
-			return int(v)
-		case string:
+		if v, ok := val.(string); ok {
 			if i, err := strconv.Atoi(v); err == nil {
 				return i
 			}
+		} else if i, ok := intValue(val); ok {
+			return i
 		}
 	}
 	return defaultValue
@@ -143,19 +176,16 @@ func (r CallToolRequest) GetInt(key string, defaultValue int) int {
 func (r CallToolRequest) RequireInt(key string) (int, error) {
 	args := r.GetArguments()
 	if val, ok := args[key]; ok {
-		switch v := val.(type) {
-		case int:
-			return v, nil
-		case float64:
-			return int(v), nil
-		case string:
+		if v, ok := val.(string); ok {
 			if i, err := strconv.Atoi(v); err == nil {
 				return i, nil
 			}
 			return 0, fmt.Errorf("argument %q cannot be converted to int", key)
-		default:
-			return 0, fmt.Errorf("argument %q is not an int", key)
 		}
+		if i, ok := intValue(val); ok {
+			return i, nil
+		}
+		return 0, fmt.Errorf("argument %q is not an int", key)
 	}
 	return 0, fmt.Errorf("required argument %q not found", key)
 }
@@ -164,15 +194,12 @@ func (r CallToolRequest) RequireInt(key string) (int, error) {
 func (r CallToolRequest) GetFloat(key string, defaultValue float64) float64 {
 	args := r.GetArguments()
 	if val, ok := args[key]; ok {
-		switch v := val.(type) {
-		case float64:
-			return v
-		case int:
-			return float64(v)
-		case string:
+		if v, ok := val.(string); ok {
 			if f, err := strconv.ParseFloat(v, 64); err == nil {
 				return f
 			}
+		} else if f, ok := floatValue(val); ok {
+			return f
 		}
 	}
 	return defaultValue
@@ -182,19 +209,16 @@ func (r CallToolRequest) GetFloat(key string, defaultValue float64) float64 {
 func (r CallToolRequest) RequireFloat(key string) (float64, error) {
 	args := r.GetArguments()
 	if val, ok := args[key]; ok {
-		switch v := val.(type) {
-		case float64:
-			return v, nil
-		case int:
-			return float64(v), nil
-		case string:
+		if v, ok := val.(string); ok {
 			if f, err := strconv.ParseFloat(v, 64); err == nil {
 				return f, nil
 			}
 			return 0, fmt.Errorf("argument %q cannot be converted to float64", key)
-		default:
-			return 0, fmt.Errorf("argument %q is not a float64", key)
 		}
+		if f, ok := floatValue(val); ok {
+			return f, nil
+		}
+		return 0, fmt.Errorf("argument %q is not a float64", key)
 	}
 	return 0, fmt.Errorf("required argument %q not found", key)
 }
@@ -210,10 +234,10 @@ func (r CallToolRequest) GetBool(key string, defaultValue bool) bool {
 			if b, err := strconv.ParseBool(v); err == nil {
 				return b
 			}
-		case int:
-			return v != 0
-		case float64:
-			return v != 0
+		default:
+			if f, ok := floatValue(v); ok {
+				return f != 0
+			}
 		}
 	}
 	return defaultValue
@@ -231,11 +255,10 @@ func (r CallToolRequest) RequireBool(key string) (bool, error) {
 				return b, nil
 			}
 			return false, fmt.Errorf("argument %q cannot be converted to bool", key)
-		case int:
-			return v != 0, nil
-		case float64:
-			return v != 0, nil
 		default:
+			if f, ok := floatValue(v); ok {
+				return f != 0, nil
+			}
 			return false, fmt.Errorf("argument %q is not a bool", key)
 		}
 	}
@@ -296,15 +319,12 @@ func (r CallToolRequest) GetIntSlice(key string, defaultValue []int) []int {
 		case []any:
 			result := make([]int, 0, len(v))
 			for _, item := range v {
-				switch num := item.(type) {
-				case int:
-					result = append(result, num)
-				case float64:
-					result = append(result, int(num))
-				case string:
+				if num, ok := item.(string); ok {
 					if i, err := strconv.Atoi(num); err == nil {
 						result = append(result, i)
 					}
+				} else if i, ok := intValue(item); ok {
+					result = append(result, i)
 				}
 			}
 			return result
@@ -323,18 +343,15 @@ func (r CallToolRequest) RequireIntSlice(key string) ([]int, error) {
 		case []any:
 			result := make([]int, 0, len(v))
 			for i, item := range v {
-				switch num := item.(type) {
-				case int:
-					result = append(result, num)
-				case float64:
-					result = append(result, int(num))
-				case string:
-					if i, err := strconv.Atoi(num); err == nil {
-						result = append(result, i)
-					} else {
+				if num, ok := item.(string); ok {
+					n, err := strconv.Atoi(num)
+					if err != nil {
 						return nil, fmt.Errorf("item %d in argument %q cannot be converted to int", i, key)
 					}
-				default:
+					result = append(result, n)
+				} else if n, ok := intValue(item); ok {
+					result = append(result, n)
+				} else {
 					return nil, fmt.Errorf("item %d in argument %q is not an int", i, key)
 				}
 			}
@@ -356,15 +373,12 @@ func (r CallToolRequest) GetFloatSlice(key string, defaultValue []float64) []flo
 		case []any:
 			result := make([]float64, 0, len(v))
 			for _, item := range v {
-				switch num := item.(type) {
-				case float64:
-					result = append(result, num)
-				case int:
-					result = append(result, float64(num))
-				case string:
+				if num, ok := item.(string); ok {
 					if f, err := strconv.ParseFloat(num, 64); err == nil {
 						result = append(result, f)
 					}
+				} else if f, ok := floatValue(item); ok {
+					result = append(result, f)
 				}
 			}
 			return result
@@ -383,18 +397,15 @@ func (r CallToolRequest) RequireFloatSlice(key string) ([]float64, error) {
 		case []any:
 			result := make([]float64, 0, len(v))
 			for i, item := range v {
-				switch num := item.(type) {
-				case float64:
-					result = append(result, num)
-				case int:
-					result = append(result, float64(num))
-				case string:
-					if f, err := strconv.ParseFloat(num, 64); err == nil {
-						result = append(result, f)
-					} else {
+				if num, ok := item.(string); ok {
+					f, err := strconv.ParseFloat(num, 64)
+					if err != nil {
 						return nil, fmt.Errorf("item %d in argument %q cannot be converted to float64", i, key)
 					}
-				default:
+					result = append(result, f)
+				} else if f, ok := floatValue(item); ok {
+					result = append(result, f)
+				} else {
 					return nil, fmt.Errorf("item %d in argument %q is not a float64", i, key)
 				}
 			}
@@ -423,10 +434,10 @@ func (r CallToolRequest) GetBoolSlice(key string, defaultValue []bool) []bool {
 					if parsed, err := strconv.ParseBool(b); err == nil {
 						result = append(result, parsed)
 					}
-				case int:
-					result = append(result, b != 0)
-				case float64:
-					result = append(result, b != 0)
+				default:
+					if f, ok := floatValue(b); ok {
+						result = append(result, f != 0)
+					}
 				}
 			}
 			return result
@@ -454,12 +465,12 @@ func (r CallToolRequest) RequireBoolSlice(key string) ([]bool, error) {
 					} else {
 						return nil, fmt.Errorf("item %d in argument %q cannot be converted to bool", i, key)
 					}
-				case int:
-					result = append(result, b != 0)
-				case float64:
-					result = append(result, b != 0)
 				default:
-					return nil, fmt.Errorf("item %d in argument %q is not a bool", i, key)
+					f, ok := floatValue(b)
+					if !ok {
+						return nil, fmt.Errorf("item %d in argument %q is not a bool", i, key)
+					}
+					result = append(result, f != 0)
 				}
 			}
 			return result, nil
@@ -470,6 +481,64 @@ func (r CallToolRequest) RequireBoolSlice(key string) ([]bool, error) {
 	return nil, fmt.Errorf("required argument %q not found", key)
 }
 
+// intValue converts a numeric argument to an int. Besides the float64 of
+// decoded JSON, it accepts the integer types and json.Number of arguments
+// built in Go or decoded with UseNumber.
+func intValue(val any) (int, bool) {
+	switch v := val.(type) {
+	case int:
+		return v, true
+	case int8:
+		return int(v), true
+	case int16:
+		return int(v), true
+	case int32:
+		return int(v), true
+	case int64:
+		return int(v), true
+	case uint:
+		return int(v), true
+	case uint8:
+		return int(v), true
+	case uint16:
+		return int(v), true
+	case uint32:
+		return int(v), true
+	case uint64:
+		return int(v), true
+	case float32:
+		return int(v), true
+	case float64:
+		return int(v), true
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return int(i), true
+		}
+		if f, err := v.Float64(); err == nil {
+			return int(f), true
+		}
+	}
+	return 0, false
+}
+
+// floatValue converts a numeric argument to a float64, accepting the same
+// types as intValue.
+func floatValue(val any) (float64, bool) {
+	switch v := val.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	if i, ok := intValue(val); ok {
+		return float64(i), true
+	}
+	return 0, false
+}
+
 // MarshalJSON implements custom JSON marshaling for CallToolResult
 func (r CallToolResult) MarshalJSON() ([]byte, error) {
 	m := make(map[string]any)
@@ -886,6 +955,15 @@ func Required() PropertyOption {
 	}
 }
 
+// Sensitive marks a property as carrying a secret, such as a password or an
+// API key, with the x-sensitive schema annotation. Servers using
+// server.WithRedaction mask its value before it reaches hooks and audit logs.
+func Sensitive() PropertyOption {
+	return func(schema map[string]any) {
+		schema["x-sensitive"] = true
+	}
+}
+
 // Title adds a display-friendly title to a property in the JSON Schema.
 // This title can be used by UI components to show a more readable property name.
 func Title(title string) PropertyOption {
@@ -1014,13 +1092,7 @@ func WithBoolean(name string, opts ...PropertyOption) ToolOption {
 			opt(schema)
 		}
 
-		// Remove required from property schema and add to InputSchema.required
-		if required, ok := schema["required"].(bool); ok && required {
-			delete(schema, "required")
-			t.InputSchema.Required = append(t.InputSchema.Required, name)
-		}
-
-		t.InputSchema.Properties[name] = schema
+		addProperty(t, name, schema)
 	}
 }
 
@@ -1036,13 +1108,7 @@ func WithNumber(name string, opts ...PropertyOption) ToolOption {
 			opt(schema)
 		}
 
-		// Remove required from property schema and add to InputSchema.required
-		if required, ok := schema["required"].(bool); ok && required {
-			delete(schema, "required")
-			t.InputSchema.Required = append(t.InputSchema.Required, name)
-		}
-
-		t.InputSchema.Properties[name] = schema
+		addProperty(t, name, schema)
 	}
 }
 
@@ -1058,13 +1124,7 @@ func WithString(name string, opts ...PropertyOption) ToolOption {
 			opt(schema)
 		}
 
-		// Remove required from property schema and add to InputSchema.required
-		if required, ok := schema["required"].(bool); ok && required {
-			delete(schema, "required")
-			t.InputSchema.Required = append(t.InputSchema.Required, name)
-		}
-
-		t.InputSchema.Properties[name] = schema
+		addProperty(t, name, schema)
 	}
 }
 
@@ -1081,13 +1141,7 @@ func WithObject(name string, opts ...PropertyOption) ToolOption {
 			opt(schema)
 		}
 
-		// Remove required from property schema and add to InputSchema.required
-		if required, ok := schema["required"].(bool); ok && required {
-			delete(schema, "required")
-			t.InputSchema.Required = append(t.InputSchema.Required, name)
-		}
-
-		t.InputSchema.Properties[name] = schema
+		addProperty(t, name, schema)
 	}
 }
 
@@ -1105,13 +1159,7 @@ func WithArray(name string, opts ...PropertyOption) ToolOption {
 			opt(schema)
 		}
 
-		// Remove required from property schema and add to InputSchema.required
-		if required, ok := schema["required"].(bool); ok && required {
-			delete(schema, "required")
-			t.InputSchema.Required = append(t.InputSchema.Required, name)
-		}
-
-		t.InputSchema.Properties[name] = schema
+		addProperty(t, name, schema)
 	}
 }
 
@@ -1126,13 +1174,7 @@ func WithAny(name string, opts ...PropertyOption) ToolOption {
 			opt(schema)
 		}
 
-		// Remove required from property schema and add to InputSchema.required
-		if required, ok := schema["required"].(bool); ok && required {
-			delete(schema, "required")
-			t.InputSchema.Required = append(t.InputSchema.Required, name)
-		}
-
-		t.InputSchema.Properties[name] = schema
+		addProperty(t, name, schema)
 	}
 }
 
@@ -1304,3 +1346,222 @@ func WithBooleanItems(opts ...PropertyOption) PropertyOption {
 		schema["items"] = itemSchema
 	}
 }
+
+// WithObjectItems configures an array's items to be objects.
+//
+// Supported options: Description(), the nested property helpers such as
+// WithStringProperty(), AdditionalProperties(), MinProperties(), MaxProperties()
+//
+// Example:
+//
+//	mcp.WithArray("books", mcp.WithObjectItems(
+//	    mcp.WithStringProperty("title", mcp.Required()),
+//	    mcp.WithNumberProperty("year"),
+//	))
+func WithObjectItems(opts ...PropertyOption) PropertyOption {
+	return func(schema map[string]any) {
+		schema["items"] = ObjectSchema(opts...)
+	}
+}
+
+//
+// Enum, Default and Composition Options
+//
+
+// WithEnumTyped specifies a list of allowed values of any type, such as the
+// integers of a number property.
+//
+// Example:
+//
+//	mcp.WithNumber("priority", mcp.WithEnumTyped(1, 2, 3))
+func WithEnumTyped[T any](values ...T) PropertyOption {
+	return func(schema map[string]any) {
+		schema["enum"] = values
+	}
+}
+
+// DefaultObject sets the default value for an object property.
+// This value will be used if the property is not explicitly provided.
+func DefaultObject(value map[string]any) PropertyOption {
+	return func(schema map[string]any) {
+		schema["default"] = value
+	}
+}
+
+// OneOf requires the value of a property to match exactly one of the given
+// schemas, typically built with ObjectSchema() and the other schema builders.
+func OneOf(schemas ...map[string]any) PropertyOption {
+	return func(schema map[string]any) {
+		schema["oneOf"] = schemas
+	}
+}
+
+// WithOneOf adds a property to the tool schema whose value must match exactly
+// one of the given schemas.
+//
+// Example:
+//
+//	mcp.WithOneOf("target", []map[string]any{
+//	    mcp.ObjectSchema(mcp.WithStringProperty("url", mcp.Required())),
+//	    mcp.ObjectSchema(mcp.WithStringProperty("path", mcp.Required())),
+//	}, mcp.Required())
+func WithOneOf(name string, schemas []map[string]any, opts ...PropertyOption) ToolOption {
+	return WithAny(name, append([]PropertyOption{OneOf(schemas...)}, opts...)...)
+}
+
+//
+// Nested Property Helpers
+//
+
+// WithStringProperty adds a string property to an object schema, such as the
+// one of WithObject() or ObjectSchema(). Required() marks it as required in
+// the object.
+func WithStringProperty(name string, opts ...PropertyOption) PropertyOption {
+	return withNestedProperty(name, "string", opts)
+}
+
+// WithNumberProperty adds a number property to an object schema.
+// Required() marks it as required in the object.
+func WithNumberProperty(name string, opts ...PropertyOption) PropertyOption {
+	return withNestedProperty(name, "number", opts)
+}
+
+// WithBooleanProperty adds a boolean property to an object schema.
+// Required() marks it as required in the object.
+func WithBooleanProperty(name string, opts ...PropertyOption) PropertyOption {
+	return withNestedProperty(name, "boolean", opts)
+}
+
+// WithObjectProperty adds an object property to an object schema, itself
+// configured with nested property helpers.
+// Required() marks it as required in the enclosing object.
+func WithObjectProperty(name string, opts ...PropertyOption) PropertyOption {
+	return withNestedProperty(name, "object", opts)
+}
+
+// WithArrayProperty adds an array property to an object schema.
+// Required() marks it as required in the object.
+func WithArrayProperty(name string, opts ...PropertyOption) PropertyOption {
+	return withNestedProperty(name, "array", opts)
+}
+
+//
+// Schema Builders
+//
+
+// ObjectSchema builds an object schema for Items() or OneOf() from property
+// options, such as the nested property helpers.
+//
+// Example:
+//
+//	mcp.ObjectSchema(
+//	    mcp.WithStringProperty("name", mcp.Required()),
+//	    mcp.WithObjectProperty("address",
+//	        mcp.WithStringProperty("city", mcp.Required()),
+//	    ),
+//	)
+func ObjectSchema(opts ...PropertyOption) map[string]any {
+	return buildSchema("object", opts)
+}
+
+// ArraySchema builds an array schema for Items() or OneOf().
+func ArraySchema(opts ...PropertyOption) map[string]any {
+	return buildSchema("array", opts)
+}
+
+// StringSchema builds a string schema for Items() or OneOf().
+func StringSchema(opts ...PropertyOption) map[string]any {
+	return buildSchema("string", opts)
+}
+
+// NumberSchema builds a number schema for Items() or OneOf().
+func NumberSchema(opts ...PropertyOption) map[string]any {
+	return buildSchema("number", opts)
+}
+
+// BooleanSchema builds a boolean schema for Items() or OneOf().
+func BooleanSchema(opts ...PropertyOption) map[string]any {
+	return buildSchema("boolean", opts)
+}
+
+// requiredPropertiesKey collects the names of the required nested properties
+// of an object schema until the schema is complete, keeping them apart from
+// the "required" flag set by Required() on the object itself.
+const requiredPropertiesKey = "\x00requiredProperties"
+
+// newSchema returns a schema of the given type with opts applied. Its
+// "required" flag, if any, is left for the caller to take.
+func newSchema(schemaType string, opts []PropertyOption) map[string]any {
+	schema := map[string]any{"type": schemaType}
+	if schemaType == "object" {
+		schema["properties"] = map[string]any{}
+	}
+	for _, opt := range opts {
+		opt(schema)
+	}
+	return schema
+}
+
+// buildSchema returns a complete schema of the given type with opts applied.
+// Required() has no meaning on such a schema and is dropped.
+func buildSchema(schemaType string, opts []PropertyOption) map[string]any {
+	schema := newSchema(schemaType, opts)
+	takeRequired(schema)
+	completeSchema(schema)
+	return schema
+}
+
+// withNestedProperty returns a PropertyOption adding a property of the given
+// type to an object schema.
+func withNestedProperty(name, schemaType string, opts []PropertyOption) PropertyOption {
+	return func(parent map[string]any) {
+		schema := newSchema(schemaType, opts)
+		if takeRequired(schema) {
+			names, _ := parent[requiredPropertiesKey].([]string)
+			parent[requiredPropertiesKey] = append(names, name)
+		}
+		completeSchema(schema)
+
+		properties, ok := parent["properties"].(map[string]any)
+		if !ok {
+			properties = map[string]any{}
+			parent["properties"] = properties
+		}
+		properties[name] = schema
+	}
+}
+
+// addProperty adds a property schema to the input schema of t, moving its
+// "required" flag to InputSchema.Required.
+func addProperty(t *Tool, name string, schema map[string]any) {
+	if takeRequired(schema) {
+		t.InputSchema.Required = append(t.InputSchema.Required, name)
+	}
+	completeSchema(schema)
+	t.InputSchema.Properties[name] = schema
+}
+
+// takeRequired removes the "required" flag set by Required() from schema and
+// reports whether it was set.
+func takeRequired(schema map[string]any) bool {
+	required, ok := schema["required"].(bool)
+	if !ok {
+		return false
+	}
+	delete(schema, "required")
+	return required
+}
+
+// completeSchema moves the names of required nested properties to the
+// "required" list of schema.
+func completeSchema(schema map[string]any) {
+	names, ok := schema[requiredPropertiesKey].([]string)
+	if !ok {
+		return
+	}
+	delete(schema, requiredPropertiesKey)
+	if existing, ok := schema["required"].([]string); ok {
+		names = append(existing, names...)
+	}
+	schema["required"] = names
+}
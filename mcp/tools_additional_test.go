@@ -461,3 +461,50 @@ func TestCallToolRequest_BindArgumentsWithRawJSON(t *testing.T) {
 	assert.Equal(t, "test", args.Name)
 	assert.Equal(t, 42, args.Value)
 }
+
+func TestCallToolRequest_NumericArgumentTypes(t *testing.T) {
+	req := CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"int64":       int64(7),
+		"uint8":       uint8(3),
+		"float32":     float32(2.5),
+		"number":      json.Number("42"),
+		"float_num":   json.Number("1.25"),
+		"int64_slice": []any{int64(1), json.Number("2"), float32(3)},
+	}
+
+	assert.Equal(t, 7, req.GetInt("int64", 0))
+	assert.Equal(t, 3, req.GetInt("uint8", 0))
+	assert.Equal(t, 42, req.GetInt("number", 0))
+	assert.Equal(t, 1, req.GetInt("float_num", 0))
+	assert.Equal(t, 2.5, req.GetFloat("float32", 0))
+	assert.Equal(t, 1.25, req.GetFloat("float_num", 0))
+	assert.Equal(t, 42.0, req.GetFloat("number", 0))
+	assert.True(t, req.GetBool("uint8", false))
+
+	i, err := req.RequireInt("number")
+	require.NoError(t, err)
+	assert.Equal(t, 42, i)
+	f, err := req.RequireFloat("int64")
+	require.NoError(t, err)
+	assert.Equal(t, 7.0, f)
+
+	ints, err := req.RequireIntSlice("int64_slice")
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, ints)
+	assert.Equal(t, []float64{1, 2, 3}, req.GetFloatSlice("int64_slice", nil))
+}
+
+func TestCallToolRequest_RawJSONArguments(t *testing.T) {
+	req := CallToolRequest{}
+	req.Params.Arguments = json.RawMessage(`{"name":"alice","count":3,"tags":["a","b"]}`)
+
+	assert.Equal(t, "alice", req.GetString("name", ""))
+	assert.Equal(t, 3, req.GetInt("count", 0))
+	assert.Equal(t, []string{"a", "b"}, req.GetStringSlice("tags", nil))
+
+	req.Params.Arguments = json.RawMessage(`["not", "an", "object"]`)
+	assert.Nil(t, req.GetArguments())
+	_, err := req.RequireString("name")
+	assert.Error(t, err)
+}
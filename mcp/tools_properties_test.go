@@ -310,3 +310,101 @@ func TestWithObjectWithAllOptions(t *testing.T) {
 	propertyNames := config["propertyNames"].(map[string]any)
 	assert.Equal(t, "^[a-z]+$", propertyNames["pattern"])
 }
+
+func TestNestedSchemaBuilders(t *testing.T) {
+	tool := NewTool("deploy",
+		WithObject("service",
+			Required(),
+			WithStringProperty("name", Required(), MinLength(1)),
+			WithNumberProperty("replicas", WithEnumTyped(1, 3, 5), DefaultNumber(1)),
+			WithObjectProperty("owner",
+				WithStringProperty("email", Required()),
+				WithBooleanProperty("notify"),
+			),
+			WithArrayProperty("ports", WithNumberItems(Min(1))),
+		),
+		WithArray("env", WithObjectItems(
+			WithStringProperty("key", Required()),
+			WithStringProperty("value"),
+		)),
+		WithOneOf("source", []map[string]any{
+			ObjectSchema(WithStringProperty("image", Required())),
+			ObjectSchema(WithStringProperty("repository", Required())),
+		}, Description("Where to deploy from")),
+		WithObject("limits", DefaultObject(map[string]any{"cpu": "1"})),
+	)
+
+	data, err := json.Marshal(tool)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `requiredProperties`)
+	var result map[string]any
+	require.NoError(t, json.Unmarshal(data, &result))
+
+	schema := result["inputSchema"].(map[string]any)
+	assert.Equal(t, []any{"service"}, schema["required"])
+	properties := schema["properties"].(map[string]any)
+
+	service := properties["service"].(map[string]any)
+	assert.Equal(t, []any{"name"}, service["required"])
+	serviceProps := service["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string", "minLength": float64(1)}, serviceProps["name"])
+	assert.Equal(t, map[string]any{"type": "number", "enum": []any{float64(1), float64(3), float64(5)}, "default": float64(1)}, serviceProps["replicas"])
+	owner := serviceProps["owner"].(map[string]any)
+	assert.Equal(t, []any{"email"}, owner["required"])
+	assert.Contains(t, owner["properties"], "notify")
+	assert.Equal(t, map[string]any{"type": "array", "items": map[string]any{"type": "number", "minimum": float64(1)}}, serviceProps["ports"])
+
+	env := properties["env"].(map[string]any)
+	items := env["items"].(map[string]any)
+	assert.Equal(t, "object", items["type"])
+	assert.Equal(t, []any{"key"}, items["required"])
+
+	source := properties["source"].(map[string]any)
+	assert.Equal(t, "Where to deploy from", source["description"])
+	alternatives := source["oneOf"].([]any)
+	require.Len(t, alternatives, 2)
+	assert.Equal(t, []any{"repository"}, alternatives[1].(map[string]any)["required"])
+
+	limits := properties["limits"].(map[string]any)
+	assert.Equal(t, map[string]any{"cpu": "1"}, limits["default"])
+
+	tests := []struct {
+		name  string
+		args  map[string]any
+		valid bool
+	}{
+		{
+			name:  "valid",
+			args:  map[string]any{"service": map[string]any{"name": "api", "replicas": 3, "owner": map[string]any{"email": "a@b.c"}}, "source": map[string]any{"image": "api:1"}},
+			valid: true,
+		},
+		{
+			name: "missing nested required",
+			args: map[string]any{"service": map[string]any{"owner": map[string]any{}}},
+		},
+		{
+			name: "not in typed enum",
+			args: map[string]any{"service": map[string]any{"name": "api", "replicas": 2}},
+		},
+		{
+			name: "no matching alternative",
+			args: map[string]any{"service": map[string]any{"name": "api"}, "source": map[string]any{"tag": "x"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schemaErrs, err := tool.ValidateArguments(tt.args)
+			require.NoError(t, err)
+			if tt.valid {
+				assert.Empty(t, schemaErrs)
+			} else {
+				assert.NotEmpty(t, schemaErrs)
+			}
+		})
+	}
+}
+
+func TestSchemaBuildersDropRequired(t *testing.T) {
+	assert.Equal(t, map[string]any{"type": "string"}, StringSchema(Required()))
+	assert.Equal(t, map[string]any{"type": "object", "properties": map[string]any{}}, ObjectSchema())
+}
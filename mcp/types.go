@@ -3,9 +3,12 @@
 package mcp
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"maps"
+	"math"
 	"net/http"
 	"strconv"
 
@@ -35,6 +38,14 @@ const (
 	// https://modelcontextprotocol.io/specification/2024-11-05/server/resources/
 	MethodResourcesRead MCPMethod = "resources/read"
 
+	// MethodResourcesSubscribe requests resources/updated notifications for a resource.
+	// https://modelcontextprotocol.io/specification/2025-03-26/server/resources#subscriptions
+	MethodResourcesSubscribe MCPMethod = "resources/subscribe"
+
+	// MethodResourcesUnsubscribe cancels a previous resources/subscribe request.
+	// https://modelcontextprotocol.io/specification/2025-03-26/server/resources#subscriptions
+	MethodResourcesUnsubscribe MCPMethod = "resources/unsubscribe"
+
 	// MethodPromptsList lists all available prompt templates.
 	// https://modelcontextprotocol.io/specification/2024-11-05/server/prompts/
 	MethodPromptsList MCPMethod = "prompts/list"
@@ -51,6 +62,15 @@ const (
 	// https://modelcontextprotocol.io/specification/2024-11-05/server/tools/
 	MethodToolsCall MCPMethod = "tools/call"
 
+	// MethodToolsValidate checks a tool call without executing it. It is an
+	// mcp-go extension and not part of the MCP specification.
+	MethodToolsValidate MCPMethod = "tools/validate"
+
+	// MethodCompletionComplete asks for completions of the value of a prompt
+	// or resource template argument.
+	// https://modelcontextprotocol.io/specification/2025-06-18/server/utilities/completion
+	MethodCompletionComplete MCPMethod = "completion/complete"
+
 	// MethodSetLogLevel configures the minimum log level for client
 	// https://modelcontextprotocol.io/specification/2025-03-26/server/utilities/logging
 	MethodSetLogLevel MCPMethod = "logging/setLevel"
@@ -67,6 +87,8 @@ const (
 	// https://modelcontextprotocol.io/specification/2025-03-26/server/resources#list-changed-notification
 	MethodNotificationResourcesListChanged = "notifications/resources/list_changed"
 
+	// MethodNotificationResourceUpdated notifies subscribed clients that a resource has changed.
+	// https://modelcontextprotocol.io/specification/2025-03-26/server/resources#subscriptions
 	MethodNotificationResourceUpdated = "notifications/resources/updated"
 
 	// MethodNotificationPromptsListChanged notifies when the list of available prompt templates changes.
@@ -80,6 +102,22 @@ const (
 	// MethodNotificationRootsListChanged notifies when the list of available roots changes.
 	// https://modelcontextprotocol.io/specification/2025-06-18/client/roots#root-list-changes
 	MethodNotificationRootsListChanged = "notifications/roots/list_changed"
+
+	// MethodNotificationProgress reports progress on a request that carried a progress token.
+	// https://modelcontextprotocol.io/specification/2025-03-26/basic/utilities/progress
+	MethodNotificationProgress = "notifications/progress"
+
+	// MethodNotificationMessage delivers a log message from the server.
+	// https://modelcontextprotocol.io/specification/2025-03-26/server/utilities/logging#log-message-notifications
+	MethodNotificationMessage = "notifications/message"
+
+	// MethodNotificationCancelled cancels a request previously issued in the same direction.
+	// https://modelcontextprotocol.io/specification/2025-03-26/basic/utilities/cancellation
+	MethodNotificationCancelled = "notifications/cancelled"
+
+	// MethodNotificationInitialized tells the server that the client finished initializing.
+	// https://modelcontextprotocol.io/specification/2025-03-26/basic/lifecycle#initialization
+	MethodNotificationInitialized = "notifications/initialized"
 )
 
 type URITemplate struct {
@@ -260,34 +298,98 @@ type Result struct {
 	Meta *Meta `json:"_meta,omitempty"`
 }
 
-// RequestId is a uniquely identifying ID for a request in JSON-RPC.
-// It can be any JSON-serializable value, typically a number or string.
-type RequestId struct {
+// RequestID is a uniquely identifying ID for a request in JSON-RPC: a
+// string, an integer or, in error responses to unidentifiable requests,
+// null.
+//
+// IDs keep the exact value they are decoded from: integers are held as
+// int64 rather than float64, and numbers that do not fit an int64 keep
+// their JSON text as a json.Number. IDs can be compared with == or Equal
+// and used as map keys: a string ID never equals a numeric one, and IDs
+// created from integers of any Go type equal the decoded ID of the same
+// number.
+type RequestID struct {
 	value any
 }
 
-// NewRequestId creates a new RequestId with the given value
-func NewRequestId(value any) RequestId {
-	return RequestId{value: value}
+// RequestId is the former name of RequestID.
+//
+// Deprecated: Use RequestID.
+type RequestId = RequestID
+
+// NewRequestID creates a new RequestID with the given value. Integers of
+// any type, and floats and json.Numbers holding integers, are stored as
+// int64; other numbers are stored as a json.Number.
+func NewRequestID(value any) RequestID {
+	switch v := value.(type) {
+	case RequestID:
+		return v
+	case int:
+		return RequestID{value: int64(v)}
+	case int8:
+		return RequestID{value: int64(v)}
+	case int16:
+		return RequestID{value: int64(v)}
+	case int32:
+		return RequestID{value: int64(v)}
+	case uint:
+		return newUnsignedRequestID(uint64(v))
+	case uint8:
+		return RequestID{value: int64(v)}
+	case uint16:
+		return RequestID{value: int64(v)}
+	case uint32:
+		return RequestID{value: int64(v)}
+	case uint64:
+		return newUnsignedRequestID(v)
+	case float32:
+		return newFloatRequestID(float64(v))
+	case float64:
+		return newFloatRequestID(v)
+	case json.Number:
+		if i, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			return RequestID{value: i}
+		}
+	}
+	return RequestID{value: value}
 }
 
-// Value returns the underlying value of the RequestId
-func (r RequestId) Value() any {
+// NewRequestId creates a new RequestID with the given value.
+//
+// Deprecated: Use NewRequestID.
+func NewRequestId(value any) RequestID {
+	return NewRequestID(value)
+}
+
+func newUnsignedRequestID(v uint64) RequestID {
+	if v > math.MaxInt64 {
+		return RequestID{value: json.Number(strconv.FormatUint(v, 10))}
+	}
+	return RequestID{value: int64(v)}
+}
+
+func newFloatRequestID(v float64) RequestID {
+	if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+		return RequestID{value: int64(v)}
+	}
+	return RequestID{value: json.Number(strconv.FormatFloat(v, 'g', -1, 64))}
+}
+
+// Value returns the underlying value of the RequestID: nil, a string, an
+// int64 or a json.Number, unless the ID was created from another type.
+func (r RequestID) Value() any {
 	return r.value
 }
 
-// String returns a string representation of the RequestId
-func (r RequestId) String() string {
+// String returns a string representation of the RequestID
+func (r RequestID) String() string {
 	switch v := r.value.(type) {
 	case string:
 		return "string:" + v
 	case int64:
 		return "int64:" + strconv.FormatInt(v, 10)
-	case float64:
-		if v == float64(int64(v)) {
-			return "int64:" + strconv.FormatInt(int64(v), 10)
-		}
-		return "float64:" + strconv.FormatFloat(v, 'f', -1, 64)
+	case json.Number:
+		return "number:" + string(v)
 	case nil:
 		return "<nil>"
 	default:
@@ -295,36 +397,42 @@ func (r RequestId) String() string {
 	}
 }
 
-// IsNil returns true if the RequestId is nil
-func (r RequestId) IsNil() bool {
+// Equal reports whether r and other identify the same request.
+func (r RequestID) Equal(other RequestID) bool {
+	switch r.value.(type) {
+	case nil, string, int64, json.Number:
+		return r == other
+	}
+	return r.String() == other.String()
+}
+
+// IsNil returns true if the RequestID is nil
+func (r RequestID) IsNil() bool {
 	return r.value == nil
 }
 
-func (r RequestId) MarshalJSON() ([]byte, error) {
+func (r RequestID) MarshalJSON() ([]byte, error) {
 	return json.Marshal(r.value)
 }
 
-func (r *RequestId) UnmarshalJSON(data []byte) error {
+func (r *RequestID) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
 	if string(data) == "null" {
 		r.value = nil
 		return nil
 	}
 
-	// Try unmarshaling as string first
 	var s string
 	if err := json.Unmarshal(data, &s); err == nil {
 		r.value = s
 		return nil
 	}
 
-	// JSON numbers are unmarshaled as float64 in Go
-	var f float64
-	if err := json.Unmarshal(data, &f); err == nil {
-		if f == float64(int64(f)) {
-			r.value = int64(f)
-		} else {
-			r.value = f
-		}
+	// Numbers are decoded from their text rather than through float64,
+	// which cannot hold every int64
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err == nil {
+		*r = NewRequestID(n)
 		return nil
 	}
 
@@ -334,7 +442,7 @@ func (r *RequestId) UnmarshalJSON(data []byte) error {
 // JSONRPCRequest represents a request that expects a response.
 type JSONRPCRequest struct {
 	JSONRPC string    `json:"jsonrpc"`
-	ID      RequestId `json:"id"`
+	ID      RequestID `json:"id"`
 	Params  any       `json:"params,omitempty"`
 	Request
 }
@@ -348,14 +456,14 @@ type JSONRPCNotification struct {
 // JSONRPCResponse represents a successful (non-error) response to a request.
 type JSONRPCResponse struct {
 	JSONRPC string    `json:"jsonrpc"`
-	ID      RequestId `json:"id"`
+	ID      RequestID `json:"id"`
 	Result  any       `json:"result"`
 }
 
 // JSONRPCError represents a non-successful (error) response to a request.
 type JSONRPCError struct {
 	JSONRPC string              `json:"jsonrpc"`
-	ID      RequestId           `json:"id"`
+	ID      RequestID           `json:"id"`
 	Error   JSONRPCErrorDetails `json:"error"`
 }
 
@@ -397,6 +505,38 @@ const (
 const (
 	// RESOURCE_NOT_FOUND indicates a requested resource was not found.
 	RESOURCE_NOT_FOUND = -32002
+
+	// RATE_LIMITED indicates a request was rejected because a rate limit
+	// was exceeded. The error data is a RateLimitErrorData.
+	RATE_LIMITED = -32029
+
+	// LIMIT_EXCEEDED indicates a request was rejected or interrupted
+	// because it exceeded a limit of the server, such as its maximum size.
+	// The error data is a LimitExceededErrorData.
+	LIMIT_EXCEEDED = -32030
+
+	// USER_REJECTED indicates the user rejected a request, such as a
+	// sampling request sent to the client.
+	USER_REJECTED = -1
+)
+
+// Reserved error code ranges. Both ranges are inclusive.
+const (
+	// RESERVED_ERROR_MIN is the lowest code in the range JSON-RPC reserves
+	// for pre-defined errors.
+	RESERVED_ERROR_MIN = -32768
+
+	// RESERVED_ERROR_MAX is the highest code in the range JSON-RPC reserves
+	// for pre-defined errors.
+	RESERVED_ERROR_MAX = -32000
+
+	// SERVER_ERROR_MIN is the lowest code in the range JSON-RPC leaves for
+	// implementation-defined server errors.
+	SERVER_ERROR_MIN = -32099
+
+	// SERVER_ERROR_MAX is the highest code in the range JSON-RPC leaves for
+	// implementation-defined server errors.
+	SERVER_ERROR_MAX = -32000
 )
 
 /* Empty result */
@@ -423,11 +563,13 @@ type CancelledNotification struct {
 }
 
 type CancelledNotificationParams struct {
+	// Meta is metadata attached to the notification.
+	Meta *Meta `json:"_meta,omitempty"`
 	// The ID of the request to cancel.
 	//
 	// This MUST correspond to the ID of a request previously issued
 	// in the same direction.
-	RequestId RequestId `json:"requestId"`
+	RequestId RequestID `json:"requestId"`
 
 	// An optional string describing the reason for the cancellation. This MAY
 	// be logged or presented to the user.
@@ -445,6 +587,8 @@ type InitializeRequest struct {
 }
 
 type InitializeParams struct {
+	// Meta is metadata attached to the request.
+	Meta *Meta `json:"_meta,omitempty"`
 	// The latest version of the Model Context Protocol that the client supports.
 	// The client MAY decide to support older versions as well.
 	ProtocolVersion string             `json:"protocolVersion"`
@@ -525,6 +669,8 @@ type ServerCapabilities struct {
 	Elicitation *struct{} `json:"elicitation,omitempty"`
 	// Present if the server supports roots requests to the client.
 	Roots *struct{} `json:"roots,omitempty"`
+	// Present if the server supports argument completion.
+	Completions *struct{} `json:"completions,omitempty"`
 }
 
 // Implementation describes the name and version of an MCP implementation.
@@ -554,6 +700,8 @@ type ProgressNotification struct {
 }
 
 type ProgressNotificationParams struct {
+	// Meta is metadata attached to the notification.
+	Meta *Meta `json:"_meta,omitempty"`
 	// The progress token which was given in the initial request, used to
 	// associate this notification with the request that is proceeding.
 	ProgressToken ProgressToken `json:"progressToken"`
@@ -575,6 +723,8 @@ type PaginatedRequest struct {
 }
 
 type PaginatedParams struct {
+	// Meta is metadata attached to the request.
+	Meta *Meta `json:"_meta,omitempty"`
 	// An opaque token representing the current pagination position.
 	// If provided, the server should return results starting after this cursor.
 	Cursor Cursor `json:"cursor,omitempty"`
@@ -627,6 +777,9 @@ type ReadResourceRequest struct {
 }
 
 type ReadResourceParams struct {
+	// Meta is metadata attached to the request, such as a content
+	// negotiation hint.
+	Meta *Meta `json:"_meta,omitempty"`
 	// The URI of the resource to read. The URI can use any protocol; it is up
 	// to the server how to interpret it.
 	URI string `json:"uri"`
@@ -658,6 +811,8 @@ type SubscribeRequest struct {
 }
 
 type SubscribeParams struct {
+	// Meta is metadata attached to the request.
+	Meta *Meta `json:"_meta,omitempty"`
 	// The URI of the resource to subscribe to. The URI can use any protocol; it
 	// is up to the server how to interpret it.
 	URI string `json:"uri"`
@@ -673,6 +828,8 @@ type UnsubscribeRequest struct {
 }
 
 type UnsubscribeParams struct {
+	// Meta is metadata attached to the request.
+	Meta *Meta `json:"_meta,omitempty"`
 	// The URI of the resource to unsubscribe from.
 	URI string `json:"uri"`
 }
@@ -685,6 +842,8 @@ type ResourceUpdatedNotification struct {
 	Params ResourceUpdatedNotificationParams `json:"params"`
 }
 type ResourceUpdatedNotificationParams struct {
+	// Meta is metadata attached to the notification.
+	Meta *Meta `json:"_meta,omitempty"`
 	// The URI of the resource that has been updated. This might be a sub-
 	// resource of the one that the client actually subscribed to.
 	URI string `json:"uri"`
@@ -789,6 +948,8 @@ type SetLevelRequest struct {
 }
 
 type SetLevelParams struct {
+	// Meta is metadata attached to the request.
+	Meta *Meta `json:"_meta,omitempty"`
 	// The level of logging that the client wants to receive from the server.
 	// The server should send all logs at this level and higher (i.e., more severe) to
 	// the client as notifications/logging/message.
@@ -804,6 +965,8 @@ type LoggingMessageNotification struct {
 }
 
 type LoggingMessageNotificationParams struct {
+	// Meta is metadata attached to the notification.
+	Meta *Meta `json:"_meta,omitempty"`
 	// The severity of this log message.
 	Level LoggingLevel `json:"level"`
 	// An optional name of the logger issuing this message.
@@ -861,6 +1024,8 @@ type ElicitationRequest struct {
 
 // ElicitationParams contains the parameters for an elicitation request.
 type ElicitationParams struct {
+	// Meta is metadata attached to the request.
+	Meta *Meta `json:"_meta,omitempty"`
 	// A human-readable message explaining what information is being requested and why.
 	Message string `json:"message"`
 	// A JSON Schema defining the expected structure of the user's response.
@@ -911,6 +1076,8 @@ type CreateMessageRequest struct {
 }
 
 type CreateMessageParams struct {
+	// Meta is metadata attached to the request.
+	Meta             *Meta             `json:"_meta,omitempty"`
 	Messages         []SamplingMessage `json:"messages"`
 	ModelPreferences *ModelPreferences `json:"modelPreferences,omitempty"`
 	SystemPrompt     string            `json:"systemPrompt,omitempty"`
@@ -1039,6 +1206,29 @@ type EmbeddedResource struct {
 
 func (EmbeddedResource) isContent() {}
 
+// UnmarshalJSON implements custom JSON unmarshaling for EmbeddedResource,
+// decoding the resource as TextResourceContents or BlobResourceContents.
+func (e *EmbeddedResource) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Annotated
+		Meta     *Meta          `json:"_meta,omitempty"`
+		Type     string         `json:"type"`
+		Resource map[string]any `json:"resource"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.Resource == nil {
+		return fmt.Errorf("resource is missing")
+	}
+	resource, err := ParseResourceContents(raw.Resource)
+	if err != nil {
+		return err
+	}
+	*e = EmbeddedResource{Annotated: raw.Annotated, Meta: raw.Meta, Type: raw.Type, Resource: resource}
+	return nil
+}
+
 // ModelPreferences represents the server's preferences for model selection,
 // requested of the client during sampling.
 //
@@ -1105,30 +1295,54 @@ type CompleteRequest struct {
 }
 
 type CompleteParams struct {
-	Ref      any `json:"ref"` // Can be PromptReference or ResourceReference
+	// Meta is metadata attached to the request.
+	Meta     *Meta `json:"_meta,omitempty"`
+	Ref      any   `json:"ref"` // Can be PromptReference or ResourceReference
 	Argument struct {
 		// The name of the argument
 		Name string `json:"name"`
 		// The value of the argument to use for completion matching.
 		Value string `json:"value"`
 	} `json:"argument"`
+	// Context holds additional information for the completion.
+	Context *CompleteContext `json:"context,omitempty"`
+}
+
+// CompleteContext is the context of a completion/complete request.
+type CompleteContext struct {
+	// Arguments are the values of the arguments the client already
+	// resolved, by name.
+	Arguments map[string]string `json:"arguments,omitempty"`
+}
+
+// MaxCompletionValues is the maximum number of values of a completion.
+const MaxCompletionValues = 100
+
+// Completion holds the completions of the value of an argument.
+type Completion struct {
+	// An array of completion values. Must not exceed 100 items.
+	Values []string `json:"values"`
+	// The total number of completion options available. This can exceed the
+	// number of values actually sent in the response.
+	Total int `json:"total,omitempty"`
+	// Indicates whether there are additional completion options beyond those
+	// provided in the current response, even if the exact total is unknown.
+	HasMore bool `json:"hasMore,omitempty"`
 }
 
 // CompleteResult is the server's response to a completion/complete request
 type CompleteResult struct {
 	Result
-	Completion struct {
-		// An array of completion values. Must not exceed 100 items.
-		Values []string `json:"values"`
-		// The total number of completion options available. This can exceed the
-		// number of values actually sent in the response.
-		Total int `json:"total,omitempty"`
-		// Indicates whether there are additional completion options beyond those
-		// provided in the current response, even if the exact total is unknown.
-		HasMore bool `json:"hasMore,omitempty"`
-	} `json:"completion"`
+	Completion Completion `json:"completion"`
 }
 
+// CompletionFunc returns the completions of value for an argument. resolved
+// holds the values of the other arguments the client already resolved, so
+// that completions can depend on them, such as the branches of the
+// repository chosen for a repository argument. It may be empty, but is
+// never nil.
+type CompletionFunc func(ctx context.Context, value string, resolved map[string]string) (*Completion, error)
+
 // ResourceReference is a reference to a resource or resource template definition.
 type ResourceReference struct {
 	Type string `json:"type"`
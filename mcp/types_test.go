@@ -139,6 +139,52 @@ func TestCallToolResultWithResourceLink(t *testing.T) {
 	assert.Equal(t, "application/pdf", resourceLink.MIMEType)
 }
 
+func TestCallToolResultWithAudioAndEmbeddedResource(t *testing.T) {
+	result := &CallToolResult{
+		Content: []Content{
+			NewAudioContent("UklGRg==", "audio/wav"),
+			NewEmbeddedResource(TextResourceContents{URI: "file:///notes.md", MIMEType: "text/markdown", Text: "# notes"}),
+			NewEmbeddedResource(BlobResourceContents{URI: "file:///logo.png", MIMEType: "image/png", Blob: "iVBORw=="}),
+		},
+	}
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+
+	var unmarshalled CallToolResult
+	require.NoError(t, json.Unmarshal(data, &unmarshalled))
+	require.Len(t, unmarshalled.Content, 3)
+
+	audio, ok := AsAudioContent(unmarshalled.Content[0])
+	require.True(t, ok)
+	assert.Equal(t, "audio/wav", audio.MIMEType)
+	assert.Equal(t, "UklGRg==", audio.Data)
+
+	text, ok := AsEmbeddedResource(unmarshalled.Content[1])
+	require.True(t, ok)
+	assert.Equal(t, TextResourceContents{URI: "file:///notes.md", MIMEType: "text/markdown", Text: "# notes"}, text.Resource)
+
+	blob, ok := AsEmbeddedResource(unmarshalled.Content[2])
+	require.True(t, ok)
+	assert.Equal(t, BlobResourceContents{URI: "file:///logo.png", MIMEType: "image/png", Blob: "iVBORw=="}, blob.Resource)
+}
+
+func TestEmbeddedResourceUnmarshalInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{name: "missing resource", json: `{"type":"resource"}`},
+		{name: "missing uri", json: `{"type":"resource","resource":{"text":"x"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resource EmbeddedResource
+			assert.Error(t, json.Unmarshal([]byte(tt.json), &resource))
+		})
+	}
+}
+
 func TestResourceContentsMetaField(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -373,3 +419,68 @@ func TestParseResourceContentsInvalidMeta(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestIDUnmarshalling(t *testing.T) {
+	tests := []struct {
+		name  string
+		json  string
+		value any
+	}{
+		{name: "string", json: `"req-1"`, value: "req-1"},
+		{name: "numeric string", json: `"1"`, value: "1"},
+		{name: "integer", json: `42`, value: int64(42)},
+		{name: "negative integer", json: `-7`, value: int64(-7)},
+		{name: "integer beyond float64 precision", json: `9007199254740993`, value: int64(9007199254740993)},
+		{name: "integer beyond int64", json: `18446744073709551616`, value: json.Number("18446744073709551616")},
+		{name: "fraction", json: `1.5`, value: json.Number("1.5")},
+		{name: "null", json: `null`, value: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var id RequestID
+			require.NoError(t, json.Unmarshal([]byte(tt.json), &id))
+			assert.Equal(t, tt.value, id.Value())
+			assert.Equal(t, tt.value == nil, id.IsNil())
+
+			data, err := json.Marshal(id)
+			require.NoError(t, err)
+			assert.Equal(t, tt.json, string(data), "IDs are echoed exactly as they were received")
+		})
+	}
+
+	var id RequestID
+	assert.Error(t, json.Unmarshal([]byte(`true`), &id))
+	assert.Error(t, json.Unmarshal([]byte(`{}`), &id))
+}
+
+func TestRequestIDEquality(t *testing.T) {
+	var decoded RequestID
+	require.NoError(t, json.Unmarshal([]byte(`1`), &decoded))
+
+	tests := []struct {
+		name  string
+		a, b  RequestID
+		equal bool
+	}{
+		{name: "int and decoded integer", a: NewRequestID(1), b: decoded, equal: true},
+		{name: "integer types", a: NewRequestID(int32(1)), b: NewRequestID(uint64(1)), equal: true},
+		{name: "integral float", a: NewRequestID(float64(1)), b: decoded, equal: true},
+		{name: "json.Number", a: NewRequestID(json.Number("1")), b: decoded, equal: true},
+		{name: "wrapped ID", a: NewRequestID(decoded), b: decoded, equal: true},
+		{name: "string and number", a: NewRequestID("1"), b: decoded, equal: false},
+		{name: "different numbers", a: NewRequestID(2), b: decoded, equal: false},
+		{name: "null and zero", a: RequestID{}, b: NewRequestID(0), equal: false},
+		{name: "nulls", a: RequestID{}, b: NewRequestID(nil), equal: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.equal, tt.a.Equal(tt.b))
+			assert.Equal(t, tt.equal, tt.a == tt.b)
+		})
+	}
+
+	pending := map[RequestID]string{NewRequestID(7): "tools/call", NewRequestID("7"): "ping"}
+	var response RequestID
+	require.NoError(t, json.Unmarshal([]byte(`7`), &response))
+	assert.Equal(t, "tools/call", pending[response], "IDs can key correlation maps")
+}
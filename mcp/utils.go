@@ -116,7 +116,7 @@ func AsBlobResourceContents(content any) (*BlobResourceContents, bool) {
 // NOTE: This function expects a Result struct, but JSONRPCResponse.Result is typed as `any`.
 // The Result struct wraps the actual result data with optional metadata.
 // For direct result assignment, use NewJSONRPCResultResponse instead.
-func NewJSONRPCResponse(id RequestId, result Result) JSONRPCResponse {
+func NewJSONRPCResponse(id RequestID, result Result) JSONRPCResponse {
 	return JSONRPCResponse{
 		JSONRPC: JSONRPC_VERSION,
 		ID:      id,
@@ -126,7 +126,7 @@ func NewJSONRPCResponse(id RequestId, result Result) JSONRPCResponse {
 
 // NewJSONRPCResultResponse creates a new JSONRPCResponse with the given id and result.
 // This function accepts any type for the result, matching the JSONRPCResponse.Result field type.
-func NewJSONRPCResultResponse(id RequestId, result any) JSONRPCResponse {
+func NewJSONRPCResultResponse(id RequestID, result any) JSONRPCResponse {
 	return JSONRPCResponse{
 		JSONRPC: JSONRPC_VERSION,
 		ID:      id,
@@ -145,7 +145,7 @@ func NewJSONRPCErrorDetails(code int, message string, data any) JSONRPCErrorDeta
 
 // NewJSONRPCError creates a new JSONRPCResponse with the given id, code, and message
 func NewJSONRPCError(
-	id RequestId,
+	id RequestID,
 	code int,
 	message string,
 	data any,
@@ -169,12 +169,7 @@ func NewProgressNotification(
 		Notification: Notification{
 			Method: "notifications/progress",
 		},
-		Params: struct {
-			ProgressToken ProgressToken `json:"progressToken"`
-			Progress      float64       `json:"progress"`
-			Total         float64       `json:"total,omitempty"`
-			Message       string        `json:"message,omitempty"`
-		}{
+		Params: ProgressNotificationParams{
 			ProgressToken: token,
 			Progress:      progress,
 		},
@@ -199,11 +194,7 @@ func NewLoggingMessageNotification(
 		Notification: Notification{
 			Method: "notifications/message",
 		},
-		Params: struct {
-			Level  LoggingLevel `json:"level"`
-			Logger string       `json:"logger,omitempty"`
-			Data   any          `json:"data"`
-		}{
+		Params: LoggingMessageNotificationParams{
 			Level:  level,
 			Logger: logger,
 			Data:   data,
@@ -259,6 +250,19 @@ func NewResourceLink(uri, name, description, mimeType string) ResourceLink {
 	}
 }
 
+// NewResourceLinkFromResource creates a ResourceLink to resource, copying its
+// name, description, MIME type and annotations
+func NewResourceLinkFromResource(resource Resource) ResourceLink {
+	return ResourceLink{
+		Annotated:   resource.Annotated,
+		Type:        ContentTypeLink,
+		URI:         resource.URI,
+		Name:        resource.Name,
+		Description: resource.Description,
+		MIMEType:    resource.MIMEType,
+	}
+}
+
 // Helper function to create a new EmbeddedResource
 func NewEmbeddedResource(resource ResourceContents) EmbeddedResource {
 	return EmbeddedResource{
@@ -388,6 +392,18 @@ func NewToolResultResource(
 	}
 }
 
+// NewToolResultResourceLink creates a new CallToolResult with text content
+// followed by links to resources the client can read, rather than their
+// contents
+func NewToolResultResourceLink(text string, links ...ResourceLink) *CallToolResult {
+	content := make([]Content, 0, len(links)+1)
+	content = append(content, NewTextContent(text))
+	for _, link := range links {
+		content = append(content, link)
+	}
+	return &CallToolResult{Content: content}
+}
+
 // NewToolResultError creates a new CallToolResult with an error message.
 // Any errors that originate from the tool SHOULD be reported inside the result object.
 func NewToolResultError(text string) *CallToolResult {
@@ -168,6 +168,22 @@ func TestNewResourceLink(t *testing.T) {
 	assert.Equal(t, "text/plain", result.MIMEType)
 }
 
+func TestNewResourceLinkFromResource(t *testing.T) {
+	resource := NewResource("file:///test.txt", "test.txt",
+		WithResourceDescription("A test file"), WithMIMEType("text/plain"), WithAnnotations([]Role{RoleUser}, 0.5))
+	result := NewResourceLinkFromResource(resource)
+
+	assert.Equal(t, ContentTypeLink, result.Type)
+	assert.Equal(t, NewResourceLink("file:///test.txt", "test.txt", "A test file", "text/plain"), ResourceLink{
+		Type:        result.Type,
+		URI:         result.URI,
+		Name:        result.Name,
+		Description: result.Description,
+		MIMEType:    result.MIMEType,
+	})
+	assert.Equal(t, resource.Annotations, result.Annotations)
+}
+
 func TestNewEmbeddedResource(t *testing.T) {
 	resource := TextResourceContents{URI: "file:///test.txt", Text: "content"}
 	result := NewEmbeddedResource(resource)
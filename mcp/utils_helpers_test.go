@@ -109,6 +109,19 @@ func TestNewToolResultResource(t *testing.T) {
 	assert.Equal(t, resource, embeddedResource.Resource)
 }
 
+func TestNewToolResultResourceLink(t *testing.T) {
+	first := NewResourceLink("file:///a.txt", "a.txt", "", "text/plain")
+	second := NewResourceLink("file:///b.txt", "b.txt", "", "text/plain")
+
+	result := NewToolResultResourceLink("Found 2 files", first, second)
+
+	require.Len(t, result.Content, 3)
+	assert.Equal(t, NewTextContent("Found 2 files"), result.Content[0])
+	assert.Equal(t, first, result.Content[1])
+	assert.Equal(t, second, result.Content[2])
+	assert.False(t, result.IsError)
+}
+
 func TestNewToolResultErrorf(t *testing.T) {
 	result := NewToolResultErrorf("error code: %d, message: %s", 404, "not found")
 
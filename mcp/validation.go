@@ -0,0 +1,219 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ValidationError describes why ValidateMessage rejected a message. It
+// wraps the sentinel error of its code, such as ErrInvalidRequest.
+type ValidationError struct {
+	// Code is the JSON-RPC error code to answer the message with:
+	// PARSE_ERROR, INVALID_REQUEST or INVALID_PARAMS.
+	Code int
+	// ID is the ID of the message, nil if it has none or it is invalid.
+	ID RequestID
+	// Method is the method of the message, empty for responses.
+	Method string
+	// Message explains what is wrong.
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrorForCode(e.Code)
+}
+
+// paramKind is the JSON type a required parameter must have.
+type paramKind string
+
+const (
+	paramString paramKind = "a string"
+	paramObject paramKind = "an object"
+	paramNumber paramKind = "a number"
+	paramAny    paramKind = "set"
+)
+
+type requiredParam struct {
+	name string
+	kind paramKind
+}
+
+// requiredParams lists the parameters messages of each method must carry.
+var requiredParams = map[string][]requiredParam{
+	string(MethodInitialize): {
+		{"protocolVersion", paramString},
+		{"capabilities", paramObject},
+		{"clientInfo", paramObject},
+	},
+	string(MethodResourcesRead):        {{"uri", paramString}},
+	string(MethodResourcesSubscribe):   {{"uri", paramString}},
+	string(MethodResourcesUnsubscribe): {{"uri", paramString}},
+	string(MethodPromptsGet):           {{"name", paramString}},
+	string(MethodToolsCall):            {{"name", paramString}},
+	string(MethodToolsValidate):        {{"name", paramString}},
+	string(MethodSetLogLevel):          {{"level", paramString}},
+	MethodNotificationCancelled:        {{"requestId", paramAny}},
+	MethodNotificationProgress:         {{"progressToken", paramAny}, {"progress", paramNumber}},
+	MethodNotificationResourceUpdated:  {{"uri", paramString}},
+}
+
+// ValidateMessage strictly checks that data is a single well-formed
+// JSON-RPC 2.0 request, notification or response as MCP defines them:
+//
+//   - it is a JSON object with no members beyond jsonrpc, id, method,
+//     params, result and error, and a jsonrpc member of "2.0";
+//   - IDs are strings or integers, and null only in error responses;
+//   - requests and notifications have a non-empty method and object params,
+//     with the parameters their method requires, and an object _meta;
+//   - responses have exactly one of result and error, and errors have an
+//     integer code and a string message.
+//
+// It returns nil or a *ValidationError carrying the code to answer the
+// message with. Unlike decoding, it never accepts a frame partially.
+func ValidateMessage(data []byte) error {
+	if !json.Valid(data) {
+		return &ValidationError{Code: PARSE_ERROR, Message: "message is not valid JSON"}
+	}
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil || members == nil {
+		return &ValidationError{Code: INVALID_REQUEST, Message: "message must be a JSON object"}
+	}
+
+	v := &ValidationError{Code: INVALID_REQUEST}
+	fail := func(code int, format string, args ...any) error {
+		v.Code = code
+		v.Message = fmt.Sprintf(format, args...)
+		return v
+	}
+
+	rawID, hasID := members["id"]
+	if hasID && !isNull(rawID) {
+		id, ok := parseID(rawID)
+		if !ok {
+			return fail(INVALID_REQUEST, "id must be a string or an integer")
+		}
+		v.ID = id
+	}
+	if rawMethod, ok := members["method"]; ok {
+		if err := json.Unmarshal(rawMethod, &v.Method); err != nil || v.Method == "" {
+			return fail(INVALID_REQUEST, "method must be a non-empty string")
+		}
+	}
+
+	for name := range members {
+		switch name {
+		case "jsonrpc", "id", "method", "params", "result", "error":
+		default:
+			return fail(INVALID_REQUEST, "unknown member %q", name)
+		}
+	}
+	var version string
+	if err := json.Unmarshal(members["jsonrpc"], &version); err != nil || version != JSONRPC_VERSION {
+		return fail(INVALID_REQUEST, "jsonrpc must be %q", JSONRPC_VERSION)
+	}
+
+	_, hasResult := members["result"]
+	rawError, hasError := members["error"]
+	if v.Method == "" {
+		if _, ok := members["params"]; ok {
+			return fail(INVALID_REQUEST, "responses have no params")
+		}
+		if hasResult == hasError {
+			return fail(INVALID_REQUEST, "responses must have exactly one of result and error")
+		}
+		if !hasID || (isNull(rawID) && !hasError) {
+			return fail(INVALID_REQUEST, "responses must have an id")
+		}
+		if hasError {
+			return validateErrorObject(rawError, fail)
+		}
+		return nil
+	}
+
+	if hasResult || hasError {
+		return fail(INVALID_REQUEST, "requests and notifications have no result or error")
+	}
+	if hasID && isNull(rawID) {
+		return fail(INVALID_REQUEST, "id of requests must not be null")
+	}
+	var params map[string]json.RawMessage
+	if rawParams, ok := members["params"]; ok {
+		if err := json.Unmarshal(rawParams, &params); err != nil || params == nil {
+			return fail(INVALID_PARAMS, "params must be an object")
+		}
+	}
+	if meta, ok := params["_meta"]; ok && !isObject(meta) {
+		return fail(INVALID_PARAMS, "_meta must be an object")
+	}
+	for _, param := range requiredParams[v.Method] {
+		raw, ok := params[param.name]
+		if !ok || isNull(raw) || !param.kind.matches(raw) {
+			return fail(INVALID_PARAMS, "params of %s must have %s %s", v.Method, param.name, param.kind)
+		}
+	}
+	return nil
+}
+
+// validateErrorObject checks the error member of a response.
+func validateErrorObject(raw json.RawMessage, fail func(int, string, ...any) error) error {
+	var details struct {
+		Code    *json.Number `json:"code"`
+		Message *string      `json:"message"`
+	}
+	if err := json.Unmarshal(raw, &details); err != nil || !isObject(raw) {
+		return fail(INVALID_REQUEST, "error must be an object")
+	}
+	if details.Code == nil {
+		return fail(INVALID_REQUEST, "error must have an integer code")
+	}
+	if _, err := strconv.ParseInt(details.Code.String(), 10, 64); err != nil {
+		return fail(INVALID_REQUEST, "error must have an integer code")
+	}
+	if details.Message == nil {
+		return fail(INVALID_REQUEST, "error must have a string message")
+	}
+	return nil
+}
+
+// parseID decodes a string or integer ID.
+func parseID(raw json.RawMessage) (RequestID, bool) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return NewRequestID(s), true
+	}
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err != nil || strings.ContainsAny(string(n), ".eE") {
+		return RequestID{}, false
+	}
+	return NewRequestID(n), true
+}
+
+func (k paramKind) matches(raw json.RawMessage) bool {
+	switch k {
+	case paramString:
+		var s string
+		return json.Unmarshal(raw, &s) == nil
+	case paramObject:
+		return isObject(raw)
+	case paramNumber:
+		var n float64
+		return json.Unmarshal(raw, &n) == nil
+	}
+	return true
+}
+
+func isNull(raw json.RawMessage) bool {
+	return string(bytes.TrimSpace(raw)) == "null"
+}
+
+func isObject(raw json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(raw)
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
@@ -0,0 +1,101 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		code    int
+		id      RequestId
+		err     string
+	}{
+		{name: "request", message: `{"jsonrpc":"2.0","id":1,"method":"ping"}`},
+		{name: "request with string id", message: `{"jsonrpc":"2.0","id":"a","method":"tools/call","params":{"name":"x","arguments":{}}}`},
+		{name: "notification", message: `{"jsonrpc":"2.0","method":"notifications/initialized"}`},
+		{name: "result", message: `{"jsonrpc":"2.0","id":1,"result":{}}`},
+		{name: "error with null id", message: `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"bad"}}`},
+		{name: "unknown method", message: `{"jsonrpc":"2.0","id":1,"method":"custom/method","params":{"anything":1}}`},
+
+		{name: "invalid JSON", message: `{"jsonrpc":`, code: PARSE_ERROR, err: "not valid JSON"},
+		{name: "batch", message: `[{"jsonrpc":"2.0","id":1,"method":"ping"}]`, code: INVALID_REQUEST, err: "must be a JSON object"},
+		{name: "null", message: `null`, code: INVALID_REQUEST, err: "must be a JSON object"},
+		{name: "missing version", message: `{"id":1,"method":"ping"}`, code: INVALID_REQUEST, id: NewRequestId(int64(1)), err: `jsonrpc must be "2.0"`},
+		{name: "wrong version", message: `{"jsonrpc":"1.0","id":1,"method":"ping"}`, code: INVALID_REQUEST, id: NewRequestId(int64(1)), err: `jsonrpc must be "2.0"`},
+		{name: "boolean id", message: `{"jsonrpc":"2.0","id":true,"method":"ping"}`, code: INVALID_REQUEST, err: "id must be a string or an integer"},
+		{name: "fractional id", message: `{"jsonrpc":"2.0","id":1.5,"method":"ping"}`, code: INVALID_REQUEST, err: "id must be a string or an integer"},
+		{name: "null request id", message: `{"jsonrpc":"2.0","id":null,"method":"ping"}`, code: INVALID_REQUEST, err: "must not be null"},
+		{name: "empty method", message: `{"jsonrpc":"2.0","id":1,"method":""}`, code: INVALID_REQUEST, err: "non-empty string"},
+		{name: "numeric method", message: `{"jsonrpc":"2.0","id":1,"method":7}`, code: INVALID_REQUEST, err: "non-empty string"},
+		{name: "unknown member", message: `{"jsonrpc":"2.0","id":1,"method":"ping","extra":true}`, code: INVALID_REQUEST, id: NewRequestId(int64(1)), err: `unknown member "extra"`},
+		{name: "array params", message: `{"jsonrpc":"2.0","id":1,"method":"ping","params":[]}`, code: INVALID_PARAMS, id: NewRequestId(int64(1)), err: "params must be an object"},
+		{name: "string meta", message: `{"jsonrpc":"2.0","id":1,"method":"ping","params":{"_meta":"x"}}`, code: INVALID_PARAMS, err: "_meta must be an object"},
+		{name: "missing tool name", message: `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{}}`, code: INVALID_PARAMS, id: NewRequestId(int64(2)), err: "params of tools/call must have name a string"},
+		{name: "numeric uri", message: `{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":5}}`, code: INVALID_PARAMS, err: "must have uri a string"},
+		{name: "initialize without client info", message: `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{}}}`, code: INVALID_PARAMS, err: "clientInfo an object"},
+		{name: "progress without progress", message: `{"jsonrpc":"2.0","method":"notifications/progress","params":{"progressToken":1}}`, code: INVALID_PARAMS, err: "progress a number"},
+		{name: "result and error", message: `{"jsonrpc":"2.0","id":1,"result":{},"error":{"code":1,"message":"x"}}`, code: INVALID_REQUEST, err: "exactly one of result and error"},
+		{name: "result without id", message: `{"jsonrpc":"2.0","result":{}}`, code: INVALID_REQUEST, err: "must have an id"},
+		{name: "request with result", message: `{"jsonrpc":"2.0","id":1,"method":"ping","result":{}}`, code: INVALID_REQUEST, err: "no result or error"},
+		{name: "error without code", message: `{"jsonrpc":"2.0","id":1,"error":{"message":"x"}}`, code: INVALID_REQUEST, err: "integer code"},
+		{name: "error with fractional code", message: `{"jsonrpc":"2.0","id":1,"error":{"code":1.5,"message":"x"}}`, code: INVALID_REQUEST, err: "integer code"},
+		{name: "error without message", message: `{"jsonrpc":"2.0","id":1,"error":{"code":1}}`, code: INVALID_REQUEST, err: "string message"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMessage([]byte(tt.message))
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var invalid *ValidationError
+			require.True(t, errors.As(err, &invalid), "unexpected error %v", err)
+			assert.Equal(t, tt.code, invalid.Code)
+			assert.Contains(t, invalid.Message, tt.err)
+			if !tt.id.IsNil() {
+				assert.Equal(t, tt.id, invalid.ID)
+			}
+			assert.True(t, errors.Is(err, ErrorForCode(tt.code)))
+		})
+	}
+}
+
+func FuzzValidateMessage(f *testing.F) {
+	for _, seed := range []string{
+		`{"jsonrpc":"2.0","id":1,"method":"ping"}`,
+		`{"jsonrpc":"2.0","id":"x","method":"tools/call","params":{"name":"a","_meta":{"progressToken":1}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/progress","params":{"progressToken":"t","progress":0.5}}`,
+		`{"jsonrpc":"2.0","id":1,"error":{"code":-32600,"message":"bad"}}`,
+		`{"jsonrpc":"2.0","id":1e3,"result":null}`,
+		`[1,2]`,
+		``,
+	} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		err := ValidateMessage(data)
+		if err == nil {
+			// Valid messages decode as one of the message types.
+			var message struct {
+				JSONRPC string          `json:"jsonrpc"`
+				ID      *RequestId      `json:"id"`
+				Method  string          `json:"method"`
+				Params  json.RawMessage `json:"params"`
+			}
+			require.NoError(t, json.Unmarshal(data, &message))
+			assert.Equal(t, JSONRPC_VERSION, message.JSONRPC)
+			return
+		}
+		var invalid *ValidationError
+		require.True(t, errors.As(err, &invalid))
+		assert.Contains(t, []int{PARSE_ERROR, INVALID_REQUEST, INVALID_PARAMS}, invalid.Code)
+	})
+}
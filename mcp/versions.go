@@ -0,0 +1,54 @@
+package mcp
+
+import "slices"
+
+// ProtocolFeature is a protocol feature that is not available in every
+// protocol version.
+type ProtocolFeature string
+
+const (
+	// FeatureAudioContent is the audio content type.
+	FeatureAudioContent ProtocolFeature = "audioContent"
+	// FeatureStructuredContent is the structuredContent field of tool results
+	// and the outputSchema field of tools.
+	FeatureStructuredContent ProtocolFeature = "structuredContent"
+	// FeatureResourceLinks is the resource_link content type.
+	FeatureResourceLinks ProtocolFeature = "resourceLinks"
+	// FeatureElicitation is the elicitation/create request.
+	FeatureElicitation ProtocolFeature = "elicitation"
+)
+
+// featureVersions maps each feature to the protocol version introducing it.
+var featureVersions = map[ProtocolFeature]string{
+	FeatureAudioContent:      "2025-03-26",
+	FeatureStructuredContent: "2025-06-18",
+	FeatureResourceLinks:     "2025-06-18",
+	FeatureElicitation:       "2025-06-18",
+}
+
+// SupportedIn reports whether the feature is available in the given
+// protocol version. Unknown features are assumed to be available.
+func (f ProtocolFeature) SupportedIn(version string) bool {
+	introduced, ok := featureVersions[f]
+	if !ok {
+		return true
+	}
+	// Protocol versions are dates, so they order lexically.
+	return version >= introduced
+}
+
+// NegotiateProtocolVersion returns the protocol version to use with a peer
+// requesting requested: requested itself if it is supported, and otherwise
+// the highest supported version. It returns "" if supported is empty.
+func NegotiateProtocolVersion(requested string, supported []string) string {
+	if slices.Contains(supported, requested) {
+		return requested
+	}
+	var highest string
+	for _, version := range supported {
+		if version > highest {
+			highest = version
+		}
+	}
+	return highest
+}
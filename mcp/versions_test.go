@@ -0,0 +1,46 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateProtocolVersion(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested string
+		supported []string
+		expected  string
+	}{
+		{name: "supported version", requested: "2025-03-26", supported: ValidProtocolVersions, expected: "2025-03-26"},
+		{name: "unknown version", requested: "2099-01-01", supported: ValidProtocolVersions, expected: LATEST_PROTOCOL_VERSION},
+		{name: "highest common", requested: LATEST_PROTOCOL_VERSION, supported: []string{"2024-11-05", "2025-03-26"}, expected: "2025-03-26"},
+		{name: "nothing supported", requested: LATEST_PROTOCOL_VERSION},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NegotiateProtocolVersion(tt.requested, tt.supported))
+		})
+	}
+}
+
+func TestProtocolFeature_SupportedIn(t *testing.T) {
+	tests := []struct {
+		feature  ProtocolFeature
+		version  string
+		expected bool
+	}{
+		{feature: FeatureAudioContent, version: "2024-11-05", expected: false},
+		{feature: FeatureAudioContent, version: "2025-03-26", expected: true},
+		{feature: FeatureStructuredContent, version: "2025-03-26", expected: false},
+		{feature: FeatureStructuredContent, version: "2025-06-18", expected: true},
+		{feature: FeatureElicitation, version: "2025-03-26", expected: false},
+		{feature: ProtocolFeature("unknown"), version: "2024-11-05", expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.feature)+"@"+tt.version, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.feature.SupportedIn(tt.version))
+		})
+	}
+}
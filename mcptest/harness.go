@@ -0,0 +1,148 @@
+package mcptest
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Option configures a server created by New.
+type Option func(*Server)
+
+// WithTools adds tools to the server.
+func WithTools(tools ...server.ServerTool) Option {
+	return func(s *Server) {
+		s.AddTools(tools...)
+	}
+}
+
+// WithPrompts adds prompts to the server.
+func WithPrompts(prompts ...server.ServerPrompt) Option {
+	return func(s *Server) {
+		s.AddPrompts(prompts...)
+	}
+}
+
+// WithResources adds resources to the server.
+func WithResources(resources ...server.ServerResource) Option {
+	return func(s *Server) {
+		s.AddResources(resources...)
+	}
+}
+
+// WithResourceTemplates adds resource templates to the server.
+func WithResourceTemplates(templates ...server.ServerResourceTemplate) Option {
+	return func(s *Server) {
+		s.AddResourceTemplates(templates...)
+	}
+}
+
+// WithServerOptions sets the options the MCP server is created with, such
+// as capabilities, hooks or middlewares.
+func WithServerOptions(opts ...server.ServerOption) Option {
+	return func(s *Server) {
+		s.serverOptions = append(s.serverOptions, opts...)
+	}
+}
+
+// WithClientOptions sets the options the connected client is created with.
+func WithClientOptions(opts ...client.ClientOption) Option {
+	return func(s *Server) {
+		s.clientOptions = append(s.clientOptions, opts...)
+	}
+}
+
+// New starts an MCP server configured by opts and connects an initialized
+// client to it. The test fails immediately if the server cannot be started,
+// and the server is closed when the test completes.
+func New(t *testing.T, opts ...Option) *Server {
+	t.Helper()
+
+	srv := NewUnstartedServer(t)
+	for _, opt := range opts {
+		opt(srv)
+	}
+	if err := srv.Start(context.Background()); err != nil {
+		t.Fatalf("mcptest: failed to start server: %v", err)
+	}
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// RequireToolExists fails the test unless the server lists a tool named
+// name, and returns the tool.
+func (s *Server) RequireToolExists(t *testing.T, name string) mcp.Tool {
+	t.Helper()
+
+	result, err := s.client.ListTools(context.Background(), mcp.ListToolsRequest{})
+	if err != nil {
+		t.Fatalf("mcptest: ListTools: %v", err)
+	}
+	names := make([]string, 0, len(result.Tools))
+	for _, tool := range result.Tools {
+		if tool.Name == name {
+			return tool
+		}
+		names = append(names, tool.Name)
+	}
+	t.Fatalf("mcptest: tool %q not found, the server lists [%s]", name, strings.Join(names, ", "))
+	return mcp.Tool{}
+}
+
+// CallToolJSON calls the named tool with args, which must marshal to a JSON
+// object, and decodes its result into out unless out is nil. The structured
+// content of the result is decoded if there is one, and its text content
+// otherwise. The test fails if the call fails, returns a tool error or its
+// result does not decode into out.
+func (s *Server) CallToolJSON(t *testing.T, name string, args any, out any) *mcp.CallToolResult {
+	t.Helper()
+
+	var request mcp.CallToolRequest
+	request.Params.Name = name
+	request.Params.Arguments = args
+	result, err := s.client.CallTool(context.Background(), request)
+	if err != nil {
+		t.Fatalf("mcptest: CallTool(%q): %v", name, err)
+	}
+
+	var text strings.Builder
+	for _, content := range result.Content {
+		if textContent, ok := content.(mcp.TextContent); ok {
+			text.WriteString(textContent.Text)
+		}
+	}
+	if result.IsError {
+		t.Fatalf("mcptest: tool %q returned an error: %s", name, text.String())
+	}
+	if out == nil {
+		return result
+	}
+
+	data := []byte(text.String())
+	if result.StructuredContent != nil {
+		if data, err = json.Marshal(result.StructuredContent); err != nil {
+			t.Fatalf("mcptest: failed to marshal structured content of %q: %v", name, err)
+		}
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		t.Fatalf("mcptest: failed to decode result of %q into %T: %v", name, out, err)
+	}
+	return result
+}
+
+// DrainNotifications returns the notifications the client has received
+// since it was started or since the previous call. Notifications are
+// delivered asynchronously, so a notification sent by a handler is not
+// necessarily received by the time the request returns.
+func (s *Server) DrainNotifications() []mcp.JSONRPCNotification {
+	s.notificationsMu.Lock()
+	defer s.notificationsMu.Unlock()
+	notifications := s.notifications
+	s.notifications = nil
+	return notifications
+}
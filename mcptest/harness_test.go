@@ -0,0 +1,67 @@
+package mcptest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/mcptest"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestNew(t *testing.T) {
+	type user struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	srv := mcptest.New(t,
+		mcptest.WithServerOptions(server.WithToolCapabilities(true)),
+		mcptest.WithTools(
+			server.ServerTool{
+				Tool:    mcp.NewTool("get_user", mcp.WithString("id")),
+				Handler: structuredContentHandler,
+			},
+			server.ServerTool{
+				Tool: mcp.NewTool("announce"),
+				Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+					err := server.ServerFromContext(ctx).SendNotificationToClient(ctx, "notifications/announcement", map[string]any{"text": "hi"})
+					if err != nil {
+						return nil, err
+					}
+					return mcp.NewToolResultText(`{"id":"7","name":"Ann"}`), nil
+				},
+			},
+		),
+	)
+
+	tool := srv.RequireToolExists(t, "get_user")
+	if _, ok := tool.InputSchema.Properties["id"]; !ok {
+		t.Errorf("expected tool schema to declare id, got %+v", tool.InputSchema)
+	}
+
+	var got user
+	srv.CallToolJSON(t, "get_user", map[string]any{"user_id": "123"}, &got)
+	if want := (user{ID: "123", Name: "John Doe"}); got != want {
+		t.Errorf("got %+v from structured content, want %+v", got, want)
+	}
+
+	srv.CallToolJSON(t, "announce", nil, &got)
+	if want := (user{ID: "7", Name: "Ann"}); got != want {
+		t.Errorf("got %+v from text content, want %+v", got, want)
+	}
+
+	var notifications []mcp.JSONRPCNotification
+	deadline := time.Now().Add(time.Second)
+	for len(notifications) == 0 && time.Now().Before(deadline) {
+		notifications = append(notifications, srv.DrainNotifications()...)
+		time.Sleep(5 * time.Millisecond)
+	}
+	if len(notifications) != 1 || notifications[0].Method != "notifications/announcement" {
+		t.Fatalf("expected one announcement, got %+v", notifications)
+	}
+	if rest := srv.DrainNotifications(); len(rest) != 0 {
+		t.Errorf("expected drained notifications to be cleared, got %+v", rest)
+	}
+}
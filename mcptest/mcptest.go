@@ -24,6 +24,8 @@ type Server struct {
 	prompts           []server.ServerPrompt
 	resources         []server.ServerResource
 	resourceTemplates []server.ServerResourceTemplate
+	serverOptions     []server.ServerOption
+	clientOptions     []client.ClientOption
 
 	cancel func()
 
@@ -34,9 +36,13 @@ type Server struct {
 
 	logBuffer bytes.Buffer
 
+	mcpServer *server.MCPServer
 	transport transport.Interface
 	client    *client.Client
 
+	notificationsMu sync.Mutex
+	notifications   []mcp.JSONRPCNotification
+
 	wg sync.WaitGroup
 }
 
@@ -127,17 +133,18 @@ func (s *Server) Start(ctx context.Context) error {
 
 	ctx, s.cancel = context.WithCancel(ctx)
 
+	mcpServer := server.NewMCPServer(s.name, "1.0.0", s.serverOptions...)
+
+	mcpServer.AddTools(s.tools...)
+	mcpServer.AddPrompts(s.prompts...)
+	mcpServer.AddResources(s.resources...)
+	mcpServer.AddResourceTemplates(s.resourceTemplates...)
+	s.mcpServer = mcpServer
+
 	// Start the MCP server in a goroutine
 	go func() {
 		defer s.wg.Done()
 
-		mcpServer := server.NewMCPServer(s.name, "1.0.0")
-
-		mcpServer.AddTools(s.tools...)
-		mcpServer.AddPrompts(s.prompts...)
-		mcpServer.AddResources(s.resources...)
-		mcpServer.AddResourceTemplates(s.resourceTemplates...)
-
 		logger := log.New(&s.logBuffer, "", 0)
 
 		stdioServer := server.NewStdioServer(mcpServer)
@@ -153,7 +160,15 @@ func (s *Server) Start(ctx context.Context) error {
 		return fmt.Errorf("transport.Start(): %w", err)
 	}
 
-	s.client = client.NewClient(s.transport)
+	s.client = client.NewClient(s.transport, s.clientOptions...)
+	s.client.OnNotification(func(notification mcp.JSONRPCNotification) {
+		s.notificationsMu.Lock()
+		defer s.notificationsMu.Unlock()
+		s.notifications = append(s.notifications, notification)
+	})
+	if err := s.client.Start(ctx); err != nil {
+		return fmt.Errorf("client.Start(): %w", err)
+	}
 
 	var initReq mcp.InitializeRequest
 	initReq.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
@@ -189,6 +204,12 @@ func (s *Server) Close() {
 	s.clientReader, s.clientWriter = nil, nil
 }
 
+// MCPServer returns the server under test, for example to send
+// notifications to the client. It is nil until the server is started.
+func (s *Server) MCPServer() *server.MCPServer {
+	return s.mcpServer
+}
+
 // Client returns an MCP client connected to the server.
 // The client is already initialized, i.e. you do _not_ need to call Client.Initialize().
 func (s *Server) Client() *client.Client {
@@ -0,0 +1,414 @@
+package mcptest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// StubOption configures a server created by NewStubServer.
+type StubOption func(*stub)
+
+// WithStubServerOptions passes options to the MCPServer of the stub, after
+// those derived from the recording.
+func WithStubServerOptions(opts ...server.ServerOption) StubOption {
+	return func(s *stub) {
+		s.serverOptions = append(s.serverOptions, opts...)
+	}
+}
+
+// WithLooseMatching makes the stub answer calls whose arguments were not
+// recorded with the responses recorded for the same tool, prompt or
+// resource with other arguments, rather than failing them.
+func WithLooseMatching() StubOption {
+	return func(s *stub) {
+		s.loose = true
+	}
+}
+
+// NewStubServer returns a server playing the server side of a recording
+// made with transport.Recorder, so that host applications can be tested
+// without the services behind the recorded server. The name, version,
+// instructions and capabilities of the server are taken from the recorded
+// initialize response, and its tools, prompts, resources and resource
+// templates from the recorded listings, as well as from the calls made to
+// them.
+//
+// Tool calls, prompt gets and resource reads are answered with the
+// response recorded for the same arguments or URI, ignoring _meta, in
+// order when several were recorded; the last one is repeated once they are
+// exhausted. Recorded errors are returned with their code. Requests that
+// were not recorded fail with an error matching
+// transport.ErrNoRecordedResponse.
+func NewStubServer(frames []transport.Frame, opts ...StubOption) (*server.MCPServer, error) {
+	s := &stub{responses: make(map[string][]*stubResponse)}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if err := s.load(frames); err != nil {
+		return nil, err
+	}
+	return s.build(), nil
+}
+
+// stub holds the exchanges of a recording.
+type stub struct {
+	serverOptions []server.ServerOption
+	loose         bool
+
+	initialize *mcp.InitializeResult
+	tools      []mcp.Tool
+	prompts    []mcp.Prompt
+	resources  []mcp.Resource
+	templates  []mcp.ResourceTemplate
+
+	// responses holds the recorded responses per method and name, tool,
+	// prompt or URI, in the order they were recorded.
+	responses map[string][]*stubResponse
+	// calledTools and gotPrompts list the tools and prompts called in the
+	// recording, for those that were not listed.
+	calledTools []string
+	gotPrompts  []string
+
+	mu     sync.Mutex
+	served map[*stubResponse]bool
+}
+
+// stubResponse is a recorded response to a request with params.
+type stubResponse struct {
+	params   any
+	response *transport.JSONRPCResponse
+}
+
+type stubMessage struct {
+	ID     *mcp.RequestID  `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// load pairs the requests the client sent in frames with their responses.
+func (s *stub) load(frames []transport.Frame) error {
+	pending := make(map[mcp.RequestID]stubMessage)
+	for _, frame := range frames {
+		var m stubMessage
+		if err := json.Unmarshal(frame.Message, &m); err != nil {
+			return fmt.Errorf("invalid recorded message: %w", err)
+		}
+		if m.ID == nil {
+			continue
+		}
+		switch {
+		case frame.Direction == transport.DirectionSent && m.Method != "":
+			pending[*m.ID] = m
+		case frame.Direction == transport.DirectionReceived && m.Method == "":
+			request, ok := pending[*m.ID]
+			if !ok {
+				continue
+			}
+			delete(pending, *m.ID)
+			response := &transport.JSONRPCResponse{}
+			if err := json.Unmarshal(frame.Message, response); err != nil {
+				return fmt.Errorf("invalid recorded response to %s: %w", request.Method, err)
+			}
+			if err := s.add(request, response); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// add records the response to request.
+func (s *stub) add(request stubMessage, response *transport.JSONRPCResponse) error {
+	var params struct {
+		Name      string `json:"name"`
+		URI       string `json:"uri"`
+		Arguments any    `json:"arguments"`
+	}
+	if len(request.Params) > 0 {
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			return fmt.Errorf("invalid recorded params of %s: %w", request.Method, err)
+		}
+	}
+	result := response.Result
+	switch mcp.MCPMethod(request.Method) {
+	case mcp.MethodInitialize:
+		if response.Error == nil {
+			s.initialize = &mcp.InitializeResult{}
+			if err := json.Unmarshal(result, s.initialize); err != nil {
+				return fmt.Errorf("invalid recorded initialize result: %w", err)
+			}
+		}
+	case mcp.MethodToolsList:
+		if response.Error == nil {
+			tools, err := recordedTools(result)
+			if err != nil {
+				return err
+			}
+			s.tools = appendByKey(s.tools, tools, func(tool mcp.Tool) string { return tool.Name })
+		}
+	case mcp.MethodPromptsList:
+		var listed mcp.ListPromptsResult
+		if response.Error == nil {
+			if err := json.Unmarshal(result, &listed); err != nil {
+				return fmt.Errorf("invalid recorded prompts/list result: %w", err)
+			}
+			s.prompts = appendByKey(s.prompts, listed.Prompts, func(prompt mcp.Prompt) string { return prompt.Name })
+		}
+	case mcp.MethodResourcesList:
+		var listed mcp.ListResourcesResult
+		if response.Error == nil {
+			if err := json.Unmarshal(result, &listed); err != nil {
+				return fmt.Errorf("invalid recorded resources/list result: %w", err)
+			}
+			s.resources = appendByKey(s.resources, listed.Resources, func(resource mcp.Resource) string { return resource.URI })
+		}
+	case mcp.MethodResourcesTemplatesList:
+		var listed mcp.ListResourceTemplatesResult
+		if response.Error == nil {
+			if err := json.Unmarshal(result, &listed); err != nil {
+				return fmt.Errorf("invalid recorded resources/templates/list result: %w", err)
+			}
+			for _, template := range listed.ResourceTemplates {
+				if template.URITemplate == nil {
+					return fmt.Errorf("recorded resource template %q has no URI template", template.Name)
+				}
+			}
+			s.templates = appendByKey(s.templates, listed.ResourceTemplates, func(template mcp.ResourceTemplate) string {
+				return template.URITemplate.Raw()
+			})
+		}
+	case mcp.MethodToolsCall:
+		if _, ok := s.responses[stubKey(request.Method, params.Name)]; !ok {
+			s.calledTools = append(s.calledTools, params.Name)
+		}
+		s.record(request.Method, params.Name, params.Arguments, response)
+	case mcp.MethodPromptsGet:
+		if _, ok := s.responses[stubKey(request.Method, params.Name)]; !ok {
+			s.gotPrompts = append(s.gotPrompts, params.Name)
+		}
+		s.record(request.Method, params.Name, params.Arguments, response)
+	case mcp.MethodResourcesRead:
+		s.record(request.Method, params.URI, nil, response)
+	}
+	return nil
+}
+
+func (s *stub) record(method, name string, arguments any, response *transport.JSONRPCResponse) {
+	key := stubKey(method, name)
+	s.responses[key] = append(s.responses[key], &stubResponse{params: arguments, response: response})
+}
+
+func stubKey(method, name string) string {
+	return method + " " + name
+}
+
+// recordedTools decodes the tools of a tools/list result, keeping their
+// schemas as recorded.
+func recordedTools(result json.RawMessage) ([]mcp.Tool, error) {
+	var listed struct {
+		Tools []json.RawMessage `json:"tools"`
+	}
+	if err := json.Unmarshal(result, &listed); err != nil {
+		return nil, fmt.Errorf("invalid recorded tools/list result: %w", err)
+	}
+	tools := make([]mcp.Tool, 0, len(listed.Tools))
+	for _, raw := range listed.Tools {
+		var tool mcp.Tool
+		var schemas struct {
+			InputSchema  json.RawMessage `json:"inputSchema"`
+			OutputSchema json.RawMessage `json:"outputSchema"`
+		}
+		if err := json.Unmarshal(raw, &tool); err != nil {
+			return nil, fmt.Errorf("invalid recorded tool: %w", err)
+		}
+		if err := json.Unmarshal(raw, &schemas); err != nil {
+			return nil, fmt.Errorf("invalid recorded tool: %w", err)
+		}
+		tool.InputSchema, tool.OutputSchema = mcp.ToolInputSchema{}, mcp.ToolOutputSchema{}
+		tool.RawInputSchema, tool.RawOutputSchema = schemas.InputSchema, schemas.OutputSchema
+		tools = append(tools, tool)
+	}
+	return tools, nil
+}
+
+// appendByKey appends the values of added to values, replacing those with
+// the same key.
+func appendByKey[T any](values, added []T, key func(T) string) []T {
+	for _, value := range added {
+		replaced := false
+		for i := range values {
+			if key(values[i]) == key(value) {
+				values[i], replaced = value, true
+				break
+			}
+		}
+		if !replaced {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// build creates the server of the stub.
+func (s *stub) build() *server.MCPServer {
+	name, version := "stub", "0.0.0"
+	var opts []server.ServerOption
+	if s.initialize != nil {
+		name, version = s.initialize.ServerInfo.Name, s.initialize.ServerInfo.Version
+		capabilities := s.initialize.Capabilities
+		if capabilities.Tools != nil {
+			opts = append(opts, server.WithToolCapabilities(capabilities.Tools.ListChanged))
+		}
+		if capabilities.Prompts != nil {
+			opts = append(opts, server.WithPromptCapabilities(capabilities.Prompts.ListChanged))
+		}
+		if capabilities.Resources != nil {
+			opts = append(opts, server.WithResourceCapabilities(capabilities.Resources.Subscribe, capabilities.Resources.ListChanged))
+		}
+		if capabilities.Logging != nil {
+			opts = append(opts, server.WithLogging())
+		}
+		if s.initialize.Instructions != "" {
+			opts = append(opts, server.WithInstructions(s.initialize.Instructions))
+		}
+	}
+	opts = append(opts, server.WithResourceResolver(s.resolveResource))
+	srv := server.NewMCPServer(name, version, append(opts, s.serverOptions...)...)
+
+	for _, tool := range s.tools {
+		srv.AddTool(tool, s.callTool)
+	}
+	for _, name := range s.calledTools {
+		if !containsKey(s.tools, name, func(tool mcp.Tool) string { return tool.Name }) {
+			srv.AddTool(mcp.NewTool(name), s.callTool)
+		}
+	}
+	for _, prompt := range s.prompts {
+		srv.AddPrompt(prompt, s.getPrompt)
+	}
+	for _, name := range s.gotPrompts {
+		if !containsKey(s.prompts, name, func(prompt mcp.Prompt) string { return prompt.Name }) {
+			srv.AddPrompt(mcp.NewPrompt(name), s.getPrompt)
+		}
+	}
+	for _, resource := range s.resources {
+		srv.AddResource(resource, s.readResource)
+	}
+	for _, template := range s.templates {
+		srv.AddResourceTemplate(template, s.readResource)
+	}
+	return srv
+}
+
+func containsKey[T any](values []T, k string, key func(T) string) bool {
+	for _, value := range values {
+		if key(value) == k {
+			return true
+		}
+	}
+	return false
+}
+
+// next returns the next recorded response of method for name and
+// arguments.
+func (s *stub) next(method, name string, arguments any) (*transport.JSONRPCResponse, error) {
+	recorded := s.responses[stubKey(method, name)]
+	var candidates []*stubResponse
+	for _, r := range recorded {
+		if equalArguments(r.params, arguments) {
+			candidates = append(candidates, r)
+		}
+	}
+	if len(candidates) == 0 && s.loose {
+		candidates = recorded
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w to %s %q with these arguments", transport.ErrNoRecordedResponse, method, name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.served == nil {
+		s.served = make(map[*stubResponse]bool)
+	}
+	for _, candidate := range candidates {
+		if !s.served[candidate] {
+			s.served[candidate] = true
+			return candidate.response, nil
+		}
+	}
+	return candidates[len(candidates)-1].response, nil
+}
+
+// equalArguments compares arguments as JSON values, a missing value being
+// equal to an empty object.
+func equalArguments(recorded, actual any) bool {
+	normalize := func(v any) any {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return v
+		}
+		var out any
+		_ = json.Unmarshal(data, &out)
+		if m, ok := out.(map[string]any); out == nil || ok && len(m) == 0 {
+			return nil
+		}
+		return out
+	}
+	return reflect.DeepEqual(normalize(recorded), normalize(actual))
+}
+
+func (s *stub) callTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	response, err := s.next(string(mcp.MethodToolsCall), request.Params.Name, request.Params.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	if response.Error != nil {
+		return nil, response.Error.ToError()
+	}
+	return mcp.ParseCallToolResult(&response.Result)
+}
+
+func (s *stub) getPrompt(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	response, err := s.next(string(mcp.MethodPromptsGet), request.Params.Name, request.Params.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	if response.Error != nil {
+		return nil, response.Error.ToError()
+	}
+	return mcp.ParseGetPromptResult(&response.Result)
+}
+
+func (s *stub) readResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	response, err := s.next(string(mcp.MethodResourcesRead), request.Params.URI, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", server.ErrResourceNotFound, err)
+	}
+	if response.Error != nil {
+		return nil, response.Error.ToError()
+	}
+	result, err := mcp.ParseReadResourceResult(&response.Result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Contents, nil
+}
+
+// resolveResource serves the resources read in the recording that were
+// neither listed nor served by a listed template.
+func (s *stub) resolveResource(ctx context.Context, uri string) (*server.ResolvedResource, error) {
+	if _, ok := s.responses[stubKey(string(mcp.MethodResourcesRead), uri)]; !ok {
+		return nil, nil
+	}
+	return &server.ResolvedResource{
+		ServerResource: server.ServerResource{Resource: mcp.NewResource(uri, uri), Handler: s.readResource},
+	}, nil
+}
@@ -0,0 +1,182 @@
+package mcptest_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/mcptest"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// newBackedServer returns a server standing for one backed by external
+// services.
+func newBackedServer() *server.MCPServer {
+	s := server.NewMCPServer("weather", "2.1.0",
+		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(false, false),
+		server.WithPromptCapabilities(false),
+		server.WithInstructions("Ask about the weather."),
+	)
+	s.AddTool(mcp.NewTool("forecast",
+		mcp.WithString("city", mcp.Required()),
+		mcp.WithReadOnlyHintAnnotation(true),
+	), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		city := request.GetString("city", "")
+		if city == "Atlantis" {
+			return nil, mcp.NewError(mcp.INVALID_PARAMS, "unknown city", nil)
+		}
+		return mcp.NewToolResultText("sunny in " + city), nil
+	})
+	s.AddResource(mcp.NewResource("weather://stations", "stations"), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: "paris, oslo"}}, nil
+	})
+	s.AddResourceTemplate(mcp.NewResourceTemplate("weather://stations/{id}", "station"), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: "station " + request.Params.URI}}, nil
+	})
+	s.AddPrompt(mcp.NewPrompt("report", mcp.WithArgument("city")), func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return mcp.NewGetPromptResult("report", []mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent("report for "+request.Params.Arguments["city"])),
+		}), nil
+	})
+	return s
+}
+
+func startClient(t *testing.T, trans transport.Interface) *client.Client {
+	t.Helper()
+	c := client.NewClient(trans)
+	t.Cleanup(func() { _ = c.Close() })
+	require.NoError(t, c.Start(context.Background()))
+	request := mcp.InitializeRequest{}
+	request.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	_, err := c.Initialize(context.Background(), request)
+	require.NoError(t, err)
+	return c
+}
+
+func callForecast(c *client.Client, city string) (*mcp.CallToolResult, error) {
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "forecast"
+	request.Params.Arguments = map[string]any{"city": city}
+	return c.CallTool(context.Background(), request)
+}
+
+func readResource(c *client.Client, uri string) (*mcp.ReadResourceResult, error) {
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = uri
+	return c.ReadResource(context.Background(), request)
+}
+
+// record exercises a session with the server and returns its recording.
+func record(t *testing.T) []transport.Frame {
+	t.Helper()
+	var recording bytes.Buffer
+	c := startClient(t, transport.NewRecorder(transport.NewInProcessTransport(newBackedServer()), &recording))
+	ctx := context.Background()
+
+	_, err := c.ListTools(ctx, mcp.ListToolsRequest{})
+	require.NoError(t, err)
+	_, err = c.ListResources(ctx, mcp.ListResourcesRequest{})
+	require.NoError(t, err)
+	_, err = c.ListResourceTemplates(ctx, mcp.ListResourceTemplatesRequest{})
+	require.NoError(t, err)
+	_, err = c.ListPrompts(ctx, mcp.ListPromptsRequest{})
+	require.NoError(t, err)
+	_, err = callForecast(c, "Paris")
+	require.NoError(t, err)
+	_, err = callForecast(c, "Atlantis")
+	require.Error(t, err)
+	_, err = readResource(c, "weather://stations")
+	require.NoError(t, err)
+	_, err = readResource(c, "weather://stations/7")
+	require.NoError(t, err)
+	getPrompt := mcp.GetPromptRequest{}
+	getPrompt.Params.Name = "report"
+	getPrompt.Params.Arguments = map[string]string{"city": "Oslo"}
+	_, err = c.GetPrompt(ctx, getPrompt)
+	require.NoError(t, err)
+
+	frames, err := transport.ReadRecording(&recording)
+	require.NoError(t, err)
+	return frames
+}
+
+func TestNewStubServer(t *testing.T) {
+	frames := record(t)
+	stub, err := mcptest.NewStubServer(frames)
+	require.NoError(t, err)
+
+	// The stub answers the recorded session as the server did.
+	mismatches, err := transport.ReplayToServer(context.Background(), stub, frames)
+	require.NoError(t, err)
+	for _, mismatch := range mismatches {
+		t.Errorf("request %s: recorded %s, stub answered %s", mismatch.Request, mismatch.Recorded, mismatch.Actual)
+	}
+
+	c := startClient(t, transport.NewInProcessTransport(stub))
+	result, err := callForecast(c, "Paris")
+	require.NoError(t, err)
+	assert.Equal(t, "sunny in Paris", result.Content[0].(mcp.TextContent).Text)
+
+	_, err = callForecast(c, "Atlantis")
+	var mcpErr *mcp.Error
+	require.ErrorAs(t, err, &mcpErr)
+	assert.Equal(t, mcp.INVALID_PARAMS, mcpErr.Code)
+
+	_, err = callForecast(c, "Rome")
+	assert.ErrorContains(t, err, transport.ErrNoRecordedResponse.Error())
+
+	read, err := readResource(c, "weather://stations/7")
+	require.NoError(t, err)
+	assert.Equal(t, "station weather://stations/7", read.Contents[0].(mcp.TextResourceContents).Text)
+	_, err = readResource(c, "weather://stations/8")
+	assert.Error(t, err)
+}
+
+func TestNewStubServer_LooseMatchingAndSequences(t *testing.T) {
+	var recording bytes.Buffer
+	calls := 0
+	backed := server.NewMCPServer("counter", "1.0.0")
+	backed.AddTool(mcp.NewTool("next"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		return mcp.NewToolResultText(string(rune('0' + calls))), nil
+	})
+	c := startClient(t, transport.NewRecorder(transport.NewInProcessTransport(backed), &recording))
+	next := func(c *client.Client, args map[string]any) string {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = "next"
+		request.Params.Arguments = args
+		result, err := c.CallTool(context.Background(), request)
+		require.NoError(t, err)
+		return result.Content[0].(mcp.TextContent).Text
+	}
+	next(c, nil)
+	next(c, nil)
+	frames, err := transport.ReadRecording(&recording)
+	require.NoError(t, err)
+
+	stub, err := mcptest.NewStubServer(frames, mcptest.WithLooseMatching())
+	require.NoError(t, err)
+	c = startClient(t, transport.NewInProcessTransport(stub))
+	assert.Equal(t, []string{"1", "2", "2"}, []string{next(c, nil), next(c, nil), next(c, nil)},
+		"recorded responses are replayed in order, repeating the last one")
+	assert.Equal(t, "2", next(c, map[string]any{"unrecorded": true}))
+
+	tools, err := c.ListTools(context.Background(), mcp.ListToolsRequest{})
+	require.NoError(t, err)
+	require.Len(t, tools.Tools, 1, "called tools are served even if they were not listed")
+	assert.Equal(t, "next", tools.Tools[0].Name)
+}
+
+func TestNewStubServer_InvalidRecording(t *testing.T) {
+	_, err := mcptest.NewStubServer([]transport.Frame{{Direction: transport.DirectionSent, Message: []byte(`[`)}})
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, transport.ErrNoRecordedResponse))
+}
@@ -0,0 +1,237 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// AuditOutcome is the outcome of an audited request.
+type AuditOutcome string
+
+const (
+	// AuditSuccess means the request succeeded.
+	AuditSuccess AuditOutcome = "success"
+	// AuditToolError means the tool ran but returned a result flagged as
+	// an error.
+	AuditToolError AuditOutcome = "tool_error"
+	// AuditError means the request failed with a JSON-RPC error.
+	AuditError AuditOutcome = "error"
+)
+
+// AuditRecord describes one audited request.
+type AuditRecord struct {
+	// Time is when the request was received.
+	Time time.Time `json:"time"`
+	// Method is tools/call or resources/read.
+	Method    mcp.MCPMethod `json:"method"`
+	RequestID any           `json:"requestId,omitempty"`
+	SessionID string        `json:"sessionId,omitempty"`
+	// Subject is the authenticated caller, as set by WithAuthFunc.
+	Subject string `json:"subject,omitempty"`
+	// Tool is the name of the tool called.
+	Tool string `json:"tool,omitempty"`
+	// URI is the URI of the resource read.
+	URI string `json:"uri,omitempty"`
+	// Arguments are the arguments of the tool call, with the fields set by
+	// WithAuditRedactFields and WithRedaction masked.
+	Arguments map[string]any `json:"arguments,omitempty"`
+	// Duration is how long the request took to handle, in nanoseconds when
+	// encoded to JSON.
+	Duration time.Duration `json:"duration"`
+	Outcome  AuditOutcome  `json:"outcome"`
+	// ErrorCode and Error describe the JSON-RPC error of a failed request.
+	ErrorCode int    `json:"errorCode,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// AuditSink stores audit records. Record is called synchronously once the
+// request is handled, before the response is sent, so it should be fast or
+// hand records off to another goroutine.
+type AuditSink interface {
+	Record(ctx context.Context, record AuditRecord) error
+}
+
+// AuditOption configures WithAudit.
+type AuditOption func(*auditor)
+
+// WithAuditRedactFields masks the values of the named argument fields, at
+// any depth and regardless of case, in audit records. Use it for fields
+// carrying secrets such as "password" or "apiKey".
+func WithAuditRedactFields(fields ...string) AuditOption {
+	return func(a *auditor) {
+		for _, field := range fields {
+			a.redact[strings.ToLower(field)] = true
+		}
+	}
+}
+
+// WithAuditErrorHandler sets a function called when the sink fails to
+// record a request. Such errors are ignored by default.
+func WithAuditErrorHandler(handler func(err error)) AuditOption {
+	return func(a *auditor) {
+		a.onError = handler
+	}
+}
+
+// WithAudit records every tools/call and resources/read request to sink:
+// who made it from which session, the tool arguments, how long it took and
+// how it ended.
+func WithAudit(sink AuditSink, opts ...AuditOption) ServerOption {
+	return func(s *MCPServer) {
+		a := &auditor{sink: sink, redact: make(map[string]bool)}
+		for _, opt := range opts {
+			opt(a)
+		}
+		s.auditor = a
+	}
+}
+
+// RedactedValue replaces the values of redacted fields.
+const RedactedValue = "[REDACTED]"
+
+// auditor builds the audit records of a server.
+type auditor struct {
+	sink    AuditSink
+	redact  map[string]bool
+	onError func(err error)
+}
+
+// startAudit starts the audit record of a request, if it is audited, and
+// returns a function recording it with the response.
+func (s *MCPServer) startAudit(
+	ctx context.Context,
+	method mcp.MCPMethod,
+	id any,
+	message json.RawMessage,
+) func(response mcp.JSONRPCMessage) {
+	a := s.auditor
+	if a == nil || (method != mcp.MethodToolsCall && method != mcp.MethodResourcesRead) {
+		return func(mcp.JSONRPCMessage) {}
+	}
+
+	record := AuditRecord{Time: time.Now(), Method: method, RequestID: id}
+	var request struct {
+		Params struct {
+			Name      string         `json:"name"`
+			URI       string         `json:"uri"`
+			Arguments map[string]any `json:"arguments"`
+		} `json:"params"`
+	}
+	// Malformed params are audited as such, with the error of the request.
+	_ = json.Unmarshal(message, &request)
+	record.Tool = request.Params.Name
+	record.URI = request.Params.URI
+	if request.Params.Arguments != nil {
+		arguments := s.RedactArguments(ctx, request.Params.Name, request.Params.Arguments)
+		record.Arguments, _ = a.redactFields(arguments).(map[string]any)
+	}
+	if session := ClientSessionFromContext(ctx); session != nil {
+		record.SessionID = session.SessionID()
+	}
+	if info, ok := AuthInfoFromContext(ctx); ok {
+		record.Subject = info.Subject
+	}
+
+	return func(response mcp.JSONRPCMessage) {
+		record.Duration = time.Since(record.Time)
+		switch response := response.(type) {
+		case mcp.JSONRPCError:
+			record.Outcome = AuditError
+			record.ErrorCode = response.Error.Code
+			record.Error = response.Error.Message
+		case mcp.JSONRPCResponse:
+			record.Outcome = AuditSuccess
+			if result, ok := response.Result.(mcp.CallToolResult); ok && result.IsError {
+				record.Outcome = AuditToolError
+			}
+		default:
+			record.Outcome = AuditError
+			record.Error = "no response"
+		}
+		if err := a.sink.Record(ctx, record); err != nil && a.onError != nil {
+			a.onError(err)
+		}
+	}
+}
+
+// redactFields returns a copy of value with the redacted fields masked.
+func (a *auditor) redactFields(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		redacted := make(map[string]any, len(value))
+		for key, field := range value {
+			if a.redact[strings.ToLower(key)] {
+				redacted[key] = RedactedValue
+			} else {
+				redacted[key] = a.redactFields(field)
+			}
+		}
+		return redacted
+	case []any:
+		redacted := make([]any, len(value))
+		for i, item := range value {
+			redacted[i] = a.redactFields(item)
+		}
+		return redacted
+	default:
+		return value
+	}
+}
+
+// JSONLAuditSink writes audit records to a writer as JSON lines.
+type JSONLAuditSink struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+}
+
+// NewJSONLAuditSink returns a sink writing one JSON object per line to w.
+func NewJSONLAuditSink(w io.Writer) *JSONLAuditSink {
+	return &JSONLAuditSink{w: w}
+}
+
+// NewStdoutAuditSink returns a sink writing JSON lines to standard output.
+// It must not be used with the stdio transport, whose protocol messages go
+// to standard output.
+func NewStdoutAuditSink() *JSONLAuditSink {
+	return NewJSONLAuditSink(os.Stdout)
+}
+
+// OpenJSONLAuditFile returns a sink appending JSON lines to the file at
+// path, which is created if needed. Close the sink to close the file.
+func OpenJSONLAuditFile(path string) (*JSONLAuditSink, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONLAuditSink{w: f, closer: f}, nil
+}
+
+// Record writes record as a line of JSON.
+func (s *JSONLAuditSink) Record(_ context.Context, record AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(line)
+	return err
+}
+
+// Close closes the file of a sink opened with OpenJSONLAuditFile. It does
+// nothing for other sinks.
+func (s *JSONLAuditSink) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
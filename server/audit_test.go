@@ -0,0 +1,103 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type memoryAuditSink struct {
+	mu      sync.Mutex
+	records []AuditRecord
+}
+
+func (s *memoryAuditSink) Record(_ context.Context, record AuditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+	return nil
+}
+
+func TestMCPServer_WithAudit(t *testing.T) {
+	sink := &memoryAuditSink{}
+	server := NewMCPServer("test-server", "1.0.0",
+		WithResourceCapabilities(false, false),
+		WithAudit(sink, WithAuditRedactFields("password", "APIKey")))
+	server.AddTool(mcp.NewTool("login"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("welcome"), nil
+	})
+	server.AddTool(mcp.NewTool("fail"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("nope"), nil
+	})
+	server.AddResource(mcp.NewResource("test://doc", "doc"), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: "doc"}}, nil
+	})
+
+	session := &fakeSession{sessionID: "session-1", notificationChannel: make(chan mcp.JSONRPCNotification, 1)}
+	ctx := WithAuthInfo(server.WithContext(context.Background(), session), AuthInfo{Subject: "alice"})
+	for _, message := range []string{
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"login","arguments":{"user":"alice","password":"hunter2","nested":[{"apiKey":"k"}]}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"fail"}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"missing"}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"resources/read","params":{"uri":"test://doc"}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/list"}`,
+	} {
+		server.HandleMessage(ctx, json.RawMessage(message))
+	}
+
+	require.Len(t, sink.records, 4, "only tool calls and resource reads are audited")
+	login := sink.records[0]
+	assert.Equal(t, mcp.MethodToolsCall, login.Method)
+	assert.Equal(t, "login", login.Tool)
+	assert.Equal(t, "session-1", login.SessionID)
+	assert.Equal(t, "alice", login.Subject)
+	assert.Equal(t, AuditSuccess, login.Outcome)
+	assert.Equal(t, map[string]any{
+		"user":     "alice",
+		"password": RedactedValue,
+		"nested":   []any{map[string]any{"apiKey": RedactedValue}},
+	}, login.Arguments)
+	assert.Positive(t, login.Duration)
+
+	assert.Equal(t, AuditToolError, sink.records[1].Outcome)
+	assert.Equal(t, AuditError, sink.records[2].Outcome)
+	assert.Equal(t, mcp.INVALID_PARAMS, sink.records[2].ErrorCode)
+	assert.Contains(t, sink.records[2].Error, "missing")
+	assert.Equal(t, mcp.MethodResourcesRead, sink.records[3].Method)
+	assert.Equal(t, "test://doc", sink.records[3].URI)
+	assert.Equal(t, AuditSuccess, sink.records[3].Outcome)
+}
+
+func TestJSONLAuditSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewJSONLAuditSink(&buf)
+	require.NoError(t, sink.Record(context.Background(), AuditRecord{Method: mcp.MethodToolsCall, Tool: "a", Outcome: AuditSuccess}))
+	require.NoError(t, sink.Record(context.Background(), AuditRecord{Method: mcp.MethodToolsCall, Tool: "b", Outcome: AuditError}))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	var record AuditRecord
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+	assert.Equal(t, "b", record.Tool)
+	assert.Equal(t, AuditError, record.Outcome)
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	for range 2 {
+		fileSink, err := OpenJSONLAuditFile(path)
+		require.NoError(t, err)
+		require.NoError(t, fileSink.Record(context.Background(), AuditRecord{Tool: "c"}))
+		require.NoError(t, fileSink.Close())
+	}
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(data), "\n"), "the file is appended to")
+}
@@ -0,0 +1,184 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrUnauthorized indicates missing or invalid credentials. An AuthFunc
+	// returning it causes a 401 response.
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrForbidden indicates valid credentials that lack the required
+	// permissions. An AuthFunc returning it causes a 403 response.
+	ErrForbidden = errors.New("forbidden")
+)
+
+// AuthInfo describes the authenticated caller of an HTTP request.
+// It is stored in the context of every request handler and can be read back
+// with AuthInfoFromContext, for example from a ToolCallCheckFunc to make
+// per-tool authorization decisions.
+type AuthInfo struct {
+	// Subject identifies the caller, such as a user or client ID.
+	Subject string
+	// Scopes are the permissions granted to the caller.
+	Scopes []string
+	// Token is the credential presented with the request.
+	Token string
+	// ExpiresAt is when the credential expires; zero if unknown.
+	ExpiresAt time.Time
+	// Extra holds any additional claims the AuthFunc wants to expose.
+	Extra map[string]any
+}
+
+// HasScope reports whether the caller was granted scope.
+func (a AuthInfo) HasScope(scope string) bool {
+	return slices.Contains(a.Scopes, scope)
+}
+
+// AuthFunc authenticates an incoming HTTP request. Returning an error
+// rejects the request: errors wrapping ErrForbidden produce a 403 response,
+// all others a 401. Return an *AuthError to control the response precisely.
+type AuthFunc func(r *http.Request) (AuthInfo, error)
+
+// AuthError is an error an AuthFunc can return to control the rejection
+// response and its WWW-Authenticate challenge (RFC 6750 section 3).
+type AuthError struct {
+	// StatusCode is the HTTP status to respond with; defaults to 401.
+	StatusCode int
+	// Code is the RFC 6750 error code, such as "invalid_token" or
+	// "insufficient_scope".
+	Code string
+	// Description is a human-readable explanation sent as error_description.
+	Description string
+	// Scopes lists the scopes needed to access the resource.
+	Scopes []string
+}
+
+func (e *AuthError) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("%s: %s", e.Code, e.Description)
+	}
+	return e.Code
+}
+
+// Is matches ErrForbidden for 403 errors and ErrUnauthorized otherwise.
+func (e *AuthError) Is(target error) bool {
+	if e.StatusCode == http.StatusForbidden {
+		return target == ErrForbidden
+	}
+	return target == ErrUnauthorized
+}
+
+type authInfoKey struct{}
+
+// AuthInfoFromContext returns the AuthInfo stored by the HTTP transport's
+// AuthFunc, if any.
+func AuthInfoFromContext(ctx context.Context) (AuthInfo, bool) {
+	info, ok := ctx.Value(authInfoKey{}).(AuthInfo)
+	return info, ok
+}
+
+// WithAuthInfo returns a copy of ctx carrying info. It is useful for tests
+// and for transports that authenticate callers themselves.
+func WithAuthInfo(ctx context.Context, info AuthInfo) context.Context {
+	return context.WithValue(ctx, authInfoKey{}, info)
+}
+
+// BearerToken extracts the token from an "Authorization: Bearer <token>"
+// header. The scheme is matched case-insensitively.
+func BearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// WithAuthFunc authenticates every request to the StreamableHTTP server
+// with fn before it is handled. Rejected requests receive a 401 or 403
+// response with a WWW-Authenticate header; accepted requests carry the
+// returned AuthInfo in their context.
+func WithAuthFunc(fn AuthFunc) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		s.authFunc = fn
+	}
+}
+
+// WithAuthResourceMetadataURL sets the protected resource metadata URL
+// (RFC 9728) advertised in the WWW-Authenticate header of rejected requests,
+// which lets OAuth clients discover the authorization server.
+func WithAuthResourceMetadataURL(metadataURL string) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		s.authResourceMetadataURL = metadataURL
+	}
+}
+
+// authenticate runs the configured AuthFunc. On success it returns the
+// request with the AuthInfo in its context; on failure it writes the
+// rejection response and returns nil.
+func (s *StreamableHTTPServer) authenticate(w http.ResponseWriter, r *http.Request) *http.Request {
+	if s.authFunc == nil {
+		return r
+	}
+	info, err := s.authFunc(r)
+	if err == nil {
+		return r.WithContext(WithAuthInfo(r.Context(), info))
+	}
+
+	authErr := &AuthError{StatusCode: http.StatusUnauthorized, Code: "invalid_token", Description: err.Error()}
+	var custom *AuthError
+	switch {
+	case errors.As(err, &custom):
+		authErr = custom
+		if authErr.StatusCode == 0 {
+			authErr.StatusCode = http.StatusUnauthorized
+		}
+	case errors.Is(err, ErrForbidden):
+		authErr.StatusCode = http.StatusForbidden
+		authErr.Code = "insufficient_scope"
+	default:
+		// RFC 6750 section 3.1: requests without credentials get a bare challenge
+		if _, ok := BearerToken(r); !ok {
+			authErr.Code = ""
+			authErr.Description = ""
+		}
+	}
+
+	w.Header().Set("WWW-Authenticate", s.authChallenge(authErr))
+	http.Error(w, http.StatusText(authErr.StatusCode), authErr.StatusCode)
+	return nil
+}
+
+// authChallenge builds a Bearer WWW-Authenticate challenge for err.
+func (s *StreamableHTTPServer) authChallenge(err *AuthError) string {
+	var params []string
+	if err.Code != "" {
+		params = append(params, "error="+quoteAuthParam(err.Code))
+	}
+	if err.Description != "" {
+		params = append(params, "error_description="+quoteAuthParam(err.Description))
+	}
+	if len(err.Scopes) > 0 {
+		params = append(params, "scope="+quoteAuthParam(strings.Join(err.Scopes, " ")))
+	}
+	if s.authResourceMetadataURL != "" {
+		params = append(params, "resource_metadata="+quoteAuthParam(s.authResourceMetadataURL))
+	}
+	if len(params) == 0 {
+		return "Bearer"
+	}
+	return "Bearer " + strings.Join(params, ", ")
+}
+
+// quoteAuthParam formats v as an HTTP quoted-string.
+func quoteAuthParam(v string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(v) + `"`
+}
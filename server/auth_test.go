@@ -0,0 +1,187 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func testAuthFunc(r *http.Request) (AuthInfo, error) {
+	token, ok := BearerToken(r)
+	if !ok {
+		return AuthInfo{}, ErrUnauthorized
+	}
+	switch token {
+	case "admin-token":
+		return AuthInfo{Subject: "alice", Scopes: []string{"tools:admin"}, Token: token}, nil
+	case "user-token":
+		return AuthInfo{Subject: "bob", Token: token}, nil
+	case "suspended-token":
+		return AuthInfo{}, fmt.Errorf("account suspended: %w", ErrForbidden)
+	case "custom-token":
+		return AuthInfo{}, &AuthError{StatusCode: http.StatusForbidden, Code: "insufficient_scope", Scopes: []string{"mcp:read"}}
+	}
+	return AuthInfo{}, errors.New("token expired")
+}
+
+func postWithToken(t *testing.T, url, token string, body any) *http.Response {
+	t.Helper()
+	jsonBody, err := json.Marshal(body)
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(jsonBody))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func TestStreamableHTTP_WithAuthFunc(t *testing.T) {
+	mcpServer := NewMCPServer("test-server", "1.0.0",
+		WithToolCallCheck(func(ctx context.Context, request mcp.CallToolRequest) error {
+			info, ok := AuthInfoFromContext(ctx)
+			if request.Params.Name == "admin" && (!ok || !info.HasScope("tools:admin")) {
+				return fmt.Errorf("tool 'admin' requires scope tools:admin: %w", ErrForbidden)
+			}
+			return nil
+		}),
+	)
+	mcpServer.AddTool(mcp.NewTool("whoami"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		info, _ := AuthInfoFromContext(ctx)
+		return mcp.NewToolResultText(info.Subject), nil
+	})
+	mcpServer.AddTool(mcp.NewTool("admin"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("done"), nil
+	})
+
+	server := httptest.NewServer(NewStreamableHTTPServer(mcpServer,
+		WithAuthFunc(testAuthFunc),
+		WithAuthResourceMetadataURL("https://mcp.example.com/.well-known/oauth-protected-resource"),
+	))
+	defer server.Close()
+	url := server.URL + "/mcp"
+
+	t.Run("rejections", func(t *testing.T) {
+		tests := []struct {
+			name              string
+			token             string
+			expectedStatus    int
+			expectedChallenge string
+		}{
+			{
+				name:              "missing token",
+				expectedStatus:    http.StatusUnauthorized,
+				expectedChallenge: `Bearer resource_metadata="https://mcp.example.com/.well-known/oauth-protected-resource"`,
+			},
+			{
+				name:              "invalid token",
+				token:             "bogus",
+				expectedStatus:    http.StatusUnauthorized,
+				expectedChallenge: `Bearer error="invalid_token", error_description="token expired", resource_metadata="https://mcp.example.com/.well-known/oauth-protected-resource"`,
+			},
+			{
+				name:              "forbidden",
+				token:             "suspended-token",
+				expectedStatus:    http.StatusForbidden,
+				expectedChallenge: `Bearer error="insufficient_scope", error_description="account suspended: forbidden", resource_metadata="https://mcp.example.com/.well-known/oauth-protected-resource"`,
+			},
+			{
+				name:              "custom auth error",
+				token:             "custom-token",
+				expectedStatus:    http.StatusForbidden,
+				expectedChallenge: `Bearer error="insufficient_scope", scope="mcp:read", resource_metadata="https://mcp.example.com/.well-known/oauth-protected-resource"`,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				resp := postWithToken(t, url, tt.token, initRequest)
+				defer resp.Body.Close()
+				assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+				assert.Equal(t, tt.expectedChallenge, resp.Header.Get("WWW-Authenticate"))
+			})
+		}
+	})
+
+	callTool := func(t *testing.T, token, name string) map[string]any {
+		t.Helper()
+		resp := postWithToken(t, url, token, initRequest)
+		sessionID := resp.Header.Get(HeaderKeySessionID)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		jsonBody, _ := json.Marshal(map[string]any{
+			"jsonrpc": "2.0",
+			"id":      2,
+			"method":  "tools/call",
+			"params":  map[string]any{"name": name},
+		})
+		req, _ := http.NewRequest(http.MethodPost, url, bytes.NewReader(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set(HeaderKeySessionID, sessionID)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(body, &response))
+		return response
+	}
+
+	t.Run("auth info reaches handlers", func(t *testing.T) {
+		response := callTool(t, "user-token", "whoami")
+		result := response["result"].(map[string]any)
+		content := result["content"].([]any)[0].(map[string]any)
+		assert.Equal(t, "bob", content["text"])
+	})
+
+	t.Run("per-tool authorization", func(t *testing.T) {
+		response := callTool(t, "user-token", "admin")
+		assert.Contains(t, response, "error")
+
+		response = callTool(t, "admin-token", "admin")
+		assert.Contains(t, response, "result")
+	})
+}
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+		ok       bool
+	}{
+		{header: "Bearer abc", expected: "abc", ok: true},
+		{header: "bearer  abc ", expected: "abc", ok: true},
+		{header: "Basic abc"},
+		{header: "Bearer "},
+		{header: ""},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.header != "" {
+			r.Header.Set("Authorization", tt.header)
+		}
+		token, ok := BearerToken(r)
+		assert.Equal(t, tt.ok, ok, tt.header)
+		assert.Equal(t, tt.expected, token, tt.header)
+	}
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// WithAutoCapabilities makes the server advertise its capabilities from
+// what it serves when a client initializes, so that no capability options
+// are needed:
+//
+//   - tools, resources and prompts are advertised once any has been
+//     registered, for all clients, a session or a tenant, with listChanged
+//     set so that clients learn about later registrations
+//   - logging is advertised to sessions that support logging/setLevel
+//
+// Capabilities configured with options such as WithToolCapabilities are
+// advertised as configured. Each session is only sent the list_changed
+// notifications of the capabilities advertised to it: a client that
+// initialized before the first prompt was registered is not sent
+// notifications/prompts/list_changed, as it was not told the server has
+// prompts.
+func WithAutoCapabilities() ServerOption {
+	return func(s *MCPServer) {
+		s.autoCapabilities = true
+	}
+}
+
+// detectCapabilities enables the capabilities the session of an initialize
+// request can use under WithAutoCapabilities. Tools, resources and prompts
+// are enabled as they are registered.
+func (s *MCPServer) detectCapabilities(session ClientSession) {
+	if !s.autoCapabilities {
+		return
+	}
+	if _, ok := session.(SessionWithLogging); ok {
+		s.implicitlyRegisterCapabilities(
+			func() bool { return s.capabilities.logging != nil },
+			func() { s.capabilities.logging = mcp.ToBoolPtr(true) },
+		)
+	}
+}
+
+// recordCapabilities remembers the capabilities advertised to session under
+// WithAutoCapabilities.
+func (s *MCPServer) recordCapabilities(session ClientSession, capabilities mcp.ServerCapabilities) {
+	if !s.autoCapabilities || session.SessionID() == "" {
+		return
+	}
+	s.sessionCapabilities.Store(session.SessionID(), capabilities)
+}
+
+// advertisesListChanged reports whether session may be sent the
+// notification of the given method: under WithAutoCapabilities, list_changed
+// notifications are only sent to the sessions that were advertised the
+// listChanged capability of the list.
+func (s *MCPServer) advertisesListChanged(session ClientSession, method string) bool {
+	if !s.autoCapabilities {
+		return true
+	}
+	value, ok := s.sessionCapabilities.Load(session.SessionID())
+	if !ok {
+		return true
+	}
+	capabilities := value.(mcp.ServerCapabilities)
+	switch method {
+	case mcp.MethodNotificationToolsListChanged:
+		return capabilities.Tools != nil && capabilities.Tools.ListChanged
+	case mcp.MethodNotificationResourcesListChanged:
+		return capabilities.Resources != nil && capabilities.Resources.ListChanged
+	case mcp.MethodNotificationPromptsListChanged:
+		return capabilities.Prompts != nil && capabilities.Prompts.ListChanged
+	}
+	return true
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// initializeWithSession registers session with server and sends it an
+// initialize request, returning the advertised capabilities as JSON.
+func initializeWithSession(t *testing.T, server *MCPServer, session ClientSession) string {
+	t.Helper()
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+	response := server.HandleMessage(server.WithContext(context.Background(), session),
+		[]byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1.0.0"}}}`))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "response %#v", response)
+	result, ok := resp.Result.(mcp.InitializeResult)
+	require.True(t, ok)
+	capabilities, err := json.Marshal(result.Capabilities)
+	require.NoError(t, err)
+	return string(capabilities)
+}
+
+func TestMCPServer_WithAutoCapabilities(t *testing.T) {
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+	tests := []struct {
+		name     string
+		options  []ServerOption
+		register func(s *MCPServer)
+		session  ClientSession
+		want     string
+	}{
+		{
+			name:     "nothing registered",
+			register: func(s *MCPServer) {},
+			session:  &sessionTestClient{sessionID: "s", notificationChannel: make(chan mcp.JSONRPCNotification, 10)},
+			want:     `{}`,
+		},
+		{
+			name: "tools, resources and prompts registered",
+			register: func(s *MCPServer) {
+				s.AddTool(mcp.NewTool("echo"), handler)
+				s.AddResource(mcp.NewResource("file:///a", "a"), nil)
+				s.AddPrompt(mcp.NewPrompt("greet"), nil)
+			},
+			session: &sessionTestClient{sessionID: "s", notificationChannel: make(chan mcp.JSONRPCNotification, 10)},
+			want:    `{"prompts":{"listChanged":true},"resources":{"listChanged":true},"tools":{"listChanged":true}}`,
+		},
+		{
+			name: "resource templates registered",
+			register: func(s *MCPServer) {
+				s.AddResourceTemplate(mcp.NewResourceTemplate("file:///{name}", "files"), nil)
+			},
+			session: &sessionTestClient{sessionID: "s", notificationChannel: make(chan mcp.JSONRPCNotification, 10)},
+			want:    `{"resources":{"listChanged":true}}`,
+		},
+		{
+			name:     "session supporting logging",
+			register: func(s *MCPServer) {},
+			session:  &sessionTestClientWithLogging{sessionID: "s", notificationChannel: make(chan mcp.JSONRPCNotification, 10)},
+			want:     `{"logging":{}}`,
+		},
+		{
+			name:    "configured capabilities",
+			options: []ServerOption{WithToolCapabilities(false), WithResourceCapabilities(true, false)},
+			register: func(s *MCPServer) {
+				s.AddTool(mcp.NewTool("echo"), handler)
+				s.AddPrompt(mcp.NewPrompt("greet"), nil)
+			},
+			session: &sessionTestClient{sessionID: "s", notificationChannel: make(chan mcp.JSONRPCNotification, 10)},
+			want:    `{"prompts":{"listChanged":true},"resources":{"subscribe":true},"tools":{}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMCPServer("test", "1.0.0", append([]ServerOption{WithAutoCapabilities()}, tt.options...)...)
+			tt.register(server)
+			assert.JSONEq(t, tt.want, initializeWithSession(t, server, tt.session))
+		})
+	}
+}
+
+func TestMCPServer_WithAutoCapabilities_ListChanged(t *testing.T) {
+	server := NewMCPServer("test", "1.0.0", WithAutoCapabilities())
+	server.AddTool(mcp.NewTool("echo"), nil)
+
+	early := &sessionTestClient{sessionID: "early", notificationChannel: make(chan mcp.JSONRPCNotification, 10)}
+	assert.JSONEq(t, `{"tools":{"listChanged":true}}`, initializeWithSession(t, server, early))
+
+	// Prompts are registered after the first session initialized: only the
+	// sessions that were advertised them are told about changes.
+	server.AddPrompt(mcp.NewPrompt("greet"), nil)
+	late := &sessionTestClient{sessionID: "late", notificationChannel: make(chan mcp.JSONRPCNotification, 10)}
+	assert.JSONEq(t, `{"prompts":{"listChanged":true},"tools":{"listChanged":true}}`, initializeWithSession(t, server, late))
+
+	server.AddPrompt(mcp.NewPrompt("farewell"), nil)
+	server.AddTool(mcp.NewTool("reverse"), nil)
+
+	methods := func(session *sessionTestClient) []string {
+		var methods []string
+		for len(session.notificationChannel) > 0 {
+			methods = append(methods, (<-session.notificationChannel).Method)
+		}
+		return methods
+	}
+	assert.Equal(t, []string{mcp.MethodNotificationToolsListChanged}, methods(early))
+	assert.Equal(t, []string{mcp.MethodNotificationPromptsListChanged, mcp.MethodNotificationToolsListChanged}, methods(late))
+}
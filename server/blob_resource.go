@@ -0,0 +1,151 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Arguments of a resources/read request that select a byte range of a blob
+// resource served by a BlobResourceHandlerFunc.
+const (
+	// BlobOffsetArgument is the number of bytes to skip.
+	BlobOffsetArgument = "offset"
+	// BlobLengthArgument is the maximum number of bytes to return.
+	BlobLengthArgument = "length"
+)
+
+// MaxBlobChunkSize is the maximum number of bytes of a blob returned by a
+// single read of a resource served by NewBlobResourceHandler.
+const MaxBlobChunkSize = 4 << 20
+
+// BlobResourceHandlerFunc opens the binary content of a resource. If the
+// returned reader is an io.Closer, it is closed once read; if it is an
+// io.Seeker, range reads seek instead of discarding the skipped bytes.
+type BlobResourceHandlerFunc func(ctx context.Context, request mcp.ReadResourceRequest) (io.Reader, error)
+
+// NewBlobResourceHandler adapts a BlobResourceHandlerFunc to a
+// ResourceHandlerFunc. The content is base64-encoded while it is read, and
+// a read returns at most MaxBlobChunkSize bytes, so that large blobs are
+// never held in memory in full. Clients read them in chunks by passing the
+// BlobOffsetArgument and BlobLengthArgument arguments; the returned
+// contents report the range in their _meta as "offset" and "length", plus
+// "size" when the reader can seek, and "hasMore" when the read stopped at
+// MaxBlobChunkSize before the end of the blob.
+func NewBlobResourceHandler(mimeType string, open BlobResourceHandlerFunc) ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		offset, err := blobRangeArgument(request.Params.Arguments, BlobOffsetArgument)
+		if err != nil {
+			return nil, err
+		}
+		length, err := blobRangeArgument(request.Params.Arguments, BlobLengthArgument)
+		if err != nil {
+			return nil, err
+		}
+
+		reader, err := open(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		if closer, ok := reader.(io.Closer); ok {
+			defer closer.Close()
+		}
+
+		meta := map[string]any{"offset": offset}
+		if seeker, ok := reader.(io.Seeker); ok {
+			size, err := seeker.Seek(0, io.SeekEnd)
+			if err == nil {
+				_, err = seeker.Seek(min(offset, size), io.SeekStart)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to seek resource '%s': %w", request.Params.URI, err)
+			}
+			meta["size"] = size
+		} else if offset > 0 {
+			if _, err := io.CopyN(io.Discard, reader, offset); err != nil && err != io.EOF {
+				return nil, fmt.Errorf("failed to read resource '%s': %w", request.Params.URI, err)
+			}
+		}
+		limit, chunked := int64(MaxBlobChunkSize), true
+		if _, ok := request.Params.Arguments[BlobLengthArgument]; ok && length <= limit {
+			limit, chunked = length, false
+		}
+
+		var blob strings.Builder
+		encoder := base64.NewEncoder(base64.StdEncoding, &blob)
+		n, err := io.Copy(encoder, io.LimitReader(reader, limit))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read resource '%s': %w", request.Params.URI, err)
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, fmt.Errorf("failed to encode resource '%s': %w", request.Params.URI, err)
+		}
+		meta["length"] = n
+		if chunked && n == limit {
+			hasMore := false
+			if size, ok := meta["size"].(int64); ok {
+				hasMore = offset+n < size
+			} else {
+				var next [1]byte
+				m, _ := io.ReadFull(reader, next[:])
+				hasMore = m > 0
+			}
+			if hasMore {
+				meta["hasMore"] = true
+			}
+		}
+
+		return []mcp.ResourceContents{mcp.BlobResourceContents{
+			Meta:     meta,
+			URI:      request.Params.URI,
+			MIMEType: mimeType,
+			Blob:     blob.String(),
+		}}, nil
+	}
+}
+
+// AddBlobResource registers a resource whose binary content is streamed
+// from the reader returned by open. See NewBlobResourceHandler.
+func (s *MCPServer) AddBlobResource(resource mcp.Resource, open BlobResourceHandlerFunc) {
+	s.AddResource(resource, NewBlobResourceHandler(resource.MIMEType, open))
+}
+
+// blobRangeArgument returns a non-negative byte count argument, zero if it
+// is absent.
+func blobRangeArgument(arguments map[string]any, name string) (int64, error) {
+	value, ok := arguments[name]
+	if !ok {
+		return 0, nil
+	}
+	var n int64
+	switch v := value.(type) {
+	case float64:
+		n = int64(v)
+	case int:
+		n = int64(v)
+	case int64:
+		n = v
+	case string:
+		parsed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: argument '%s' must be an integer", mcp.ErrInvalidParams, name)
+		}
+		n = parsed
+	case []string:
+		if len(v) != 1 {
+			return 0, fmt.Errorf("%w: argument '%s' must be an integer", mcp.ErrInvalidParams, name)
+		}
+		return blobRangeArgument(map[string]any{name: v[0]}, name)
+	default:
+		return 0, fmt.Errorf("%w: argument '%s' must be an integer", mcp.ErrInvalidParams, name)
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("%w: argument '%s' must not be negative", mcp.ErrInvalidParams, name)
+	}
+	return n, nil
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// streamOnly hides the Seek method of a reader.
+type streamOnly struct{ io.Reader }
+
+// trackingCloser records whether it was closed.
+type trackingCloser struct {
+	io.Reader
+	closed bool
+}
+
+func (c *trackingCloser) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestNewBlobResourceHandler(t *testing.T) {
+	content := []byte("0123456789")
+
+	tests := []struct {
+		name         string
+		seekable     bool
+		arguments    map[string]any
+		expected     string
+		expectedSize any
+		expectedErr  error
+	}{
+		{name: "whole blob", seekable: true, expected: "0123456789", expectedSize: int64(10)},
+		{name: "offset", seekable: true, arguments: map[string]any{"offset": float64(4)}, expected: "456789", expectedSize: int64(10)},
+		{name: "range", seekable: true, arguments: map[string]any{"offset": float64(2), "length": float64(3)}, expected: "234", expectedSize: int64(10)},
+		{name: "offset past the end", seekable: true, arguments: map[string]any{"offset": float64(20)}, expected: "", expectedSize: int64(10)},
+		{name: "template arguments", seekable: true, arguments: map[string]any{"offset": []string{"8"}}, expected: "89", expectedSize: int64(10)},
+		{name: "range without seeking", arguments: map[string]any{"offset": "5", "length": float64(2)}, expected: "56"},
+		{name: "negative length", arguments: map[string]any{"length": float64(-1)}, expectedErr: mcp.ErrInvalidParams},
+		{name: "invalid offset", arguments: map[string]any{"offset": "start"}, expectedErr: mcp.ErrInvalidParams},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opened *trackingCloser
+			handler := NewBlobResourceHandler("application/octet-stream", func(ctx context.Context, request mcp.ReadResourceRequest) (io.Reader, error) {
+				if tt.seekable {
+					return bytes.NewReader(content), nil
+				}
+				opened = &trackingCloser{Reader: streamOnly{bytes.NewReader(content)}}
+				return opened, nil
+			})
+
+			request := mcp.ReadResourceRequest{}
+			request.Params.URI = "blob://data"
+			request.Params.Arguments = tt.arguments
+			contents, err := handler(context.Background(), request)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, contents, 1)
+
+			blob, ok := contents[0].(mcp.BlobResourceContents)
+			require.True(t, ok)
+			assert.Equal(t, "blob://data", blob.URI)
+			assert.Equal(t, "application/octet-stream", blob.MIMEType)
+			decoded, err := base64.StdEncoding.DecodeString(blob.Blob)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(decoded))
+			assert.Equal(t, int64(len(tt.expected)), blob.Meta["length"])
+			assert.Equal(t, tt.expectedSize, blob.Meta["size"])
+			if opened != nil {
+				assert.True(t, opened.closed, "the reader is closed")
+			}
+		})
+	}
+}
+
+func TestNewBlobResourceHandler_MaxChunkSize(t *testing.T) {
+	content := bytes.Repeat([]byte("x"), MaxBlobChunkSize+10)
+
+	tests := []struct {
+		name           string
+		seekable       bool
+		arguments      map[string]any
+		expectedLength int
+		expectedMore   any
+	}{
+		{name: "whole blob", seekable: true, expectedLength: MaxBlobChunkSize, expectedMore: true},
+		{name: "whole blob without seeking", expectedLength: MaxBlobChunkSize, expectedMore: true},
+		{name: "last chunk", seekable: true, arguments: map[string]any{"offset": float64(MaxBlobChunkSize)}, expectedLength: 10},
+		{name: "last chunk without seeking", arguments: map[string]any{"offset": float64(10)}, expectedLength: MaxBlobChunkSize},
+		{name: "length beyond the limit", arguments: map[string]any{"length": float64(MaxBlobChunkSize + 5)}, expectedLength: MaxBlobChunkSize, expectedMore: true},
+		{name: "length at the limit", arguments: map[string]any{"length": float64(MaxBlobChunkSize)}, expectedLength: MaxBlobChunkSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewBlobResourceHandler("application/octet-stream", func(ctx context.Context, request mcp.ReadResourceRequest) (io.Reader, error) {
+				if tt.seekable {
+					return bytes.NewReader(content), nil
+				}
+				return streamOnly{bytes.NewReader(content)}, nil
+			})
+			request := mcp.ReadResourceRequest{}
+			request.Params.URI = "blob://data"
+			request.Params.Arguments = tt.arguments
+			contents, err := handler(context.Background(), request)
+			require.NoError(t, err)
+			blob := contents[0].(mcp.BlobResourceContents)
+			assert.Equal(t, base64.StdEncoding.EncodedLen(tt.expectedLength), len(blob.Blob))
+			assert.Equal(t, int64(tt.expectedLength), blob.Meta["length"])
+			assert.Equal(t, tt.expectedMore, blob.Meta["hasMore"])
+		})
+	}
+}
+
+func TestMCPServer_AddBlobResource(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+	server.AddBlobResource(mcp.NewResource("blob://image", "image", mcp.WithMIMEType("image/png")),
+		func(ctx context.Context, request mcp.ReadResourceRequest) (io.Reader, error) {
+			return bytes.NewReader([]byte{0x89, 'P', 'N', 'G'}), nil
+		})
+
+	response := server.HandleMessage(context.Background(), json.RawMessage(
+		`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"blob://image","arguments":{"length":1}}}`))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "expected success response, got %#v", response)
+	result := resp.Result.(mcp.ReadResourceResult)
+	require.Len(t, result.Contents, 1)
+	blob := result.Contents[0].(mcp.BlobResourceContents)
+	assert.Equal(t, "image/png", blob.MIMEType)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte{0x89}), blob.Blob)
+}
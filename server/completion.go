@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// WithCompletions enables the completions capability of the server. It is
+// enabled implicitly when a prompt with an argument completed by
+// mcp.WithArgumentCompletion is registered.
+func WithCompletions() ServerOption {
+	return func(s *MCPServer) {
+		s.capabilities.completions = mcp.ToBoolPtr(true)
+	}
+}
+
+// implicitlyRegisterCompletionCapabilities enables the completions
+// capability if any argument of prompts is completed.
+func (s *MCPServer) implicitlyRegisterCompletionCapabilities(prompts []ServerPrompt) {
+	for _, prompt := range prompts {
+		for _, arg := range prompt.Prompt.Arguments {
+			if arg.Completion != nil {
+				s.implicitlyRegisterCapabilities(
+					func() bool { return s.capabilities.completions != nil },
+					func() { s.capabilities.completions = mcp.ToBoolPtr(true) },
+				)
+				return
+			}
+		}
+	}
+}
+
+// completionRef is the reference of a completion/complete request.
+type completionRef struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	URI  string `json:"uri"`
+}
+
+func (s *MCPServer) handleComplete(
+	ctx context.Context,
+	id any,
+	request mcp.CompleteRequest,
+) (*mcp.CompleteResult, *requestError) {
+	var ref completionRef
+	raw, err := json.Marshal(request.Params.Ref)
+	if err == nil {
+		err = json.Unmarshal(raw, &ref)
+	}
+	if err != nil {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INVALID_PARAMS,
+			err:  fmt.Errorf("invalid completion reference: %w", err),
+		}
+	}
+
+	result := &mcp.CompleteResult{Completion: mcp.Completion{Values: []string{}}}
+	switch ref.Type {
+	case "ref/prompt":
+	case "ref/resource":
+		// Resource template arguments have no completions
+		return result, nil
+	default:
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INVALID_PARAMS,
+			err:  fmt.Errorf("unknown completion reference type '%s'", ref.Type),
+		}
+	}
+
+	prompt, ok := s.overlayPrompts(ctx)[ref.Name]
+	if !ok {
+		prompt, ok = s.prompts.get(ref.Name)
+	}
+	if !ok || !s.promptVisible(ctx, prompt.Prompt) {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INVALID_PARAMS,
+			err:  fmt.Errorf("prompt '%s' not found: %w", ref.Name, ErrPromptNotFound),
+			data: mcp.NotFoundErrorData{Type: "prompt", Name: ref.Name},
+		}
+	}
+
+	var complete mcp.CompletionFunc
+	for _, arg := range prompt.Prompt.Arguments {
+		if arg.Name == request.Params.Argument.Name {
+			complete = arg.Completion
+			break
+		}
+	}
+	if complete == nil {
+		return result, nil
+	}
+
+	resolved := map[string]string{}
+	if request.Params.Context != nil {
+		for name, value := range request.Params.Context.Arguments {
+			if name != request.Params.Argument.Name {
+				resolved[name] = value
+			}
+		}
+	}
+	completion, err := complete(ctx, request.Params.Argument.Value, resolved)
+	if err != nil {
+		return nil, &requestError{
+			id:   id,
+			code: s.handlerErrorCode(err),
+			err:  err,
+		}
+	}
+	if completion != nil {
+		result.Completion = *completion
+	}
+	if result.Completion.Values == nil {
+		result.Completion.Values = []string{}
+	}
+	if n := len(result.Completion.Values); n > mcp.MaxCompletionValues {
+		result.Completion.Values = result.Completion.Values[:mcp.MaxCompletionValues]
+		result.Completion.Total = max(result.Completion.Total, n)
+		result.Completion.HasMore = true
+	}
+	return result, nil
+}
@@ -0,0 +1,199 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestMCPServer_Completion(t *testing.T) {
+	branches := map[string][]string{
+		"mcp-go": {"main", "develop", "docs"},
+	}
+	prompt := mcp.NewPrompt("checkout",
+		mcp.WithArgument("repository",
+			mcp.WithArgumentCompletion(func(ctx context.Context, value string, resolved map[string]string) (*mcp.Completion, error) {
+				return &mcp.Completion{Values: []string{"mcp-go"}}, nil
+			}),
+		),
+		mcp.WithArgument("branch",
+			mcp.WithArgumentCompletion(func(ctx context.Context, value string, resolved map[string]string) (*mcp.Completion, error) {
+				var values []string
+				for _, branch := range branches[resolved["repository"]] {
+					if strings.HasPrefix(branch, value) {
+						values = append(values, branch)
+					}
+				}
+				return &mcp.Completion{Values: values}, nil
+			}),
+		),
+		mcp.WithArgument("count",
+			mcp.WithArgumentCompletion(func(ctx context.Context, value string, resolved map[string]string) (*mcp.Completion, error) {
+				values := make([]string, 150)
+				for i := range values {
+					values[i] = fmt.Sprint(i)
+				}
+				return &mcp.Completion{Values: values}, nil
+			}),
+		),
+		mcp.WithArgument("broken",
+			mcp.WithArgumentCompletion(func(ctx context.Context, value string, resolved map[string]string) (*mcp.Completion, error) {
+				return nil, errors.New("completion failed")
+			}),
+		),
+		mcp.WithArgument("plain"),
+	)
+
+	tests := []struct {
+		name       string
+		message    string
+		want       mcp.Completion
+		wantCode   int
+		wantErrMsg string
+	}{
+		{
+			name:    "argument",
+			message: `{"ref":{"type":"ref/prompt","name":"checkout"},"argument":{"name":"repository","value":"m"}}`,
+			want:    mcp.Completion{Values: []string{"mcp-go"}},
+		},
+		{
+			name:    "dependent argument",
+			message: `{"ref":{"type":"ref/prompt","name":"checkout"},"argument":{"name":"branch","value":"d"},"context":{"arguments":{"repository":"mcp-go"}}}`,
+			want:    mcp.Completion{Values: []string{"develop", "docs"}},
+		},
+		{
+			name:    "dependent argument without context",
+			message: `{"ref":{"type":"ref/prompt","name":"checkout"},"argument":{"name":"branch","value":"d"}}`,
+			want:    mcp.Completion{Values: []string{}},
+		},
+		{
+			name:    "too many values",
+			message: `{"ref":{"type":"ref/prompt","name":"checkout"},"argument":{"name":"count","value":""}}`,
+			want: func() mcp.Completion {
+				values := make([]string, mcp.MaxCompletionValues)
+				for i := range values {
+					values[i] = fmt.Sprint(i)
+				}
+				return mcp.Completion{Values: values, Total: 150, HasMore: true}
+			}(),
+		},
+		{
+			name:    "argument without completion",
+			message: `{"ref":{"type":"ref/prompt","name":"checkout"},"argument":{"name":"plain","value":"x"}}`,
+			want:    mcp.Completion{Values: []string{}},
+		},
+		{
+			name:    "resource reference",
+			message: `{"ref":{"type":"ref/resource","uri":"file:///{path}"},"argument":{"name":"path","value":"x"}}`,
+			want:    mcp.Completion{Values: []string{}},
+		},
+		{
+			name:       "completion error",
+			message:    `{"ref":{"type":"ref/prompt","name":"checkout"},"argument":{"name":"broken","value":""}}`,
+			wantCode:   mcp.INTERNAL_ERROR,
+			wantErrMsg: "completion failed",
+		},
+		{
+			name:       "unknown prompt",
+			message:    `{"ref":{"type":"ref/prompt","name":"missing"},"argument":{"name":"branch","value":""}}`,
+			wantCode:   mcp.INVALID_PARAMS,
+			wantErrMsg: "prompt 'missing' not found",
+		},
+		{
+			name:       "unknown reference type",
+			message:    `{"ref":{"type":"ref/tool","name":"checkout"},"argument":{"name":"branch","value":""}}`,
+			wantCode:   mcp.INVALID_PARAMS,
+			wantErrMsg: "unknown completion reference type 'ref/tool'",
+		},
+	}
+
+	server := NewMCPServer("test-server", "1.0.0")
+	server.AddPrompt(prompt, nil)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := server.HandleMessage(context.Background(),
+				[]byte(`{"jsonrpc":"2.0","id":1,"method":"completion/complete","params":`+tt.message+`}`))
+			if tt.wantCode != 0 {
+				errorResponse, ok := response.(mcp.JSONRPCError)
+				require.True(t, ok, "response %#v", response)
+				assert.Equal(t, tt.wantCode, errorResponse.Error.Code)
+				assert.Contains(t, errorResponse.Error.Message, tt.wantErrMsg)
+				return
+			}
+			resp, ok := response.(mcp.JSONRPCResponse)
+			require.True(t, ok, "response %#v", response)
+			result, ok := resp.Result.(mcp.CompleteResult)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, result.Completion)
+		})
+	}
+}
+
+func TestMCPServer_CompletionCapability(t *testing.T) {
+	completion := func(ctx context.Context, value string, resolved map[string]string) (*mcp.Completion, error) {
+		return &mcp.Completion{}, nil
+	}
+
+	tests := []struct {
+		name    string
+		options []ServerOption
+		prompt  mcp.Prompt
+		want    bool
+	}{
+		{
+			name:   "completed argument",
+			prompt: mcp.NewPrompt("p", mcp.WithArgument("a", mcp.WithArgumentCompletion(completion))),
+			want:   true,
+		},
+		{
+			name:   "no completed argument",
+			prompt: mcp.NewPrompt("p", mcp.WithArgument("a")),
+		},
+		{
+			name:    "explicit",
+			options: []ServerOption{WithCompletions()},
+			prompt:  mcp.NewPrompt("p"),
+			want:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMCPServer("test-server", "1.0.0", tt.options...)
+			server.AddPrompt(tt.prompt, nil)
+
+			response := server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"initialize"}`))
+			resp, ok := response.(mcp.JSONRPCResponse)
+			require.True(t, ok, "response %#v", response)
+			result, ok := resp.Result.(mcp.InitializeResult)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, result.Capabilities.Completions != nil)
+
+			// Completion functions are not listed to clients
+			raw, err := json.Marshal(tt.prompt)
+			require.NoError(t, err)
+			assert.NotContains(t, string(raw), "completion")
+		})
+	}
+}
+
+func TestMCPServer_CompletionNotNegotiated(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithPromptCapabilities(false))
+	server.AddPrompt(mcp.NewPrompt("p", mcp.WithArgument("a")), nil)
+
+	response := server.HandleMessage(context.Background(),
+		[]byte(`{"jsonrpc":"2.0","id":1,"method":"completion/complete","params":{"ref":{"type":"ref/prompt","name":"p"},"argument":{"name":"a","value":""}}}`))
+	errorResponse, ok := response.(mcp.JSONRPCError)
+	require.True(t, ok, "response %#v", response)
+	assert.Equal(t, mcp.METHOD_NOT_FOUND, errorResponse.Error.Code)
+	assert.Equal(t, mcp.MethodNotFoundErrorData{Method: "completion/complete", Capability: "completions"}, errorResponse.Error.Data)
+}
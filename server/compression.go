@@ -0,0 +1,91 @@
+package server
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/util"
+)
+
+// WithCompression makes the server compress JSON responses of at least
+// minSize bytes, such as large tool results and resource blobs, with the
+// coding of compressors the Accept-Encoding header of the request prefers.
+// Without compressors, gzip is used. Request bodies in these codings are
+// decompressed; gzip request bodies are accepted even without this option.
+// Responses streamed as SSE are not compressed.
+func WithCompression(minSize int, compressors ...util.Compressor) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		if len(compressors) == 0 {
+			compressors = []util.Compressor{util.GzipCompressor()}
+		}
+		s.compressors = compressors
+		s.compressMinSize = minSize
+	}
+}
+
+// requestCompressors returns the compressors request bodies can be encoded
+// with.
+func (s *StreamableHTTPServer) requestCompressors() []util.Compressor {
+	if s.compressors == nil {
+		return []util.Compressor{util.GzipCompressor()}
+	}
+	return s.compressors
+}
+
+// decompressRequestBody returns the body of r decoded from its
+// Content-Encoding. It answers the request and returns false if the coding
+// is not supported or the body is not valid in it.
+func (s *StreamableHTTPServer) decompressRequestBody(w http.ResponseWriter, r *http.Request) (io.ReadCloser, bool) {
+	encoding := r.Header.Get("Content-Encoding")
+	if encoding == "" || encoding == "identity" {
+		return r.Body, true
+	}
+	compressors := s.requestCompressors()
+	compressor := util.FindCompressor(encoding, compressors)
+	if compressor == nil {
+		w.Header().Set("Accept-Encoding", util.AcceptEncoding(compressors))
+		http.Error(w, "Unsupported content encoding: "+encoding, http.StatusUnsupportedMediaType)
+		return nil, false
+	}
+	body, err := compressor.NewReader(r.Body)
+	if err != nil {
+		s.writeJSONRPCError(w, nil, mcp.PARSE_ERROR, "request body is not valid "+compressor.Encoding())
+		return nil, false
+	}
+	return body, true
+}
+
+// writeJSONResponse writes response as the JSON body of an HTTP response
+// with status, compressed if it is large enough and the client accepts one
+// of the compressors. The Content-Type and other headers must be set.
+func (s *StreamableHTTPServer) writeJSONResponse(w http.ResponseWriter, r *http.Request, status int, response mcp.JSONRPCMessage) error {
+	buf := util.GetBuffer()
+	defer util.PutBuffer(buf)
+	if err := s.server.encodeJSON(buf, response); err != nil {
+		return err
+	}
+
+	var compressor util.Compressor
+	if s.compressors != nil {
+		w.Header().Add("Vary", "Accept-Encoding")
+		compressor = util.NegotiateCompressor(r.Header.Get("Accept-Encoding"), s.compressors)
+	}
+	if compressor == nil || buf.Len() < s.compressMinSize {
+		w.WriteHeader(status)
+		_, err := w.Write(buf.Bytes())
+		return err
+	}
+
+	w.Header().Set("Content-Encoding", compressor.Encoding())
+	w.WriteHeader(status)
+	cw, err := compressor.NewWriter(w)
+	if err != nil {
+		return err
+	}
+	if _, err := cw.Write(buf.Bytes()); err != nil {
+		_ = cw.Close()
+		return err
+	}
+	return cw.Close()
+}
@@ -0,0 +1,151 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/util"
+)
+
+func gzipBytes(t *testing.T, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestStreamableHTTPServer_Compression(t *testing.T) {
+	mcpServer := NewMCPServer("test", "1.0.0", WithLimits(Limits{MaxRequestSize: 1024}))
+	mcpServer.AddTool(mcp.NewTool("big"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(strings.Repeat("large result ", 1000)), nil
+	})
+	callBig := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"big"}}`
+	ping := `{"jsonrpc":"2.0","id":1,"method":"ping"}`
+
+	tests := []struct {
+		name            string
+		options         []StreamableHTTPOption
+		body            []byte
+		contentEncoding string
+		acceptEncoding  string
+		wantStatus      int
+		wantEncoding    string
+	}{
+		{
+			name:           "large response",
+			options:        []StreamableHTTPOption{WithCompression(1024)},
+			body:           []byte(callBig),
+			acceptEncoding: "br, gzip;q=0.8",
+			wantStatus:     http.StatusOK,
+			wantEncoding:   "gzip",
+		},
+		{
+			name:           "response under the threshold",
+			options:        []StreamableHTTPOption{WithCompression(1024)},
+			body:           []byte(ping),
+			acceptEncoding: "gzip",
+			wantStatus:     http.StatusOK,
+		},
+		{
+			name:       "client not accepting compression",
+			options:    []StreamableHTTPOption{WithCompression(0)},
+			body:       []byte(callBig),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:           "compression not enabled",
+			body:           []byte(callBig),
+			acceptEncoding: "gzip",
+			wantStatus:     http.StatusOK,
+		},
+		{
+			name:            "gzip request",
+			body:            gzipBytes(t, callBig),
+			contentEncoding: "gzip",
+			wantStatus:      http.StatusOK,
+		},
+		{
+			name:            "unsupported request encoding",
+			body:            []byte(callBig),
+			contentEncoding: "br",
+			wantStatus:      http.StatusUnsupportedMediaType,
+		},
+		{
+			name:            "invalid gzip request",
+			body:            []byte(callBig),
+			contentEncoding: "gzip",
+			wantStatus:      http.StatusBadRequest,
+		},
+		{
+			name:            "request over the size limit once decompressed",
+			body:            gzipBytes(t, `{"jsonrpc":"2.0","id":1,"method":"ping","params":{"padding":"`+strings.Repeat("a", 2048)+`"}}`),
+			contentEncoding: "gzip",
+			wantStatus:      http.StatusRequestEntityTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testServer := NewTestStreamableHTTPServer(mcpServer, append([]StreamableHTTPOption{WithStateLess(true)}, tt.options...)...)
+			defer testServer.Close()
+
+			req, err := http.NewRequest(http.MethodPost, testServer.URL, bytes.NewReader(tt.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			if tt.contentEncoding != "" {
+				req.Header.Set("Content-Encoding", tt.contentEncoding)
+			}
+			// Setting Accept-Encoding keeps the transport from decompressing.
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			assert.Equal(t, tt.wantEncoding, resp.Header.Get("Content-Encoding"))
+			body := resp.Body
+			if tt.wantEncoding != "" {
+				body, err = gzip.NewReader(resp.Body)
+				require.NoError(t, err)
+			}
+			raw, err := io.ReadAll(body)
+			require.NoError(t, err)
+			var response struct {
+				Result json.RawMessage `json:"result"`
+			}
+			require.NoError(t, json.Unmarshal(raw, &response))
+			assert.NotEmpty(t, response.Result)
+		})
+	}
+}
+
+func TestStreamableHTTPServer_CompressionAcceptEncodingOnRejection(t *testing.T) {
+	testServer := NewTestStreamableHTTPServer(NewMCPServer("test", "1.0.0"), WithCompression(0, util.GzipCompressor()))
+	defer testServer.Close()
+
+	req, err := http.NewRequest(http.MethodPost, testServer.URL, strings.NewReader("{}"))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "zstd")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+	assert.Equal(t, "gzip", resp.Header.Get("Accept-Encoding"))
+}
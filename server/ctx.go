@@ -1,8 +1,32 @@
 package server
 
+import (
+	"context"
+	"net/http"
+)
+
 type contextKey int
 
 const (
 	// This const is used as key for context value lookup
 	requestHeader contextKey = iota
+	httpRequest
 )
+
+// HTTPRequestFromContext returns the HTTP request that carried the message
+// being handled, when it arrived over the SSE or streamable HTTP transport.
+// Handlers can use its headers, RemoteAddr and TLS state to tell clients
+// apart or to audit calls. The body has already been consumed by the
+// transport and must not be read.
+func HTTPRequestFromContext(ctx context.Context) (*http.Request, bool) {
+	r, ok := ctx.Value(httpRequest).(*http.Request)
+	return r, ok && r != nil
+}
+
+// WithHTTPRequest returns a copy of ctx carrying r, along with its headers,
+// as the HTTPRequestFromContext of handlers. The HTTP transports call it for
+// every message; it is useful for tests and custom transports.
+func WithHTTPRequest(ctx context.Context, r *http.Request) context.Context {
+	ctx = context.WithValue(ctx, requestHeader, r.Header)
+	return context.WithValue(ctx, httpRequest, r)
+}
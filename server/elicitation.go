@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -22,6 +23,9 @@ func (s *MCPServer) RequestElicitation(ctx context.Context, request mcp.Elicitat
 	if session == nil {
 		return nil, ErrNoActiveSession
 	}
+	if !s.FeatureSupported(ctx, mcp.FeatureElicitation) {
+		return nil, fmt.Errorf("%w: protocol version %s predates elicitation", ErrElicitationNotSupported, s.NegotiatedProtocolVersion(ctx))
+	}
 
 	// Check if the session supports elicitation requests
 	if elicitationSession, ok := session.(SessionWithElicitation); ok {
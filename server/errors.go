@@ -11,14 +11,41 @@ var (
 	ErrResourceNotFound = errors.New("resource not found")
 	ErrPromptNotFound   = errors.New("prompt not found")
 	ErrToolNotFound     = errors.New("tool not found")
+	ErrToolUnavailable  = errors.New("tool unavailable")
+	ErrRequestTimeout   = errors.New("request timed out")
+
+	// ErrIdempotencyKeyReused is returned when a tool call reuses the
+	// idempotency key of a call with different arguments.
+	ErrIdempotencyKeyReused = errors.New("idempotency key reused with different arguments")
+
+	// ErrToolVersionNotFound is returned when a call selects a version a
+	// versioned tool does not have.
+	ErrToolVersionNotFound = errors.New("tool version not found")
+
+	// ErrSchedulerQueueFull is returned when a request is rejected because
+	// the queue of the WithScheduler scheduler is full.
+	ErrSchedulerQueueFull = errors.New("too many requests queued")
+
+	// ErrTenantRequired is returned by TenantFromHeader and TenantFromAuth
+	// for requests that carry no tenant.
+	ErrTenantRequired = errors.New("tenant required")
+
+	// ErrTenantMismatch is returned when a request of a session resolves to
+	// another tenant than the one the session is bound to.
+	ErrTenantMismatch = errors.New("request tenant does not match the session tenant")
+
+	// ErrResourceTemplateConflict is matched by ResourceTemplateConflict.
+	ErrResourceTemplateConflict = errors.New("conflicting resource templates")
 
 	// Session-related errors
 	ErrSessionNotFound                        = errors.New("session not found")
 	ErrSessionExists                          = errors.New("session already exists")
 	ErrSessionNotInitialized                  = errors.New("session not properly initialized")
+	ErrSessionClosed                          = errors.New("session closed")
 	ErrSessionDoesNotSupportTools             = errors.New("session does not support per-session tools")
 	ErrSessionDoesNotSupportResources         = errors.New("session does not support per-session resources")
 	ErrSessionDoesNotSupportResourceTemplates = errors.New("session does not support resource templates")
+	ErrSessionDoesNotSupportPrompts           = errors.New("session does not support per-session prompts")
 	ErrSessionDoesNotSupportLogging           = errors.New("session does not support setting logging level")
 
 	// Notification-related errors
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/util"
+)
+
+// WithServerLogger sets the logger receiving the events of the server:
+// registered and unregistered sessions at slog.LevelInfo, handled requests
+// at slog.LevelDebug, or higher if they failed, and dropped notifications
+// at slog.LevelWarn. The StreamableHTTP and SSE servers serving it log
+// their transport errors to it as well unless given a logger of their own.
+// Use util.NewSlogLogger to log to a *slog.Logger. Without it events are
+// discarded.
+func WithServerLogger(logger util.Logger) ServerOption {
+	return func(s *MCPServer) {
+		s.logger = logger
+	}
+}
+
+// logEvent logs an event of the server, if it has a logger.
+func (s *MCPServer) logEvent(ctx context.Context, level slog.Level, msg string, args ...any) {
+	util.Log(ctx, s.logger, level, msg, args...)
+}
+
+// transportLogger returns the logger of the server for transports that are
+// not given one, falling back to util.DefaultLogger().
+func (s *MCPServer) transportLogger() util.Logger {
+	if s != nil && s.logger != nil {
+		return s.logger
+	}
+	return util.DefaultLogger()
+}
+
+// logRequest returns a function logging the handling of a request once it
+// is called with the response.
+func (s *MCPServer) logRequest(ctx context.Context, method mcp.MCPMethod, id any) func(response mcp.JSONRPCMessage) {
+	if s.logger == nil {
+		return func(mcp.JSONRPCMessage) {}
+	}
+	start := time.Now()
+	return func(response mcp.JSONRPCMessage) {
+		args := []any{
+			"method", string(method),
+			"request_id", fmt.Sprint(id),
+			"duration", time.Since(start),
+		}
+		if session := ClientSessionFromContext(ctx); session != nil {
+			args = append(args, "session_id", session.SessionID())
+		}
+
+		var details *mcp.JSONRPCErrorDetails
+		switch response := response.(type) {
+		case mcp.JSONRPCError:
+			details = &response.Error
+		case *mcp.JSONRPCError:
+			details = &response.Error
+		}
+		if details == nil {
+			s.logEvent(ctx, slog.LevelDebug, "request handled", args...)
+			return
+		}
+		// Internal errors are the server's fault, others the client's.
+		level := slog.LevelInfo
+		if details.Code == mcp.INTERNAL_ERROR {
+			level = slog.LevelError
+		}
+		args = append(args, "error_code", details.Code, "error", details.Message)
+		s.logEvent(ctx, level, "request failed", args...)
+	}
+}
+
+// logTransportError logs an error of the named transport while doing op.
+func logTransportError(ctx context.Context, logger util.Logger, transport, op string, err error, args ...any) {
+	args = append([]any{"transport", transport, "op", op, "error", err}, args...)
+	util.Log(ctx, logger, slog.LevelError, "transport error", args...)
+}
+
+func (s *StreamableHTTPServer) logTransportError(ctx context.Context, op string, err error, args ...any) {
+	logTransportError(ctx, s.logger, "streamable_http", op, err, args...)
+}
+
+func (s *SSEServer) logTransportError(ctx context.Context, op string, err error, args ...any) {
+	logTransportError(ctx, s.logger, "sse", op, err, args...)
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/util"
+)
+
+// recordHandler is a slog.Handler keeping the records it handles.
+type recordHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *recordHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordHandler) Handle(_ context.Context, record slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, record)
+	return nil
+}
+
+func (h *recordHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *recordHandler) WithGroup(string) slog.Handler      { return h }
+
+// event is a handled record with its attributes as strings.
+type event struct {
+	level slog.Level
+	msg   string
+	attrs map[string]string
+}
+
+// events returns the records with message msg.
+func (h *recordHandler) events(msg string) []event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var events []event
+	for _, record := range h.records {
+		if record.Message != msg {
+			continue
+		}
+		e := event{level: record.Level, msg: record.Message, attrs: map[string]string{}}
+		record.Attrs(func(attr slog.Attr) bool {
+			e.attrs[attr.Key] = attr.Value.String()
+			return true
+		})
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestMCPServer_WithServerLogger(t *testing.T) {
+	handler := &recordHandler{}
+	server := NewMCPServer("test-server", "1.0.0",
+		WithServerLogger(util.NewSlogLogger(slog.New(handler))),
+	)
+
+	ch := make(chan mcp.JSONRPCNotification, 1)
+	session := &sessionTestClient{sessionID: "s1", notificationChannel: ch, initialized: true}
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+	ctx := server.WithContext(context.Background(), session)
+
+	t.Run("session registered", func(t *testing.T) {
+		events := handler.events("session registered")
+		require.Len(t, events, 1)
+		assert.Equal(t, slog.LevelInfo, events[0].level)
+		assert.Equal(t, "s1", events[0].attrs["session_id"])
+	})
+
+	t.Run("request handled", func(t *testing.T) {
+		server.HandleMessage(ctx, json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+		events := handler.events("request handled")
+		require.Len(t, events, 1)
+		assert.Equal(t, slog.LevelDebug, events[0].level)
+		assert.Equal(t, "ping", events[0].attrs["method"])
+		assert.Equal(t, "1", events[0].attrs["request_id"])
+		assert.Equal(t, "s1", events[0].attrs["session_id"])
+		assert.NotEmpty(t, events[0].attrs["duration"])
+	})
+
+	t.Run("request failed", func(t *testing.T) {
+		server.HandleMessage(ctx, json.RawMessage(`{"jsonrpc":"2.0","id":2,"method":"unknown/method"}`))
+		events := handler.events("request failed")
+		require.Len(t, events, 1)
+		assert.Equal(t, slog.LevelInfo, events[0].level)
+		assert.Equal(t, "unknown/method", events[0].attrs["method"])
+		assert.Equal(t, "-32601", events[0].attrs["error_code"])
+	})
+
+	t.Run("notification dropped", func(t *testing.T) {
+		require.NoError(t, server.SendNotificationToSpecificClient("s1", "first", nil))
+		require.ErrorIs(t, server.SendNotificationToSpecificClient("s1", "second", nil), ErrNotificationChannelBlocked)
+		events := handler.events("notification dropped")
+		require.Len(t, events, 1)
+		assert.Equal(t, slog.LevelWarn, events[0].level)
+		assert.Equal(t, "s1", events[0].attrs["session_id"])
+		assert.Equal(t, "second", events[0].attrs["method"])
+		assert.Equal(t, "drop", events[0].attrs["policy"])
+	})
+
+	t.Run("session unregistered", func(t *testing.T) {
+		server.UnregisterSession(context.Background(), "s1")
+		events := handler.events("session unregistered")
+		require.Len(t, events, 1)
+		assert.Equal(t, "s1", events[0].attrs["session_id"])
+	})
+}
+
+func TestMCPServer_WithoutServerLogger(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+	assert.Nil(t, server.logger)
+	// Without a logger the events are discarded.
+	server.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+
+	assert.Equal(t, util.DefaultLogger(), NewStreamableHTTPServer(server).logger)
+	assert.Equal(t, util.DefaultLogger(), NewSSEServer(server).logger)
+}
+
+func TestTransportLoggers(t *testing.T) {
+	serverLogger := util.NewSlogLogger(slog.New(&recordHandler{}))
+	transportLogger := util.NewSlogLogger(slog.New(&recordHandler{}))
+	server := NewMCPServer("test-server", "1.0.0", WithServerLogger(serverLogger))
+
+	assert.Same(t, serverLogger, NewStreamableHTTPServer(server).logger)
+	assert.Same(t, serverLogger, NewSSEServer(server).logger)
+	assert.Same(t, transportLogger, NewStreamableHTTPServer(server, WithLogger(transportLogger)).logger)
+	assert.Same(t, transportLogger, NewSSEServer(server, WithSSELogger(transportLogger)).logger)
+}
+
+func TestLogTransportError(t *testing.T) {
+	handler := &recordHandler{}
+	s := NewStreamableHTTPServer(NewMCPServer("test-server", "1.0.0"), WithLogger(util.NewSlogLogger(slog.New(handler))))
+
+	s.logTransportError(context.Background(), "write SSE event", assert.AnError, "session_id", "s1")
+
+	events := handler.events("transport error")
+	require.Len(t, events, 1)
+	assert.Equal(t, slog.LevelError, events[0].level)
+	assert.Equal(t, map[string]string{
+		"transport":  "streamable_http",
+		"op":         "write SSE event",
+		"error":      assert.AnError.Error(),
+		"session_id": "s1",
+	}, events[0].attrs)
+}
@@ -0,0 +1,382 @@
+// Package exectool exposes local commands as MCP tools.
+//
+// A Command lists the program and its arguments as text/template templates
+// executed with the tool call arguments. Each argument is expanded on its
+// own and the program is run directly, without a shell, so arguments cannot
+// inject other commands:
+//
+//	tool, err := exectool.NewTool("grep", exectool.Command{
+//	    Description: "Search files for a pattern",
+//	    Args:        []string{"grep", "-rn", "--", "{{.pattern}}", "."},
+//	    Params: []exectool.Param{
+//	        {Name: "pattern", Required: true},
+//	    },
+//	}, exectool.WithDir("/srv/docs"), exectool.WithTimeout(5*time.Second))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	s.AddTools(tool)
+//
+// Commands run with a minimal environment holding only PATH unless more
+// variables are allowed with WithEnvAllowlist, and their output is capped
+// by WithMaxOutput. End templated arguments with "--", as above, when a
+// program would otherwise read arguments starting with "-" as options.
+package exectool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mark3labs/mcp-go/server/internal/argtemplate"
+)
+
+// Param is an argument of a command tool.
+type Param struct {
+	Name        string
+	Description string
+	// Type is the JSON schema type of the argument, string if empty. It is
+	// ignored if Schema is set.
+	Type string
+	// Schema optionally gives the full JSON schema of the argument.
+	Schema   map[string]any
+	Required bool
+}
+
+// Command describes a command run by a tool.
+type Command struct {
+	Description string
+	// Args are the templates of the program and its arguments.
+	Args []string
+	// Stdin is an optional template of the standard input of the command.
+	Stdin string
+	// Env are optional templates of additional environment variables, in
+	// the KEY=value form.
+	Env    []string
+	Params []Param
+	// Annotations optionally describe the behavior of the tool.
+	Annotations *mcp.ToolAnnotation
+}
+
+// Option configures the tools built by NewTool and the handlers built by
+// NewHandler.
+type Option func(*options)
+
+type options struct {
+	timeout    time.Duration
+	maxOutput  int
+	dir        string
+	dirParam   string
+	dirRoots   []string
+	envNames   []string
+	env        []string
+	inheritEnv bool
+}
+
+// WithTimeout bounds the run time of the command. The command is killed
+// when the timeout expires, along with the processes it started on
+// platforms with process groups, and the call fails with a tool error. There is
+// no timeout by default, besides the cancellation of the request.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithMaxOutput bounds the bytes of standard output and standard error
+// kept from the command, 1 MiB each by default. Output beyond the limit is
+// discarded and the result notes the truncation.
+func WithMaxOutput(size int) Option {
+	return func(o *options) {
+		o.maxOutput = size
+	}
+}
+
+// WithDir sets the working directory of the command.
+func WithDir(dir string) Option {
+	return func(o *options) {
+		o.dir = dir
+	}
+}
+
+// WithDirParam lets calls choose the working directory of the command with
+// the argument param, which must name a directory inside one of roots,
+// after resolving symbolic links. The argument is optional; without it the
+// command runs in the directory set by WithDir. Relative paths are resolved
+// against the first root.
+func WithDirParam(param string, roots ...string) Option {
+	return func(o *options) {
+		o.dirParam = param
+		o.dirRoots = append(o.dirRoots, roots...)
+	}
+}
+
+// WithEnvAllowlist passes the named variables of the server's environment
+// to the command, in addition to PATH.
+func WithEnvAllowlist(names ...string) Option {
+	return func(o *options) {
+		o.envNames = append(o.envNames, names...)
+	}
+}
+
+// WithEnv sets an environment variable of the command.
+func WithEnv(name, value string) Option {
+	return func(o *options) {
+		o.env = append(o.env, name+"="+value)
+	}
+}
+
+// WithInheritedEnv passes the whole environment of the server to the
+// command instead of an allowlist.
+func WithInheritedEnv() Option {
+	return func(o *options) {
+		o.inheritEnv = true
+	}
+}
+
+// waitDelay bounds the wait for the output of a killed command.
+const waitDelay = 100 * time.Millisecond
+
+// NewTool builds a tool running command.
+func NewTool(name string, command Command, opts ...Option) (server.ServerTool, error) {
+	o := newOptions(opts)
+	if o.dirParam != "" {
+		command.Params = append(command.Params, Param{
+			Name:        o.dirParam,
+			Description: "Working directory of the command",
+		})
+	}
+	properties := make(map[string]any, len(command.Params))
+	var required []string
+	for _, param := range command.Params {
+		if _, ok := properties[param.Name]; ok {
+			return server.ServerTool{}, fmt.Errorf("parameter %q of tool %q is defined more than once", param.Name, name)
+		}
+		properties[param.Name] = param.schema()
+		if param.Required {
+			required = append(required, param.Name)
+		}
+	}
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	rawSchema, err := json.Marshal(schema)
+	if err != nil {
+		return server.ServerTool{}, fmt.Errorf("invalid parameter schema of tool %q: %w", name, err)
+	}
+
+	handler, err := newHandler(command, o)
+	if err != nil {
+		return server.ServerTool{}, fmt.Errorf("tool %q: %w", name, err)
+	}
+	tool := mcp.NewTool(name, mcp.WithDescription(command.Description), mcp.WithRawInputSchema(rawSchema))
+	if command.Annotations != nil {
+		tool.Annotations = *command.Annotations
+	}
+	return server.ServerTool{Tool: tool, Handler: handler}, nil
+}
+
+// NewHandler builds a handler running command, for tools whose definition
+// is built separately. Only the Args, Stdin and Env fields of command are
+// used.
+func NewHandler(command Command, opts ...Option) (server.ToolHandlerFunc, error) {
+	return newHandler(command, newOptions(opts))
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{maxOutput: 1 << 20}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+func (p Param) schema() map[string]any {
+	schema := make(map[string]any, len(p.Schema)+2)
+	for key, value := range p.Schema {
+		schema[key] = value
+	}
+	if p.Schema == nil {
+		schema["type"] = "string"
+		if p.Type != "" {
+			schema["type"] = p.Type
+		}
+	}
+	if p.Description != "" {
+		schema["description"] = p.Description
+	}
+	return schema
+}
+
+func newHandler(command Command, o *options) (server.ToolHandlerFunc, error) {
+	if len(command.Args) == 0 {
+		return nil, errors.New("command has no program")
+	}
+	args, err := argtemplate.Parse("argument", command.Args, nil)
+	if err != nil {
+		return nil, err
+	}
+	env, err := argtemplate.Parse("environment", command.Env, nil)
+	if err != nil {
+		return nil, err
+	}
+	var stdin []*template.Template
+	if command.Stdin != "" {
+		if stdin, err = argtemplate.Parse("stdin", []string{command.Stdin}, nil); err != nil {
+			return nil, err
+		}
+	}
+	baseEnv := o.environment()
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments := request.GetArguments()
+		argv := make([]string, len(args))
+		for i, tmpl := range args {
+			value, err := argtemplate.Render(tmpl, arguments)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to expand command", err), nil
+			}
+			argv[i] = value
+		}
+		environ := baseEnv
+		for _, tmpl := range env {
+			value, err := argtemplate.Render(tmpl, arguments)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to expand environment", err), nil
+			}
+			environ = append(environ[:len(environ):len(environ)], value)
+		}
+		dir, err := o.workingDir(arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if o.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, o.timeout)
+			defer cancel()
+		}
+		cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+		killProcessGroup(cmd)
+		// Processes the command leaves behind may hold its output open; stop
+		// waiting for them shortly after it is killed.
+		cmd.WaitDelay = waitDelay
+		cmd.Dir = dir
+		cmd.Env = environ
+		if stdin != nil {
+			input, err := argtemplate.Render(stdin[0], arguments)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to expand standard input", err), nil
+			}
+			cmd.Stdin = strings.NewReader(input)
+		}
+		stdout, stderr := &limitedBuffer{limit: o.maxOutput}, &limitedBuffer{limit: o.maxOutput}
+		cmd.Stdout, cmd.Stderr = stdout, stderr
+
+		err = cmd.Run()
+		if err != nil && ctx.Err() != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) && o.timeout > 0 {
+				return mcp.NewToolResultErrorf("command timed out after %s", o.timeout), nil
+			}
+			return mcp.NewToolResultErrorFromErr("command canceled", ctx.Err()), nil
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			message := strings.TrimSpace(stderr.String())
+			if message == "" {
+				message = strings.TrimSpace(stdout.String())
+			}
+			return mcp.NewToolResultErrorf("command exited with status %d: %s", exitErr.ExitCode(), message), nil
+		}
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to run command", err), nil
+		}
+
+		output := stdout.String()
+		if stdout.truncated {
+			output += fmt.Sprintf("\n[output truncated to %d bytes]", o.maxOutput)
+		}
+		return mcp.NewToolResultText(output), nil
+	}, nil
+}
+
+// environment returns the environment commands start from.
+func (o *options) environment() []string {
+	var env []string
+	if o.inheritEnv {
+		env = os.Environ()
+	} else {
+		for _, name := range append([]string{"PATH"}, o.envNames...) {
+			if value, ok := os.LookupEnv(name); ok {
+				env = append(env, name+"="+value)
+			}
+		}
+	}
+	return append(env, o.env...)
+}
+
+// workingDir returns the working directory of a call, checking the one
+// chosen with the WithDirParam argument against the allowed roots.
+func (o *options) workingDir(args map[string]any) (string, error) {
+	if o.dirParam == "" {
+		return o.dir, nil
+	}
+	requested, _ := args[o.dirParam].(string)
+	if requested == "" {
+		return o.dir, nil
+	}
+	if len(o.dirRoots) == 0 {
+		return "", fmt.Errorf("directory %q is not allowed", requested)
+	}
+	if !filepath.IsAbs(requested) {
+		requested = filepath.Join(o.dirRoots[0], requested)
+	}
+	resolved, err := filepath.EvalSymlinks(requested)
+	if err != nil {
+		return "", fmt.Errorf("invalid directory %q: %w", args[o.dirParam], err)
+	}
+	for _, root := range o.dirRoots {
+		root, err := filepath.EvalSymlinks(root)
+		if err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(root, resolved); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return resolved, nil
+		}
+	}
+	return "", fmt.Errorf("directory %q is outside the allowed directories", args[o.dirParam])
+}
+
+// limitedBuffer keeps the first limit bytes written to it. The buffer is
+// not embedded so that io.Copy cannot bypass Write through ReadFrom.
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); room < len(p) {
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		b.truncated = true
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) String() string {
+	return b.buf.String()
+}
@@ -0,0 +1,224 @@
+package exectool
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func skipOnWindows(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the test commands need a POSIX shell")
+	}
+}
+
+func callTool(t *testing.T, tool server.ServerTool, args map[string]any) (string, bool) {
+	t.Helper()
+	request := mcp.CallToolRequest{}
+	request.Params.Name = tool.Tool.Name
+	request.Params.Arguments = args
+	result, err := tool.Handler(context.Background(), request)
+	require.NoError(t, err)
+	require.Len(t, result.Content, 1)
+	return result.Content[0].(mcp.TextContent).Text, result.IsError
+}
+
+func TestNewTool_Schema(t *testing.T) {
+	tool, err := NewTool("grep", Command{
+		Description: "Search files",
+		Args:        []string{"grep", "--", "{{.pattern}}"},
+		Params: []Param{
+			{Name: "pattern", Description: "Pattern to search for", Required: true},
+			{Name: "limit", Type: "integer"},
+		},
+		Annotations: &mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)},
+	}, WithDirParam("dir", t.TempDir()))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Search files", tool.Tool.Description)
+	assert.True(t, *tool.Tool.Annotations.ReadOnlyHint)
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(tool.Tool.RawInputSchema, &schema))
+	assert.Equal(t, map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"pattern": map[string]any{"type": "string", "description": "Pattern to search for"},
+			"limit":   map[string]any{"type": "integer"},
+			"dir":     map[string]any{"type": "string", "description": "Working directory of the command"},
+		},
+		"required": []any{"pattern"},
+	}, schema)
+}
+
+func TestNewTool_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		command Command
+	}{
+		{name: "no program", command: Command{}},
+		{name: "bad template", command: Command{Args: []string{"echo", "{{.x"}}},
+		{name: "duplicate parameter", command: Command{Args: []string{"echo"}, Params: []Param{{Name: "a"}, {Name: "a"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTool("tool", tt.command)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewTool_Run(t *testing.T) {
+	skipOnWindows(t)
+	t.Setenv("EXECTOOL_ALLOWED", "allowed")
+	t.Setenv("EXECTOOL_SECRET", "secret")
+
+	tests := []struct {
+		name     string
+		command  Command
+		opts     []Option
+		args     map[string]any
+		expected string
+		isError  bool
+	}{
+		{
+			name:     "arguments are not split or interpreted",
+			command:  Command{Args: []string{"printf", "%s|", "{{.text}}", "{{.missing}}"}},
+			args:     map[string]any{"text": "a b; echo injected"},
+			expected: "a b; echo injected||",
+		},
+		{
+			name:     "values are not altered",
+			command:  Command{Args: []string{"printf", "%s|", "{{.text}}", "{{$.missing}}"}},
+			args:     map[string]any{"text": "<no value>"},
+			expected: "<no value>||",
+		},
+		{
+			name:     "standard input",
+			command:  Command{Args: []string{"cat"}, Stdin: "{{json .}}"},
+			args:     map[string]any{"n": 1},
+			expected: `{"n":1}`,
+		},
+		{
+			name:     "environment allowlist",
+			command:  Command{Args: []string{"sh", "-c", `echo "$EXECTOOL_ALLOWED-$EXECTOOL_SECRET-$FIXED-$CALL"`}, Env: []string{"CALL={{.v}}"}},
+			opts:     []Option{WithEnvAllowlist("EXECTOOL_ALLOWED"), WithEnv("FIXED", "fixed")},
+			args:     map[string]any{"v": "call"},
+			expected: "allowed--fixed-call\n",
+		},
+		{
+			name:     "inherited environment",
+			command:  Command{Args: []string{"sh", "-c", `echo "$EXECTOOL_SECRET"`}},
+			opts:     []Option{WithInheritedEnv()},
+			expected: "secret\n",
+		},
+		{
+			name:     "truncated output",
+			command:  Command{Args: []string{"printf", "0123456789"}},
+			opts:     []Option{WithMaxOutput(4)},
+			expected: "0123\n[output truncated to 4 bytes]",
+		},
+		{
+			name:     "failing command",
+			command:  Command{Args: []string{"sh", "-c", "echo oops >&2; exit 3"}},
+			expected: "command exited with status 3: oops",
+			isError:  true,
+		},
+		{
+			name:     "timeout",
+			command:  Command{Args: []string{"sleep", "5"}},
+			opts:     []Option{WithTimeout(50 * time.Millisecond)},
+			expected: "command timed out after 50ms",
+			isError:  true,
+		},
+		{
+			name:     "timeout with a background process",
+			command:  Command{Args: []string{"sh", "-c", "sleep 5 & wait"}},
+			opts:     []Option{WithTimeout(50 * time.Millisecond)},
+			expected: "command timed out after 50ms",
+			isError:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool, err := NewTool("tool", tt.command, tt.opts...)
+			require.NoError(t, err)
+			start := time.Now()
+			text, isError := callTool(t, tool, tt.args)
+			assert.Equal(t, tt.expected, text)
+			assert.Equal(t, tt.isError, isError)
+			assert.Less(t, time.Since(start), 2*time.Second)
+		})
+	}
+}
+
+func TestNewTool_DirParam(t *testing.T) {
+	skipOnWindows(t)
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, "sub"), 0o755))
+	outside := t.TempDir()
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "escape")))
+	fixed := t.TempDir()
+
+	tool, err := NewTool("pwd", Command{Args: []string{"pwd", "-P"}}, WithDir(fixed), WithDirParam("dir", root))
+	require.NoError(t, err)
+	resolve := func(dir string) string {
+		resolved, err := filepath.EvalSymlinks(dir)
+		require.NoError(t, err)
+		return resolved
+	}
+
+	tests := []struct {
+		name     string
+		dir      any
+		expected string
+		isError  bool
+	}{
+		{name: "default directory", expected: resolve(fixed)},
+		{name: "relative to the root", dir: "sub", expected: resolve(filepath.Join(root, "sub"))},
+		{name: "absolute inside the root", dir: filepath.Join(root, "sub"), expected: resolve(filepath.Join(root, "sub"))},
+		{name: "parent of the root", dir: "..", isError: true},
+		{name: "other directory", dir: outside, isError: true},
+		{name: "symbolic link out of the root", dir: "escape", isError: true},
+		{name: "missing directory", dir: "missing", isError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := map[string]any{}
+			if tt.dir != nil {
+				args["dir"] = tt.dir
+			}
+			text, isError := callTool(t, tool, args)
+			assert.Equal(t, tt.isError, isError, text)
+			if !tt.isError {
+				assert.Equal(t, tt.expected, strings.TrimSpace(text))
+			}
+		})
+	}
+}
+
+func TestNewHandler(t *testing.T) {
+	skipOnWindows(t)
+	handler, err := NewHandler(Command{Args: []string{"echo", "{{.name}}"}})
+	require.NoError(t, err)
+
+	s := server.NewMCPServer("test-server", "1.0.0")
+	s.AddTool(mcp.NewTool("echo", mcp.WithString("name")), handler)
+	response := s.HandleMessage(context.Background(), json.RawMessage(
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"name":"world"}}}`))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "unexpected response %#v", response)
+	result := resp.Result.(mcp.CallToolResult)
+	assert.Equal(t, "world\n", result.Content[0].(mcp.TextContent).Text)
+}
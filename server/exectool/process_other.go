@@ -0,0 +1,9 @@
+//go:build !unix
+
+package exectool
+
+import "os/exec"
+
+// killProcessGroup leaves cmd as is: only its own process is killed on
+// cancellation, and WaitDelay bounds the wait for its output.
+func killProcessGroup(cmd *exec.Cmd) {}
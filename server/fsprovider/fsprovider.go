@@ -0,0 +1,428 @@
+// Package fsprovider exposes directories of the local filesystem as MCP
+// resources.
+//
+// Each root directory is served under its file URI: the root itself and the
+// directories below it are read as JSON listings of their entries, and files
+// as their content, as text or as a base64 blob depending on their MIME type:
+//
+//	provider, err := fsprovider.New([]string{"/srv/docs"},
+//	    fsprovider.WithMaxFileSize(1<<20),
+//	)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	provider.Register(s)
+//	go provider.Watch(ctx, s)
+//
+// Paths are resolved within their root only: ".." segments are rejected and
+// symbolic links are handled according to the SymlinkPolicy, by default
+// followed only when they point inside the root.
+//
+// Watch watches the roots for changes and sends resource updated
+// notifications to the sessions subscribed to the changed files and to the
+// directories whose listing changed. The server needs resource
+// subscriptions enabled with server.WithResourceCapabilities for clients to
+// subscribe.
+package fsprovider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mark3labs/mcp-go/util"
+)
+
+// DirectoryMIMEType is the MIME type of directory listings.
+const DirectoryMIMEType = "application/json"
+
+// SymlinkPolicy decides how symbolic links below a root are handled.
+type SymlinkPolicy int
+
+const (
+	// SymlinksWithinRoot follows symbolic links pointing inside the root of
+	// the path and rejects the others. It is the default.
+	SymlinksWithinRoot SymlinkPolicy = iota
+	// SymlinksDeny rejects every path going through a symbolic link and
+	// leaves links out of directory listings.
+	SymlinksDeny
+	// SymlinksFollow follows every symbolic link, wherever it points.
+	SymlinksFollow
+)
+
+// Option configures a Provider.
+type Option func(*Provider)
+
+// WithMaxFileSize bounds the size of the files that can be read, 10 MiB by
+// default. Reading a larger file fails.
+func WithMaxFileSize(size int64) Option {
+	return func(p *Provider) {
+		p.maxFileSize = size
+	}
+}
+
+// WithSymlinkPolicy sets how symbolic links are handled.
+func WithSymlinkPolicy(policy SymlinkPolicy) Option {
+	return func(p *Provider) {
+		p.symlinks = policy
+	}
+}
+
+// Provider serves root directories as resources.
+type Provider struct {
+	roots       []root
+	maxFileSize int64
+	symlinks    SymlinkPolicy
+}
+
+type root struct {
+	path string
+	uri  string
+}
+
+// New returns a provider serving the given directories. Relative roots are
+// made absolute. New fails if a root is not an existing directory.
+func New(roots []string, opts ...Option) (*Provider, error) {
+	p := &Provider{maxFileSize: 10 << 20}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if len(roots) == 0 {
+		return nil, errors.New("fsprovider: no root directory")
+	}
+	for _, dir := range roots {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, fmt.Errorf("fsprovider: invalid root %q: %w", dir, err)
+		}
+		resolved, err := filepath.EvalSymlinks(abs)
+		if err != nil {
+			return nil, fmt.Errorf("fsprovider: invalid root %q: %w", dir, err)
+		}
+		info, err := os.Stat(resolved)
+		if err != nil {
+			return nil, fmt.Errorf("fsprovider: invalid root %q: %w", dir, err)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("fsprovider: root %q is not a directory", dir)
+		}
+		p.roots = append(p.roots, root{path: resolved, uri: util.FileURI(resolved)})
+	}
+	return p, nil
+}
+
+// Register adds the resources and resource templates of the provider to s.
+func (p *Provider) Register(s *server.MCPServer) {
+	s.AddResources(p.Resources()...)
+	s.AddResourceTemplates(p.ResourceTemplates()...)
+}
+
+// Resources returns a resource listing each root directory.
+func (p *Provider) Resources() []server.ServerResource {
+	resources := make([]server.ServerResource, len(p.roots))
+	for i, r := range p.roots {
+		resources[i] = server.ServerResource{
+			Resource: mcp.NewResource(r.uri, filepath.Base(r.path),
+				mcp.WithResourceDescription("Directory "+r.path),
+				mcp.WithMIMEType(DirectoryMIMEType)),
+			Handler: p.handler(r),
+		}
+	}
+	return resources
+}
+
+// ResourceTemplates returns a template serving the files and directories
+// below each root, of the form file:///root{/path*}.
+func (p *Provider) ResourceTemplates() []server.ServerResourceTemplate {
+	templates := make([]server.ServerResourceTemplate, len(p.roots))
+	for i, r := range p.roots {
+		templates[i] = server.ServerResourceTemplate{
+			Template: mcp.NewResourceTemplate(r.uri+"{/path*}", filepath.Base(r.path)+" files",
+				mcp.WithTemplateDescription("Files and directories below "+r.path)),
+			Handler: server.ResourceTemplateHandlerFunc(p.handler(r)),
+		}
+	}
+	return templates
+}
+
+// handler reads the files and directories of r.
+func (p *Provider) handler(r root) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		var segments []string
+		switch value := request.Params.Arguments["path"].(type) {
+		case []string:
+			segments = value
+		case string:
+			segments = []string{value}
+		}
+		path, err := p.resolve(r, segments)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", server.ErrResourceNotFound, request.Params.URI, err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", server.ErrResourceNotFound, request.Params.URI)
+		}
+		if info.IsDir() {
+			return p.readDir(r, path, request.Params.URI)
+		}
+		return p.readFile(path, info, request.Params.URI)
+	}
+}
+
+// resolve returns the path of the segments below r, checking it against the
+// symbolic link policy.
+func (p *Provider) resolve(r root, segments []string) (string, error) {
+	path := r.path
+	for i, segment := range segments {
+		if segment == "" && i == len(segments)-1 {
+			// A trailing slash.
+			break
+		}
+		if segment == "" || segment == "." || segment == ".." || strings.ContainsAny(segment, `/\`) {
+			return "", fmt.Errorf("invalid path segment %q", segment)
+		}
+		path = filepath.Join(path, segment)
+		if p.symlinks == SymlinksDeny {
+			info, err := os.Lstat(path)
+			if err != nil {
+				return "", err
+			}
+			if info.Mode()&fs.ModeSymlink != 0 {
+				return "", errors.New("symbolic links are not allowed")
+			}
+		}
+	}
+	if p.symlinks == SymlinksWithinRoot {
+		resolved, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			return "", err
+		}
+		if !within(r.path, resolved) {
+			return "", errors.New("symbolic link points outside of the root")
+		}
+	}
+	return path, nil
+}
+
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// Entry is an entry of a directory listing.
+type Entry struct {
+	Name string `json:"name"`
+	URI  string `json:"uri"`
+	// Type is "file" or "directory".
+	Type string `json:"type"`
+	// Size is the size of files in bytes.
+	Size    int64     `json:"size,omitempty"`
+	ModTime time.Time `json:"modTime"`
+}
+
+func (p *Provider) readDir(r root, dir, uri string) ([]mcp.ResourceContents, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", uri, err)
+	}
+	base := strings.TrimSuffix(util.FileURI(dir), "/")
+	listing := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if entry.Type()&fs.ModeSymlink != 0 {
+			if p.symlinks == SymlinksDeny {
+				continue
+			}
+			if _, err := p.resolve(r, relativeSegments(r.path, path)); err != nil {
+				continue
+			}
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		item := Entry{
+			Name:    entry.Name(),
+			URI:     base + "/" + url.PathEscape(entry.Name()),
+			Type:    "file",
+			ModTime: info.ModTime().UTC(),
+		}
+		if info.IsDir() {
+			item.Type = "directory"
+		} else {
+			item.Size = info.Size()
+		}
+		listing = append(listing, item)
+	}
+	data, err := json.Marshal(listing)
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{URI: uri, MIMEType: DirectoryMIMEType, Text: string(data)},
+	}, nil
+}
+
+func relativeSegments(root, path string) []string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return nil
+	}
+	return strings.Split(rel, string(filepath.Separator))
+}
+
+func (p *Provider) readFile(path string, info fs.FileInfo, uri string) ([]mcp.ResourceContents, error) {
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s is not a regular file", uri)
+	}
+	if info.Size() > p.maxFileSize {
+		return nil, fmt.Errorf("%s is %d bytes, more than the limit of %d bytes", uri, info.Size(), p.maxFileSize)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", uri, err)
+	}
+	if int64(len(data)) > p.maxFileSize {
+		return nil, fmt.Errorf("%s is more than the limit of %d bytes", uri, p.maxFileSize)
+	}
+	mimeType := server.DetectMIMEType(path, data)
+	if isText(mimeType) && utf8.Valid(data) {
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{URI: uri, MIMEType: mimeType, Text: string(data)},
+		}, nil
+	}
+	return []mcp.ResourceContents{
+		mcp.BlobResourceContents{URI: uri, MIMEType: mimeType, Blob: base64.StdEncoding.EncodeToString(data)},
+	}, nil
+}
+
+// isText reports whether content of mimeType is returned as text.
+func isText(mimeType string) bool {
+	mediaType, _, _ := strings.Cut(mimeType, ";")
+	mediaType = strings.TrimSpace(mediaType)
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		strings.HasSuffix(mediaType, "+json"),
+		strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/xml", "application/javascript", "application/yaml", "application/toml":
+		return true
+	}
+	return false
+}
+
+// watchDelay is how long Watch gathers the events of a burst of changes,
+// such as the several writes of one save, before notifying them once.
+const watchDelay = 50 * time.Millisecond
+
+// Watch watches the roots with fsnotify until ctx is done and calls
+// s.NotifyResourceUpdated for every file created, modified or removed, and
+// for the directories whose entries changed. Directories created below the
+// roots are watched as they appear; symbolic links are not followed. It
+// returns ctx.Err(), or the error of setting up the watches.
+func (p *Provider) Watch(ctx context.Context, s *server.MCPServer) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("fsprovider: %w", err)
+	}
+	defer watcher.Close()
+	for _, r := range p.roots {
+		if err := watchTree(watcher, r.path, nil); err != nil {
+			return fmt.Errorf("fsprovider: watching %s: %w", r.path, err)
+		}
+	}
+
+	pending := make(map[string]bool)
+	var flush <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event := <-watcher.Events:
+			for _, uri := range eventURIs(watcher, event) {
+				pending[uri] = true
+			}
+			if len(pending) > 0 && flush == nil {
+				flush = time.After(watchDelay)
+			}
+		case <-watcher.Errors:
+			// Events lost to an overflow of the kernel queue cannot be
+			// recovered; later ones are still reported.
+		case <-flush:
+			uris := make([]string, 0, len(pending))
+			for uri := range pending {
+				uris = append(uris, uri)
+			}
+			sort.Strings(uris)
+			for _, uri := range uris {
+				s.NotifyResourceUpdated(uri)
+			}
+			clear(pending)
+			flush = nil
+		}
+	}
+}
+
+// watchTree adds dir and the directories below it to watcher. If created
+// is not nil, it is called with every entry below dir, which may have been
+// created before the watches were added.
+func watchTree(watcher *fsnotify.Watcher, dir string, created func(path string)) error {
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			return nil
+		}
+		if path != dir && created != nil {
+			created(path)
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if err := watcher.Add(path); err != nil && path == dir {
+			return err
+		}
+		return nil
+	})
+}
+
+// eventURIs returns the URIs of the resources changed by event: the file
+// and, when it was created, removed or renamed, its directory. Created
+// directories are added to watcher.
+func eventURIs(watcher *fsnotify.Watcher, event fsnotify.Event) []string {
+	fileURI := util.FileURI(event.Name)
+	dirURI := util.FileURI(filepath.Dir(event.Name))
+	switch {
+	case event.Has(fsnotify.Create):
+		uris := []string{dirURI, fileURI}
+		if info, err := os.Lstat(event.Name); err == nil && info.IsDir() {
+			_ = watchTree(watcher, event.Name, func(path string) {
+				uris = append(uris, util.FileURI(path), util.FileURI(filepath.Dir(path)))
+			})
+		}
+		return uris
+	case event.Has(fsnotify.Remove), event.Has(fsnotify.Rename):
+		return []string{dirURI, fileURI}
+	case event.Has(fsnotify.Write):
+		return []string{fileURI}
+	}
+	return nil
+}
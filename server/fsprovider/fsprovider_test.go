@@ -0,0 +1,292 @@
+package fsprovider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// newTree creates a root with files, a subdirectory and symbolic links
+// pointing inside and outside of it.
+func newTree(t *testing.T) (root, outside string) {
+	t.Helper()
+	root, outside = t.TempDir(), t.TempDir()
+	write := func(path string, content []byte) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, content, 0o600))
+	}
+	write(filepath.Join(root, "notes.txt"), []byte("hello"))
+	write(filepath.Join(root, "my notes.md"), []byte("# notes"))
+	write(filepath.Join(root, "image.bin"), []byte{0x89, 'P', 'N', 'G', 0x0d, 0x0a, 0x1a, 0x0a, 0x00})
+	write(filepath.Join(root, "sub", "data.json"), []byte(`{"a":1}`))
+	write(filepath.Join(outside, "secret.txt"), []byte("secret"))
+	if runtime.GOOS != "windows" {
+		require.NoError(t, os.Symlink(filepath.Join(root, "notes.txt"), filepath.Join(root, "inside-link")))
+		require.NoError(t, os.Symlink(filepath.Join(outside, "secret.txt"), filepath.Join(root, "outside-link")))
+	}
+	return root, outside
+}
+
+func newTestServer(t *testing.T, roots []string, opts ...Option) (*server.MCPServer, *Provider) {
+	t.Helper()
+	provider, err := New(roots, opts...)
+	require.NoError(t, err)
+	s := server.NewMCPServer("test-server", "1.0.0", server.WithResourceCapabilities(true, false))
+	provider.Register(s)
+	return s, provider
+}
+
+func readResource(t *testing.T, ctx context.Context, s *server.MCPServer, uri string) (mcp.ResourceContents, error) {
+	t.Helper()
+	message := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":%q}}`, uri)
+	switch response := s.HandleMessage(ctx, json.RawMessage(message)).(type) {
+	case mcp.JSONRPCResponse:
+		result := response.Result.(mcp.ReadResourceResult)
+		require.Len(t, result.Contents, 1)
+		return result.Contents[0], nil
+	case mcp.JSONRPCError:
+		return nil, errors.New(response.Error.Message)
+	default:
+		t.Fatalf("unexpected response %#v", response)
+		return nil, nil
+	}
+}
+
+func TestNew(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(file, nil, 0o600))
+
+	_, err := New(nil)
+	assert.Error(t, err)
+	_, err = New([]string{filepath.Join(t.TempDir(), "missing")})
+	assert.Error(t, err)
+	_, err = New([]string{file})
+	assert.Error(t, err)
+}
+
+func TestProvider_Read(t *testing.T) {
+	root, _ := newTree(t)
+	s, provider := newTestServer(t, []string{root})
+	rootURI := provider.roots[0].uri
+
+	contents, err := readResource(t, context.Background(), s, rootURI+"/notes.txt")
+	require.NoError(t, err)
+	text := contents.(mcp.TextResourceContents)
+	assert.Equal(t, "hello", text.Text)
+	assert.Equal(t, "text/plain; charset=utf-8", text.MIMEType)
+
+	contents, err = readResource(t, context.Background(), s, rootURI+"/my%20notes.md")
+	require.NoError(t, err)
+	assert.Equal(t, "# notes", contents.(mcp.TextResourceContents).Text)
+
+	contents, err = readResource(t, context.Background(), s, rootURI+"/sub/data.json")
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", contents.(mcp.TextResourceContents).MIMEType)
+
+	contents, err = readResource(t, context.Background(), s, rootURI+"/image.bin")
+	require.NoError(t, err)
+	blob := contents.(mcp.BlobResourceContents)
+	data, err := base64.StdEncoding.DecodeString(blob.Blob)
+	require.NoError(t, err)
+	assert.Len(t, data, 9)
+
+	for _, uri := range []string{rootURI + "/missing.txt", rootURI + "/%2e%2e/etc/passwd", rootURI + "/sub/%2e%2e/notes.txt"} {
+		_, err = readResource(t, context.Background(), s, uri)
+		assert.Error(t, err, uri)
+	}
+}
+
+func TestProvider_ReadDirectory(t *testing.T) {
+	root, _ := newTree(t)
+	s, provider := newTestServer(t, []string{root}, WithSymlinkPolicy(SymlinksDeny))
+	rootURI := provider.roots[0].uri
+
+	for _, uri := range []string{rootURI, rootURI + "/"} {
+		contents, err := readResource(t, context.Background(), s, uri)
+		require.NoError(t, err)
+		text := contents.(mcp.TextResourceContents)
+		assert.Equal(t, DirectoryMIMEType, text.MIMEType)
+		var entries []Entry
+		require.NoError(t, json.Unmarshal([]byte(text.Text), &entries))
+
+		names := make(map[string]Entry)
+		for _, entry := range entries {
+			names[entry.Name] = entry
+		}
+		assert.Len(t, names, 4, "symbolic links are left out")
+		assert.Equal(t, "directory", names["sub"].Type)
+		assert.Equal(t, "file", names["notes.txt"].Type)
+		assert.Equal(t, int64(5), names["notes.txt"].Size)
+		assert.Equal(t, rootURI+"/my%20notes.md", names["my notes.md"].URI)
+	}
+}
+
+func TestProvider_SymlinkPolicy(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symbolic links needs privileges on Windows")
+	}
+	tests := []struct {
+		name      string
+		policy    SymlinkPolicy
+		insideOK  bool
+		outsideOK bool
+	}{
+		{name: "within root", policy: SymlinksWithinRoot, insideOK: true, outsideOK: false},
+		{name: "deny", policy: SymlinksDeny, insideOK: false, outsideOK: false},
+		{name: "follow", policy: SymlinksFollow, insideOK: true, outsideOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, _ := newTree(t)
+			s, provider := newTestServer(t, []string{root}, WithSymlinkPolicy(tt.policy))
+			rootURI := provider.roots[0].uri
+
+			_, err := readResource(t, context.Background(), s, rootURI+"/inside-link")
+			assert.Equal(t, tt.insideOK, err == nil, "inside link: %v", err)
+			_, err = readResource(t, context.Background(), s, rootURI+"/outside-link")
+			assert.Equal(t, tt.outsideOK, err == nil, "outside link: %v", err)
+		})
+	}
+}
+
+func TestProvider_MaxFileSize(t *testing.T) {
+	root, _ := newTree(t)
+	s, provider := newTestServer(t, []string{root}, WithMaxFileSize(4))
+
+	_, err := readResource(t, context.Background(), s, provider.roots[0].uri+"/notes.txt")
+	assert.Error(t, err)
+}
+
+func TestProvider_MultipleRoots(t *testing.T) {
+	first, _ := newTree(t)
+	second := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(second, "other.txt"), []byte("other"), 0o600))
+	s, provider := newTestServer(t, []string{first, second})
+
+	assert.Len(t, provider.Resources(), 2)
+	assert.Len(t, provider.ResourceTemplates(), 2)
+	contents, err := readResource(t, context.Background(), s, provider.roots[1].uri+"/other.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "other", contents.(mcp.TextResourceContents).Text)
+	_, err = readResource(t, context.Background(), s, provider.roots[0].uri+"/other.txt")
+	assert.Error(t, err)
+}
+
+type testSession struct {
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *testSession) SessionID() string { return "session-1" }
+func (s *testSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+func (s *testSession) Initialize()       {}
+func (s *testSession) Initialized() bool { return true }
+
+func TestProvider_Watch(t *testing.T) {
+	root, _ := newTree(t)
+	s, provider := newTestServer(t, []string{root})
+	rootURI := provider.roots[0].uri
+
+	session := &testSession{notifications: make(chan mcp.JSONRPCNotification, 10)}
+	require.NoError(t, s.RegisterSession(context.Background(), session))
+	ctx := s.WithContext(context.Background(), session)
+	for _, uri := range []string{rootURI + "/notes.txt", rootURI + "/sub"} {
+		response := s.HandleMessage(ctx, json.RawMessage(fmt.Sprintf(
+			`{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":%q}}`, uri)))
+		require.IsType(t, mcp.JSONRPCResponse{}, response)
+	}
+
+	watchCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- provider.Watch(watchCtx, s) }()
+	// Let Watch add its watches.
+	time.Sleep(30 * time.Millisecond)
+
+	expectUpdate := func(uri string) {
+		t.Helper()
+		select {
+		case notification := <-session.notifications:
+			assert.Equal(t, mcp.MethodNotificationResourceUpdated, notification.Method)
+			assert.Equal(t, uri, notification.Params.AdditionalFields["uri"])
+		case <-time.After(2 * time.Second):
+			t.Fatalf("no update for %s", uri)
+		}
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(root, "notes.txt"), []byte("hello again"), 0o600))
+	expectUpdate(rootURI + "/notes.txt")
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "sub", "new.txt"), []byte("new"), 0o600))
+	expectUpdate(rootURI + "/sub")
+
+	// Changes to files nobody subscribed to send nothing.
+	require.NoError(t, os.WriteFile(filepath.Join(root, "my notes.md"), []byte("changed"), 0o600))
+	time.Sleep(4 * watchDelay)
+	assert.Empty(t, session.notifications)
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestProvider_Watch_CreatedDirectory(t *testing.T) {
+	root, _ := newTree(t)
+	s, provider := newTestServer(t, []string{root})
+	rootURI := provider.roots[0].uri
+
+	session := &testSession{notifications: make(chan mcp.JSONRPCNotification, 10)}
+	require.NoError(t, s.RegisterSession(context.Background(), session))
+	ctx := s.WithContext(context.Background(), session)
+	for _, uri := range []string{rootURI + "/new", rootURI + "/new/nested/file.txt"} {
+		response := s.HandleMessage(ctx, json.RawMessage(fmt.Sprintf(
+			`{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":%q}}`, uri)))
+		require.IsType(t, mcp.JSONRPCResponse{}, response)
+	}
+
+	watchCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = provider.Watch(watchCtx, s) }()
+	time.Sleep(30 * time.Millisecond)
+
+	updated := func() []string {
+		t.Helper()
+		var uris []string
+		timeout := time.After(2 * time.Second)
+		for {
+			select {
+			case notification := <-session.notifications:
+				uris = append(uris, notification.Params.AdditionalFields["uri"].(string))
+			case <-time.After(4 * watchDelay):
+				if len(uris) > 0 {
+					return uris
+				}
+			case <-timeout:
+				t.Fatal("no update")
+				return nil
+			}
+		}
+	}
+
+	// Entries created with the directory, before it is watched, are
+	// reported with it.
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "new", "nested"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "new", "nested", "file.txt"), []byte("a"), 0o600))
+	assert.ElementsMatch(t, []string{rootURI + "/new", rootURI + "/new/nested/file.txt"}, updated())
+
+	// The new directories are watched.
+	require.NoError(t, os.WriteFile(filepath.Join(root, "new", "nested", "file.txt"), []byte("b"), 0o600))
+	assert.Equal(t, []string{rootURI + "/new/nested/file.txt"}, updated())
+}
@@ -61,6 +61,31 @@ type OnErrorHookFunc func(ctx context.Context, id any, method mcp.MCPMethod, mes
 // Should any errors arise during func execution, the service will promptly return the corresponding error message.
 type OnRequestInitializationFunc func(ctx context.Context, id any, message any) error
 
+// OnPanicHookFunc is a hook that will be called when WithRecovery recovers
+// from a panic in a request handler, before the request is answered with an
+// internal error. The session, if any, is available through
+// ClientSessionFromContext, and err carries the recovered value and the
+// stack trace of the panic.
+type OnPanicHookFunc func(ctx context.Context, id any, method mcp.MCPMethod, message any, err *PanicError)
+
+// OnDeprecatedToolCallHookFunc is a hook that will be called when a
+// deprecated tool, or a tool through a deprecated alias, is called, before
+// the tool runs.
+type OnDeprecatedToolCallHookFunc func(ctx context.Context, id any, call DeprecatedToolCall)
+
+// OnLimitExceededHookFunc is a hook that will be called when a message
+// exceeds a limit set with WithLimits, before it is rejected. The id is nil
+// for notifications, responses, and HTTP request bodies too large to read.
+type OnLimitExceededHookFunc func(ctx context.Context, id any, method mcp.MCPMethod, err *LimitExceededError)
+
+// OnInitializeHookFunc is a hook that will be called when a client
+// initializes a session, before the server answers. It can change the
+// capabilities of the handshake and attach values to the session with
+// SetSessionValue. Returning an error rejects the client: the session is not
+// initialized and the client receives the error, with the code of an
+// *mcp.Error or INVALID_REQUEST.
+type OnInitializeHookFunc func(ctx context.Context, id any, handshake *InitializeHandshake) error
+
 type OnBeforeInitializeFunc func(ctx context.Context, id any, message *mcp.InitializeRequest)
 type OnAfterInitializeFunc func(ctx context.Context, id any, message *mcp.InitializeRequest, result *mcp.InitializeResult)
 
@@ -79,6 +104,12 @@ type OnAfterListResourceTemplatesFunc func(ctx context.Context, id any, message
 type OnBeforeReadResourceFunc func(ctx context.Context, id any, message *mcp.ReadResourceRequest)
 type OnAfterReadResourceFunc func(ctx context.Context, id any, message *mcp.ReadResourceRequest, result *mcp.ReadResourceResult)
 
+type OnBeforeSubscribeFunc func(ctx context.Context, id any, message *mcp.SubscribeRequest)
+type OnAfterSubscribeFunc func(ctx context.Context, id any, message *mcp.SubscribeRequest, result *mcp.EmptyResult)
+
+type OnBeforeUnsubscribeFunc func(ctx context.Context, id any, message *mcp.UnsubscribeRequest)
+type OnAfterUnsubscribeFunc func(ctx context.Context, id any, message *mcp.UnsubscribeRequest, result *mcp.EmptyResult)
+
 type OnBeforeListPromptsFunc func(ctx context.Context, id any, message *mcp.ListPromptsRequest)
 type OnAfterListPromptsFunc func(ctx context.Context, id any, message *mcp.ListPromptsRequest, result *mcp.ListPromptsResult)
 
@@ -91,6 +122,12 @@ type OnAfterListToolsFunc func(ctx context.Context, id any, message *mcp.ListToo
 type OnBeforeCallToolFunc func(ctx context.Context, id any, message *mcp.CallToolRequest)
 type OnAfterCallToolFunc func(ctx context.Context, id any, message *mcp.CallToolRequest, result *mcp.CallToolResult)
 
+type OnBeforeValidateToolFunc func(ctx context.Context, id any, message *mcp.ValidateToolRequest)
+type OnAfterValidateToolFunc func(ctx context.Context, id any, message *mcp.ValidateToolRequest, result *mcp.ValidateToolResult)
+
+type OnBeforeCompleteFunc func(ctx context.Context, id any, message *mcp.CompleteRequest)
+type OnAfterCompleteFunc func(ctx context.Context, id any, message *mcp.CompleteRequest, result *mcp.CompleteResult)
+
 type Hooks struct {
 	OnRegisterSession             []OnRegisterSessionHookFunc
 	OnUnregisterSession           []OnUnregisterSessionHookFunc
@@ -98,6 +135,10 @@ type Hooks struct {
 	OnSuccess                     []OnSuccessHookFunc
 	OnError                       []OnErrorHookFunc
 	OnRequestInitialization       []OnRequestInitializationFunc
+	OnPanic                       []OnPanicHookFunc
+	OnDeprecatedToolCall          []OnDeprecatedToolCallHookFunc
+	OnLimitExceeded               []OnLimitExceededHookFunc
+	OnInitialize                  []OnInitializeHookFunc
 	OnBeforeInitialize            []OnBeforeInitializeFunc
 	OnAfterInitialize             []OnAfterInitializeFunc
 	OnBeforePing                  []OnBeforePingFunc
@@ -110,6 +151,10 @@ type Hooks struct {
 	OnAfterListResourceTemplates  []OnAfterListResourceTemplatesFunc
 	OnBeforeReadResource          []OnBeforeReadResourceFunc
 	OnAfterReadResource           []OnAfterReadResourceFunc
+	OnBeforeSubscribe             []OnBeforeSubscribeFunc
+	OnAfterSubscribe              []OnAfterSubscribeFunc
+	OnBeforeUnsubscribe           []OnBeforeUnsubscribeFunc
+	OnAfterUnsubscribe            []OnAfterUnsubscribeFunc
 	OnBeforeListPrompts           []OnBeforeListPromptsFunc
 	OnAfterListPrompts            []OnAfterListPromptsFunc
 	OnBeforeGetPrompt             []OnBeforeGetPromptFunc
@@ -118,6 +163,10 @@ type Hooks struct {
 	OnAfterListTools              []OnAfterListToolsFunc
 	OnBeforeCallTool              []OnBeforeCallToolFunc
 	OnAfterCallTool               []OnAfterCallToolFunc
+	OnBeforeValidateTool          []OnBeforeValidateToolFunc
+	OnAfterValidateTool           []OnAfterValidateToolFunc
+	OnBeforeComplete              []OnBeforeCompleteFunc
+	OnAfterComplete               []OnAfterCompleteFunc
 }
 
 func (c *Hooks) AddBeforeAny(hook BeforeAnyHookFunc) {
@@ -260,6 +309,70 @@ func (c *Hooks) onRequestInitialization(ctx context.Context, id any, message any
 	}
 	return nil
 }
+
+// AddOnPanic registers a hook function that will be called when WithRecovery
+// recovers from a panic in a request handler.
+func (c *Hooks) AddOnPanic(hook OnPanicHookFunc) {
+	c.OnPanic = append(c.OnPanic, hook)
+}
+
+func (c *Hooks) onPanic(ctx context.Context, id any, method mcp.MCPMethod, message any, err *PanicError) {
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnPanic {
+		hook(ctx, id, method, message, err)
+	}
+}
+
+// AddOnDeprecatedToolCall registers a hook function that will be called when
+// a deprecated tool or alias is called.
+func (c *Hooks) AddOnDeprecatedToolCall(hook OnDeprecatedToolCallHookFunc) {
+	c.OnDeprecatedToolCall = append(c.OnDeprecatedToolCall, hook)
+}
+
+func (c *Hooks) onDeprecatedToolCall(ctx context.Context, id any, call DeprecatedToolCall) {
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnDeprecatedToolCall {
+		hook(ctx, id, call)
+	}
+}
+
+// AddOnLimitExceeded registers a hook function that will be called when a
+// message exceeds a limit set with WithLimits.
+func (c *Hooks) AddOnLimitExceeded(hook OnLimitExceededHookFunc) {
+	c.OnLimitExceeded = append(c.OnLimitExceeded, hook)
+}
+
+func (c *Hooks) onLimitExceeded(ctx context.Context, id any, method mcp.MCPMethod, err *LimitExceededError) {
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnLimitExceeded {
+		hook(ctx, id, method, err)
+	}
+}
+
+// AddOnInitialize registers a hook function that will be called when a
+// client initializes a session. Hooks run in the order they were added; the
+// first error rejects the client.
+func (c *Hooks) AddOnInitialize(hook OnInitializeHookFunc) {
+	c.OnInitialize = append(c.OnInitialize, hook)
+}
+
+func (c *Hooks) onInitialize(ctx context.Context, id any, handshake *InitializeHandshake) error {
+	if c == nil {
+		return nil
+	}
+	for _, hook := range c.OnInitialize {
+		if err := hook(ctx, id, handshake); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 func (c *Hooks) AddBeforeInitialize(hook OnBeforeInitializeFunc) {
 	c.OnBeforeInitialize = append(c.OnBeforeInitialize, hook)
 }
@@ -422,6 +535,60 @@ func (c *Hooks) afterReadResource(ctx context.Context, id any, message *mcp.Read
 		hook(ctx, id, message, result)
 	}
 }
+func (c *Hooks) AddBeforeSubscribe(hook OnBeforeSubscribeFunc) {
+	c.OnBeforeSubscribe = append(c.OnBeforeSubscribe, hook)
+}
+
+func (c *Hooks) AddAfterSubscribe(hook OnAfterSubscribeFunc) {
+	c.OnAfterSubscribe = append(c.OnAfterSubscribe, hook)
+}
+
+func (c *Hooks) beforeSubscribe(ctx context.Context, id any, message *mcp.SubscribeRequest) {
+	c.beforeAny(ctx, id, mcp.MethodResourcesSubscribe, message)
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnBeforeSubscribe {
+		hook(ctx, id, message)
+	}
+}
+
+func (c *Hooks) afterSubscribe(ctx context.Context, id any, message *mcp.SubscribeRequest, result *mcp.EmptyResult) {
+	c.onSuccess(ctx, id, mcp.MethodResourcesSubscribe, message, result)
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnAfterSubscribe {
+		hook(ctx, id, message, result)
+	}
+}
+func (c *Hooks) AddBeforeUnsubscribe(hook OnBeforeUnsubscribeFunc) {
+	c.OnBeforeUnsubscribe = append(c.OnBeforeUnsubscribe, hook)
+}
+
+func (c *Hooks) AddAfterUnsubscribe(hook OnAfterUnsubscribeFunc) {
+	c.OnAfterUnsubscribe = append(c.OnAfterUnsubscribe, hook)
+}
+
+func (c *Hooks) beforeUnsubscribe(ctx context.Context, id any, message *mcp.UnsubscribeRequest) {
+	c.beforeAny(ctx, id, mcp.MethodResourcesUnsubscribe, message)
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnBeforeUnsubscribe {
+		hook(ctx, id, message)
+	}
+}
+
+func (c *Hooks) afterUnsubscribe(ctx context.Context, id any, message *mcp.UnsubscribeRequest, result *mcp.EmptyResult) {
+	c.onSuccess(ctx, id, mcp.MethodResourcesUnsubscribe, message, result)
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnAfterUnsubscribe {
+		hook(ctx, id, message, result)
+	}
+}
 func (c *Hooks) AddBeforeListPrompts(hook OnBeforeListPromptsFunc) {
 	c.OnBeforeListPrompts = append(c.OnBeforeListPrompts, hook)
 }
@@ -530,3 +697,57 @@ func (c *Hooks) afterCallTool(ctx context.Context, id any, message *mcp.CallTool
 		hook(ctx, id, message, result)
 	}
 }
+func (c *Hooks) AddBeforeValidateTool(hook OnBeforeValidateToolFunc) {
+	c.OnBeforeValidateTool = append(c.OnBeforeValidateTool, hook)
+}
+
+func (c *Hooks) AddAfterValidateTool(hook OnAfterValidateToolFunc) {
+	c.OnAfterValidateTool = append(c.OnAfterValidateTool, hook)
+}
+
+func (c *Hooks) beforeValidateTool(ctx context.Context, id any, message *mcp.ValidateToolRequest) {
+	c.beforeAny(ctx, id, mcp.MethodToolsValidate, message)
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnBeforeValidateTool {
+		hook(ctx, id, message)
+	}
+}
+
+func (c *Hooks) afterValidateTool(ctx context.Context, id any, message *mcp.ValidateToolRequest, result *mcp.ValidateToolResult) {
+	c.onSuccess(ctx, id, mcp.MethodToolsValidate, message, result)
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnAfterValidateTool {
+		hook(ctx, id, message, result)
+	}
+}
+func (c *Hooks) AddBeforeComplete(hook OnBeforeCompleteFunc) {
+	c.OnBeforeComplete = append(c.OnBeforeComplete, hook)
+}
+
+func (c *Hooks) AddAfterComplete(hook OnAfterCompleteFunc) {
+	c.OnAfterComplete = append(c.OnAfterComplete, hook)
+}
+
+func (c *Hooks) beforeComplete(ctx context.Context, id any, message *mcp.CompleteRequest) {
+	c.beforeAny(ctx, id, mcp.MethodCompletionComplete, message)
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnBeforeComplete {
+		hook(ctx, id, message)
+	}
+}
+
+func (c *Hooks) afterComplete(ctx context.Context, id any, message *mcp.CompleteRequest, result *mcp.CompleteResult) {
+	c.onSuccess(ctx, id, mcp.MethodCompletionComplete, message, result)
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnAfterComplete {
+		hook(ctx, id, message, result)
+	}
+}
@@ -0,0 +1,381 @@
+// Package httptool exposes REST endpoints as MCP tools.
+//
+// An Endpoint describes an HTTP operation and its parameters. NewTool turns
+// it into a server.ServerTool whose input schema lists the parameters and
+// whose handler maps the call arguments to the path, query string, headers
+// and JSON body of a request:
+//
+//	tool, err := httptool.NewTool("get_user", httptool.Endpoint{
+//	    Method:      http.MethodGet,
+//	    URL:         "https://api.example.com/users/{id}",
+//	    Description: "Fetch a user",
+//	    Params: []httptool.Param{
+//	        {Name: "id", In: httptool.InPath, Type: "string", Required: true},
+//	        {Name: "fields", In: httptool.InQuery, Type: "string"},
+//	    },
+//	}, httptool.WithAuth(httptool.BearerToken(os.Getenv("API_TOKEN"))))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	s.AddTools(tool)
+//
+// FromOpenAPI builds the tools of every operation of an OpenAPI 3 document.
+//
+// JSON object responses are returned as structured content along with their
+// text, other responses as text. Responses with an error status are
+// reported as tool errors carrying the status and the response body.
+package httptool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Location is where a parameter goes in the request.
+type Location string
+
+const (
+	InPath   Location = "path"
+	InQuery  Location = "query"
+	InHeader Location = "header"
+	// InBody parameters are sent as the properties of a JSON object body.
+	InBody Location = "body"
+)
+
+// Param is a parameter of an endpoint, exposed as a tool argument.
+type Param struct {
+	Name        string
+	In          Location
+	Description string
+	// Type is the JSON schema type of the argument, string if empty. It is
+	// ignored if Schema is set.
+	Type string
+	// Schema optionally gives the full JSON schema of the argument.
+	Schema   map[string]any
+	Required bool
+}
+
+// Endpoint describes an HTTP operation.
+type Endpoint struct {
+	// Method defaults to GET.
+	Method string
+	// URL is the endpoint URL. Path parameters appear in it as {name}.
+	URL         string
+	Description string
+	Params      []Param
+	// Annotations optionally describe the behavior of the tool. Without
+	// them GET and HEAD endpoints are annotated read-only.
+	Annotations *mcp.ToolAnnotation
+}
+
+// Authenticator adds credentials to a request.
+type Authenticator func(req *http.Request) error
+
+// BearerToken authenticates requests with an Authorization bearer token.
+func BearerToken(token string) Authenticator {
+	return func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+}
+
+// APIKey authenticates requests with a key in the given header.
+func APIKey(header, key string) Authenticator {
+	return func(req *http.Request) error {
+		req.Header.Set(header, key)
+		return nil
+	}
+}
+
+// BasicAuth authenticates requests with HTTP basic authentication.
+func BasicAuth(username, password string) Authenticator {
+	return func(req *http.Request) error {
+		req.SetBasicAuth(username, password)
+		return nil
+	}
+}
+
+// Option configures the tools built by NewTool and FromOpenAPI.
+type Option func(*options)
+
+type options struct {
+	client          *http.Client
+	auth            Authenticator
+	headers         http.Header
+	baseURL         string
+	maxResponseSize int64
+	operations      map[string]bool
+}
+
+// WithHTTPClient sets the client sending the requests. It defaults to
+// http.DefaultClient.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+// WithAuth sets how requests are authenticated.
+func WithAuth(auth Authenticator) Option {
+	return func(o *options) {
+		o.auth = auth
+	}
+}
+
+// WithHeader adds a header sent with every request.
+func WithHeader(name, value string) Option {
+	return func(o *options) {
+		o.headers.Add(name, value)
+	}
+}
+
+// WithBaseURL sets the base URL of operations read by FromOpenAPI,
+// overriding the servers of the document.
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) {
+		o.baseURL = baseURL
+	}
+}
+
+// WithMaxResponseSize bounds the size of the response bodies returned to
+// the client, 1 MiB by default. Longer bodies are truncated and returned as
+// text ending with a note of the truncation.
+func WithMaxResponseSize(size int64) Option {
+	return func(o *options) {
+		o.maxResponseSize = size
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{client: http.DefaultClient, headers: make(http.Header), maxResponseSize: 1 << 20}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// NewTool builds a tool calling endpoint.
+func NewTool(name string, endpoint Endpoint, opts ...Option) (server.ServerTool, error) {
+	return newTool(name, endpoint, newOptions(opts))
+}
+
+func newTool(name string, endpoint Endpoint, o *options) (server.ServerTool, error) {
+	if endpoint.Method == "" {
+		endpoint.Method = http.MethodGet
+	}
+	endpoint.Method = strings.ToUpper(endpoint.Method)
+	if _, err := url.Parse(endpoint.URL); err != nil {
+		return server.ServerTool{}, fmt.Errorf("invalid URL of tool %q: %w", name, err)
+	}
+
+	properties := make(map[string]any, len(endpoint.Params))
+	var required []string
+	for _, param := range endpoint.Params {
+		switch param.In {
+		case InPath:
+			if !strings.Contains(endpoint.URL, "{"+param.Name+"}") {
+				return server.ServerTool{}, fmt.Errorf("path parameter %q of tool %q is not in URL %s", param.Name, name, endpoint.URL)
+			}
+		case InQuery, InHeader, InBody:
+		default:
+			return server.ServerTool{}, fmt.Errorf("parameter %q of tool %q has invalid location %q", param.Name, name, param.In)
+		}
+		if _, ok := properties[param.Name]; ok {
+			return server.ServerTool{}, fmt.Errorf("parameter %q of tool %q is defined more than once", param.Name, name)
+		}
+		properties[param.Name] = param.schema()
+		if param.Required || param.In == InPath {
+			required = append(required, param.Name)
+		}
+	}
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	rawSchema, err := json.Marshal(schema)
+	if err != nil {
+		return server.ServerTool{}, fmt.Errorf("invalid parameter schema of tool %q: %w", name, err)
+	}
+
+	tool := mcp.NewTool(name, mcp.WithDescription(endpoint.Description), mcp.WithRawInputSchema(rawSchema))
+	switch {
+	case endpoint.Annotations != nil:
+		tool.Annotations = *endpoint.Annotations
+	case endpoint.Method == http.MethodGet || endpoint.Method == http.MethodHead:
+		tool.Annotations.ReadOnlyHint = mcp.ToBoolPtr(true)
+	}
+	return server.ServerTool{Tool: tool, Handler: newHandler(endpoint, o)}, nil
+}
+
+func (p Param) schema() map[string]any {
+	if p.Schema != nil {
+		if p.Description == "" {
+			return p.Schema
+		}
+		schema := make(map[string]any, len(p.Schema)+1)
+		for key, value := range p.Schema {
+			schema[key] = value
+		}
+		schema["description"] = p.Description
+		return schema
+	}
+	schema := map[string]any{"type": p.Type}
+	if p.Type == "" {
+		schema["type"] = "string"
+	}
+	if p.Description != "" {
+		schema["description"] = p.Description
+	}
+	return schema
+}
+
+func newHandler(endpoint Endpoint, o *options) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		req, err := buildRequest(ctx, endpoint, request.GetArguments())
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		for name, values := range o.headers {
+			for _, value := range values {
+				req.Header.Add(name, value)
+			}
+		}
+		if o.auth != nil {
+			if err := o.auth(req); err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to authenticate request", err), nil
+			}
+		}
+
+		resp, err := o.client.Do(req)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("request failed", err), nil
+		}
+		defer resp.Body.Close()
+		return convertResponse(resp, o.maxResponseSize)
+	}
+}
+
+// buildRequest maps the call arguments to a request of endpoint.
+func buildRequest(ctx context.Context, endpoint Endpoint, args map[string]any) (*http.Request, error) {
+	target := endpoint.URL
+	query := url.Values{}
+	header := make(http.Header)
+	var body map[string]any
+	for _, param := range endpoint.Params {
+		value, ok := args[param.Name]
+		if !ok || value == nil {
+			if param.Required || param.In == InPath {
+				return nil, fmt.Errorf("missing required argument %q", param.Name)
+			}
+			continue
+		}
+		switch param.In {
+		case InPath:
+			segment := formatValue(value)
+			if segment == "." || segment == ".." {
+				// Escaping leaves dot segments as is, which would move
+				// the request to another path.
+				return nil, fmt.Errorf("argument %q must not be %q", param.Name, segment)
+			}
+			target = strings.ReplaceAll(target, "{"+param.Name+"}", url.PathEscape(segment))
+		case InQuery:
+			if values, ok := value.([]any); ok {
+				for _, v := range values {
+					query.Add(param.Name, formatValue(v))
+				}
+			} else {
+				query.Add(param.Name, formatValue(value))
+			}
+		case InHeader:
+			header.Set(param.Name, formatValue(value))
+		case InBody:
+			if body == nil {
+				body = make(map[string]any)
+			}
+			body[param.Name] = value
+		}
+	}
+
+	if len(query) > 0 {
+		separator := "?"
+		if strings.Contains(target, "?") {
+			separator = "&"
+		}
+		target += separator + query.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request body: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, endpoint.Method, target, reader)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	req.Header = header
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json, */*;q=0.8")
+	return req, nil
+}
+
+// formatValue formats an argument for a path, query or header parameter.
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case map[string]any, []any:
+		data, _ := json.Marshal(v)
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// convertResponse turns a response into a tool result. Bodies longer than
+// maxSize are cut and returned as text ending with a note of the
+// truncation.
+func convertResponse(resp *http.Response, maxSize int64) (*mcp.CallToolResult, error) {
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("failed to read response", err), nil
+	}
+	truncated := int64(len(data)) > maxSize
+	if truncated {
+		data = data[:maxSize]
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return mcp.NewToolResultErrorf("%s: %s", resp.Status, strings.TrimSpace(string(data))), nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !truncated && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")) {
+		var structured map[string]any
+		if err := json.Unmarshal(data, &structured); err == nil {
+			return mcp.NewToolResultStructured(structured, string(data)), nil
+		}
+	}
+	text := string(data)
+	if truncated {
+		text += fmt.Sprintf("\n[response truncated to %d bytes]", maxSize)
+	}
+	return mcp.NewToolResultText(text), nil
+}
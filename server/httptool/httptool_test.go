@@ -0,0 +1,174 @@
+package httptool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// recordedRequest is what the test upstream saw of a request.
+type recordedRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	Body   string
+}
+
+func newUpstream(t *testing.T) (*httptest.Server, *recordedRequest) {
+	t.Helper()
+	var recorded recordedRequest
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		recorded = recordedRequest{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery, Header: r.Header, Body: string(body)}
+		switch r.URL.Path {
+		case "/missing":
+			http.Error(w, "not found", http.StatusNotFound)
+		case "/text":
+			_, _ = w.Write([]byte("plain"))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"ok":true}`))
+		}
+	}))
+	t.Cleanup(upstream.Close)
+	return upstream, &recorded
+}
+
+func callTool(t *testing.T, tool server.ServerTool, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	request := mcp.CallToolRequest{}
+	request.Params.Name = tool.Tool.Name
+	request.Params.Arguments = args
+	result, err := tool.Handler(context.Background(), request)
+	require.NoError(t, err)
+	return result
+}
+
+func TestNewTool(t *testing.T) {
+	upstream, recorded := newUpstream(t)
+
+	tool, err := NewTool("update_user", Endpoint{
+		Method:      "put",
+		URL:         upstream.URL + "/users/{id}",
+		Description: "Update a user",
+		Params: []Param{
+			{Name: "id", In: InPath},
+			{Name: "notify", In: InQuery, Type: "boolean"},
+			{Name: "tags", In: InQuery, Type: "array"},
+			{Name: "X-Request-Id", In: InHeader},
+			{Name: "name", In: InBody, Required: true, Description: "New name"},
+			{Name: "age", In: InBody, Type: "integer"},
+		},
+	}, WithAuth(BearerToken("t0ken")), WithHeader("User-Agent", "mcp-test"), WithHTTPClient(upstream.Client()))
+	require.NoError(t, err)
+
+	schema, err := json.Marshal(tool.Tool.RawInputSchema)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"id": {"type": "string"},
+			"notify": {"type": "boolean"},
+			"tags": {"type": "array"},
+			"X-Request-Id": {"type": "string"},
+			"name": {"type": "string", "description": "New name"},
+			"age": {"type": "integer"}
+		},
+		"required": ["id", "name"]
+	}`, string(schema))
+
+	result := callTool(t, tool, map[string]any{
+		"id": "a/b", "notify": true, "tags": []any{"x", "y"}, "X-Request-Id": "r1", "name": "Ada", "age": 36.0,
+	})
+	assert.False(t, result.IsError)
+	assert.Equal(t, map[string]any{"ok": true}, result.StructuredContent)
+
+	assert.Equal(t, http.MethodPut, recorded.Method)
+	assert.Equal(t, "/users/a/b", recorded.Path)
+	assert.Equal(t, "notify=true&tags=x&tags=y", recorded.Query)
+	assert.Equal(t, "Bearer t0ken", recorded.Header.Get("Authorization"))
+	assert.Equal(t, "mcp-test", recorded.Header.Get("User-Agent"))
+	assert.Equal(t, "r1", recorded.Header.Get("X-Request-Id"))
+	assert.Equal(t, "application/json", recorded.Header.Get("Content-Type"))
+	assert.JSONEq(t, `{"name":"Ada","age":36}`, recorded.Body)
+
+	result = callTool(t, tool, map[string]any{"id": "1"})
+	assert.True(t, result.IsError)
+	assert.Equal(t, `missing required argument "name"`, result.Content[0].(mcp.TextContent).Text)
+
+	for _, id := range []string{".", ".."} {
+		result = callTool(t, tool, map[string]any{"id": id, "name": "Ada"})
+		assert.True(t, result.IsError)
+		assert.Equal(t, fmt.Sprintf(`argument "id" must not be %q`, id), result.Content[0].(mcp.TextContent).Text)
+	}
+}
+
+func TestNewTool_Responses(t *testing.T) {
+	upstream, _ := newUpstream(t)
+	tests := []struct {
+		name    string
+		path    string
+		opts    []Option
+		text    string
+		isError bool
+	}{
+		{name: "json", path: "/json", text: `{"ok":true}`},
+		{name: "text", path: "/text", text: "plain"},
+		{name: "error status", path: "/missing", text: "404 Not Found: not found", isError: true},
+		{name: "truncated json", path: "/json", opts: []Option{WithMaxResponseSize(5)}, text: "{\"ok\"\n[response truncated to 5 bytes]"},
+		{name: "truncated text", path: "/text", opts: []Option{WithMaxResponseSize(3)}, text: "pla\n[response truncated to 3 bytes]"},
+		{name: "text at the size limit", path: "/text", opts: []Option{WithMaxResponseSize(5)}, text: "plain"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool, err := NewTool("get", Endpoint{URL: upstream.URL + tt.path}, tt.opts...)
+			require.NoError(t, err)
+			assert.True(t, *tool.Tool.Annotations.ReadOnlyHint)
+			result := callTool(t, tool, nil)
+			assert.Equal(t, tt.isError, result.IsError)
+			assert.Equal(t, tt.text, result.Content[0].(mcp.TextContent).Text)
+		})
+	}
+}
+
+func TestNewTool_InvalidEndpoints(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint Endpoint
+		err      string
+	}{
+		{
+			name:     "path parameter not in URL",
+			endpoint: Endpoint{URL: "http://localhost/users", Params: []Param{{Name: "id", In: InPath}}},
+			err:      `path parameter "id" of tool "t" is not in URL`,
+		},
+		{
+			name:     "invalid location",
+			endpoint: Endpoint{URL: "http://localhost", Params: []Param{{Name: "id", In: "cookie"}}},
+			err:      `invalid location "cookie"`,
+		},
+		{
+			name:     "duplicate parameter",
+			endpoint: Endpoint{URL: "http://localhost", Params: []Param{{Name: "id", In: InQuery}, {Name: "id", In: InBody}}},
+			err:      `defined more than once`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTool("t", tt.endpoint)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package httptool
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// operationMethods are the operations of an OpenAPI path item, in the order
+// their tools are built.
+var operationMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// WithOperations restricts FromOpenAPI to the operations with the given
+// operation IDs.
+func WithOperations(operationIDs ...string) Option {
+	return func(o *options) {
+		if o.operations == nil {
+			o.operations = make(map[string]bool, len(operationIDs))
+		}
+		for _, id := range operationIDs {
+			o.operations[id] = true
+		}
+	}
+}
+
+// FromOpenAPI builds a tool for every operation of an OpenAPI 3 document,
+// given in JSON or YAML. Tools are named after the operation ID, or after
+// the method and path of operations without one, and described by the
+// operation summary and description. Path, query and header parameters
+// become arguments, as do the properties of JSON object request bodies.
+//
+// Requests are sent to the first server of the document unless WithBaseURL
+// is used. Local references ($ref to "#/...") are resolved; operations with
+// a request body that is not a JSON object are not supported.
+func FromOpenAPI(document []byte, opts ...Option) ([]server.ServerTool, error) {
+	o := newOptions(opts)
+	var doc map[string]any
+	if err := yaml.Unmarshal(document, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode OpenAPI document: %w", err)
+	}
+	if version, _ := doc["openapi"].(string); !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q", version)
+	}
+
+	baseURL := o.baseURL
+	if baseURL == "" {
+		if servers, _ := doc["servers"].([]any); len(servers) > 0 {
+			if first, ok := servers[0].(map[string]any); ok {
+				baseURL, _ = first["url"].(string)
+			}
+		}
+	}
+	if baseURL == "" {
+		return nil, fmt.Errorf("OpenAPI document has no servers, use WithBaseURL")
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	paths, _ := doc["paths"].(map[string]any)
+	pathNames := make([]string, 0, len(paths))
+	for path := range paths {
+		pathNames = append(pathNames, path)
+	}
+	sort.Strings(pathNames)
+
+	resolver := refResolver{doc: doc}
+	var tools []server.ServerTool
+	for _, path := range pathNames {
+		item, ok := resolver.object(paths[path])
+		if !ok {
+			continue
+		}
+		for _, method := range operationMethods {
+			operation, ok := resolver.object(item[method])
+			if !ok {
+				continue
+			}
+			id, _ := operation["operationId"].(string)
+			if o.operations != nil && !o.operations[id] {
+				continue
+			}
+			name := id
+			if name == "" {
+				name = method + "_" + strings.Trim(invalidNameChars.ReplaceAllString(path, "_"), "_")
+			}
+			name = invalidNameChars.ReplaceAllString(name, "_")
+
+			endpoint, err := resolver.endpoint(baseURL+path, strings.ToUpper(method), item, operation)
+			if err != nil {
+				return nil, fmt.Errorf("operation %s %s: %w", strings.ToUpper(method), path, err)
+			}
+			tool, err := newTool(name, endpoint, o)
+			if err != nil {
+				return nil, err
+			}
+			tools = append(tools, tool)
+		}
+	}
+	return tools, nil
+}
+
+// refResolver resolves the local references of an OpenAPI document.
+type refResolver struct {
+	doc map[string]any
+}
+
+// maxRefDepth bounds the nesting of inlined schema references, which also
+// stops recursive schemas.
+const maxRefDepth = 16
+
+// resolve follows value if it is a reference.
+func (r refResolver) resolve(value any) (any, error) {
+	for range maxRefDepth {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return value, nil
+		}
+		ref, ok := object["$ref"].(string)
+		if !ok {
+			return value, nil
+		}
+		if !strings.HasPrefix(ref, "#/") {
+			return nil, fmt.Errorf("unsupported reference %q", ref)
+		}
+		var target any = r.doc
+		for _, token := range strings.Split(ref[2:], "/") {
+			token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+			parent, ok := target.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("unresolvable reference %q", ref)
+			}
+			if target, ok = parent[token]; !ok {
+				return nil, fmt.Errorf("unresolvable reference %q", ref)
+			}
+		}
+		value = target
+	}
+	return nil, fmt.Errorf("references nested too deeply")
+}
+
+func (r refResolver) object(value any) (map[string]any, bool) {
+	resolved, err := r.resolve(value)
+	if err != nil {
+		return nil, false
+	}
+	object, ok := resolved.(map[string]any)
+	return object, ok
+}
+
+// inline returns a copy of schema with its references replaced by their
+// targets, so that it stands on its own as a tool argument schema.
+func (r refResolver) inline(schema any, depth int) (any, error) {
+	if depth > maxRefDepth {
+		return nil, fmt.Errorf("schema references nested too deeply")
+	}
+	resolved, err := r.resolve(schema)
+	if err != nil {
+		return nil, err
+	}
+	switch v := resolved.(type) {
+	case map[string]any:
+		inlined := make(map[string]any, len(v))
+		for key, value := range v {
+			if inlined[key], err = r.inline(value, depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return inlined, nil
+	case []any:
+		inlined := make([]any, len(v))
+		for i, value := range v {
+			if inlined[i], err = r.inline(value, depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return inlined, nil
+	default:
+		return v, nil
+	}
+}
+
+// endpoint builds the endpoint of an operation. Parameters of the path
+// item apply unless the operation overrides them.
+func (r refResolver) endpoint(url, method string, item, operation map[string]any) (Endpoint, error) {
+	endpoint := Endpoint{Method: method, URL: url}
+	summary, _ := operation["summary"].(string)
+	description, _ := operation["description"].(string)
+	endpoint.Description = strings.TrimSpace(strings.Join([]string{summary, description}, "\n\n"))
+
+	type key struct{ name, in string }
+	params := make(map[key]Param)
+	var order []key
+	for _, list := range []any{item["parameters"], operation["parameters"]} {
+		entries, _ := list.([]any)
+		for _, entry := range entries {
+			param, ok := r.object(entry)
+			if !ok {
+				return Endpoint{}, fmt.Errorf("invalid parameter")
+			}
+			name, _ := param["name"].(string)
+			in, _ := param["in"].(string)
+			if in == "cookie" {
+				continue
+			}
+			p := Param{Name: name, In: Location(in)}
+			p.Description, _ = param["description"].(string)
+			p.Required, _ = param["required"].(bool)
+			if schema, ok := param["schema"]; ok {
+				inlined, err := r.inline(schema, 0)
+				if err != nil {
+					return Endpoint{}, err
+				}
+				p.Schema, _ = inlined.(map[string]any)
+			}
+			k := key{name, in}
+			if _, ok := params[k]; !ok {
+				order = append(order, k)
+			}
+			params[k] = p
+		}
+	}
+	for _, k := range order {
+		endpoint.Params = append(endpoint.Params, params[k])
+	}
+
+	requestBody, ok := r.object(operation["requestBody"])
+	if !ok {
+		return endpoint, nil
+	}
+	content, _ := requestBody["content"].(map[string]any)
+	media, ok := content["application/json"].(map[string]any)
+	if !ok {
+		return Endpoint{}, fmt.Errorf("unsupported request body, only application/json is")
+	}
+	inlined, err := r.inline(media["schema"], 0)
+	if err != nil {
+		return Endpoint{}, err
+	}
+	schema, _ := inlined.(map[string]any)
+	properties, ok := schema["properties"].(map[string]any)
+	if !ok {
+		return Endpoint{}, fmt.Errorf("unsupported request body, only JSON objects are")
+	}
+	required := make(map[string]bool)
+	if list, ok := schema["required"].([]any); ok {
+		for _, name := range list {
+			if name, ok := name.(string); ok {
+				required[name] = true
+			}
+		}
+	}
+	bodyRequired, _ := requestBody["required"].(bool)
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		propertySchema, _ := properties[name].(map[string]any)
+		endpoint.Params = append(endpoint.Params, Param{
+			Name:     name,
+			In:       InBody,
+			Schema:   propertySchema,
+			Required: bodyRequired && required[name],
+		})
+	}
+	return endpoint, nil
+}
@@ -0,0 +1,106 @@
+package httptool
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const petstore = `
+openapi: 3.0.3
+info: {title: Petstore, version: 1.0.0}
+servers:
+  - url: https://petstore.example.com/v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List pets
+      parameters:
+        - {name: limit, in: query, schema: {type: integer, maximum: 100}}
+      responses:
+        200: {description: A list of pets}
+    post:
+      operationId: createPet
+      summary: Create a pet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/NewPet"}
+      responses:
+        201: {description: Created}
+  /pets/{petId}:
+    parameters:
+      - $ref: "#/components/parameters/PetId"
+    delete:
+      responses:
+        204: {description: Deleted}
+components:
+  parameters:
+    PetId: {name: petId, in: path, required: true, schema: {type: string}}
+  schemas:
+    NewPet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        owner: {$ref: "#/components/schemas/Owner"}
+    Owner:
+      type: object
+      properties:
+        email: {type: string}
+`
+
+func TestFromOpenAPI(t *testing.T) {
+	upstream, recorded := newUpstream(t)
+	tools, err := FromOpenAPI([]byte(petstore), WithBaseURL(upstream.URL), WithHTTPClient(upstream.Client()))
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.Tool.Name)
+	}
+	assert.Equal(t, []string{"listPets", "createPet", "delete_pets_petId"}, names)
+
+	assert.Equal(t, "List pets", tools[0].Tool.Description)
+	schema, err := json.Marshal(tools[1].Tool.RawInputSchema)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"owner": {"type": "object", "properties": {"email": {"type": "string"}}}
+		},
+		"required": ["name"]
+	}`, string(schema))
+
+	result := callTool(t, tools[0], map[string]any{"limit": 10.0})
+	assert.False(t, result.IsError)
+	assert.Equal(t, "/pets", recorded.Path)
+	assert.Equal(t, "limit=10", recorded.Query)
+
+	callTool(t, tools[1], map[string]any{"name": "Rex", "owner": map[string]any{"email": "a@example.com"}})
+	assert.Equal(t, http.MethodPost, recorded.Method)
+	assert.JSONEq(t, `{"name":"Rex","owner":{"email":"a@example.com"}}`, recorded.Body)
+
+	callTool(t, tools[2], map[string]any{"petId": "42"})
+	assert.Equal(t, http.MethodDelete, recorded.Method)
+	assert.Equal(t, "/pets/42", recorded.Path)
+}
+
+func TestFromOpenAPI_Options(t *testing.T) {
+	tools, err := FromOpenAPI([]byte(petstore), WithOperations("listPets"))
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	assert.Equal(t, "listPets", tools[0].Tool.Name)
+
+	_, err = FromOpenAPI([]byte(`{"openapi": "3.1.0", "paths": {}}`))
+	assert.ErrorContains(t, err, "no servers")
+
+	_, err = FromOpenAPI([]byte(`{"swagger": "2.0"}`))
+	assert.ErrorContains(t, err, "unsupported OpenAPI version")
+}
@@ -0,0 +1,142 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// IdempotencyKeyMeta is the _meta field of a tools/call request carrying its
+// idempotency key with WithIdempotency.
+const IdempotencyKeyMeta = "idempotencyKey"
+
+// WithIdempotency deduplicates tool calls carrying an idempotency key in
+// the IdempotencyKeyMeta field of their _meta. The result of a completed
+// call is kept for window, and a call of the same tool with the same key
+// within it, typically a retry after the client reconnected, gets that
+// result without the tool being run again. A retry arriving while the first
+// call is still running waits for it.
+//
+// Keys are scoped by tenant, by tool and by the authenticated subject, but
+// not by session, so that retries from a new session are recognized. Calls
+// without an authenticated subject are scoped by session instead, so that
+// unauthenticated clients never share results. Reusing a
+// key with different arguments fails with an INVALID_PARAMS error matching
+// ErrIdempotencyKeyReused. Calls failing with an error are not kept, so
+// their retries run the tool again.
+func WithIdempotency(window time.Duration) ServerOption {
+	return func(s *MCPServer) {
+		s.idempotency = &idempotencyCache{
+			window:  window,
+			entries: make(map[idempotencyKey]*idempotentCall),
+		}
+	}
+}
+
+type idempotencyKey struct {
+	tenant, subject, session, tool, key string
+}
+
+// idempotentCall is a tool call whose result is kept by the cache. Done is
+// closed once the call completes.
+type idempotentCall struct {
+	arguments string
+	done      chan struct{}
+	result    *mcp.CallToolResult
+	err       error
+	expires   time.Time
+}
+
+// expired reports whether the call completed more than the window ago.
+func (e *idempotentCall) expired(now time.Time) bool {
+	return !e.expires.IsZero() && e.expires.Before(now)
+}
+
+// idempotencyCache keeps the results of tool calls by idempotency key.
+type idempotencyCache struct {
+	window time.Duration
+
+	mu        sync.Mutex
+	entries   map[idempotencyKey]*idempotentCall
+	lastSweep time.Time
+}
+
+// call runs handler for request unless a call with the same idempotency key
+// already completed or is in flight, in which case its result is returned.
+func (c *idempotencyCache) call(ctx context.Context, request mcp.CallToolRequest, handler ToolHandlerFunc) (*mcp.CallToolResult, error) {
+	if c == nil || request.Params.Meta == nil {
+		return handler(ctx, request)
+	}
+	idempotency, _ := request.Params.Meta.AdditionalFields[IdempotencyKeyMeta].(string)
+	if idempotency == "" {
+		return handler(ctx, request)
+	}
+	key := idempotencyKey{tool: request.Params.Name, key: idempotency}
+	key.tenant, _ = TenantFromContext(ctx)
+	if info, ok := AuthInfoFromContext(ctx); ok && info.Subject != "" {
+		key.subject = info.Subject
+	} else if session := ClientSessionFromContext(ctx); session != nil {
+		key.session = session.SessionID()
+	}
+	arguments, err := json.Marshal(request.Params.Arguments)
+	if err != nil {
+		return handler(ctx, request)
+	}
+
+	for {
+		c.mu.Lock()
+		now := time.Now()
+		c.sweep(now)
+		existing, ok := c.entries[key]
+		if !ok || existing.expired(now) {
+			break
+		}
+		c.mu.Unlock()
+
+		if existing.arguments != string(arguments) {
+			return nil, fmt.Errorf("%w: %q", ErrIdempotencyKeyReused, idempotency)
+		}
+		select {
+		case <-existing.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if existing.err == nil {
+			return existing.result, nil
+		}
+		// The call failed and was forgotten; try again, possibly running the
+		// tool from this call.
+	}
+
+	entry := &idempotentCall{arguments: string(arguments), done: make(chan struct{})}
+	c.entries[key] = entry
+	c.mu.Unlock()
+
+	entry.result, entry.err = handler(ctx, request)
+	c.mu.Lock()
+	if entry.err != nil {
+		delete(c.entries, key)
+	} else {
+		entry.expires = time.Now().Add(c.window)
+	}
+	c.mu.Unlock()
+	close(entry.done)
+	return entry.result, entry.err
+}
+
+// sweep drops the expired entries, at most once per window.
+func (c *idempotencyCache) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < c.window {
+		return
+	}
+	c.lastSweep = now
+	for key, entry := range c.entries {
+		if entry.expired(now) {
+			delete(c.entries, key)
+		}
+	}
+}
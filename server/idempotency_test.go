@@ -0,0 +1,186 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestMCPServer_WithIdempotency(t *testing.T) {
+	tests := []struct {
+		name     string
+		first    string
+		retry    string
+		ctx      func(subject string) context.Context
+		subjects [2]string
+		wait     time.Duration
+		calls    int32
+		errCode  int
+	}{
+		{
+			name:  "retry returns the cached result",
+			first: `{"name":"charge","arguments":{"amount":5},"_meta":{"idempotencyKey":"k1"}}`,
+			retry: `{"name":"charge","arguments":{"amount":5},"_meta":{"idempotencyKey":"k1"}}`,
+			calls: 1,
+		},
+		{
+			name:  "different keys both run",
+			first: `{"name":"charge","arguments":{"amount":5},"_meta":{"idempotencyKey":"k1"}}`,
+			retry: `{"name":"charge","arguments":{"amount":5},"_meta":{"idempotencyKey":"k2"}}`,
+			calls: 2,
+		},
+		{
+			name:  "calls without a key always run",
+			first: `{"name":"charge","arguments":{"amount":5}}`,
+			retry: `{"name":"charge","arguments":{"amount":5}}`,
+			calls: 2,
+		},
+		{
+			name:  "expired results are forgotten",
+			first: `{"name":"charge","arguments":{"amount":5},"_meta":{"idempotencyKey":"k1"}}`,
+			retry: `{"name":"charge","arguments":{"amount":5},"_meta":{"idempotencyKey":"k1"}}`,
+			wait:  80 * time.Millisecond,
+			calls: 2,
+		},
+		{
+			name:     "keys are scoped by subject",
+			first:    `{"name":"charge","arguments":{"amount":5},"_meta":{"idempotencyKey":"k1"}}`,
+			retry:    `{"name":"charge","arguments":{"amount":5},"_meta":{"idempotencyKey":"k1"}}`,
+			subjects: [2]string{"alice", "bob"},
+			calls:    2,
+		},
+		{
+			name:    "reused key with different arguments",
+			first:   `{"name":"charge","arguments":{"amount":5},"_meta":{"idempotencyKey":"k1"}}`,
+			retry:   `{"name":"charge","arguments":{"amount":6},"_meta":{"idempotencyKey":"k1"}}`,
+			calls:   1,
+			errCode: mcp.INVALID_PARAMS,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMCPServer("test-server", "1.0.0", WithIdempotency(50*time.Millisecond))
+			var calls atomic.Int32
+			server.AddTool(mcp.NewTool("charge"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				n := calls.Add(1)
+				return mcp.NewToolResultText(fmt.Sprintf("charge %d", n)), nil
+			})
+
+			call := func(subject, params string) mcp.JSONRPCMessage {
+				ctx := context.Background()
+				if subject != "" {
+					ctx = WithAuthInfo(ctx, AuthInfo{Subject: subject})
+				}
+				return server.HandleMessage(ctx, json.RawMessage(
+					`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":`+params+`}`))
+			}
+
+			first := call(tt.subjects[0], tt.first)
+			require.IsType(t, mcp.JSONRPCResponse{}, first)
+			time.Sleep(tt.wait)
+			retry := call(tt.subjects[1], tt.retry)
+
+			assert.Equal(t, tt.calls, calls.Load())
+			if tt.errCode != 0 {
+				errResp, ok := retry.(mcp.JSONRPCError)
+				require.True(t, ok, "unexpected response %#v", retry)
+				assert.Equal(t, tt.errCode, errResp.Error.Code)
+				return
+			}
+			require.IsType(t, mcp.JSONRPCResponse{}, retry)
+			text := func(message mcp.JSONRPCMessage) string {
+				return message.(mcp.JSONRPCResponse).Result.(mcp.CallToolResult).Content[0].(mcp.TextContent).Text
+			}
+			assert.Equal(t, fmt.Sprintf("charge %d", tt.calls), text(retry))
+		})
+	}
+}
+
+func TestMCPServer_WithIdempotency_Concurrent(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithIdempotency(time.Minute))
+	var calls atomic.Int32
+	release := make(chan struct{})
+	server.AddTool(mcp.NewTool("charge"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if calls.Add(1) == 1 {
+			<-release
+			return nil, fmt.Errorf("card declined")
+		}
+		return mcp.NewToolResultText("charged"), nil
+	})
+
+	request := json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"charge","_meta":{"idempotencyKey":"k1"}}}`)
+	responses := make([]mcp.JSONRPCMessage, 3)
+	var wg sync.WaitGroup
+	for i := range responses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			responses[i] = server.HandleMessage(context.Background(), request)
+		}()
+		if i == 0 {
+			require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, int32(1), calls.Load(), "duplicates wait for the call in flight")
+	close(release)
+	wg.Wait()
+
+	assert.IsType(t, mcp.JSONRPCError{}, responses[0], "errors are returned to the original caller")
+	assert.Equal(t, int32(2), calls.Load(), "a failed call is run once more for its duplicates")
+	for _, response := range responses[1:] {
+		require.IsType(t, mcp.JSONRPCResponse{}, response)
+	}
+}
+
+func TestMCPServer_WithIdempotency_Tenants(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0",
+		WithIdempotency(time.Minute),
+		WithTenancy(TenantFromHeader("X-Tenant")),
+	)
+	var calls atomic.Int32
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		tenant, _ := TenantFromContext(ctx)
+		return mcp.NewToolResultText(fmt.Sprintf("%s %d", tenant, calls.Add(1))), nil
+	}
+	server.AddTenantTools("acme", ServerTool{Tool: mcp.NewTool("charge"), Handler: handler})
+	server.AddTenantTools("globex", ServerTool{Tool: mcp.NewTool("charge"), Handler: handler})
+
+	newSession := func(id string) ClientSession {
+		session := &sessionTestClient{sessionID: id, initialized: true, notificationChannel: make(chan mcp.JSONRPCNotification, 10)}
+		require.NoError(t, server.RegisterSession(context.Background(), session))
+		return session
+	}
+	call := func(ctx context.Context) string {
+		t.Helper()
+		response := server.HandleMessage(ctx, json.RawMessage(
+			`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"charge","_meta":{"idempotencyKey":"k1"}}}`))
+		require.IsType(t, mcp.JSONRPCResponse{}, response)
+		return response.(mcp.JSONRPCResponse).Result.(mcp.CallToolResult).Content[0].(mcp.TextContent).Text
+	}
+
+	acme, globex := newSession("acme-1"), newSession("globex-1")
+	assert.Equal(t, "acme 1", call(tenantRequest(server, acme, "acme")))
+	assert.Equal(t, "globex 2", call(tenantRequest(server, globex, "globex")), "tenants do not share results")
+	assert.Equal(t, "acme 1", call(tenantRequest(server, acme, "acme")), "retries of a session get its result")
+
+	other := newSession("acme-2")
+	assert.Equal(t, "acme 3", call(tenantRequest(server, other, "acme")), "unauthenticated sessions do not share results")
+
+	authenticated := func(session ClientSession, tenant string) context.Context {
+		return WithAuthInfo(tenantRequest(server, session, tenant), AuthInfo{Subject: "alice"})
+	}
+	assert.Equal(t, "acme 4", call(authenticated(newSession("acme-3"), "acme")))
+	assert.Equal(t, "acme 4", call(authenticated(newSession("acme-4"), "acme")), "subjects share results across sessions")
+	assert.Equal(t, "globex 5", call(authenticated(newSession("globex-2"), "globex")), "subjects do not share results across tenants")
+}
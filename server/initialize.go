@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// InitializeHandshake is the initialize exchange of a client, passed to the
+// OnInitialize hooks before the server answers.
+type InitializeHandshake struct {
+	// Request is the initialize request of the client. Its client info and
+	// capabilities are recorded in the session after the hooks ran, so
+	// removing a client capability keeps the server from using it.
+	Request *mcp.InitializeRequest
+	// Result is the answer of the server. Hooks may downgrade its protocol
+	// version or remove server capabilities.
+	Result *mcp.InitializeResult
+	// Session is the session being initialized, nil for transports without
+	// sessions.
+	Session ClientSession
+}
+
+// ClientInfo returns the name and version the client reported.
+func (h *InitializeHandshake) ClientInfo() mcp.Implementation {
+	return h.Request.Params.ClientInfo
+}
+
+// ClientCapabilities returns the capabilities the client reported.
+func (h *InitializeHandshake) ClientCapabilities() mcp.ClientCapabilities {
+	return h.Request.Params.Capabilities
+}
+
+// runInitializeHooks passes the handshake to the OnInitialize hooks and
+// turns a rejection into the error sent to the client.
+func (s *MCPServer) runInitializeHooks(ctx context.Context, id any, handshake *InitializeHandshake) *requestError {
+	err := s.hooks.onInitialize(ctx, id, handshake)
+	if err == nil {
+		return nil
+	}
+	code := mcp.INVALID_REQUEST
+	var mcpErr *mcp.Error
+	if errors.As(err, &mcpErr) {
+		code = mcpErr.Code
+	} else if mapped, ok := mcp.ChainErrorCodeMappers(s.errorCodeMapper, mcp.CodeForError)(err); ok {
+		code = mapped
+	}
+	return &requestError{id: id, code: code, err: err}
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func initializeMessage(clientVersion string) json.RawMessage {
+	return json.RawMessage(fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 1,
+		"method": "initialize",
+		"params": {
+			"protocolVersion": %q,
+			"clientInfo": {"name": "test-client", "version": %q},
+			"capabilities": {"sampling": {}, "roots": {"listChanged": true}}
+		}
+	}`, mcp.LATEST_PROTOCOL_VERSION, clientVersion))
+}
+
+func TestHooks_OnInitialize(t *testing.T) {
+	errTooOld := errors.New("client version 0.9 is no longer supported")
+
+	tests := []struct {
+		name          string
+		clientVersion string
+		hook          OnInitializeHookFunc
+		wantCode      int
+		wantMessage   string
+		check         func(t *testing.T, session *InProcessSession, result mcp.InitializeResult)
+	}{
+		{
+			name:          "accept",
+			clientVersion: "1.0",
+			hook: func(ctx context.Context, id any, handshake *InitializeHandshake) error {
+				assert.Equal(t, mcp.Implementation{Name: "test-client", Version: "1.0"}, handshake.ClientInfo())
+				assert.NotNil(t, handshake.ClientCapabilities().Sampling)
+				assert.NotNil(t, handshake.Session)
+				return nil
+			},
+			check: func(t *testing.T, session *InProcessSession, result mcp.InitializeResult) {
+				assert.True(t, session.Initialized())
+				assert.Equal(t, "1.0", session.GetClientInfo().Version)
+				assert.NotNil(t, result.Capabilities.Logging)
+			},
+		},
+		{
+			name:          "reject",
+			clientVersion: "0.9",
+			hook: func(ctx context.Context, id any, handshake *InitializeHandshake) error {
+				if handshake.ClientInfo().Version == "0.9" {
+					return errTooOld
+				}
+				return nil
+			},
+			wantCode:    mcp.INVALID_REQUEST,
+			wantMessage: errTooOld.Error(),
+			check: func(t *testing.T, session *InProcessSession, _ mcp.InitializeResult) {
+				assert.False(t, session.Initialized())
+				assert.Empty(t, session.GetClientInfo().Name)
+			},
+		},
+		{
+			name:          "reject with code",
+			clientVersion: "0.9",
+			hook: func(ctx context.Context, id any, handshake *InitializeHandshake) error {
+				return mcp.NewError(-32001, "upgrade required", map[string]string{"minVersion": "1.0"})
+			},
+			wantCode:    -32001,
+			wantMessage: "upgrade required",
+		},
+		{
+			name:          "downgrade capabilities",
+			clientVersion: "1.0",
+			hook: func(ctx context.Context, id any, handshake *InitializeHandshake) error {
+				handshake.Request.Params.Capabilities.Sampling = nil
+				handshake.Result.Capabilities.Logging = nil
+				return nil
+			},
+			check: func(t *testing.T, session *InProcessSession, result mcp.InitializeResult) {
+				assert.Nil(t, session.GetClientCapabilities().Sampling)
+				assert.NotNil(t, session.GetClientCapabilities().Roots)
+				assert.Nil(t, result.Capabilities.Logging)
+			},
+		},
+		{
+			name:          "tag session",
+			clientVersion: "1.0",
+			hook: func(ctx context.Context, id any, handshake *InitializeHandshake) error {
+				SetSessionValue(ctx, "tier", "beta")
+				return nil
+			},
+			check: func(t *testing.T, session *InProcessSession, _ mcp.InitializeResult) {
+				tier, ok := session.Get("tier")
+				assert.True(t, ok)
+				assert.Equal(t, "beta", tier)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hooks := &Hooks{}
+			hooks.AddOnInitialize(tt.hook)
+			s := NewMCPServer("test", "1.0.0", WithHooks(hooks), WithLogging())
+			session := NewInProcessSession("session", nil)
+			ctx := s.WithContext(context.Background(), session)
+
+			var result mcp.InitializeResult
+			switch response := s.HandleMessage(ctx, initializeMessage(tt.clientVersion)).(type) {
+			case mcp.JSONRPCError:
+				require.NotZero(t, tt.wantCode, "unexpected error: %v", response.Error.Message)
+				assert.Equal(t, tt.wantCode, response.Error.Code)
+				assert.Equal(t, tt.wantMessage, response.Error.Message)
+			case mcp.JSONRPCResponse:
+				require.Zero(t, tt.wantCode, "expected an error")
+				var ok bool
+				result, ok = response.Result.(mcp.InitializeResult)
+				require.True(t, ok)
+			default:
+				t.Fatalf("unexpected response %T", response)
+			}
+			if tt.check != nil {
+				tt.check(t, session, result)
+			}
+		})
+	}
+}
+
+func TestHooks_OnInitialize_FirstRejectionWins(t *testing.T) {
+	var calls []string
+	hooks := &Hooks{}
+	hooks.AddOnInitialize(func(ctx context.Context, id any, handshake *InitializeHandshake) error {
+		calls = append(calls, "first")
+		return errors.New("rejected")
+	})
+	hooks.AddOnInitialize(func(ctx context.Context, id any, handshake *InitializeHandshake) error {
+		calls = append(calls, "second")
+		return nil
+	})
+	s := NewMCPServer("test", "1.0.0", WithHooks(hooks))
+
+	response := s.HandleMessage(context.Background(), initializeMessage("1.0"))
+	_, ok := response.(mcp.JSONRPCError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"first"}, calls)
+}
@@ -32,9 +32,11 @@ type InProcessSession struct {
 	loggingLevel       atomic.Value
 	clientInfo         atomic.Value
 	clientCapabilities atomic.Value
+	protocolVersion    atomic.Value
 	samplingHandler    SamplingHandler
 	elicitationHandler ElicitationHandler
 	rootsHandler       RootsHandler
+	values             sync.Map
 	mu                 sync.RWMutex
 }
 
@@ -99,6 +101,37 @@ func (s *InProcessSession) SetClientCapabilities(clientCapabilities mcp.ClientCa
 	s.clientCapabilities.Store(clientCapabilities)
 }
 
+func (s *InProcessSession) ClientInfo() mcp.Implementation {
+	return s.GetClientInfo()
+}
+
+func (s *InProcessSession) ClientCapabilities() mcp.ClientCapabilities {
+	return s.GetClientCapabilities()
+}
+
+func (s *InProcessSession) NegotiatedProtocolVersion() string {
+	if version, ok := s.protocolVersion.Load().(string); ok {
+		return version
+	}
+	return ""
+}
+
+func (s *InProcessSession) SetNegotiatedProtocolVersion(version string) {
+	s.protocolVersion.Store(version)
+}
+
+func (s *InProcessSession) Set(key string, value any) {
+	s.values.Store(key, value)
+}
+
+func (s *InProcessSession) Get(key string) (any, bool) {
+	return s.values.Load(key)
+}
+
+func (s *InProcessSession) Delete(key string) {
+	s.values.Delete(key)
+}
+
 func (s *InProcessSession) SetLogLevel(level mcp.LoggingLevel) {
 	s.loggingLevel.Store(level)
 }
@@ -162,4 +195,6 @@ var (
 	_ SessionWithSampling    = (*InProcessSession)(nil)
 	_ SessionWithElicitation = (*InProcessSession)(nil)
 	_ SessionWithRoots       = (*InProcessSession)(nil)
+	_ SessionWithValues      = (*InProcessSession)(nil)
+	_ SessionWithHandshake   = (*InProcessSession)(nil)
 )
@@ -0,0 +1,119 @@
+// Package argtemplate expands text/template templates with the arguments of
+// tool calls, for the packages turning commands and endpoints into tools.
+package argtemplate
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// Funcs are the functions available to every template: json encodes a
+// value.
+var Funcs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// Parse parses texts in order, as templates of kind reported in errors.
+// funcs are made available to the templates in addition to Funcs.
+func Parse(kind string, texts []string, funcs template.FuncMap) ([]*template.Template, error) {
+	all := Funcs
+	if len(funcs) > 0 {
+		all = maps.Clone(Funcs)
+		maps.Copy(all, funcs)
+	}
+	templates := make([]*template.Template, len(texts))
+	for i, text := range texts {
+		tmpl, err := template.New(kind).Funcs(all).Option("missingkey=zero").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s template %q: %w", kind, text, err)
+		}
+		templates[i] = tmpl
+	}
+	return templates, nil
+}
+
+// Render executes tmpl with args. Arguments the template refers to but
+// args lacks are rendered as empty strings.
+func Render(tmpl *template.Template, args map[string]any) (string, error) {
+	var missing []string
+	if tmpl.Tree != nil {
+		for _, name := range fieldNames(tmpl.Tree.Root, nil) {
+			if _, ok := args[name]; !ok {
+				missing = append(missing, name)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		args = maps.Clone(args)
+		if args == nil {
+			args = make(map[string]any, len(missing))
+		}
+		for _, name := range missing {
+			args[name] = ""
+		}
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, args); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// fieldNames appends to names the arguments node refers to as .name or
+// $.name. Longer chains such as .a.b are left out: a string in place of a
+// missing a would not have the field b.
+func fieldNames(node parse.Node, names []string) []string {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return names
+		}
+		for _, n := range node.Nodes {
+			names = fieldNames(n, names)
+		}
+	case *parse.ActionNode:
+		names = fieldNames(node.Pipe, names)
+	case *parse.PipeNode:
+		if node == nil {
+			return names
+		}
+		for _, cmd := range node.Cmds {
+			names = fieldNames(cmd, names)
+		}
+	case *parse.CommandNode:
+		for _, arg := range node.Args {
+			names = fieldNames(arg, names)
+		}
+	case *parse.ChainNode:
+		names = fieldNames(node.Node, names)
+	case *parse.FieldNode:
+		if len(node.Ident) == 1 {
+			names = append(names, node.Ident[0])
+		}
+	case *parse.VariableNode:
+		if len(node.Ident) == 2 && node.Ident[0] == "$" {
+			names = append(names, node.Ident[1])
+		}
+	case *parse.IfNode:
+		names = fieldNames(&node.BranchNode, names)
+	case *parse.RangeNode:
+		names = fieldNames(&node.BranchNode, names)
+	case *parse.WithNode:
+		names = fieldNames(&node.BranchNode, names)
+	case *parse.BranchNode:
+		names = fieldNames(node.Pipe, names)
+		names = fieldNames(node.List, names)
+		names = fieldNames(node.ElseList, names)
+	case *parse.TemplateNode:
+		names = fieldNames(node.Pipe, names)
+	}
+	return names
+}
@@ -67,6 +67,26 @@ var MCPRequestTypes = []MCPRequestType{
 		HookName:       "ReadResource",
 		UnmarshalError: "invalid read resource request",
 		HandlerFunc:    "handleReadResource",
+	}, {
+		MethodName:     "MethodResourcesSubscribe",
+		ParamType:      "SubscribeRequest",
+		ResultType:     "EmptyResult",
+		Group:          "resources",
+		GroupName:      "Resources",
+		GroupHookName:  "Resource",
+		HookName:       "Subscribe",
+		UnmarshalError: "invalid subscribe request",
+		HandlerFunc:    "handleSubscribe",
+	}, {
+		MethodName:     "MethodResourcesUnsubscribe",
+		ParamType:      "UnsubscribeRequest",
+		ResultType:     "EmptyResult",
+		Group:          "resources",
+		GroupName:      "Resources",
+		GroupHookName:  "Resource",
+		HookName:       "Unsubscribe",
+		UnmarshalError: "invalid unsubscribe request",
+		HandlerFunc:    "handleUnsubscribe",
 	}, {
 		MethodName:     "MethodPromptsList",
 		ParamType:      "ListPromptsRequest",
@@ -107,5 +127,25 @@ var MCPRequestTypes = []MCPRequestType{
 		HookName:       "CallTool",
 		UnmarshalError: "invalid call tool request",
 		HandlerFunc:    "handleToolCall",
+	}, {
+		MethodName:     "MethodToolsValidate",
+		ParamType:      "ValidateToolRequest",
+		ResultType:     "ValidateToolResult",
+		Group:          "tools",
+		GroupName:      "Tools",
+		GroupHookName:  "Tool",
+		HookName:       "ValidateTool",
+		UnmarshalError: "invalid validate tool request",
+		HandlerFunc:    "handleValidateTool",
+	}, {
+		MethodName:     "MethodCompletionComplete",
+		ParamType:      "CompleteRequest",
+		ResultType:     "CompleteResult",
+		Group:          "completions",
+		GroupName:      "Completions",
+		GroupHookName:  "Completion",
+		HookName:       "Complete",
+		UnmarshalError: "invalid complete request",
+		HandlerFunc:    "handleComplete",
 	},
 }
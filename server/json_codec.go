@@ -0,0 +1,30 @@
+package server
+
+import (
+	"bytes"
+
+	"github.com/mark3labs/mcp-go/util"
+)
+
+// WithJSONCodec makes the server decode requests and encode responses and
+// notifications with codec instead of encoding/json, for example with sonic
+// or jsoniter on servers handling many messages:
+//
+//	server.NewMCPServer("name", "1.0.0", server.WithJSONCodec(sonic.ConfigStd))
+//
+// The codec must call the json.Marshaler and json.Unmarshaler methods of
+// the mcp types, as the standard library compatible configurations of these
+// packages do.
+func WithJSONCodec(codec util.JSONCodec) ServerOption {
+	return func(s *MCPServer) {
+		if codec != nil {
+			s.jsonCodec = codec
+		}
+	}
+}
+
+// encodeJSON appends the JSON encoding of v and a newline to buf with the
+// codec of the server.
+func (s *MCPServer) encodeJSON(buf *bytes.Buffer, v any) error {
+	return util.EncodeJSON(s.jsonCodec, buf, v)
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// countingCodec is a JSON codec counting its calls, encoding with
+// encoding/json.
+type countingCodec struct {
+	marshals, unmarshals atomic.Int32
+}
+
+func (c *countingCodec) Marshal(v any) ([]byte, error) {
+	c.marshals.Add(1)
+	return json.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v any) error {
+	c.unmarshals.Add(1)
+	return json.Unmarshal(data, v)
+}
+
+func TestMCPServer_WithJSONCodec(t *testing.T) {
+	t.Run("stdio", func(t *testing.T) {
+		codec := &countingCodec{}
+		stdioServer := NewStdioServer(NewMCPServer("test", "1.0.0", WithJSONCodec(codec)))
+		stdin := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1.0.0"}}}
+{"jsonrpc":"2.0","id":2,"method":"ping"}
+`)
+		var stdout bytes.Buffer
+		require.NoError(t, stdioServer.Listen(context.Background(), stdin, &stdout))
+
+		lines := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+		require.Len(t, lines, 2)
+		assert.JSONEq(t, `{"jsonrpc":"2.0","id":2,"result":{}}`, lines[1])
+		assert.Equal(t, int32(2), codec.marshals.Load())
+		assert.GreaterOrEqual(t, codec.unmarshals.Load(), int32(2), "requests are decoded with the codec")
+	})
+
+	t.Run("streamable HTTP", func(t *testing.T) {
+		codec := &countingCodec{}
+		testServer := NewTestStreamableHTTPServer(NewMCPServer("test", "1.0.0", WithJSONCodec(codec)), WithStateLess(true))
+		defer testServer.Close()
+
+		resp, err := postJSON(testServer.URL, map[string]any{"jsonrpc": "2.0", "id": 1, "method": "ping"})
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"jsonrpc":"2.0","id":1,"result":{}}`, string(body))
+		assert.Equal(t, int32(1), codec.marshals.Load())
+		assert.GreaterOrEqual(t, codec.unmarshals.Load(), int32(2), "the body and the request are decoded with the codec")
+	})
+}
+
+// BenchmarkStdioServer_WriteResponse compares writing a tools/call response
+// encoded with json.Marshal and fmt.Fprintf, as the stdio server did, with
+// writeResponse, which encodes it into a pooled buffer.
+func BenchmarkStdioServer_WriteResponse(b *testing.B) {
+	response := mcp.NewJSONRPCResultResponse(mcp.NewRequestID(int64(1)),
+		mcp.NewToolResultText(strings.Repeat("forecast: sunny, 21°C. ", 40)))
+	stdioServer := NewStdioServer(NewMCPServer("test", "1.0.0"))
+
+	b.Run("Marshal", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			data, err := json.Marshal(response)
+			if err != nil {
+				b.Fatal(err)
+			}
+			_, _ = fmt.Fprintf(io.Discard, "%s\n", data)
+		}
+	})
+	b.Run("Pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := stdioServer.writeResponse(response, io.Discard); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
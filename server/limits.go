@@ -0,0 +1,207 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Limit names a limit set with WithLimits.
+type Limit string
+
+const (
+	// LimitRequestSize is the maximum size of a message in bytes.
+	LimitRequestSize Limit = "request_size"
+	// LimitParamsDepth is the maximum nesting depth of the objects and
+	// arrays of a message.
+	LimitParamsDepth Limit = "params_depth"
+	// LimitDuration is the maximum time a request handler may take.
+	LimitDuration Limit = "duration"
+)
+
+// Limits protects a server from abusive clients. Zero values disable the
+// corresponding limit.
+type Limits struct {
+	// MaxRequestSize is the maximum size of a message in bytes. The HTTP
+	// transports stop reading request bodies beyond it.
+	MaxRequestSize int64
+	// MaxParamsDepth is the maximum nesting depth of the objects and arrays
+	// in the params of a message: params of {"a":{"b":[1]}} are 3 levels
+	// deep.
+	MaxParamsDepth int
+	// MaxHandlerDuration is the time budget of request handlers. Their
+	// context is cancelled once it is spent, and the request is answered
+	// with a LIMIT_EXCEEDED error.
+	MaxHandlerDuration time.Duration
+}
+
+// WithLimits sets limits on the size and depth of messages and on the
+// duration of request handlers. Requests exceeding a limit are answered
+// with a LIMIT_EXCEEDED error whose data is an mcp.LimitExceededErrorData;
+// notifications and responses exceeding one are dropped. Either way the
+// *LimitExceededError is reported to the OnLimitExceeded and OnError hooks.
+func WithLimits(limits Limits) ServerOption {
+	return func(s *MCPServer) {
+		s.limits = limits
+	}
+}
+
+// LimitExceededError is the error of a message exceeding a limit. It
+// matches mcp.ErrLimitExceeded.
+type LimitExceededError struct {
+	// Limit is the exceeded limit.
+	Limit Limit
+	// Max is the value of the limit: a number of bytes for
+	// LimitRequestSize, of levels for LimitParamsDepth, and a time.Duration
+	// for LimitDuration.
+	Max int64
+	// Method is the method of the message, if known.
+	Method mcp.MCPMethod
+}
+
+func (e *LimitExceededError) Error() string {
+	return e.details().Error()
+}
+
+// Unwrap returns the *mcp.Error sent to the client.
+func (e *LimitExceededError) Unwrap() error {
+	return e.details()
+}
+
+func (e *LimitExceededError) details() *mcp.Error {
+	var message string
+	data := mcp.LimitExceededErrorData{Limit: string(e.Limit), Max: e.Max}
+	switch e.Limit {
+	case LimitRequestSize:
+		message = fmt.Sprintf("request exceeds the maximum size of %d bytes", e.Max)
+	case LimitParamsDepth:
+		message = fmt.Sprintf("params exceed the maximum depth of %d", e.Max)
+	case LimitDuration:
+		message = fmt.Sprintf("request exceeded the handler time budget of %s", time.Duration(e.Max))
+		data.Max = time.Duration(e.Max).Milliseconds()
+	default:
+		message = fmt.Sprintf("request exceeds the %s limit of %d", e.Limit, e.Max)
+	}
+	return mcp.NewError(mcp.LIMIT_EXCEEDED, message, data)
+}
+
+// reportLimitExceeded calls the hooks for a message exceeding a limit.
+func (s *MCPServer) reportLimitExceeded(ctx context.Context, id any, message any, err *LimitExceededError) {
+	s.hooks.onLimitExceeded(ctx, id, err.Method, err)
+	s.hooks.onError(ctx, id, err.Method, message, err)
+}
+
+// rejectOversizedMessage checks message against the size and depth
+// limits. It returns the response to a message exceeding one, nil for
+// notifications and responses, and whether the message was rejected.
+func (s *MCPServer) rejectOversizedMessage(ctx context.Context, message json.RawMessage) (mcp.JSONRPCMessage, bool) {
+	var limitErr *LimitExceededError
+	switch {
+	case s.limits.MaxRequestSize > 0 && int64(len(message)) > s.limits.MaxRequestSize:
+		limitErr = &LimitExceededError{Limit: LimitRequestSize, Max: s.limits.MaxRequestSize}
+	case s.limits.MaxParamsDepth > 0 && exceedsDepth(message, s.limits.MaxParamsDepth+1):
+		// The message object itself is one level above its params.
+		limitErr = &LimitExceededError{Limit: LimitParamsDepth, Max: int64(s.limits.MaxParamsDepth)}
+	default:
+		return nil, false
+	}
+
+	var frame struct {
+		ID     mcp.RequestID   `json:"id"`
+		Method mcp.MCPMethod   `json:"method"`
+		Result json.RawMessage `json:"result"`
+		Error  json.RawMessage `json:"error"`
+	}
+	_ = json.Unmarshal(message, &frame)
+	limitErr.Method = frame.Method
+	id := frame.ID.Value()
+	s.reportLimitExceeded(ctx, id, nil, limitErr)
+	if id == nil || frame.Result != nil || frame.Error != nil {
+		return nil, true
+	}
+	return (&requestError{id: id, code: mcp.LIMIT_EXCEEDED, err: limitErr}).ToJSONRPCError(), true
+}
+
+// exceedsDepth reports whether the objects and arrays of the JSON value
+// data nest deeper than depth. It does not validate data.
+func exceedsDepth(data []byte, depth int) bool {
+	current := 0
+	inString := false
+	escaped := false
+	for _, c := range data {
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			switch c {
+			case '\\':
+				escaped = true
+			case '"':
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[':
+			current++
+			if current > depth {
+				return true
+			}
+		case c == '}' || c == ']':
+			current--
+		}
+	}
+	return false
+}
+
+// withDurationLimit returns the context of a request handler, cancelled
+// with a *LimitExceededError cause once the MaxHandlerDuration is spent.
+func (s *MCPServer) withDurationLimit(ctx context.Context, method mcp.MCPMethod) (context.Context, context.CancelFunc) {
+	if s.limits.MaxHandlerDuration <= 0 {
+		return ctx, func() {}
+	}
+	cause := &LimitExceededError{Limit: LimitDuration, Max: int64(s.limits.MaxHandlerDuration), Method: method}
+	return context.WithTimeoutCause(ctx, s.limits.MaxHandlerDuration, cause)
+}
+
+// durationLimitError returns the error answering a request whose handler
+// spent its time budget, or nil if it did not, and calls the
+// OnLimitExceeded hooks.
+func (s *MCPServer) durationLimitError(ctx context.Context, id any) *requestError {
+	var limitErr *LimitExceededError
+	if !errors.As(context.Cause(ctx), &limitErr) {
+		return nil
+	}
+	s.hooks.onLimitExceeded(ctx, id, limitErr.Method, limitErr)
+	return &requestError{id: id, code: mcp.LIMIT_EXCEEDED, err: limitErr}
+}
+
+// limitRequestBody limits the HTTP request body to the MaxRequestSize.
+func (s *MCPServer) limitRequestBody(w http.ResponseWriter, body io.ReadCloser) io.ReadCloser {
+	if s.limits.MaxRequestSize <= 0 {
+		return body
+	}
+	return http.MaxBytesReader(w, body, s.limits.MaxRequestSize)
+}
+
+// rejectOversizedBody answers an HTTP request whose body could not be read
+// because of err if the body exceeded the MaxRequestSize, and reports
+// whether it did.
+func (s *MCPServer) rejectOversizedBody(ctx context.Context, w http.ResponseWriter, err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	if !errors.As(err, &maxBytesErr) {
+		return false
+	}
+	limitErr := &LimitExceededError{Limit: LimitRequestSize, Max: maxBytesErr.Limit}
+	s.reportLimitExceeded(ctx, nil, nil, limitErr)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusRequestEntityTooLarge)
+	_ = json.NewEncoder(w).Encode((&requestError{code: mcp.LIMIT_EXCEEDED, err: limitErr}).ToJSONRPCError())
+	return true
+}
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// limitRecorder records the errors reported to the OnLimitExceeded hook.
+type limitRecorder struct {
+	mu   sync.Mutex
+	errs []*LimitExceededError
+}
+
+func (r *limitRecorder) hooks() *Hooks {
+	hooks := &Hooks{}
+	hooks.AddOnLimitExceeded(func(ctx context.Context, id any, method mcp.MCPMethod, err *LimitExceededError) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.errs = append(r.errs, err)
+	})
+	return hooks
+}
+
+func (r *limitRecorder) recorded() []*LimitExceededError {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.errs
+}
+
+func TestMCPServer_WithLimits(t *testing.T) {
+	tests := []struct {
+		name         string
+		limits       Limits
+		message      string
+		expectedErr  *LimitExceededError
+		expectedData mcp.LimitExceededErrorData
+		dropped      bool
+	}{
+		{
+			name:         "oversized request",
+			limits:       Limits{MaxRequestSize: 64},
+			message:      `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"text":"` + strings.Repeat("a", 64) + `"}}}`,
+			expectedErr:  &LimitExceededError{Limit: LimitRequestSize, Max: 64, Method: mcp.MethodToolsCall},
+			expectedData: mcp.LimitExceededErrorData{Limit: "request_size", Max: 64},
+		},
+		{
+			name:         "deep params",
+			limits:       Limits{MaxParamsDepth: 3},
+			message:      `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"text":["a",{"b":1}]}}}`,
+			expectedErr:  &LimitExceededError{Limit: LimitParamsDepth, Max: 3, Method: mcp.MethodToolsCall},
+			expectedData: mcp.LimitExceededErrorData{Limit: "params_depth", Max: 3},
+		},
+		{
+			name:        "oversized notification is dropped",
+			limits:      Limits{MaxRequestSize: 16},
+			message:     `{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+			expectedErr: &LimitExceededError{Limit: LimitRequestSize, Max: 16, Method: "notifications/initialized"},
+			dropped:     true,
+		},
+		{
+			name:    "within limits",
+			limits:  Limits{MaxRequestSize: 1024, MaxParamsDepth: 3},
+			message: `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"text":"[[[[{{{{"}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &limitRecorder{}
+			server := NewMCPServer("test-server", "1.0.0",
+				WithToolCapabilities(false),
+				WithLimits(tt.limits),
+				WithHooks(recorder.hooks()),
+			)
+			server.AddTool(mcp.NewTool("echo"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultText("ok"), nil
+			})
+
+			response := server.HandleMessage(context.Background(), json.RawMessage(tt.message))
+			if tt.expectedErr == nil {
+				assert.IsType(t, mcp.JSONRPCResponse{}, response)
+				assert.Empty(t, recorder.recorded())
+				return
+			}
+
+			assert.Equal(t, []*LimitExceededError{tt.expectedErr}, recorder.recorded())
+			if tt.dropped {
+				assert.Nil(t, response)
+				return
+			}
+			errResponse, ok := response.(mcp.JSONRPCError)
+			require.True(t, ok, "expected JSONRPCError, got %T", response)
+			assert.Equal(t, mcp.LIMIT_EXCEEDED, errResponse.Error.Code)
+			assert.Equal(t, tt.expectedData, errResponse.Error.Data)
+		})
+	}
+}
+
+func TestMCPServer_WithLimits_HandlerDuration(t *testing.T) {
+	recorder := &limitRecorder{}
+	var toolErr error
+	server := NewMCPServer("test-server", "1.0.0",
+		WithToolCapabilities(false),
+		WithLimits(Limits{MaxHandlerDuration: 20 * time.Millisecond}),
+		WithHooks(recorder.hooks()),
+	)
+	server.AddTool(mcp.NewTool("slow"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		<-ctx.Done()
+		toolErr = context.Cause(ctx)
+		return nil, ctx.Err()
+	})
+
+	response := server.HandleMessage(context.Background(), json.RawMessage(
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"slow"}}`))
+
+	errResponse, ok := response.(mcp.JSONRPCError)
+	require.True(t, ok, "expected JSONRPCError, got %T", response)
+	assert.Equal(t, mcp.LIMIT_EXCEEDED, errResponse.Error.Code)
+	assert.Equal(t, mcp.LimitExceededErrorData{Limit: "duration", Max: 20}, errResponse.Error.Data)
+	assert.ErrorIs(t, toolErr, mcp.ErrLimitExceeded)
+
+	require.Len(t, recorder.recorded(), 1)
+	assert.Equal(t, LimitDuration, recorder.recorded()[0].Limit)
+	assert.Equal(t, mcp.MethodToolsCall, recorder.recorded()[0].Method)
+}
+
+func TestLimitExceededError(t *testing.T) {
+	err := error(&LimitExceededError{Limit: LimitDuration, Max: int64(time.Second)})
+	assert.EqualError(t, err, "limit exceeded: request exceeded the handler time budget of 1s")
+	assert.ErrorIs(t, err, mcp.ErrLimitExceeded)
+
+	var mcpErr *mcp.Error
+	require.True(t, errors.As(err, &mcpErr))
+	assert.Equal(t, mcp.LimitExceededErrorData{Limit: "duration", Max: 1000}, mcpErr.Data)
+}
+
+func TestExceedsDepth(t *testing.T) {
+	tests := []struct {
+		data     string
+		depth    int
+		expected bool
+	}{
+		{data: `1`, depth: 0},
+		{data: `{}`, depth: 0, expected: true},
+		{data: `{"a":{"b":[1]}}`, depth: 3},
+		{data: `{"a":{"b":[[1]]}}`, depth: 3, expected: true},
+		{data: `[{},{},{}]`, depth: 2},
+		{data: `{"a":"[[[{{{"}`, depth: 1},
+		{data: `{"a":"\"[[[{{{"}`, depth: 1},
+		{data: `{"a":"\\","b":[[1]]}`, depth: 2, expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			assert.Equal(t, tt.expected, exceedsDepth([]byte(tt.data), tt.depth))
+		})
+	}
+}
+
+func TestStreamableHTTPServer_RequestSizeLimit(t *testing.T) {
+	recorder := &limitRecorder{}
+	server := NewMCPServer("test-server", "1.0.0",
+		WithLimits(Limits{MaxRequestSize: 64}),
+		WithHooks(recorder.hooks()),
+	)
+	testServer := NewTestStreamableHTTPServer(server, WithStateLess(true))
+	defer testServer.Close()
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"ping","params":{"padding":"` + strings.Repeat("a", 64) + `"}}`
+	resp, err := http.Post(testServer.URL, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	raw, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var errResponse struct {
+		Error mcp.JSONRPCErrorDetails `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &errResponse))
+	assert.Equal(t, mcp.LIMIT_EXCEEDED, errResponse.Error.Code)
+
+	require.Len(t, recorder.recorded(), 1)
+	assert.Equal(t, LimitRequestSize, recorder.recorded()[0].Limit)
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WithListChangedDebounce batches list_changed notifications. Instead of
+// sending one notification per registration, the server waits for window
+// after the first change and then sends a single notification per session
+// and list type, so registering hundreds of tools in a burst produces one
+// notifications/tools/list_changed per session.
+//
+// A window of zero (the default) sends notifications immediately.
+func WithListChangedDebounce(window time.Duration) ServerOption {
+	return func(s *MCPServer) {
+		s.listChanged.window = window
+	}
+}
+
+// listChangedKey identifies a pending notification. An empty sessionID
+// stands for all sessions.
+type listChangedKey struct {
+	sessionID string
+	method    string
+}
+
+// listChangedBatcher holds the list_changed notifications that are waiting
+// for their debounce window to elapse.
+type listChangedBatcher struct {
+	window  time.Duration
+	mu      sync.Mutex
+	pending map[listChangedKey]*time.Timer
+}
+
+// schedule arranges for send to run once the window started by the first
+// call for key has elapsed. It reports false if send was merged into a
+// notification that is already pending.
+func (b *listChangedBatcher) schedule(key listChangedKey, send func()) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.pending[key]; ok {
+		return false
+	}
+	if key.sessionID != "" {
+		if _, ok := b.pending[listChangedKey{method: key.method}]; ok {
+			// a notification for every session is already on its way
+			return false
+		}
+	}
+	if b.pending == nil {
+		b.pending = make(map[listChangedKey]*time.Timer)
+	}
+	b.pending[key] = time.AfterFunc(b.window, func() {
+		b.mu.Lock()
+		delete(b.pending, key)
+		if key.sessionID == "" {
+			// the broadcast supersedes notifications queued for single sessions
+			for pendingKey, timer := range b.pending {
+				if pendingKey.method == key.method && timer.Stop() {
+					delete(b.pending, pendingKey)
+				}
+			}
+		}
+		b.mu.Unlock()
+		send()
+	})
+	return true
+}
+
+// notifyListChanged tells every initialized session that the list behind
+// method changed, batching the notification if WithListChangedDebounce
+// is set.
+func (s *MCPServer) notifyListChanged(method string) {
+	if s.listChanged.window <= 0 {
+		s.sendNotificationToAllClients(newNotification(method, nil))
+		return
+	}
+	s.listChanged.schedule(listChangedKey{method: method}, func() {
+		s.sendNotificationToAllClients(newNotification(method, nil))
+	})
+}
+
+// notifySessionListChanged tells a single session that the list behind
+// method changed. When notifications are batched, delivery errors are
+// reported to the OnError hooks instead of being returned.
+func (s *MCPServer) notifySessionListChanged(sessionID, method string) error {
+	if s.listChanged.window <= 0 {
+		return s.SendNotificationToSpecificClient(sessionID, method, nil)
+	}
+	s.listChanged.schedule(listChangedKey{sessionID: sessionID, method: method}, func() {
+		err := s.SendNotificationToSpecificClient(sessionID, method, nil)
+		if err != nil && !errors.Is(err, ErrSessionNotFound) {
+			s.hooks.onError(context.Background(), nil, "notification", map[string]any{
+				"method":    method,
+				"sessionID": sessionID,
+			}, fmt.Errorf("failed to send batched notification: %w", err))
+		}
+	})
+	return nil
+}
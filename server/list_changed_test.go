@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// drainNotifications collects the methods of the notifications that arrive
+// on ch within wait.
+func drainNotifications(ch chan mcp.JSONRPCNotification, wait time.Duration) []string {
+	var methods []string
+	timeout := time.After(wait)
+	for {
+		select {
+		case notification := <-ch:
+			methods = append(methods, notification.Method)
+		case <-timeout:
+			return methods
+		}
+	}
+}
+
+func TestMCPServer_WithListChangedDebounce(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0",
+		WithToolCapabilities(true),
+		WithPromptCapabilities(true),
+		WithResourceCapabilities(false, true),
+		WithListChangedDebounce(30*time.Millisecond),
+	)
+
+	session := &sessionTestClientWithTools{
+		sessionID:           "session-1",
+		notificationChannel: make(chan mcp.JSONRPCNotification, 200),
+		initialized:         true,
+	}
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+
+	t.Run("a burst of registrations sends one notification per list", func(t *testing.T) {
+		for i := range 100 {
+			server.AddTool(mcp.NewTool(fmt.Sprintf("tool-%d", i)), echoToolHandler)
+		}
+		server.DeleteTools("tool-0", "tool-1")
+		server.AddPrompt(mcp.NewPrompt("greet"), nil)
+		server.AddResource(mcp.NewResource("file:///a", "a"), nil)
+
+		assert.Empty(t, session.notificationChannel, "notifications wait for the window")
+
+		methods := drainNotifications(session.notificationChannel, 150*time.Millisecond)
+		assert.ElementsMatch(t, []string{
+			mcp.MethodNotificationToolsListChanged,
+			mcp.MethodNotificationPromptsListChanged,
+			mcp.MethodNotificationResourcesListChanged,
+		}, methods)
+	})
+
+	t.Run("session registrations are batched too", func(t *testing.T) {
+		for i := range 20 {
+			require.NoError(t, server.AddSessionTool("session-1", mcp.NewTool(fmt.Sprintf("session-tool-%d", i)), echoToolHandler))
+		}
+		methods := drainNotifications(session.notificationChannel, 150*time.Millisecond)
+		assert.Equal(t, []string{mcp.MethodNotificationToolsListChanged}, methods)
+	})
+
+	t.Run("a pending broadcast absorbs session notifications", func(t *testing.T) {
+		server.AddTool(mcp.NewTool("late"), echoToolHandler)
+		require.NoError(t, server.AddSessionTool("session-1", mcp.NewTool("late-session"), echoToolHandler))
+		methods := drainNotifications(session.notificationChannel, 150*time.Millisecond)
+		assert.Equal(t, []string{mcp.MethodNotificationToolsListChanged}, methods)
+	})
+}
+
+func TestMCPServer_ListChangedWithoutDebounce(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithToolCapabilities(true))
+	session := fakeSession{
+		sessionID:           "session-1",
+		notificationChannel: make(chan mcp.JSONRPCNotification, 10),
+		initialized:         true,
+	}
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+
+	server.AddTool(mcp.NewTool("a"), echoToolHandler)
+	server.AddTool(mcp.NewTool("b"), echoToolHandler)
+	assert.Len(t, session.notificationChannel, 2, "notifications are sent immediately by default")
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Logger sends notifications/message log entries to a single client session.
+// Messages below the minimum level the client selected with logging/setLevel
+// are dropped without error.
+//
+// A Logger is cheap to create and safe for concurrent use.
+type Logger struct {
+	server  *MCPServer
+	session ClientSession
+	name    string
+}
+
+// LoggerFromContext returns a Logger bound to the server and client session
+// stored in ctx. Inside request handlers both are always present.
+// The returned Logger is never nil; if the session is missing or does not
+// support logging, its methods return the corresponding error.
+func LoggerFromContext(ctx context.Context) *Logger {
+	return &Logger{
+		server:  ServerFromContext(ctx),
+		session: ClientSessionFromContext(ctx),
+	}
+}
+
+// Logger returns a Logger for the client session stored in ctx.
+func (s *MCPServer) Logger(ctx context.Context) *Logger {
+	return &Logger{
+		server:  s,
+		session: ClientSessionFromContext(ctx),
+	}
+}
+
+// Named returns a copy of the Logger that reports the given logger name
+// in every message it sends.
+func (l *Logger) Named(name string) *Logger {
+	clone := *l
+	clone.name = name
+	return &clone
+}
+
+// Name returns the logger name attached to outgoing messages.
+func (l *Logger) Name() string {
+	return l.name
+}
+
+// Enabled reports whether a message at the given level would be delivered
+// to the client under its current log level.
+func (l *Logger) Enabled(level mcp.LoggingLevel) bool {
+	sessionLogging, ok := l.session.(SessionWithLogging)
+	if !ok || !l.session.Initialized() {
+		return false
+	}
+	return level.ShouldSendTo(sessionLogging.GetLogLevel())
+}
+
+// Log sends data at the given level. Data may be a string or any JSON
+// serializable value, such as a map of structured fields.
+func (l *Logger) Log(level mcp.LoggingLevel, data any) error {
+	if l.server == nil {
+		return fmt.Errorf("logger has no server: %w", ErrUnsupported)
+	}
+	if l.session == nil || !l.session.Initialized() {
+		return ErrNotificationNotInitialized
+	}
+	sessionLogging, ok := l.session.(SessionWithLogging)
+	if !ok {
+		return ErrSessionDoesNotSupportLogging
+	}
+	if !level.ShouldSendTo(sessionLogging.GetLogLevel()) {
+		return nil
+	}
+	notification := mcp.NewLoggingMessageNotification(level, l.name, data)
+	return l.server.sendNotificationToSpecificClient(l.session, notification.ToJSONRPCNotification())
+}
+
+// Logf formats a message according to a format specifier and sends it at the given level.
+func (l *Logger) Logf(level mcp.LoggingLevel, format string, args ...any) error {
+	return l.Log(level, fmt.Sprintf(format, args...))
+}
+
+// Debug sends data at debug level.
+func (l *Logger) Debug(data any) error {
+	return l.Log(mcp.LoggingLevelDebug, data)
+}
+
+// Info sends data at info level.
+func (l *Logger) Info(data any) error {
+	return l.Log(mcp.LoggingLevelInfo, data)
+}
+
+// Notice sends data at notice level.
+func (l *Logger) Notice(data any) error {
+	return l.Log(mcp.LoggingLevelNotice, data)
+}
+
+// Warning sends data at warning level.
+func (l *Logger) Warning(data any) error {
+	return l.Log(mcp.LoggingLevelWarning, data)
+}
+
+// Error sends data at error level.
+func (l *Logger) Error(data any) error {
+	return l.Log(mcp.LoggingLevelError, data)
+}
+
+// Critical sends data at critical level.
+func (l *Logger) Critical(data any) error {
+	return l.Log(mcp.LoggingLevelCritical, data)
+}
+
+// Alert sends data at alert level.
+func (l *Logger) Alert(data any) error {
+	return l.Log(mcp.LoggingLevelAlert, data)
+}
+
+// Emergency sends data at emergency level.
+func (l *Logger) Emergency(data any) error {
+	return l.Log(mcp.LoggingLevelEmergency, data)
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestLogger_RespectsSessionLevel(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithLogging())
+	ctx := context.Background()
+
+	sessionChan := make(chan mcp.JSONRPCNotification, 10)
+	session := &sessionTestClientWithLogging{
+		sessionID:           "session-1",
+		notificationChannel: sessionChan,
+	}
+	session.Initialize()
+	session.SetLogLevel(mcp.LoggingLevelWarning)
+	require.NoError(t, server.RegisterSession(ctx, session))
+
+	logger := server.Logger(server.WithContext(ctx, session)).Named("db")
+
+	assert.False(t, logger.Enabled(mcp.LoggingLevelInfo))
+	assert.True(t, logger.Enabled(mcp.LoggingLevelError))
+
+	require.NoError(t, logger.Info("dropped"))
+	require.NoError(t, logger.Error(map[string]any{"query": "SELECT 1", "rows": 0}))
+
+	select {
+	case notif := <-sessionChan:
+		assert.Equal(t, "notifications/message", notif.Method)
+		assert.Equal(t, mcp.LoggingLevelError, notif.Params.AdditionalFields["level"])
+		assert.Equal(t, "db", notif.Params.AdditionalFields["logger"])
+		assert.Equal(t, map[string]any{"query": "SELECT 1", "rows": 0}, notif.Params.AdditionalFields["data"])
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("Expected log notification not received")
+	}
+
+	select {
+	case notif := <-sessionChan:
+		t.Errorf("Unexpected notification: %v", notif)
+	default:
+	}
+}
+
+func TestLogger_Errors(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithLogging())
+	ctx := context.Background()
+
+	t.Run("no session", func(t *testing.T) {
+		err := server.Logger(ctx).Info("message")
+		assert.ErrorIs(t, err, ErrNotificationNotInitialized)
+	})
+
+	t.Run("no server", func(t *testing.T) {
+		err := LoggerFromContext(ctx).Info("message")
+		assert.ErrorIs(t, err, ErrUnsupported)
+	})
+
+	t.Run("session without logging support", func(t *testing.T) {
+		session := &sessionTestClient{
+			sessionID:           "session-2",
+			notificationChannel: make(chan mcp.JSONRPCNotification, 1),
+			initialized:         true,
+		}
+		err := server.Logger(server.WithContext(ctx, session)).Info("message")
+		assert.ErrorIs(t, err, ErrSessionDoesNotSupportLogging)
+	})
+}
+
+func TestLogger_FromToolHandler(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithLogging())
+	server.AddTool(mcp.NewTool("work"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := LoggerFromContext(ctx).Named("worker").Logf(mcp.LoggingLevelInfo, "processed %d items", 3); err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText("done"), nil
+	})
+
+	sessionChan := make(chan mcp.JSONRPCNotification, 10)
+	session := &sessionTestClientWithLogging{
+		sessionID:           "session-3",
+		notificationChannel: sessionChan,
+	}
+	session.Initialize()
+	ctx := server.WithContext(context.Background(), session)
+	require.NoError(t, server.RegisterSession(ctx, session))
+
+	setLevel := server.HandleMessage(ctx, json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"logging/setLevel","params":{"level":"info"}}`))
+	_, ok := setLevel.(mcp.JSONRPCResponse)
+	require.True(t, ok, "expected success response, got %#v", setLevel)
+
+	response := server.HandleMessage(ctx, json.RawMessage(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"work"}}`))
+	_, ok = response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "expected success response, got %#v", response)
+
+	select {
+	case notif := <-sessionChan:
+		assert.Equal(t, mcp.LoggingLevelInfo, notif.Params.AdditionalFields["level"])
+		assert.Equal(t, "worker", notif.Params.AdditionalFields["logger"])
+		assert.Equal(t, "processed 3 items", notif.Params.AdditionalFields["data"])
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("Expected log notification not received")
+	}
+}
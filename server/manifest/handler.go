@@ -0,0 +1,169 @@
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mark3labs/mcp-go/server/exectool"
+	"github.com/mark3labs/mcp-go/server/internal/argtemplate"
+)
+
+// maxResponseSize bounds the output of commands and the bodies of HTTP
+// responses returned to the client.
+const maxResponseSize = 1 << 20
+
+// newHandler builds the handler of a tool from its binding. Requests of
+// HTTP bindings are sent with client, or http.DefaultClient if it is nil.
+// Commands and the env template function only see the environment
+// variables named in envAllowlist, and commands also PATH.
+func newHandler(tool Tool, client *http.Client, envAllowlist []string) (server.ToolHandlerFunc, error) {
+	if tool.Command != nil {
+		return newCommandHandler(*tool.Command, envAllowlist)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return newHTTPHandler(tool.Name, *tool.HTTP, client, envAllowlist)
+}
+
+// templateFuncs returns the functions of templates besides those of
+// argtemplate: env reads one of the allowed environment variables, and
+// pathescape and queryescape escape a value for a URL.
+func templateFuncs(envAllowlist []string) template.FuncMap {
+	funcs := template.FuncMap{
+		"env": func(name string) (string, error) {
+			if !slices.Contains(envAllowlist, name) {
+				return "", fmt.Errorf("environment variable %q is not allowed", name)
+			}
+			return os.Getenv(name), nil
+		},
+	}
+	maps.Copy(funcs, escapeFuncs)
+	return funcs
+}
+
+func withTimeout(ctx context.Context, timeout Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, time.Duration(timeout))
+}
+
+func newCommandHandler(binding CommandBinding, envAllowlist []string) (server.ToolHandlerFunc, error) {
+	return exectool.NewHandler(
+		exectool.Command{Args: binding.Args, Env: binding.Env},
+		exectool.WithDir(binding.Dir),
+		exectool.WithTimeout(time.Duration(binding.Timeout)),
+		exectool.WithMaxOutput(maxResponseSize),
+		exectool.WithEnvAllowlist(envAllowlist...),
+	)
+}
+
+func newHTTPHandler(name string, binding HTTPBinding, client *http.Client, envAllowlist []string) (server.ToolHandlerFunc, error) {
+	funcs := templateFuncs(envAllowlist)
+	method := strings.ToUpper(binding.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	urls, err := argtemplate.Parse("url", []string{binding.URL}, funcs)
+	if err != nil {
+		return nil, err
+	}
+	escapeURLTemplate(urls[0])
+	headerNames := make([]string, 0, len(binding.Headers))
+	headerTexts := make([]string, 0, len(binding.Headers))
+	for header, text := range binding.Headers {
+		headerNames = append(headerNames, header)
+		headerTexts = append(headerTexts, text)
+	}
+	headers, err := argtemplate.Parse("header", headerTexts, funcs)
+	if err != nil {
+		return nil, err
+	}
+	var body *template.Template
+	if binding.Body != "" {
+		bodies, err := argtemplate.Parse("body", []string{binding.Body}, funcs)
+		if err != nil {
+			return nil, err
+		}
+		body = bodies[0]
+	}
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments := request.GetArguments()
+		url, err := argtemplate.Render(urls[0], arguments)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to expand url", err), nil
+		}
+		var reader io.Reader
+		contentType := ""
+		switch {
+		case body != nil:
+			rendered, err := argtemplate.Render(body, arguments)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to expand body", err), nil
+			}
+			reader = strings.NewReader(rendered)
+		case !isBodyless(method):
+			data, err := json.Marshal(request.GetRawArguments())
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to encode arguments", err), nil
+			}
+			reader, contentType = bytes.NewReader(data), "application/json"
+		}
+
+		ctx, cancel := withTimeout(ctx, binding.Timeout)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, method, url, reader)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid request", err), nil
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		for i, tmpl := range headers {
+			value, err := argtemplate.Render(tmpl, arguments)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to expand header", err), nil
+			}
+			req.Header.Set(headerNames[i], value)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return mcp.NewToolResultErrorf("request of tool %q timed out after %s", name, time.Duration(binding.Timeout)), nil
+			}
+			return mcp.NewToolResultErrorFromErr("request failed", err), nil
+		}
+		defer resp.Body.Close()
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to read response", err), nil
+		}
+		if resp.StatusCode >= http.StatusBadRequest {
+			return mcp.NewToolResultErrorf("%s: %s", resp.Status, strings.TrimSpace(string(data))), nil
+		}
+		if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+			var structured any
+			if err := json.Unmarshal(data, &structured); err == nil {
+				return mcp.NewToolResultStructured(structured, string(data)), nil
+			}
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	}, nil
+}
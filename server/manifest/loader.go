@@ -0,0 +1,188 @@
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Loader registers the tools of a manifest file on a server and keeps them
+// in sync with the file.
+type Loader struct {
+	server       *server.MCPServer
+	path         string
+	httpClient   *http.Client
+	envAllowlist []string
+	errorHandler func(error)
+	source       string
+
+	mu      sync.Mutex
+	content []byte
+	names   []string
+}
+
+// Option configures a Loader.
+type Option func(*Loader)
+
+// WithHTTPClient sets the client sending the requests of HTTP bindings.
+// It defaults to http.DefaultClient.
+func WithHTTPClient(client *http.Client) Option {
+	return func(l *Loader) {
+		l.httpClient = client
+	}
+}
+
+// WithEnvAllowlist lets the tools of the manifest see the named variables
+// of the server's environment. Commands get them in addition to PATH, and
+// templates can read them with env.
+func WithEnvAllowlist(names ...string) Option {
+	return func(l *Loader) {
+		l.envAllowlist = append(l.envAllowlist, names...)
+	}
+}
+
+// WithErrorHandler sets a function called when Watch fails to reload the
+// manifest. The previously loaded tools stay registered.
+func WithErrorHandler(handler func(error)) Option {
+	return func(l *Loader) {
+		l.errorHandler = handler
+	}
+}
+
+// NewLoader creates a loader of the manifest file at path for s.
+func NewLoader(s *server.MCPServer, path string, opts ...Option) *Loader {
+	l := &Loader{
+		server: s,
+		path:   path,
+		source: "manifest:" + path,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Load reads the manifest and registers its tools, replacing the tools of
+// the previous load and removing those no longer listed. Nothing is
+// changed if the manifest is invalid or identical to the one last loaded.
+func (l *Loader) Load() error {
+	content, err := os.ReadFile(l.path)
+	if err != nil {
+		return fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.content != nil && bytes.Equal(content, l.content) {
+		return nil
+	}
+	m, err := Parse(content)
+	if err != nil {
+		return fmt.Errorf("invalid manifest %s: %w", l.path, err)
+	}
+	tools, err := m.ServerTools(l.httpClient, l.envAllowlist...)
+	if err != nil {
+		return err
+	}
+	for i := range tools {
+		tools[i].Source = l.source
+	}
+
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.Tool.Name)
+	}
+	var removed []string
+	for _, name := range l.names {
+		if !slices.Contains(names, name) {
+			removed = append(removed, name)
+		}
+	}
+	if len(removed) > 0 || len(tools) > 0 {
+		l.server.UpdateTools(removed, tools...)
+	}
+	l.content, l.names = content, names
+	return nil
+}
+
+// Tools returns the names of the tools registered by the last load.
+func (l *Loader) Tools() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.Clone(l.names)
+}
+
+// watchDelay is how long Watch gathers the events of a burst of changes,
+// such as the several writes of one save, before reloading once.
+const watchDelay = 50 * time.Millisecond
+
+// Watch watches the manifest file with fsnotify and reloads it when it
+// changes, until ctx is done. The directory of the file is watched, so
+// editors replacing the file by a rename are followed. Adding, replacing or
+// removing tools makes the server send one notifications/tools/list_changed
+// per reload if it declared the capability. Reload errors are passed to the
+// error handler. It returns ctx.Err(), or the error of setting up the watch.
+func (l *Loader) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("manifest: %w", err)
+	}
+	defer watcher.Close()
+	path := filepath.Clean(l.path)
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("manifest: watching %s: %w", l.path, err)
+	}
+
+	var reload <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event := <-watcher.Events:
+			if filepath.Clean(event.Name) == path && reload == nil {
+				reload = time.After(watchDelay)
+			}
+		case <-watcher.Errors:
+			// Events lost to an overflow of the kernel queue cannot be
+			// recovered; later ones are still reported.
+		case <-reload:
+			reload = nil
+			if err := l.Load(); err != nil && l.errorHandler != nil {
+				l.errorHandler(err)
+			}
+		}
+	}
+}
+
+// ServerTools builds the tools of the manifest. Requests of HTTP bindings
+// are sent with client, or http.DefaultClient if it is nil. The tools see
+// the environment variables named in envAllowlist, as with WithEnvAllowlist.
+func (m *Manifest) ServerTools(client *http.Client, envAllowlist ...string) ([]server.ServerTool, error) {
+	tools := make([]server.ServerTool, 0, len(m.Tools))
+	for _, tool := range m.Tools {
+		schema, err := tool.inputSchema()
+		if err != nil {
+			return nil, err
+		}
+		handler, err := newHandler(tool, client, envAllowlist)
+		if err != nil {
+			return nil, fmt.Errorf("tool %q: %w", tool.Name, err)
+		}
+		tools = append(tools, server.ServerTool{
+			Tool:    mcp.NewTool(tool.Name, mcp.WithDescription(tool.Description), mcp.WithRawInputSchema(schema)),
+			Handler: handler,
+		})
+	}
+	return tools, nil
+}
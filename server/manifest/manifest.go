@@ -0,0 +1,202 @@
+// Package manifest registers tools described in a JSON or YAML manifest
+// file on a server.MCPServer, and reloads them when the file changes.
+//
+// A manifest lists tools with their name, description and input schema, and
+// binds each to a command to run or an HTTP endpoint to call:
+//
+//	tools:
+//	  - name: disk_usage
+//	    description: Report the disk usage of a directory
+//	    inputSchema:
+//	      type: object
+//	      properties:
+//	        path: {type: string}
+//	      required: [path]
+//	    command:
+//	      args: ["du", "-sh", "{{.path}}"]
+//	      timeout: 5s
+//	  - name: get_issue
+//	    description: Fetch an issue from the tracker
+//	    inputSchema:
+//	      type: object
+//	      properties:
+//	        id: {type: integer}
+//	    http:
+//	      method: GET
+//	      url: "https://tracker.example.com/api/issues/{{.id}}"
+//	      headers:
+//	        Authorization: "Bearer {{env \"TRACKER_TOKEN\"}}"
+//
+// Arguments, URLs, headers and bodies are text/template templates executed
+// with the tool call arguments. Besides the builtin functions, templates can
+// call env to read an environment variable, json to encode a value, and
+// pathescape and queryescape to escape a value for a URL.
+//
+// Manifests only get the environment variables the server allows: commands
+// run with PATH and the variables named by WithEnvAllowlist, which are also
+// the only ones env can read. The example above needs
+// WithEnvAllowlist("TRACKER_TOKEN").
+//
+// A Loader registers the tools of a manifest and, with Watch, watches the
+// file to replace them when it changes:
+//
+//	loader := manifest.NewLoader(s, "tools.yaml", manifest.WithEnvAllowlist("TRACKER_TOKEN"))
+//	if err := loader.Load(); err != nil {
+//	    log.Fatal(err)
+//	}
+//	go loader.Watch(ctx)
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/mark3labs/mcp-go/server/internal/duration"
+)
+
+// Manifest is the content of a manifest file.
+type Manifest struct {
+	Tools []Tool `json:"tools" yaml:"tools"`
+}
+
+// Tool describes a tool and the backend it is bound to. Exactly one of
+// Command and HTTP must be set.
+type Tool struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// InputSchema is the JSON schema of the tool arguments. It defaults to
+	// an object schema accepting any properties.
+	InputSchema map[string]any  `json:"inputSchema,omitempty" yaml:"inputSchema,omitempty"`
+	Command     *CommandBinding `json:"command,omitempty" yaml:"command,omitempty"`
+	HTTP        *HTTPBinding    `json:"http,omitempty" yaml:"http,omitempty"`
+}
+
+// CommandBinding runs a local command for each call. The standard output of
+// the command is returned as text; a failing command is reported as a tool
+// error carrying its standard error.
+type CommandBinding struct {
+	// Args are the templates of the program and its arguments. Each
+	// argument is expanded separately and passed without a shell.
+	Args []string `json:"args" yaml:"args"`
+	// Dir is the working directory of the command.
+	Dir string `json:"dir,omitempty" yaml:"dir,omitempty"`
+	// Env lists additional environment variables as KEY=value templates.
+	Env []string `json:"env,omitempty" yaml:"env,omitempty"`
+	// Timeout bounds the run time of the command.
+	Timeout Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// HTTPBinding calls an HTTP endpoint for each call. JSON responses are
+// returned as structured content, others as text. Responses with an error
+// status are reported as tool errors.
+type HTTPBinding struct {
+	// Method defaults to GET.
+	Method string `json:"method,omitempty" yaml:"method,omitempty"`
+	// URL is the template of the endpoint URL. Values printed by its
+	// actions are escaped as path segments before the "?" and as query
+	// values after it; actions ending with pathescape, queryescape or
+	// urlquery are not escaped again.
+	URL string `json:"url" yaml:"url"`
+	// Headers are templates of the request headers.
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	// Body is the template of the request body. Without it, requests other
+	// than GET, HEAD and DELETE send the arguments as JSON.
+	Body string `json:"body,omitempty" yaml:"body,omitempty"`
+	// Timeout bounds the duration of the request.
+	Timeout Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// Duration is a time.Duration read from a string such as "5s".
+type Duration = duration.Duration
+
+// Parse decodes a manifest in JSON or YAML and validates it.
+func Parse(data []byte) (*Manifest, error) {
+	var m Manifest
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&m); err != nil {
+			return nil, fmt.Errorf("failed to decode manifest: %w", err)
+		}
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&m); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to decode manifest: %w", err)
+		}
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// ReadFile reads and parses the manifest file at path.
+func ReadFile(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Validate checks that every tool is named, bound to exactly one backend
+// and has parsable templates.
+func (m *Manifest) Validate() error {
+	var errs []error
+	names := make(map[string]bool, len(m.Tools))
+	for i, tool := range m.Tools {
+		if tool.Name == "" {
+			errs = append(errs, fmt.Errorf("tool %d has no name", i))
+			continue
+		}
+		if names[tool.Name] {
+			errs = append(errs, fmt.Errorf("tool %q is defined more than once", tool.Name))
+		}
+		names[tool.Name] = true
+		switch {
+		case tool.Command == nil && tool.HTTP == nil:
+			errs = append(errs, fmt.Errorf("tool %q has no command or http binding", tool.Name))
+		case tool.Command != nil && tool.HTTP != nil:
+			errs = append(errs, fmt.Errorf("tool %q has both a command and an http binding", tool.Name))
+		case tool.Command != nil && len(tool.Command.Args) == 0:
+			errs = append(errs, fmt.Errorf("tool %q has an empty command", tool.Name))
+		case tool.HTTP != nil && tool.HTTP.URL == "":
+			errs = append(errs, fmt.Errorf("tool %q has no http url", tool.Name))
+		default:
+			if _, err := newHandler(tool, nil, nil); err != nil {
+				errs = append(errs, fmt.Errorf("tool %q: %w", tool.Name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// inputSchema returns the JSON schema of the tool arguments.
+func (t Tool) inputSchema() (json.RawMessage, error) {
+	schema := t.InputSchema
+	if schema == nil {
+		schema = map[string]any{"type": "object"}
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid input schema of tool %q: %w", t.Name, err)
+	}
+	return data, nil
+}
+
+// isBodyless reports whether requests of method send no body by default.
+func isBodyless(method string) bool {
+	switch strings.ToUpper(method) {
+	case "", "GET", "HEAD", "DELETE":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,345 @@
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		err      string
+		tools    []string
+	}{
+		{
+			name: "yaml",
+			manifest: `
+tools:
+  - name: echo
+    description: Echo a message
+    inputSchema:
+      type: object
+      properties:
+        message: {type: string}
+    command:
+      args: ["echo", "{{.message}}"]
+      timeout: 2s
+`,
+			tools: []string{"echo"},
+		},
+		{
+			name:     "json",
+			manifest: `{"tools": [{"name": "get", "http": {"url": "http://localhost/{{.id}}"}}]}`,
+			tools:    []string{"get"},
+		},
+		{
+			name:     "empty",
+			manifest: ``,
+		},
+		{
+			name:     "no binding",
+			manifest: `{"tools": [{"name": "get"}]}`,
+			err:      `tool "get" has no command or http binding`,
+		},
+		{
+			name:     "duplicate tool",
+			manifest: `{"tools": [{"name": "a", "command": {"args": ["true"]}}, {"name": "a", "command": {"args": ["true"]}}]}`,
+			err:      `tool "a" is defined more than once`,
+		},
+		{
+			name:     "invalid template",
+			manifest: `{"tools": [{"name": "a", "command": {"args": ["echo", "{{.x"]}}]}`,
+			err:      `invalid argument template`,
+		},
+		{
+			name:     "invalid timeout",
+			manifest: "tools:\n  - name: a\n    command: {args: [\"true\"], timeout: soon}\n",
+			err:      `invalid duration`,
+		},
+		{
+			name:     "unknown field",
+			manifest: `{"tools": [{"name": "a", "cmd": {"args": ["true"]}}]}`,
+			err:      `unknown field`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Parse([]byte(tt.manifest))
+			if tt.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+			require.NoError(t, err)
+			var names []string
+			for _, tool := range m.Tools {
+				names = append(names, tool.Name)
+			}
+			assert.Equal(t, tt.tools, names)
+		})
+	}
+}
+
+func callTool(t *testing.T, s *server.MCPServer, name string, args map[string]any) mcp.CallToolResult {
+	t.Helper()
+	data, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0", "id": 1, "method": "tools/call",
+		"params": map[string]any{"name": name, "arguments": args},
+	})
+	require.NoError(t, err)
+	response := s.HandleMessage(context.Background(), data)
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "unexpected response %#v", response)
+	return resp.Result.(mcp.CallToolResult)
+}
+
+func TestHTTPBinding(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/issues/42":
+			assert.Equal(t, "Bearer s3cret", r.Header.Get("Authorization"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":42,"title":"Broken build"}`))
+		case "/issues":
+			var body map[string]any
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			_, _ = w.Write([]byte("created " + body["title"].(string)))
+		default:
+			http.Error(w, "no such issue", http.StatusNotFound)
+		}
+	}))
+	defer upstream.Close()
+	t.Setenv("TRACKER_TOKEN", "s3cret")
+
+	m, err := Parse([]byte(`
+tools:
+  - name: get_issue
+    http:
+      url: "` + upstream.URL + `/issues/{{.id}}"
+      headers:
+        Authorization: 'Bearer {{env "TRACKER_TOKEN"}}'
+  - name: create_issue
+    http:
+      method: post
+      url: "` + upstream.URL + `/issues"
+`))
+	require.NoError(t, err)
+	tools, err := m.ServerTools(upstream.Client(), "TRACKER_TOKEN")
+	require.NoError(t, err)
+	s := server.NewMCPServer("test-server", "1.0.0")
+	s.AddTools(tools...)
+
+	result := callTool(t, s, "get_issue", map[string]any{"id": 42})
+	assert.False(t, result.IsError)
+	assert.Equal(t, map[string]any{"id": 42.0, "title": "Broken build"}, result.StructuredContent)
+
+	result = callTool(t, s, "create_issue", map[string]any{"title": "Flaky test"})
+	assert.Equal(t, "created Flaky test", result.Content[0].(mcp.TextContent).Text)
+
+	result = callTool(t, s, "get_issue", map[string]any{"id": 7})
+	assert.True(t, result.IsError)
+	assert.Equal(t, "404 Not Found: no such issue", result.Content[0].(mcp.TextContent).Text)
+}
+
+type testSession struct {
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *testSession) SessionID() string { return "session-1" }
+func (s *testSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+func (s *testSession) Initialize()       {}
+func (s *testSession) Initialized() bool { return true }
+
+func TestLoader_Watch(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses the echo command")
+	}
+	path := filepath.Join(t.TempDir(), "tools.yaml")
+	write := func(content string) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	write(`
+tools:
+  - name: greet
+    command:
+      args: ["echo", "hello {{.name}}"]
+  - name: other
+    command:
+      args: ["true"]
+`)
+
+	s := server.NewMCPServer("test-server", "1.0.0", server.WithToolCapabilities(true))
+	notifications := make(chan mcp.JSONRPCNotification, 10)
+	require.NoError(t, s.RegisterSession(context.Background(), &testSession{notifications: notifications}))
+
+	errs := make(chan error, 10)
+	loader := NewLoader(s, path, WithErrorHandler(func(err error) { errs <- err }))
+	require.NoError(t, loader.Load())
+	assert.Equal(t, []string{"greet", "other"}, loader.Tools())
+	result := callTool(t, s, "greet", map[string]any{"name": "world"})
+	assert.Equal(t, "hello world\n", result.Content[0].(mcp.TextContent).Text)
+	require.NotNil(t, s.GetTool("other"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = loader.Watch(ctx) }()
+	// Let Watch add its watch.
+	time.Sleep(30 * time.Millisecond)
+	drain := func() {
+		for len(notifications) > 0 {
+			<-notifications
+		}
+	}
+	drain()
+
+	write("tools:\n  - name: greet\n    command: {args: [\"echo\", \"hi {{.name}}\"]}\n")
+	require.Eventually(t, func() bool { return s.GetTool("other") == nil }, time.Second, 5*time.Millisecond)
+	result = callTool(t, s, "greet", map[string]any{"name": "world"})
+	assert.Equal(t, "hi world\n", result.Content[0].(mcp.TextContent).Text)
+	select {
+	case notification := <-notifications:
+		assert.Equal(t, mcp.MethodNotificationToolsListChanged, notification.Method)
+	case <-time.After(time.Second):
+		t.Fatal("no list_changed notification after reload")
+	}
+	select {
+	case notification := <-notifications:
+		t.Fatalf("reload sent a second notification: %s", notification.Method)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	write("tools: [{name: broken}]\n")
+	select {
+	case err := <-errs:
+		assert.Contains(t, err.Error(), "no command or http binding")
+	case <-time.After(time.Second):
+		t.Fatal("no reload error")
+	}
+	assert.NotNil(t, s.GetTool("greet"), "tools stay registered when a reload fails")
+}
+
+func TestEnvAllowlist(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses the sh command")
+	}
+	t.Setenv("MANIFEST_ALLOWED", "allowed")
+	t.Setenv("MANIFEST_SECRET", "secret")
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("X-Value")))
+	}))
+	defer upstream.Close()
+
+	m, err := Parse([]byte(`
+tools:
+  - name: print_env
+    command:
+      args: ["sh", "-c", 'echo "$MANIFEST_ALLOWED-$MANIFEST_SECRET"']
+  - name: send_allowed
+    http:
+      url: "` + upstream.URL + `"
+      headers:
+        X-Value: '{{env "MANIFEST_ALLOWED"}}'
+  - name: send_secret
+    http:
+      url: "` + upstream.URL + `"
+      headers:
+        X-Value: '{{env "MANIFEST_SECRET"}}'
+`))
+	require.NoError(t, err)
+	tools, err := m.ServerTools(upstream.Client(), "MANIFEST_ALLOWED")
+	require.NoError(t, err)
+	s := server.NewMCPServer("test-server", "1.0.0")
+	s.AddTools(tools...)
+
+	result := callTool(t, s, "print_env", nil)
+	assert.Equal(t, "allowed-\n", result.Content[0].(mcp.TextContent).Text)
+
+	result = callTool(t, s, "send_allowed", nil)
+	assert.Equal(t, "allowed", result.Content[0].(mcp.TextContent).Text)
+
+	result = callTool(t, s, "send_secret", nil)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `environment variable "MANIFEST_SECRET" is not allowed`)
+}
+
+func TestHTTPBinding_EscapesURL(t *testing.T) {
+	requests := make(chan *http.Request, 1)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- r
+	}))
+	defer upstream.Close()
+
+	tests := []struct {
+		name      string
+		url       string
+		args      map[string]any
+		wantPath  string
+		wantQuery url.Values
+	}{
+		{
+			name:     "path",
+			url:      "/items/{{.id}}",
+			args:     map[string]any{"id": "../admin?x="},
+			wantPath: "/items/../admin?x=",
+		},
+		{
+			name:      "query",
+			url:       "/search?q={{.q}}&limit={{.limit}}",
+			args:      map[string]any{"q": "a&admin=true", "limit": 10},
+			wantPath:  "/search",
+			wantQuery: url.Values{"q": {"a&admin=true"}, "limit": {"10"}},
+		},
+		{
+			name:      "explicit escaping",
+			url:       `/items/{{.id | pathescape}}?q={{urlquery .q}}`,
+			args:      map[string]any{"id": "a/b", "q": "c d"},
+			wantPath:  "/items/a/b",
+			wantQuery: url.Values{"q": {"c d"}},
+		},
+		{
+			name:     "conditional and missing values",
+			url:      `/items/{{if .id}}{{.id}}{{else}}all{{end}}/{{.missing}}`,
+			args:     map[string]any{"id": "x y"},
+			wantPath: "/items/x y/",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Manifest{Tools: []Tool{{Name: "get", HTTP: &HTTPBinding{URL: upstream.URL + tt.url}}}}
+			tools, err := m.ServerTools(upstream.Client())
+			require.NoError(t, err)
+			s := server.NewMCPServer("test-server", "1.0.0")
+			s.AddTools(tools...)
+
+			result := callTool(t, s, "get", tt.args)
+			require.False(t, result.IsError, "result %#v", result)
+			r := <-requests
+			// The path is decoded, so escaping shows as a single segment
+			assert.Equal(t, tt.wantPath, r.URL.Path)
+			assert.Equal(t, strings.Count(tt.url, "/"), strings.Count(r.URL.EscapedPath(), "/"))
+			if tt.wantQuery != nil {
+				assert.Equal(t, tt.wantQuery, r.URL.Query())
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package manifest
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// escapeFuncs escape the values inserted in URLs.
+var escapeFuncs = template.FuncMap{
+	"pathescape":  func(v any) string { return url.PathEscape(urlValue(v)) },
+	"queryescape": func(v any) string { return url.QueryEscape(urlValue(v)) },
+}
+
+func urlValue(v any) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// escapeURLTemplate makes the actions of tmpl escape what they print: as a
+// path segment before the "?" of the URL, and as a query value after it, so
+// that arguments cannot change the rest of the URL. Actions ending with
+// pathescape, queryescape or urlquery already escape their value and are
+// left as they are.
+func escapeURLTemplate(tmpl *template.Template) {
+	inQuery := false
+	var walk func(list *parse.ListNode)
+	walk = func(list *parse.ListNode) {
+		if list == nil {
+			return
+		}
+		for _, node := range list.Nodes {
+			switch node := node.(type) {
+			case *parse.TextNode:
+				if strings.ContainsAny(string(node.Text), "?#") {
+					inQuery = true
+				}
+			case *parse.ActionNode:
+				escaper := "pathescape"
+				if inQuery {
+					escaper = "queryescape"
+				}
+				escapePipe(node.Pipe, escaper)
+			case *parse.IfNode:
+				walk(node.List)
+				walk(node.ElseList)
+			case *parse.RangeNode:
+				walk(node.List)
+				walk(node.ElseList)
+			case *parse.WithNode:
+				walk(node.List)
+				walk(node.ElseList)
+			}
+		}
+	}
+	walk(tmpl.Tree.Root)
+}
+
+func escapePipe(pipe *parse.PipeNode, escaper string) {
+	if len(pipe.Decl) > 0 || len(pipe.Cmds) == 0 {
+		// Assignments print nothing
+		return
+	}
+	last := pipe.Cmds[len(pipe.Cmds)-1]
+	if ident, ok := last.Args[0].(*parse.IdentifierNode); ok {
+		switch ident.Ident {
+		case "pathescape", "queryescape", "urlquery":
+			return
+		}
+	}
+	pipe.Cmds = append(pipe.Cmds, &parse.CommandNode{
+		NodeType: parse.NodeCommand,
+		Pos:      last.Pos,
+		Args:     []parse.Node{parse.NewIdentifier(escaper).SetPos(last.Pos)},
+	})
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type propagatedMetaKey struct{}
+
+// WithMetaPropagation copies the given keys of each request's _meta field,
+// such as a correlation ID attached by the host, to the _meta field of its
+// result and of the notifications sent to the client while handling it.
+// Handlers can read the propagated fields with PropagatedMetaFromContext.
+// Fields set by the handler itself take precedence.
+func WithMetaPropagation(keys ...string) ServerOption {
+	return func(s *MCPServer) {
+		s.metaPropagation = append(s.metaPropagation, keys...)
+	}
+}
+
+// PropagatedMetaFromContext returns the _meta fields of the request being
+// handled that WithMetaPropagation propagates, or nil if there are none.
+// The map must not be modified.
+func PropagatedMetaFromContext(ctx context.Context) map[string]any {
+	fields, _ := ctx.Value(propagatedMetaKey{}).(map[string]any)
+	return fields
+}
+
+// withPropagatedMeta adds the propagated _meta fields of message to ctx.
+func (s *MCPServer) withPropagatedMeta(ctx context.Context, message json.RawMessage) context.Context {
+	if len(s.metaPropagation) == 0 {
+		return ctx
+	}
+	var request struct {
+		Params struct {
+			Meta map[string]any `json:"_meta"`
+		} `json:"params"`
+	}
+	// Params of other shapes carry no metadata.
+	_ = json.Unmarshal(message, &request)
+	var fields map[string]any
+	for _, key := range s.metaPropagation {
+		value, ok := request.Params.Meta[key]
+		if !ok {
+			continue
+		}
+		if fields == nil {
+			fields = make(map[string]any)
+		}
+		fields[key] = value
+	}
+	if fields == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, propagatedMetaKey{}, fields)
+}
+
+// propagateResultMeta adds the propagated _meta fields of ctx to *meta,
+// replacing it with a copy so that results shared between requests are left
+// untouched.
+func propagateResultMeta(ctx context.Context, meta **mcp.Meta) {
+	fields := PropagatedMetaFromContext(ctx)
+	if fields == nil {
+		return
+	}
+	propagated := (*meta).Clone()
+	if propagated == nil {
+		propagated = &mcp.Meta{}
+	}
+	for key, value := range fields {
+		if propagated.Get(key) == nil {
+			propagated.Set(key, value)
+		}
+	}
+	*meta = propagated
+}
+
+// propagateNotificationMeta adds the propagated _meta fields of ctx to
+// notification.
+func propagateNotificationMeta(ctx context.Context, notification mcp.JSONRPCNotification) mcp.JSONRPCNotification {
+	fields := PropagatedMetaFromContext(ctx)
+	if fields == nil {
+		return notification
+	}
+	meta := maps.Clone(fields)
+	maps.Copy(meta, notification.Params.Meta)
+	notification.Params.Meta = meta
+	return notification
+}
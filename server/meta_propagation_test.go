@@ -0,0 +1,87 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestMCPServer_WithMetaPropagation(t *testing.T) {
+	shared := mcp.NewToolResultText("shared")
+	server := NewMCPServer("test-server", "1.0.0",
+		WithToolCapabilities(false),
+		WithMetaPropagation("correlationId", "tenant"),
+	)
+	var handlerMeta map[string]any
+	server.AddTool(mcp.NewTool("notify"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		handlerMeta = PropagatedMetaFromContext(ctx)
+		require.NoError(t, server.SendNotificationToClient(ctx, "notifications/progress", map[string]any{"progressToken": 1, "progress": 1}))
+		return shared, nil
+	})
+	server.AddTool(mcp.NewTool("own"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result := mcp.NewToolResultText("own")
+		result.Meta = &mcp.Meta{}
+		result.Meta.Set("correlationId", "from-handler")
+		return result, nil
+	})
+
+	session := &fakeSession{sessionID: "session-1", notificationChannel: make(chan mcp.JSONRPCNotification, 1), initialized: true}
+	ctx := server.WithContext(context.Background(), session)
+	call := func(message string) map[string]any {
+		t.Helper()
+		response := server.HandleMessage(ctx, json.RawMessage(message))
+		data, err := json.Marshal(response)
+		require.NoError(t, err)
+		var decoded struct {
+			Result struct {
+				Meta map[string]any `json:"_meta"`
+			} `json:"result"`
+		}
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		return decoded.Result.Meta
+	}
+
+	t.Run("tool call", func(t *testing.T) {
+		meta := call(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"notify","_meta":{"correlationId":"abc","other":1}}}`)
+		assert.Equal(t, map[string]any{"correlationId": "abc"}, meta)
+		assert.Equal(t, map[string]any{"correlationId": "abc"}, handlerMeta)
+		assert.Nil(t, shared.Meta, "shared results are not modified")
+
+		require.Len(t, session.notificationChannel, 1)
+		notification := <-session.notificationChannel
+		assert.Equal(t, map[string]any{"correlationId": "abc"}, notification.Params.Meta)
+	})
+
+	t.Run("handler fields take precedence", func(t *testing.T) {
+		meta := call(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"own","_meta":{"correlationId":"abc","tenant":"t1"}}}`)
+		assert.Equal(t, map[string]any{"correlationId": "from-handler", "tenant": "t1"}, meta)
+	})
+
+	t.Run("empty result", func(t *testing.T) {
+		meta := call(`{"jsonrpc":"2.0","id":3,"method":"ping","params":{"_meta":{"tenant":"t1"}}}`)
+		assert.Equal(t, map[string]any{"tenant": "t1"}, meta)
+	})
+
+	t.Run("no propagated fields", func(t *testing.T) {
+		meta := call(`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"notify","_meta":{"other":1}}}`)
+		assert.Nil(t, meta)
+		assert.Nil(t, handlerMeta)
+		notification := <-session.notificationChannel
+		assert.Empty(t, notification.Params.Meta)
+	})
+}
+
+func TestMCPServer_WithoutMetaPropagation(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+	response := server.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"ping","params":{"_meta":{"correlationId":"abc"}}}`))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	result, ok := resp.Result.(mcp.EmptyResult)
+	require.True(t, ok)
+	assert.Nil(t, result.Meta)
+}
@@ -0,0 +1,351 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/yosida95/uritemplate/v3"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// mountKind identifies the registry of a sub-server that changed.
+type mountKind int
+
+const (
+	mountTools mountKind = iota
+	mountPrompts
+	mountResources
+)
+
+// mount is a sub-server mounted under a prefix of a parent server. It records
+// the names and URIs it registered on the parent so they can be replaced
+// when the sub-server changes.
+type mount struct {
+	// mu serializes syncs of the mount.
+	mu        sync.Mutex
+	parent    *MCPServer
+	sub       *MCPServer
+	prefix    string
+	tools     []string
+	prompts   []string
+	resources []string
+	templates []string
+}
+
+// Mount composes sub into s under prefix. The tools, prompts, resources and
+// resource templates of sub are listed by s with their names prefixed as
+// "prefix_name" and their URIs as "scheme://prefix/rest", so a tool "search"
+// mounted under "docs" is called as "docs_search" and a resource
+// "file:///readme.md" is read as "file://docs//readme.md". An empty prefix
+// merges sub into s without a namespace.
+//
+// Requests for mounted entries are served by sub, including its tool call
+// checks, rate limits and middlewares; the middlewares of s apply as well.
+// Contents returned by mounted resources have their URIs prefixed, and
+// resource updates notified by sub are forwarded to the subscribers of s.
+//
+// The mount is live: tools, prompts and resources added to or removed from
+// sub later are reflected in s, which notifies its clients when its lists
+// change. Only the server-wide registrations of sub are mounted, not
+// session-specific ones. Mounting another server under the same prefix
+// replaces the previous mount. A server must not be mounted into itself,
+// directly or indirectly.
+func (s *MCPServer) Mount(prefix string, sub *MCPServer) {
+	s.Unmount(prefix)
+
+	m := &mount{parent: s, sub: sub, prefix: prefix}
+	s.mountsMu.Lock()
+	if s.mounts == nil {
+		s.mounts = make(map[string]*mount)
+	}
+	s.mounts[prefix] = m
+	s.mountsMu.Unlock()
+
+	sub.mountsMu.Lock()
+	sub.mountedIn = append(sub.mountedIn, m)
+	sub.mountsMu.Unlock()
+
+	m.sync(mountTools)
+	m.sync(mountPrompts)
+	m.sync(mountResources)
+}
+
+// Unmount removes the server mounted under prefix, along with everything it
+// registered on s.
+func (s *MCPServer) Unmount(prefix string) {
+	s.mountsMu.Lock()
+	m, ok := s.mounts[prefix]
+	delete(s.mounts, prefix)
+	s.mountsMu.Unlock()
+	if !ok {
+		return
+	}
+
+	m.sub.mountsMu.Lock()
+	m.sub.mountedIn = slices.DeleteFunc(m.sub.mountedIn, func(other *mount) bool { return other == m })
+	m.sub.mountsMu.Unlock()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.tools) > 0 {
+		s.DeleteTools(m.tools...)
+	}
+	if len(m.prompts) > 0 {
+		s.DeletePrompts(m.prompts...)
+	}
+	if len(m.resources) > 0 {
+		s.DeleteResources(m.resources...)
+	}
+	if len(m.templates) > 0 {
+		s.DeleteResourceTemplates(m.templates...)
+	}
+	m.tools, m.prompts, m.resources, m.templates = nil, nil, nil, nil
+}
+
+// syncMounts re-registers a registry of s on every server s is mounted in.
+func (s *MCPServer) syncMounts(kind mountKind) {
+	s.mountsMu.RLock()
+	mounts := slices.Clone(s.mountedIn)
+	s.mountsMu.RUnlock()
+	for _, m := range mounts {
+		m.sync(kind)
+	}
+}
+
+// forwardResourceUpdated notifies the servers s is mounted in that the
+// resource uri of s was updated.
+func (s *MCPServer) forwardResourceUpdated(uri string) {
+	s.mountsMu.RLock()
+	mounts := slices.Clone(s.mountedIn)
+	s.mountsMu.RUnlock()
+	for _, m := range mounts {
+		m.parent.NotifyResourceUpdated(m.uri(uri))
+	}
+}
+
+// sync registers the current entries of a registry of the sub-server on the
+// parent and removes the ones that no longer exist.
+func (m *mount) sync(kind mountKind) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// A sync racing with Unmount must not register anything again.
+	m.parent.mountsMu.RLock()
+	mounted := m.parent.mounts[m.prefix] == m
+	m.parent.mountsMu.RUnlock()
+	if !mounted {
+		return
+	}
+
+	switch kind {
+	case mountTools:
+		m.syncTools()
+	case mountPrompts:
+		m.syncPrompts()
+	case mountResources:
+		m.syncResources()
+	}
+}
+
+func (m *mount) syncTools() {
+	tools := make([]ServerTool, 0, m.sub.tools.len())
+	m.sub.tools.each(func(name string, entry ServerTool) bool {
+		tool := entry.Tool
+		tool.Name = m.name(name)
+		tools = append(tools, ServerTool{Tool: tool, Handler: m.toolHandler(name)})
+		return true
+	})
+
+	names := make([]string, len(tools))
+	for i, tool := range tools {
+		names[i] = tool.Tool.Name
+	}
+	if stale := staleNames(m.tools, names); len(stale) > 0 {
+		m.parent.DeleteTools(stale...)
+	}
+	m.tools = names
+	if len(tools) > 0 {
+		m.parent.AddTools(tools...)
+	}
+}
+
+func (m *mount) syncPrompts() {
+	prompts := make([]ServerPrompt, 0, m.sub.prompts.len())
+	m.sub.prompts.each(func(name string, entry ServerPrompt) bool {
+		prompt := entry.Prompt
+		prompt.Name = m.name(name)
+		prompts = append(prompts, ServerPrompt{Prompt: prompt, Handler: m.promptHandler(name)})
+		return true
+	})
+
+	names := make([]string, len(prompts))
+	for i, prompt := range prompts {
+		names[i] = prompt.Prompt.Name
+	}
+	if stale := staleNames(m.prompts, names); len(stale) > 0 {
+		m.parent.DeletePrompts(stale...)
+	}
+	m.prompts = names
+	if len(prompts) > 0 {
+		m.parent.AddPrompts(prompts...)
+	}
+}
+
+func (m *mount) syncResources() {
+	resources := make([]ServerResource, 0, m.sub.resources.len())
+	m.sub.resources.each(func(uri string, entry resourceEntry) bool {
+		resource := entry.resource
+		resource.URI = m.uri(uri)
+		resources = append(resources, ServerResource{Resource: resource, Handler: m.resourceHandler(uri)})
+		return true
+	})
+	templates := make([]ServerResourceTemplate, 0, m.sub.resourceTemplates.len())
+	m.sub.resourceTemplates.each(func(raw string, entry resourceTemplateEntry) bool {
+		prefixed, err := uritemplate.New(m.uri(raw))
+		if err != nil {
+			return true
+		}
+		template := entry.template
+		template.URITemplate = &mcp.URITemplate{Template: prefixed}
+		templates = append(templates, ServerResourceTemplate{
+			Template: template,
+			Handler:  ResourceTemplateHandlerFunc(m.resourceHandler("")),
+		})
+		return true
+	})
+
+	uris := make([]string, len(resources))
+	for i, resource := range resources {
+		uris[i] = resource.Resource.URI
+	}
+	if stale := staleNames(m.resources, uris); len(stale) > 0 {
+		m.parent.DeleteResources(stale...)
+	}
+	m.resources = uris
+	if len(resources) > 0 {
+		m.parent.AddResources(resources...)
+	}
+
+	raws := make([]string, len(templates))
+	for i, template := range templates {
+		raws[i] = template.Template.URITemplate.Raw()
+	}
+	if stale := staleNames(m.templates, raws); len(stale) > 0 {
+		m.parent.DeleteResourceTemplates(stale...)
+	}
+	m.templates = raws
+	if len(templates) > 0 {
+		m.parent.AddResourceTemplates(templates...)
+	}
+}
+
+// toolHandler returns a handler calling the tool name of the sub-server.
+func (m *mount) toolHandler(name string) ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		request.Params.Name = name
+		result, reqErr := m.sub.handleToolCall(ctx, nil, request)
+		if reqErr != nil {
+			return nil, mountedError(reqErr)
+		}
+		return result, nil
+	}
+}
+
+// promptHandler returns a handler getting the prompt name of the sub-server.
+func (m *mount) promptHandler(name string) PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		request.Params.Name = name
+		result, reqErr := m.sub.handleGetPrompt(ctx, nil, request)
+		if reqErr != nil {
+			return nil, mountedError(reqErr)
+		}
+		return result, nil
+	}
+}
+
+// resourceHandler returns a handler reading a resource of the sub-server.
+// The resource read is uri or, if uri is empty, the requested URI with the
+// prefix removed, as for resource templates.
+func (m *mount) resourceHandler(uri string) ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		if uri != "" {
+			request.Params.URI = uri
+		} else {
+			request.Params.URI = m.unprefixURI(request.Params.URI)
+		}
+		result, reqErr := m.sub.handleReadResource(ctx, nil, request)
+		if reqErr != nil {
+			return nil, mountedError(reqErr)
+		}
+		for i, content := range result.Contents {
+			switch c := content.(type) {
+			case mcp.TextResourceContents:
+				c.URI = m.uri(c.URI)
+				result.Contents[i] = c
+			case mcp.BlobResourceContents:
+				c.URI = m.uri(c.URI)
+				result.Contents[i] = c
+			}
+		}
+		return result.Contents, nil
+	}
+}
+
+// name returns the parent name of a tool or prompt of the sub-server.
+func (m *mount) name(name string) string {
+	if m.prefix == "" {
+		return name
+	}
+	return m.prefix + "_" + name
+}
+
+// uri returns the parent URI of a resource of the sub-server, inserting the
+// prefix as the first path segment after the scheme.
+func (m *mount) uri(uri string) string {
+	if m.prefix == "" {
+		return uri
+	}
+	if scheme, rest, ok := strings.Cut(uri, "://"); ok {
+		return scheme + "://" + m.prefix + "/" + rest
+	}
+	return m.prefix + "/" + uri
+}
+
+// unprefixURI reverses uri.
+func (m *mount) unprefixURI(uri string) string {
+	if m.prefix == "" {
+		return uri
+	}
+	if scheme, rest, ok := strings.Cut(uri, "://"); ok {
+		if rest, ok := strings.CutPrefix(rest, m.prefix+"/"); ok {
+			return scheme + "://" + rest
+		}
+		return uri
+	}
+	return strings.TrimPrefix(uri, m.prefix+"/")
+}
+
+// mountedError converts an error of the sub-server to a handler error that
+// keeps its JSON-RPC code and data when returned by the parent.
+func mountedError(reqErr *requestError) error {
+	var mcpErr *mcp.Error
+	if errors.As(reqErr.err, &mcpErr) {
+		return reqErr.err
+	}
+	return mcp.NewError(reqErr.code, reqErr.err.Error(), reqErr.data)
+}
+
+// staleNames returns the entries of previous missing from current.
+func staleNames(previous, current []string) []string {
+	var stale []string
+	for _, name := range previous {
+		if !slices.Contains(current, name) {
+			stale = append(stale, name)
+		}
+	}
+	return stale
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func newMountedSubServer() *MCPServer {
+	sub := NewMCPServer("docs", "1.0.0")
+	sub.AddTool(mcp.NewTool("search"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("searched " + request.Params.Name), nil
+	})
+	sub.AddTool(mcp.NewTool("fail"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, mcp.NewError(-32001, "quota exceeded", nil)
+	})
+	sub.AddPrompt(mcp.NewPrompt("summarize"), func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return mcp.NewGetPromptResult("summarize "+request.Params.Name, nil), nil
+	})
+	sub.AddResource(mcp.NewResource("file:///readme.md", "readme"), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: "# docs"}}, nil
+	})
+	sub.AddResourceTemplate(mcp.NewResourceTemplate("pages://{id}", "page"), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: "page"}}, nil
+	})
+	return sub
+}
+
+func handleMounted(t *testing.T, server *MCPServer, method, params string) mcp.JSONRPCMessage {
+	t.Helper()
+	return server.HandleMessage(context.Background(), json.RawMessage(
+		`{"jsonrpc":"2.0","id":1,"method":"`+method+`","params":`+params+`}`))
+}
+
+func TestMCPServer_Mount(t *testing.T) {
+	parent := NewMCPServer("app", "1.0.0")
+	parent.AddTool(mcp.NewTool("status"), echoToolHandler)
+	parent.Mount("docs", newMountedSubServer())
+
+	tools := parent.ListTools()
+	assert.Contains(t, tools, "status")
+	assert.Contains(t, tools, "docs_search")
+	assert.Contains(t, tools, "docs_fail")
+	assert.NotContains(t, tools, "search")
+
+	tests := []struct {
+		name         string
+		method       string
+		params       string
+		expected     any
+		expectedCode int
+	}{
+		{name: "tool", method: "tools/call", params: `{"name":"docs_search"}`, expected: "searched search"},
+		{name: "tool error keeps its code", method: "tools/call", params: `{"name":"docs_fail"}`, expectedCode: -32001},
+		{name: "prompt", method: "prompts/get", params: `{"name":"docs_summarize"}`, expected: "summarize summarize"},
+		{name: "resource", method: "resources/read", params: `{"uri":"file://docs//readme.md"}`, expected: "file://docs//readme.md"},
+		{name: "resource template", method: "resources/read", params: `{"uri":"pages://docs/7"}`, expected: "pages://docs/7"},
+		{name: "unprefixed resource", method: "resources/read", params: `{"uri":"file:///readme.md"}`, expectedCode: mcp.RESOURCE_NOT_FOUND},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := handleMounted(t, parent, tt.method, tt.params)
+			if tt.expectedCode != 0 {
+				errorResponse, ok := response.(mcp.JSONRPCError)
+				require.True(t, ok, "expected error response, got %#v", response)
+				assert.Equal(t, tt.expectedCode, errorResponse.Error.Code)
+				return
+			}
+			resp, ok := response.(mcp.JSONRPCResponse)
+			require.True(t, ok, "expected success response, got %#v", response)
+			switch result := resp.Result.(type) {
+			case mcp.CallToolResult:
+				assert.Equal(t, tt.expected, result.Content[0].(mcp.TextContent).Text)
+			case mcp.GetPromptResult:
+				assert.Equal(t, tt.expected, result.Description)
+			case mcp.ReadResourceResult:
+				assert.Equal(t, tt.expected, result.Contents[0].(mcp.TextResourceContents).URI)
+			default:
+				t.Fatalf("unexpected result %#v", result)
+			}
+		})
+	}
+}
+
+func TestMCPServer_MountIsLive(t *testing.T) {
+	parent := NewMCPServer("app", "1.0.0")
+	sub := newMountedSubServer()
+	parent.Mount("docs", sub)
+
+	sub.AddTool(mcp.NewTool("index"), echoToolHandler)
+	sub.DeleteTools("fail")
+	sub.DeletePrompts("summarize")
+	sub.RemoveResource("file:///readme.md")
+
+	tools := parent.ListTools()
+	assert.Contains(t, tools, "docs_index")
+	assert.NotContains(t, tools, "docs_fail")
+
+	response := handleMounted(t, parent, "prompts/get", `{"name":"docs_summarize"}`)
+	_, ok := response.(mcp.JSONRPCError)
+	assert.True(t, ok, "deleted prompts are removed from the parent")
+	response = handleMounted(t, parent, "resources/read", `{"uri":"file://docs//readme.md"}`)
+	_, ok = response.(mcp.JSONRPCError)
+	assert.True(t, ok, "deleted resources are removed from the parent")
+
+	parent.Unmount("docs")
+	assert.Empty(t, parent.ListTools())
+	sub.AddTool(mcp.NewTool("again"), echoToolHandler)
+	assert.Empty(t, parent.ListTools(), "unmounted servers are no longer synced")
+}
+
+func TestMCPServer_MountNested(t *testing.T) {
+	root := NewMCPServer("root", "1.0.0")
+	middle := NewMCPServer("middle", "1.0.0")
+	leaf := NewMCPServer("leaf", "1.0.0")
+	root.Mount("a", middle)
+	middle.Mount("b", leaf)
+	leaf.AddTool(mcp.NewTool("ping"), echoToolHandler)
+
+	assert.Contains(t, root.ListTools(), "a_b_ping")
+	response := handleMounted(t, root, "tools/call", `{"name":"a_b_ping"}`)
+	_, ok := response.(mcp.JSONRPCResponse)
+	assert.True(t, ok, "expected success response, got %#v", response)
+}
+
+func TestMCPServer_MountForwardsResourceUpdates(t *testing.T) {
+	parent := NewMCPServer("app", "1.0.0", WithResourceCapabilities(true, false))
+	sub := newMountedSubServer()
+	parent.Mount("docs", sub)
+
+	session := &fakeSession{
+		sessionID:           "session-1",
+		notificationChannel: make(chan mcp.JSONRPCNotification, 1),
+		initialized:         true,
+	}
+	require.NoError(t, parent.RegisterSession(context.Background(), session))
+	ctx := parent.WithContext(context.Background(), session)
+	response := parent.HandleMessage(ctx, json.RawMessage(
+		`{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"file://docs//readme.md"}}`))
+	_, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "expected success response, got %#v", response)
+
+	sub.NotifyResourceUpdated("file:///readme.md")
+	select {
+	case notification := <-session.notificationChannel:
+		assert.Equal(t, mcp.MethodNotificationResourceUpdated, notification.Method)
+		assert.Equal(t, "file://docs//readme.md", notification.Params.AdditionalFields["uri"])
+	default:
+		t.Fatal("expected a forwarded resource update")
+	}
+}
+
+func TestMountedError(t *testing.T) {
+	data := mcp.NotFoundErrorData{Type: "tool", Name: "search"}
+	err := mountedError(&requestError{code: mcp.INVALID_PARAMS, err: ErrToolNotFound, data: data})
+	var mcpErr *mcp.Error
+	require.True(t, errors.As(err, &mcpErr))
+	assert.Equal(t, mcp.INVALID_PARAMS, mcpErr.Code)
+	assert.Equal(t, ErrToolNotFound.Error(), mcpErr.Message)
+	assert.Equal(t, data, mcpErr.Data)
+}
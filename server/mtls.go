@@ -0,0 +1,124 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// ClientCertificate describes the verified certificate a client presented
+// over mutual TLS.
+type ClientCertificate struct {
+	// CommonName is the common name of the certificate's subject.
+	CommonName string
+	// DNSNames, EmailAddresses and URIs are the subject alternative names
+	// of the certificate.
+	DNSNames       []string
+	EmailAddresses []string
+	URIs           []string
+	// Certificate is the certificate itself, for anything not above.
+	Certificate *x509.Certificate
+}
+
+// Identity returns the first URI, DNS name or email address of the
+// certificate, in that order, or its common name if it has none.
+func (c ClientCertificate) Identity() string {
+	for _, names := range [][]string{c.URIs, c.DNSNames, c.EmailAddresses} {
+		if len(names) > 0 {
+			return names[0]
+		}
+	}
+	return c.CommonName
+}
+
+// ClientCertificateFromContext returns the client certificate of the HTTP
+// request carrying the message being handled. Only certificates verified
+// against the client certificate authorities of the server are returned,
+// so this reports false for requests without TLS, without a certificate,
+// or served with a tls.Config that does not verify client certificates.
+func ClientCertificateFromContext(ctx context.Context) (ClientCertificate, bool) {
+	r, ok := HTTPRequestFromContext(ctx)
+	if !ok {
+		return ClientCertificate{}, false
+	}
+	return verifiedClientCertificate(r)
+}
+
+// ClientCertificateAuthFunc returns an AuthFunc accepting requests with a
+// verified client certificate. The AuthInfo has the certificate's Identity
+// as its Subject and the ClientCertificate in Extra under
+// "client_certificate". Requests without one are rejected with
+// ErrUnauthorized.
+func ClientCertificateAuthFunc() AuthFunc {
+	return func(r *http.Request) (AuthInfo, error) {
+		cert, ok := verifiedClientCertificate(r)
+		if !ok {
+			return AuthInfo{}, fmt.Errorf("no verified client certificate: %w", ErrUnauthorized)
+		}
+		return AuthInfo{
+			Subject:   cert.Identity(),
+			ExpiresAt: cert.Certificate.NotAfter,
+			Extra:     map[string]any{"client_certificate": cert},
+		}, nil
+	}
+}
+
+func verifiedClientCertificate(r *http.Request) (ClientCertificate, bool) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return ClientCertificate{}, false
+	}
+	cert := r.TLS.VerifiedChains[0][0]
+	uris := make([]string, len(cert.URIs))
+	for i, uri := range cert.URIs {
+		uris[i] = uri.String()
+	}
+	return ClientCertificate{
+		CommonName:     cert.Subject.CommonName,
+		DNSNames:       cert.DNSNames,
+		EmailAddresses: cert.EmailAddresses,
+		URIs:           uris,
+		Certificate:    cert,
+	}, true
+}
+
+// requireClientCertificates returns a copy of config, which may be nil,
+// rejecting clients without a certificate signed by one of clientCAs.
+func requireClientCertificates(config *tls.Config, clientCAs *x509.CertPool) *tls.Config {
+	if config == nil {
+		config = &tls.Config{}
+	} else {
+		config = config.Clone()
+	}
+	config.ClientAuth = tls.RequireAndVerifyClientCert
+	config.ClientCAs = clientCAs
+	return config
+}
+
+// listenAndServeTLS starts srv with TLS if certFile and keyFile or config
+// are set, and without otherwise.
+func listenAndServeTLS(srv *http.Server, certFile, keyFile string, config *tls.Config) error {
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return fmt.Errorf("both TLS cert and key must be provided")
+		}
+		if _, err := os.Stat(certFile); err != nil {
+			return fmt.Errorf("failed to find TLS certificate file: %w", err)
+		}
+		if _, err := os.Stat(keyFile); err != nil {
+			return fmt.Errorf("failed to find TLS key file: %w", err)
+		}
+	}
+	if config != nil {
+		srv.TLSConfig = config
+	}
+	if certFile == "" && config == nil {
+		return srv.ListenAndServe()
+	}
+	if certFile == "" && len(config.Certificates) == 0 && config.GetCertificate == nil && config.GetConfigForClient == nil {
+		return fmt.Errorf("TLS requires a certificate: set the TLS cert and key or the Certificates of the TLS config")
+	}
+	return srv.ListenAndServeTLS(certFile, keyFile)
+}
@@ -0,0 +1,276 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPKI is a certificate authority with a server and a client certificate
+// signed by it.
+type testPKI struct {
+	pool   *x509.CertPool
+	server tls.Certificate
+	client tls.Certificate
+}
+
+func newTestPKI(t *testing.T) testPKI {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, ca, ca, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err = x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	issue := func(serial int64, template *x509.Certificate) tls.Certificate {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		template.SerialNumber = big.NewInt(serial)
+		template.NotBefore = time.Now().Add(-time.Hour)
+		template.NotAfter = time.Now().Add(time.Hour)
+		template.KeyUsage = x509.KeyUsageDigitalSignature
+		der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+	}
+	spiffe, err := url.Parse("spiffe://example.org/agent-1")
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	return testPKI{
+		pool: pool,
+		server: issue(2, &x509.Certificate{
+			Subject:     pkix.Name{CommonName: "localhost"},
+			IPAddresses: []net.IP{net.IPv4(127, 0, 0, 1)},
+			ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		}),
+		client: issue(3, &x509.Certificate{
+			Subject:        pkix.Name{CommonName: "agent-1"},
+			URIs:           []*url.URL{spiffe},
+			EmailAddresses: []string{"agent-1@example.org"},
+			ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		}),
+	}
+}
+
+// httpsClient returns a client trusting pool and presenting certs.
+func httpsClient(pool *x509.CertPool, certs ...tls.Certificate) *http.Client {
+	return &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: pool, Certificates: certs},
+		},
+	}
+}
+
+// startTLS runs start on a free local address and waits until it accepts
+// TLS connections. It returns the address.
+func startTLS(t *testing.T, start func(addr string) error) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	errs := make(chan error, 1)
+	go func() { errs <- start(addr) }()
+	require.Eventually(t, func() bool {
+		select {
+		case err := <-errs:
+			require.NoError(t, err)
+		default:
+		}
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+	return addr
+}
+
+func TestClientCertificateFromContext(t *testing.T) {
+	cert := &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "agent-1"},
+		DNSNames: []string{"agent-1.example.org"},
+	}
+
+	tests := []struct {
+		name         string
+		state        *tls.ConnectionState
+		wantOK       bool
+		wantIdentity string
+	}{
+		{name: "no TLS"},
+		{name: "unverified certificate", state: &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}},
+		{
+			name:         "verified certificate",
+			state:        &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}, VerifiedChains: [][]*x509.Certificate{{cert}}},
+			wantOK:       true,
+			wantIdentity: "agent-1.example.org",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodPost, "https://localhost/mcp", nil)
+			require.NoError(t, err)
+			r.TLS = tt.state
+
+			got, ok := ClientCertificateFromContext(WithHTTPRequest(context.Background(), r))
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantIdentity, got.Identity())
+			if ok {
+				assert.Equal(t, "agent-1", got.CommonName)
+				assert.Same(t, cert, got.Certificate)
+			}
+		})
+	}
+
+	_, ok := ClientCertificateFromContext(context.Background())
+	assert.False(t, ok)
+}
+
+func TestClientCertificate_Identity(t *testing.T) {
+	tests := []struct {
+		name string
+		cert ClientCertificate
+		want string
+	}{
+		{name: "common name", cert: ClientCertificate{CommonName: "cn"}, want: "cn"},
+		{name: "email", cert: ClientCertificate{CommonName: "cn", EmailAddresses: []string{"a@example.org"}}, want: "a@example.org"},
+		{name: "DNS name", cert: ClientCertificate{CommonName: "cn", DNSNames: []string{"a.example.org"}, EmailAddresses: []string{"a@example.org"}}, want: "a.example.org"},
+		{name: "URI", cert: ClientCertificate{CommonName: "cn", DNSNames: []string{"a.example.org"}, URIs: []string{"spiffe://example.org/a"}}, want: "spiffe://example.org/a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.cert.Identity())
+		})
+	}
+}
+
+func TestStreamableHTTPServer_ClientCAs(t *testing.T) {
+	pki := newTestPKI(t)
+	mcpServer := NewMCPServer("test", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("whoami"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		cert, ok := ClientCertificateFromContext(ctx)
+		if !ok {
+			return mcp.NewToolResultError("no client certificate"), nil
+		}
+		auth, _ := AuthInfoFromContext(ctx)
+		return mcp.NewToolResultText(cert.Identity() + " " + cert.CommonName + " " + auth.Subject), nil
+	})
+	server := NewStreamableHTTPServer(mcpServer,
+		WithStateLess(true),
+		WithClientCAs(pki.pool),
+		WithTLSConfig(&tls.Config{Certificates: []tls.Certificate{pki.server}, MinVersion: tls.VersionTLS12}),
+		WithAuthFunc(ClientCertificateAuthFunc()),
+	)
+	addr := startTLS(t, server.Start)
+	defer server.Shutdown(context.Background())
+	endpoint := "https://" + addr + "/mcp"
+
+	call := func(client *http.Client) (*http.Response, error) {
+		body, err := json.Marshal(map[string]any{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"method":  "tools/call",
+			"params":  map[string]any{"name": "whoami"},
+		})
+		require.NoError(t, err)
+		return client.Post(endpoint, "application/json", bytes.NewReader(body))
+	}
+
+	t.Run("client certificate", func(t *testing.T) {
+		resp, err := call(httpsClient(pki.pool, pki.client))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var response struct {
+			Result mcp.CallToolResult `json:"result"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		require.False(t, response.Result.IsError)
+		require.Len(t, response.Result.Content, 1)
+		assert.Equal(t, "spiffe://example.org/agent-1 agent-1 spiffe://example.org/agent-1",
+			response.Result.Content[0].(mcp.TextContent).Text)
+	})
+
+	t.Run("no client certificate", func(t *testing.T) {
+		_, err := call(httpsClient(pki.pool))
+		assert.Error(t, err)
+	})
+}
+
+func TestSSEServer_ClientCAs(t *testing.T) {
+	pki := newTestPKI(t)
+	server := NewSSEServer(NewMCPServer("test", "1.0.0"),
+		WithSSETLSConfig(&tls.Config{Certificates: []tls.Certificate{pki.server}}),
+		WithSSEClientCAs(pki.pool),
+	)
+	addr := startTLS(t, server.Start)
+	defer server.Shutdown(context.Background())
+	endpoint := "https://" + addr + "/sse"
+
+	resp, err := httpsClient(pki.pool, pki.client).Get(endpoint)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(line, "event: endpoint"), line)
+
+	_, err = httpsClient(pki.pool).Get(endpoint)
+	assert.Error(t, err)
+}
+
+func TestListenAndServeTLS_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		certFile string
+		keyFile  string
+		config   *tls.Config
+		wantErr  string
+	}{
+		{name: "key without cert", keyFile: "key.pem", wantErr: "both TLS cert and key must be provided"},
+		{name: "missing cert file", certFile: "/nonexistent/cert.pem", keyFile: "/nonexistent/key.pem", wantErr: "failed to find TLS certificate file"},
+		{name: "config without certificate", config: &tls.Config{}, wantErr: "TLS requires a certificate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := listenAndServeTLS(&http.Server{Addr: "127.0.0.1:0"}, tt.certFile, tt.keyFile, tt.config)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
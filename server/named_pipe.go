@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+)
+
+// NamedPipeOption configures ServeNamedPipe.
+type NamedPipeOption func(*namedPipeConfig)
+
+type namedPipeConfig struct {
+	securityDescriptor string
+	stdioOptions       []StdioOption
+}
+
+// WithPipeSecurityDescriptor restricts access to the pipe with a security
+// descriptor in SDDL form, for example "D:P(A;;GA;;;OW)" to grant access to
+// the owner only. Without it, the pipe gets the default security descriptor
+// of the process, which lets everyone read from it. Remote clients are
+// always rejected.
+func WithPipeSecurityDescriptor(sddl string) NamedPipeOption {
+	return func(c *namedPipeConfig) {
+		c.securityDescriptor = sddl
+	}
+}
+
+// WithPipeStdioOptions sets the options of the StdioServer serving each
+// pipe connection.
+func WithPipeStdioOptions(opts ...StdioOption) NamedPipeOption {
+	return func(c *namedPipeConfig) {
+		c.stdioOptions = append(c.stdioOptions, opts...)
+	}
+}
+
+// pipeListener accepts the connections of a named pipe.
+type pipeListener interface {
+	Accept() (io.ReadWriteCloser, error)
+	Close() error
+}
+
+// ServeNamedPipe serves server over the Windows named pipe name, which is
+// either a full pipe path such as \\.\pipe\my-server or just the final
+// component of one. It lets desktop hosts connect to a running server
+// instead of spawning it for stdio. Like stdio, the pipe serves one client
+// at a time with the stdio framing; clients connecting while another one is
+// served wait for the pipe to become available.
+//
+// ServeNamedPipe runs until ctx is done. On other platforms it returns
+// ErrUnsupported.
+func ServeNamedPipe(ctx context.Context, server *MCPServer, name string, opts ...NamedPipeOption) error {
+	config := &namedPipeConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	listener, err := listenNamedPipe(pipePath(name), config)
+	if err != nil {
+		return err
+	}
+	return servePipe(ctx, server, listener, config)
+}
+
+// servePipe serves the connections accepted by listener one after the
+// other until ctx is done.
+func servePipe(ctx context.Context, server *MCPServer, listener pipeListener, config *namedPipeConfig) error {
+	stop := context.AfterFunc(ctx, func() { _ = listener.Close() })
+	defer stop()
+	defer listener.Close()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to accept pipe connection: %w", err)
+		}
+
+		stdio := NewStdioServer(server)
+		for _, opt := range config.stdioOptions {
+			opt(stdio)
+		}
+		err = stdio.Listen(ctx, conn, conn)
+		_ = conn.Close()
+		if err != nil && ctx.Err() == nil {
+			stdio.errLogger.Printf("Pipe connection closed: %v", err)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// pipePath expands a pipe name to its full path.
+func pipePath(name string) string {
+	if strings.HasPrefix(name, `\\`) {
+		return name
+	}
+	return `\\.\pipe\` + name
+}
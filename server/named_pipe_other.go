@@ -0,0 +1,9 @@
+//go:build !windows
+
+package server
+
+import "fmt"
+
+func listenNamedPipe(path string, config *namedPipeConfig) (pipeListener, error) {
+	return nil, fmt.Errorf("named pipe %s: %w", path, ErrUnsupported)
+}
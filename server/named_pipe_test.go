@@ -0,0 +1,97 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePipeListener hands out the server ends of in-memory connections.
+type fakePipeListener struct {
+	conns  chan net.Conn
+	closed chan struct{}
+}
+
+func newFakePipeListener() *fakePipeListener {
+	return &fakePipeListener{conns: make(chan net.Conn), closed: make(chan struct{})}
+}
+
+func (l *fakePipeListener) Accept() (io.ReadWriteCloser, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *fakePipeListener) Close() error {
+	select {
+	case <-l.closed:
+	default:
+		close(l.closed)
+	}
+	return nil
+}
+
+// dial connects a client to the listener.
+func (l *fakePipeListener) dial() net.Conn {
+	client, server := net.Pipe()
+	l.conns <- server
+	return client
+}
+
+func TestServePipe(t *testing.T) {
+	mcpServer := NewMCPServer("test-server", "1.0.0")
+	listener := newFakePipeListener()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- servePipe(ctx, mcpServer, listener, &namedPipeConfig{})
+	}()
+
+	// Clients are served one after the other.
+	for i := 0; i < 2; i++ {
+		conn := listener.dial()
+		_, err := conn.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}` + "\n"))
+		require.NoError(t, err)
+
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		require.NoError(t, err)
+		var response map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &response))
+		assert.Equal(t, float64(1), response["id"])
+		assert.Contains(t, response, "result")
+		require.NoError(t, conn.Close())
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(2 * time.Second):
+		t.Fatal("servePipe did not return after the context was cancelled")
+	}
+}
+
+func TestServeNamedPipe_Unsupported(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("named pipes are supported on Windows")
+	}
+	err := ServeNamedPipe(context.Background(), NewMCPServer("test-server", "1.0.0"), "mcp-test")
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestPipePath(t *testing.T) {
+	assert.Equal(t, `\\.\pipe\mcp-server`, pipePath("mcp-server"))
+	assert.Equal(t, `\\server\pipe\mcp`, pipePath(`\\server\pipe\mcp`))
+}
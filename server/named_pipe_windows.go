@@ -0,0 +1,172 @@
+//go:build windows
+
+package server
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	modkernel32 = syscall.NewLazyDLL("kernel32.dll")
+	modadvapi32 = syscall.NewLazyDLL("advapi32.dll")
+
+	procCreateNamedPipeW = modkernel32.NewProc("CreateNamedPipeW")
+	procConnectNamedPipe = modkernel32.NewProc("ConnectNamedPipe")
+	procLocalFree        = modkernel32.NewProc("LocalFree")
+
+	procConvertStringSecurityDescriptorToSecurityDescriptorW = modadvapi32.NewProc("ConvertStringSecurityDescriptorToSecurityDescriptorW")
+)
+
+const (
+	pipeAccessDuplex          = 0x00000003
+	fileFlagFirstPipeInstance = 0x00080000
+	pipeRejectRemoteClients   = 0x00000008
+	pipeUnlimitedInstances    = 255
+	pipeBufferSize            = 64 * 1024
+	sddlRevision1             = 1
+
+	errorPipeConnected syscall.Errno = 535
+)
+
+// windowsPipeListener creates the instances of a named pipe. One instance
+// is kept pending so that a client connecting while another one is served
+// is queued instead of refused.
+type windowsPipeListener struct {
+	path               string
+	securityAttributes *syscall.SecurityAttributes
+	securityDescriptor uintptr
+
+	mu      sync.Mutex
+	pending syscall.Handle
+	closed  bool
+}
+
+func listenNamedPipe(path string, config *namedPipeConfig) (pipeListener, error) {
+	l := &windowsPipeListener{path: path}
+	if config.securityDescriptor != "" {
+		sddl, err := syscall.UTF16PtrFromString(config.securityDescriptor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid security descriptor: %w", err)
+		}
+		r, _, err := procConvertStringSecurityDescriptorToSecurityDescriptorW.Call(
+			uintptr(unsafe.Pointer(sddl)), sddlRevision1, uintptr(unsafe.Pointer(&l.securityDescriptor)), 0)
+		if r == 0 {
+			return nil, fmt.Errorf("invalid security descriptor %q: %w", config.securityDescriptor, err)
+		}
+		l.securityAttributes = &syscall.SecurityAttributes{
+			Length:             uint32(unsafe.Sizeof(syscall.SecurityAttributes{})),
+			SecurityDescriptor: l.securityDescriptor,
+		}
+	}
+
+	// Creating the first instance right away reports a pipe name already in
+	// use and lets clients connect before Accept is called.
+	h, err := l.createInstance(true)
+	if err != nil {
+		l.free()
+		return nil, err
+	}
+	l.pending = h
+	return l, nil
+}
+
+func (l *windowsPipeListener) createInstance(first bool) (syscall.Handle, error) {
+	path, err := syscall.UTF16PtrFromString(l.path)
+	if err != nil {
+		return syscall.InvalidHandle, fmt.Errorf("invalid pipe name %q: %w", l.path, err)
+	}
+	openMode := uintptr(pipeAccessDuplex)
+	if first {
+		openMode |= fileFlagFirstPipeInstance
+	}
+	r, _, err := procCreateNamedPipeW.Call(
+		uintptr(unsafe.Pointer(path)),
+		openMode,
+		pipeRejectRemoteClients, // byte mode, blocking
+		pipeUnlimitedInstances,
+		pipeBufferSize,
+		pipeBufferSize,
+		0,
+		uintptr(unsafe.Pointer(l.securityAttributes)),
+	)
+	if h := syscall.Handle(r); h != syscall.InvalidHandle {
+		return h, nil
+	}
+	return syscall.InvalidHandle, fmt.Errorf("failed to create named pipe %s: %w", l.path, err)
+}
+
+func (l *windowsPipeListener) Accept() (io.ReadWriteCloser, error) {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil, net.ErrClosed
+	}
+	h := l.pending
+	l.pending = syscall.InvalidHandle
+	if h == syscall.InvalidHandle {
+		var err error
+		if h, err = l.createInstance(false); err != nil {
+			l.mu.Unlock()
+			return nil, err
+		}
+	}
+	l.mu.Unlock()
+
+	r, _, err := procConnectNamedPipe.Call(uintptr(h), 0)
+	if r == 0 && err != errorPipeConnected {
+		_ = syscall.CloseHandle(h)
+		return nil, fmt.Errorf("failed to connect named pipe %s: %w", l.path, err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		_ = syscall.CloseHandle(h)
+		return nil, net.ErrClosed
+	}
+	if next, err := l.createInstance(false); err == nil {
+		l.pending = next
+	}
+	return os.NewFile(uintptr(h), l.path), nil
+}
+
+// Close stops accepting connections. A pending Accept is woken up by
+// connecting to the pipe.
+func (l *windowsPipeListener) Close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	l.mu.Unlock()
+
+	if path, err := syscall.UTF16PtrFromString(l.path); err == nil {
+		h, err := syscall.CreateFile(path, syscall.GENERIC_READ|syscall.GENERIC_WRITE, 0, nil, syscall.OPEN_EXISTING, 0, 0)
+		if err == nil {
+			_ = syscall.CloseHandle(h)
+		}
+	}
+
+	l.mu.Lock()
+	if l.pending != syscall.InvalidHandle {
+		_ = syscall.CloseHandle(l.pending)
+		l.pending = syscall.InvalidHandle
+	}
+	l.mu.Unlock()
+	l.free()
+	return nil
+}
+
+func (l *windowsPipeListener) free() {
+	if l.securityDescriptor != 0 {
+		_, _, _ = procLocalFree.Call(l.securityDescriptor)
+		l.securityDescriptor = 0
+	}
+}
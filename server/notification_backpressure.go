@@ -0,0 +1,228 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// NotificationOverflowPolicy decides what happens to a notification sent to
+// a session whose notification channel is full, which is the case when its
+// client does not read notifications as fast as the server sends them.
+type NotificationOverflowPolicy int
+
+const (
+	// NotificationOverflowDrop drops the notification. It is the default.
+	NotificationOverflowDrop NotificationOverflowPolicy = iota
+	// NotificationOverflowBlock waits for room in the channel for up to the
+	// timeout set with WithNotificationBlockTimeout, or until the context of
+	// the sender is done, and drops the notification if there is none.
+	// Broadcasts such as list changed notifications wait for each slow
+	// session in turn.
+	NotificationOverflowBlock
+	// NotificationOverflowDisconnect drops the notification and unregisters
+	// the session, as WithSessionTTL does for idle sessions, so that a stuck
+	// client does not silently miss notifications. The connection of the
+	// session is closed if it implements SessionWithClose, and its client
+	// has to reconnect.
+	NotificationOverflowDisconnect
+)
+
+// String returns the name of the policy.
+func (p NotificationOverflowPolicy) String() string {
+	switch p {
+	case NotificationOverflowDrop:
+		return "drop"
+	case NotificationOverflowBlock:
+		return "block"
+	case NotificationOverflowDisconnect:
+		return "disconnect"
+	default:
+		return fmt.Sprintf("NotificationOverflowPolicy(%d)", int(p))
+	}
+}
+
+// defaultNotificationBlockTimeout is how long NotificationOverflowBlock waits
+// unless WithNotificationBlockTimeout is used.
+const defaultNotificationBlockTimeout = time.Second
+
+// NotificationDropFunc is called for every notification dropped because the
+// notification channel of session is full. The context carries the session.
+type NotificationDropFunc func(ctx context.Context, session ClientSession, notification mcp.JSONRPCNotification)
+
+// NotificationBackpressureOption configures WithNotificationBackpressure.
+type NotificationBackpressureOption func(*notificationBackpressure)
+
+// WithNotificationBlockTimeout sets how long NotificationOverflowBlock waits
+// for room in a notification channel. It defaults to one second.
+func WithNotificationBlockTimeout(timeout time.Duration) NotificationBackpressureOption {
+	return func(b *notificationBackpressure) {
+		b.blockTimeout = timeout
+	}
+}
+
+// WithNotificationDropHandler sets a function called for every dropped
+// notification, whatever the policy. It runs on the goroutine sending the
+// notification and should return quickly.
+func WithNotificationDropHandler(handler NotificationDropFunc) NotificationBackpressureOption {
+	return func(b *notificationBackpressure) {
+		b.onDrop = handler
+	}
+}
+
+// WithNotificationBackpressure sets what happens to notifications sent to a
+// session whose notification channel is full. Whatever the policy, dropped
+// notifications are reported to the OnError hooks, counted in
+// NotificationStats, and make the methods sending to a single session return
+// ErrNotificationChannelBlocked.
+func WithNotificationBackpressure(policy NotificationOverflowPolicy, opts ...NotificationBackpressureOption) ServerOption {
+	return func(s *MCPServer) {
+		s.notifications.policy = policy
+		for _, opt := range opts {
+			opt(&s.notifications)
+		}
+	}
+}
+
+// NotificationStats counts the notifications that could not be delivered
+// right away because the notification channel of their session was full.
+type NotificationStats struct {
+	// Dropped is the number of notifications dropped.
+	Dropped uint64
+	// Delayed is the number of notifications delivered after waiting for
+	// room under NotificationOverflowBlock.
+	Delayed uint64
+	// Disconnected is the number of sessions unregistered under
+	// NotificationOverflowDisconnect.
+	Disconnected uint64
+	// DroppedBySession is the number of notifications dropped per session,
+	// for sessions still registered that had any dropped.
+	DroppedBySession map[string]uint64
+}
+
+// NotificationStats returns the notification delivery counters of the
+// server, which help finding clients too slow to keep up with notifications.
+func (s *MCPServer) NotificationStats() NotificationStats {
+	b := &s.notifications
+	stats := NotificationStats{
+		Dropped:      b.dropped.Load(),
+		Delayed:      b.delayed.Load(),
+		Disconnected: b.disconnected.Load(),
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.sessionDrops) > 0 {
+		stats.DroppedBySession = make(map[string]uint64, len(b.sessionDrops))
+		for sessionID, dropped := range b.sessionDrops {
+			stats.DroppedBySession[sessionID] = dropped
+		}
+	}
+	return stats
+}
+
+// notificationBackpressure applies the overflow policy of a server and
+// counts the notifications it could not deliver right away.
+type notificationBackpressure struct {
+	policy       NotificationOverflowPolicy
+	blockTimeout time.Duration
+	onDrop       NotificationDropFunc
+
+	mu           sync.Mutex
+	sessionDrops map[string]uint64
+
+	dropped      atomic.Uint64
+	delayed      atomic.Uint64
+	disconnected atomic.Uint64
+}
+
+// recordDrop counts a notification dropped for sessionID.
+func (b *notificationBackpressure) recordDrop(sessionID string) {
+	b.dropped.Add(1)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.sessionDrops == nil {
+		b.sessionDrops = make(map[string]uint64)
+	}
+	b.sessionDrops[sessionID]++
+}
+
+// forget drops the counters of an unregistered session.
+func (b *notificationBackpressure) forget(sessionID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.sessionDrops, sessionID)
+}
+
+// deliverNotification sends notification to the notification channel of
+// session, applying the overflow policy if the channel is full. The
+// list_changed notifications of capabilities the session was not
+// advertised are dropped.
+func (s *MCPServer) deliverNotification(ctx context.Context, session ClientSession, notification mcp.JSONRPCNotification) error {
+	if !s.advertisesListChanged(session, notification.Method) {
+		return nil
+	}
+	select {
+	case session.NotificationChannel() <- notification:
+		return nil
+	default:
+	}
+
+	b := &s.notifications
+	if b.policy == NotificationOverflowBlock && b.wait(ctx, session, notification) {
+		b.delayed.Add(1)
+		return nil
+	}
+
+	sessionID := session.SessionID()
+	b.recordDrop(sessionID)
+	s.logEvent(ctx, slog.LevelWarn, "notification dropped",
+		"session_id", sessionID, "method", notification.Method, "policy", b.policy.String())
+	if b.onDrop != nil {
+		b.onDrop(s.WithContext(ctx, session), session, notification)
+	}
+	// Channel is blocked, if there's an error hook, use it
+	if s.hooks != nil && len(s.hooks.OnError) > 0 {
+		method := notification.Method
+		err := ErrNotificationChannelBlocked
+		// Copy hooks pointer to local variable to avoid race condition
+		hooks := s.hooks
+		go func(sessionID string, hooks *Hooks) {
+			// Use the error hook to report the blocked channel
+			hooks.onError(ctx, nil, "notification", map[string]any{
+				"method":    method,
+				"sessionID": sessionID,
+			}, fmt.Errorf("notification channel blocked for session %s: %w", sessionID, err))
+		}(sessionID, hooks)
+	}
+	if b.policy == NotificationOverflowDisconnect {
+		if _, ok := s.sessions.Load(sessionID); ok {
+			b.disconnected.Add(1)
+			s.UnregisterSession(s.WithContext(context.WithoutCancel(ctx), session), sessionID)
+		}
+	}
+	return ErrNotificationChannelBlocked
+}
+
+// wait waits for room in the notification channel of session and reports
+// whether notification could be sent.
+func (b *notificationBackpressure) wait(ctx context.Context, session ClientSession, notification mcp.JSONRPCNotification) bool {
+	timeout := b.blockTimeout
+	if timeout <= 0 {
+		timeout = defaultNotificationBlockTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case session.NotificationChannel() <- notification:
+		return true
+	case <-timer.C:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,183 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// closingSession is a session with a connection that records being closed.
+type closingSession struct {
+	*sessionTestClient
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func (s *closingSession) Close() {
+	s.closeOnce.Do(func() { close(s.closed) })
+}
+
+// registerBlockedSession registers a session whose notification channel has
+// room for a single notification, which it already holds.
+func registerBlockedSession(t *testing.T, server *MCPServer, sessionID string) chan mcp.JSONRPCNotification {
+	t.Helper()
+	ch := make(chan mcp.JSONRPCNotification, 1)
+	session := &closingSession{
+		sessionTestClient: &sessionTestClient{sessionID: sessionID, notificationChannel: ch, initialized: true},
+		closed:            make(chan struct{}),
+	}
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+	require.NoError(t, server.SendNotificationToSpecificClient(sessionID, "first", nil))
+	return ch
+}
+
+func TestMCPServer_NotificationBackpressure(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     NotificationOverflowPolicy
+		expected   NotificationStats
+		registered bool
+	}{
+		{
+			name:       "drop",
+			policy:     NotificationOverflowDrop,
+			expected:   NotificationStats{Dropped: 1, DroppedBySession: map[string]uint64{"slow": 1}},
+			registered: true,
+		},
+		{
+			name:       "block",
+			policy:     NotificationOverflowBlock,
+			expected:   NotificationStats{Dropped: 1, DroppedBySession: map[string]uint64{"slow": 1}},
+			registered: true,
+		},
+		{
+			name:     "disconnect",
+			policy:   NotificationOverflowDisconnect,
+			expected: NotificationStats{Dropped: 1, Disconnected: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var dropped []string
+			server := NewMCPServer("test-server", "1.0.0", WithNotificationBackpressure(tt.policy,
+				WithNotificationBlockTimeout(20*time.Millisecond),
+				WithNotificationDropHandler(func(ctx context.Context, session ClientSession, notification mcp.JSONRPCNotification) {
+					assert.Equal(t, session, ClientSessionFromContext(ctx))
+					mu.Lock()
+					defer mu.Unlock()
+					dropped = append(dropped, session.SessionID()+" "+notification.Method)
+				})))
+			registerBlockedSession(t, server, "slow")
+			value, _ := server.sessions.Load("slow")
+			session := value.(*closingSession)
+
+			start := time.Now()
+			err := server.SendNotificationToSpecificClient("slow", "second", nil)
+			assert.ErrorIs(t, err, ErrNotificationChannelBlocked)
+			if tt.policy == NotificationOverflowBlock {
+				assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond, "the sender waits for room")
+			}
+
+			mu.Lock()
+			assert.Equal(t, []string{"slow second"}, dropped)
+			mu.Unlock()
+			assert.Equal(t, tt.expected, server.NotificationStats())
+			assert.Equal(t, tt.registered, sessionRegistered(server, "slow"))
+			select {
+			case <-session.closed:
+				assert.False(t, tt.registered, "the connections of sessions that are kept stay open")
+			default:
+				assert.True(t, tt.registered, "the connections of disconnected sessions are closed")
+			}
+		})
+	}
+}
+
+func TestMCPServer_NotificationBackpressure_DisconnectSSE(t *testing.T) {
+	mcpServer := NewMCPServer("test-server", "1.0.0", WithNotificationBackpressure(NotificationOverflowDisconnect))
+	testServer := NewTestServer(mcpServer)
+	defer testServer.Close()
+
+	sseResp, err := http.Get(testServer.URL + "/sse")
+	require.NoError(t, err)
+	defer sseResp.Body.Close()
+	endpointEvent, err := readSSEEvent(sseResp)
+	require.NoError(t, err)
+	messageURL := strings.TrimSpace(strings.Split(strings.Split(endpointEvent, "data: ")[1], "\n")[0])
+	sessionID := strings.SplitN(messageURL, "sessionId=", 2)[1]
+	resp, err := http.Post(messageURL, "application/json", strings.NewReader(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","clientInfo":{"name":"test","version":"1.0.0"}}}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Eventually(t, func() bool {
+		value, ok := mcpServer.sessions.Load(sessionID)
+		return ok && value.(ClientSession).Initialized()
+	}, time.Second, 5*time.Millisecond)
+
+	// The client reads nothing until the notifications fill the socket
+	// buffers and the queues of the session.
+	payload := map[string]any{"data": strings.Repeat("x", 64<<10)}
+	for i := 0; ; i++ {
+		require.Less(t, i, 10000, "the notification channel never filled up")
+		err := mcpServer.SendNotificationToSpecificClient(sessionID, "flood", payload)
+		if err != nil {
+			require.ErrorIs(t, err, ErrNotificationChannelBlocked)
+			break
+		}
+	}
+
+	assert.False(t, sessionRegistered(mcpServer, sessionID))
+	assert.True(t, streamEnds(sseResp), "the stream of a disconnected session ends")
+}
+
+func TestMCPServer_NotificationBackpressure_BlockDelivers(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithNotificationBackpressure(NotificationOverflowBlock,
+		WithNotificationBlockTimeout(time.Second)))
+	ch := registerBlockedSession(t, server, "slow")
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		<-ch
+	}()
+	require.NoError(t, server.SendNotificationToSpecificClient("slow", "second", nil))
+	assert.Equal(t, "second", (<-ch).Method)
+	assert.Equal(t, NotificationStats{Delayed: 1}, server.NotificationStats())
+}
+
+func TestMCPServer_NotificationBackpressure_BlockContext(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithNotificationBackpressure(NotificationOverflowBlock,
+		WithNotificationBlockTimeout(time.Minute)))
+	registerBlockedSession(t, server, "slow")
+	value, _ := server.sessions.Load("slow")
+
+	ctx, cancel := context.WithTimeout(server.WithContext(context.Background(), value.(ClientSession)), 20*time.Millisecond)
+	defer cancel()
+	err := server.SendNotificationToClient(ctx, "second", nil)
+	assert.ErrorIs(t, err, ErrNotificationChannelBlocked, "the sender stops waiting when its context is done")
+}
+
+func TestMCPServer_NotificationStats(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+	registerBlockedSession(t, server, "slow")
+	fast := make(chan mcp.JSONRPCNotification, 10)
+	require.NoError(t, server.RegisterSession(context.Background(),
+		&sessionTestClient{sessionID: "fast", notificationChannel: fast, initialized: true}))
+
+	server.SendNotificationToAllClients("broadcast", nil)
+	server.SendNotificationToAllClients("broadcast", nil)
+	require.Len(t, fast, 2, "slow sessions do not hold up the others")
+	assert.Equal(t, NotificationStats{Dropped: 2, DroppedBySession: map[string]uint64{"slow": 2}}, server.NotificationStats())
+
+	server.UnregisterSession(context.Background(), "slow")
+	assert.Equal(t, NotificationStats{Dropped: 2}, server.NotificationStats(), "unregistered sessions are forgotten")
+}
@@ -0,0 +1,166 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// NotificationBus carries notifications between the servers of a cluster,
+// so that clients connected to any of them receive them. The redisbus
+// package provides a Redis implementation. Implementations must be safe for
+// concurrent use.
+type NotificationBus interface {
+	// Publish sends message to every server subscribed to the bus.
+	Publish(ctx context.Context, message BusMessage) error
+	// Subscribe calls handler with every message published to the bus,
+	// including the messages published by the subscriber itself, until the
+	// bus is closed.
+	Subscribe(handler func(message BusMessage))
+}
+
+// BusMessage is a notification published to a NotificationBus.
+type BusMessage struct {
+	// Origin identifies the server that published the message.
+	Origin string `json:"origin"`
+	// Notification is the notification to send to every client.
+	Notification *mcp.JSONRPCNotification `json:"notification,omitempty"`
+	// ResourceURI is the URI of an updated resource, whose subscribers are
+	// sent a notifications/resources/updated notification.
+	ResourceURI string `json:"resourceUri,omitempty"`
+}
+
+const (
+	// maxBusQueue bounds the messages waiting to be published to the
+	// notification bus.
+	maxBusQueue = 1024
+	// busPublishTimeout bounds the publication of a message.
+	busPublishTimeout = 5 * time.Second
+)
+
+// ErrNotificationBusFull is reported to the OnError hooks for messages
+// dropped because too many are waiting to be published.
+var ErrNotificationBusFull = errors.New("notification bus queue is full")
+
+// busQueue holds the messages waiting to be published, in order.
+type busQueue struct {
+	mu         sync.Mutex
+	messages   []BusMessage
+	publishing bool
+}
+
+// WithNotificationBus shares the notifications sent with
+// SendNotificationToAllClients and NotifyResourceUpdated with the other
+// servers subscribed to bus, and delivers theirs to the clients of this
+// server. This lets notifications reach every client of a clustered
+// deployment, whichever node it is connected to. List changed notifications
+// are not shared, as every node has its own tools, prompts and resources.
+//
+// Messages are published in the background, in order, so that a slow bus
+// does not hold up the handlers sending notifications. Failures to publish,
+// including messages dropped when too many are waiting, are reported to the
+// OnError hooks.
+func WithNotificationBus(bus NotificationBus) ServerOption {
+	return func(s *MCPServer) {
+		s.notificationBus = bus
+		s.busOrigin = uuid.New().String()
+		bus.Subscribe(s.handleBusMessage)
+	}
+}
+
+// publish queues message to be shared with the other servers of the
+// notification bus.
+func (s *MCPServer) publish(message BusMessage) {
+	if s.notificationBus == nil {
+		return
+	}
+	message.Origin = s.busOrigin
+	q := &s.busQueue
+	q.mu.Lock()
+	if len(q.messages) >= maxBusQueue {
+		q.mu.Unlock()
+		s.hooks.onError(context.Background(), nil, "notification", message, fmt.Errorf("failed to publish notification: %w", ErrNotificationBusFull))
+		return
+	}
+	q.messages = append(q.messages, message)
+	if !q.publishing {
+		q.publishing = true
+		go s.publishQueued()
+	}
+	q.mu.Unlock()
+}
+
+// publishQueued publishes the queued messages until none is left.
+func (s *MCPServer) publishQueued() {
+	q := &s.busQueue
+	for {
+		q.mu.Lock()
+		if len(q.messages) == 0 {
+			q.publishing = false
+			q.mu.Unlock()
+			return
+		}
+		message := q.messages[0]
+		q.messages[0] = BusMessage{}
+		q.messages = q.messages[1:]
+		q.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), busPublishTimeout)
+		err := s.notificationBus.Publish(ctx, message)
+		cancel()
+		if err != nil {
+			s.hooks.onError(context.Background(), nil, "notification", message, fmt.Errorf("failed to publish notification: %w", err))
+		}
+	}
+}
+
+// handleBusMessage delivers a message of another server to the clients of
+// s.
+func (s *MCPServer) handleBusMessage(message BusMessage) {
+	if message.Origin == s.busOrigin {
+		return
+	}
+	if message.Notification != nil {
+		s.sendNotificationToAllClients(*message.Notification)
+	}
+	if message.ResourceURI != "" {
+		s.resourceCache.invalidate(message.ResourceURI)
+		s.notifyResourceSubscribers(message.ResourceURI)
+	}
+}
+
+// MemoryNotificationBus is a NotificationBus connecting the servers of a
+// single process, such as in tests. Messages are delivered synchronously.
+type MemoryNotificationBus struct {
+	mu       sync.RWMutex
+	handlers []func(message BusMessage)
+}
+
+// NewMemoryNotificationBus returns an empty MemoryNotificationBus.
+func NewMemoryNotificationBus() *MemoryNotificationBus {
+	return &MemoryNotificationBus{}
+}
+
+// Publish implements NotificationBus.
+func (b *MemoryNotificationBus) Publish(ctx context.Context, message BusMessage) error {
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+	for _, handler := range handlers {
+		handler(message)
+	}
+	return nil
+}
+
+// Subscribe implements NotificationBus.
+func (b *MemoryNotificationBus) Subscribe(handler func(message BusMessage)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers[:len(b.handlers):len(b.handlers)], handler)
+}
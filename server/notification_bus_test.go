@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type failingBus struct{}
+
+func (failingBus) Publish(ctx context.Context, message BusMessage) error {
+	return errors.New("bus down")
+}
+
+func (failingBus) Subscribe(handler func(message BusMessage)) {}
+
+func TestMCPServer_WithNotificationBus(t *testing.T) {
+	bus := NewMemoryNotificationBus()
+	newNode := func(sessionID string) (*MCPServer, *fakeSession) {
+		s := NewMCPServer("node", "1.0.0",
+			WithNotificationBus(bus),
+			WithToolCapabilities(true),
+			WithResourceCapabilities(true, false),
+		)
+		session := &fakeSession{sessionID: sessionID, notificationChannel: make(chan mcp.JSONRPCNotification, 10), initialized: true}
+		require.NoError(t, s.RegisterSession(context.Background(), session))
+		return s, session
+	}
+	a, sessionA := newNode("session-a")
+	b, sessionB := newNode("session-b")
+
+	t.Run("broadcast", func(t *testing.T) {
+		a.SendNotificationToAllClients("notifications/custom", map[string]any{"n": 1})
+		for _, session := range []*fakeSession{sessionA, sessionB} {
+			select {
+			case notification := <-session.notificationChannel:
+				assert.Equal(t, "notifications/custom", notification.Method)
+				assert.Equal(t, 1, notification.Params.AdditionalFields["n"])
+			case <-time.After(time.Second):
+				t.Fatalf("no notification for %s", session.sessionID)
+			}
+		}
+	})
+
+	t.Run("resource updated", func(t *testing.T) {
+		ctx := b.WithContext(context.Background(), sessionB)
+		response := b.HandleMessage(ctx, json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"test://doc"}}`))
+		require.IsType(t, mcp.JSONRPCResponse{}, response)
+
+		a.NotifyResourceUpdated("test://doc")
+		select {
+		case notification := <-sessionB.notificationChannel:
+			assert.Equal(t, mcp.MethodNotificationResourceUpdated, notification.Method)
+			assert.Equal(t, "test://doc", notification.Params.AdditionalFields["uri"])
+		case <-time.After(time.Second):
+			t.Fatal("no notification for the subscriber")
+		}
+		assert.Empty(t, sessionA.notificationChannel, "not subscribed")
+
+		a.NotifyResourceUpdated("test://other")
+		time.Sleep(20 * time.Millisecond)
+		assert.Empty(t, sessionB.notificationChannel)
+	})
+
+	t.Run("list changed stays local", func(t *testing.T) {
+		a.AddTool(mcp.NewTool("local"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return nil, nil
+		})
+		require.Len(t, sessionA.notificationChannel, 1)
+		assert.Equal(t, mcp.MethodNotificationToolsListChanged, (<-sessionA.notificationChannel).Method)
+		assert.Empty(t, sessionB.notificationChannel)
+	})
+}
+
+func TestMCPServer_WithNotificationBus_PublishError(t *testing.T) {
+	hookErrs := make(chan error, 1)
+	hooks := &Hooks{}
+	hooks.AddOnError(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+		hookErrs <- err
+	})
+	s := NewMCPServer("node", "1.0.0", WithNotificationBus(failingBus{}), WithHooks(hooks))
+	s.SendNotificationToAllClients("notifications/custom", nil)
+	select {
+	case err := <-hookErrs:
+		assert.ErrorContains(t, err, "failed to publish notification: bus down")
+	case <-time.After(time.Second):
+		t.Fatal("publish error not reported")
+	}
+}
+
+// stalledBus blocks publications until released.
+type stalledBus struct {
+	started   chan struct{}
+	release   chan struct{}
+	published chan BusMessage
+}
+
+func (b *stalledBus) Publish(ctx context.Context, message BusMessage) error {
+	select {
+	case b.started <- struct{}{}:
+	default:
+	}
+	<-b.release
+	b.published <- message
+	return nil
+}
+
+func (b *stalledBus) Subscribe(handler func(message BusMessage)) {}
+
+func TestMCPServer_WithNotificationBus_Stalled(t *testing.T) {
+	bus := &stalledBus{started: make(chan struct{}), release: make(chan struct{}), published: make(chan BusMessage, maxBusQueue+1)}
+	var dropped atomic.Int32
+	hooks := &Hooks{}
+	hooks.AddOnError(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+		if errors.Is(err, ErrNotificationBusFull) {
+			dropped.Add(1)
+		}
+	})
+	s := NewMCPServer("node", "1.0.0", WithNotificationBus(bus), WithHooks(hooks))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// One message is being published, maxBusQueue wait and the last
+		// ones are dropped
+		s.NotifyResourceUpdated("test://doc/0")
+		<-bus.started
+		for i := 1; i < maxBusQueue+3; i++ {
+			s.NotifyResourceUpdated(fmt.Sprintf("test://doc/%d", i))
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("notifying blocks on a stalled bus")
+	}
+	assert.Equal(t, int32(2), dropped.Load())
+
+	close(bus.release)
+	for i := range maxBusQueue + 1 {
+		select {
+		case message := <-bus.published:
+			assert.Equal(t, fmt.Sprintf("test://doc/%d", i), message.ResourceURI, "messages are published in order")
+		case <-time.After(time.Second):
+			t.Fatalf("message %d not published", i)
+		}
+	}
+}
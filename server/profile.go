@@ -0,0 +1,97 @@
+package server
+
+import "strings"
+
+// Profile is a named, reusable bundle of server and transport options.
+// Teams can define profiles for their standard server postures once and
+// apply them to every MCP service they build:
+//
+//	var Hardened = server.NewProfile("public-hardened",
+//	    server.WithRecovery(),
+//	    server.WithToolCallCheck(requireScopes),
+//	).WithHTTP(server.WithAuthFunc(verifyToken))
+//
+//	s := server.NewMCPServer("billing", "1.0.0", server.WithProfile(Hardened))
+//	httpServer := server.NewStreamableHTTPServer(s, server.WithHTTPProfile(Hardened))
+//
+// Options are applied in order, so options added later, by With, by a later
+// profile in CombineProfiles, or passed after WithProfile, override earlier
+// ones.
+type Profile struct {
+	// Name identifies the profile, for example in logs or diagnostics.
+	Name string
+	// Options are applied to the MCPServer by WithProfile.
+	Options []ServerOption
+	// HTTPOptions are applied to a StreamableHTTPServer by WithHTTPProfile.
+	HTTPOptions []StreamableHTTPOption
+}
+
+// NewProfile creates a profile with the given server options.
+func NewProfile(name string, options ...ServerOption) Profile {
+	return Profile{Name: name, Options: options}
+}
+
+// With returns a copy of the profile with options appended, overriding
+// any earlier option that sets the same value.
+func (p Profile) With(options ...ServerOption) Profile {
+	p.Options = append(append([]ServerOption(nil), p.Options...), options...)
+	return p
+}
+
+// WithHTTP returns a copy of the profile with Streamable HTTP transport
+// options appended.
+func (p Profile) WithHTTP(options ...StreamableHTTPOption) Profile {
+	p.HTTPOptions = append(append([]StreamableHTTPOption(nil), p.HTTPOptions...), options...)
+	return p
+}
+
+// CombineProfiles merges profiles into a new one. Options are applied in
+// the order the profiles are given, so later profiles win. An empty name
+// joins the names of the combined profiles with "+".
+func CombineProfiles(name string, profiles ...Profile) Profile {
+	combined := Profile{Name: name}
+	names := make([]string, 0, len(profiles))
+	for _, p := range profiles {
+		names = append(names, p.Name)
+		combined.Options = append(combined.Options, p.Options...)
+		combined.HTTPOptions = append(combined.HTTPOptions, p.HTTPOptions...)
+	}
+	if combined.Name == "" {
+		combined.Name = strings.Join(names, "+")
+	}
+	return combined
+}
+
+// WithProfile applies the server options of each profile in order.
+func WithProfile(profiles ...Profile) ServerOption {
+	return func(s *MCPServer) {
+		for _, p := range profiles {
+			for _, option := range p.Options {
+				option(s)
+			}
+		}
+	}
+}
+
+// WithHTTPProfile applies the Streamable HTTP options of each profile in
+// order.
+func WithHTTPProfile(profiles ...Profile) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		for _, p := range profiles {
+			for _, option := range p.HTTPOptions {
+				option(s)
+			}
+		}
+	}
+}
+
+// LocalDevProfile suits servers run on a developer machine: handler panics
+// are recovered into errors and the logging capability is enabled so log
+// messages reach the client.
+func LocalDevProfile() Profile {
+	return NewProfile("local-dev",
+		WithRecovery(),
+		WithResourceRecovery(),
+		WithLogging(),
+	)
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestProfile(t *testing.T) {
+	base := NewProfile("base",
+		WithInstructions("base instructions"),
+		WithPaginationLimit(10),
+	).WithHTTP(WithEndpointPath("/base"))
+	strict := NewProfile("strict", WithPaginationLimit(5)).WithHTTP(WithEndpointPath("/strict"))
+
+	tests := []struct {
+		name                 string
+		options              []ServerOption
+		expectedInstructions string
+		expectedLimit        int
+	}{
+		{
+			name:                 "single profile",
+			options:              []ServerOption{WithProfile(base)},
+			expectedInstructions: "base instructions",
+			expectedLimit:        10,
+		},
+		{
+			name:                 "later profiles override earlier ones",
+			options:              []ServerOption{WithProfile(CombineProfiles("", base, strict))},
+			expectedInstructions: "base instructions",
+			expectedLimit:        5,
+		},
+		{
+			name:                 "With overrides profile options",
+			options:              []ServerOption{WithProfile(base.With(WithInstructions("custom")))},
+			expectedInstructions: "custom",
+			expectedLimit:        10,
+		},
+		{
+			name:                 "options after the profile win",
+			options:              []ServerOption{WithProfile(base), WithPaginationLimit(1)},
+			expectedInstructions: "base instructions",
+			expectedLimit:        1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMCPServer("test-server", "1.0.0", tt.options...)
+			assert.Equal(t, tt.expectedInstructions, server.instructions)
+			require.NotNil(t, server.paginationLimit)
+			assert.Equal(t, tt.expectedLimit, *server.paginationLimit)
+		})
+	}
+
+	t.Run("With does not modify the original profile", func(t *testing.T) {
+		_ = base.With(WithInstructions("other"))
+		assert.Len(t, base.Options, 2)
+	})
+
+	t.Run("combined names", func(t *testing.T) {
+		assert.Equal(t, "base+strict", CombineProfiles("", base, strict).Name)
+		assert.Equal(t, "custom", CombineProfiles("custom", base, strict).Name)
+	})
+
+	t.Run("http options", func(t *testing.T) {
+		httpServer := NewStreamableHTTPServer(NewMCPServer("test-server", "1.0.0"),
+			WithHTTPProfile(CombineProfiles("", base, strict)))
+		assert.Equal(t, "/strict", httpServer.endpointPath)
+	})
+}
+
+func TestLocalDevProfile(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithProfile(LocalDevProfile()))
+	server.AddTool(mcp.NewTool("panic"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		panic("boom")
+	})
+
+	response := callTool(server, "panic")
+	errorResponse, ok := response.(mcp.JSONRPCError)
+	require.True(t, ok, "expected the panic to be recovered, got %#v", response)
+	assert.Contains(t, errorResponse.Error.Message, "boom")
+	require.NotNil(t, server.capabilities.logging)
+	assert.True(t, *server.capabilities.logging)
+}
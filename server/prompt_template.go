@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// PromptTemplateMessage is one message of a templated prompt. Text is a
+// text/template rendered with the prompt arguments as data, so an argument
+// is referenced as {{.topic}}, or {{index . "my-topic"}} when its name is
+// not a valid identifier. Declared arguments that were not provided render
+// as empty strings.
+type PromptTemplateMessage struct {
+	Role mcp.Role
+	Text string
+}
+
+// UserPromptMessage returns a templated message sent as the user.
+func UserPromptMessage(text string) PromptTemplateMessage {
+	return PromptTemplateMessage{Role: mcp.RoleUser, Text: text}
+}
+
+// AssistantPromptMessage returns a templated message sent as the assistant.
+func AssistantPromptMessage(text string) PromptTemplateMessage {
+	return PromptTemplateMessage{Role: mcp.RoleAssistant, Text: text}
+}
+
+// PromptTemplate renders a prompt from templated messages, so prompts that
+// only substitute arguments into text need no hand-written handler.
+type PromptTemplate struct {
+	prompt   mcp.Prompt
+	roles    []mcp.Role
+	messages []*template.Template
+}
+
+// NewPromptTemplate parses the messages of a prompt. It returns an error if
+// a message is not a valid template.
+func NewPromptTemplate(prompt mcp.Prompt, messages ...PromptTemplateMessage) (*PromptTemplate, error) {
+	t := &PromptTemplate{prompt: prompt}
+	for i, message := range messages {
+		tmpl, err := template.New(fmt.Sprintf("%s[%d]", prompt.Name, i)).
+			Option("missingkey=zero").
+			Parse(message.Text)
+		if err != nil {
+			return nil, fmt.Errorf("prompt '%s': failed to parse message %d: %w", prompt.Name, i, err)
+		}
+		t.roles = append(t.roles, message.Role)
+		t.messages = append(t.messages, tmpl)
+	}
+	return t, nil
+}
+
+// Prompt returns the prompt definition the template renders.
+func (t *PromptTemplate) Prompt() mcp.Prompt {
+	return t.prompt
+}
+
+// Render validates the arguments against the prompt definition and renders
+// the messages. A missing required argument is reported as
+// mcp.ErrInvalidParams.
+func (t *PromptTemplate) Render(arguments map[string]string) (*mcp.GetPromptResult, error) {
+	var missing []string
+	data := make(map[string]string, len(t.prompt.Arguments)+len(arguments))
+	for _, arg := range t.prompt.Arguments {
+		value, ok := arguments[arg.Name]
+		if arg.Required && (!ok || value == "") {
+			missing = append(missing, arg.Name)
+		}
+		data[arg.Name] = value
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: prompt '%s' requires arguments: %s",
+			mcp.ErrInvalidParams, t.prompt.Name, strings.Join(missing, ", "))
+	}
+	for name, value := range arguments {
+		data[name] = value
+	}
+
+	messages := make([]mcp.PromptMessage, 0, len(t.messages))
+	for i, tmpl := range t.messages {
+		var text strings.Builder
+		if err := tmpl.Execute(&text, data); err != nil {
+			return nil, fmt.Errorf("prompt '%s': failed to render message %d: %w", t.prompt.Name, i, err)
+		}
+		messages = append(messages, mcp.NewPromptMessage(t.roles[i], mcp.NewTextContent(text.String())))
+	}
+	return mcp.NewGetPromptResult(t.prompt.Description, messages), nil
+}
+
+// Handler returns a PromptHandlerFunc that renders the template with the
+// request arguments.
+func (t *PromptTemplate) Handler() PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return t.Render(request.Params.Arguments)
+	}
+}
+
+// AddPromptTemplate registers a prompt rendered from templated messages:
+//
+//	err := s.AddPromptTemplate(
+//	    mcp.NewPrompt("review", mcp.WithArgument("language", mcp.RequiredArgument())),
+//	    server.UserPromptMessage("Review this {{.language}} code for bugs."),
+//	)
+func (s *MCPServer) AddPromptTemplate(prompt mcp.Prompt, messages ...PromptTemplateMessage) error {
+	t, err := NewPromptTemplate(prompt, messages...)
+	if err != nil {
+		return err
+	}
+	s.AddPrompt(prompt, t.Handler())
+	return nil
+}
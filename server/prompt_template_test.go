@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestPromptTemplate_Render(t *testing.T) {
+	prompt := mcp.NewPrompt("review",
+		mcp.WithPromptDescription("Code review"),
+		mcp.WithArgument("language", mcp.RequiredArgument()),
+		mcp.WithArgument("focus-area"),
+	)
+	tmpl, err := NewPromptTemplate(prompt,
+		UserPromptMessage(`Review this {{.language}} code{{with index . "focus-area"}}, focusing on {{.}}{{end}}.`),
+		AssistantPromptMessage("Sure, send the {{.language}} code."),
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		arguments     map[string]string
+		expectedTexts []string
+		expectedErr   error
+	}{
+		{
+			name:          "all arguments",
+			arguments:     map[string]string{"language": "Go", "focus-area": "concurrency"},
+			expectedTexts: []string{"Review this Go code, focusing on concurrency.", "Sure, send the Go code."},
+		},
+		{
+			name:          "optional argument omitted",
+			arguments:     map[string]string{"language": "Go"},
+			expectedTexts: []string{"Review this Go code.", "Sure, send the Go code."},
+		},
+		{
+			name:        "required argument missing",
+			arguments:   map[string]string{"focus-area": "style"},
+			expectedErr: mcp.ErrInvalidParams,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tmpl.Render(tt.arguments)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Contains(t, err.Error(), "language")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "Code review", result.Description)
+			require.Len(t, result.Messages, len(tt.expectedTexts))
+			for i, text := range tt.expectedTexts {
+				assert.Equal(t, text, result.Messages[i].Content.(mcp.TextContent).Text)
+			}
+			assert.Equal(t, mcp.RoleUser, result.Messages[0].Role)
+			assert.Equal(t, mcp.RoleAssistant, result.Messages[1].Role)
+		})
+	}
+}
+
+func TestMCPServer_AddPromptTemplate(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+
+	err := server.AddPromptTemplate(mcp.NewPrompt("broken"), UserPromptMessage("{{.unclosed"))
+	assert.Error(t, err)
+
+	require.NoError(t, server.AddPromptTemplate(
+		mcp.NewPrompt("greet", mcp.WithArgument("name", mcp.RequiredArgument())),
+		UserPromptMessage("Say hello to {{.name}}."),
+	))
+
+	response := server.HandleMessage(context.Background(), json.RawMessage(
+		`{"jsonrpc":"2.0","id":1,"method":"prompts/get","params":{"name":"greet","arguments":{"name":"Ada"}}}`))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "expected success response, got %#v", response)
+	result := resp.Result.(mcp.GetPromptResult)
+	require.Len(t, result.Messages, 1)
+	assert.Equal(t, "Say hello to Ada.", result.Messages[0].Content.(mcp.TextContent).Text)
+
+	response = server.HandleMessage(context.Background(), json.RawMessage(
+		`{"jsonrpc":"2.0","id":2,"method":"prompts/get","params":{"name":"greet"}}`))
+	errorResponse, ok := response.(mcp.JSONRPCError)
+	require.True(t, ok, "expected error response, got %#v", response)
+	assert.Equal(t, mcp.INVALID_PARAMS, errorResponse.Error.Code)
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// SessionPromptFilterFunc decides which prompts a session may see and get.
+// It receives the session making the request (nil outside of a session)
+// and returns the subset of prompts that remain visible, typically based on
+// the session's client info, its values or the AuthInfo in ctx.
+type SessionPromptFilterFunc func(ctx context.Context, session ClientSession, prompts []mcp.Prompt) []mcp.Prompt
+
+// WithSessionPromptFilter adds a per-session visibility policy for
+// prompts. Hidden prompts are left out of prompts/list and rejected by
+// prompts/get as if they did not exist.
+//
+// When the inputs of a policy change, call RefreshPromptVisibility so the
+// affected sessions receive notifications/prompts/list_changed.
+func WithSessionPromptFilter(filter SessionPromptFilterFunc) ServerOption {
+	return func(s *MCPServer) {
+		s.promptFiltersMu.Lock()
+		s.sessionPromptFilters = append(s.sessionPromptFilters, filter)
+		s.promptFiltersMu.Unlock()
+	}
+}
+
+// listPrompts returns the prompts the session of ctx can see: the server's,
+// overridden by those of its tenant and its own, through the session prompt
+// filters and sorted by name.
+func (s *MCPServer) listPrompts(ctx context.Context) []mcp.Prompt {
+	promptMap := make(map[string]mcp.Prompt, s.prompts.len())
+	s.prompts.each(func(name string, prompt ServerPrompt) bool {
+		promptMap[name] = prompt.Prompt
+		return true
+	})
+
+	// Tenant and session prompts override global ones
+	for name, prompt := range s.overlayPrompts(ctx) {
+		promptMap[name] = prompt.Prompt
+	}
+	prompts := slices.Collect(maps.Values(promptMap))
+
+	// sort prompts by name
+	sort.Slice(prompts, func(i, j int) bool {
+		return prompts[i].Name < prompts[j].Name
+	})
+	return s.applySessionPromptFilters(ctx, prompts)
+}
+
+// applySessionPromptFilters runs the session prompt filters over prompts.
+func (s *MCPServer) applySessionPromptFilters(ctx context.Context, prompts []mcp.Prompt) []mcp.Prompt {
+	s.promptFiltersMu.RLock()
+	filters := s.sessionPromptFilters
+	s.promptFiltersMu.RUnlock()
+
+	if len(filters) == 0 {
+		return prompts
+	}
+	session := ClientSessionFromContext(ctx)
+	for _, filter := range filters {
+		prompts = filter(ctx, session, prompts)
+	}
+	return prompts
+}
+
+// promptVisible reports whether the session prompt filters keep prompt.
+func (s *MCPServer) promptVisible(ctx context.Context, prompt mcp.Prompt) bool {
+	visible := s.applySessionPromptFilters(ctx, []mcp.Prompt{prompt})
+	return slices.ContainsFunc(visible, func(p mcp.Prompt) bool { return p.Name == prompt.Name })
+}
+
+// recordVisiblePrompts remembers the prompt names last listed to a
+// session, so RefreshPromptVisibility can tell whether the visible set has
+// changed.
+func (s *MCPServer) recordVisiblePrompts(sessionID string, prompts []mcp.Prompt) {
+	s.visiblePromptsMu.Lock()
+	s.visiblePrompts[sessionID] = promptNames(prompts)
+	s.visiblePromptsMu.Unlock()
+}
+
+func (s *MCPServer) forgetVisiblePrompts(sessionID string) {
+	s.visiblePromptsMu.Lock()
+	delete(s.visiblePrompts, sessionID)
+	s.visiblePromptsMu.Unlock()
+}
+
+func promptNames(prompts []mcp.Prompt) []string {
+	names := make([]string, 0, len(prompts))
+	for _, prompt := range prompts {
+		names = append(names, prompt.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// RefreshPromptVisibility re-evaluates the prompt filters for a session
+// and sends it notifications/prompts/list_changed if the set of visible
+// prompts differs from what it was last sent in prompts/list. ctx is passed
+// to the filters, so it should carry whatever they depend on, such as
+// AuthInfo.
+//
+// Sessions that have not listed prompts yet are not notified.
+func (s *MCPServer) RefreshPromptVisibility(ctx context.Context, sessionID string) error {
+	sessionValue, ok := s.sessions.Load(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+	session := sessionValue.(ClientSession)
+
+	names := promptNames(s.listPrompts(s.WithContext(ctx, session)))
+
+	s.visiblePromptsMu.Lock()
+	previous, listed := s.visiblePrompts[sessionID]
+	changed := listed && !slices.Equal(previous, names)
+	if changed {
+		s.visiblePrompts[sessionID] = names
+	}
+	s.visiblePromptsMu.Unlock()
+
+	if !changed || !session.Initialized() || s.capabilities.prompts == nil || !s.capabilities.prompts.listChanged {
+		return nil
+	}
+	return s.notifySessionListChanged(sessionID, mcp.MethodNotificationPromptsListChanged)
+}
+
+// RefreshAllPromptVisibility calls RefreshPromptVisibility for every
+// registered session and returns the joined errors.
+func (s *MCPServer) RefreshAllPromptVisibility(ctx context.Context) error {
+	var errs []error
+	s.sessions.Range(func(key, _ any) bool {
+		if err := s.RefreshPromptVisibility(ctx, key.(string)); err != nil && !errors.Is(err, ErrSessionNotFound) {
+			errs = append(errs, err)
+		}
+		return true
+	})
+	return errors.Join(errs...)
+}
@@ -0,0 +1,532 @@
+// Package proxy provides an MCP gateway that aggregates the tools,
+// resources, resource templates and prompts of several upstream MCP
+// servers and exposes them through a single server.MCPServer.
+//
+// Each upstream is reached through a started and initialized client:
+//
+//	gateway := server.NewMCPServer("gateway", "1.0.0")
+//	p := proxy.New(gateway)
+//	if err := p.AddUpstream(ctx, "github", githubClient); err != nil {
+//	    log.Fatal(err)
+//	}
+//	server.ServeStdio(gateway)
+//
+// Tool and prompt names are prefixed with the upstream name ("github_search")
+// so that upstreams cannot shadow each other. Resource URIs are global by
+// nature and are exposed unchanged. Remaining name collisions are resolved
+// by the ConflictPolicy.
+//
+// Calls are forwarded to the owning upstream with the original name.
+// Progress and log notifications an upstream streams for a call are
+// relayed to the downstream session that made it, and list_changed
+// notifications from an upstream trigger a refresh of its entries.
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+var (
+	// ErrUpstreamExists is returned when adding an upstream under a name
+	// that is already registered.
+	ErrUpstreamExists = errors.New("upstream already registered")
+
+	// ErrUpstreamNotFound is returned for operations on an unknown upstream.
+	ErrUpstreamNotFound = errors.New("upstream not found")
+
+	// ErrNameConflict is returned when two upstreams expose the same name
+	// and the ConflictPolicy is ConflictError.
+	ErrNameConflict = errors.New("name conflict between upstreams")
+)
+
+// ConflictPolicy decides what happens when an upstream exposes a tool,
+// prompt, resource or template under a name another upstream already owns.
+type ConflictPolicy int
+
+const (
+	// ConflictError rejects the upstream with ErrNameConflict. Nothing from
+	// the conflicting sync is registered.
+	ConflictError ConflictPolicy = iota
+	// ConflictKeepFirst keeps the entry of the upstream that registered it
+	// first and ignores the newcomer's entry.
+	ConflictKeepFirst
+	// ConflictReplace lets the newcomer take the name over.
+	ConflictReplace
+)
+
+// Option configures a Proxy.
+type Option func(*Proxy)
+
+// WithSeparator sets the string placed between the upstream name and the
+// tool or prompt name. The default is "_".
+func WithSeparator(separator string) Option {
+	return func(p *Proxy) {
+		p.separator = separator
+	}
+}
+
+// WithoutPrefix exposes tool and prompt names unchanged. Collisions are
+// then left entirely to the ConflictPolicy.
+func WithoutPrefix() Option {
+	return func(p *Proxy) {
+		p.prefix = false
+	}
+}
+
+// WithConflictPolicy sets how name collisions between upstreams are
+// resolved. The default is ConflictError.
+func WithConflictPolicy(policy ConflictPolicy) Option {
+	return func(p *Proxy) {
+		p.conflicts = policy
+	}
+}
+
+// WithErrorHandler sets a function that receives errors from work the proxy
+// does in the background, such as refreshing an upstream after it sent a
+// list_changed notification.
+func WithErrorHandler(handler func(upstream string, err error)) Option {
+	return func(p *Proxy) {
+		p.onError = handler
+	}
+}
+
+// entry kinds, used to keep the names of the different registries apart
+const (
+	kindTool     = "tool"
+	kindPrompt   = "prompt"
+	kindResource = "resource"
+	kindTemplate = "template"
+)
+
+type entryKey struct {
+	kind string
+	name string
+}
+
+// upstream is a registered upstream server and the names it currently
+// exposes through the proxy.
+type upstream struct {
+	name    string
+	client  *client.Client
+	exposed map[entryKey]struct{}
+
+	// syncMu serializes the syncs of the upstream, so that an older
+	// listing never overwrites a newer one.
+	syncMu sync.Mutex
+	// removeHandler removes the notification handler of the upstream from
+	// its client.
+	removeHandler func()
+}
+
+// Proxy aggregates upstream MCP servers into one MCPServer.
+type Proxy struct {
+	server    *server.MCPServer
+	separator string
+	prefix    bool
+	conflicts ConflictPolicy
+	onError   func(upstream string, err error)
+
+	mu        sync.Mutex
+	upstreams map[string]*upstream
+	owners    map[entryKey]string
+}
+
+// New creates a proxy that registers upstream entries on mcpServer.
+func New(mcpServer *server.MCPServer, opts ...Option) *Proxy {
+	p := &Proxy{
+		server:    mcpServer,
+		separator: "_",
+		prefix:    true,
+		upstreams: make(map[string]*upstream),
+		owners:    make(map[entryKey]string),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Server returns the MCPServer the proxy registers entries on.
+func (p *Proxy) Server() *server.MCPServer {
+	return p.server
+}
+
+// Upstreams returns the names of the registered upstreams, sorted.
+func (p *Proxy) Upstreams() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Sorted(maps.Keys(p.upstreams))
+}
+
+// AddUpstream registers an upstream and exposes its entries. c must be
+// started and initialized; the proxy does not own it and never closes it.
+func (p *Proxy) AddUpstream(ctx context.Context, name string, c *client.Client) error {
+	p.mu.Lock()
+	if _, ok := p.upstreams[name]; ok {
+		p.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrUpstreamExists, name)
+	}
+	u := &upstream{name: name, client: c, exposed: make(map[entryKey]struct{})}
+	p.upstreams[name] = u
+	p.mu.Unlock()
+
+	// The handler is registered first so that a list_changed notification
+	// sent while the entries are being listed is not missed.
+	u.removeHandler = c.OnNotificationMethod("*", func(notification mcp.JSONRPCNotification) {
+		p.handleUpstreamNotification(u, notification)
+	})
+	if err := p.sync(ctx, u); err != nil {
+		u.removeHandler()
+		p.mu.Lock()
+		delete(p.upstreams, name)
+		p.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Refresh re-lists the entries of an upstream and updates the proxy's
+// registries to match.
+func (p *Proxy) Refresh(ctx context.Context, name string) error {
+	p.mu.Lock()
+	u, ok := p.upstreams[name]
+	p.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUpstreamNotFound, name)
+	}
+	return p.sync(ctx, u)
+}
+
+// RemoveUpstream unregisters an upstream and removes its entries from
+// the server. The upstream's client is left open, but the proxy no longer
+// handles its notifications.
+func (p *Proxy) RemoveUpstream(name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	u, ok := p.upstreams[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUpstreamNotFound, name)
+	}
+	delete(p.upstreams, name)
+	u.removeHandler()
+	p.unregister(u, u.exposed)
+	return nil
+}
+
+// exposedName returns the name under which an upstream tool or prompt is
+// exposed.
+func (p *Proxy) exposedName(u *upstream, name string) string {
+	if !p.prefix {
+		return name
+	}
+	return u.name + p.separator + name
+}
+
+// upstreamEntries is everything an upstream offers, keyed by exposed name.
+type upstreamEntries struct {
+	tools     map[string]server.ServerTool
+	prompts   map[string]server.ServerPrompt
+	resources map[string]server.ServerResource
+	templates map[string]server.ServerResourceTemplate
+}
+
+// fetch lists all entries of an upstream according to its capabilities.
+func (p *Proxy) fetch(ctx context.Context, u *upstream) (*upstreamEntries, error) {
+	entries := &upstreamEntries{
+		tools:     make(map[string]server.ServerTool),
+		prompts:   make(map[string]server.ServerPrompt),
+		resources: make(map[string]server.ServerResource),
+		templates: make(map[string]server.ServerResourceTemplate),
+	}
+	capabilities := u.client.GetServerCapabilities()
+
+	if capabilities.Tools != nil {
+		result, err := u.client.ListTools(ctx, mcp.ListToolsRequest{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tools of upstream %s: %w", u.name, err)
+		}
+		for _, tool := range result.Tools {
+			original := tool.Name
+			tool.Name = p.exposedName(u, original)
+			entries.tools[tool.Name] = server.ServerTool{Tool: tool, Handler: p.toolHandler(u, original), Source: u.name}
+		}
+	}
+
+	if capabilities.Prompts != nil {
+		result, err := u.client.ListPrompts(ctx, mcp.ListPromptsRequest{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list prompts of upstream %s: %w", u.name, err)
+		}
+		for _, prompt := range result.Prompts {
+			original := prompt.Name
+			prompt.Name = p.exposedName(u, original)
+			entries.prompts[prompt.Name] = server.ServerPrompt{Prompt: prompt, Handler: p.promptHandler(u, original)}
+		}
+	}
+
+	if capabilities.Resources != nil {
+		resources, err := u.client.ListResources(ctx, mcp.ListResourcesRequest{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list resources of upstream %s: %w", u.name, err)
+		}
+		for _, resource := range resources.Resources {
+			entries.resources[resource.URI] = server.ServerResource{Resource: resource, Handler: p.resourceHandler(u), Source: u.name}
+		}
+
+		templates, err := u.client.ListResourceTemplates(ctx, mcp.ListResourceTemplatesRequest{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list resource templates of upstream %s: %w", u.name, err)
+		}
+		for _, template := range templates.ResourceTemplates {
+			if template.URITemplate == nil {
+				continue
+			}
+			entries.templates[template.URITemplate.Raw()] = server.ServerResourceTemplate{
+				Template: template,
+				Handler:  server.ResourceTemplateHandlerFunc(p.resourceHandler(u)),
+				Source:   u.name,
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+// sync brings the server's registries in line with what u offers.
+func (p *Proxy) sync(ctx context.Context, u *upstream) error {
+	u.syncMu.Lock()
+	defer u.syncMu.Unlock()
+
+	entries, err := p.fetch(ctx, u)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.upstreams[u.name] != u {
+		// removed while we were listing
+		return nil
+	}
+
+	// Resolve conflicts before touching the server so that a rejected
+	// sync leaves everything as it was.
+	claimed := make(map[entryKey]struct{})
+	var taken []entryKey
+	claim := func(key entryKey) (bool, error) {
+		owner, owned := p.owners[key]
+		if !owned || owner == u.name {
+			claimed[key] = struct{}{}
+			return true, nil
+		}
+		switch p.conflicts {
+		case ConflictKeepFirst:
+			return false, nil
+		case ConflictReplace:
+			claimed[key] = struct{}{}
+			taken = append(taken, key)
+			return true, nil
+		default:
+			return false, fmt.Errorf("%w: %s %q of upstream %s is already provided by %s",
+				ErrNameConflict, key.kind, key.name, u.name, owner)
+		}
+	}
+
+	var (
+		tools     []server.ServerTool
+		prompts   []server.ServerPrompt
+		resources []server.ServerResource
+		templates []server.ServerResourceTemplate
+	)
+	for _, name := range slices.Sorted(maps.Keys(entries.tools)) {
+		ok, err := claim(entryKey{kindTool, name})
+		if err != nil {
+			return err
+		}
+		if ok {
+			tools = append(tools, entries.tools[name])
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(entries.prompts)) {
+		ok, err := claim(entryKey{kindPrompt, name})
+		if err != nil {
+			return err
+		}
+		if ok {
+			prompts = append(prompts, entries.prompts[name])
+		}
+	}
+	for _, uri := range slices.Sorted(maps.Keys(entries.resources)) {
+		ok, err := claim(entryKey{kindResource, uri})
+		if err != nil {
+			return err
+		}
+		if ok {
+			resources = append(resources, entries.resources[uri])
+		}
+	}
+	for _, uriTemplate := range slices.Sorted(maps.Keys(entries.templates)) {
+		ok, err := claim(entryKey{kindTemplate, uriTemplate})
+		if err != nil {
+			return err
+		}
+		if ok {
+			templates = append(templates, entries.templates[uriTemplate])
+		}
+	}
+
+	// Drop what the upstream no longer offers.
+	stale := make(map[entryKey]struct{})
+	for key := range u.exposed {
+		if _, ok := claimed[key]; !ok {
+			stale[key] = struct{}{}
+		}
+	}
+	p.unregister(u, stale)
+
+	// Take names over from other upstreams.
+	for _, key := range taken {
+		if previous, ok := p.upstreams[p.owners[key]]; ok {
+			delete(previous.exposed, key)
+		}
+	}
+	for key := range claimed {
+		p.owners[key] = u.name
+		u.exposed[key] = struct{}{}
+	}
+
+	if len(tools) > 0 {
+		p.server.AddTools(tools...)
+	}
+	if len(prompts) > 0 {
+		p.server.AddPrompts(prompts...)
+	}
+	if len(resources) > 0 {
+		p.server.AddResources(resources...)
+	}
+	if len(templates) > 0 {
+		p.server.AddResourceTemplates(templates...)
+	}
+	return nil
+}
+
+// unregister removes keys owned by u from the server. p.mu must be held.
+func (p *Proxy) unregister(u *upstream, keys map[entryKey]struct{}) {
+	var tools, prompts, resources, templates []string
+	for key := range keys {
+		delete(u.exposed, key)
+		if p.owners[key] != u.name {
+			continue
+		}
+		delete(p.owners, key)
+		switch key.kind {
+		case kindTool:
+			tools = append(tools, key.name)
+		case kindPrompt:
+			prompts = append(prompts, key.name)
+		case kindResource:
+			resources = append(resources, key.name)
+		case kindTemplate:
+			templates = append(templates, key.name)
+		}
+	}
+	if len(tools) > 0 {
+		p.server.DeleteTools(tools...)
+	}
+	if len(prompts) > 0 {
+		p.server.DeletePrompts(prompts...)
+	}
+	if len(resources) > 0 {
+		p.server.DeleteResources(resources...)
+	}
+	if len(templates) > 0 {
+		p.server.DeleteResourceTemplates(templates...)
+	}
+}
+
+func (p *Proxy) toolHandler(u *upstream, name string) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		request.Params.Name = name
+		// Never pass the downstream's HTTP headers, such as its credentials,
+		// on to the upstream.
+		request.Header = nil
+
+		// The upstream gets a progress token of its own so that tokens from
+		// different downstream sessions cannot collide; relayRequestNotifications
+		// maps it back.
+		var progressToken mcp.ProgressToken
+		if meta := request.Params.Meta; meta != nil {
+			progressToken = meta.ProgressToken
+			request.Params.Meta = &mcp.Meta{AdditionalFields: meta.AdditionalFields}
+		}
+
+		ctx = client.WithRequestNotifications(ctx, p.relayRequestNotifications(ctx, progressToken))
+		return u.client.CallTool(ctx, request)
+	}
+}
+
+func (p *Proxy) promptHandler(u *upstream, name string) server.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		request.Params.Name = name
+		request.Header = nil
+		return u.client.GetPrompt(ctx, request)
+	}
+}
+
+func (p *Proxy) resourceHandler(u *upstream) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		request.Header = nil
+		result, err := u.client.ReadResource(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		return result.Contents, nil
+	}
+}
+
+// relayRequestNotifications returns a handler that forwards the
+// notifications an upstream streams for a call to the downstream session
+// in ctx. Progress is only relayed when the downstream asked for it, under
+// the downstream's own token.
+func (p *Proxy) relayRequestNotifications(ctx context.Context, progressToken mcp.ProgressToken) func(mcp.JSONRPCNotification) {
+	return func(notification mcp.JSONRPCNotification) {
+		params := maps.Clone(notification.Params.AdditionalFields)
+		if notification.Method == mcp.MethodNotificationProgress {
+			if progressToken == nil {
+				return
+			}
+			params["progressToken"] = progressToken
+		}
+		// The downstream may have gone away; there is no one left to tell.
+		_ = p.server.SendNotificationToClient(ctx, notification.Method, params)
+	}
+}
+
+// handleUpstreamNotification reacts to notifications an upstream sends
+// outside of a call.
+func (p *Proxy) handleUpstreamNotification(u *upstream, notification mcp.JSONRPCNotification) {
+	switch notification.Method {
+	case mcp.MethodNotificationToolsListChanged,
+		mcp.MethodNotificationPromptsListChanged,
+		mcp.MethodNotificationResourcesListChanged:
+		go func() {
+			if err := p.Refresh(context.Background(), u.name); err != nil && !errors.Is(err, ErrUpstreamNotFound) && p.onError != nil {
+				p.onError(u.name, err)
+			}
+		}()
+	case mcp.MethodNotificationResourceUpdated:
+		// Delivered only to downstream sessions subscribed to the URI.
+		if uri, _ := notification.Params.AdditionalFields["uri"].(string); uri != "" {
+			p.server.NotifyResourceUpdated(uri)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// testSession is a downstream session of the proxy.
+type testSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *testSession) SessionID() string { return s.id }
+func (s *testSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+func (s *testSession) Initialize()       {}
+func (s *testSession) Initialized() bool { return true }
+
+// newUpstream starts an upstream server over Streamable HTTP and returns an
+// initialized client for it.
+func newUpstream(t *testing.T, name string, opts ...transport.StreamableHTTPCOption) (*server.MCPServer, *client.Client) {
+	t.Helper()
+	upstream := server.NewMCPServer(name, "1.0.0",
+		server.WithToolCapabilities(true),
+		server.WithPromptCapabilities(true),
+		server.WithResourceCapabilities(false, true),
+	)
+	upstream.AddTool(mcp.NewTool("search", mcp.WithString("query")), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if request.Params.Meta != nil && request.Params.Meta.ProgressToken != nil {
+			_ = server.ServerFromContext(ctx).SendNotificationToClient(ctx, mcp.MethodNotificationProgress, map[string]any{
+				"progressToken": request.Params.Meta.ProgressToken,
+				"progress":      1,
+				"total":         1,
+			})
+		}
+		time.Sleep(50 * time.Millisecond)
+		return mcp.NewToolResultText(fmt.Sprintf("%s: %s", name, request.GetString("query", ""))), nil
+	})
+	upstream.AddPrompt(mcp.NewPrompt("greet"), func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return mcp.NewGetPromptResult("greeting", []mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleAssistant, mcp.NewTextContent("hello from "+name)),
+		}), nil
+	})
+	uri := "docs://" + name + "/readme"
+	upstream.AddResource(mcp.NewResource(uri, "readme"), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: uri, Text: name + " readme"}}, nil
+	})
+
+	httpServer := server.NewTestStreamableHTTPServer(upstream)
+	t.Cleanup(httpServer.Close)
+
+	c, err := client.NewStreamableHttpClient(httpServer.URL, opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	require.NoError(t, c.Start(context.Background()))
+	request := mcp.InitializeRequest{}
+	request.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	request.Params.ClientInfo = mcp.Implementation{Name: "proxy", Version: "1.0.0"}
+	_, err = c.Initialize(context.Background(), request)
+	require.NoError(t, err)
+	return upstream, c
+}
+
+func handle(t *testing.T, gateway *server.MCPServer, session server.ClientSession, message string) mcp.JSONRPCResponse {
+	t.Helper()
+	response := gateway.HandleMessage(gateway.WithContext(context.Background(), session), json.RawMessage(message))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "expected success response, got %#v", response)
+	return resp
+}
+
+func TestProxy(t *testing.T) {
+	_, alpha := newUpstream(t, "alpha")
+	_, beta := newUpstream(t, "beta")
+
+	gateway := server.NewMCPServer("gateway", "1.0.0")
+	p := New(gateway)
+	require.NoError(t, p.AddUpstream(context.Background(), "alpha", alpha))
+	require.NoError(t, p.AddUpstream(context.Background(), "beta", beta))
+	assert.Equal(t, []string{"alpha", "beta"}, p.Upstreams())
+
+	session := &testSession{id: "downstream", notifications: make(chan mcp.JSONRPCNotification, 10)}
+	require.NoError(t, gateway.RegisterSession(context.Background(), session))
+
+	t.Run("lists combined entries", func(t *testing.T) {
+		tools := handle(t, gateway, session, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`).Result.(mcp.ListToolsResult).Tools
+		require.Len(t, tools, 2)
+		assert.Equal(t, "alpha_search", tools[0].Name)
+		assert.Equal(t, "beta_search", tools[1].Name)
+		assert.Contains(t, tools[0].InputSchema.Properties, "query")
+
+		prompts := handle(t, gateway, session, `{"jsonrpc":"2.0","id":2,"method":"prompts/list"}`).Result.(mcp.ListPromptsResult).Prompts
+		require.Len(t, prompts, 2)
+		resources := handle(t, gateway, session, `{"jsonrpc":"2.0","id":3,"method":"resources/list"}`).Result.(mcp.ListResourcesResult).Resources
+		require.Len(t, resources, 2)
+	})
+
+	t.Run("forwards calls with progress", func(t *testing.T) {
+		result := handle(t, gateway, session, `{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"beta_search","arguments":{"query":"go"},"_meta":{"progressToken":"downstream-token"}}}`).
+			Result.(mcp.CallToolResult)
+		require.Len(t, result.Content, 1)
+		assert.Equal(t, "beta: go", result.Content[0].(mcp.TextContent).Text)
+
+		require.Len(t, session.notifications, 1)
+		progress := <-session.notifications
+		assert.Equal(t, mcp.MethodNotificationProgress, progress.Method)
+		assert.Equal(t, "downstream-token", progress.Params.AdditionalFields["progressToken"], "the downstream token is restored")
+	})
+
+	t.Run("progress is not relayed unless requested", func(t *testing.T) {
+		handle(t, gateway, session, `{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"alpha_search"}}`)
+		assert.Empty(t, session.notifications)
+	})
+
+	t.Run("forwards prompts and resources", func(t *testing.T) {
+		prompt := handle(t, gateway, session, `{"jsonrpc":"2.0","id":6,"method":"prompts/get","params":{"name":"alpha_greet"}}`).Result.(mcp.GetPromptResult)
+		require.Len(t, prompt.Messages, 1)
+		assert.Equal(t, "hello from alpha", prompt.Messages[0].Content.(mcp.TextContent).Text)
+
+		read := handle(t, gateway, session, `{"jsonrpc":"2.0","id":7,"method":"resources/read","params":{"uri":"docs://beta/readme"}}`).Result.(mcp.ReadResourceResult)
+		require.Len(t, read.Contents, 1)
+		assert.Equal(t, "beta readme", read.Contents[0].(mcp.TextResourceContents).Text)
+	})
+
+	t.Run("removing an upstream removes its entries", func(t *testing.T) {
+		require.NoError(t, p.RemoveUpstream("beta"))
+		assert.Nil(t, gateway.GetTool("beta_search"))
+		assert.NotNil(t, gateway.GetTool("alpha_search"))
+		assert.ErrorIs(t, p.RemoveUpstream("beta"), ErrUpstreamNotFound)
+		// drain the list_changed notifications caused by the removal
+		for len(session.notifications) > 0 {
+			<-session.notifications
+		}
+	})
+
+	assert.ErrorIs(t, p.AddUpstream(context.Background(), "alpha", alpha), ErrUpstreamExists)
+}
+
+func TestProxy_ConflictPolicies(t *testing.T) {
+	_, alpha := newUpstream(t, "alpha")
+	_, beta := newUpstream(t, "beta")
+
+	t.Run("error", func(t *testing.T) {
+		gateway := server.NewMCPServer("gateway", "1.0.0")
+		p := New(gateway, WithoutPrefix())
+		require.NoError(t, p.AddUpstream(context.Background(), "alpha", alpha))
+		err := p.AddUpstream(context.Background(), "beta", beta)
+		assert.ErrorIs(t, err, ErrNameConflict)
+		assert.Equal(t, []string{"alpha"}, p.Upstreams())
+		assert.Nil(t, gateway.GetTool("beta_search"))
+	})
+
+	t.Run("keep first", func(t *testing.T) {
+		gateway := server.NewMCPServer("gateway", "1.0.0")
+		p := New(gateway, WithoutPrefix(), WithConflictPolicy(ConflictKeepFirst))
+		require.NoError(t, p.AddUpstream(context.Background(), "alpha", alpha))
+		require.NoError(t, p.AddUpstream(context.Background(), "beta", beta))
+		tool := gateway.GetTool("search")
+		require.NotNil(t, tool)
+		assert.Equal(t, "alpha", tool.Source)
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		gateway := server.NewMCPServer("gateway", "1.0.0")
+		p := New(gateway, WithoutPrefix(), WithConflictPolicy(ConflictReplace))
+		require.NoError(t, p.AddUpstream(context.Background(), "alpha", alpha))
+		require.NoError(t, p.AddUpstream(context.Background(), "beta", beta))
+		tool := gateway.GetTool("search")
+		require.NotNil(t, tool)
+		assert.Equal(t, "beta", tool.Source)
+
+		// alpha no longer owns the name, so removing it keeps beta's tool
+		require.NoError(t, p.RemoveUpstream("alpha"))
+		assert.NotNil(t, gateway.GetTool("search"))
+	})
+
+	t.Run("custom separator", func(t *testing.T) {
+		gateway := server.NewMCPServer("gateway", "1.0.0")
+		p := New(gateway, WithSeparator("."))
+		require.NoError(t, p.AddUpstream(context.Background(), "alpha", alpha))
+		assert.NotNil(t, gateway.GetTool("alpha.search"))
+	})
+}
+
+func TestProxy_RefreshOnListChanged(t *testing.T) {
+	upstream, alpha := newUpstream(t, "alpha", transport.WithContinuousListening())
+
+	gateway := server.NewMCPServer("gateway", "1.0.0")
+	p := New(gateway)
+	require.NoError(t, p.AddUpstream(context.Background(), "alpha", alpha))
+
+	upstream.AddTool(mcp.NewTool("fetch"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("fetched"), nil
+	})
+	require.Eventually(t, func() bool { return gateway.GetTool("alpha_fetch") != nil }, 2*time.Second, 20*time.Millisecond)
+
+	upstream.DeleteTools("search")
+	require.Eventually(t, func() bool { return gateway.GetTool("alpha_search") == nil }, 2*time.Second, 20*time.Millisecond)
+}
+
+// roundTripFunc is an http.RoundTripper implemented by a function.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestProxy_ListChangedDuringFirstSync(t *testing.T) {
+	// The upstream gains a tool right after answering the first tools/list
+	// of the proxy, and its list_changed notification arrives while the
+	// proxy is still listing the other entries.
+	var upstream *server.MCPServer
+	var once sync.Once
+	httpClient := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		var body []byte
+		if r.Body != nil {
+			body, _ = io.ReadAll(r.Body)
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		resp, err := http.DefaultTransport.RoundTrip(r)
+		if err == nil && bytes.Contains(body, []byte(`"tools/list"`)) {
+			once.Do(func() {
+				upstream.AddTool(mcp.NewTool("fetch"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+					return mcp.NewToolResultText("fetched"), nil
+				})
+				time.Sleep(100 * time.Millisecond)
+			})
+		}
+		return resp, err
+	})}
+	upstream, alpha := newUpstream(t, "alpha", transport.WithContinuousListening(), transport.WithHTTPBasicClient(httpClient))
+
+	gateway := server.NewMCPServer("gateway", "1.0.0")
+	p := New(gateway)
+	require.NoError(t, p.AddUpstream(context.Background(), "alpha", alpha))
+	require.Eventually(t, func() bool { return gateway.GetTool("alpha_fetch") != nil }, 2*time.Second, 20*time.Millisecond)
+}
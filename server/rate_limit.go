@@ -0,0 +1,175 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// RateLimit configures a token bucket: up to Burst calls may be made at
+// once, and the bucket refills at Rate calls per second.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimitConfig sets the limits applied to tool calls. A call must be
+// allowed by every configured limit; nil limits are not enforced.
+type RateLimitConfig struct {
+	// Global limits tool calls across all sessions.
+	Global *RateLimit
+	// PerSession limits tool calls of each session separately.
+	PerSession *RateLimit
+	// PerTool limits calls of the named tools across all sessions.
+	PerTool map[string]RateLimit
+}
+
+// WithRateLimit limits the rate of tool calls. Calls over a limit are
+// rejected with a RATE_LIMITED error whose data is an
+// mcp.RateLimitErrorData telling the client when to retry. tools/validate
+// requests take no tokens and report a call that would be rejected as a
+// diagnostic.
+func WithRateLimit(config RateLimitConfig) ServerOption {
+	return func(s *MCPServer) {
+		s.rateLimiter = newRateLimiter(config)
+	}
+}
+
+// tokenBucket is a token bucket refilled continuously at rate tokens per
+// second up to burst tokens.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit RateLimit, now time.Time) *tokenBucket {
+	return &tokenBucket{
+		rate:   limit.Rate,
+		burst:  float64(limit.Burst),
+		tokens: float64(limit.Burst),
+		last:   now,
+	}
+}
+
+// refill adds the tokens accumulated since the last call.
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed*b.rate)
+	}
+	b.last = now
+}
+
+// wait returns how long until a token is available, zero if one is.
+func (b *tokenBucket) wait() time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+	if b.rate <= 0 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// rateLimiter holds the buckets of a RateLimitConfig.
+type rateLimiter struct {
+	config RateLimitConfig
+	now    func() time.Time
+
+	mu       sync.Mutex
+	global   *tokenBucket
+	sessions map[string]*tokenBucket
+	tools    map[string]*tokenBucket
+}
+
+func newRateLimiter(config RateLimitConfig) *rateLimiter {
+	return &rateLimiter{
+		config:   config,
+		now:      time.Now,
+		sessions: make(map[string]*tokenBucket),
+		tools:    make(map[string]*tokenBucket),
+	}
+}
+
+// allow takes a token from every bucket that applies to the call, or
+// returns a RATE_LIMITED error without taking any if one of them is empty.
+func (l *rateLimiter) allow(ctx context.Context, toolName string) error {
+	return l.check(ctx, toolName, true)
+}
+
+// peek returns the error allow would return for the call without taking
+// any tokens.
+func (l *rateLimiter) peek(ctx context.Context, toolName string) error {
+	return l.check(ctx, toolName, false)
+}
+
+// check implements allow and peek; take reports whether an allowed call
+// takes a token from each bucket.
+func (l *rateLimiter) check(ctx context.Context, toolName string, take bool) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+
+	type scopedBucket struct {
+		scope  string
+		bucket *tokenBucket
+	}
+	var buckets []scopedBucket
+	if limit, ok := l.config.PerTool[toolName]; ok {
+		bucket, ok := l.tools[toolName]
+		if !ok {
+			bucket = newTokenBucket(limit, now)
+			l.tools[toolName] = bucket
+		}
+		buckets = append(buckets, scopedBucket{"tool", bucket})
+	}
+	if l.config.PerSession != nil {
+		if session := ClientSessionFromContext(ctx); session != nil {
+			bucket, ok := l.sessions[session.SessionID()]
+			if !ok {
+				bucket = newTokenBucket(*l.config.PerSession, now)
+				l.sessions[session.SessionID()] = bucket
+			}
+			buckets = append(buckets, scopedBucket{"session", bucket})
+		}
+	}
+	if l.config.Global != nil {
+		if l.global == nil {
+			l.global = newTokenBucket(*l.config.Global, now)
+		}
+		buckets = append(buckets, scopedBucket{"global", l.global})
+	}
+
+	for _, b := range buckets {
+		b.bucket.refill(now)
+		if wait := b.bucket.wait(); wait > 0 {
+			return mcp.NewError(mcp.RATE_LIMITED,
+				fmt.Sprintf("%s rate limit exceeded for tool '%s'", b.scope, toolName),
+				mcp.RateLimitErrorData{Scope: b.scope, RetryAfter: wait.Seconds()})
+		}
+	}
+	if take {
+		for _, b := range buckets {
+			b.bucket.tokens--
+		}
+	}
+	return nil
+}
+
+// forgetSession drops the bucket of a session that has gone away.
+func (l *rateLimiter) forgetSession(sessionID string) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	delete(l.sessions, sessionID)
+	l.mu.Unlock()
+}
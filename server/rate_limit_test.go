@@ -0,0 +1,153 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestMCPServer_WithRateLimit(t *testing.T) {
+	type call struct {
+		session string
+		tool    string
+		// method defaults to tools/call.
+		method  string
+		advance time.Duration
+		// expectedScope is the exceeded limit, empty if the call is allowed.
+		expectedScope string
+	}
+
+	tests := []struct {
+		name   string
+		config RateLimitConfig
+		calls  []call
+	}{
+		{
+			name:   "per tool",
+			config: RateLimitConfig{PerTool: map[string]RateLimit{"search": {Rate: 1, Burst: 2}}},
+			calls: []call{
+				{session: "a", tool: "search"},
+				{session: "b", tool: "search"},
+				{session: "a", tool: "search", expectedScope: "tool"},
+				{session: "a", tool: "fetch"},
+				{session: "a", tool: "search", advance: time.Second},
+			},
+		},
+		{
+			name:   "per session",
+			config: RateLimitConfig{PerSession: &RateLimit{Rate: 1, Burst: 1}},
+			calls: []call{
+				{session: "a", tool: "search"},
+				{session: "a", tool: "fetch", expectedScope: "session"},
+				{session: "b", tool: "search"},
+			},
+		},
+		{
+			name:   "global",
+			config: RateLimitConfig{Global: &RateLimit{Rate: 10, Burst: 1}},
+			calls: []call{
+				{session: "a", tool: "search"},
+				{session: "b", tool: "fetch", expectedScope: "global"},
+				{session: "b", tool: "fetch", advance: 100 * time.Millisecond},
+			},
+		},
+		{
+			name: "rejected calls take no tokens",
+			config: RateLimitConfig{
+				PerSession: &RateLimit{Rate: 1, Burst: 2},
+				PerTool:    map[string]RateLimit{"search": {Rate: 1, Burst: 1}},
+			},
+			calls: []call{
+				{session: "a", tool: "search"},
+				{session: "a", tool: "search", expectedScope: "tool"},
+				{session: "a", tool: "fetch"},
+			},
+		},
+		{
+			name:   "validate requests",
+			config: RateLimitConfig{PerTool: map[string]RateLimit{"search": {Rate: 1, Burst: 2}}},
+			calls: []call{
+				{session: "a", tool: "search", method: "tools/validate"},
+				{session: "a", tool: "search", method: "tools/validate"},
+				{session: "a", tool: "search"},
+				{session: "a", tool: "search"},
+				{session: "a", tool: "search", method: "tools/validate", expectedScope: "tool"},
+				{session: "a", tool: "search", expectedScope: "tool"},
+				{session: "a", tool: "search", method: "tools/validate", advance: time.Second},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMCPServer("test-server", "1.0.0", WithRateLimit(tt.config))
+			now := time.Now()
+			server.rateLimiter.now = func() time.Time { return now }
+			server.AddTool(mcp.NewTool("search"), echoToolHandler)
+			server.AddTool(mcp.NewTool("fetch"), echoToolHandler)
+
+			for i, c := range tt.calls {
+				now = now.Add(c.advance)
+				session := fakeSession{sessionID: c.session, notificationChannel: make(chan mcp.JSONRPCNotification, 1), initialized: true}
+				method := c.method
+				if method == "" {
+					method = "tools/call"
+				}
+				message, err := json.Marshal(map[string]any{
+					"jsonrpc": "2.0",
+					"id":      i,
+					"method":  method,
+					"params":  map[string]any{"name": c.tool},
+				})
+				require.NoError(t, err)
+				response := server.HandleMessage(server.WithContext(context.Background(), session), message)
+
+				if method == "tools/validate" {
+					// Validation takes no tokens and reports the limit as a
+					// diagnostic.
+					resp, ok := response.(mcp.JSONRPCResponse)
+					require.True(t, ok, "call %d: expected response, got %#v", i, response)
+					result := resp.Result.(mcp.ValidateToolResult)
+					if c.expectedScope == "" {
+						assert.True(t, result.Valid, "call %d: %v", i, result.Diagnostics)
+						continue
+					}
+					assert.False(t, result.Valid, "call %d", i)
+					require.Len(t, result.Diagnostics, 1)
+					assert.Contains(t, result.Diagnostics[0].Message, c.expectedScope+" rate limit exceeded")
+					continue
+				}
+				if c.expectedScope == "" {
+					assert.IsType(t, mcp.JSONRPCResponse{}, response, "call %d", i)
+					continue
+				}
+				errorResponse, ok := response.(mcp.JSONRPCError)
+				require.True(t, ok, "call %d: expected error response, got %#v", i, response)
+				assert.Equal(t, mcp.RATE_LIMITED, errorResponse.Error.Code)
+				data, ok := errorResponse.Error.Data.(mcp.RateLimitErrorData)
+				require.True(t, ok)
+				assert.Equal(t, c.expectedScope, data.Scope)
+				assert.Greater(t, data.RetryAfter, 0.0)
+			}
+		})
+	}
+}
+
+func TestRateLimiter_ForgetSession(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithRateLimit(RateLimitConfig{PerSession: &RateLimit{Rate: 1, Burst: 1}}))
+	session := fakeSession{sessionID: "a", notificationChannel: make(chan mcp.JSONRPCNotification, 1), initialized: true}
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+
+	ctx := server.WithContext(context.Background(), session)
+	require.NoError(t, server.rateLimiter.allow(ctx, "search"))
+	assert.ErrorIs(t, server.rateLimiter.allow(ctx, "search"), mcp.ErrRateLimited)
+
+	server.UnregisterSession(context.Background(), "a")
+	assert.NoError(t, server.rateLimiter.allow(ctx, "search"))
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// checkToolSchemas reports the tools whose raw schemas are malformed to the
+// OnError hooks. The tools are registered regardless.
+func (s *MCPServer) checkToolSchemas(tools []ServerTool) {
+	for _, tool := range tools {
+		if err := tool.Tool.ValidateSchema(); err != nil {
+			s.hooks.onError(context.Background(), nil, mcp.MethodToolsList, tool.Tool.Name,
+				fmt.Errorf("tool '%s' has a malformed schema: %w", tool.Tool.Name, err))
+		}
+	}
+}
+
+// checkPromptSchemas reports the prompts whose raw arguments schemas are
+// malformed to the OnError hooks. The prompts are registered regardless.
+func (s *MCPServer) checkPromptSchemas(prompts []ServerPrompt) {
+	for _, prompt := range prompts {
+		if err := prompt.Prompt.ValidateSchema(); err != nil {
+			s.hooks.onError(context.Background(), nil, mcp.MethodPromptsList, prompt.Prompt.Name,
+				fmt.Errorf("prompt '%s' has a malformed arguments schema: %w", prompt.Prompt.Name, err))
+		}
+	}
+}
+
+// validatePromptArguments checks the arguments of a prompts/get request
+// against the raw arguments schema of prompt.
+func validatePromptArguments(id any, prompt mcp.Prompt, args map[string]string) *requestError {
+	schemaErrs, err := prompt.ValidateArguments(args)
+	if err == nil && len(schemaErrs) > 0 {
+		err = schemaErrs[0]
+	}
+	if err != nil {
+		return &requestError{
+			id:   id,
+			code: mcp.INVALID_PARAMS,
+			err:  fmt.Errorf("invalid arguments for prompt '%s': %w", prompt.Name, err),
+		}
+	}
+	return nil
+}
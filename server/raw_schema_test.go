@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestMCPServer_AddTools_InvalidRawSchema(t *testing.T) {
+	var hookErrs []error
+	hooks := &Hooks{}
+	hooks.AddOnError(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+		hookErrs = append(hookErrs, err)
+	})
+	s := NewMCPServer("test-server", "1.0.0", WithHooks(hooks))
+
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+	s.AddTools(
+		ServerTool{Tool: mcp.NewToolWithRawSchema("valid", "", json.RawMessage(`{"type":"object","properties":{"id":{"type":"integer"}}}`)), Handler: handler},
+		ServerTool{Tool: mcp.NewToolWithRawSchema("invalid", "", json.RawMessage(`{"type":"object","properties":{"id":{"type":"int"}}}`)), Handler: handler},
+	)
+
+	assert.NotNil(t, s.GetTool("valid"))
+	assert.NotNil(t, s.GetTool("invalid"))
+	require.Len(t, hookErrs, 1)
+	assert.ErrorIs(t, hookErrs[0], mcp.ErrInvalidSchema)
+	assert.ErrorContains(t, hookErrs[0], `tool 'invalid' has a malformed schema: input schema of tool "invalid": invalid schema: /properties/id/type: unknown type "int"`)
+}
+
+func TestMCPServer_AddTools_ECMAPattern(t *testing.T) {
+	var hookErrs []error
+	hooks := &Hooks{}
+	hooks.AddOnError(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+		hookErrs = append(hookErrs, err)
+	})
+	s := NewMCPServer("test-server", "1.0.0", WithHooks(hooks))
+
+	s.AddTool(mcp.NewToolWithRawSchema("password", "", json.RawMessage(`{
+		"type": "object",
+		"properties": {"value": {"type": "string", "pattern": "^(?=.*[0-9]).{8,}$"}}
+	}`)), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	assert.Empty(t, hookErrs)
+	assert.NotNil(t, s.GetTool("password"))
+
+	response := s.HandleMessage(context.Background(), json.RawMessage(
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"password","arguments":{"value":"secret123"}}}`,
+	))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "expected success response, got %#v", response)
+	result, ok := resp.Result.(mcp.CallToolResult)
+	require.True(t, ok)
+	assert.False(t, result.IsError)
+
+	// Validation cannot check the pattern and says so rather than passing
+	response = s.HandleMessage(context.Background(), json.RawMessage(
+		`{"jsonrpc":"2.0","id":2,"method":"tools/validate","params":{"name":"password","arguments":{"value":"secret123"}}}`,
+	))
+	errorResponse, ok := response.(mcp.JSONRPCError)
+	require.True(t, ok, "expected error response, got %#v", response)
+	assert.Contains(t, errorResponse.Error.Message, "invalid pattern")
+}
+
+func TestMCPServer_AddSessionTools_InvalidRawSchema(t *testing.T) {
+	s := NewMCPServer("test-server", "1.0.0")
+	session := &sessionTestClientWithTools{
+		sessionID:           "session-1",
+		notificationChannel: make(chan mcp.JSONRPCNotification, 10),
+		initialized:         true,
+	}
+	require.NoError(t, s.RegisterSession(context.Background(), session))
+
+	err := s.AddSessionTool(session.SessionID(), mcp.NewToolWithRawSchema("invalid", "", json.RawMessage(`{"required":true}`)), nil)
+	assert.ErrorIs(t, err, mcp.ErrInvalidSchema)
+	assert.Empty(t, session.GetSessionTools())
+}
+
+func TestMCPServer_RawPromptArgumentsSchema(t *testing.T) {
+	var hookErrs []error
+	hooks := &Hooks{}
+	hooks.AddOnError(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+		hookErrs = append(hookErrs, err)
+	})
+	s := NewMCPServer("test-server", "1.0.0", WithHooks(hooks))
+
+	handler := func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return mcp.NewGetPromptResult("review", []mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(request.Params.Arguments["code"])),
+		}), nil
+	}
+	s.AddPrompt(mcp.NewPrompt("review", mcp.WithRawArgumentsSchema(json.RawMessage(`{
+		"type": "object",
+		"properties": {"code": {"type": "string", "minLength": 1}},
+		"required": ["code"]
+	}`))), handler)
+	s.AddPrompt(mcp.NewPrompt("broken", mcp.WithRawArgumentsSchema(json.RawMessage(`{"minLength":"1"}`))), handler)
+
+	require.Len(t, hookErrs, 1)
+	assert.ErrorContains(t, hookErrs[0], `prompt 'broken' has a malformed arguments schema`)
+
+	tests := []struct {
+		name    string
+		params  string
+		wantErr string
+	}{
+		{
+			name:   "valid arguments",
+			params: `{"name":"review","arguments":{"code":"x := 1"}}`,
+		},
+		{
+			name:    "missing argument",
+			params:  `{"name":"review"}`,
+			wantErr: "invalid arguments for prompt 'review': /code: required property is missing",
+		},
+		{
+			name:    "short argument",
+			params:  `{"name":"review","arguments":{"code":""}}`,
+			wantErr: "invalid arguments for prompt 'review': /code: expected at least 1 characters, got 0",
+		},
+		{
+			name:   "malformed schema",
+			params: `{"name":"broken","arguments":{"code":""}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := s.HandleMessage(context.Background(), json.RawMessage(
+				`{"jsonrpc":"2.0","id":1,"method":"prompts/get","params":`+tt.params+`}`,
+			))
+			if tt.wantErr == "" {
+				_, ok := response.(mcp.JSONRPCResponse)
+				assert.True(t, ok, "expected success response, got %#v", response)
+				return
+			}
+			resp, ok := response.(mcp.JSONRPCError)
+			require.True(t, ok, "expected error response, got %#v", response)
+			assert.Equal(t, mcp.INVALID_PARAMS, resp.Error.Code)
+			assert.Contains(t, resp.Error.Message, tt.wantErr)
+		})
+	}
+}
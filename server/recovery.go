@@ -0,0 +1,63 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// PanicError is the error a request is answered with when WithRecovery or
+// WithResourceRecovery recovers from a panic in its handler. It is passed
+// to the OnPanic hooks, and can be found with errors.As by OnError hooks.
+type PanicError struct {
+	// Handler describes the handler that panicked, such as "search tool"
+	// or "prompts/get".
+	Handler string
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic recovered in %s handler: %v", e.Handler, e.Value)
+}
+
+// recoverRequest is deferred by HandleMessage when WithRecovery is used. It
+// answers a request whose handling panicked with an internal error. The
+// method and ID are parsed again from message as the panic may have happened
+// before or while HandleMessage parsed them.
+func (s *MCPServer) recoverRequest(ctx context.Context, message json.RawMessage, response *mcp.JSONRPCMessage) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	var baseMessage struct {
+		Method mcp.MCPMethod `json:"method"`
+		ID     mcp.RequestID `json:"id,omitempty"`
+	}
+	_ = json.Unmarshal(message, &baseMessage)
+	id := baseMessage.ID.Value()
+
+	err := &PanicError{Handler: string(baseMessage.Method), Value: r, Stack: debug.Stack()}
+	s.hooks.onPanic(ctx, id, baseMessage.Method, s.redactMessage(ctx, message), err)
+	if id == nil {
+		// Notifications are not answered.
+		*response = nil
+		return
+	}
+	*response = createErrorResponse(id, mcp.INTERNAL_ERROR, err.Error())
+}
+
+// reportRecoveredPanic passes a panic a handler middleware recovered from,
+// and turned into the error of the request, to the OnPanic hooks.
+func (s *MCPServer) reportRecoveredPanic(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		s.hooks.onPanic(ctx, id, method, message, panicErr)
+	}
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestMCPServer_WithRecoveryOnPanic(t *testing.T) {
+	type panicReport struct {
+		id        any
+		method    mcp.MCPMethod
+		sessionID string
+		err       *PanicError
+	}
+
+	tests := []struct {
+		name            string
+		message         string
+		expectedHandler string
+		expectResponse  bool
+	}{
+		{
+			name:            "tool handler",
+			message:         `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"explode"}}`,
+			expectedHandler: "explode tool",
+			expectResponse:  true,
+		},
+		{
+			name:            "prompt handler",
+			message:         `{"jsonrpc":"2.0","id":2,"method":"prompts/get","params":{"name":"explode"}}`,
+			expectedHandler: "prompts/get",
+			expectResponse:  true,
+		},
+		{
+			name:            "notification handler",
+			message:         `{"jsonrpc":"2.0","method":"notifications/explode"}`,
+			expectedHandler: "notifications/explode",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reports []panicReport
+			var errorHookErrs []error
+			hooks := &Hooks{}
+			hooks.AddOnPanic(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err *PanicError) {
+				report := panicReport{id: id, method: method, err: err}
+				if session := ClientSessionFromContext(ctx); session != nil {
+					report.sessionID = session.SessionID()
+				}
+				reports = append(reports, report)
+			})
+			hooks.AddOnError(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+				errorHookErrs = append(errorHookErrs, err)
+			})
+
+			server := NewMCPServer("test-server", "1.0.0", WithRecovery(), WithHooks(hooks))
+			server.AddTool(mcp.NewTool("explode"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				panic("boom")
+			})
+			server.AddPrompt(mcp.NewPrompt("explode"), func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+				panic("boom")
+			})
+			server.AddNotificationHandler("notifications/explode", func(ctx context.Context, notification mcp.JSONRPCNotification) {
+				panic("boom")
+			})
+
+			session := &fakeSession{sessionID: "session-1", notificationChannel: make(chan mcp.JSONRPCNotification, 1)}
+			response := server.HandleMessage(server.WithContext(context.Background(), session), json.RawMessage(tt.message))
+
+			require.Len(t, reports, 1)
+			report := reports[0]
+			assert.Equal(t, "session-1", report.sessionID)
+			assert.Equal(t, tt.expectedHandler, report.err.Handler)
+			assert.Equal(t, "boom", report.err.Value)
+			assert.Contains(t, string(report.err.Stack), "recovery_test.go", "the stack is the one of the panic")
+
+			if !tt.expectResponse {
+				assert.Nil(t, response)
+				return
+			}
+			assert.NotNil(t, report.id)
+			errResponse, ok := response.(mcp.JSONRPCError)
+			require.True(t, ok, "unexpected response %#v", response)
+			assert.Equal(t, mcp.INTERNAL_ERROR, errResponse.Error.Code)
+			assert.Equal(t, "panic recovered in "+tt.expectedHandler+" handler: boom", errResponse.Error.Message)
+			if report.method == mcp.MethodToolsCall {
+				require.Len(t, errorHookErrs, 1)
+				var panicErr *PanicError
+				assert.True(t, errors.As(errorHookErrs[0], &panicErr), "OnError hooks see the panic")
+			}
+		})
+	}
+}
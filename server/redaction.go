@@ -0,0 +1,224 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// RedactionOption configures WithRedaction.
+type RedactionOption func(*redactor)
+
+// RedactPaths masks the argument fields at the given paths in the calls of
+// every tool. A path is a dot-separated list of field names, optionally
+// prefixed with "$.", in which "*" matches any field or array element:
+// "password", "credentials.token" or "accounts.*.secret".
+func RedactPaths(paths ...string) RedactionOption {
+	return RedactToolPaths("", paths...)
+}
+
+// RedactToolPaths masks the argument fields at the given paths in the calls
+// of the named tool. See RedactPaths for the path syntax.
+func RedactToolPaths(tool string, paths ...string) RedactionOption {
+	return func(r *redactor) {
+		for _, path := range paths {
+			r.paths[tool] = append(r.paths[tool], parseRedactionPath(path))
+		}
+	}
+}
+
+// WithRedaction masks sensitive tool arguments before they reach what
+// observes requests rather than handles them: the request hooks, including
+// OnRequestInitialization and OnPanic, and the records of WithAudit. Tool
+// handlers and middlewares still get the actual arguments, so hooks that
+// modify a tool call request have no effect on the call.
+//
+// Fields are masked with RedactedValue when they are at a path set by
+// RedactPaths or RedactToolPaths, or are declared with the x-sensitive
+// annotation in the input schema of the tool, as mcp.Sensitive does.
+func WithRedaction(opts ...RedactionOption) ServerOption {
+	return func(s *MCPServer) {
+		r := &redactor{paths: make(map[string][][]string)}
+		for _, opt := range opts {
+			opt(r)
+		}
+		s.redactor = r
+	}
+}
+
+// RedactArguments returns a copy of the arguments of a call of the named
+// tool with the sensitive fields masked, for example to log them from a
+// middleware. It returns arguments as is without WithRedaction.
+func (s *MCPServer) RedactArguments(ctx context.Context, tool string, arguments any) any {
+	if s.redactor == nil || arguments == nil {
+		return arguments
+	}
+	paths := append(append([][]string{}, s.redactor.paths[""]...), s.redactor.paths[tool]...)
+	if serverTool, ok := s.lookupTool(ctx, tool); ok {
+		paths = append(paths, sensitiveSchemaPaths(serverTool.Tool)...)
+	}
+	if len(paths) == 0 {
+		return arguments
+	}
+
+	redacted := copyJSONValue(arguments)
+	for _, path := range paths {
+		maskPath(redacted, path)
+	}
+	return redacted
+}
+
+// redactor holds the paths set by RedactPaths and RedactToolPaths, keyed by
+// tool name, the empty name applying to every tool.
+type redactor struct {
+	paths map[string][][]string
+}
+
+func parseRedactionPath(path string) []string {
+	return strings.Split(strings.TrimPrefix(path, "$."), ".")
+}
+
+// redactHookRequest returns the request passed to the hooks, which is a
+// redacted copy for tool calls when WithRedaction is used.
+func redactHookRequest[T any](ctx context.Context, s *MCPServer, request *T) *T {
+	if s.redactor == nil {
+		return request
+	}
+	call, ok := any(request).(*mcp.CallToolRequest)
+	if !ok {
+		return request
+	}
+	redacted := *call
+	redacted.Params.Arguments = s.RedactArguments(ctx, call.Params.Name, call.Params.Arguments)
+	return any(&redacted).(*T)
+}
+
+// redactMessage returns message with the arguments of a tool call redacted,
+// for the hooks receiving the raw message.
+func (s *MCPServer) redactMessage(ctx context.Context, message json.RawMessage) json.RawMessage {
+	if s.redactor == nil {
+		return message
+	}
+	var envelope map[string]json.RawMessage
+	if json.Unmarshal(message, &envelope) != nil || string(envelope["method"]) != `"`+string(mcp.MethodToolsCall)+`"` {
+		return message
+	}
+	var params map[string]any
+	if json.Unmarshal(envelope["params"], &params) != nil {
+		return message
+	}
+	name, _ := params["name"].(string)
+	params["arguments"] = s.RedactArguments(ctx, name, params["arguments"])
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return message
+	}
+	envelope["params"] = raw
+	redacted, err := json.Marshal(envelope)
+	if err != nil {
+		return message
+	}
+	return redacted
+}
+
+// sensitiveSchemaPaths returns the paths of the properties annotated with
+// x-sensitive in the input schema of tool.
+func sensitiveSchemaPaths(tool mcp.Tool) [][]string {
+	raw := tool.RawInputSchema
+	if raw == nil {
+		var err error
+		if raw, err = json.Marshal(tool.InputSchema); err != nil {
+			return nil
+		}
+	}
+	var schema map[string]any
+	if json.Unmarshal(raw, &schema) != nil {
+		return nil
+	}
+	var paths [][]string
+	collectSensitivePaths(schema, nil, &paths)
+	return paths
+}
+
+func collectSensitivePaths(schema map[string]any, path []string, paths *[][]string) {
+	if sensitive, _ := schema["x-sensitive"].(bool); sensitive && len(path) > 0 {
+		*paths = append(*paths, append([]string{}, path...))
+		return
+	}
+	if properties, ok := schema["properties"].(map[string]any); ok {
+		for name, property := range properties {
+			if property, ok := property.(map[string]any); ok {
+				collectSensitivePaths(property, append(path, name), paths)
+			}
+		}
+	}
+	if items, ok := schema["items"].(map[string]any); ok {
+		collectSensitivePaths(items, append(path, "*"), paths)
+	}
+}
+
+// copyJSONValue deep copies the maps and slices of a decoded JSON value.
+// Values of other types, such as structs, are converted to their JSON form
+// so that their fields can be masked.
+func copyJSONValue(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(value))
+		for key, field := range value {
+			copied[key] = copyJSONValue(field)
+		}
+		return copied
+	case []any:
+		copied := make([]any, len(value))
+		for i, item := range value {
+			copied[i] = copyJSONValue(item)
+		}
+		return copied
+	case nil, string, bool, float64, json.Number:
+		return value
+	default:
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return value
+		}
+		var decoded any
+		if json.Unmarshal(raw, &decoded) != nil {
+			return value
+		}
+		return decoded
+	}
+}
+
+// maskPath replaces the fields of value at path with RedactedValue.
+func maskPath(value any, path []string) {
+	if len(path) == 0 {
+		return
+	}
+	segment, rest := path[0], path[1:]
+	switch value := value.(type) {
+	case map[string]any:
+		for key, field := range value {
+			if segment != "*" && segment != key {
+				continue
+			}
+			if len(rest) == 0 {
+				value[key] = RedactedValue
+			} else {
+				maskPath(field, rest)
+			}
+		}
+	case []any:
+		if segment != "*" {
+			return
+		}
+		for i, item := range value {
+			if len(rest) == 0 {
+				value[i] = RedactedValue
+			} else {
+				maskPath(item, rest)
+			}
+		}
+	}
+}
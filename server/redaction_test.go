@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestMCPServer_RedactArguments(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   []RedactionOption
+		tool      string
+		arguments any
+		expected  any
+	}{
+		{
+			name:      "top level path",
+			options:   []RedactionOption{RedactPaths("password")},
+			tool:      "login",
+			arguments: map[string]any{"user": "alice", "password": "hunter2"},
+			expected:  map[string]any{"user": "alice", "password": RedactedValue},
+		},
+		{
+			name:      "nested path with wildcard",
+			options:   []RedactionOption{RedactPaths("$.accounts.*.secret")},
+			tool:      "login",
+			arguments: map[string]any{"accounts": []any{map[string]any{"id": 1.0, "secret": "a"}, map[string]any{"secret": "b"}}},
+			expected:  map[string]any{"accounts": []any{map[string]any{"id": 1.0, "secret": RedactedValue}, map[string]any{"secret": RedactedValue}}},
+		},
+		{
+			name:      "tool specific path",
+			options:   []RedactionOption{RedactToolPaths("other", "user")},
+			tool:      "login",
+			arguments: map[string]any{"user": "alice"},
+			expected:  map[string]any{"user": "alice"},
+		},
+		{
+			name:      "schema annotation",
+			tool:      "login",
+			arguments: map[string]any{"user": "alice", "token": "t0ken", "profile": map[string]any{"pin": "1234"}},
+			expected:  map[string]any{"user": "alice", "token": RedactedValue, "profile": map[string]any{"pin": RedactedValue}},
+		},
+		{
+			name:    "struct arguments",
+			options: []RedactionOption{RedactPaths("password")},
+			tool:    "login",
+			arguments: struct {
+				Password string `json:"password"`
+			}{Password: "hunter2"},
+			expected: map[string]any{"password": RedactedValue},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMCPServer("test-server", "1.0.0", WithRedaction(tt.options...))
+			server.AddTool(mcp.NewTool("login",
+				mcp.WithString("user"),
+				mcp.WithString("token", mcp.Sensitive()),
+				mcp.WithObject("profile", mcp.Properties(map[string]any{
+					"pin": map[string]any{"type": "string", "x-sensitive": true},
+				})),
+			), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultText("ok"), nil
+			})
+
+			original, err := json.Marshal(tt.arguments)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, server.RedactArguments(context.Background(), tt.tool, tt.arguments))
+			after, err := json.Marshal(tt.arguments)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(original), string(after), "the arguments are not modified")
+		})
+	}
+}
+
+func TestMCPServer_WithRedactionHooksAndAudit(t *testing.T) {
+	var hookArguments []any
+	var initMessage string
+	hooks := &Hooks{}
+	hooks.AddBeforeCallTool(func(ctx context.Context, id any, message *mcp.CallToolRequest) {
+		hookArguments = append(hookArguments, message.Params.Arguments)
+	})
+	hooks.AddAfterCallTool(func(ctx context.Context, id any, message *mcp.CallToolRequest, result *mcp.CallToolResult) {
+		hookArguments = append(hookArguments, message.Params.Arguments)
+	})
+	hooks.AddOnRequestInitialization(func(ctx context.Context, id any, message any) error {
+		initMessage = string(message.(json.RawMessage))
+		return nil
+	})
+	sink := &memoryAuditSink{}
+	server := NewMCPServer("test-server", "1.0.0",
+		WithHooks(hooks), WithAudit(sink), WithRedaction(RedactPaths("password")))
+
+	var handlerArguments any
+	server.AddTool(mcp.NewTool("login"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		handlerArguments = request.Params.Arguments
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	response := server.HandleMessage(context.Background(), json.RawMessage(
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"login","arguments":{"user":"alice","password":"hunter2"}}}`))
+	_, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "unexpected response %#v", response)
+
+	redacted := map[string]any{"user": "alice", "password": RedactedValue}
+	assert.Equal(t, map[string]any{"user": "alice", "password": "hunter2"}, handlerArguments, "handlers get the actual arguments")
+	assert.Equal(t, []any{redacted, redacted}, hookArguments)
+	assert.NotContains(t, initMessage, "hunter2")
+	assert.Contains(t, initMessage, RedactedValue)
+	require.Len(t, sink.records, 1)
+	assert.Equal(t, redacted, sink.records[0].Arguments)
+}
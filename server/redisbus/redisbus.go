@@ -0,0 +1,303 @@
+// Package redisbus provides a server.NotificationBus on Redis pub/sub, so
+// that the servers of a clustered deployment share their notifications:
+//
+//	bus := redisbus.New("redis:6379")
+//	defer bus.Close()
+//	s := server.NewMCPServer("clustered", "1.0.0", server.WithNotificationBus(bus))
+//
+// It speaks the Redis protocol directly and has no dependencies. Messages
+// published while the subscription is reconnecting are lost, as with any
+// Redis pub/sub subscriber.
+package redisbus
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// DefaultChannel is the Redis channel notifications are published on.
+const DefaultChannel = "mcp:notifications"
+
+// ErrClosed is returned by Publish once the bus is closed.
+var ErrClosed = errors.New("redisbus: bus closed")
+
+// Option configures a Bus.
+type Option func(*Bus)
+
+// WithChannel sets the Redis channel notifications are published on. The
+// servers of a cluster must use the same channel.
+func WithChannel(channel string) Option {
+	return func(b *Bus) {
+		b.channel = channel
+	}
+}
+
+// WithAuth authenticates connections with the AUTH command. An empty
+// username authenticates with the password only, as Redis before 6 does.
+func WithAuth(username, password string) Option {
+	return func(b *Bus) {
+		b.username = username
+		b.password = password
+	}
+}
+
+// WithTLS connects to Redis over TLS with the given configuration.
+func WithTLS(config *tls.Config) Option {
+	return func(b *Bus) {
+		dialer := &tls.Dialer{Config: config}
+		b.dial = dialer.DialContext
+	}
+}
+
+// WithDialer sets the function opening connections to Redis.
+func WithDialer(dial func(ctx context.Context, network, addr string) (net.Conn, error)) Option {
+	return func(b *Bus) {
+		b.dial = dial
+	}
+}
+
+// WithErrorHandler sets a function called with the errors of the
+// subscription, which is retried after each of them. By default they are
+// ignored.
+func WithErrorHandler(handler func(err error)) Option {
+	return func(b *Bus) {
+		b.onError = handler
+	}
+}
+
+// WithTimeout bounds connecting to Redis and publishing a message when the
+// context has no deadline. It defaults to five seconds.
+func WithTimeout(timeout time.Duration) Option {
+	return func(b *Bus) {
+		b.timeout = timeout
+	}
+}
+
+// WithReconnectDelay sets how long the subscription waits before
+// reconnecting after an error. It defaults to one second.
+func WithReconnectDelay(delay time.Duration) Option {
+	return func(b *Bus) {
+		b.reconnectDelay = delay
+	}
+}
+
+// Bus is a server.NotificationBus publishing messages as JSON on a Redis
+// channel. It is safe for concurrent use.
+type Bus struct {
+	addr           string
+	channel        string
+	username       string
+	password       string
+	dial           func(ctx context.Context, network, addr string) (net.Conn, error)
+	onError        func(err error)
+	reconnectDelay time.Duration
+	timeout        time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	publishMu sync.Mutex
+	publisher *conn
+
+	mu         sync.Mutex
+	handlers   []func(message server.BusMessage)
+	subscriber *conn
+	subscribed bool
+	done       chan struct{}
+}
+
+var _ server.NotificationBus = (*Bus)(nil)
+
+// New returns a Bus on the Redis server at addr, such as "localhost:6379".
+// Connections are opened when the bus is first used.
+func New(addr string, opts ...Option) *Bus {
+	var dialer net.Dialer
+	b := &Bus{
+		addr:           addr,
+		channel:        DefaultChannel,
+		dial:           dialer.DialContext,
+		onError:        func(error) {},
+		reconnectDelay: time.Second,
+		timeout:        5 * time.Second,
+		done:           make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	b.ctx, b.cancel = context.WithCancel(context.Background())
+	return b
+}
+
+// Publish implements server.NotificationBus.
+func (b *Bus) Publish(ctx context.Context, message server.BusMessage) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("redisbus: failed to marshal message: %w", err)
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.timeout)
+		defer cancel()
+	}
+
+	b.publishMu.Lock()
+	defer b.publishMu.Unlock()
+	if b.ctx.Err() != nil {
+		return ErrClosed
+	}
+	if b.publisher == nil {
+		c, err := b.connect(ctx)
+		if err != nil {
+			return err
+		}
+		b.publisher = c
+	}
+	if _, err := b.publisher.do(ctx, "PUBLISH", b.channel, string(payload)); err != nil {
+		var redisErr redisError
+		if !errors.As(err, &redisErr) {
+			// The connection is in an unknown state.
+			b.publisher.Close()
+			b.publisher = nil
+		}
+		return fmt.Errorf("redisbus: failed to publish: %w", err)
+	}
+	return nil
+}
+
+// Subscribe implements server.NotificationBus. The first call starts the
+// subscription, which reconnects after errors until the bus is closed.
+func (b *Bus) Subscribe(handler func(message server.BusMessage)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+	if !b.subscribed {
+		b.subscribed = true
+		go b.subscribe()
+	}
+}
+
+// Close stops the subscription and closes the connections to Redis.
+func (b *Bus) Close() error {
+	b.cancel()
+
+	b.mu.Lock()
+	if b.subscriber != nil {
+		b.subscriber.Close()
+	}
+	subscribed := b.subscribed
+	b.mu.Unlock()
+	if subscribed {
+		<-b.done
+	}
+
+	b.publishMu.Lock()
+	defer b.publishMu.Unlock()
+	if b.publisher != nil {
+		b.publisher.Close()
+		b.publisher = nil
+	}
+	return nil
+}
+
+// subscribe receives the messages of the channel until the bus is closed.
+func (b *Bus) subscribe() {
+	defer close(b.done)
+	for {
+		err := b.receive()
+		if b.ctx.Err() != nil {
+			return
+		}
+		b.onError(fmt.Errorf("redisbus: subscription failed: %w", err))
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-time.After(b.reconnectDelay):
+		}
+	}
+}
+
+// receive subscribes to the channel on a new connection and dispatches its
+// messages until the connection fails.
+func (b *Bus) receive() error {
+	ctx, cancel := context.WithTimeout(b.ctx, b.timeout)
+	defer cancel()
+	c, err := b.connect(ctx)
+	if err != nil {
+		return err
+	}
+	b.mu.Lock()
+	if b.ctx.Err() != nil {
+		b.mu.Unlock()
+		c.Close()
+		return b.ctx.Err()
+	}
+	b.subscriber = c
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		b.subscriber = nil
+		b.mu.Unlock()
+		c.Close()
+	}()
+
+	if err := c.send(ctx, "SUBSCRIBE", b.channel); err != nil {
+		return err
+	}
+	// Messages may take any time to arrive
+	if err := c.SetDeadline(time.Time{}); err != nil {
+		return err
+	}
+	for {
+		reply, err := c.readReply()
+		if err != nil {
+			return err
+		}
+		// Pushed messages are ["message", channel, payload].
+		fields, ok := reply.([]any)
+		if !ok || len(fields) != 3 || fields[0] != "message" {
+			continue
+		}
+		payload, _ := fields[2].(string)
+		var message server.BusMessage
+		if err := json.Unmarshal([]byte(payload), &message); err != nil {
+			b.onError(fmt.Errorf("redisbus: invalid message: %w", err))
+			continue
+		}
+		b.mu.Lock()
+		handlers := b.handlers
+		b.mu.Unlock()
+		for _, handler := range handlers {
+			handler(message)
+		}
+	}
+}
+
+// connect opens an authenticated connection.
+func (b *Bus) connect(ctx context.Context) (*conn, error) {
+	netConn, err := b.dial(ctx, "tcp", b.addr)
+	if err != nil {
+		return nil, fmt.Errorf("redisbus: failed to connect: %w", err)
+	}
+	c := &conn{Conn: netConn, r: bufio.NewReader(netConn)}
+	if b.password != "" {
+		args := []string{"AUTH", b.password}
+		if b.username != "" {
+			args = []string{"AUTH", b.username, b.password}
+		}
+		if _, err := c.do(ctx, args...); err != nil {
+			c.Close()
+			return nil, fmt.Errorf("redisbus: failed to authenticate: %w", err)
+		}
+	}
+	return c, nil
+}
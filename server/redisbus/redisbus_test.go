@@ -0,0 +1,283 @@
+package redisbus
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// fakeRedis implements AUTH, PUBLISH and SUBSCRIBE of the Redis protocol.
+type fakeRedis struct {
+	listener net.Listener
+	password string
+
+	mu          sync.Mutex
+	subscribers map[string][]net.Conn
+	conns       []net.Conn
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	r := &fakeRedis{listener: listener, password: password, subscribers: map[string][]net.Conn{}}
+	go r.serve()
+	t.Cleanup(r.close)
+	return r
+}
+
+func (r *fakeRedis) addr() string {
+	return r.listener.Addr().String()
+}
+
+func (r *fakeRedis) close() {
+	r.listener.Close()
+	r.dropConnections()
+}
+
+// dropConnections closes every client connection.
+func (r *fakeRedis) dropConnections() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.conns {
+		c.Close()
+	}
+	r.conns = nil
+	r.subscribers = map[string][]net.Conn{}
+}
+
+func (r *fakeRedis) subscriberCount(channel string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.subscribers[channel])
+}
+
+func (r *fakeRedis) serve() {
+	for {
+		c, err := r.listener.Accept()
+		if err != nil {
+			return
+		}
+		r.mu.Lock()
+		r.conns = append(r.conns, c)
+		r.mu.Unlock()
+		go r.handle(c)
+	}
+}
+
+func (r *fakeRedis) handle(netConn net.Conn) {
+	c := &conn{Conn: netConn, r: bufio.NewReader(netConn)}
+	authenticated := r.password == ""
+	for {
+		reply, err := c.readReply()
+		if err != nil {
+			return
+		}
+		args, _ := reply.([]any)
+		if len(args) == 0 {
+			return
+		}
+		command, _ := args[0].(string)
+		switch {
+		case strings.EqualFold(command, "AUTH"):
+			if args[len(args)-1] == r.password {
+				authenticated = true
+				netConn.Write([]byte("+OK\r\n"))
+			} else {
+				netConn.Write([]byte("-WRONGPASS invalid password\r\n"))
+			}
+		case !authenticated:
+			netConn.Write([]byte("-NOAUTH Authentication required.\r\n"))
+		case strings.EqualFold(command, "SUBSCRIBE"):
+			channel := args[1].(string)
+			r.mu.Lock()
+			r.subscribers[channel] = append(r.subscribers[channel], netConn)
+			r.mu.Unlock()
+			writeArray(netConn, "subscribe", channel)
+		case strings.EqualFold(command, "PUBLISH"):
+			channel, payload := args[1].(string), args[2].(string)
+			r.mu.Lock()
+			subscribers := r.subscribers[channel]
+			for _, subscriber := range subscribers {
+				writeArray(subscriber, "message", channel, payload)
+			}
+			r.mu.Unlock()
+			netConn.Write([]byte(":" + strconv.Itoa(len(subscribers)) + "\r\n"))
+		default:
+			netConn.Write([]byte("-ERR unknown command\r\n"))
+		}
+	}
+}
+
+func writeArray(w net.Conn, values ...string) {
+	var b strings.Builder
+	b.WriteString("*" + strconv.Itoa(len(values)) + "\r\n")
+	for _, v := range values {
+		b.WriteString("$" + strconv.Itoa(len(v)) + "\r\n" + v + "\r\n")
+	}
+	w.Write([]byte(b.String()))
+}
+
+// testSession is an initialized session buffering its notifications.
+type testSession struct {
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *testSession) SessionID() string { return "session-b" }
+func (s *testSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+func (s *testSession) Initialize()       {}
+func (s *testSession) Initialized() bool { return true }
+
+func TestBus(t *testing.T) {
+	redis := newFakeRedis(t, "secret")
+	errs := make(chan error, 10)
+	newBus := func() *Bus {
+		bus := New(redis.addr(),
+			WithAuth("", "secret"),
+			WithChannel("test"),
+			WithReconnectDelay(10*time.Millisecond),
+			WithErrorHandler(func(err error) { errs <- err }),
+		)
+		t.Cleanup(func() { bus.Close() })
+		return bus
+	}
+
+	received := make(chan server.BusMessage, 10)
+	subscriber := newBus()
+	subscriber.Subscribe(func(message server.BusMessage) { received <- message })
+	require.Eventually(t, func() bool { return redis.subscriberCount("test") == 1 }, time.Second, 5*time.Millisecond)
+
+	publisher := newBus()
+	notification := mcp.JSONRPCNotification{JSONRPC: mcp.JSONRPC_VERSION, Notification: mcp.Notification{Method: "notifications/custom"}}
+	require.NoError(t, publisher.Publish(context.Background(), server.BusMessage{Origin: "a", Notification: &notification}))
+
+	select {
+	case message := <-received:
+		assert.Equal(t, "a", message.Origin)
+		require.NotNil(t, message.Notification)
+		assert.Equal(t, "notifications/custom", message.Notification.Method)
+	case <-time.After(time.Second):
+		t.Fatal("message not received")
+	}
+
+	t.Run("reconnect", func(t *testing.T) {
+		redis.dropConnections()
+		select {
+		case err := <-errs:
+			assert.ErrorContains(t, err, "subscription failed")
+		case <-time.After(time.Second):
+			t.Fatal("error not reported")
+		}
+		require.Eventually(t, func() bool { return redis.subscriberCount("test") == 1 }, time.Second, 5*time.Millisecond)
+
+		// The first publish notices the dropped connection.
+		err := publisher.Publish(context.Background(), server.BusMessage{Origin: "a", ResourceURI: "test://doc"})
+		if err != nil {
+			require.NoError(t, publisher.Publish(context.Background(), server.BusMessage{Origin: "a", ResourceURI: "test://doc"}))
+		}
+		select {
+		case message := <-received:
+			assert.Equal(t, "test://doc", message.ResourceURI)
+		case <-time.After(time.Second):
+			t.Fatal("message not received after reconnecting")
+		}
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		bus := newBus()
+		require.NoError(t, bus.Close())
+		assert.ErrorIs(t, bus.Publish(context.Background(), server.BusMessage{}), ErrClosed)
+	})
+}
+
+func TestBus_WrongPassword(t *testing.T) {
+	redis := newFakeRedis(t, "secret")
+	bus := New(redis.addr(), WithAuth("user", "wrong"))
+	defer bus.Close()
+	err := bus.Publish(context.Background(), server.BusMessage{Origin: "a"})
+	assert.ErrorContains(t, err, "failed to authenticate: WRONGPASS")
+}
+
+func TestBus_Servers(t *testing.T) {
+	redis := newFakeRedis(t, "")
+	newNode := func() *server.MCPServer {
+		bus := New(redis.addr())
+		t.Cleanup(func() { bus.Close() })
+		return server.NewMCPServer("node", "1.0.0", server.WithNotificationBus(bus))
+	}
+	a := newNode()
+	b := newNode()
+	require.Eventually(t, func() bool { return redis.subscriberCount(DefaultChannel) == 2 }, time.Second, 5*time.Millisecond)
+
+	session := &testSession{notifications: make(chan mcp.JSONRPCNotification, 1)}
+	require.NoError(t, b.RegisterSession(context.Background(), session))
+
+	a.SendNotificationToAllClients("notifications/custom", map[string]any{"from": "a"})
+	select {
+	case notification := <-session.notifications:
+		assert.Equal(t, "notifications/custom", notification.Method)
+		assert.Equal(t, "a", notification.Params.AdditionalFields["from"])
+	case <-time.After(time.Second):
+		t.Fatal("notification not delivered to the other node")
+	}
+}
+
+func TestBus_Timeout(t *testing.T) {
+	// A server accepting connections without ever replying
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		var conns []net.Conn
+		defer func() {
+			for _, c := range conns {
+				c.Close()
+			}
+		}()
+		for {
+			c, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, c)
+		}
+	}()
+
+	bus := New(listener.Addr().String(), WithTimeout(50*time.Millisecond))
+	defer bus.Close()
+	start := time.Now()
+	err = bus.Publish(context.Background(), server.BusMessage{Origin: "a"})
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestReadReply_Limits(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply string
+		err   string
+	}{
+		{name: "bulk string", reply: "$" + strconv.Itoa(maxBulkLength+1) + "\r\n", err: "invalid bulk string length"},
+		{name: "array", reply: "*" + strconv.Itoa(maxArrayLength+1) + "\r\n", err: "invalid array length"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &conn{r: bufio.NewReader(strings.NewReader(tt.reply))}
+			_, err := c.readReply()
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package server
+
+import "sync"
+
+// registryShards is the number of shards of a registry. It is a power of
+// two, so that names are assigned to shards by masking their hash.
+const registryShards = 32
+
+// registry maps the names of the tools, prompts or resources of a server to
+// their entries. Entries are split into shards by the hash of their name,
+// each with its own lock, so that looking a name up only waits for
+// registrations of names in the same shard: servers registering tools at a
+// high rate, such as multi-tenant servers, do not stall tools/call.
+//
+// A registry keeps no order among its entries, and makes single operations
+// atomic only: callers changing several entries at once serialize their
+// changes with a lock of their own, and readers may see some of the changes
+// of another caller before others. The zero value is an empty registry.
+type registry[V any] struct {
+	shards [registryShards]registryShard[V]
+}
+
+type registryShard[V any] struct {
+	mu      sync.RWMutex
+	entries map[string]V
+}
+
+// shardIndex returns the index of the shard of name, chosen by its FNV-1a
+// hash.
+func shardIndex(name string) int {
+	hash := uint32(2166136261)
+	for i := 0; i < len(name); i++ {
+		hash ^= uint32(name[i])
+		hash *= 16777619
+	}
+	return int(hash & (registryShards - 1))
+}
+
+func (r *registry[V]) shard(name string) *registryShard[V] {
+	return &r.shards[shardIndex(name)]
+}
+
+// get returns the entry registered under name.
+func (r *registry[V]) get(name string) (V, bool) {
+	shard := r.shard(name)
+	shard.mu.RLock()
+	entry, ok := shard.entries[name]
+	shard.mu.RUnlock()
+	return entry, ok
+}
+
+// set registers entry under name, and returns the entry it replaces.
+func (r *registry[V]) set(name string, entry V) (V, bool) {
+	shard := r.shard(name)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	if shard.entries == nil {
+		shard.entries = make(map[string]V)
+	}
+	previous, ok := shard.entries[name]
+	shard.entries[name] = entry
+	return previous, ok
+}
+
+// delete removes the entry registered under name and returns it.
+func (r *registry[V]) delete(name string) (V, bool) {
+	shard := r.shard(name)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	entry, ok := shard.entries[name]
+	if ok {
+		delete(shard.entries, name)
+	}
+	return entry, ok
+}
+
+// len returns the number of entries.
+func (r *registry[V]) len() int {
+	n := 0
+	for i := range r.shards {
+		shard := &r.shards[i]
+		shard.mu.RLock()
+		n += len(shard.entries)
+		shard.mu.RUnlock()
+	}
+	return n
+}
+
+// each calls f with the entries, one shard at a time, until f returns
+// false. The shard of the entry is locked while f runs, so f must not
+// change the registry.
+func (r *registry[V]) each(f func(name string, entry V) bool) {
+	for i := range r.shards {
+		shard := &r.shards[i]
+		shard.mu.RLock()
+		for name, entry := range shard.entries {
+			if !f(name, entry) {
+				shard.mu.RUnlock()
+				return
+			}
+		}
+		shard.mu.RUnlock()
+	}
+}
+
+// snapshot returns a copy of the entries.
+func (r *registry[V]) snapshot() map[string]V {
+	entries := make(map[string]V, r.len())
+	r.each(func(name string, entry V) bool {
+		entries[name] = entry
+		return true
+	})
+	return entries
+}
+
+// reset replaces the entries with entries, and returns the replaced ones.
+// Each shard is replaced at once, so that readers never miss names that
+// are both replaced and registered again.
+func (r *registry[V]) reset(entries map[string]V) map[string]V {
+	var next [registryShards]map[string]V
+	for name, entry := range entries {
+		i := shardIndex(name)
+		if next[i] == nil {
+			next[i] = make(map[string]V)
+		}
+		next[i][name] = entry
+	}
+	previous := make(map[string]V)
+	for i := range r.shards {
+		shard := &r.shards[i]
+		shard.mu.Lock()
+		for name, entry := range shard.entries {
+			previous[name] = entry
+		}
+		shard.entries = next[i]
+		shard.mu.Unlock()
+	}
+	return previous
+}
@@ -0,0 +1,286 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestRegistry(t *testing.T) {
+	var r registry[int]
+	_, ok := r.get("a")
+	assert.False(t, ok, "the zero value is empty")
+
+	for i := range 100 {
+		_, replaced := r.set(fmt.Sprintf("name%d", i), i)
+		require.False(t, replaced)
+	}
+	previous, replaced := r.set("name7", 70)
+	assert.True(t, replaced)
+	assert.Equal(t, 7, previous)
+	entry, ok := r.get("name7")
+	assert.True(t, ok)
+	assert.Equal(t, 70, entry)
+	assert.Equal(t, 100, r.len())
+
+	removed, ok := r.delete("name8")
+	assert.True(t, ok)
+	assert.Equal(t, 8, removed)
+	_, ok = r.delete("name8")
+	assert.False(t, ok)
+	assert.Equal(t, 99, r.len())
+	assert.Len(t, r.snapshot(), 99)
+
+	visited := 0
+	r.each(func(string, int) bool {
+		visited++
+		return visited < 10
+	})
+	assert.Equal(t, 10, visited, "each stops when f returns false")
+
+	previousEntries := r.reset(map[string]int{"name1": 1, "other": 2})
+	assert.Len(t, previousEntries, 99)
+	assert.Equal(t, 70, previousEntries["name7"])
+	assert.Equal(t, map[string]int{"name1": 1, "other": 2}, r.snapshot())
+}
+
+func TestRegistry_Concurrency(t *testing.T) {
+	var r registry[int]
+	var wg sync.WaitGroup
+	for writer := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 1000 {
+				name := fmt.Sprintf("w%d-%d", writer, i)
+				r.set(name, i)
+				if i%2 == 1 {
+					r.delete(name)
+				}
+			}
+		}()
+	}
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 1000 {
+				r.get(fmt.Sprintf("w0-%d", i))
+				if i%100 == 0 {
+					r.snapshot()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 2000, r.len())
+}
+
+func TestMCPServer_ConcurrentToolRegistration(t *testing.T) {
+	server := NewMCPServer("test", "1.0.0", WithToolCapabilities(false))
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+	server.AddTools(ServerTool{Tool: mcp.NewTool("stable"), Handler: handler, Aliases: []ToolAlias{{Name: "steady"}}})
+
+	var wg sync.WaitGroup
+	var calls atomic.Int32
+	for writer := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 200 {
+				name := fmt.Sprintf("dynamic-%d-%d", writer, i)
+				server.AddTool(mcp.NewTool(name), handler)
+				if i%2 == 1 {
+					server.DeleteTools(name)
+				}
+			}
+		}()
+	}
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 200 {
+				for _, name := range []string{"stable", "steady"} {
+					if _, ok := server.lookupTool(context.Background(), name); ok {
+						calls.Add(1)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(4*200*2), calls.Load(), "stable tools stay visible while others are registered")
+	tools := server.listTools(context.Background())
+	assert.Len(t, tools, 1+4*100)
+	assert.True(t, sort.SliceIsSorted(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name }))
+}
+
+// rwMutexRegistry is a map guarded by a single lock, as the registries of
+// the server were, for comparison in benchmarks.
+type rwMutexRegistry[V any] struct {
+	mu      sync.RWMutex
+	entries map[string]V
+}
+
+func (r *rwMutexRegistry[V]) get(name string) (V, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, ok := r.entries[name]
+	return entry, ok
+}
+
+func (r *rwMutexRegistry[V]) set(name string, entry V) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[name] = entry
+}
+
+// BenchmarkRegistry_LookupWhileRegistering measures looking up 10k tools
+// while another goroutine keeps registering tools, with a registry and with
+// a map guarded by a single lock.
+func BenchmarkRegistry_LookupWhileRegistering(b *testing.B) {
+	tools := getTools(10000)
+	names := make([]string, len(tools))
+	for i, tool := range tools {
+		names[i] = tool.Name
+	}
+
+	run := func(b *testing.B, get func(string) bool, set func(string, ServerTool)) {
+		for _, tool := range tools {
+			set(tool.Name, ServerTool{Tool: tool})
+		}
+		stop := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				default:
+					tool := tools[i%len(tools)]
+					set(tool.Name, ServerTool{Tool: tool})
+				}
+			}
+		}()
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				if !get(names[i%len(names)]) {
+					b.Error("tool not found")
+				}
+				i++
+			}
+		})
+		b.StopTimer()
+		close(stop)
+		<-done
+	}
+
+	b.Run("RWMutex", func(b *testing.B) {
+		r := &rwMutexRegistry[ServerTool]{entries: make(map[string]ServerTool)}
+		run(b, func(name string) bool {
+			_, ok := r.get(name)
+			return ok
+		}, r.set)
+	})
+	b.Run("Sharded", func(b *testing.B) {
+		var r registry[ServerTool]
+		run(b, func(name string) bool {
+			_, ok := r.get(name)
+			return ok
+		}, func(name string, tool ServerTool) { r.set(name, tool) })
+	})
+}
+
+// BenchmarkMCPServer_AddTools10k measures registering 10k tools one at a
+// time.
+func BenchmarkMCPServer_AddTools10k(b *testing.B) {
+	tools := getTools(10000)
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		server := NewMCPServer("test", "1.0.0", WithToolCapabilities(false))
+		for _, tool := range tools {
+			server.AddTool(tool, handler)
+		}
+	}
+}
+
+// BenchmarkMCPServer_CallToolWhileRegistering measures tools/call requests
+// to a server with 10k tools while tools are registered and deleted
+// concurrently, as multi-tenant servers do.
+func BenchmarkMCPServer_CallToolWhileRegistering(b *testing.B) {
+	server := NewMCPServer("test", "1.0.0", WithToolCapabilities(false))
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+	for _, tool := range getTools(10000) {
+		server.AddTool(tool, handler)
+	}
+	messages := make([][]byte, 100)
+	for i := range messages {
+		messages[i] = []byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":"tool%d"}}`, i, i*97))
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+				name := fmt.Sprintf("dynamic%d", i%1000)
+				server.AddTool(mcp.NewTool(name), handler)
+				server.DeleteTools(name)
+			}
+		}
+	}()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if _, ok := server.HandleMessage(context.Background(), messages[i%len(messages)]).(mcp.JSONRPCResponse); !ok {
+				b.Error("tool call failed")
+			}
+			i++
+		}
+	})
+	b.StopTimer()
+	close(stop)
+	<-done
+}
+
+// BenchmarkMCPServer_ListTools10k measures listing 10k tools.
+func BenchmarkMCPServer_ListTools10k(b *testing.B) {
+	server := NewMCPServer("test", "1.0.0", WithToolCapabilities(false))
+	for _, tool := range getTools(10000) {
+		server.AddTool(tool, nil)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if tools := server.listTools(context.Background()); len(tools) != 10000 {
+			b.Fatalf("listed %d tools", len(tools))
+		}
+	}
+}
@@ -5,7 +5,6 @@ package server
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -15,19 +14,32 @@ import (
 func (s *MCPServer) HandleMessage(
 	ctx context.Context,
 	message json.RawMessage,
-) mcp.JSONRPCMessage {
+) (response mcp.JSONRPCMessage) {
 	// Add server to context
 	ctx = context.WithValue(ctx, serverKey{}, s)
 	var err *requestError
 
+	if s.recovery {
+		defer s.recoverRequest(ctx, message, &response)
+	}
+
+	if rejected, ok := s.rejectOversizedMessage(ctx, message); ok {
+		return rejected
+	}
+
+	if rejected, ok := s.rejectInvalidMessage(ctx, message); ok {
+		return rejected
+	}
+
 	var baseMessage struct {
-		JSONRPC string        `json:"jsonrpc"`
-		Method  mcp.MCPMethod `json:"method"`
-		ID      any           `json:"id,omitempty"`
-		Result  any           `json:"result,omitempty"`
+		JSONRPC string          `json:"jsonrpc"`
+		Method  mcp.MCPMethod   `json:"method"`
+		ID      json.RawMessage `json:"id,omitempty"`
+		Result  any             `json:"result,omitempty"`
+		Error   json.RawMessage `json:"error,omitempty"`
 	}
 
-	if err := json.Unmarshal(message, &baseMessage); err != nil {
+	if err := s.jsonCodec.Unmarshal(message, &baseMessage); err != nil {
 		return createErrorResponse(
 			nil,
 			mcp.PARSE_ERROR,
@@ -35,18 +47,39 @@ func (s *MCPServer) HandleMessage(
 		)
 	}
 
+	// Integer IDs are decoded as int64 rather than float64, so that they are
+	// echoed and reported to hooks exactly as they were sent
+	var requestID mcp.RequestID
+	if baseMessage.ID != nil {
+		if err := requestID.UnmarshalJSON(baseMessage.ID); err != nil {
+			return createErrorResponse(
+				nil,
+				mcp.PARSE_ERROR,
+				"Failed to parse message",
+			)
+		}
+	}
+	id := requestID.Value()
+
 	// Check for valid JSONRPC version
 	if baseMessage.JSONRPC != mcp.JSONRPC_VERSION {
 		return createErrorResponse(
-			baseMessage.ID,
+			id,
 			mcp.INVALID_REQUEST,
 			"Invalid JSON-RPC version",
 		)
 	}
 
+	// Every message from the client, including responses to server pings,
+	// counts as activity of its session
+	s.sessionTTL.touch(ClientSessionFromContext(ctx))
+
+	// Only a message without an ID is a notification. A request must not
+	// have a null ID, while a response with one reports an error the client
+	// could not attribute to a request
 	if baseMessage.ID == nil {
 		var notification mcp.JSONRPCNotification
-		if err := json.Unmarshal(message, &notification); err != nil {
+		if err := s.jsonCodec.Unmarshal(message, &notification); err != nil {
 			return createErrorResponse(
 				nil,
 				mcp.PARSE_ERROR,
@@ -56,6 +89,16 @@ func (s *MCPServer) HandleMessage(
 		s.handleNotification(ctx, notification)
 		return nil // Return nil for notifications
 	}
+	if id == nil {
+		if baseMessage.Result != nil || baseMessage.Error != nil {
+			return nil
+		}
+		return createErrorResponse(
+			nil,
+			mcp.INVALID_REQUEST,
+			"Request ID must not be null",
+		)
+	}
 
 	if baseMessage.Result != nil {
 		// this is a response to a request sent by the server (e.g. from a ping
@@ -63,10 +106,18 @@ func (s *MCPServer) HandleMessage(
 		return nil
 	}
 
-	handleErr := s.hooks.onRequestInitialization(ctx, baseMessage.ID, message)
+	ctx = s.withPropagatedMeta(ctx, message)
+	ctx, endSpan := s.startRequestSpan(ctx, baseMessage.Method, id, message)
+	defer func() { endSpan(response) }()
+	logRequest := s.logRequest(ctx, baseMessage.Method, id)
+	defer func() { logRequest(response) }()
+	endAudit := s.startAudit(ctx, baseMessage.Method, id, message)
+	defer func() { endAudit(response) }()
+
+	handleErr := s.hooks.onRequestInitialization(ctx, id, s.redactMessage(ctx, message))
 	if handleErr != nil {
 		return createErrorResponse(
-			baseMessage.ID,
+			id,
 			mcp.INVALID_REQUEST,
 			handleErr.Error(),
 		)
@@ -80,260 +131,470 @@ func (s *MCPServer) HandleMessage(
 		headers = make(http.Header)
 	}
 
+	ctx, tenantErr := s.tenancy.bind(ctx, id)
+	if tenantErr != nil {
+		s.hooks.onError(ctx, id, baseMessage.Method, nil, tenantErr)
+		return tenantErr.ToJSONRPCError()
+	}
+
+	ctx, cancelTimeout := s.withRequestTimeout(ctx, baseMessage.Method, message)
+	defer cancelTimeout()
+	ctx, cancelBudget := s.withDurationLimit(ctx, baseMessage.Method)
+	defer cancelBudget()
+
+	release, schedErr := s.scheduler.acquire(ctx, id, baseMessage.Method, message)
+	if schedErr != nil {
+		s.hooks.onError(ctx, id, baseMessage.Method, nil, schedErr)
+		return schedErr.ToJSONRPCError()
+	}
+	defer release()
+
 	switch baseMessage.Method {
 	case mcp.MethodInitialize:
 		var request mcp.InitializeRequest
 		var result *mcp.InitializeResult
-		if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
+		hookRequest := &request
+		if unmarshalErr := s.jsonCodec.Unmarshal(message, &request); unmarshalErr != nil {
 			err = &requestError{
-				id:   baseMessage.ID,
+				id:   id,
 				code: mcp.INVALID_REQUEST,
 				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
 			}
 		} else {
 			request.Header = headers
-			s.hooks.beforeInitialize(ctx, baseMessage.ID, &request)
-			result, err = s.handleInitialize(ctx, baseMessage.ID, request)
+			hookRequest = redactHookRequest(ctx, s, &request)
+			s.hooks.beforeInitialize(ctx, id, hookRequest)
+			result, err = s.handleInitialize(ctx, id, request)
+			if timeoutErr := requestTimeoutError(ctx, id); timeoutErr != nil {
+				err = timeoutErr
+			} else if limitErr := s.durationLimitError(ctx, id); limitErr != nil {
+				err = limitErr
+			}
 		}
 		if err != nil {
-			s.hooks.onError(ctx, baseMessage.ID, baseMessage.Method, &request, err)
+			s.reportRecoveredPanic(ctx, id, baseMessage.Method, hookRequest, err)
+			s.hooks.onError(ctx, id, baseMessage.Method, hookRequest, err)
 			return err.ToJSONRPCError()
 		}
-		s.hooks.afterInitialize(ctx, baseMessage.ID, &request, result)
-		return createResponse(baseMessage.ID, *result)
+		s.hooks.afterInitialize(ctx, id, hookRequest, result)
+		reply := *result
+		propagateResultMeta(ctx, &reply.Meta)
+		return createResponse(id, reply)
 	case mcp.MethodPing:
 		var request mcp.PingRequest
 		var result *mcp.EmptyResult
-		if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
+		hookRequest := &request
+		if unmarshalErr := s.jsonCodec.Unmarshal(message, &request); unmarshalErr != nil {
 			err = &requestError{
-				id:   baseMessage.ID,
+				id:   id,
 				code: mcp.INVALID_REQUEST,
 				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
 			}
 		} else {
 			request.Header = headers
-			s.hooks.beforePing(ctx, baseMessage.ID, &request)
-			result, err = s.handlePing(ctx, baseMessage.ID, request)
+			hookRequest = redactHookRequest(ctx, s, &request)
+			s.hooks.beforePing(ctx, id, hookRequest)
+			result, err = s.handlePing(ctx, id, request)
+			if timeoutErr := requestTimeoutError(ctx, id); timeoutErr != nil {
+				err = timeoutErr
+			} else if limitErr := s.durationLimitError(ctx, id); limitErr != nil {
+				err = limitErr
+			}
 		}
 		if err != nil {
-			s.hooks.onError(ctx, baseMessage.ID, baseMessage.Method, &request, err)
+			s.reportRecoveredPanic(ctx, id, baseMessage.Method, hookRequest, err)
+			s.hooks.onError(ctx, id, baseMessage.Method, hookRequest, err)
 			return err.ToJSONRPCError()
 		}
-		s.hooks.afterPing(ctx, baseMessage.ID, &request, result)
-		return createResponse(baseMessage.ID, *result)
+		s.hooks.afterPing(ctx, id, hookRequest, result)
+		reply := *result
+		propagateResultMeta(ctx, &reply.Meta)
+		return createResponse(id, reply)
 	case mcp.MethodSetLogLevel:
 		var request mcp.SetLevelRequest
 		var result *mcp.EmptyResult
+		hookRequest := &request
 		if s.capabilities.logging == nil {
+			err = capabilityError(id, baseMessage.Method, "logging")
+		} else if unmarshalErr := s.jsonCodec.Unmarshal(message, &request); unmarshalErr != nil {
 			err = &requestError{
-				id:   baseMessage.ID,
-				code: mcp.METHOD_NOT_FOUND,
-				err:  fmt.Errorf("logging %w", ErrUnsupported),
-			}
-		} else if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
-			err = &requestError{
-				id:   baseMessage.ID,
+				id:   id,
 				code: mcp.INVALID_REQUEST,
 				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
 			}
 		} else {
 			request.Header = headers
-			s.hooks.beforeSetLevel(ctx, baseMessage.ID, &request)
-			result, err = s.handleSetLevel(ctx, baseMessage.ID, request)
+			hookRequest = redactHookRequest(ctx, s, &request)
+			s.hooks.beforeSetLevel(ctx, id, hookRequest)
+			result, err = s.handleSetLevel(ctx, id, request)
+			if timeoutErr := requestTimeoutError(ctx, id); timeoutErr != nil {
+				err = timeoutErr
+			} else if limitErr := s.durationLimitError(ctx, id); limitErr != nil {
+				err = limitErr
+			}
 		}
 		if err != nil {
-			s.hooks.onError(ctx, baseMessage.ID, baseMessage.Method, &request, err)
+			s.reportRecoveredPanic(ctx, id, baseMessage.Method, hookRequest, err)
+			s.hooks.onError(ctx, id, baseMessage.Method, hookRequest, err)
 			return err.ToJSONRPCError()
 		}
-		s.hooks.afterSetLevel(ctx, baseMessage.ID, &request, result)
-		return createResponse(baseMessage.ID, *result)
+		s.hooks.afterSetLevel(ctx, id, hookRequest, result)
+		reply := *result
+		propagateResultMeta(ctx, &reply.Meta)
+		return createResponse(id, reply)
 	case mcp.MethodResourcesList:
 		var request mcp.ListResourcesRequest
 		var result *mcp.ListResourcesResult
+		hookRequest := &request
 		if s.capabilities.resources == nil {
+			err = capabilityError(id, baseMessage.Method, "resources")
+		} else if unmarshalErr := s.jsonCodec.Unmarshal(message, &request); unmarshalErr != nil {
 			err = &requestError{
-				id:   baseMessage.ID,
-				code: mcp.METHOD_NOT_FOUND,
-				err:  fmt.Errorf("resources %w", ErrUnsupported),
-			}
-		} else if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
-			err = &requestError{
-				id:   baseMessage.ID,
+				id:   id,
 				code: mcp.INVALID_REQUEST,
 				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
 			}
 		} else {
 			request.Header = headers
-			s.hooks.beforeListResources(ctx, baseMessage.ID, &request)
-			result, err = s.handleListResources(ctx, baseMessage.ID, request)
+			hookRequest = redactHookRequest(ctx, s, &request)
+			s.hooks.beforeListResources(ctx, id, hookRequest)
+			result, err = s.handleListResources(ctx, id, request)
+			if timeoutErr := requestTimeoutError(ctx, id); timeoutErr != nil {
+				err = timeoutErr
+			} else if limitErr := s.durationLimitError(ctx, id); limitErr != nil {
+				err = limitErr
+			}
 		}
 		if err != nil {
-			s.hooks.onError(ctx, baseMessage.ID, baseMessage.Method, &request, err)
+			s.reportRecoveredPanic(ctx, id, baseMessage.Method, hookRequest, err)
+			s.hooks.onError(ctx, id, baseMessage.Method, hookRequest, err)
 			return err.ToJSONRPCError()
 		}
-		s.hooks.afterListResources(ctx, baseMessage.ID, &request, result)
-		return createResponse(baseMessage.ID, *result)
+		s.hooks.afterListResources(ctx, id, hookRequest, result)
+		reply := *result
+		propagateResultMeta(ctx, &reply.Meta)
+		return createResponse(id, reply)
 	case mcp.MethodResourcesTemplatesList:
 		var request mcp.ListResourceTemplatesRequest
 		var result *mcp.ListResourceTemplatesResult
+		hookRequest := &request
 		if s.capabilities.resources == nil {
+			err = capabilityError(id, baseMessage.Method, "resources")
+		} else if unmarshalErr := s.jsonCodec.Unmarshal(message, &request); unmarshalErr != nil {
 			err = &requestError{
-				id:   baseMessage.ID,
-				code: mcp.METHOD_NOT_FOUND,
-				err:  fmt.Errorf("resources %w", ErrUnsupported),
-			}
-		} else if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
-			err = &requestError{
-				id:   baseMessage.ID,
+				id:   id,
 				code: mcp.INVALID_REQUEST,
 				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
 			}
 		} else {
 			request.Header = headers
-			s.hooks.beforeListResourceTemplates(ctx, baseMessage.ID, &request)
-			result, err = s.handleListResourceTemplates(ctx, baseMessage.ID, request)
+			hookRequest = redactHookRequest(ctx, s, &request)
+			s.hooks.beforeListResourceTemplates(ctx, id, hookRequest)
+			result, err = s.handleListResourceTemplates(ctx, id, request)
+			if timeoutErr := requestTimeoutError(ctx, id); timeoutErr != nil {
+				err = timeoutErr
+			} else if limitErr := s.durationLimitError(ctx, id); limitErr != nil {
+				err = limitErr
+			}
 		}
 		if err != nil {
-			s.hooks.onError(ctx, baseMessage.ID, baseMessage.Method, &request, err)
+			s.reportRecoveredPanic(ctx, id, baseMessage.Method, hookRequest, err)
+			s.hooks.onError(ctx, id, baseMessage.Method, hookRequest, err)
 			return err.ToJSONRPCError()
 		}
-		s.hooks.afterListResourceTemplates(ctx, baseMessage.ID, &request, result)
-		return createResponse(baseMessage.ID, *result)
+		s.hooks.afterListResourceTemplates(ctx, id, hookRequest, result)
+		reply := *result
+		propagateResultMeta(ctx, &reply.Meta)
+		return createResponse(id, reply)
 	case mcp.MethodResourcesRead:
 		var request mcp.ReadResourceRequest
 		var result *mcp.ReadResourceResult
+		hookRequest := &request
 		if s.capabilities.resources == nil {
+			err = capabilityError(id, baseMessage.Method, "resources")
+		} else if unmarshalErr := s.jsonCodec.Unmarshal(message, &request); unmarshalErr != nil {
 			err = &requestError{
-				id:   baseMessage.ID,
-				code: mcp.METHOD_NOT_FOUND,
-				err:  fmt.Errorf("resources %w", ErrUnsupported),
+				id:   id,
+				code: mcp.INVALID_REQUEST,
+				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
+			}
+		} else {
+			request.Header = headers
+			hookRequest = redactHookRequest(ctx, s, &request)
+			s.hooks.beforeReadResource(ctx, id, hookRequest)
+			result, err = s.handleReadResource(ctx, id, request)
+			if timeoutErr := requestTimeoutError(ctx, id); timeoutErr != nil {
+				err = timeoutErr
+			} else if limitErr := s.durationLimitError(ctx, id); limitErr != nil {
+				err = limitErr
 			}
-		} else if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
+		}
+		if err != nil {
+			s.reportRecoveredPanic(ctx, id, baseMessage.Method, hookRequest, err)
+			s.hooks.onError(ctx, id, baseMessage.Method, hookRequest, err)
+			return err.ToJSONRPCError()
+		}
+		s.hooks.afterReadResource(ctx, id, hookRequest, result)
+		reply := *result
+		propagateResultMeta(ctx, &reply.Meta)
+		return createResponse(id, reply)
+	case mcp.MethodResourcesSubscribe:
+		var request mcp.SubscribeRequest
+		var result *mcp.EmptyResult
+		hookRequest := &request
+		if s.capabilities.resources == nil {
+			err = capabilityError(id, baseMessage.Method, "resources")
+		} else if unmarshalErr := s.jsonCodec.Unmarshal(message, &request); unmarshalErr != nil {
 			err = &requestError{
-				id:   baseMessage.ID,
+				id:   id,
 				code: mcp.INVALID_REQUEST,
 				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
 			}
 		} else {
 			request.Header = headers
-			s.hooks.beforeReadResource(ctx, baseMessage.ID, &request)
-			result, err = s.handleReadResource(ctx, baseMessage.ID, request)
+			hookRequest = redactHookRequest(ctx, s, &request)
+			s.hooks.beforeSubscribe(ctx, id, hookRequest)
+			result, err = s.handleSubscribe(ctx, id, request)
+			if timeoutErr := requestTimeoutError(ctx, id); timeoutErr != nil {
+				err = timeoutErr
+			} else if limitErr := s.durationLimitError(ctx, id); limitErr != nil {
+				err = limitErr
+			}
+		}
+		if err != nil {
+			s.reportRecoveredPanic(ctx, id, baseMessage.Method, hookRequest, err)
+			s.hooks.onError(ctx, id, baseMessage.Method, hookRequest, err)
+			return err.ToJSONRPCError()
+		}
+		s.hooks.afterSubscribe(ctx, id, hookRequest, result)
+		reply := *result
+		propagateResultMeta(ctx, &reply.Meta)
+		return createResponse(id, reply)
+	case mcp.MethodResourcesUnsubscribe:
+		var request mcp.UnsubscribeRequest
+		var result *mcp.EmptyResult
+		hookRequest := &request
+		if s.capabilities.resources == nil {
+			err = capabilityError(id, baseMessage.Method, "resources")
+		} else if unmarshalErr := s.jsonCodec.Unmarshal(message, &request); unmarshalErr != nil {
+			err = &requestError{
+				id:   id,
+				code: mcp.INVALID_REQUEST,
+				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
+			}
+		} else {
+			request.Header = headers
+			hookRequest = redactHookRequest(ctx, s, &request)
+			s.hooks.beforeUnsubscribe(ctx, id, hookRequest)
+			result, err = s.handleUnsubscribe(ctx, id, request)
+			if timeoutErr := requestTimeoutError(ctx, id); timeoutErr != nil {
+				err = timeoutErr
+			} else if limitErr := s.durationLimitError(ctx, id); limitErr != nil {
+				err = limitErr
+			}
 		}
 		if err != nil {
-			s.hooks.onError(ctx, baseMessage.ID, baseMessage.Method, &request, err)
+			s.reportRecoveredPanic(ctx, id, baseMessage.Method, hookRequest, err)
+			s.hooks.onError(ctx, id, baseMessage.Method, hookRequest, err)
 			return err.ToJSONRPCError()
 		}
-		s.hooks.afterReadResource(ctx, baseMessage.ID, &request, result)
-		return createResponse(baseMessage.ID, *result)
+		s.hooks.afterUnsubscribe(ctx, id, hookRequest, result)
+		reply := *result
+		propagateResultMeta(ctx, &reply.Meta)
+		return createResponse(id, reply)
 	case mcp.MethodPromptsList:
 		var request mcp.ListPromptsRequest
 		var result *mcp.ListPromptsResult
+		hookRequest := &request
 		if s.capabilities.prompts == nil {
+			err = capabilityError(id, baseMessage.Method, "prompts")
+		} else if unmarshalErr := s.jsonCodec.Unmarshal(message, &request); unmarshalErr != nil {
 			err = &requestError{
-				id:   baseMessage.ID,
-				code: mcp.METHOD_NOT_FOUND,
-				err:  fmt.Errorf("prompts %w", ErrUnsupported),
-			}
-		} else if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
-			err = &requestError{
-				id:   baseMessage.ID,
+				id:   id,
 				code: mcp.INVALID_REQUEST,
 				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
 			}
 		} else {
 			request.Header = headers
-			s.hooks.beforeListPrompts(ctx, baseMessage.ID, &request)
-			result, err = s.handleListPrompts(ctx, baseMessage.ID, request)
+			hookRequest = redactHookRequest(ctx, s, &request)
+			s.hooks.beforeListPrompts(ctx, id, hookRequest)
+			result, err = s.handleListPrompts(ctx, id, request)
+			if timeoutErr := requestTimeoutError(ctx, id); timeoutErr != nil {
+				err = timeoutErr
+			} else if limitErr := s.durationLimitError(ctx, id); limitErr != nil {
+				err = limitErr
+			}
 		}
 		if err != nil {
-			s.hooks.onError(ctx, baseMessage.ID, baseMessage.Method, &request, err)
+			s.reportRecoveredPanic(ctx, id, baseMessage.Method, hookRequest, err)
+			s.hooks.onError(ctx, id, baseMessage.Method, hookRequest, err)
 			return err.ToJSONRPCError()
 		}
-		s.hooks.afterListPrompts(ctx, baseMessage.ID, &request, result)
-		return createResponse(baseMessage.ID, *result)
+		s.hooks.afterListPrompts(ctx, id, hookRequest, result)
+		reply := *result
+		propagateResultMeta(ctx, &reply.Meta)
+		return createResponse(id, reply)
 	case mcp.MethodPromptsGet:
 		var request mcp.GetPromptRequest
 		var result *mcp.GetPromptResult
+		hookRequest := &request
 		if s.capabilities.prompts == nil {
+			err = capabilityError(id, baseMessage.Method, "prompts")
+		} else if unmarshalErr := s.jsonCodec.Unmarshal(message, &request); unmarshalErr != nil {
 			err = &requestError{
-				id:   baseMessage.ID,
-				code: mcp.METHOD_NOT_FOUND,
-				err:  fmt.Errorf("prompts %w", ErrUnsupported),
-			}
-		} else if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
-			err = &requestError{
-				id:   baseMessage.ID,
+				id:   id,
 				code: mcp.INVALID_REQUEST,
 				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
 			}
 		} else {
 			request.Header = headers
-			s.hooks.beforeGetPrompt(ctx, baseMessage.ID, &request)
-			result, err = s.handleGetPrompt(ctx, baseMessage.ID, request)
+			hookRequest = redactHookRequest(ctx, s, &request)
+			s.hooks.beforeGetPrompt(ctx, id, hookRequest)
+			result, err = s.handleGetPrompt(ctx, id, request)
+			if timeoutErr := requestTimeoutError(ctx, id); timeoutErr != nil {
+				err = timeoutErr
+			} else if limitErr := s.durationLimitError(ctx, id); limitErr != nil {
+				err = limitErr
+			}
 		}
 		if err != nil {
-			s.hooks.onError(ctx, baseMessage.ID, baseMessage.Method, &request, err)
+			s.reportRecoveredPanic(ctx, id, baseMessage.Method, hookRequest, err)
+			s.hooks.onError(ctx, id, baseMessage.Method, hookRequest, err)
 			return err.ToJSONRPCError()
 		}
-		s.hooks.afterGetPrompt(ctx, baseMessage.ID, &request, result)
-		return createResponse(baseMessage.ID, *result)
+		s.hooks.afterGetPrompt(ctx, id, hookRequest, result)
+		reply := *result
+		propagateResultMeta(ctx, &reply.Meta)
+		return createResponse(id, reply)
 	case mcp.MethodToolsList:
 		var request mcp.ListToolsRequest
 		var result *mcp.ListToolsResult
+		hookRequest := &request
 		if s.capabilities.tools == nil {
+			err = capabilityError(id, baseMessage.Method, "tools")
+		} else if unmarshalErr := s.jsonCodec.Unmarshal(message, &request); unmarshalErr != nil {
 			err = &requestError{
-				id:   baseMessage.ID,
-				code: mcp.METHOD_NOT_FOUND,
-				err:  fmt.Errorf("tools %w", ErrUnsupported),
-			}
-		} else if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
-			err = &requestError{
-				id:   baseMessage.ID,
+				id:   id,
 				code: mcp.INVALID_REQUEST,
 				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
 			}
 		} else {
 			request.Header = headers
-			s.hooks.beforeListTools(ctx, baseMessage.ID, &request)
-			result, err = s.handleListTools(ctx, baseMessage.ID, request)
+			hookRequest = redactHookRequest(ctx, s, &request)
+			s.hooks.beforeListTools(ctx, id, hookRequest)
+			result, err = s.handleListTools(ctx, id, request)
+			if timeoutErr := requestTimeoutError(ctx, id); timeoutErr != nil {
+				err = timeoutErr
+			} else if limitErr := s.durationLimitError(ctx, id); limitErr != nil {
+				err = limitErr
+			}
 		}
 		if err != nil {
-			s.hooks.onError(ctx, baseMessage.ID, baseMessage.Method, &request, err)
+			s.reportRecoveredPanic(ctx, id, baseMessage.Method, hookRequest, err)
+			s.hooks.onError(ctx, id, baseMessage.Method, hookRequest, err)
 			return err.ToJSONRPCError()
 		}
-		s.hooks.afterListTools(ctx, baseMessage.ID, &request, result)
-		return createResponse(baseMessage.ID, *result)
+		s.hooks.afterListTools(ctx, id, hookRequest, result)
+		reply := *result
+		propagateResultMeta(ctx, &reply.Meta)
+		return createResponse(id, reply)
 	case mcp.MethodToolsCall:
 		var request mcp.CallToolRequest
 		var result *mcp.CallToolResult
+		hookRequest := &request
 		if s.capabilities.tools == nil {
+			err = capabilityError(id, baseMessage.Method, "tools")
+		} else if unmarshalErr := s.jsonCodec.Unmarshal(message, &request); unmarshalErr != nil {
 			err = &requestError{
-				id:   baseMessage.ID,
-				code: mcp.METHOD_NOT_FOUND,
-				err:  fmt.Errorf("tools %w", ErrUnsupported),
+				id:   id,
+				code: mcp.INVALID_REQUEST,
+				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
+			}
+		} else {
+			request.Header = headers
+			hookRequest = redactHookRequest(ctx, s, &request)
+			s.hooks.beforeCallTool(ctx, id, hookRequest)
+			result, err = s.handleToolCall(ctx, id, request)
+			if timeoutErr := requestTimeoutError(ctx, id); timeoutErr != nil {
+				err = timeoutErr
+			} else if limitErr := s.durationLimitError(ctx, id); limitErr != nil {
+				err = limitErr
 			}
-		} else if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
+		}
+		if err != nil {
+			s.reportRecoveredPanic(ctx, id, baseMessage.Method, hookRequest, err)
+			s.hooks.onError(ctx, id, baseMessage.Method, hookRequest, err)
+			return err.ToJSONRPCError()
+		}
+		s.hooks.afterCallTool(ctx, id, hookRequest, result)
+		reply := *result
+		propagateResultMeta(ctx, &reply.Meta)
+		return createResponse(id, reply)
+	case mcp.MethodToolsValidate:
+		var request mcp.ValidateToolRequest
+		var result *mcp.ValidateToolResult
+		hookRequest := &request
+		if s.capabilities.tools == nil {
+			err = capabilityError(id, baseMessage.Method, "tools")
+		} else if unmarshalErr := s.jsonCodec.Unmarshal(message, &request); unmarshalErr != nil {
 			err = &requestError{
-				id:   baseMessage.ID,
+				id:   id,
 				code: mcp.INVALID_REQUEST,
 				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
 			}
 		} else {
 			request.Header = headers
-			s.hooks.beforeCallTool(ctx, baseMessage.ID, &request)
-			result, err = s.handleToolCall(ctx, baseMessage.ID, request)
+			hookRequest = redactHookRequest(ctx, s, &request)
+			s.hooks.beforeValidateTool(ctx, id, hookRequest)
+			result, err = s.handleValidateTool(ctx, id, request)
+			if timeoutErr := requestTimeoutError(ctx, id); timeoutErr != nil {
+				err = timeoutErr
+			} else if limitErr := s.durationLimitError(ctx, id); limitErr != nil {
+				err = limitErr
+			}
 		}
 		if err != nil {
-			s.hooks.onError(ctx, baseMessage.ID, baseMessage.Method, &request, err)
+			s.reportRecoveredPanic(ctx, id, baseMessage.Method, hookRequest, err)
+			s.hooks.onError(ctx, id, baseMessage.Method, hookRequest, err)
 			return err.ToJSONRPCError()
 		}
-		s.hooks.afterCallTool(ctx, baseMessage.ID, &request, result)
-		return createResponse(baseMessage.ID, *result)
+		s.hooks.afterValidateTool(ctx, id, hookRequest, result)
+		reply := *result
+		propagateResultMeta(ctx, &reply.Meta)
+		return createResponse(id, reply)
+	case mcp.MethodCompletionComplete:
+		var request mcp.CompleteRequest
+		var result *mcp.CompleteResult
+		hookRequest := &request
+		if s.capabilities.completions == nil {
+			err = capabilityError(id, baseMessage.Method, "completions")
+		} else if unmarshalErr := s.jsonCodec.Unmarshal(message, &request); unmarshalErr != nil {
+			err = &requestError{
+				id:   id,
+				code: mcp.INVALID_REQUEST,
+				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
+			}
+		} else {
+			request.Header = headers
+			hookRequest = redactHookRequest(ctx, s, &request)
+			s.hooks.beforeComplete(ctx, id, hookRequest)
+			result, err = s.handleComplete(ctx, id, request)
+			if timeoutErr := requestTimeoutError(ctx, id); timeoutErr != nil {
+				err = timeoutErr
+			} else if limitErr := s.durationLimitError(ctx, id); limitErr != nil {
+				err = limitErr
+			}
+		}
+		if err != nil {
+			s.reportRecoveredPanic(ctx, id, baseMessage.Method, hookRequest, err)
+			s.hooks.onError(ctx, id, baseMessage.Method, hookRequest, err)
+			return err.ToJSONRPCError()
+		}
+		s.hooks.afterComplete(ctx, id, hookRequest, result)
+		reply := *result
+		propagateResultMeta(ctx, &reply.Meta)
+		return createResponse(id, reply)
 	default:
-		return createErrorResponse(
-			baseMessage.ID,
-			mcp.METHOD_NOT_FOUND,
-			fmt.Sprintf("Method %s not found", baseMessage.Method),
-		)
+		return unknownMethodResponse(id, baseMessage.Method)
 	}
 }
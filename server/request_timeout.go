@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// WithRequestTimeout cancels the context of every request handler after
+// timeout. A request whose handler has not returned successfully by then
+// is answered with a REQUEST_INTERRUPTED error matching ErrRequestTimeout.
+// WithMethodTimeout and WithToolTimeout override the timeout, and a zero
+// timeout disables it.
+func WithRequestTimeout(timeout time.Duration) ServerOption {
+	return func(s *MCPServer) {
+		s.requestTimeoutConfig().fallback = timeout
+	}
+}
+
+// WithMethodTimeout overrides the request timeout for the requests of
+// method, for example to give resources/read more time than other requests.
+// A zero timeout disables the timeout for the method.
+func WithMethodTimeout(method mcp.MCPMethod, timeout time.Duration) ServerOption {
+	return func(s *MCPServer) {
+		s.requestTimeoutConfig().methods[method] = timeout
+	}
+}
+
+// WithToolTimeout overrides the request timeout for calls of the named tool,
+// taking precedence over a WithMethodTimeout for tools/call. A zero timeout
+// disables the timeout for the tool.
+func WithToolTimeout(name string, timeout time.Duration) ServerOption {
+	return func(s *MCPServer) {
+		s.requestTimeoutConfig().tools[name] = timeout
+	}
+}
+
+// requestTimeouts holds the timeouts set by WithRequestTimeout,
+// WithMethodTimeout and WithToolTimeout.
+type requestTimeouts struct {
+	fallback time.Duration
+	methods  map[mcp.MCPMethod]time.Duration
+	tools    map[string]time.Duration
+}
+
+func (s *MCPServer) requestTimeoutConfig() *requestTimeouts {
+	if s.requestTimeouts == nil {
+		s.requestTimeouts = &requestTimeouts{
+			methods: make(map[mcp.MCPMethod]time.Duration),
+			tools:   make(map[string]time.Duration),
+		}
+	}
+	return s.requestTimeouts
+}
+
+// timeoutFor returns the timeout of a request, zero meaning none.
+func (t *requestTimeouts) timeoutFor(method mcp.MCPMethod, message json.RawMessage) time.Duration {
+	if method == mcp.MethodToolsCall && len(t.tools) > 0 {
+		var call struct {
+			Params struct {
+				Name string `json:"name"`
+			} `json:"params"`
+		}
+		if json.Unmarshal(message, &call) == nil {
+			if timeout, ok := t.tools[call.Params.Name]; ok {
+				return timeout
+			}
+		}
+	}
+	if timeout, ok := t.methods[method]; ok {
+		return timeout
+	}
+	return t.fallback
+}
+
+// withRequestTimeout returns the context of a request handler, cancelled
+// with an ErrRequestTimeout cause once the timeout of the request elapses.
+func (s *MCPServer) withRequestTimeout(
+	ctx context.Context,
+	method mcp.MCPMethod,
+	message json.RawMessage,
+) (context.Context, context.CancelFunc) {
+	if s.requestTimeouts == nil {
+		return ctx, func() {}
+	}
+	timeout := s.requestTimeouts.timeoutFor(method, message)
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeoutCause(ctx, timeout, fmt.Errorf("%w after %s", ErrRequestTimeout, timeout))
+}
+
+// requestTimeoutError returns the error answering a request whose timeout
+// elapsed before its handler returned, or nil if it did not.
+func requestTimeoutError(ctx context.Context, id any) *requestError {
+	cause := context.Cause(ctx)
+	if !errors.Is(cause, ErrRequestTimeout) {
+		return nil
+	}
+	return &requestError{
+		id:   id,
+		code: mcp.REQUEST_INTERRUPTED,
+		err:  cause,
+	}
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestMCPServer_WithRequestTimeout(t *testing.T) {
+	// waitTool blocks until its context is done or, for tools ignoring
+	// their context, until the sleep elapses.
+	waitTool := func(ignoreContext bool) ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if ignoreContext {
+				time.Sleep(50 * time.Millisecond)
+				return mcp.NewToolResultText("done"), nil
+			}
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Second):
+				return mcp.NewToolResultText("done"), nil
+			}
+		}
+	}
+
+	tests := []struct {
+		name        string
+		options     []ServerOption
+		tool        string
+		expectedErr bool
+	}{
+		{
+			name:        "default timeout",
+			options:     []ServerOption{WithRequestTimeout(20 * time.Millisecond)},
+			tool:        "wait",
+			expectedErr: true,
+		},
+		{
+			name:        "handler ignoring its context",
+			options:     []ServerOption{WithRequestTimeout(20 * time.Millisecond)},
+			tool:        "sleep",
+			expectedErr: true,
+		},
+		{
+			name:    "method override",
+			options: []ServerOption{WithRequestTimeout(20 * time.Millisecond), WithMethodTimeout(mcp.MethodToolsCall, 0)},
+			tool:    "sleep",
+		},
+		{
+			name: "tool override takes precedence",
+			options: []ServerOption{
+				WithMethodTimeout(mcp.MethodToolsCall, 20*time.Millisecond),
+				WithToolTimeout("sleep", time.Second),
+			},
+			tool: "sleep",
+		},
+		{
+			name: "no timeout",
+			tool: "sleep",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMCPServer("test-server", "1.0.0", append([]ServerOption{WithToolCapabilities(false)}, tt.options...)...)
+			server.AddTool(mcp.NewTool("wait"), waitTool(false))
+			server.AddTool(mcp.NewTool("sleep"), waitTool(true))
+
+			response := server.HandleMessage(context.Background(), json.RawMessage(fmt.Sprintf(
+				`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":%q}}`, tt.tool)))
+			if !tt.expectedErr {
+				_, ok := response.(mcp.JSONRPCResponse)
+				assert.True(t, ok, "unexpected response %#v", response)
+				return
+			}
+			errResponse, ok := response.(mcp.JSONRPCError)
+			require.True(t, ok, "unexpected response %#v", response)
+			assert.Equal(t, mcp.REQUEST_INTERRUPTED, errResponse.Error.Code)
+			assert.Contains(t, errResponse.Error.Message, "request timed out after 20ms")
+		})
+	}
+}
+
+func TestMCPServer_RequestTimeoutOnlyAppliesToItsMethod(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithMethodTimeout(mcp.MethodToolsCall, time.Nanosecond))
+	response := server.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+	_, ok := response.(mcp.JSONRPCResponse)
+	assert.True(t, ok, "unexpected response %#v", response)
+}
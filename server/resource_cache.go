@@ -0,0 +1,155 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// WithResourceCaching adds validators to the results of resource reads and
+// answers conditional reads of unchanged contents with an empty result
+// marked as not modified, see mcp.SetConditionalRead.
+//
+// The validators of a read are those handlers set with
+// SetResourceValidators or, by default, an entity tag hashed from the
+// contents. Validators set by the handlers of resources and templates
+// registered on the server are stored per URI, so that conditional reads
+// matching them are answered without calling the handler again. They are
+// dropped when NotifyResourceUpdated is called for the URI or the resources
+// of the server change, so handlers setting validators must be paired with
+// resource update notifications. Middlewares run for every read.
+func WithResourceCaching() ServerOption {
+	return func(s *MCPServer) {
+		s.resourceCache = &resourceCache{entries: make(map[string]mcp.ResourceValidators)}
+	}
+}
+
+// SetResourceValidators sets the validators of the contents returned by the
+// resource handler called with ctx. It has no effect unless the server uses
+// WithResourceCaching.
+func SetResourceValidators(ctx context.Context, validators mcp.ResourceValidators) {
+	if state, ok := ctx.Value(resourceReadKey{}).(*resourceRead); ok {
+		state.validators = validators
+		state.handlerValidators = true
+	}
+}
+
+// resourceCache holds the validators set by handlers by resource URI.
+type resourceCache struct {
+	mu      sync.RWMutex
+	entries map[string]mcp.ResourceValidators
+}
+
+// resourceReadKey is the context key of the resourceRead of a read.
+type resourceReadKey struct{}
+
+// resourceRead is the state of a read with WithResourceCaching.
+type resourceRead struct {
+	validators        mcp.ResourceValidators
+	handlerValidators bool
+	notModified       bool
+}
+
+func (c *resourceCache) get(uri string) (mcp.ResourceValidators, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	validators, ok := c.entries[uri]
+	return validators, ok
+}
+
+func (c *resourceCache) set(uri string, validators mcp.ResourceValidators) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[uri] = validators
+}
+
+// invalidate drops the validators of uris, or of all URIs without any. It
+// is a no-op on a nil cache.
+func (c *resourceCache) invalidate(uris ...string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(uris) == 0 {
+		clear(c.entries)
+		return
+	}
+	for _, uri := range uris {
+		delete(c.entries, uri)
+	}
+}
+
+// cachingResourceHandler wraps the handler of a read for WithResourceCaching.
+// Handlers of the server's own resources and templates are shared by
+// every session, so the validators they set are stored; those of session
+// and tenant resources are not.
+func (s *MCPServer) cachingResourceHandler(handler ResourceHandlerFunc, shared bool) ResourceHandlerFunc {
+	if s.resourceCache == nil {
+		return handler
+	}
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		state, ok := ctx.Value(resourceReadKey{}).(*resourceRead)
+		if !ok {
+			return handler(ctx, request)
+		}
+		uri := request.Params.URI
+		conditional := mcp.ConditionalReadValidators(request)
+
+		if shared && !conditional.IsZero() {
+			if stored, ok := s.resourceCache.get(uri); ok && stored.Matches(conditional) {
+				state.validators = stored
+				state.notModified = true
+				return []mcp.ResourceContents{}, nil
+			}
+		}
+
+		contents, err := handler(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		if state.handlerValidators {
+			if shared {
+				s.resourceCache.set(uri, state.validators)
+			}
+		} else {
+			state.validators = mcp.ResourceValidators{ETag: contentsETag(contents)}
+		}
+		if !conditional.IsZero() && state.validators.Matches(conditional) {
+			state.notModified = true
+			return []mcp.ResourceContents{}, nil
+		}
+		return contents, nil
+	}
+}
+
+// withResourceRead returns the context of a read and a function building
+// its result from the contents returned by the handlers.
+func (s *MCPServer) withResourceRead(ctx context.Context) (context.Context, func(contents []mcp.ResourceContents) *mcp.ReadResourceResult) {
+	if s.resourceCache == nil {
+		return ctx, func(contents []mcp.ResourceContents) *mcp.ReadResourceResult {
+			return &mcp.ReadResourceResult{Contents: contents}
+		}
+	}
+	state := &resourceRead{}
+	return context.WithValue(ctx, resourceReadKey{}, state), func(contents []mcp.ResourceContents) *mcp.ReadResourceResult {
+		result := &mcp.ReadResourceResult{Contents: contents}
+		mcp.SetResourceValidators(result, state.validators)
+		if state.notModified {
+			result.Contents = []mcp.ResourceContents{}
+			result.Meta.AdditionalFields[mcp.NotModifiedMetaKey] = true
+		}
+		return result
+	}
+}
+
+// contentsETag returns an entity tag hashed from contents.
+func contentsETag(contents []mcp.ResourceContents) string {
+	hash := sha256.New()
+	_ = json.NewEncoder(hash).Encode(contents)
+	return `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+}
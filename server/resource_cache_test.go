@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// readResource reads uri from server, conditionally on validators if they
+// are set.
+func readResource(t *testing.T, server *MCPServer, uri string, validators mcp.ResourceValidators) *mcp.ReadResourceResult {
+	t.Helper()
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = uri
+	mcp.SetConditionalRead(&request, validators)
+	params, err := json.Marshal(request.Params)
+	require.NoError(t, err)
+
+	response := server.HandleMessage(context.Background(), json.RawMessage(fmt.Sprintf(
+		`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":%s}`, params)))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "expected JSONRPCResponse, got %#v", response)
+	result, ok := resp.Result.(mcp.ReadResourceResult)
+	require.True(t, ok, "expected ReadResourceResult, got %T", resp.Result)
+	return &result
+}
+
+func TestMCPServer_WithResourceCaching(t *testing.T) {
+	var handlerCalls, middlewareCalls int
+	server := NewMCPServer("test-server", "1.0.0",
+		WithResourceCaching(),
+		WithResourceHandlerMiddleware(func(next ResourceHandlerFunc) ResourceHandlerFunc {
+			return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+				middlewareCalls++
+				return next(ctx, request)
+			}
+		}),
+	)
+	server.AddResource(mcp.NewResource("docs://manual", "Manual"), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		handlerCalls++
+		SetResourceValidators(ctx, mcp.ResourceValidators{ETag: `"v1"`})
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: "a large manual"}}, nil
+	})
+	v1 := mcp.ResourceValidators{ETag: `"v1"`}
+
+	t.Run("unconditional read", func(t *testing.T) {
+		result := readResource(t, server, "docs://manual", mcp.ResourceValidators{})
+		assert.Len(t, result.Contents, 1)
+		assert.Equal(t, v1, mcp.ResourceValidatorsFromResult(result))
+		assert.False(t, mcp.IsNotModified(result))
+		assert.Equal(t, 1, handlerCalls)
+	})
+
+	t.Run("stored validators skip the handler", func(t *testing.T) {
+		result := readResource(t, server, "docs://manual", v1)
+		assert.True(t, mcp.IsNotModified(result))
+		assert.Empty(t, result.Contents)
+		assert.Equal(t, v1, mcp.ResourceValidatorsFromResult(result))
+		assert.Equal(t, 1, handlerCalls)
+		assert.Equal(t, 2, middlewareCalls)
+	})
+
+	t.Run("stale validators", func(t *testing.T) {
+		result := readResource(t, server, "docs://manual", mcp.ResourceValidators{ETag: `"v0"`})
+		assert.False(t, mcp.IsNotModified(result))
+		assert.Len(t, result.Contents, 1)
+		assert.Equal(t, 2, handlerCalls)
+	})
+
+	t.Run("updates drop the stored validators", func(t *testing.T) {
+		server.NotifyResourceUpdated("docs://manual")
+		result := readResource(t, server, "docs://manual", v1)
+		assert.True(t, mcp.IsNotModified(result))
+		assert.Equal(t, 3, handlerCalls)
+	})
+}
+
+func TestMCPServer_WithResourceCaching_HashedETag(t *testing.T) {
+	var handlerCalls int
+	text := "alice"
+	server := NewMCPServer("test-server", "1.0.0", WithResourceCaching())
+	server.AddResourceTemplate(mcp.NewResourceTemplate("users://{id}", "User"), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		handlerCalls++
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: text}}, nil
+	})
+
+	first := readResource(t, server, "users://1", mcp.ResourceValidators{})
+	validators := mcp.ResourceValidatorsFromResult(first)
+	require.NotEmpty(t, validators.ETag)
+
+	second := readResource(t, server, "users://1", validators)
+	assert.True(t, mcp.IsNotModified(second))
+	assert.Equal(t, 2, handlerCalls, "hashed entity tags need the contents")
+
+	text = "bob"
+	third := readResource(t, server, "users://1", validators)
+	assert.False(t, mcp.IsNotModified(third))
+	assert.NotEqual(t, validators, mcp.ResourceValidatorsFromResult(third))
+}
+
+func TestMCPServer_WithoutResourceCaching(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+	server.AddResource(mcp.NewResource("docs://manual", "Manual"), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		SetResourceValidators(ctx, mcp.ResourceValidators{ETag: `"v1"`})
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: "manual"}}, nil
+	})
+
+	result := readResource(t, server, "docs://manual", mcp.ResourceValidators{ETag: `"v1"`})
+	assert.Len(t, result.Contents, 1)
+	assert.Nil(t, result.Meta)
+}
@@ -0,0 +1,200 @@
+package server
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ResourceListDiff describes how a change of the registered resources
+// affected the list returned by resources/list. Registering a resource
+// identical to the one already listed under its URI, or a session resource
+// identical to the global one it overrides, changes nothing, and no
+// notifications/resources/list_changed is sent for it.
+type ResourceListDiff struct {
+	// SessionID is the session whose list changed, or empty for a change of
+	// the global resources.
+	SessionID string
+	// Added, Removed and Changed hold the sorted URIs of the resources that
+	// appeared in, disappeared from or were modified in the list.
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+// Empty reports whether the list did not change.
+func (d ResourceListDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// String summarizes the diff for logging.
+func (d ResourceListDiff) String() string {
+	var parts []string
+	for _, part := range []struct {
+		name string
+		uris []string
+	}{{"added", d.Added}, {"removed", d.Removed}, {"changed", d.Changed}} {
+		if len(part.uris) > 0 {
+			parts = append(parts, fmt.Sprintf("%s %s", part.name, strings.Join(part.uris, ", ")))
+		}
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "unchanged")
+	}
+	scope := "global resources"
+	if d.SessionID != "" {
+		scope = "resources of session " + d.SessionID
+	}
+	return scope + ": " + strings.Join(parts, "; ")
+}
+
+// ResourceListDiffFunc is called with every change of a resource list.
+type ResourceListDiffFunc func(diff ResourceListDiff)
+
+// WithResourceListDiffHandler sets a function called whenever the global
+// resources or the resources of a session change, for example to log what
+// sessions are told about by notifications/resources/list_changed.
+func WithResourceListDiffHandler(handler ResourceListDiffFunc) ServerOption {
+	return func(s *MCPServer) {
+		s.resourceListDiffHandler = handler
+	}
+}
+
+// LastResourceListDiff returns the last change of the resources visible to
+// a session, or of the global resources if sessionID is empty. It reports
+// false if the list never changed.
+func (s *MCPServer) LastResourceListDiff(sessionID string) (ResourceListDiff, bool) {
+	s.resourceListDiffsMu.Lock()
+	defer s.resourceListDiffsMu.Unlock()
+	diff, ok := s.resourceListDiffs[sessionID]
+	return diff, ok
+}
+
+func (s *MCPServer) forgetResourceListDiff(sessionID string) {
+	s.resourceListDiffsMu.Lock()
+	delete(s.resourceListDiffs, sessionID)
+	s.resourceListDiffsMu.Unlock()
+}
+
+// resourceListDiffer accumulates a ResourceListDiff from the resources
+// listed under each URI before and after a change.
+type resourceListDiffer struct {
+	sessionID string
+	uris      []string
+	before    map[string]listedResource
+	after     map[string]listedResource
+}
+
+type listedResource struct {
+	resource mcp.Resource
+	listed   bool
+}
+
+func newResourceListDiffer(sessionID string) *resourceListDiffer {
+	return &resourceListDiffer{
+		sessionID: sessionID,
+		before:    make(map[string]listedResource),
+		after:     make(map[string]listedResource),
+	}
+}
+
+// compare records the resource listed under uri before and after a step of
+// the change. Steps of the same URI are combined, comparing the state before
+// the first with the state after the last.
+func (d *resourceListDiffer) compare(uri string, before mcp.Resource, listedBefore bool, after mcp.Resource, listedAfter bool) {
+	if _, ok := d.before[uri]; !ok {
+		d.uris = append(d.uris, uri)
+		d.before[uri] = listedResource{resource: before, listed: listedBefore}
+	}
+	d.after[uri] = listedResource{resource: after, listed: listedAfter}
+}
+
+func (d *resourceListDiffer) result() ResourceListDiff {
+	diff := ResourceListDiff{SessionID: d.sessionID}
+	for _, uri := range d.uris {
+		before, after := d.before[uri], d.after[uri]
+		switch {
+		case !before.listed && after.listed:
+			diff.Added = append(diff.Added, uri)
+		case before.listed && !after.listed:
+			diff.Removed = append(diff.Removed, uri)
+		case before.listed && after.listed && !reflect.DeepEqual(before.resource, after.resource):
+			diff.Changed = append(diff.Changed, uri)
+		}
+	}
+	slices.Sort(diff.Added)
+	slices.Sort(diff.Removed)
+	slices.Sort(diff.Changed)
+	return diff
+}
+
+// recordResourceListDiff stores a non-empty diff and passes it to the diff
+// handler.
+func (s *MCPServer) recordResourceListDiff(diff ResourceListDiff) {
+	if diff.Empty() {
+		return
+	}
+	s.resourceListDiffsMu.Lock()
+	s.resourceListDiffs[diff.SessionID] = diff
+	s.resourceListDiffsMu.Unlock()
+	if s.resourceListDiffHandler != nil {
+		s.resourceListDiffHandler(diff)
+	}
+}
+
+// notifyGlobalResourceListChanged records a change of the global resources
+// and sends notifications/resources/list_changed to the sessions that see
+// it. Sessions overriding every changed URI with resources of their own see
+// no change and are left out.
+func (s *MCPServer) notifyGlobalResourceListChanged(diff ResourceListDiff) {
+	s.recordResourceListDiff(diff)
+	if diff.Empty() || s.capabilities.resources == nil || !s.capabilities.resources.listChanged {
+		return
+	}
+
+	var notified, shadowed []string
+	s.sessions.Range(func(key, value any) bool {
+		session, ok := value.(ClientSession)
+		if !ok || !session.Initialized() {
+			return true
+		}
+		if withResources, ok := session.(SessionWithResources); ok && diff.shadowedBy(withResources.GetSessionResources()) {
+			shadowed = append(shadowed, key.(string))
+		} else {
+			notified = append(notified, key.(string))
+		}
+		return true
+	})
+	if len(shadowed) == 0 {
+		s.notifyListChanged(mcp.MethodNotificationResourcesListChanged)
+		return
+	}
+	for _, sessionID := range notified {
+		_ = s.notifySessionListChanged(sessionID, mcp.MethodNotificationResourcesListChanged)
+	}
+}
+
+// shadowedBy reports whether every URI of a global diff is overridden by
+// the given session resources.
+func (d ResourceListDiff) shadowedBy(sessionResources map[string]ServerResource) bool {
+	if len(sessionResources) == 0 {
+		return false
+	}
+	for _, uris := range [][]string{d.Added, d.Removed, d.Changed} {
+		for _, uri := range uris {
+			if _, ok := sessionResources[uri]; !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// globalResource returns the global resource registered under uri.
+func (s *MCPServer) globalResource(uri string) (mcp.Resource, bool) {
+	entry, ok := s.resources.get(uri)
+	return entry.resource, ok
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func resourceDiffHandler(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return nil, nil
+}
+
+func TestMCPServer_ResourceListDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		change   func(server *MCPServer)
+		expected ResourceListDiff
+		notified bool
+	}{
+		{
+			name: "identical resource",
+			change: func(server *MCPServer) {
+				server.AddResource(mcp.NewResource("test://a", "A"), resourceDiffHandler)
+			},
+		},
+		{
+			name: "added and changed resources",
+			change: func(server *MCPServer) {
+				server.AddResources(
+					ServerResource{Resource: mcp.NewResource("test://c", "C"), Handler: resourceDiffHandler},
+					ServerResource{Resource: mcp.NewResource("test://a", "A", mcp.WithMIMEType("text/plain")), Handler: resourceDiffHandler},
+				)
+			},
+			expected: ResourceListDiff{Added: []string{"test://c"}, Changed: []string{"test://a"}},
+			notified: true,
+		},
+		{
+			name: "deleted resources",
+			change: func(server *MCPServer) {
+				server.DeleteResources("test://b", "test://missing")
+			},
+			expected: ResourceListDiff{Removed: []string{"test://b"}},
+			notified: true,
+		},
+		{
+			name: "replaced resources",
+			change: func(server *MCPServer) {
+				server.SetResources(
+					ServerResource{Resource: mcp.NewResource("test://a", "A"), Handler: resourceDiffHandler},
+					ServerResource{Resource: mcp.NewResource("test://d", "D"), Handler: resourceDiffHandler},
+				)
+			},
+			expected: ResourceListDiff{Added: []string{"test://d"}, Removed: []string{"test://b"}},
+			notified: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diffs []ResourceListDiff
+			server := NewMCPServer("test-server", "1.0.0",
+				WithResourceCapabilities(false, true),
+				WithResourceListDiffHandler(func(diff ResourceListDiff) { diffs = append(diffs, diff) }))
+			server.AddResource(mcp.NewResource("test://a", "A"), resourceDiffHandler)
+			server.AddResource(mcp.NewResource("test://b", "B"), resourceDiffHandler)
+			diffs = nil
+
+			notifications := make(chan mcp.JSONRPCNotification, 10)
+			require.NoError(t, server.RegisterSession(context.Background(), &sessionTestClient{
+				sessionID:           "session-1",
+				notificationChannel: notifications,
+				initialized:         true,
+			}))
+
+			tt.change(server)
+			if !tt.notified {
+				assert.Empty(t, notifications)
+				assert.Empty(t, diffs)
+				return
+			}
+			assert.Len(t, notifications, 1)
+			require.Len(t, diffs, 1)
+			assert.Equal(t, tt.expected, diffs[0])
+			last, ok := server.LastResourceListDiff("")
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, last)
+		})
+	}
+}
+
+func TestMCPServer_SessionResourceListDiff(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithResourceCapabilities(false, true))
+	server.AddResource(mcp.NewResource("test://shared", "Shared"), resourceDiffHandler)
+
+	notifications := make(chan mcp.JSONRPCNotification, 10)
+	session := &sessionTestClientWithResources{
+		sessionID:           "session-1",
+		notificationChannel: notifications,
+		initialized:         true,
+		sessionResources:    make(map[string]ServerResource),
+	}
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+	other := make(chan mcp.JSONRPCNotification, 10)
+	require.NoError(t, server.RegisterSession(context.Background(), &sessionTestClient{
+		sessionID:           "session-2",
+		notificationChannel: other,
+		initialized:         true,
+	}))
+
+	// Overriding a global resource with an identical one changes nothing.
+	require.NoError(t, server.AddSessionResource("session-1", mcp.NewResource("test://shared", "Shared"), resourceDiffHandler))
+	assert.Empty(t, notifications)
+	_, ok := server.LastResourceListDiff("session-1")
+	assert.False(t, ok)
+
+	require.NoError(t, server.AddSessionResource("session-1", mcp.NewResource("test://shared", "Mine"), resourceDiffHandler))
+	require.Len(t, notifications, 1)
+	<-notifications
+	diff, ok := server.LastResourceListDiff("session-1")
+	require.True(t, ok)
+	assert.Equal(t, ResourceListDiff{SessionID: "session-1", Changed: []string{"test://shared"}}, diff)
+	assert.Equal(t, "resources of session session-1: changed test://shared", diff.String())
+
+	// A global change shadowed by the session's own resource only reaches
+	// the other session.
+	server.AddResource(mcp.NewResource("test://shared", "Shared v2"), resourceDiffHandler)
+	assert.Empty(t, notifications)
+	assert.Len(t, other, 1)
+	<-other
+
+	// Deleting the override reveals the global resource again.
+	require.NoError(t, server.DeleteSessionResources("session-1", "test://shared"))
+	require.Len(t, notifications, 1)
+	diff, _ = server.LastResourceListDiff("session-1")
+	assert.Equal(t, []string{"test://shared"}, diff.Changed)
+
+	server.UnregisterSession(context.Background(), "session-1")
+	_, ok = server.LastResourceListDiff("session-1")
+	assert.False(t, ok)
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// WithResourceLinkValidation checks the resource links tool handlers return.
+// A call whose result links to a URI that no registered resource or resource
+// template serves fails with an internal error rather than handing the
+// client a link it cannot read.
+func WithResourceLinkValidation() ServerOption {
+	return func(s *MCPServer) {
+		s.validateResourceLinks = true
+	}
+}
+
+// ResourceLink returns a link to the resource at uri as seen by the session
+// of ctx, for tool handlers to reference a resource instead of embedding
+// its contents. The name, description, MIME type and annotations of the
+// link are those of the registered resource or, failing that, of the most
+// specific resource template matching uri, or of the resource a resolver
+// materializes for it. Tenant and session resources and templates take
+// precedence over the server's. An error matching
+// ErrResourceNotFound is returned if none serves uri.
+func (s *MCPServer) ResourceLink(ctx context.Context, uri string) (mcp.ResourceLink, error) {
+	link, ok := s.resolveResourceLink(ctx, uri)
+	if !ok {
+		return mcp.ResourceLink{}, fmt.Errorf("resource URI '%s': %w", uri, ErrResourceNotFound)
+	}
+	return link, nil
+}
+
+// resolveResourceLink looks up uri the way handleReadResource does and
+// describes the resource or template serving it as a link.
+func (s *MCPServer) resolveResourceLink(ctx context.Context, uri string) (mcp.ResourceLink, bool) {
+	if resource, ok := s.overlayResources(ctx)[uri]; ok {
+		return mcp.NewResourceLinkFromResource(resource.Resource), true
+	}
+	sessionTemplates := s.overlayResourceTemplates(ctx)
+
+	if entry, ok := s.resources.get(uri); ok {
+		return mcp.NewResourceLinkFromResource(entry.resource), true
+	}
+
+	var matched *mcp.ResourceTemplate
+	for _, serverTemplate := range sessionTemplates {
+		matched = preferMatchingTemplate(uri, &serverTemplate.Template, matched)
+	}
+	if matched == nil {
+		s.resourceTemplates.each(func(_ string, entry resourceTemplateEntry) bool {
+			matched = preferMatchingTemplate(uri, &entry.template, matched)
+			return true
+		})
+	}
+	if matched == nil {
+		// Resolvers may register the resource, so they are asked unlocked.
+		resolved, err := s.resolveResource(ctx, uri)
+		if err != nil || resolved == nil {
+			return mcp.ResourceLink{}, false
+		}
+		return mcp.NewResourceLinkFromResource(resolved.Resource), true
+	}
+	link := mcp.NewResourceLink(uri, matched.Name, matched.Description, matched.MIMEType)
+	link.Annotated = matched.Annotated
+	return link, true
+}
+
+// preferMatchingTemplate returns candidate if it matches uri and is
+// preferred over best, and best otherwise.
+func preferMatchingTemplate(uri string, candidate, best *mcp.ResourceTemplate) *mcp.ResourceTemplate {
+	template := candidate.URITemplate
+	if template == nil || !matchesTemplate(uri, template) {
+		return best
+	}
+	if best != nil && !preferTemplate(template, best.URITemplate) {
+		return best
+	}
+	return candidate
+}
+
+// checkResourceLinks returns an error for the first resource link of result
+// that does not resolve, if WithResourceLinkValidation is used.
+func (s *MCPServer) checkResourceLinks(ctx context.Context, result *mcp.CallToolResult) error {
+	if !s.validateResourceLinks || result == nil {
+		return nil
+	}
+	for _, content := range result.Content {
+		link, ok := content.(mcp.ResourceLink)
+		if !ok {
+			continue
+		}
+		if _, ok := s.resolveResourceLink(ctx, link.URI); !ok {
+			return fmt.Errorf("tool result links to unknown resource URI '%s': %w", link.URI, ErrResourceNotFound)
+		}
+	}
+	return nil
+}
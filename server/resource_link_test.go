@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func noContents(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return nil, nil
+}
+
+func TestMCPServer_ResourceLink(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+	server.AddResource(mcp.NewResource("test://docs/readme", "readme",
+		mcp.WithResourceDescription("The readme"), mcp.WithMIMEType("text/markdown")), noContents)
+	server.AddResourceTemplate(mcp.NewResourceTemplate("test://docs/{name}", "doc",
+		mcp.WithTemplateDescription("A document")), namedTemplateHandler("doc"))
+	server.AddResourceTemplate(mcp.NewResourceTemplate("test://docs/{name}.json", "json doc",
+		mcp.WithTemplateMIMEType("application/json")), namedTemplateHandler("json"))
+
+	session := &sessionTestClientWithResources{sessionID: "session-1", initialized: true}
+	session.SetSessionResources(map[string]ServerResource{
+		"test://docs/readme": {Resource: mcp.NewResource("test://docs/readme", "session readme"), Handler: noContents},
+	})
+	sessionCtx := server.WithContext(context.Background(), session)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		uri      string
+		expected mcp.ResourceLink
+	}{
+		{
+			name:     "resource",
+			ctx:      context.Background(),
+			uri:      "test://docs/readme",
+			expected: mcp.NewResourceLink("test://docs/readme", "readme", "The readme", "text/markdown"),
+		},
+		{
+			name:     "session resource",
+			ctx:      sessionCtx,
+			uri:      "test://docs/readme",
+			expected: mcp.NewResourceLink("test://docs/readme", "session readme", "", ""),
+		},
+		{
+			name:     "template",
+			ctx:      context.Background(),
+			uri:      "test://docs/guide",
+			expected: mcp.NewResourceLink("test://docs/guide", "doc", "A document", ""),
+		},
+		{
+			name:     "most specific template",
+			ctx:      context.Background(),
+			uri:      "test://docs/config.json",
+			expected: mcp.NewResourceLink("test://docs/config.json", "json doc", "", "application/json"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link, err := server.ResourceLink(tt.ctx, tt.uri)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, link)
+		})
+	}
+
+	_, err := server.ResourceLink(context.Background(), "test://other/readme")
+	assert.ErrorIs(t, err, ErrResourceNotFound)
+}
+
+func TestMCPServer_WithResourceLinkValidation(t *testing.T) {
+	for _, validate := range []bool{false, true} {
+		t.Run(fmt.Sprintf("validate=%v", validate), func(t *testing.T) {
+			var opts []ServerOption
+			if validate {
+				opts = append(opts, WithResourceLinkValidation())
+			}
+			server := NewMCPServer("test-server", "1.0.0", opts...)
+			server.AddResourceTemplate(mcp.NewResourceTemplate("test://docs/{name}", "doc"), namedTemplateHandler("doc"))
+			server.AddTool(mcp.NewTool("link"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				link, err := ServerFromContext(ctx).ResourceLink(ctx, "test://docs/guide")
+				if err != nil {
+					return nil, err
+				}
+				return mcp.NewToolResultResourceLink("found", link, mcp.NewResourceLink(request.GetString("uri", ""), "other", "", "")), nil
+			})
+
+			call := func(uri string) mcp.JSONRPCMessage {
+				return server.HandleMessage(context.Background(), json.RawMessage(fmt.Sprintf(
+					`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"link","arguments":{"uri":%q}}}`, uri)))
+			}
+
+			response, ok := call("test://docs/other").(mcp.JSONRPCResponse)
+			require.True(t, ok)
+			result := response.Result.(mcp.CallToolResult)
+			require.Len(t, result.Content, 3)
+			assert.Equal(t, mcp.NewResourceLink("test://docs/guide", "doc", "", ""), result.Content[1])
+
+			response2 := call("test://missing")
+			if !validate {
+				assert.IsType(t, mcp.JSONRPCResponse{}, response2)
+				return
+			}
+			errResponse, ok := response2.(mcp.JSONRPCError)
+			require.True(t, ok, "unexpected response %#v", response2)
+			assert.Equal(t, mcp.INTERNAL_ERROR, errResponse.Error.Code)
+			assert.Contains(t, errResponse.Error.Message, "test://missing")
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// AcceptMetaKey is the _meta key of a resources/read request listing the
+// MIME types the client prefers, most preferred first, for example
+// "application/json, text/*". A list of strings is accepted too.
+const AcceptMetaKey = "accept"
+
+// sniffLen is the number of bytes http.DetectContentType considers.
+const sniffLen = 512
+
+// WithResourceMIMEDetection adds a middleware that fills in the MIME type
+// of resource contents returned without one. The type is derived from the
+// extension of the content URI or, failing that, by sniffing the content.
+func WithResourceMIMEDetection() ServerOption {
+	return WithResourceHandlerMiddleware(func(next ResourceHandlerFunc) ResourceHandlerFunc {
+		return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			contents, err := next(ctx, request)
+			if err != nil {
+				return nil, err
+			}
+			for i, content := range contents {
+				switch c := content.(type) {
+				case mcp.TextResourceContents:
+					if c.MIMEType == "" {
+						c.MIMEType = DetectMIMEType(c.URI, []byte(c.Text))
+						contents[i] = c
+					}
+				case mcp.BlobResourceContents:
+					if c.MIMEType == "" {
+						// Only the sniffed prefix of the blob is decoded.
+						prefix := c.Blob[:min(len(c.Blob), base64.StdEncoding.EncodedLen(sniffLen))]
+						data, _ := base64.StdEncoding.DecodeString(prefix)
+						c.MIMEType = DetectMIMEType(c.URI, data)
+						contents[i] = c
+					}
+				}
+			}
+			return contents, nil
+		}
+	})
+}
+
+// DetectMIMEType returns the MIME type of content identified by uri. The
+// extension of the URI path is used if it has a registered type, otherwise
+// the type is sniffed from the first bytes of content.
+func DetectMIMEType(uri string, content []byte) string {
+	p := uri
+	if u, err := url.Parse(uri); err == nil && u.Path != "" {
+		p = u.Path
+	}
+	if ext := path.Ext(p); ext != "" {
+		if mimeType := mime.TypeByExtension(ext); mimeType != "" {
+			return mimeType
+		}
+	}
+	return http.DetectContentType(content[:min(len(content), sniffLen)])
+}
+
+// AcceptedMIMETypes returns the MIME types of the AcceptMetaKey hint of a
+// read request, most preferred first. Media type parameters such as q are
+// dropped.
+func AcceptedMIMETypes(request mcp.ReadResourceRequest) []string {
+	if request.Params.Meta == nil {
+		return nil
+	}
+	var values []string
+	switch accept := request.Params.Meta.AdditionalFields[AcceptMetaKey].(type) {
+	case string:
+		values = strings.Split(accept, ",")
+	case []string:
+		values = accept
+	case []any:
+		for _, value := range accept {
+			if s, ok := value.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	accepted := make([]string, 0, len(values))
+	for _, value := range values {
+		if mediaType := normalizeMediaType(value); mediaType != "" {
+			accepted = append(accepted, mediaType)
+		}
+	}
+	return accepted
+}
+
+// NegotiateMIMEType returns the offered MIME type that best matches the
+// accept hint of the request. Without a hint the first offered type is
+// returned. The second return value is false if no offered type is
+// acceptable.
+func NegotiateMIMEType(request mcp.ReadResourceRequest, offered ...string) (string, bool) {
+	if len(offered) == 0 {
+		return "", false
+	}
+	accepted := AcceptedMIMETypes(request)
+	if len(accepted) == 0 {
+		return offered[0], true
+	}
+	for _, pattern := range accepted {
+		for _, candidate := range offered {
+			if mediaTypeMatches(pattern, normalizeMediaType(candidate)) {
+				return candidate, true
+			}
+		}
+	}
+	return "", false
+}
+
+// ResourceRepresentation renders a resource in one MIME type.
+type ResourceRepresentation struct {
+	MIMEType string
+	Handler  ResourceHandlerFunc
+}
+
+// NewNegotiatedResourceHandler returns a handler that serves the
+// representation matching the accept hint of each request, so one resource
+// or template can be read, for example, as JSON or as plain text:
+//
+//	s.AddResourceTemplate(template, server.ResourceTemplateHandlerFunc(server.NewNegotiatedResourceHandler(
+//	    server.ResourceRepresentation{MIMEType: "application/json", Handler: renderJSON},
+//	    server.ResourceRepresentation{MIMEType: "text/plain", Handler: renderText},
+//	)))
+//
+// The first representation is served when the request has no hint. A
+// request whose hint matches none of them fails with mcp.ErrInvalidParams.
+// Contents returned without a MIME type get the one of the representation.
+func NewNegotiatedResourceHandler(representations ...ResourceRepresentation) ResourceHandlerFunc {
+	offered := make([]string, len(representations))
+	for i, representation := range representations {
+		offered[i] = representation.MIMEType
+	}
+
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		mimeType, ok := NegotiateMIMEType(request, offered...)
+		if !ok {
+			return nil, fmt.Errorf("%w: resource '%s' is not available as %s, only as %s",
+				mcp.ErrInvalidParams, request.Params.URI,
+				strings.Join(AcceptedMIMETypes(request), ", "), strings.Join(offered, ", "))
+		}
+
+		var handler ResourceHandlerFunc
+		for _, representation := range representations {
+			if representation.MIMEType == mimeType {
+				handler = representation.Handler
+				break
+			}
+		}
+		contents, err := handler(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		for i, content := range contents {
+			switch c := content.(type) {
+			case mcp.TextResourceContents:
+				if c.MIMEType == "" {
+					c.MIMEType = mimeType
+					contents[i] = c
+				}
+			case mcp.BlobResourceContents:
+				if c.MIMEType == "" {
+					c.MIMEType = mimeType
+					contents[i] = c
+				}
+			}
+		}
+		return contents, nil
+	}
+}
+
+// normalizeMediaType lowercases a media type and strips its parameters.
+func normalizeMediaType(value string) string {
+	mediaType, _, _ := strings.Cut(value, ";")
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+// mediaTypeMatches reports whether mediaType matches pattern, which may
+// use wildcards such as "*/*" or "text/*".
+func mediaTypeMatches(pattern, mediaType string) bool {
+	if pattern == "*/*" || pattern == "*" || pattern == mediaType {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "/*"); ok {
+		return strings.HasPrefix(mediaType, prefix+"/")
+	}
+	return false
+}
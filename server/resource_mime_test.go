@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n")
+
+func TestDetectMIMEType(t *testing.T) {
+	tests := []struct {
+		name     string
+		uri      string
+		content  []byte
+		expected string
+	}{
+		{name: "extension", uri: "file:///data/config.json", content: []byte("{}"), expected: "application/json"},
+		{name: "extension with query", uri: "https://example.com/logo.png?size=2", expected: "image/png"},
+		{name: "sniffed binary", uri: "blob://logo", content: pngHeader, expected: "image/png"},
+		{name: "sniffed text", uri: "notes://today", content: []byte("hello"), expected: "text/plain; charset=utf-8"},
+		{name: "unknown extension", uri: "file:///data.unknownext", content: []byte("<html><body></body></html>"), expected: "text/html; charset=utf-8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DetectMIMEType(tt.uri, tt.content))
+		})
+	}
+}
+
+func TestMCPServer_WithResourceMIMEDetection(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithResourceMIMEDetection())
+	server.AddResource(mcp.NewResource("blob://logo", "logo"), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{
+			mcp.BlobResourceContents{URI: "blob://logo", Blob: base64.StdEncoding.EncodeToString(pngHeader)},
+			mcp.TextResourceContents{URI: "file:///config.json", Text: "{}"},
+			mcp.TextResourceContents{URI: "notes://today", MIMEType: "text/markdown", Text: "# kept"},
+		}, nil
+	})
+
+	response := server.HandleMessage(context.Background(), json.RawMessage(
+		`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"blob://logo"}}`))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "expected success response, got %#v", response)
+	contents := resp.Result.(mcp.ReadResourceResult).Contents
+	require.Len(t, contents, 3)
+	assert.Equal(t, "image/png", contents[0].(mcp.BlobResourceContents).MIMEType)
+	assert.Equal(t, "application/json", contents[1].(mcp.TextResourceContents).MIMEType)
+	assert.Equal(t, "text/markdown", contents[2].(mcp.TextResourceContents).MIMEType, "explicit types are kept")
+}
+
+func TestNewNegotiatedResourceHandler(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+	server.AddResourceTemplate(
+		mcp.NewResourceTemplate("users://{id}", "user"),
+		ResourceTemplateHandlerFunc(NewNegotiatedResourceHandler(
+			ResourceRepresentation{MIMEType: "application/json", Handler: func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+				return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: `{"name":"Ada"}`}}, nil
+			}},
+			ResourceRepresentation{MIMEType: "text/plain", Handler: func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+				return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: "Ada"}}, nil
+			}},
+		)),
+	)
+
+	tests := []struct {
+		name             string
+		meta             string
+		expectedMIMEType string
+		expectedText     string
+		expectedCode     int
+	}{
+		{name: "no hint serves the first representation", expectedMIMEType: "application/json", expectedText: `{"name":"Ada"}`},
+		{name: "preferred type", meta: `{"accept":"text/plain, application/json"}`, expectedMIMEType: "text/plain", expectedText: "Ada"},
+		{name: "wildcard with parameters", meta: `{"accept":"text/*;q=0.8"}`, expectedMIMEType: "text/plain", expectedText: "Ada"},
+		{name: "list of types", meta: `{"accept":["image/png","application/json"]}`, expectedMIMEType: "application/json", expectedText: `{"name":"Ada"}`},
+		{name: "nothing acceptable", meta: `{"accept":"image/png"}`, expectedCode: mcp.INVALID_PARAMS},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := `{"uri":"users://1"}`
+			if tt.meta != "" {
+				params = `{"uri":"users://1","_meta":` + tt.meta + `}`
+			}
+			response := server.HandleMessage(context.Background(), json.RawMessage(
+				`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":`+params+`}`))
+
+			if tt.expectedCode != 0 {
+				errorResponse, ok := response.(mcp.JSONRPCError)
+				require.True(t, ok, "expected error response, got %#v", response)
+				assert.Equal(t, tt.expectedCode, errorResponse.Error.Code)
+				return
+			}
+			resp, ok := response.(mcp.JSONRPCResponse)
+			require.True(t, ok, "expected success response, got %#v", response)
+			contents := resp.Result.(mcp.ReadResourceResult).Contents
+			require.Len(t, contents, 1)
+			text := contents[0].(mcp.TextResourceContents)
+			assert.Equal(t, tt.expectedMIMEType, text.MIMEType)
+			assert.Equal(t, tt.expectedText, text.Text)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ResolvedResource is a resource materialized by a ResourceResolverFunc.
+type ResolvedResource struct {
+	ServerResource
+	// Register adds the resource to the server, as AddResources does, so
+	// later reads and lists of it skip the resolver. Without it nothing is
+	// kept and the resolver is asked again on every read.
+	Register bool
+}
+
+// ResourceResolverFunc materializes the resource at uri when a read matches
+// no registered resource or resource template, such as a row of a database
+// or an object of a bucket too numerous to register up front. It returns
+// nil and no error if it does not serve uri. An error fails the read with
+// the code handler errors are reported with.
+type ResourceResolverFunc func(ctx context.Context, uri string) (*ResolvedResource, error)
+
+// WithResourceResolver adds a resolver asked for resources no registered
+// resource or resource template serves. Resolvers are asked in the order
+// they are added until one returns a resource, whose handler then serves
+// the read through the resource handler middlewares. Resource links to
+// URIs a resolver serves also resolve; see ResourceLink. The resources
+// capability is enabled even if no resource is registered.
+func WithResourceResolver(resolver ResourceResolverFunc) ServerOption {
+	return func(s *MCPServer) {
+		s.implicitlyRegisterResourceCapabilities()
+		s.resourceResolvers = append(s.resourceResolvers, resolver)
+	}
+}
+
+// resolveResource asks the resolvers of the server for the resource at
+// uri, registering it if the resolver asks to. It returns nil if no
+// resolver serves uri.
+func (s *MCPServer) resolveResource(ctx context.Context, uri string) (*ServerResource, error) {
+	for _, resolver := range s.resourceResolvers {
+		resolved, err := resolver(ctx, uri)
+		if err != nil {
+			return nil, err
+		}
+		if resolved == nil {
+			continue
+		}
+		if resolved.Handler == nil {
+			return nil, fmt.Errorf("resolver returned no handler for resource URI '%s'", uri)
+		}
+		if resolved.Register {
+			s.AddResources(resolved.ServerResource)
+		}
+		return &resolved.ServerResource, nil
+	}
+	return nil, nil
+}
+
+// readResource serves request with handler through the resource handler
+// middlewares, caching validators per URI if shared.
+func (s *MCPServer) readResource(
+	ctx context.Context,
+	id any,
+	request mcp.ReadResourceRequest,
+	handler ResourceHandlerFunc,
+	shared bool,
+) ([]mcp.ResourceContents, *requestError) {
+	finalHandler := s.cachingResourceHandler(handler, shared)
+	s.resourceMiddlewareMu.RLock()
+	mw := s.resourceHandlerMiddlewares
+	// Apply middlewares in reverse order
+	for i := len(mw) - 1; i >= 0; i-- {
+		finalHandler = mw[i](finalHandler)
+	}
+	s.resourceMiddlewareMu.RUnlock()
+
+	contents, err := finalHandler(ctx, request)
+	if err != nil {
+		return nil, &requestError{
+			id:   id,
+			code: s.handlerErrorCode(err),
+			err:  err,
+		}
+	}
+	return contents, nil
+}
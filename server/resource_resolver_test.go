@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func readResourceMessage(server *MCPServer, uri string) mcp.JSONRPCMessage {
+	return server.HandleMessage(context.Background(),
+		[]byte(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"`+uri+`"}}`))
+}
+
+// rowResolver resolves test://rows/<id> to a text resource, counting calls.
+func rowResolver(calls *atomic.Int32, register bool) ResourceResolverFunc {
+	return func(ctx context.Context, uri string) (*ResolvedResource, error) {
+		calls.Add(1)
+		id, ok := strings.CutPrefix(uri, "test://rows/")
+		if !ok {
+			return nil, nil
+		}
+		if id == "broken" {
+			return nil, errors.New("backend unavailable")
+		}
+		return &ResolvedResource{
+			ServerResource: ServerResource{
+				Resource: mcp.NewResource(uri, "row "+id),
+				Handler: func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+					return []mcp.ResourceContents{mcp.TextResourceContents{URI: uri, Text: "row " + id}}, nil
+				},
+			},
+			Register: register,
+		}, nil
+	}
+}
+
+func TestMCPServer_ResourceResolver(t *testing.T) {
+	tests := []struct {
+		name      string
+		uri       string
+		wantText  string
+		wantCode  int
+		wantCalls int32
+	}{
+		{name: "resolved", uri: "test://rows/42", wantText: "row 42", wantCalls: 1},
+		{name: "registered resource skips the resolver", uri: "test://static", wantText: "static"},
+		{name: "template skips the resolver", uri: "test://docs/readme", wantText: "doc"},
+		{name: "not resolved", uri: "test://other/1", wantCode: mcp.RESOURCE_NOT_FOUND, wantCalls: 1},
+		{name: "resolver error", uri: "test://rows/broken", wantCode: mcp.INTERNAL_ERROR, wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := NewMCPServer("test-server", "1.0.0", WithResourceResolver(rowResolver(&calls, false)))
+			server.AddResource(mcp.NewResource("test://static", "static"),
+				func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+					return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: "static"}}, nil
+				})
+			server.AddResourceTemplate(mcp.NewResourceTemplate("test://docs/{name}", "doc"), namedTemplateHandler("doc"))
+
+			response := readResourceMessage(server, tt.uri)
+			assert.Equal(t, tt.wantCalls, calls.Load())
+			if tt.wantCode != 0 {
+				errResponse, ok := response.(mcp.JSONRPCError)
+				require.True(t, ok, "unexpected response %v", response)
+				assert.Equal(t, tt.wantCode, errResponse.Error.Code)
+				return
+			}
+			resp, ok := response.(mcp.JSONRPCResponse)
+			require.True(t, ok, "unexpected response %v", response)
+			result := resp.Result.(mcp.ReadResourceResult)
+			require.Len(t, result.Contents, 1)
+			assert.Equal(t, tt.wantText, result.Contents[0].(mcp.TextResourceContents).Text)
+		})
+	}
+}
+
+func TestMCPServer_ResourceResolverRegister(t *testing.T) {
+	tests := []struct {
+		name      string
+		register  bool
+		wantCalls int32
+		wantList  int
+	}{
+		{name: "not registered", register: false, wantCalls: 2, wantList: 0},
+		{name: "registered", register: true, wantCalls: 1, wantList: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := NewMCPServer("test-server", "1.0.0", WithResourceResolver(rowResolver(&calls, tt.register)))
+			for i := 0; i < 2; i++ {
+				_, ok := readResourceMessage(server, "test://rows/7").(mcp.JSONRPCResponse)
+				require.True(t, ok)
+			}
+			assert.Equal(t, tt.wantCalls, calls.Load())
+			assert.Equal(t, tt.wantList, server.resources.len())
+		})
+	}
+}
+
+func TestMCPServer_ResourceResolverOrderAndMiddleware(t *testing.T) {
+	var firstCalls, secondCalls atomic.Int32
+	var middlewareCalls atomic.Int32
+	server := NewMCPServer("test-server", "1.0.0",
+		WithResourceResolver(func(ctx context.Context, uri string) (*ResolvedResource, error) {
+			firstCalls.Add(1)
+			return nil, nil
+		}),
+		WithResourceResolver(rowResolver(&secondCalls, false)),
+		WithResourceHandlerMiddleware(func(next ResourceHandlerFunc) ResourceHandlerFunc {
+			return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+				middlewareCalls.Add(1)
+				return next(ctx, request)
+			}
+		}),
+	)
+
+	_, ok := readResourceMessage(server, "test://rows/1").(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	assert.Equal(t, int32(1), firstCalls.Load())
+	assert.Equal(t, int32(1), secondCalls.Load())
+	assert.Equal(t, int32(1), middlewareCalls.Load())
+}
+
+func TestMCPServer_ResourceResolverLinks(t *testing.T) {
+	var calls atomic.Int32
+	server := NewMCPServer("test-server", "1.0.0", WithResourceResolver(rowResolver(&calls, true)))
+
+	link, err := server.ResourceLink(context.Background(), "test://rows/3")
+	require.NoError(t, err)
+	assert.Equal(t, mcp.NewResourceLink("test://rows/3", "row 3", "", ""), link)
+
+	_, err = server.ResourceLink(context.Background(), "test://rows/broken")
+	assert.ErrorIs(t, err, ErrResourceNotFound)
+	_, err = server.ResourceLink(context.Background(), "test://other/1")
+	assert.ErrorIs(t, err, ErrResourceNotFound)
+}
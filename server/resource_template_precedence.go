@@ -0,0 +1,212 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yosida95/uritemplate/v3"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ResourceTemplateConflict describes two resource templates that both match
+// URI without either being more specific than the other. Such a URI is
+// served by the template whose raw form sorts first, which is deterministic
+// but rarely intended.
+type ResourceTemplateConflict struct {
+	// Template is the template being registered, or the one sorting first
+	// when conflicts are listed with ResourceTemplateConflicts.
+	Template string
+	// Existing is the template it conflicts with.
+	Existing string
+	// URI is an example URI both templates match.
+	URI string
+}
+
+// Error implements the error interface so that conflicts can be logged or
+// returned as is. It matches ErrResourceTemplateConflict.
+func (c ResourceTemplateConflict) Error() string {
+	return fmt.Sprintf("resource template %q conflicts with %q: both match %q", c.Template, c.Existing, c.URI)
+}
+
+// Is matches ErrResourceTemplateConflict.
+func (c ResourceTemplateConflict) Is(target error) bool {
+	return target == ErrResourceTemplateConflict
+}
+
+// ResourceTemplateConflictFunc is called for every conflict a newly
+// registered resource template introduces.
+type ResourceTemplateConflictFunc func(conflict ResourceTemplateConflict)
+
+// WithResourceTemplateConflictHandler sets a function called when a resource
+// template is registered that conflicts with one already registered, for
+// example to log it or to fail fast during startup.
+func WithResourceTemplateConflictHandler(handler ResourceTemplateConflictFunc) ServerOption {
+	return func(s *MCPServer) {
+		s.templateConflictHandler = handler
+	}
+}
+
+// ResourceTemplateConflicts returns every pair of conflicting resource
+// templates registered with the server, ordered by template.
+func (s *MCPServer) ResourceTemplateConflicts() []ResourceTemplateConflict {
+	templates := make(map[string]*mcp.URITemplate, s.resourceTemplates.len())
+	raws := make([]string, 0, len(templates))
+	s.resourceTemplates.each(func(raw string, entry resourceTemplateEntry) bool {
+		raws = append(raws, raw)
+		templates[raw] = entry.template.URITemplate
+		return true
+	})
+
+	sort.Strings(raws)
+	var conflicts []ResourceTemplateConflict
+	for i, raw := range raws {
+		for _, other := range raws[i+1:] {
+			if conflict, ok := templateConflict(templates[raw], templates[other]); ok {
+				conflicts = append(conflicts, conflict)
+			}
+		}
+	}
+	return conflicts
+}
+
+// reportResourceTemplateConflicts passes the conflicts the given templates
+// have with the other registered templates to the conflict handler.
+func (s *MCPServer) reportResourceTemplateConflicts(added []ServerResourceTemplate) {
+	if s.templateConflictHandler == nil {
+		return
+	}
+
+	var conflicts []ResourceTemplateConflict
+	for _, entry := range added {
+		template := entry.Template.URITemplate
+		if template == nil {
+			continue
+		}
+		s.resourceTemplates.each(func(raw string, existing resourceTemplateEntry) bool {
+			if raw == template.Raw() {
+				return true
+			}
+			if conflict, ok := templateConflict(template, existing.template.URITemplate); ok {
+				conflicts = append(conflicts, conflict)
+			}
+			return true
+		})
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		if conflicts[i].Template != conflicts[j].Template {
+			return conflicts[i].Template < conflicts[j].Template
+		}
+		return conflicts[i].Existing < conflicts[j].Existing
+	})
+	for _, conflict := range conflicts {
+		s.templateConflictHandler(conflict)
+	}
+}
+
+// templateConflict reports whether two templates are equally specific and
+// match a common URI. The common URI is searched for by expanding each
+// template with placeholder values and matching the result against the other.
+func templateConflict(template, other *mcp.URITemplate) (ResourceTemplateConflict, bool) {
+	if template == nil || other == nil || compareTemplateSpecificity(template.Raw(), other.Raw()) != 0 {
+		return ResourceTemplateConflict{}, false
+	}
+	for _, pair := range [][2]*mcp.URITemplate{{template, other}, {other, template}} {
+		uri, err := pair[0].Expand(placeholderValues(pair[0]))
+		if err == nil && matchesTemplate(uri, pair[1]) {
+			return ResourceTemplateConflict{Template: template.Raw(), Existing: other.Raw(), URI: uri}, true
+		}
+	}
+	return ResourceTemplateConflict{}, false
+}
+
+func placeholderValues(template *mcp.URITemplate) uritemplate.Values {
+	values := uritemplate.Values{}
+	for _, name := range template.Varnames() {
+		values.Set(name, uritemplate.String("x"))
+	}
+	return values
+}
+
+// preferTemplate reports whether candidate, a template matching a URI,
+// should serve it rather than best, the preferred template so far, which is
+// nil when there is none. More specific templates are preferred, and equally
+// specific ones by their raw form to keep the choice deterministic.
+func preferTemplate(candidate, best *mcp.URITemplate) bool {
+	if best == nil {
+		return true
+	}
+	if c := compareTemplateSpecificity(candidate.Raw(), best.Raw()); c != 0 {
+		return c > 0
+	}
+	return candidate.Raw() < best.Raw()
+}
+
+// compareTemplateSpecificity compares two raw templates and returns a
+// positive number if a is more specific than b, a negative number if it is
+// less specific and zero if neither is. Path segments are compared from left
+// to right, the first literal segment facing one with a variable deciding.
+// Failing that, the template with more segments, then with more literal
+// characters, then with fewer expressions, is more specific.
+func compareTemplateSpecificity(a, b string) int {
+	segmentsA, segmentsB := templateSegments(a), templateSegments(b)
+	for i := 0; i < len(segmentsA) && i < len(segmentsB); i++ {
+		literalA := !strings.Contains(segmentsA[i], "{")
+		literalB := !strings.Contains(segmentsB[i], "{")
+		if literalA != literalB {
+			if literalA {
+				return 1
+			}
+			return -1
+		}
+	}
+	if len(segmentsA) != len(segmentsB) {
+		return len(segmentsA) - len(segmentsB)
+	}
+
+	literalsA, expressionsA := templateComposition(a)
+	literalsB, expressionsB := templateComposition(b)
+	if literalsA != literalsB {
+		return literalsA - literalsB
+	}
+	return expressionsB - expressionsA
+}
+
+// templateSegments splits a raw template at the slashes outside of its
+// expressions.
+func templateSegments(raw string) []string {
+	var segments []string
+	start, inExpression := 0, false
+	for i, r := range raw {
+		switch {
+		case r == '{':
+			inExpression = true
+		case r == '}':
+			inExpression = false
+		case r == '/' && !inExpression:
+			segments = append(segments, raw[start:i])
+			start = i + 1
+		}
+	}
+	return append(segments, raw[start:])
+}
+
+// templateComposition counts the literal characters and the expressions of
+// a raw template.
+func templateComposition(raw string) (literals, expressions int) {
+	inExpression := false
+	for _, r := range raw {
+		switch {
+		case r == '{':
+			inExpression = true
+			expressions++
+		case r == '}':
+			inExpression = false
+		case !inExpression:
+			literals++
+		}
+	}
+	return literals, expressions
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// namedTemplateHandler answers with the raw template serving the read.
+func namedTemplateHandler(template string) ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{URI: request.Params.URI, Text: template},
+		}, nil
+	}
+}
+
+func TestMCPServer_ResourceTemplatePrecedence(t *testing.T) {
+	tests := []struct {
+		name      string
+		templates []string
+		uri       string
+		expected  string
+	}{
+		{
+			name:      "literal segment beats variable",
+			templates: []string{"test://users/{id}/profile", "test://users/me/{section}"},
+			uri:       "test://users/me/profile",
+			expected:  "test://users/me/{section}",
+		},
+		{
+			name:      "more segments beat a reserved expansion",
+			templates: []string{"test://{+path}", "test://docs/{name}"},
+			uri:       "test://docs/readme",
+			expected:  "test://docs/{name}",
+		},
+		{
+			name:      "more literal characters win",
+			templates: []string{"test://files/{name}", "test://files/{name}.json"},
+			uri:       "test://files/config.json",
+			expected:  "test://files/{name}.json",
+		},
+		{
+			name:      "ties go to the first template in order",
+			templates: []string{"test://items/{name}", "test://items/{id}"},
+			uri:       "test://items/42",
+			expected:  "test://items/{id}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMCPServer("test-server", "1.0.0")
+			for _, template := range tt.templates {
+				server.AddResourceTemplate(mcp.NewResourceTemplate(template, template), namedTemplateHandler(template))
+			}
+
+			// Templates are stored in a map, so repeat the read to catch any
+			// dependency on iteration order.
+			for i := 0; i < 20; i++ {
+				response := server.HandleMessage(context.Background(), json.RawMessage(fmt.Sprintf(
+					`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":%q}}`, tt.uri)))
+				resp, ok := response.(mcp.JSONRPCResponse)
+				require.True(t, ok, "unexpected response %#v", response)
+				result := resp.Result.(mcp.ReadResourceResult)
+				require.Len(t, result.Contents, 1)
+				assert.Equal(t, tt.expected, result.Contents[0].(mcp.TextResourceContents).Text)
+			}
+		})
+	}
+}
+
+func TestCompareTemplateSpecificity(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "test://users/me/{section}", b: "test://users/{id}/profile", expected: 1},
+		{a: "test://users/{id}", b: "test://users/{id}/posts", expected: -1},
+		{a: "test://files{/path*}", b: "test://files/{name}", expected: -1},
+		{a: "test://items/{id}", b: "test://items/{name}", expected: 0},
+		{a: "test://items/{id}", b: "test://items/{id}{.format}", expected: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			got := compareTemplateSpecificity(tt.a, tt.b)
+			switch {
+			case tt.expected > 0:
+				assert.Positive(t, got)
+			case tt.expected < 0:
+				assert.Negative(t, got)
+			default:
+				assert.Zero(t, got)
+			}
+		})
+	}
+}
+
+func TestMCPServer_ResourceTemplateConflicts(t *testing.T) {
+	var reported []ResourceTemplateConflict
+	server := NewMCPServer("test-server", "1.0.0",
+		WithResourceTemplateConflictHandler(func(conflict ResourceTemplateConflict) {
+			reported = append(reported, conflict)
+		}))
+
+	for _, template := range []string{"test://items/{id}", "test://users/{id}", "test://users/me", "test://items/{name}"} {
+		server.AddResourceTemplate(mcp.NewResourceTemplate(template, template), namedTemplateHandler(template))
+	}
+	// Replacing a template does not conflict with itself.
+	server.AddResourceTemplate(mcp.NewResourceTemplate("test://items/{id}", "items"), namedTemplateHandler("items"))
+
+	require.Len(t, reported, 2)
+	assert.Equal(t, ResourceTemplateConflict{
+		Template: "test://items/{name}",
+		Existing: "test://items/{id}",
+		URI:      "test://items/x",
+	}, reported[0])
+	assert.Equal(t, "test://items/{id}", reported[1].Template)
+	assert.True(t, errors.Is(reported[0], ErrResourceTemplateConflict))
+
+	conflicts := server.ResourceTemplateConflicts()
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, "test://items/{id}", conflicts[0].Template)
+	assert.Equal(t, "test://items/{name}", conflicts[0].Existing)
+
+	server.DeleteResourceTemplates("test://items/{name}")
+	assert.Empty(t, server.ResourceTemplateConflicts())
+}
@@ -0,0 +1,232 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Priority is the scheduling class of a request. Waiting requests of a
+// higher priority are started before those of a lower one.
+type Priority int
+
+const (
+	// PriorityLow is for requests that can wait for every other one.
+	PriorityLow Priority = -1
+	// PriorityNormal is the priority of tool calls and prompts by default.
+	PriorityNormal Priority = 0
+	// PriorityHigh is the priority of the cheap requests clients need to
+	// stay responsive, like lists and reads, by default.
+	PriorityHigh Priority = 1
+	// PriorityCritical requests bypass the scheduler and never wait, like
+	// pings and initialize by default.
+	PriorityCritical Priority = 2
+)
+
+// SchedulerConfig configures the scheduler set by WithScheduler.
+type SchedulerConfig struct {
+	// MaxConcurrent bounds the requests handled at once across sessions.
+	// It must be positive.
+	MaxConcurrent int
+	// MaxQueued bounds the requests waiting to be handled. Requests over
+	// the bound are rejected with a RATE_LIMITED error matching
+	// ErrSchedulerQueueFull. Zero means no bound.
+	MaxQueued int
+	// Priority classifies requests, DefaultRequestPriority if nil.
+	Priority func(ctx context.Context, method mcp.MCPMethod, message []byte) Priority
+}
+
+// WithScheduler bounds the number of requests handled at once and decides
+// which waiting request runs next: the one of the highest priority, taking
+// turns between sessions within a priority so that a chatty session cannot
+// starve the others. Notifications, including cancellations, are never
+// queued, and neither are PriorityCritical requests.
+func WithScheduler(config SchedulerConfig) ServerOption {
+	return func(s *MCPServer) {
+		s.scheduler = newScheduler(config)
+	}
+}
+
+// DefaultRequestPriority is the default SchedulerConfig.Priority. Pings and
+// initialize are critical; lists, reads, subscriptions and logging levels
+// are high; everything else, including tool calls, is normal.
+func DefaultRequestPriority(_ context.Context, method mcp.MCPMethod, _ []byte) Priority {
+	switch method {
+	case mcp.MethodPing, mcp.MethodInitialize:
+		return PriorityCritical
+	case mcp.MethodToolsList, mcp.MethodResourcesList, mcp.MethodResourcesTemplatesList,
+		mcp.MethodPromptsList, mcp.MethodResourcesRead,
+		mcp.MethodResourcesSubscribe, mcp.MethodResourcesUnsubscribe, mcp.MethodSetLogLevel:
+		return PriorityHigh
+	default:
+		return PriorityNormal
+	}
+}
+
+// scheduler hands out slots to run request handlers.
+type scheduler struct {
+	config SchedulerConfig
+
+	mu      sync.Mutex
+	running int
+	queued  int
+	// classes holds the waiting requests of each priority.
+	classes map[Priority]*schedulerClass
+}
+
+// schedulerClass queues the waiting requests of a priority by session and
+// serves the sessions in turn.
+type schedulerClass struct {
+	// sessions are the sessions with waiting requests, in serving order.
+	sessions []string
+	waiting  map[string][]*schedulerWaiter
+}
+
+type schedulerWaiter struct {
+	ready chan struct{}
+}
+
+func newScheduler(config SchedulerConfig) *scheduler {
+	if config.MaxConcurrent <= 0 {
+		config.MaxConcurrent = 1
+	}
+	if config.Priority == nil {
+		config.Priority = DefaultRequestPriority
+	}
+	return &scheduler{config: config, classes: make(map[Priority]*schedulerClass)}
+}
+
+// gates reports whether acquire may make the request wait for a slot.
+func (sc *scheduler) gates(ctx context.Context, method mcp.MCPMethod, message []byte) bool {
+	return sc != nil && sc.config.Priority(ctx, method, message) < PriorityCritical
+}
+
+// acquire waits for a slot to handle a request and returns the function
+// releasing it. It is a no-op without a scheduler.
+func (sc *scheduler) acquire(ctx context.Context, id any, method mcp.MCPMethod, message []byte) (release func(), reqErr *requestError) {
+	if sc == nil {
+		return func() {}, nil
+	}
+	priority := sc.config.Priority(ctx, method, message)
+	if priority >= PriorityCritical {
+		return func() {}, nil
+	}
+
+	sc.mu.Lock()
+	if sc.running < sc.config.MaxConcurrent && sc.queued == 0 {
+		sc.running++
+		sc.mu.Unlock()
+		return sc.release, nil
+	}
+	if sc.config.MaxQueued > 0 && sc.queued >= sc.config.MaxQueued {
+		sc.mu.Unlock()
+		return nil, &requestError{id: id, code: mcp.RATE_LIMITED, err: ErrSchedulerQueueFull}
+	}
+	sessionID := ""
+	if session := ClientSessionFromContext(ctx); session != nil {
+		sessionID = session.SessionID()
+	}
+	waiter := &schedulerWaiter{ready: make(chan struct{})}
+	sc.enqueue(priority, sessionID, waiter)
+	sc.mu.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return sc.release, nil
+	case <-ctx.Done():
+		sc.mu.Lock()
+		removed := sc.remove(priority, sessionID, waiter)
+		sc.mu.Unlock()
+		if !removed {
+			// The slot was handed over just as the context was done.
+			sc.release()
+		}
+		if timeoutErr := requestTimeoutError(ctx, id); timeoutErr != nil {
+			return nil, timeoutErr
+		}
+		return nil, &requestError{id: id, code: mcp.REQUEST_INTERRUPTED, err: fmt.Errorf("request canceled while queued: %w", context.Cause(ctx))}
+	}
+}
+
+// release frees a slot, handing it to the next waiting request if any.
+func (sc *scheduler) release() {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if waiter := sc.next(); waiter != nil {
+		close(waiter.ready)
+		return
+	}
+	sc.running--
+}
+
+// enqueue adds a waiter. The scheduler must be locked.
+func (sc *scheduler) enqueue(priority Priority, sessionID string, waiter *schedulerWaiter) {
+	class, ok := sc.classes[priority]
+	if !ok {
+		class = &schedulerClass{waiting: make(map[string][]*schedulerWaiter)}
+		sc.classes[priority] = class
+	}
+	if len(class.waiting[sessionID]) == 0 {
+		class.sessions = append(class.sessions, sessionID)
+	}
+	class.waiting[sessionID] = append(class.waiting[sessionID], waiter)
+	sc.queued++
+}
+
+// next dequeues the waiter to run next: the first one of the next session
+// in turn of the highest priority. The scheduler must be locked.
+func (sc *scheduler) next() *schedulerWaiter {
+	var best *schedulerClass
+	bestPriority := Priority(0)
+	for priority, class := range sc.classes {
+		if len(class.sessions) > 0 && (best == nil || priority > bestPriority) {
+			best, bestPriority = class, priority
+		}
+	}
+	if best == nil {
+		return nil
+	}
+
+	sessionID := best.sessions[0]
+	queue := best.waiting[sessionID]
+	waiter := queue[0]
+	best.sessions = best.sessions[1:]
+	if len(queue) > 1 {
+		best.waiting[sessionID] = queue[1:]
+		// The session takes its next turn after the others.
+		best.sessions = append(best.sessions, sessionID)
+	} else {
+		delete(best.waiting, sessionID)
+	}
+	sc.queued--
+	return waiter
+}
+
+// remove drops a waiter whose context is done, reporting whether it was
+// still queued. The scheduler must be locked.
+func (sc *scheduler) remove(priority Priority, sessionID string, waiter *schedulerWaiter) bool {
+	class := sc.classes[priority]
+	queue := class.waiting[sessionID]
+	for i, w := range queue {
+		if w != waiter {
+			continue
+		}
+		queue = append(queue[:i:i], queue[i+1:]...)
+		if len(queue) > 0 {
+			class.waiting[sessionID] = queue
+		} else {
+			delete(class.waiting, sessionID)
+			for j, id := range class.sessions {
+				if id == sessionID {
+					class.sessions = append(class.sessions[:j:j], class.sessions[j+1:]...)
+					break
+				}
+			}
+		}
+		sc.queued--
+		return true
+	}
+	return false
+}
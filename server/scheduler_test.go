@@ -0,0 +1,161 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func sessionContext(s *MCPServer, sessionID string) context.Context {
+	return s.WithContext(context.Background(), &sessionTestClient{sessionID: sessionID, initialized: true})
+}
+
+func TestScheduler_Order(t *testing.T) {
+	s := NewMCPServer("test-server", "1.0.0")
+	sc := newScheduler(SchedulerConfig{MaxConcurrent: 1})
+
+	release, reqErr := sc.acquire(context.Background(), 0, mcp.MethodToolsCall, nil)
+	require.Nil(t, reqErr)
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	queued := 0
+	queue := func(name, sessionID string, method mcp.MCPMethod) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, reqErr := sc.acquire(sessionContext(s, sessionID), name, method, nil)
+			if !assert.Nil(t, reqErr) {
+				return
+			}
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			release()
+		}()
+		// Wait for the request to be queued to make the order deterministic.
+		queued++
+		require.Eventually(t, func() bool {
+			sc.mu.Lock()
+			defer sc.mu.Unlock()
+			return sc.queued == queued
+		}, time.Second, time.Millisecond)
+	}
+
+	queue("a1", "a", mcp.MethodToolsCall)
+	queue("a2", "a", mcp.MethodToolsCall)
+	queue("a3", "a", mcp.MethodToolsCall)
+	queue("b1", "b", mcp.MethodToolsCall)
+	queue("c1", "c", mcp.MethodToolsList)
+
+	// Pings never wait.
+	releasePing, reqErr := sc.acquire(sessionContext(s, "a"), "ping", mcp.MethodPing, nil)
+	require.Nil(t, reqErr)
+	releasePing()
+
+	release()
+	wg.Wait()
+	assert.Equal(t, []string{"c1", "a1", "b1", "a2", "a3"}, order)
+	assert.Zero(t, sc.running)
+	assert.Zero(t, sc.queued)
+}
+
+func TestScheduler_QueueFull(t *testing.T) {
+	sc := newScheduler(SchedulerConfig{MaxConcurrent: 1, MaxQueued: 1})
+	release, reqErr := sc.acquire(context.Background(), 1, mcp.MethodToolsCall, nil)
+	require.Nil(t, reqErr)
+
+	done := make(chan *requestError)
+	go func() {
+		release, reqErr := sc.acquire(context.Background(), 2, mcp.MethodToolsCall, nil)
+		if reqErr == nil {
+			release()
+		}
+		done <- reqErr
+	}()
+	require.Eventually(t, func() bool {
+		sc.mu.Lock()
+		defer sc.mu.Unlock()
+		return sc.queued == 1
+	}, time.Second, time.Millisecond)
+
+	_, reqErr = sc.acquire(context.Background(), 3, mcp.MethodToolsCall, nil)
+	require.NotNil(t, reqErr)
+	assert.Equal(t, mcp.RATE_LIMITED, reqErr.code)
+	assert.ErrorIs(t, reqErr, ErrSchedulerQueueFull)
+
+	release()
+	assert.Nil(t, <-done)
+}
+
+func TestScheduler_CanceledWhileQueued(t *testing.T) {
+	sc := newScheduler(SchedulerConfig{MaxConcurrent: 1})
+	release, reqErr := sc.acquire(context.Background(), 1, mcp.MethodToolsCall, nil)
+	require.Nil(t, reqErr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan *requestError)
+	go func() {
+		_, reqErr := sc.acquire(ctx, 2, mcp.MethodToolsCall, nil)
+		done <- reqErr
+	}()
+	require.Eventually(t, func() bool {
+		sc.mu.Lock()
+		defer sc.mu.Unlock()
+		return sc.queued == 1
+	}, time.Second, time.Millisecond)
+	cancel()
+
+	reqErr = <-done
+	require.NotNil(t, reqErr)
+	assert.Equal(t, mcp.REQUEST_INTERRUPTED, reqErr.code)
+	assert.True(t, errors.Is(reqErr, context.Canceled))
+	assert.Zero(t, sc.queued)
+
+	release()
+	assert.Zero(t, sc.running)
+}
+
+func TestMCPServer_WithScheduler(t *testing.T) {
+	started := make(chan struct{}, 10)
+	unblock := make(chan struct{})
+	s := NewMCPServer("test-server", "1.0.0", WithScheduler(SchedulerConfig{MaxConcurrent: 1}))
+	s.AddTool(mcp.NewTool("slow"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		started <- struct{}{}
+		<-unblock
+		return mcp.NewToolResultText("done"), nil
+	})
+
+	call := func(id int) mcp.JSONRPCMessage {
+		return s.HandleMessage(sessionContext(s, fmt.Sprint("session-", id)), json.RawMessage(fmt.Sprintf(
+			`{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":"slow"}}`, id)))
+	}
+	responses := make(chan mcp.JSONRPCMessage, 2)
+	go func() { responses <- call(1) }()
+	<-started
+	go func() { responses <- call(2) }()
+
+	// The second call waits for the first, while pings still get through.
+	ping := s.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":3,"method":"ping"}`))
+	assert.IsType(t, mcp.JSONRPCResponse{}, ping)
+	select {
+	case <-started:
+		t.Fatal("second call started while the first was running")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(unblock)
+	for i := 0; i < 2; i++ {
+		assert.IsType(t, mcp.JSONRPCResponse{}, <-responses)
+	}
+}
@@ -6,13 +6,18 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"maps"
+	"runtime/debug"
 	"slices"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/tracing"
+	"github.com/mark3labs/mcp-go/util"
 )
 
 // resourceEntry holds both a resource and its handler
@@ -55,6 +60,19 @@ type ToolFilterFunc func(ctx context.Context, tools []mcp.Tool) []mcp.Tool
 type ServerTool struct {
 	Tool    mcp.Tool
 	Handler ToolHandlerFunc
+	// Lifecycle optionally initializes and releases resources the handler needs.
+	Lifecycle *ToolLifecycle
+	// Source optionally names the middleware or plugin that registered the
+	// tool. It is reported by SessionRegistry for session-scoped tools.
+	Source string
+	// Aliases optionally lists other names the tool can be called by. A
+	// tool registered under the name of an alias takes precedence over it.
+	Aliases []ToolAlias
+	// Deprecation optionally marks the tool as deprecated.
+	Deprecation *ToolDeprecation
+
+	// versions is set for tools registered with AddVersionedTool.
+	versions *toolVersions
 }
 
 // ServerPrompt combines a Prompt with its handler function.
@@ -67,12 +85,18 @@ type ServerPrompt struct {
 type ServerResource struct {
 	Resource mcp.Resource
 	Handler  ResourceHandlerFunc
+	// Source optionally names the middleware or plugin that registered the
+	// resource. It is reported by SessionRegistry for session-scoped resources.
+	Source string
 }
 
 // ServerResourceTemplate combines a ResourceTemplate with its handler function.
 type ServerResourceTemplate struct {
 	Template mcp.ResourceTemplate
 	Handler  ResourceTemplateHandlerFunc
+	// Source optionally names the middleware or plugin that registered the
+	// template. It is reported by SessionRegistry for session-scoped templates.
+	Source string
 }
 
 // serverKey is the context key for storing the server instance
@@ -116,6 +140,8 @@ type requestError struct {
 	id   any
 	code int
 	err  error
+	// code and data are sent with the error, unless err wraps an *mcp.Error
+	data any
 }
 
 func (e *requestError) Error() string {
@@ -123,10 +149,17 @@ func (e *requestError) Error() string {
 }
 
 func (e *requestError) ToJSONRPCError() mcp.JSONRPCError {
+	details := mcp.NewJSONRPCErrorDetails(e.code, e.err.Error(), e.data)
+	// An *mcp.Error controls the code, message and data sent to the
+	// client, which all come from the same error.
+	var mcpErr *mcp.Error
+	if errors.As(e.err, &mcpErr) {
+		details = mcpErr.JSONRPCErrorDetails()
+	}
 	return mcp.JSONRPCError{
 		JSONRPC: mcp.JSONRPC_VERSION,
-		ID:      mcp.NewRequestId(e.id),
-		Error:   mcp.NewJSONRPCErrorDetails(e.code, e.err.Error(), nil),
+		ID:      mcp.NewRequestID(e.id),
+		Error:   details,
 	}
 }
 
@@ -134,36 +167,101 @@ func (e *requestError) Unwrap() error {
 	return e.err
 }
 
+// capabilityError returns the error for a request for a method of a
+// capability the server does not have.
+func capabilityError(id any, method mcp.MCPMethod, capability string) *requestError {
+	return &requestError{
+		id:   id,
+		code: mcp.METHOD_NOT_FOUND,
+		err:  fmt.Errorf("%s %w", capability, ErrUnsupported),
+		data: mcp.MethodNotFoundErrorData{Method: string(method), Capability: capability},
+	}
+}
+
 // NotificationHandlerFunc handles incoming notifications.
 type NotificationHandlerFunc func(ctx context.Context, notification mcp.JSONRPCNotification)
 
 // MCPServer implements a Model Context Protocol server that can handle various types of requests
 // including resources, prompts, and tools.
 type MCPServer struct {
-	// Separate mutexes for different resource types
-	resourcesMu            sync.RWMutex
+	// Separate mutexes for different resource types. resourcesMu,
+	// promptsMu and toolsMu only serialize changes to their registries,
+	// which are read without them
+	resourcesMu            sync.Mutex
 	resourceMiddlewareMu   sync.RWMutex
-	promptsMu              sync.RWMutex
-	toolsMu                sync.RWMutex
+	promptsMu              sync.Mutex
+	toolsMu                sync.Mutex
 	toolMiddlewareMu       sync.RWMutex
 	notificationHandlersMu sync.RWMutex
 	capabilitiesMu         sync.RWMutex
 	toolFiltersMu          sync.RWMutex
+	toolCallChecksMu       sync.RWMutex
+	visibleToolsMu         sync.Mutex
+	promptFiltersMu        sync.RWMutex
+	visiblePromptsMu       sync.Mutex
+	subscriptionsMu        sync.RWMutex
+	sessionRegistryMu      sync.Mutex
+	resourceListDiffsMu    sync.Mutex
+	mountsMu               sync.RWMutex
 
 	name                       string
 	version                    string
 	instructions               string
-	resources                  map[string]resourceEntry
-	resourceTemplates          map[string]resourceTemplateEntry
-	prompts                    map[string]mcp.Prompt
-	promptHandlers             map[string]PromptHandlerFunc
-	tools                      map[string]ServerTool
+	resources                  registry[resourceEntry]
+	resourceTemplates          registry[resourceTemplateEntry]
+	templateConflictHandler    ResourceTemplateConflictFunc
+	resourceListDiffHandler    ResourceListDiffFunc
+	resourceListDiffs          map[string]ResourceListDiff
+	validateResourceLinks      bool
+	resourceResolvers          []ResourceResolverFunc
+	prompts                    registry[ServerPrompt]
+	tools                      registry[ServerTool]
+	toolAliases                registry[toolAlias]
+	deprecationWarnings        bool
 	toolHandlerMiddlewares     []ToolHandlerMiddleware
 	resourceHandlerMiddlewares []ResourceHandlerMiddleware
 	toolFilters                []ToolFilterFunc
+	toolCallChecks             []ToolCallCheckFunc
+	sessionToolFilters         []SessionToolFilterFunc
+	visibleTools               map[string]listedTools
+	sessionPromptFilters       []SessionPromptFilterFunc
+	visiblePrompts             map[string][]string
 	notificationHandlers       map[string]NotificationHandlerFunc
+	subscriptions              map[string]map[string]resourceSubscription
+	sessionRegistrations       map[string]map[string]time.Time
+	sessionRegistryEncoder     SessionRegistryEncoder
 	capabilities               serverCapabilities
 	paginationLimit            *int
+	errorCodeMapper            mcp.ErrorCodeMapper
+	rateLimiter                *rateLimiter
+	scheduler                  *scheduler
+	requestTimeouts            *requestTimeouts
+	recovery                   bool
+	strictValidation           bool
+	metaPropagation            []string
+	notificationBus            NotificationBus
+	busOrigin                  string
+	busQueue                   busQueue
+	auditor                    *auditor
+	redactor                   *redactor
+	idempotency                *idempotencyCache
+	protocolVersions           []string
+	sessionProtocolVersions    sync.Map
+	autoCapabilities           bool
+	sessionCapabilities        sync.Map
+	sessionTTL                 *sessionTTL
+	tracer                     tracing.Tracer
+	tracePropagator            tracing.Propagator
+	listChanged                listChangedBatcher
+	notifications              notificationBackpressure
+	tenancy                    tenancy
+	mounts                     map[string]*mount
+	mountedIn                  []*mount
+	eagerToolInit              bool
+	logger                     util.Logger
+	jsonCodec                  util.JSONCodec
+	limits                     Limits
+	resourceCache              *resourceCache
 	sessions                   sync.Map
 	hooks                      *Hooks
 }
@@ -184,6 +282,7 @@ type serverCapabilities struct {
 	sampling    *bool
 	elicitation *bool
 	roots       *bool
+	completions *bool
 }
 
 // resourceCapabilities defines the supported resource-related features
@@ -243,11 +342,11 @@ func WithResourceRecovery() ServerOption {
 		return func(ctx context.Context, request mcp.ReadResourceRequest) (result []mcp.ResourceContents, err error) {
 			defer func() {
 				if r := recover(); r != nil {
-					err = fmt.Errorf(
-						"panic recovered in %s resource handler: %v",
-						request.Params.URI,
-						r,
-					)
+					err = &PanicError{
+						Handler: request.Params.URI + " resource",
+						Value:   r,
+						Stack:   debug.Stack(),
+					}
 				}
 			}()
 			return next(ctx, request)
@@ -266,22 +365,31 @@ func WithToolFilter(
 	}
 }
 
-// WithRecovery adds a middleware that recovers from panics in tool handlers.
+// WithRecovery recovers from panics in request handlers, answering the
+// request with an internal error whose message includes the panic value. It
+// covers every method, as well as the tool handlers called by servers this
+// one is mounted into. The OnPanic hooks are called with the method, the
+// recovered value and the stack trace; the session is available from the
+// context.
 func WithRecovery() ServerOption {
-	return WithToolHandlerMiddleware(func(next ToolHandlerFunc) ToolHandlerFunc {
+	recoverTools := WithToolHandlerMiddleware(func(next ToolHandlerFunc) ToolHandlerFunc {
 		return func(ctx context.Context, request mcp.CallToolRequest) (result *mcp.CallToolResult, err error) {
 			defer func() {
 				if r := recover(); r != nil {
-					err = fmt.Errorf(
-						"panic recovered in %s tool handler: %v",
-						request.Params.Name,
-						r,
-					)
+					err = &PanicError{
+						Handler: request.Params.Name + " tool",
+						Value:   r,
+						Stack:   debug.Stack(),
+					}
 				}
 			}()
 			return next(ctx, request)
 		}
 	})
+	return func(s *MCPServer) {
+		s.recovery = true
+		recoverTools(s)
+	}
 }
 
 // WithHooks allows adding hooks that will be called before or after
@@ -293,6 +401,17 @@ func WithHooks(hooks *Hooks) ServerOption {
 	}
 }
 
+// WithErrorCodeMapper sets how errors returned by tool, resource and prompt
+// handlers are translated into JSON-RPC error codes. The mapper is consulted
+// first; errors it does not recognize fall back to mcp.DefaultErrorCodes and
+// then to INTERNAL_ERROR. Use mcp.ErrorCodeTable.Code to build a mapper from
+// a table of domain sentinel errors.
+func WithErrorCodeMapper(mapper mcp.ErrorCodeMapper) ServerOption {
+	return func(s *MCPServer) {
+		s.errorCodeMapper = mapper
+	}
+}
+
 // WithPromptCapabilities configures prompt-related server capabilities
 func WithPromptCapabilities(listChanged bool) ServerOption {
 	return func(s *MCPServer) {
@@ -347,16 +466,17 @@ func NewMCPServer(
 	opts ...ServerOption,
 ) *MCPServer {
 	s := &MCPServer{
-		resources:                  make(map[string]resourceEntry),
-		resourceTemplates:          make(map[string]resourceTemplateEntry),
-		prompts:                    make(map[string]mcp.Prompt),
-		promptHandlers:             make(map[string]PromptHandlerFunc),
-		tools:                      make(map[string]ServerTool),
 		toolHandlerMiddlewares:     make([]ToolHandlerMiddleware, 0),
 		resourceHandlerMiddlewares: make([]ResourceHandlerMiddleware, 0),
 		name:                       name,
 		version:                    version,
 		notificationHandlers:       make(map[string]NotificationHandlerFunc),
+		subscriptions:              make(map[string]map[string]resourceSubscription),
+		sessionRegistrations:       make(map[string]map[string]time.Time),
+		visibleTools:               make(map[string]listedTools),
+		visiblePrompts:             make(map[string][]string),
+		resourceListDiffs:          make(map[string]ResourceListDiff),
+		jsonCodec:                  util.StdJSONCodec(),
 		capabilities: serverCapabilities{
 			tools:     nil,
 			resources: nil,
@@ -381,28 +501,53 @@ func (s *MCPServer) GenerateInProcessSessionID() string {
 func (s *MCPServer) AddResources(resources ...ServerResource) {
 	s.implicitlyRegisterResourceCapabilities()
 
+	differ := newResourceListDiffer("")
 	s.resourcesMu.Lock()
 	for _, entry := range resources {
-		s.resources[entry.Resource.URI] = resourceEntry{
+		previous, existed := s.resources.set(entry.Resource.URI, resourceEntry{
 			resource: entry.Resource,
 			handler:  entry.Handler,
-		}
+		})
+		differ.compare(entry.Resource.URI, previous.resource, existed, entry.Resource, true)
+		s.resourceCache.invalidate(entry.Resource.URI)
 	}
 	s.resourcesMu.Unlock()
 
 	// When the list of available resources changes, servers that declared the listChanged capability SHOULD send a notification
-	if s.capabilities.resources.listChanged {
-		// Send notification to all initialized sessions
-		s.SendNotificationToAllClients(mcp.MethodNotificationResourcesListChanged, nil)
-	}
+	s.notifyGlobalResourceListChanged(differ.result())
+
+	s.syncMounts(mountResources)
 }
 
 // SetResources replaces all existing resources with the provided list
 func (s *MCPServer) SetResources(resources ...ServerResource) {
+	s.implicitlyRegisterResourceCapabilities()
+
+	differ := newResourceListDiffer("")
 	s.resourcesMu.Lock()
-	s.resources = make(map[string]resourceEntry, len(resources))
+	entries := make(map[string]resourceEntry, len(resources))
+	for _, entry := range resources {
+		entries[entry.Resource.URI] = resourceEntry{
+			resource: entry.Resource,
+			handler:  entry.Handler,
+		}
+	}
+	previous := s.resources.reset(entries)
+	for _, entry := range resources {
+		old, existed := previous[entry.Resource.URI]
+		differ.compare(entry.Resource.URI, old.resource, existed, entry.Resource, true)
+	}
+	for uri, old := range previous {
+		if _, ok := entries[uri]; !ok {
+			differ.compare(uri, old.resource, true, mcp.Resource{}, false)
+		}
+	}
 	s.resourcesMu.Unlock()
-	s.AddResources(resources...)
+	s.resourceCache.invalidate()
+
+	s.notifyGlobalResourceListChanged(differ.result())
+
+	s.syncMounts(mountResources)
 }
 
 // AddResource registers a new resource and its handler
@@ -415,35 +560,25 @@ func (s *MCPServer) AddResource(
 
 // DeleteResources removes resources from the server
 func (s *MCPServer) DeleteResources(uris ...string) {
+	differ := newResourceListDiffer("")
 	s.resourcesMu.Lock()
-	var exists bool
 	for _, uri := range uris {
-		if _, ok := s.resources[uri]; ok {
-			delete(s.resources, uri)
-			exists = true
+		if entry, ok := s.resources.delete(uri); ok {
+			s.resourceCache.invalidate(uri)
+			differ.compare(uri, entry.resource, true, mcp.Resource{}, false)
 		}
 	}
 	s.resourcesMu.Unlock()
 
 	// Send notification to all initialized sessions if listChanged capability is enabled and we actually remove a resource
-	if exists && s.capabilities.resources != nil && s.capabilities.resources.listChanged {
-		s.SendNotificationToAllClients(mcp.MethodNotificationResourcesListChanged, nil)
-	}
+	s.notifyGlobalResourceListChanged(differ.result())
+
+	s.syncMounts(mountResources)
 }
 
 // RemoveResource removes a resource from the server
 func (s *MCPServer) RemoveResource(uri string) {
-	s.resourcesMu.Lock()
-	_, exists := s.resources[uri]
-	if exists {
-		delete(s.resources, uri)
-	}
-	s.resourcesMu.Unlock()
-
-	// Send notification to all initialized sessions if listChanged capability is enabled and we actually remove a resource
-	if exists && s.capabilities.resources != nil && s.capabilities.resources.listChanged {
-		s.SendNotificationToAllClients(mcp.MethodNotificationResourcesListChanged, nil)
-	}
+	s.DeleteResources(uri)
 }
 
 // AddResourceTemplates registers multiple resource templates at once
@@ -452,24 +587,30 @@ func (s *MCPServer) AddResourceTemplates(resourceTemplates ...ServerResourceTemp
 
 	s.resourcesMu.Lock()
 	for _, entry := range resourceTemplates {
-		s.resourceTemplates[entry.Template.URITemplate.Raw()] = resourceTemplateEntry{
+		s.resourceTemplates.set(entry.Template.URITemplate.Raw(), resourceTemplateEntry{
 			template: entry.Template,
 			handler:  entry.Handler,
-		}
+		})
 	}
 	s.resourcesMu.Unlock()
+	// Any stored URI may now be served by another template.
+	s.resourceCache.invalidate()
+
+	s.reportResourceTemplateConflicts(resourceTemplates)
 
 	// When the list of available resources changes, servers that declared the listChanged capability SHOULD send a notification
 	if s.capabilities.resources.listChanged {
 		// Send notification to all initialized sessions
-		s.SendNotificationToAllClients(mcp.MethodNotificationResourcesListChanged, nil)
+		s.notifyListChanged(mcp.MethodNotificationResourcesListChanged)
 	}
+
+	s.syncMounts(mountResources)
 }
 
 // SetResourceTemplates replaces all existing resource templates with the provided list
 func (s *MCPServer) SetResourceTemplates(templates ...ServerResourceTemplate) {
 	s.resourcesMu.Lock()
-	s.resourceTemplates = make(map[string]resourceTemplateEntry, len(templates))
+	s.resourceTemplates.reset(nil)
 	s.resourcesMu.Unlock()
 	s.AddResourceTemplates(templates...)
 }
@@ -482,22 +623,47 @@ func (s *MCPServer) AddResourceTemplate(
 	s.AddResourceTemplates(ServerResourceTemplate{Template: template, Handler: handler})
 }
 
+// DeleteResourceTemplates removes resource templates from the server
+func (s *MCPServer) DeleteResourceTemplates(uriTemplates ...string) {
+	s.resourcesMu.Lock()
+	var exists bool
+	for _, uriTemplate := range uriTemplates {
+		if _, ok := s.resourceTemplates.delete(uriTemplate); ok {
+			exists = true
+		}
+	}
+	s.resourcesMu.Unlock()
+	if exists {
+		s.resourceCache.invalidate()
+	}
+
+	// Send notification to all initialized sessions if listChanged capability is enabled and we actually remove a template
+	if exists && s.capabilities.resources != nil && s.capabilities.resources.listChanged {
+		s.notifyListChanged(mcp.MethodNotificationResourcesListChanged)
+	}
+
+	s.syncMounts(mountResources)
+}
+
 // AddPrompts registers multiple prompts at once
 func (s *MCPServer) AddPrompts(prompts ...ServerPrompt) {
+	s.checkPromptSchemas(prompts)
 	s.implicitlyRegisterPromptCapabilities()
+	s.implicitlyRegisterCompletionCapabilities(prompts)
 
 	s.promptsMu.Lock()
 	for _, entry := range prompts {
-		s.prompts[entry.Prompt.Name] = entry.Prompt
-		s.promptHandlers[entry.Prompt.Name] = entry.Handler
+		s.prompts.set(entry.Prompt.Name, entry)
 	}
 	s.promptsMu.Unlock()
 
 	// When the list of available prompts changes, servers that declared the listChanged capability SHOULD send a notification.
 	if s.capabilities.prompts.listChanged {
 		// Send notification to all initialized sessions
-		s.SendNotificationToAllClients(mcp.MethodNotificationPromptsListChanged, nil)
+		s.notifyListChanged(mcp.MethodNotificationPromptsListChanged)
 	}
+
+	s.syncMounts(mountPrompts)
 }
 
 // AddPrompt registers a new prompt handler with the given name
@@ -508,8 +674,7 @@ func (s *MCPServer) AddPrompt(prompt mcp.Prompt, handler PromptHandlerFunc) {
 // SetPrompts replaces all existing prompts with the provided list
 func (s *MCPServer) SetPrompts(prompts ...ServerPrompt) {
 	s.promptsMu.Lock()
-	s.prompts = make(map[string]mcp.Prompt, len(prompts))
-	s.promptHandlers = make(map[string]PromptHandlerFunc, len(prompts))
+	s.prompts.reset(nil)
 	s.promptsMu.Unlock()
 	s.AddPrompts(prompts...)
 }
@@ -519,9 +684,7 @@ func (s *MCPServer) DeletePrompts(names ...string) {
 	s.promptsMu.Lock()
 	var exists bool
 	for _, name := range names {
-		if _, ok := s.prompts[name]; ok {
-			delete(s.prompts, name)
-			delete(s.promptHandlers, name)
+		if _, ok := s.prompts.delete(name); ok {
 			exists = true
 		}
 	}
@@ -530,8 +693,10 @@ func (s *MCPServer) DeletePrompts(names ...string) {
 	// Send notification to all initialized sessions if listChanged capability is enabled, and we actually remove a prompt
 	if exists && s.capabilities.prompts != nil && s.capabilities.prompts.listChanged {
 		// Send notification to all initialized sessions
-		s.SendNotificationToAllClients(mcp.MethodNotificationPromptsListChanged, nil)
+		s.notifyListChanged(mcp.MethodNotificationPromptsListChanged)
 	}
+
+	s.syncMounts(mountPrompts)
 }
 
 // AddTool registers a new tool and its handler
@@ -552,14 +717,14 @@ func (s *MCPServer) implicitlyRegisterToolCapabilities() {
 func (s *MCPServer) implicitlyRegisterResourceCapabilities() {
 	s.implicitlyRegisterCapabilities(
 		func() bool { return s.capabilities.resources != nil },
-		func() { s.capabilities.resources = &resourceCapabilities{} },
+		func() { s.capabilities.resources = &resourceCapabilities{listChanged: s.autoCapabilities} },
 	)
 }
 
 func (s *MCPServer) implicitlyRegisterPromptCapabilities() {
 	s.implicitlyRegisterCapabilities(
 		func() bool { return s.capabilities.prompts != nil },
-		func() { s.capabilities.prompts = &promptCapabilities{} },
+		func() { s.capabilities.prompts = &promptCapabilities{listChanged: s.autoCapabilities} },
 	)
 }
 
@@ -580,48 +745,88 @@ func (s *MCPServer) implicitlyRegisterCapabilities(check func() bool, register f
 
 // AddTools registers multiple tools at once
 func (s *MCPServer) AddTools(tools ...ServerTool) {
+	s.UpdateTools(nil, tools...)
+}
+
+// UpdateTools removes the named tools and registers tools as a single
+// change, so sessions receive one tools list_changed notification for both.
+func (s *MCPServer) UpdateTools(deleted []string, tools ...ServerTool) {
+	s.checkToolSchemas(tools)
 	s.implicitlyRegisterToolCapabilities()
 
+	kept := make(map[*ToolLifecycle]struct{}, len(tools))
+	for _, tool := range tools {
+		kept[tool.Lifecycle] = struct{}{}
+	}
+	var replaced []ServerTool
 	s.toolsMu.Lock()
+	for _, name := range deleted {
+		if old, ok := s.tools.delete(name); ok {
+			if _, ok := kept[old.Lifecycle]; !ok {
+				replaced = append(replaced, old)
+			}
+			s.unregisterToolAliases(old)
+		}
+	}
 	for _, entry := range tools {
-		s.tools[entry.Tool.Name] = entry
+		if old, ok := s.tools.set(entry.Tool.Name, entry); ok {
+			if old.Lifecycle != entry.Lifecycle {
+				replaced = append(replaced, old)
+			}
+			s.unregisterToolAliases(old)
+		}
+		s.registerToolAliases(entry)
 	}
 	s.toolsMu.Unlock()
+	closeTools(replaced)
+	s.eagerInitTools(tools)
 
 	// When the list of available tools changes, servers that declared the listChanged capability SHOULD send a notification.
 	if s.capabilities.tools.listChanged {
 		// Send notification to all initialized sessions
-		s.SendNotificationToAllClients(mcp.MethodNotificationToolsListChanged, nil)
+		s.notifyToolsListChanged()
 	}
+
+	s.syncMounts(mountTools)
 }
 
 // SetTools replaces all existing tools with the provided list
 func (s *MCPServer) SetTools(tools ...ServerTool) {
 	s.toolsMu.Lock()
-	s.tools = make(map[string]ServerTool, len(tools))
+	old := s.tools.reset(nil)
+	s.toolAliases.reset(nil)
 	s.toolsMu.Unlock()
+
+	kept := make(map[*ToolLifecycle]struct{}, len(tools))
+	for _, tool := range tools {
+		kept[tool.Lifecycle] = struct{}{}
+	}
+	var replaced []ServerTool
+	for _, tool := range old {
+		if _, ok := kept[tool.Lifecycle]; !ok {
+			replaced = append(replaced, tool)
+		}
+	}
+	closeTools(replaced)
 	s.AddTools(tools...)
 }
 
 // GetTool retrieves the specified tool
 func (s *MCPServer) GetTool(toolName string) *ServerTool {
-	s.toolsMu.RLock()
-	defer s.toolsMu.RUnlock()
-	if tool, ok := s.tools[toolName]; ok {
+	if tool, ok := s.tools.get(toolName); ok {
 		return &tool
 	}
 	return nil
 }
 
 func (s *MCPServer) ListTools() map[string]*ServerTool {
-	s.toolsMu.RLock()
-	defer s.toolsMu.RUnlock()
-	if len(s.tools) == 0 {
+	tools := s.tools.snapshot()
+	if len(tools) == 0 {
 		return nil
 	}
 	// Create a copy to prevent external modification
-	toolsCopy := make(map[string]*ServerTool, len(s.tools))
-	for name, tool := range s.tools {
+	toolsCopy := make(map[string]*ServerTool, len(tools))
+	for name, tool := range tools {
 		toolsCopy[name] = &tool
 	}
 	return toolsCopy
@@ -631,19 +836,24 @@ func (s *MCPServer) ListTools() map[string]*ServerTool {
 func (s *MCPServer) DeleteTools(names ...string) {
 	s.toolsMu.Lock()
 	var exists bool
+	var removed []ServerTool
 	for _, name := range names {
-		if _, ok := s.tools[name]; ok {
-			delete(s.tools, name)
+		if tool, ok := s.tools.delete(name); ok {
+			removed = append(removed, tool)
+			s.unregisterToolAliases(tool)
 			exists = true
 		}
 	}
 	s.toolsMu.Unlock()
+	closeTools(removed)
 
 	// When the list of available tools changes, servers that declared the listChanged capability SHOULD send a notification.
 	if exists && s.capabilities.tools != nil && s.capabilities.tools.listChanged {
 		// Send notification to all initialized sessions
-		s.SendNotificationToAllClients(mcp.MethodNotificationToolsListChanged, nil)
+		s.notifyToolsListChanged()
 	}
+
+	s.syncMounts(mountTools)
 }
 
 // AddNotificationHandler registers a new handler for incoming notifications
@@ -658,11 +868,15 @@ func (s *MCPServer) AddNotificationHandler(
 
 func (s *MCPServer) handleInitialize(
 	ctx context.Context,
-	_ any,
+	id any,
 	request mcp.InitializeRequest,
 ) (*mcp.InitializeResult, *requestError) {
+	session := ClientSessionFromContext(ctx)
+	s.detectCapabilities(session)
 	capabilities := mcp.ServerCapabilities{}
 
+	s.capabilitiesMu.RLock()
+
 	// Only add resource capabilities if they're configured
 	if s.capabilities.resources != nil {
 		capabilities.Resources = &struct {
@@ -708,6 +922,11 @@ func (s *MCPServer) handleInitialize(
 		capabilities.Roots = &struct{}{}
 	}
 
+	if s.capabilities.completions != nil && *s.capabilities.completions {
+		capabilities.Completions = &struct{}{}
+	}
+	s.capabilitiesMu.RUnlock()
+
 	result := mcp.InitializeResult{
 		ProtocolVersion: s.protocolVersion(request.Params.ProtocolVersion),
 		ServerInfo: mcp.Implementation{
@@ -718,7 +937,13 @@ func (s *MCPServer) handleInitialize(
 		Instructions: s.instructions,
 	}
 
-	if session := ClientSessionFromContext(ctx); session != nil {
+	if err := s.runInitializeHooks(ctx, id, &InitializeHandshake{Request: &request, Result: &result, Session: session}); err != nil {
+		return nil, err
+	}
+
+	if session != nil {
+		s.recordProtocolVersion(session, result.ProtocolVersion)
+		s.recordCapabilities(session, result.Capabilities)
 		session.Initialize()
 
 		// Store client info if the session supports it
@@ -740,11 +965,7 @@ func (s *MCPServer) protocolVersion(clientVersion string) string {
 		clientVersion = "2025-03-26"
 	}
 
-	if slices.Contains(mcp.ValidProtocolVersions, clientVersion) {
-		return clientVersion
-	}
-
-	return mcp.LATEST_PROTOCOL_VERSION
+	return mcp.NegotiateProtocolVersion(clientVersion, s.supportedProtocolVersions())
 }
 
 func (s *MCPServer) handlePing(
@@ -839,24 +1060,15 @@ func (s *MCPServer) handleListResources(
 	id any,
 	request mcp.ListResourcesRequest,
 ) (*mcp.ListResourcesResult, *requestError) {
-	s.resourcesMu.RLock()
-	resourceMap := make(map[string]mcp.Resource, len(s.resources))
-	for uri, entry := range s.resources {
+	resourceMap := make(map[string]mcp.Resource, s.resources.len())
+	s.resources.each(func(uri string, entry resourceEntry) bool {
 		resourceMap[uri] = entry.resource
-	}
-	s.resourcesMu.RUnlock()
+		return true
+	})
 
-	// Check if there are session-specific resources
-	session := ClientSessionFromContext(ctx)
-	if session != nil {
-		if sessionWithResources, ok := session.(SessionWithResources); ok {
-			if sessionResources := sessionWithResources.GetSessionResources(); sessionResources != nil {
-				// Merge session-specific resources with global resources
-				for uri, serverResource := range sessionResources {
-					resourceMap[uri] = serverResource.Resource
-				}
-			}
-		}
+	// Merge tenant- and session-specific resources with global resources
+	for uri, serverResource := range s.overlayResources(ctx) {
+		resourceMap[uri] = serverResource.Resource
 	}
 
 	// Sort the resources by name
@@ -893,25 +1105,16 @@ func (s *MCPServer) handleListResourceTemplates(
 	request mcp.ListResourceTemplatesRequest,
 ) (*mcp.ListResourceTemplatesResult, *requestError) {
 	// Get global templates
-	s.resourcesMu.RLock()
-	templateMap := make(map[string]mcp.ResourceTemplate, len(s.resourceTemplates))
-	for uri, entry := range s.resourceTemplates {
+	templateMap := make(map[string]mcp.ResourceTemplate, s.resourceTemplates.len())
+	s.resourceTemplates.each(func(uri string, entry resourceTemplateEntry) bool {
 		templateMap[uri] = entry.template
-	}
-	s.resourcesMu.RUnlock()
+		return true
+	})
 
-	// Check if there are session-specific resource templates
-	session := ClientSessionFromContext(ctx)
-	if session != nil {
-		if sessionWithTemplates, ok := session.(SessionWithResourceTemplates); ok {
-			if sessionTemplates := sessionWithTemplates.GetSessionResourceTemplates(); sessionTemplates != nil {
-				// Merge session-specific templates with global templates
-				// Session templates override global ones
-				for uriTemplate, serverTemplate := range sessionTemplates {
-					templateMap[uriTemplate] = serverTemplate.Template
-				}
-			}
-		}
+	// Merge tenant- and session-specific templates with global templates,
+	// which they override
+	for uriTemplate, serverTemplate := range s.overlayResourceTemplates(ctx) {
+		templateMap[uriTemplate] = serverTemplate.Template
 	}
 
 	// Convert map to slice for sorting and pagination
@@ -950,127 +1153,106 @@ func (s *MCPServer) handleReadResource(
 	id any,
 	request mcp.ReadResourceRequest,
 ) (*mcp.ReadResourceResult, *requestError) {
-	s.resourcesMu.RLock()
+	ctx, result := s.withResourceRead(ctx)
 
-	// First check session-specific resources
+	// First check tenant- and session-specific resources
 	var handler ResourceHandlerFunc
 	var ok bool
+	var shared bool
 
-	session := ClientSessionFromContext(ctx)
-	if session != nil {
-		if sessionWithResources, typeAssertOk := session.(SessionWithResources); typeAssertOk {
-			if sessionResources := sessionWithResources.GetSessionResources(); sessionResources != nil {
-				resource, sessionOk := sessionResources[request.Params.URI]
-				if sessionOk {
-					handler = resource.Handler
-					ok = true
-				}
-			}
-		}
+	if resource, sessionOk := s.overlayResources(ctx)[request.Params.URI]; sessionOk {
+		handler = resource.Handler
+		ok = true
+	}
+	var sessionTemplates map[string]ServerResourceTemplate
+	if !ok {
+		sessionTemplates = s.overlayResourceTemplates(ctx)
 	}
 
 	// If not found in session tools, check global tools
 	if !ok {
-		globalResource, rok := s.resources[request.Params.URI]
+		globalResource, rok := s.resources.get(request.Params.URI)
 		if rok {
 			handler = globalResource.handler
 			ok = true
+			shared = true
 		}
 	}
 
 	// First try direct resource handlers
 	if ok {
-		s.resourcesMu.RUnlock()
-
-		finalHandler := handler
-		s.resourceMiddlewareMu.RLock()
-		mw := s.resourceHandlerMiddlewares
-		// Apply middlewares in reverse order
-		for i := len(mw) - 1; i >= 0; i-- {
-			finalHandler = mw[i](finalHandler)
+		contents, reqErr := s.readResource(ctx, id, request, handler, shared)
+		if reqErr != nil {
+			return nil, reqErr
 		}
-		s.resourceMiddlewareMu.RUnlock()
-
-		contents, err := finalHandler(ctx, request)
-		if err != nil {
-			return nil, &requestError{
-				id:   id,
-				code: mcp.INTERNAL_ERROR,
-				err:  err,
-			}
-		}
-		return &mcp.ReadResourceResult{Contents: contents}, nil
+		return result(contents), nil
 	}
 
-	// If no direct handler found, try matching against templates
+	// If no direct handler found, try matching against templates. The most
+	// specific matching template serves the URI, see compareTemplateSpecificity.
 	var matchedHandler ResourceTemplateHandlerFunc
-	var matched bool
+	var matchedTemplate *mcp.URITemplate
 
-	// First check session templates if available
-	if session != nil {
-		if sessionWithTemplates, ok := session.(SessionWithResourceTemplates); ok {
-			sessionTemplates := sessionWithTemplates.GetSessionResourceTemplates()
-			for _, serverTemplate := range sessionTemplates {
-				if serverTemplate.Template.URITemplate == nil {
-					continue
-				}
-				if matchesTemplate(request.Params.URI, serverTemplate.Template.URITemplate) {
-					matchedHandler = serverTemplate.Handler
-					matched = true
-					matchedVars := serverTemplate.Template.URITemplate.Match(request.Params.URI)
-					// Convert matched variables to a map
-					request.Params.Arguments = make(map[string]any, len(matchedVars))
-					for name, value := range matchedVars {
-						request.Params.Arguments[name] = value.V
-					}
-					break
-				}
-			}
+	// First check tenant and session templates if available
+	for _, serverTemplate := range sessionTemplates {
+		template := serverTemplate.Template.URITemplate
+		if template == nil {
+			continue
+		}
+		if matchesTemplate(request.Params.URI, template) && preferTemplate(template, matchedTemplate) {
+			matchedHandler = serverTemplate.Handler
+			matchedTemplate = template
 		}
 	}
 
 	// If not found in session templates, check global templates
-	if !matched {
-		for _, entry := range s.resourceTemplates {
-			template := entry.template
-			if template.URITemplate == nil {
-				continue
-			}
-			if matchesTemplate(request.Params.URI, template.URITemplate) {
+	sharedTemplate := matchedTemplate == nil
+	if matchedTemplate == nil {
+		s.resourceTemplates.each(func(_ string, entry resourceTemplateEntry) bool {
+			template := entry.template.URITemplate
+			if template != nil && matchesTemplate(request.Params.URI, template) && preferTemplate(template, matchedTemplate) {
 				matchedHandler = entry.handler
-				matched = true
-				matchedVars := template.URITemplate.Match(request.Params.URI)
-				// Convert matched variables to a map
-				request.Params.Arguments = make(map[string]any, len(matchedVars))
-				for name, value := range matchedVars {
-					request.Params.Arguments[name] = value.V
-				}
-				break
+				matchedTemplate = template
 			}
+			return true
+		})
+	}
+	matched := matchedTemplate != nil
+	if matched {
+		matchedVars := matchedTemplate.Match(request.Params.URI)
+		// Convert matched variables to a map
+		request.Params.Arguments = make(map[string]any, len(matchedVars))
+		for name, value := range matchedVars {
+			request.Params.Arguments[name] = value.V
 		}
 	}
-	s.resourcesMu.RUnlock()
 
 	if matched {
 		// If a match is found, then we have a final handler and can
 		// apply middlewares.
-		s.resourceMiddlewareMu.RLock()
-		finalHandler := ResourceHandlerFunc(matchedHandler)
-		mw := s.resourceHandlerMiddlewares
-		// Apply middlewares in reverse order
-		for i := len(mw) - 1; i >= 0; i-- {
-			finalHandler = mw[i](finalHandler)
+		contents, reqErr := s.readResource(ctx, id, request, ResourceHandlerFunc(matchedHandler), sharedTemplate)
+		if reqErr != nil {
+			return nil, reqErr
 		}
-		s.resourceMiddlewareMu.RUnlock()
-		contents, err := finalHandler(ctx, request)
-		if err != nil {
-			return nil, &requestError{
-				id:   id,
-				code: mcp.INTERNAL_ERROR,
-				err:  err,
-			}
+		return result(contents), nil
+	}
+
+	// Finally ask the resolvers to materialize the resource. Resources they
+	// do not register are not cached, as nothing invalidates them.
+	resolved, err := s.resolveResource(ctx, request.Params.URI)
+	if err != nil {
+		return nil, &requestError{
+			id:   id,
+			code: s.handlerErrorCode(err),
+			err:  err,
 		}
-		return &mcp.ReadResourceResult{Contents: contents}, nil
+	}
+	if resolved != nil {
+		contents, reqErr := s.readResource(ctx, id, request, resolved.Handler, false)
+		if reqErr != nil {
+			return nil, reqErr
+		}
+		return result(contents), nil
 	}
 
 	return nil, &requestError{
@@ -1081,7 +1263,22 @@ func (s *MCPServer) handleReadResource(
 			request.Params.URI,
 			ErrResourceNotFound,
 		),
+		data: mcp.NotFoundErrorData{Type: "resource", URI: request.Params.URI},
+	}
+}
+
+// handlerErrorCode picks the JSON-RPC error code reported for an error
+// returned by a tool, resource or prompt handler. The code of an
+// *mcp.Error takes precedence over the configured mapper.
+func (s *MCPServer) handlerErrorCode(err error) int {
+	var mcpErr *mcp.Error
+	if errors.As(err, &mcpErr) {
+		return mcpErr.Code
 	}
+	if code, ok := mcp.ChainErrorCodeMappers(s.errorCodeMapper, mcp.CodeForError)(err); ok {
+		return code
+	}
+	return mcp.INTERNAL_ERROR
 }
 
 // matchesTemplate checks if a URI matches a URI template pattern
@@ -1094,17 +1291,11 @@ func (s *MCPServer) handleListPrompts(
 	id any,
 	request mcp.ListPromptsRequest,
 ) (*mcp.ListPromptsResult, *requestError) {
-	s.promptsMu.RLock()
-	prompts := make([]mcp.Prompt, 0, len(s.prompts))
-	for _, prompt := range s.prompts {
-		prompts = append(prompts, prompt)
+	prompts := s.listPrompts(ctx)
+	if session := ClientSessionFromContext(ctx); session != nil {
+		s.recordVisiblePrompts(session.SessionID(), prompts)
 	}
-	s.promptsMu.RUnlock()
 
-	// sort prompts by name
-	sort.Slice(prompts, func(i, j int) bool {
-		return prompts[i].Name < prompts[j].Name
-	})
 	promptsToReturn, nextCursor, err := listByPagination(
 		ctx,
 		s,
@@ -1132,28 +1323,36 @@ func (s *MCPServer) handleGetPrompt(
 	id any,
 	request mcp.GetPromptRequest,
 ) (*mcp.GetPromptResult, *requestError) {
-	s.promptsMu.RLock()
-	handler, ok := s.promptHandlers[request.Params.Name]
-	s.promptsMu.RUnlock()
-
+	prompt, ok := s.overlayPrompts(ctx)[request.Params.Name]
+	handler := prompt.Handler
 	if !ok {
+		prompt, ok = s.prompts.get(request.Params.Name)
+		handler = prompt.Handler
+	}
+
+	if !ok || !s.promptVisible(ctx, prompt.Prompt) {
 		return nil, &requestError{
 			id:   id,
 			code: mcp.INVALID_PARAMS,
 			err:  fmt.Errorf("prompt '%s' not found: %w", request.Params.Name, ErrPromptNotFound),
+			data: mcp.NotFoundErrorData{Type: "prompt", Name: request.Params.Name},
 		}
 	}
 
+	if rerr := validatePromptArguments(id, prompt.Prompt, request.Params.Arguments); rerr != nil {
+		return nil, rerr
+	}
+
 	result, err := handler(ctx, request)
 	if err != nil {
 		return nil, &requestError{
 			id:   id,
-			code: mcp.INTERNAL_ERROR,
+			code: s.handlerErrorCode(err),
 			err:  err,
 		}
 	}
 
-	return result, nil
+	return s.adaptPromptResult(ctx, result), nil
 }
 
 func (s *MCPServer) handleListTools(
@@ -1161,56 +1360,84 @@ func (s *MCPServer) handleListTools(
 	id any,
 	request mcp.ListToolsRequest,
 ) (*mcp.ListToolsResult, *requestError) {
-	// Get the base tools from the server
-	s.toolsMu.RLock()
-	tools := make([]mcp.Tool, 0, len(s.tools))
+	tools := s.listTools(ctx)
+	if session := ClientSessionFromContext(ctx); session != nil {
+		s.recordVisibleTools(ctx, session.SessionID(), tools)
+	}
+
+	// Apply pagination
+	toolsToReturn, nextCursor, err := listByPagination(
+		ctx,
+		s,
+		request.Params.Cursor,
+		tools,
+	)
+	if err != nil {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INVALID_PARAMS,
+			err:  err,
+		}
+	}
+
+	result := mcp.ListToolsResult{
+		Tools: s.adaptTools(ctx, toolsToReturn),
+		PaginatedResult: mcp.PaginatedResult{
+			NextCursor: nextCursor,
+		},
+	}
+	return &result, nil
+}
 
+// listTools returns the tools visible in ctx: the server's tools merged
+// with the session's own tools, after all tool filters have been applied.
+func (s *MCPServer) listTools(ctx context.Context) []mcp.Tool {
+	// Get the base tools from the server
 	// Get all tool names for consistent ordering
-	toolNames := make([]string, 0, len(s.tools))
-	for name := range s.tools {
+	toolNames := make([]string, 0, s.tools.len())
+	s.tools.each(func(name string, _ ServerTool) bool {
 		toolNames = append(toolNames, name)
-	}
+		return true
+	})
 
 	// Sort the tool names for consistent ordering
 	sort.Strings(toolNames)
 
-	// Add tools in sorted order
+	// Add tools in sorted order, skipping tools deleted since their names
+	// were collected
+	tools := make([]mcp.Tool, 0, len(toolNames))
 	for _, name := range toolNames {
-		tools = append(tools, s.tools[name].Tool)
+		if tool, ok := s.tools.get(name); ok {
+			tools = append(tools, withToolAliasMeta(listedTool(tool), tool))
+		}
 	}
-	s.toolsMu.RUnlock()
 
-	// Check if there are session-specific tools
-	session := ClientSessionFromContext(ctx)
-	if session != nil {
-		if sessionWithTools, ok := session.(SessionWithTools); ok {
-			if sessionTools := sessionWithTools.GetSessionTools(); sessionTools != nil {
-				// Override or add session-specific tools
-				// We need to create a map first to merge the tools properly
-				toolMap := make(map[string]mcp.Tool)
-
-				// Add global tools first
-				for _, tool := range tools {
-					toolMap[tool.Name] = tool
-				}
+	// Check if there are tenant- or session-specific tools
+	if sessionTools := s.overlayTools(ctx); sessionTools != nil {
+		// Override or add session-specific tools
+		// We need to create a map first to merge the tools properly
+		toolMap := make(map[string]mcp.Tool)
 
-				// Then override with session-specific tools
-				for name, serverTool := range sessionTools {
-					toolMap[name] = serverTool.Tool
-				}
+		// Add global tools first
+		for _, tool := range tools {
+			toolMap[tool.Name] = tool
+		}
 
-				// Convert back to slice
-				tools = make([]mcp.Tool, 0, len(toolMap))
-				for _, tool := range toolMap {
-					tools = append(tools, tool)
-				}
+		// Then override with session-specific tools
+		for name, serverTool := range sessionTools {
+			toolMap[name] = withToolAliasMeta(listedTool(serverTool), serverTool)
+		}
 
-				// Sort again to maintain consistent ordering
-				sort.Slice(tools, func(i, j int) bool {
-					return tools[i].Name < tools[j].Name
-				})
-			}
+		// Convert back to slice
+		tools = make([]mcp.Tool, 0, len(toolMap))
+		for _, tool := range toolMap {
+			tools = append(tools, tool)
 		}
+
+		// Sort again to maintain consistent ordering
+		sort.Slice(tools, func(i, j int) bool {
+			return tools[i].Name < tools[j].Name
+		})
 	}
 
 	// Apply tool filters if any are defined
@@ -1222,28 +1449,7 @@ func (s *MCPServer) handleListTools(
 	}
 	s.toolFiltersMu.RUnlock()
 
-	// Apply pagination
-	toolsToReturn, nextCursor, err := listByPagination(
-		ctx,
-		s,
-		request.Params.Cursor,
-		tools,
-	)
-	if err != nil {
-		return nil, &requestError{
-			id:   id,
-			code: mcp.INVALID_PARAMS,
-			err:  err,
-		}
-	}
-
-	result := mcp.ListToolsResult{
-		Tools: toolsToReturn,
-		PaginatedResult: mcp.PaginatedResult{
-			NextCursor: nextCursor,
-		},
-	}
-	return &result, nil
+	return s.applySessionToolFilters(ctx, tools)
 }
 
 func (s *MCPServer) handleToolCall(
@@ -1251,35 +1457,50 @@ func (s *MCPServer) handleToolCall(
 	id any,
 	request mcp.CallToolRequest,
 ) (*mcp.CallToolResult, *requestError) {
-	// First check session-specific tools
-	var tool ServerTool
-	var ok bool
-
-	session := ClientSessionFromContext(ctx)
-	if session != nil {
-		if sessionWithTools, typeAssertOk := session.(SessionWithTools); typeAssertOk {
-			if sessionTools := sessionWithTools.GetSessionTools(); sessionTools != nil {
-				var sessionOk bool
-				tool, sessionOk = sessionTools[request.Params.Name]
-				if sessionOk {
-					ok = true
-				}
-			}
-		}
-	}
-
-	// If not found in session tools, check global tools
+	tool, alias, ok := s.findTool(ctx, request.Params.Name)
 	if !ok {
-		s.toolsMu.RLock()
-		tool, ok = s.tools[request.Params.Name]
-		s.toolsMu.RUnlock()
+		tool, request, ok = s.findVersionedTool(ctx, request)
 	}
-
 	if !ok {
 		return nil, &requestError{
 			id:   id,
 			code: mcp.INVALID_PARAMS,
 			err:  fmt.Errorf("tool '%s' not found: %w", request.Params.Name, ErrToolNotFound),
+			data: mcp.NotFoundErrorData{Type: "tool", Name: request.Params.Name},
+		}
+	}
+
+	var deprecated *DeprecatedToolCall
+	if deprecation := toolDeprecation(tool, alias); deprecation != nil {
+		deprecated = &DeprecatedToolCall{Name: request.Params.Name, Tool: tool.Tool.Name, Deprecation: *deprecation}
+		s.hooks.onDeprecatedToolCall(ctx, id, *deprecated)
+	}
+	// Handlers, checks and limits see the canonical name of aliased tools.
+	request.Params.Name = tool.Tool.Name
+
+	if err := s.runToolCallChecks(ctx, request); err != nil {
+		return nil, &requestError{
+			id:   id,
+			code: s.handlerErrorCode(err),
+			err:  err,
+		}
+	}
+
+	if err := s.rateLimiter.allow(ctx, request.Params.Name); err != nil {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.RATE_LIMITED,
+			err:  err,
+		}
+	}
+
+	if tool.Lifecycle != nil {
+		if err := tool.Lifecycle.ensureInit(ctx); err != nil {
+			return nil, &requestError{
+				id:   id,
+				code: mcp.INTERNAL_ERROR,
+				err:  fmt.Errorf("tool '%s' init failed: %w: %w", request.Params.Name, ErrToolUnavailable, err),
+			}
 		}
 	}
 
@@ -1294,8 +1515,20 @@ func (s *MCPServer) handleToolCall(
 	}
 	s.toolMiddlewareMu.RUnlock()
 
-	result, err := finalHandler(ctx, request)
+	result, err := s.idempotency.call(ctx, request, finalHandler)
 	if err != nil {
+		code := s.handlerErrorCode(err)
+		if errors.Is(err, ErrIdempotencyKeyReused) {
+			code = mcp.INVALID_PARAMS
+		}
+		return nil, &requestError{
+			id:   id,
+			code: code,
+			err:  err,
+		}
+	}
+
+	if err := s.checkResourceLinks(ctx, result); err != nil {
 		return nil, &requestError{
 			id:   id,
 			code: mcp.INTERNAL_ERROR,
@@ -1303,9 +1536,52 @@ func (s *MCPServer) handleToolCall(
 		}
 	}
 
+	result = s.adaptCallToolResult(ctx, result)
+	if deprecated != nil && s.deprecationWarnings {
+		result = withDeprecationWarning(result, *deprecated)
+	}
 	return result, nil
 }
 
+// findTool looks up a tool by name or alias, preferring session-specific
+// tools over global ones. Tools hidden from the session by a
+// SessionToolFilterFunc are not found. The alias the tool was found by, if
+// any, is returned as well.
+func (s *MCPServer) findTool(ctx context.Context, name string) (ServerTool, *ToolAlias, bool) {
+	tool, alias, ok := s.resolveTool(ctx, name)
+	if !ok || !s.toolVisible(ctx, tool.Tool) {
+		return ServerTool{}, nil, false
+	}
+	return tool, alias, true
+}
+
+func (s *MCPServer) lookupTool(ctx context.Context, name string) (ServerTool, bool) {
+	tool, _, ok := s.resolveTool(ctx, name)
+	return tool, ok
+}
+
+// resolveTool looks up a tool by name in the tenant's and session's tools
+// and then in the server's, then by alias in the same order.
+func (s *MCPServer) resolveTool(ctx context.Context, name string) (ServerTool, *ToolAlias, bool) {
+	sessionTools := s.overlayTools(ctx)
+	if tool, ok := sessionTools[name]; ok {
+		return tool, nil, true
+	}
+
+	if tool, ok := s.tools.get(name); ok {
+		return tool, nil, true
+	}
+	if tool, alias, ok := resolveToolAlias(sessionTools, name); ok {
+		return tool, alias, true
+	}
+	if alias, ok := s.toolAliases.get(name); ok {
+		if tool, ok := s.tools.get(alias.canonical); ok {
+			return tool, &ToolAlias{Name: name, Deprecation: alias.deprecation}, true
+		}
+	}
+	return ServerTool{}, nil, false
+}
+
 func (s *MCPServer) handleNotification(
 	ctx context.Context,
 	notification mcp.JSONRPCNotification,
@@ -1321,7 +1597,7 @@ func (s *MCPServer) handleNotification(
 }
 
 func createResponse(id any, result any) mcp.JSONRPCMessage {
-	return mcp.NewJSONRPCResultResponse(mcp.NewRequestId(id), result)
+	return mcp.NewJSONRPCResultResponse(mcp.NewRequestID(id), result)
 }
 
 func createErrorResponse(
@@ -1331,7 +1607,21 @@ func createErrorResponse(
 ) mcp.JSONRPCMessage {
 	return mcp.JSONRPCError{
 		JSONRPC: mcp.JSONRPC_VERSION,
-		ID:      mcp.NewRequestId(id),
+		ID:      mcp.NewRequestID(id),
 		Error:   mcp.NewJSONRPCErrorDetails(code, message, nil),
 	}
 }
+
+// unknownMethodResponse returns the response to a request for a method the
+// server does not support.
+func unknownMethodResponse(id any, method mcp.MCPMethod) mcp.JSONRPCMessage {
+	return mcp.JSONRPCError{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestID(id),
+		Error: mcp.NewJSONRPCErrorDetails(
+			mcp.METHOD_NOT_FOUND,
+			fmt.Sprintf("Method %s not found", method),
+			mcp.MethodNotFoundErrorData{Method: string(method)},
+		),
+	}
+}
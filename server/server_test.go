@@ -344,6 +344,33 @@ func TestMCPServer_Tools(t *testing.T) {
 				assert.Equal(t, "test-tool-2", tools[1].Name)
 			},
 		},
+		{
+			name: "UpdateTools sends single notifications/tools/list_changed",
+			action: func(t *testing.T, server *MCPServer, notificationChannel chan mcp.JSONRPCNotification) {
+				err := server.RegisterSession(context.TODO(), &fakeSession{
+					sessionID:           "test",
+					notificationChannel: notificationChannel,
+					initialized:         true,
+				})
+				require.NoError(t, err)
+				server.SetTools(
+					ServerTool{Tool: mcp.NewTool("test-tool-1")},
+					ServerTool{Tool: mcp.NewTool("test-tool-2")})
+				server.UpdateTools([]string{"test-tool-1"}, ServerTool{Tool: mcp.NewTool("test-tool-3")})
+			},
+			expectedNotifications: 2,
+			validate: func(t *testing.T, notifications []mcp.JSONRPCNotification, toolsList mcp.JSONRPCMessage) {
+				// One for SetTools
+				assert.Equal(t, mcp.MethodNotificationToolsListChanged, notifications[0].Method)
+				// One for UpdateTools
+				assert.Equal(t, mcp.MethodNotificationToolsListChanged, notifications[1].Method)
+
+				tools := toolsList.(mcp.JSONRPCResponse).Result.(mcp.ListToolsResult).Tools
+				assert.Len(t, tools, 2)
+				assert.Equal(t, "test-tool-2", tools[0].Name)
+				assert.Equal(t, "test-tool-3", tools[1].Name)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -504,6 +531,44 @@ func TestMCPServer_HandlePagination(t *testing.T) {
 	}
 }
 
+func TestMCPServer_RequestIDs(t *testing.T) {
+	tests := []struct {
+		name   string
+		id     string
+		hookID any
+	}{
+		{name: "string", id: `"req-1"`, hookID: "req-1"},
+		{name: "integer", id: `3`, hookID: int64(3)},
+		{name: "integer beyond float64 precision", id: `9007199254740993`, hookID: int64(9007199254740993)},
+		{name: "integer beyond int64", id: `18446744073709551616`, hookID: json.Number("18446744073709551616")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hooks := &Hooks{}
+			var hookID any
+			hooks.AddBeforeAny(func(ctx context.Context, id any, method mcp.MCPMethod, message any) {
+				hookID = id
+			})
+			server := NewMCPServer("test-server", "1.0.0", WithHooks(hooks))
+
+			for _, message := range []string{
+				`{"jsonrpc":"2.0","id":` + tt.id + `,"method":"ping"}`,
+				`{"jsonrpc":"2.0","id":` + tt.id + `,"method":"unknown"}`,
+			} {
+				response := server.HandleMessage(context.Background(), []byte(message))
+				data, err := json.Marshal(response)
+				require.NoError(t, err)
+				var echoed struct {
+					ID json.RawMessage `json:"id"`
+				}
+				require.NoError(t, json.Unmarshal(data, &echoed))
+				assert.Equal(t, tt.id, string(echoed.ID), "the response carries the ID exactly as it was sent")
+			}
+			assert.Equal(t, tt.hookID, hookID)
+		})
+	}
+}
+
 func TestMCPServer_HandleNotifications(t *testing.T) {
 	server := createTestServer()
 	notificationReceived := false
@@ -1297,6 +1362,21 @@ func TestMCPServer_HandleInvalidMessages(t *testing.T) {
 			message:     `{"id": 1, "method": "initialize"}`,
 			expectedErr: mcp.INVALID_REQUEST,
 		},
+		{
+			name:        "Null ID",
+			message:     `{"jsonrpc": "2.0", "id": null, "method": "ping"}`,
+			expectedErr: mcp.INVALID_REQUEST,
+		},
+		{
+			name:        "Null ID with a notification method",
+			message:     `{"jsonrpc": "2.0", "id": null, "method": "notifications/initialized"}`,
+			expectedErr: mcp.INVALID_REQUEST,
+		},
+		{
+			name:        "Invalid ID",
+			message:     `{"jsonrpc": "2.0", "id": {"a": 1}, "method": "ping"}`,
+			expectedErr: mcp.PARSE_ERROR,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1550,6 +1630,83 @@ func TestMCPServer_HandleMethodsWithoutCapabilities(t *testing.T) {
 	}
 }
 
+func TestMCPServer_ErrorData(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []ServerOption
+		message  string
+		code     int
+		data     any
+		sentinel error
+	}{
+		{
+			name:     "unknown method",
+			message:  `{"jsonrpc":"2.0","id":1,"method":"nonexistent"}`,
+			code:     mcp.METHOD_NOT_FOUND,
+			data:     mcp.MethodNotFoundErrorData{Method: "nonexistent"},
+			sentinel: mcp.ErrUnknownMethod,
+		},
+		{
+			name:     "tools not negotiated",
+			message:  `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`,
+			code:     mcp.METHOD_NOT_FOUND,
+			data:     mcp.MethodNotFoundErrorData{Method: "tools/list", Capability: "tools"},
+			sentinel: mcp.ErrCapabilityNotNegotiated,
+		},
+		{
+			name:     "subscriptions not negotiated",
+			options:  []ServerOption{WithResourceCapabilities(false, false)},
+			message:  `{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"file:///a"}}`,
+			code:     mcp.METHOD_NOT_FOUND,
+			data:     mcp.MethodNotFoundErrorData{Method: "resources/subscribe", Capability: "resources.subscribe"},
+			sentinel: mcp.ErrCapabilityNotNegotiated,
+		},
+		{
+			name:     "tool not found",
+			options:  []ServerOption{WithToolCapabilities(false)},
+			message:  `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"missing"}}`,
+			code:     mcp.INVALID_PARAMS,
+			data:     mcp.NotFoundErrorData{Type: "tool", Name: "missing"},
+			sentinel: mcp.ErrToolNotFound,
+		},
+		{
+			name:     "prompt not found",
+			options:  []ServerOption{WithPromptCapabilities(false)},
+			message:  `{"jsonrpc":"2.0","id":1,"method":"prompts/get","params":{"name":"missing"}}`,
+			code:     mcp.INVALID_PARAMS,
+			data:     mcp.NotFoundErrorData{Type: "prompt", Name: "missing"},
+			sentinel: mcp.ErrPromptNotFound,
+		},
+		{
+			name:     "resource not found",
+			options:  []ServerOption{WithResourceCapabilities(false, false)},
+			message:  `{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"file:///missing"}}`,
+			code:     mcp.RESOURCE_NOT_FOUND,
+			data:     mcp.NotFoundErrorData{Type: "resource", URI: "file:///missing"},
+			sentinel: mcp.ErrResourceNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMCPServer("test-server", "1.0.0", tt.options...)
+			response := server.HandleMessage(context.Background(), []byte(tt.message))
+
+			errorResponse, ok := response.(mcp.JSONRPCError)
+			require.True(t, ok, "response %#v", response)
+			assert.Equal(t, tt.code, errorResponse.Error.Code)
+			assert.Equal(t, tt.data, errorResponse.Error.Data)
+
+			// Clients decode the error from the wire before matching it
+			raw, err := json.Marshal(errorResponse)
+			require.NoError(t, err)
+			var received mcp.JSONRPCError
+			require.NoError(t, json.Unmarshal(raw, &received))
+			assert.ErrorIs(t, received.Error.ToError(), tt.sentinel)
+		})
+	}
+}
+
 func TestMCPServer_Instructions(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -1729,7 +1886,7 @@ func TestMCPServer_ResourceTemplates(t *testing.T) {
 	)
 
 	t.Run("Check bulk add resource templates", func(t *testing.T) {
-		assert.Equal(t, 3, len(server.resourceTemplates))
+		assert.Equal(t, 3, server.resourceTemplates.len())
 	})
 
 	t.Run("Get resource template again", func(t *testing.T) {
@@ -2866,3 +3023,92 @@ func TestMCPServer_ListTools(t *testing.T) {
 		assert.Contains(t, tools3, "test-tool")
 	})
 }
+
+func TestMCPServer_WithErrorCodeMapper(t *testing.T) {
+	errQuotaExceeded := errors.New("quota exceeded")
+
+	tests := []struct {
+		name         string
+		opts         []ServerOption
+		handlerErr   error
+		expectedCode int
+	}{
+		{
+			name:         "unmapped error defaults to internal error",
+			handlerErr:   errors.New("boom"),
+			expectedCode: mcp.INTERNAL_ERROR,
+		},
+		{
+			name:         "mcp sentinel maps to its code",
+			handlerErr:   fmt.Errorf("bad input: %w", mcp.ErrInvalidParams),
+			expectedCode: mcp.INVALID_PARAMS,
+		},
+		{
+			name: "custom mapper translates domain errors",
+			opts: []ServerOption{WithErrorCodeMapper(mcp.ErrorCodeTable{
+				{Err: errQuotaExceeded, Code: -31000},
+			}.Code)},
+			handlerErr:   fmt.Errorf("user 42: %w", errQuotaExceeded),
+			expectedCode: -31000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMCPServer("test-server", "1.0.0", tt.opts...)
+			server.AddTool(mcp.NewTool("fail"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return nil, tt.handlerErr
+			})
+			server.AddPrompt(mcp.NewPrompt("fail"), func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+				return nil, tt.handlerErr
+			})
+			server.AddResource(mcp.NewResource("test://fail", "fail"), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+				return nil, tt.handlerErr
+			})
+
+			for _, message := range []string{
+				`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"fail"}}`,
+				`{"jsonrpc":"2.0","id":1,"method":"prompts/get","params":{"name":"fail"}}`,
+				`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"test://fail"}}`,
+			} {
+				response := server.HandleMessage(context.Background(), []byte(message))
+				errorResponse, ok := response.(mcp.JSONRPCError)
+				require.True(t, ok, "expected error response, got %#v", response)
+				assert.Equal(t, tt.expectedCode, errorResponse.Error.Code, message)
+			}
+		})
+	}
+}
+
+func TestMCPServer_StructuredErrors(t *testing.T) {
+	details := map[string]any{"field": "city"}
+	server := NewMCPServer("test-server", "1.0.0",
+		// The code of an *mcp.Error wins over the mapper.
+		WithErrorCodeMapper(func(err error) (int, bool) { return mcp.INTERNAL_ERROR, true }),
+	)
+	server.AddTool(mcp.NewTool("fail"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, fmt.Errorf("validating: %w", mcp.NewError(mcp.INVALID_PARAMS, "missing city", details))
+	})
+
+	response := server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"fail"}}`))
+	errorResponse, ok := response.(mcp.JSONRPCError)
+	require.True(t, ok, "expected error response, got %#v", response)
+	assert.Equal(t, mcp.INVALID_PARAMS, errorResponse.Error.Code)
+	assert.Equal(t, "missing city", errorResponse.Error.Message)
+	assert.Equal(t, details, errorResponse.Error.Data)
+}
+
+func TestRequestError_WrappedStructuredError(t *testing.T) {
+	// The code, message and data all come from the *mcp.Error, even when
+	// the request error was given another code.
+	err := &requestError{
+		id:   1,
+		code: mcp.INTERNAL_ERROR,
+		err:  fmt.Errorf("calling tool: %w", mcp.NewError(mcp.RATE_LIMITED, "slow down", mcp.RateLimitErrorData{Scope: "tool"})),
+		data: mcp.NotFoundErrorData{Type: "tool", Name: "search"},
+	}
+	details := err.ToJSONRPCError().Error
+	assert.Equal(t, mcp.RATE_LIMITED, details.Code)
+	assert.Equal(t, "slow down", details.Message)
+	assert.Equal(t, mcp.RateLimitErrorData{Scope: "tool"}, details.Data)
+}
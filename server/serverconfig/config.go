@@ -0,0 +1,337 @@
+// Package serverconfig builds a server.MCPServer and its transport from a
+// JSON or YAML configuration file, for deployments that would rather edit a
+// file than a chain of functional options:
+//
+//	name: inventory
+//	version: 1.4.0
+//	capabilities:
+//	  tools: {listChanged: true}
+//	  resources: {subscribe: true}
+//	  logging: true
+//	timeouts:
+//	  request: 30s
+//	  tools:
+//	    export: 5m
+//	rateLimits:
+//	  perSession: {rate: 5, burst: 10}
+//	auth:
+//	  tokens: ["${INVENTORY_TOKEN}"]
+//	transport:
+//	  type: streamable-http
+//	  address: ":8080"
+//
+// Load reads a file, applies overrides from environment variables and
+// validates the result. Every scalar and list setting can be overridden by
+// a variable named after its path with the MCP prefix, such as
+// MCP_TRANSPORT_ADDRESS or MCP_TIMEOUTS_REQUEST; lists are comma-separated.
+// References to environment variables such as ${INVENTORY_TOKEN} in the
+// file itself are expanded before it is decoded.
+//
+// The server is then created and served with:
+//
+//	cfg, err := serverconfig.Load("server.yaml")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	s := cfg.NewServer()
+//	// register tools, resources and prompts
+//	log.Fatal(cfg.Serve(ctx, s))
+package serverconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/mark3labs/mcp-go/server/internal/duration"
+)
+
+// Transport types.
+const (
+	TransportStdio          = "stdio"
+	TransportSSE            = "sse"
+	TransportStreamableHTTP = "streamable-http"
+)
+
+// Config is the content of a configuration file.
+type Config struct {
+	Name         string       `json:"name" yaml:"name"`
+	Version      string       `json:"version" yaml:"version"`
+	Instructions string       `json:"instructions,omitempty" yaml:"instructions,omitempty"`
+	Capabilities Capabilities `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`
+	Timeouts     Timeouts     `json:"timeouts,omitempty" yaml:"timeouts,omitempty"`
+	RateLimits   *RateLimits  `json:"rateLimits,omitempty" yaml:"rateLimits,omitempty"`
+	Auth         *Auth        `json:"auth,omitempty" yaml:"auth,omitempty"`
+	Transport    Transport    `json:"transport,omitempty" yaml:"transport,omitempty"`
+	// PaginationLimit is the page size of list results; zero disables
+	// pagination.
+	PaginationLimit int `json:"paginationLimit,omitempty" yaml:"paginationLimit,omitempty"`
+}
+
+// Capabilities lists the capabilities the server advertises. Nil
+// capabilities are not advertised.
+type Capabilities struct {
+	Tools       *ListChanged        `json:"tools,omitempty" yaml:"tools,omitempty"`
+	Resources   *ResourceCapability `json:"resources,omitempty" yaml:"resources,omitempty"`
+	Prompts     *ListChanged        `json:"prompts,omitempty" yaml:"prompts,omitempty"`
+	Logging     bool                `json:"logging,omitempty" yaml:"logging,omitempty"`
+	Elicitation bool                `json:"elicitation,omitempty" yaml:"elicitation,omitempty"`
+	Roots       bool                `json:"roots,omitempty" yaml:"roots,omitempty"`
+}
+
+// ListChanged configures a capability whose list changes can be notified.
+type ListChanged struct {
+	ListChanged bool `json:"listChanged,omitempty" yaml:"listChanged,omitempty"`
+}
+
+// ResourceCapability configures the resources capability.
+type ResourceCapability struct {
+	Subscribe   bool `json:"subscribe,omitempty" yaml:"subscribe,omitempty"`
+	ListChanged bool `json:"listChanged,omitempty" yaml:"listChanged,omitempty"`
+}
+
+// Timeouts bounds the handling of requests. Zero durations are not
+// enforced.
+type Timeouts struct {
+	// Request bounds the handling of every request.
+	Request Duration `json:"request,omitempty" yaml:"request,omitempty"`
+	// Methods bounds the handling of requests of the named methods.
+	Methods map[string]Duration `json:"methods,omitempty" yaml:"methods,omitempty"`
+	// Tools bounds the calls of the named tools.
+	Tools map[string]Duration `json:"tools,omitempty" yaml:"tools,omitempty"`
+	// SessionTTL unregisters sessions idle for longer.
+	SessionTTL Duration `json:"sessionTTL,omitempty" yaml:"sessionTTL,omitempty"`
+}
+
+// RateLimits limits the rate of tool calls, as server.RateLimitConfig.
+type RateLimits struct {
+	Global     *RateLimit           `json:"global,omitempty" yaml:"global,omitempty"`
+	PerSession *RateLimit           `json:"perSession,omitempty" yaml:"perSession,omitempty"`
+	PerTool    map[string]RateLimit `json:"perTool,omitempty" yaml:"perTool,omitempty"`
+}
+
+// RateLimit is a token bucket of Burst calls refilled at Rate calls per
+// second.
+type RateLimit struct {
+	Rate  float64 `json:"rate" yaml:"rate"`
+	Burst int     `json:"burst" yaml:"burst"`
+}
+
+// Auth requires requests to the streamable HTTP transport to carry one of
+// the listed bearer tokens.
+type Auth struct {
+	Tokens []string `json:"tokens" yaml:"tokens"`
+	// ResourceMetadataURL is the protected resource metadata URL advertised
+	// to rejected clients.
+	ResourceMetadataURL string `json:"resourceMetadataURL,omitempty" yaml:"resourceMetadataURL,omitempty"`
+}
+
+// Transport configures how the server is served.
+type Transport struct {
+	// Type is one of TransportStdio, the default, TransportSSE and
+	// TransportStreamableHTTP.
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+	// Address is the listen address of the HTTP transports.
+	Address string `json:"address,omitempty" yaml:"address,omitempty"`
+	// EndpointPath is the path of the streamable HTTP endpoint.
+	EndpointPath string `json:"endpointPath,omitempty" yaml:"endpointPath,omitempty"`
+	// BaseURL is the public URL of the SSE server.
+	BaseURL string `json:"baseURL,omitempty" yaml:"baseURL,omitempty"`
+	// Stateless disables sessions of the streamable HTTP transport.
+	Stateless bool `json:"stateless,omitempty" yaml:"stateless,omitempty"`
+	// HeartbeatInterval is how often the streamable HTTP transport pings
+	// idle streams.
+	HeartbeatInterval Duration `json:"heartbeatInterval,omitempty" yaml:"heartbeatInterval,omitempty"`
+	TLS               *TLS     `json:"tls,omitempty" yaml:"tls,omitempty"`
+}
+
+// TLS serves the streamable HTTP transport over HTTPS.
+type TLS struct {
+	CertFile string `json:"certFile" yaml:"certFile"`
+	KeyFile  string `json:"keyFile" yaml:"keyFile"`
+}
+
+// Duration is a time.Duration read from a string such as "5s".
+type Duration = duration.Duration
+
+// Option configures Load.
+type Option func(*loader)
+
+type loader struct {
+	prefix    string
+	lookupEnv func(string) (string, bool)
+}
+
+// WithEnvPrefix sets the prefix of the variables overriding settings. It
+// defaults to MCP.
+func WithEnvPrefix(prefix string) Option {
+	return func(l *loader) {
+		l.prefix = prefix
+	}
+}
+
+// WithLookupEnv sets the function reading environment variables, both for
+// overrides and for references in the file. It defaults to os.LookupEnv.
+func WithLookupEnv(lookup func(string) (string, bool)) Option {
+	return func(l *loader) {
+		l.lookupEnv = lookup
+	}
+}
+
+// Load reads the configuration file at path, expands the environment
+// variables it references, applies the overrides of environment variables
+// and validates the result. With an empty path the configuration is read
+// from the environment only.
+func Load(path string, opts ...Option) (*Config, error) {
+	l := &loader{prefix: "MCP", lookupEnv: os.LookupEnv}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	var cfg Config
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		data = []byte(os.Expand(string(data), func(name string) string {
+			value, _ := l.lookupEnv(name)
+			return value
+		}))
+		if err := decode(data, &cfg); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.ApplyEnv(l.prefix, l.lookupEnv); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Parse decodes a configuration in JSON or YAML and validates it, without
+// consulting the environment.
+func Parse(data []byte) (*Config, error) {
+	var cfg Config
+	if err := decode(data, &cfg); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func decode(data []byte, cfg *Config) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(cfg); err != nil {
+			return fmt.Errorf("failed to decode config: %w", err)
+		}
+		return nil
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to decode config: %w", err)
+	}
+	return nil
+}
+
+// Validate checks that the server is named and versioned, that the
+// transport is known and has what it needs, and that limits and durations
+// are usable.
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Name == "" {
+		errs = append(errs, errors.New("name is required"))
+	}
+	if c.Version == "" {
+		errs = append(errs, errors.New("version is required"))
+	}
+	if c.PaginationLimit < 0 {
+		errs = append(errs, errors.New("paginationLimit must not be negative"))
+	}
+
+	checkDuration := func(name string, d Duration) {
+		if d < 0 {
+			errs = append(errs, fmt.Errorf("%s must not be negative", name))
+		}
+	}
+	checkDuration("timeouts.request", c.Timeouts.Request)
+	checkDuration("timeouts.sessionTTL", c.Timeouts.SessionTTL)
+	for method, d := range c.Timeouts.Methods {
+		checkDuration(fmt.Sprintf("timeout of method %q", method), d)
+	}
+	for tool, d := range c.Timeouts.Tools {
+		checkDuration(fmt.Sprintf("timeout of tool %q", tool), d)
+	}
+	checkDuration("transport.heartbeatInterval", c.Transport.HeartbeatInterval)
+
+	if limits := c.RateLimits; limits != nil {
+		checkLimit := func(name string, limit RateLimit) {
+			if limit.Rate <= 0 || limit.Burst < 1 {
+				errs = append(errs, fmt.Errorf("%s needs a positive rate and burst", name))
+			}
+		}
+		if limits.Global != nil {
+			checkLimit("global rate limit", *limits.Global)
+		}
+		if limits.PerSession != nil {
+			checkLimit("per-session rate limit", *limits.PerSession)
+		}
+		for tool, limit := range limits.PerTool {
+			checkLimit(fmt.Sprintf("rate limit of tool %q", tool), limit)
+		}
+	}
+
+	transport := c.Transport
+	switch transport.Type {
+	case "", TransportStdio:
+		if transport.Address != "" {
+			errs = append(errs, errors.New("the stdio transport has no address"))
+		}
+	case TransportSSE, TransportStreamableHTTP:
+		if transport.Address == "" {
+			errs = append(errs, fmt.Errorf("the %s transport needs an address", transport.Type))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unknown transport type %q", transport.Type))
+	}
+	if transport.BaseURL != "" {
+		if u, err := url.Parse(transport.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			errs = append(errs, fmt.Errorf("invalid transport base URL %q", transport.BaseURL))
+		}
+	}
+	if transport.TLS != nil {
+		if transport.Type != TransportStreamableHTTP {
+			errs = append(errs, fmt.Errorf("TLS is only supported by the %s transport", TransportStreamableHTTP))
+		}
+		if transport.TLS.CertFile == "" || transport.TLS.KeyFile == "" {
+			errs = append(errs, errors.New("TLS needs both a certificate and a key file"))
+		}
+	}
+	if c.Auth != nil {
+		if transport.Type != TransportStreamableHTTP {
+			errs = append(errs, fmt.Errorf("auth is only supported by the %s transport", TransportStreamableHTTP))
+		}
+		if len(c.Auth.Tokens) == 0 {
+			errs = append(errs, errors.New("auth needs at least one token"))
+		}
+		for i, token := range c.Auth.Tokens {
+			if token == "" {
+				errs = append(errs, fmt.Errorf("auth token %d is empty", i))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
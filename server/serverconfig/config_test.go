@@ -0,0 +1,289 @@
+package serverconfig
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const initializeRequest = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","clientInfo":{"name":"test","version":"1.0.0"}}}`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{
+			name: "yaml",
+			config: `
+name: test
+version: 1.0.0
+capabilities:
+  tools: {listChanged: true}
+timeouts:
+  request: 30s
+  tools: {slow: 5m}
+rateLimits:
+  perSession: {rate: 5, burst: 10}
+transport:
+  type: streamable-http
+  address: ":8080"
+`,
+		},
+		{
+			name:   "json",
+			config: `{"name": "test", "version": "1.0.0", "transport": {"type": "sse", "address": ":8080"}}`,
+		},
+		{
+			name:   "missing name and version",
+			config: `{}`,
+			err:    "name is required\nversion is required",
+		},
+		{
+			name:   "unknown transport",
+			config: "name: test\nversion: 1.0.0\ntransport: {type: websocket}\n",
+			err:    `unknown transport type "websocket"`,
+		},
+		{
+			name:   "http transport without address",
+			config: "name: test\nversion: 1.0.0\ntransport: {type: streamable-http}\n",
+			err:    "the streamable-http transport needs an address",
+		},
+		{
+			name:   "auth over stdio",
+			config: "name: test\nversion: 1.0.0\nauth: {tokens: [secret]}\n",
+			err:    "auth is only supported by the streamable-http transport",
+		},
+		{
+			name:   "incomplete TLS",
+			config: "name: test\nversion: 1.0.0\ntransport: {type: streamable-http, address: ':443', tls: {certFile: cert.pem}}\n",
+			err:    "TLS needs both a certificate and a key file",
+		},
+		{
+			name:   "invalid rate limit",
+			config: "name: test\nversion: 1.0.0\nrateLimits: {perTool: {search: {rate: 1}}}\n",
+			err:    `rate limit of tool "search" needs a positive rate and burst`,
+		},
+		{
+			name:   "negative timeout",
+			config: "name: test\nversion: 1.0.0\ntimeouts: {request: -1s}\n",
+			err:    "timeouts.request must not be negative",
+		},
+		{
+			name:   "invalid duration",
+			config: "name: test\nversion: 1.0.0\ntimeouts: {request: soon}\n",
+			err:    "invalid duration",
+		},
+		{
+			name:   "unknown field",
+			config: `{"name": "test", "version": "1.0.0", "timeout": "5s"}`,
+			err:    "unknown field",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.config))
+			if tt.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+name: test
+version: 1.0.0
+auth:
+  tokens: ["${API_TOKEN}"]
+transport:
+  type: streamable-http
+  address: ":8080"
+`), 0o600))
+	env := map[string]string{
+		"API_TOKEN":                           "s3cret",
+		"APP_VERSION":                         "2.0.0",
+		"APP_TRANSPORT_ADDRESS":               ":9090",
+		"APP_TRANSPORT_STATELESS":             "true",
+		"APP_TIMEOUTS_REQUEST":                "10s",
+		"APP_RATE_LIMITS_PER_SESSION_RATE":    "2.5",
+		"APP_RATE_LIMITS_PER_SESSION_BURST":   "5",
+		"APP_AUTH_RESOURCE_METADATA_URL":      "https://example.com/.well-known/oauth-protected-resource",
+		"APP_CAPABILITIES_TOOLS_LIST_CHANGED": "true",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	cfg, err := Load(path, WithEnvPrefix("APP"), WithLookupEnv(lookup))
+	require.NoError(t, err)
+	assert.Equal(t, "test", cfg.Name)
+	assert.Equal(t, "2.0.0", cfg.Version)
+	assert.Equal(t, ":9090", cfg.Transport.Address)
+	assert.True(t, cfg.Transport.Stateless)
+	assert.Equal(t, Duration(10*time.Second), cfg.Timeouts.Request)
+	assert.Equal(t, &RateLimit{Rate: 2.5, Burst: 5}, cfg.RateLimits.PerSession)
+	assert.Nil(t, cfg.RateLimits.Global, "sections without overrides stay unset")
+	assert.Equal(t, []string{"s3cret"}, cfg.Auth.Tokens)
+	assert.Equal(t, env["APP_AUTH_RESOURCE_METADATA_URL"], cfg.Auth.ResourceMetadataURL)
+	assert.Equal(t, &ListChanged{ListChanged: true}, cfg.Capabilities.Tools)
+	assert.Nil(t, cfg.Capabilities.Prompts)
+
+	t.Run("environment only", func(t *testing.T) {
+		env := map[string]string{"MCP_NAME": "test", "MCP_VERSION": "1.0.0", "MCP_AUTH_TOKENS": "a, b"}
+		_, err := Load("", WithLookupEnv(func(name string) (string, bool) {
+			value, ok := env[name]
+			return value, ok
+		}))
+		require.Error(t, err, "auth requires the streamable HTTP transport")
+
+		env["MCP_TRANSPORT_TYPE"] = TransportStreamableHTTP
+		env["MCP_TRANSPORT_ADDRESS"] = ":8080"
+		cfg, err := Load("", WithLookupEnv(func(name string) (string, bool) {
+			value, ok := env[name]
+			return value, ok
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, cfg.Auth.Tokens)
+	})
+
+	t.Run("invalid override", func(t *testing.T) {
+		_, err := Load(path, WithLookupEnv(func(name string) (string, bool) {
+			if name == "MCP_TRANSPORT_STATELESS" {
+				return "maybe", true
+			}
+			return "", false
+		}))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid value of MCP_TRANSPORT_STATELESS")
+	})
+}
+
+func TestEnvName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"name":                "NAME",
+		"perSession":          "PER_SESSION",
+		"resourceMetadataURL": "RESOURCE_METADATA_URL",
+		"baseURL":             "BASE_URL",
+		"sessionTTL":          "SESSION_TTL",
+		"tls":                 "TLS",
+	} {
+		assert.Equal(t, expected, envName(name))
+	}
+}
+
+func TestConfig_NewServer(t *testing.T) {
+	cfg, err := Parse([]byte(`
+name: inventory
+version: 1.4.0
+instructions: Manage the inventory
+capabilities:
+  tools: {listChanged: true}
+  resources: {subscribe: true}
+  logging: true
+rateLimits:
+  global: {rate: 1, burst: 1}
+`))
+	require.NoError(t, err)
+	s := cfg.NewServer()
+	s.AddTool(mcp.NewTool("count"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("42"), nil
+	})
+
+	response := s.HandleMessage(context.Background(), []byte(initializeRequest))
+	result := response.(mcp.JSONRPCResponse).Result.(mcp.InitializeResult)
+	assert.Equal(t, "inventory", result.ServerInfo.Name)
+	assert.Equal(t, "1.4.0", result.ServerInfo.Version)
+	assert.Equal(t, "Manage the inventory", result.Instructions)
+	require.NotNil(t, result.Capabilities.Tools)
+	assert.True(t, result.Capabilities.Tools.ListChanged)
+	require.NotNil(t, result.Capabilities.Resources)
+	assert.True(t, result.Capabilities.Resources.Subscribe)
+	assert.NotNil(t, result.Capabilities.Logging)
+	assert.Nil(t, result.Capabilities.Prompts)
+
+	call := []byte(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"count"}}`)
+	assert.IsType(t, mcp.JSONRPCResponse{}, s.HandleMessage(context.Background(), call))
+	assert.IsType(t, mcp.JSONRPCError{}, s.HandleMessage(context.Background(), call), "the global rate limit applies")
+}
+
+func TestConfig_StreamableHTTPAuth(t *testing.T) {
+	cfg, err := Parse([]byte(`
+name: test
+version: 1.0.0
+auth:
+  tokens: [s3cret]
+transport:
+  type: streamable-http
+  address: ":8080"
+`))
+	require.NoError(t, err)
+	ts := server.NewTestStreamableHTTPServer(cfg.NewServer(), cfg.StreamableHTTPOptions()...)
+	defer ts.Close()
+
+	post := func(token string) int {
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/mcp", strings.NewReader(initializeRequest))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+	assert.Equal(t, http.StatusUnauthorized, post(""))
+	assert.Equal(t, http.StatusUnauthorized, post("wrong"))
+	assert.Equal(t, http.StatusOK, post("s3cret"))
+}
+
+func TestConfig_Serve(t *testing.T) {
+	listener := httptest.NewServer(http.NotFoundHandler())
+	address := listener.Listener.Addr().String()
+	listener.Close()
+
+	cfg, err := Parse([]byte(`{"name": "test", "version": "1.0.0", "transport": {"type": "streamable-http", "address": "` + address + `"}}`))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- cfg.Serve(ctx, cfg.NewServer())
+	}()
+
+	require.Eventually(t, func() bool {
+		resp, err := http.Post("http://"+address+"/mcp", "application/json", strings.NewReader(initializeRequest))
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, 2*time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve did not return after the context was cancelled")
+	}
+}
@@ -0,0 +1,142 @@
+package serverconfig
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var durationType = reflect.TypeFor[Duration]()
+
+// ApplyEnv overrides the settings of c with the environment variables named
+// after their path, upper-cased and joined with underscores after prefix:
+// transport.address is overridden by PREFIX_TRANSPORT_ADDRESS and
+// rateLimits.perSession.rate by PREFIX_RATE_LIMITS_PER_SESSION_RATE. Lists
+// are read as comma-separated values. Settings keyed by tool or method name
+// cannot be overridden. Variables are read with lookup, or os.LookupEnv if
+// it is nil.
+func (c *Config) ApplyEnv(prefix string, lookup func(string) (string, bool)) error {
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+	_, err := applyEnv(reflect.ValueOf(c).Elem(), prefix, lookup)
+	return err
+}
+
+// applyEnv sets the fields of the struct v from the variables under prefix
+// and reports whether any was set.
+func applyEnv(v reflect.Value, prefix string, lookup func(string) (string, bool)) (bool, error) {
+	var errs []error
+	set := false
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		tag, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		name := envName(tag)
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+
+		value := v.Field(i)
+		switch {
+		case value.Kind() == reflect.Struct && value.Type() != durationType:
+			fieldSet, err := applyEnv(value, name, lookup)
+			set = set || fieldSet
+			errs = append(errs, err)
+		case value.Kind() == reflect.Pointer && value.Type().Elem().Kind() == reflect.Struct:
+			// Nil sections are only allocated if one of their settings
+			// is overridden.
+			target := reflect.New(value.Type().Elem())
+			if !value.IsNil() {
+				target.Elem().Set(value.Elem())
+			}
+			fieldSet, err := applyEnv(target.Elem(), name, lookup)
+			if fieldSet {
+				value.Set(target)
+				set = true
+			}
+			errs = append(errs, err)
+		default:
+			env, ok := lookup(name)
+			if !ok {
+				continue
+			}
+			if err := setValue(value, env); err != nil {
+				errs = append(errs, fmt.Errorf("invalid value of %s: %w", name, err))
+				continue
+			}
+			set = true
+		}
+	}
+	return set, errors.Join(errs...)
+}
+
+// setValue sets the scalar or string list v from s.
+func setValue(v reflect.Value, s string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(Duration(d)))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported setting of type %s", v.Type())
+		}
+		var items []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported setting of type %s", v.Type())
+	}
+	return nil
+}
+
+// envName converts a camel case setting name to upper snake case, keeping
+// acronyms together: resourceMetadataURL becomes RESOURCE_METADATA_URL.
+func envName(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
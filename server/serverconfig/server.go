@@ -0,0 +1,183 @@
+package serverconfig
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// shutdownTimeout bounds the graceful shutdown of the HTTP transports once
+// the context of Serve is done.
+const shutdownTimeout = 5 * time.Second
+
+// ServerOptions returns the options configuring a server as c describes.
+func (c *Config) ServerOptions() []server.ServerOption {
+	var opts []server.ServerOption
+	if c.Instructions != "" {
+		opts = append(opts, server.WithInstructions(c.Instructions))
+	}
+
+	capabilities := c.Capabilities
+	if capabilities.Tools != nil {
+		opts = append(opts, server.WithToolCapabilities(capabilities.Tools.ListChanged))
+	}
+	if capabilities.Resources != nil {
+		opts = append(opts, server.WithResourceCapabilities(capabilities.Resources.Subscribe, capabilities.Resources.ListChanged))
+	}
+	if capabilities.Prompts != nil {
+		opts = append(opts, server.WithPromptCapabilities(capabilities.Prompts.ListChanged))
+	}
+	if capabilities.Logging {
+		opts = append(opts, server.WithLogging())
+	}
+	if capabilities.Elicitation {
+		opts = append(opts, server.WithElicitation())
+	}
+	if capabilities.Roots {
+		opts = append(opts, server.WithRoots())
+	}
+
+	timeouts := c.Timeouts
+	if timeouts.Request > 0 {
+		opts = append(opts, server.WithRequestTimeout(time.Duration(timeouts.Request)))
+	}
+	for method, timeout := range timeouts.Methods {
+		opts = append(opts, server.WithMethodTimeout(mcp.MCPMethod(method), time.Duration(timeout)))
+	}
+	for tool, timeout := range timeouts.Tools {
+		opts = append(opts, server.WithToolTimeout(tool, time.Duration(timeout)))
+	}
+	if timeouts.SessionTTL > 0 {
+		opts = append(opts, server.WithSessionTTL(time.Duration(timeouts.SessionTTL)))
+	}
+
+	if limits := c.RateLimits; limits != nil {
+		config := server.RateLimitConfig{
+			Global:     rateLimit(limits.Global),
+			PerSession: rateLimit(limits.PerSession),
+		}
+		if len(limits.PerTool) > 0 {
+			config.PerTool = make(map[string]server.RateLimit, len(limits.PerTool))
+			for tool, limit := range limits.PerTool {
+				config.PerTool[tool] = server.RateLimit{Rate: limit.Rate, Burst: limit.Burst}
+			}
+		}
+		opts = append(opts, server.WithRateLimit(config))
+	}
+	if c.PaginationLimit > 0 {
+		opts = append(opts, server.WithPaginationLimit(c.PaginationLimit))
+	}
+	return opts
+}
+
+func rateLimit(limit *RateLimit) *server.RateLimit {
+	if limit == nil {
+		return nil
+	}
+	return &server.RateLimit{Rate: limit.Rate, Burst: limit.Burst}
+}
+
+// NewServer creates the server c describes. The options in opts, such as
+// hooks, are applied after those of the configuration.
+func (c *Config) NewServer(opts ...server.ServerOption) *server.MCPServer {
+	return server.NewMCPServer(c.Name, c.Version, append(c.ServerOptions(), opts...)...)
+}
+
+// StreamableHTTPOptions returns the options of the streamable HTTP
+// transport as c describes, including its authentication.
+func (c *Config) StreamableHTTPOptions() []server.StreamableHTTPOption {
+	transport := c.Transport
+	var opts []server.StreamableHTTPOption
+	if transport.EndpointPath != "" {
+		opts = append(opts, server.WithEndpointPath(transport.EndpointPath))
+	}
+	if transport.Stateless {
+		opts = append(opts, server.WithStateLess(true))
+	}
+	if transport.HeartbeatInterval > 0 {
+		opts = append(opts, server.WithHeartbeatInterval(time.Duration(transport.HeartbeatInterval)))
+	}
+	if transport.TLS != nil {
+		opts = append(opts, server.WithTLSCert(transport.TLS.CertFile, transport.TLS.KeyFile))
+	}
+	if c.Auth != nil {
+		opts = append(opts, server.WithAuthFunc(c.Auth.authFunc()))
+		if c.Auth.ResourceMetadataURL != "" {
+			opts = append(opts, server.WithAuthResourceMetadataURL(c.Auth.ResourceMetadataURL))
+		}
+	}
+	return opts
+}
+
+// SSEOptions returns the options of the SSE transport as c describes.
+func (c *Config) SSEOptions() []server.SSEOption {
+	var opts []server.SSEOption
+	if c.Transport.BaseURL != "" {
+		opts = append(opts, server.WithBaseURL(c.Transport.BaseURL))
+	}
+	return opts
+}
+
+// authFunc accepts requests carrying one of the configured tokens.
+func (a *Auth) authFunc() server.AuthFunc {
+	return func(r *http.Request) (server.AuthInfo, error) {
+		token, ok := server.BearerToken(r)
+		if !ok {
+			return server.AuthInfo{}, server.ErrUnauthorized
+		}
+		for _, candidate := range a.Tokens {
+			if subtle.ConstantTimeCompare([]byte(token), []byte(candidate)) == 1 {
+				return server.AuthInfo{Token: token}, nil
+			}
+		}
+		return server.AuthInfo{}, server.ErrUnauthorized
+	}
+}
+
+// Serve serves s over the configured transport until ctx is done, then
+// shuts the transport down. It returns nil after a shutdown caused by ctx.
+func (c *Config) Serve(ctx context.Context, s *server.MCPServer) error {
+	var start func() error
+	var shutdown func(context.Context) error
+	switch c.Transport.Type {
+	case TransportSSE:
+		sseServer := server.NewSSEServer(s, c.SSEOptions()...)
+		start = func() error { return sseServer.Start(c.Transport.Address) }
+		shutdown = sseServer.Shutdown
+	case TransportStreamableHTTP:
+		httpServer := server.NewStreamableHTTPServer(s, c.StreamableHTTPOptions()...)
+		start = func() error { return httpServer.Start(c.Transport.Address) }
+		shutdown = httpServer.Shutdown
+	default:
+		err := server.NewStdioServer(s).Listen(ctx, os.Stdin, os.Stdout)
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- start()
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/invopop/jsonschema"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+var (
+	contextType        = reflect.TypeFor[context.Context]()
+	errorType          = reflect.TypeFor[error]()
+	callToolResultType = reflect.TypeFor[*mcp.CallToolResult]()
+)
+
+// RegisterService registers a tool for every exported method of svc of the
+// form
+//
+//	func(ctx context.Context, args Args) (Result, error)
+//
+// where Args is a struct or a pointer to one, much like net/rpc registers the
+// methods of a receiver. Methods of any other form are ignored.
+//
+// Tools are named after their method in snake case, GetWeather becoming
+// get_weather, and their input schema is generated from Args, honoring its
+// json and jsonschema tags. A blank field of Args can carry tags overriding
+// the defaults: its mcp tag sets the tool name, or skips the method if it is
+// "-", and its description tag sets the tool description:
+//
+//	type WeatherArgs struct {
+//		_    struct{} `mcp:"weather" description:"Get the weather of a city"`
+//		City string   `json:"city" jsonschema:"required"`
+//	}
+//
+// A Result of type *mcp.CallToolResult is returned as is and a string as
+// text content. Any other Result is returned as structured content, and
+// declared as the output schema if it is a struct. Errors returned by a
+// method are reported as tool errors.
+//
+// RegisterService registers nothing and returns an error if svc has no
+// suitable method or two methods map to the same tool name.
+func (s *MCPServer) RegisterService(svc any) error {
+	tools, err := serviceTools(svc)
+	if err != nil {
+		return err
+	}
+	s.AddTools(tools...)
+	return nil
+}
+
+// serviceTools builds the tools RegisterService registers for svc.
+func serviceTools(svc any) ([]ServerTool, error) {
+	value := reflect.ValueOf(svc)
+	if !value.IsValid() {
+		return nil, fmt.Errorf("service is nil")
+	}
+	typ := value.Type()
+
+	var tools []ServerTool
+	names := make(map[string]string)
+	for i := 0; i < typ.NumMethod(); i++ {
+		method := typ.Method(i)
+		argsType, resultType, ok := serviceMethodTypes(method.Type)
+		if !ok {
+			continue
+		}
+		structType := derefType(argsType)
+
+		name, description := serviceToolName(method.Name), ""
+		if field, ok := structType.FieldByName("_"); ok {
+			if tag, ok := field.Tag.Lookup("mcp"); ok && tag != "" {
+				name = tag
+			}
+			description = field.Tag.Get("description")
+		}
+		if name == "-" {
+			continue
+		}
+		if other, ok := names[name]; ok {
+			return nil, fmt.Errorf("methods %s and %s of %s are both registered as tool %q", other, method.Name, typ, name)
+		}
+		names[name] = method.Name
+
+		inputSchema, err := reflectSchema(structType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate input schema of %s.%s: %w", typ, method.Name, err)
+		}
+		tool := mcp.NewTool(name, mcp.WithDescription(description), mcp.WithRawInputSchema(inputSchema))
+		if resultStruct := derefType(resultType); resultStruct.Kind() == reflect.Struct && resultType != callToolResultType {
+			outputSchema, err := reflectSchema(resultStruct)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate output schema of %s.%s: %w", typ, method.Name, err)
+			}
+			tool.RawOutputSchema = outputSchema
+		}
+
+		tools = append(tools, ServerTool{
+			Tool:    tool,
+			Handler: serviceHandler(value.Method(i), argsType),
+		})
+	}
+	if len(tools) == 0 {
+		return nil, fmt.Errorf("type %s has no exported methods of suitable type", typ)
+	}
+	return tools, nil
+}
+
+// serviceMethodTypes returns the argument and result types of a method type
+// suitable for RegisterService, which excludes the receiver.
+func serviceMethodTypes(method reflect.Type) (args, result reflect.Type, ok bool) {
+	if method.NumIn() != 3 || method.NumOut() != 2 {
+		return nil, nil, false
+	}
+	if method.In(1) != contextType || method.Out(1) != errorType {
+		return nil, nil, false
+	}
+	args = method.In(2)
+	if derefType(args).Kind() != reflect.Struct {
+		return nil, nil, false
+	}
+	return args, method.Out(0), true
+}
+
+// serviceHandler calls method with the tool call arguments bound to a new
+// value of argsType.
+func serviceHandler(method reflect.Value, argsType reflect.Type) ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := reflect.New(derefType(argsType))
+		if err := request.BindArguments(args.Interface()); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to bind arguments: %v", err)), nil
+		}
+		if argsType.Kind() != reflect.Pointer {
+			args = args.Elem()
+		}
+
+		out := method.Call([]reflect.Value{reflect.ValueOf(ctx), args})
+		if err, _ := out[1].Interface().(error); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("tool execution failed: %v", err)), nil
+		}
+		switch result := out[0].Interface().(type) {
+		case *mcp.CallToolResult:
+			return result, nil
+		case string:
+			return mcp.NewToolResultText(result), nil
+		default:
+			return mcp.NewToolResultStructuredOnly(result), nil
+		}
+	}
+}
+
+// serviceToolName converts a method name to snake case, keeping acronyms
+// together: GetHTTPStatus becomes get_http_status.
+func serviceToolName(method string) string {
+	runes := []rune(method)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			startsWord := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])))
+			if startsWord {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		return t.Elem()
+	}
+	return t
+}
+
+// reflectSchema generates the JSON schema of t the way mcp.WithInputSchema
+// does.
+func reflectSchema(t reflect.Type) (json.RawMessage, error) {
+	reflector := jsonschema.Reflector{
+		DoNotReference:            true,
+		Anonymous:                 true,
+		AllowAdditionalProperties: true,
+	}
+	schema := reflector.ReflectFromType(t)
+	schema.Version = ""
+	return json.Marshal(schema)
+}
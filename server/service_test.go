@@ -0,0 +1,149 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type weatherService struct {
+	calls int
+}
+
+type weatherArgs struct {
+	_    struct{} `mcp:"weather" description:"Get the weather of a city"`
+	City string   `json:"city" jsonschema:"required"`
+}
+
+type forecastArgs struct {
+	City string `json:"city"`
+}
+
+type forecast struct {
+	City        string  `json:"city"`
+	Temperature float64 `json:"temperature"`
+}
+
+func (w *weatherService) GetWeather(ctx context.Context, args weatherArgs) (string, error) {
+	w.calls++
+	return "sunny in " + args.City, nil
+}
+
+func (w *weatherService) GetHTTPForecast(ctx context.Context, args *forecastArgs) (forecast, error) {
+	if args.City == "" {
+		return forecast{}, errors.New("city is required")
+	}
+	return forecast{City: args.City, Temperature: 21.5}, nil
+}
+
+func (w *weatherService) Echo(ctx context.Context, args struct {
+	Text string `json:"text"`
+}) (*mcp.CallToolResult, error) {
+	return mcp.NewToolResultText(args.Text), nil
+}
+
+func (w *weatherService) Internal(ctx context.Context, args struct {
+	_ struct{} `mcp:"-"`
+}) (string, error) {
+	return "", nil
+}
+
+// Methods of any other form are not registered.
+func (w *weatherService) Calls() int                                 { return w.calls }
+func (w *weatherService) NoContext(args weatherArgs) (string, error) { return "", nil }
+func (w *weatherService) NotStruct(ctx context.Context, city string) (string, error) {
+	return "", nil
+}
+
+func TestMCPServer_RegisterService(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+	svc := &weatherService{}
+	require.NoError(t, server.RegisterService(svc))
+
+	tools := server.ListTools()
+	names := make([]string, 0, len(tools))
+	for name := range tools {
+		names = append(names, name)
+	}
+	assert.ElementsMatch(t, []string{"weather", "get_http_forecast", "echo"}, names)
+
+	weather := tools["weather"].Tool
+	assert.Equal(t, "Get the weather of a city", weather.Description)
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(weather.RawInputSchema, &schema))
+	assert.Equal(t, []any{"city"}, schema["required"])
+	assert.Contains(t, schema["properties"], "city")
+	assert.NotContains(t, schema["properties"], "_")
+	assert.Nil(t, weather.RawOutputSchema, "text results have no output schema")
+	assert.NotNil(t, tools["get_http_forecast"].Tool.RawOutputSchema)
+	assert.Nil(t, tools["echo"].Tool.RawOutputSchema)
+
+	tests := []struct {
+		name       string
+		tool       string
+		arguments  map[string]any
+		text       string
+		structured any
+		isError    bool
+	}{
+		{name: "string result", tool: "weather", arguments: map[string]any{"city": "Paris"}, text: "sunny in Paris"},
+		{name: "structured result", tool: "get_http_forecast", arguments: map[string]any{"city": "Oslo"}, text: `{"city":"Oslo","temperature":21.5}`, structured: forecast{City: "Oslo", Temperature: 21.5}},
+		{name: "error", tool: "get_http_forecast", text: "tool execution failed: city is required", isError: true},
+		{name: "call tool result", tool: "echo", arguments: map[string]any{"text": "hi"}, text: "hi"},
+		{name: "invalid arguments", tool: "weather", arguments: map[string]any{"city": 1}, isError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := mcp.CallToolRequest{}
+			request.Params.Name = tt.tool
+			request.Params.Arguments = tt.arguments
+			result, err := tools[tt.tool].Handler(context.Background(), request)
+			require.NoError(t, err)
+			assert.Equal(t, tt.isError, result.IsError)
+			if tt.text != "" {
+				require.Len(t, result.Content, 1)
+				assert.Equal(t, tt.text, result.Content[0].(mcp.TextContent).Text)
+			}
+			assert.Equal(t, tt.structured, result.StructuredContent)
+		})
+	}
+	assert.Equal(t, 1, svc.Calls())
+}
+
+func TestMCPServer_RegisterService_Errors(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+	assert.Error(t, server.RegisterService(nil))
+	assert.Error(t, server.RegisterService(struct{}{}), "services need a suitable method")
+	assert.Error(t, server.RegisterService(&conflictingService{}), "tool names must be unique")
+	assert.Empty(t, server.ListTools())
+}
+
+type conflictingService struct{}
+
+func (conflictingService) Weather(ctx context.Context, args weatherArgs) (string, error) {
+	return "", nil
+}
+
+func (conflictingService) GetWeather(ctx context.Context, args weatherArgs) (string, error) {
+	return "", nil
+}
+
+func TestServiceToolName(t *testing.T) {
+	tests := map[string]string{
+		"GetWeather":    "get_weather",
+		"Echo":          "echo",
+		"GetHTTPStatus": "get_http_status",
+		"HTTPGet":       "http_get",
+		"ListV2Items":   "list_v2_items",
+		"ID":            "id",
+	}
+	for method, expected := range tests {
+		assert.Equal(t, expected, serviceToolName(method), method)
+	}
+}
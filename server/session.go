@@ -3,6 +3,8 @@ package server
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"maps"
 	"net/url"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -20,6 +22,16 @@ type ClientSession interface {
 	SessionID() string
 }
 
+// SessionWithClose is an extension of ClientSession for transports that
+// keep a connection open for the session. UnregisterSession calls Close, so
+// that the connection of a session the server ends, such as one that
+// expired, ends too. Close must be safe to call more than once.
+type SessionWithClose interface {
+	ClientSession
+	// Close ends the connection of the session.
+	Close()
+}
+
 // SessionWithLogging is an extension of ClientSession that can receive log message notifications and set log level
 type SessionWithLogging interface {
 	ClientSession
@@ -62,6 +74,16 @@ type SessionWithResourceTemplates interface {
 	SetSessionResourceTemplates(templates map[string]ServerResourceTemplate)
 }
 
+// SessionWithPrompts is an extension of ClientSession that can store session-specific prompt data.
+// Its methods must be safe for concurrent use.
+type SessionWithPrompts interface {
+	ClientSession
+	// GetSessionPrompts returns the prompts specific to this session, if any
+	GetSessionPrompts() map[string]ServerPrompt
+	// SetSessionPrompts sets prompts specific to this session
+	SetSessionPrompts(prompts map[string]ServerPrompt)
+}
+
 // SessionWithClientInfo is an extension of ClientSession that can store client info
 type SessionWithClientInfo interface {
 	ClientSession
@@ -75,6 +97,24 @@ type SessionWithClientInfo interface {
 	SetClientCapabilities(clientCapabilities mcp.ClientCapabilities)
 }
 
+// SessionWithHandshake is an extension of SessionWithClientInfo that
+// exposes what the client reported and negotiated at initialize, so
+// handlers can adapt to the client, for example by skipping flows that
+// need sampling when the client lacks the sampling capability
+type SessionWithHandshake interface {
+	SessionWithClientInfo
+	// ClientInfo returns the name and version the client reported
+	ClientInfo() mcp.Implementation
+	// ClientCapabilities returns the capabilities the client reported
+	ClientCapabilities() mcp.ClientCapabilities
+	// NegotiatedProtocolVersion returns the protocol version negotiated at
+	// initialize, or "" before initialize
+	NegotiatedProtocolVersion() string
+	// SetNegotiatedProtocolVersion sets the protocol version negotiated at
+	// initialize
+	SetNegotiatedProtocolVersion(version string)
+}
+
 // SessionWithElicitation is an extension of ClientSession that can send elicitation requests
 type SessionWithElicitation interface {
 	ClientSession
@@ -89,6 +129,30 @@ type SessionWithRoots interface {
 	ListRoots(ctx context.Context, request mcp.ListRootsRequest) (*mcp.ListRootsResult, error)
 }
 
+// SessionWithPing is an extension of ClientSession that can send ping
+// requests to the client, used to keep idle sessions alive under
+// WithSessionTTL
+type SessionWithPing interface {
+	ClientSession
+	// Ping sends a ping request to the client. The response of the client is
+	// handled by HandleMessage.
+	Ping(ctx context.Context) error
+}
+
+// SessionWithValues is an extension of ClientSession that can store
+// arbitrary values for the lifetime of the session, such as the identity
+// of the user or their locale. See SetSessionValue and SessionValue. Its
+// methods must be safe for concurrent use.
+type SessionWithValues interface {
+	ClientSession
+	// Set stores value under key
+	Set(key string, value any)
+	// Get returns the value stored under key, if any
+	Get(key string) (any, bool)
+	// Delete removes the value stored under key
+	Delete(key string)
+}
+
 // SessionWithStreamableHTTPConfig extends ClientSession to support streamable HTTP transport configurations
 type SessionWithStreamableHTTPConfig interface {
 	ClientSession
@@ -116,6 +180,35 @@ func ClientSessionFromContext(ctx context.Context) ClientSession {
 	return nil
 }
 
+// SetSessionValue stores value under key in the session of ctx, so that
+// later requests of the session can read it with SessionValue. It reports
+// false if ctx has no session or the session cannot store values.
+func SetSessionValue(ctx context.Context, key string, value any) bool {
+	session, ok := ClientSessionFromContext(ctx).(SessionWithValues)
+	if !ok {
+		return false
+	}
+	session.Set(key, value)
+	return true
+}
+
+// SessionValue returns the value stored under key in the session of ctx.
+func SessionValue(ctx context.Context, key string) (any, bool) {
+	session, ok := ClientSessionFromContext(ctx).(SessionWithValues)
+	if !ok {
+		return nil, false
+	}
+	return session.Get(key)
+}
+
+// DeleteSessionValue removes the value stored under key in the session of
+// ctx.
+func DeleteSessionValue(ctx context.Context, key string) {
+	if session, ok := ClientSessionFromContext(ctx).(SessionWithValues); ok {
+		session.Delete(key)
+	}
+}
+
 // WithContext sets the current client session and returns the provided context
 func (s *MCPServer) WithContext(
 	ctx context.Context,
@@ -133,26 +226,12 @@ func (s *MCPServer) RegisterSession(
 	if _, exists := s.sessions.LoadOrStore(sessionID, session); exists {
 		return ErrSessionExists
 	}
+	s.sessionTTL.track(s, sessionID)
+	s.logEvent(ctx, slog.LevelInfo, "session registered", "session_id", sessionID)
 	s.hooks.RegisterSession(ctx, session)
 	return nil
 }
 
-func (s *MCPServer) buildLogNotification(notification mcp.LoggingMessageNotification) mcp.JSONRPCNotification {
-	return mcp.JSONRPCNotification{
-		JSONRPC: mcp.JSONRPC_VERSION,
-		Notification: mcp.Notification{
-			Method: notification.Method,
-			Params: mcp.NotificationParams{
-				AdditionalFields: map[string]any{
-					"level":  notification.Params.Level,
-					"logger": notification.Params.Logger,
-					"data":   notification.Params.Data,
-				},
-			},
-		},
-	}
-}
-
 func (s *MCPServer) SendLogMessageToClient(ctx context.Context, notification mcp.LoggingMessageNotification) error {
 	session := ClientSessionFromContext(ctx)
 	if session == nil || !session.Initialized() {
@@ -165,31 +244,13 @@ func (s *MCPServer) SendLogMessageToClient(ctx context.Context, notification mcp
 	if !notification.Params.Level.ShouldSendTo(sessionLogging.GetLogLevel()) {
 		return nil
 	}
-	return s.sendNotificationCore(ctx, session, s.buildLogNotification(notification))
+	return s.sendNotificationCore(ctx, session, notification.ToJSONRPCNotification())
 }
 
 func (s *MCPServer) sendNotificationToAllClients(notification mcp.JSONRPCNotification) {
 	s.sessions.Range(func(k, v any) bool {
 		if session, ok := v.(ClientSession); ok && session.Initialized() {
-			select {
-			case session.NotificationChannel() <- notification:
-				// Successfully sent notification
-			default:
-				// Channel is blocked, if there's an error hook, use it
-				if s.hooks != nil && len(s.hooks.OnError) > 0 {
-					err := ErrNotificationChannelBlocked
-					// Copy hooks pointer to local variable to avoid race condition
-					hooks := s.hooks
-					go func(sessionID string, hooks *Hooks) {
-						ctx := context.Background()
-						// Use the error hook to report the blocked channel
-						hooks.onError(ctx, nil, "notification", map[string]any{
-							"method":    notification.Method,
-							"sessionID": sessionID,
-						}, fmt.Errorf("notification channel blocked for session %s: %w", sessionID, err))
-					}(session.SessionID(), hooks)
-				}
-			}
+			_ = s.deliverNotification(context.Background(), session, notification)
 		}
 		return true
 	})
@@ -200,26 +261,7 @@ func (s *MCPServer) sendNotificationToSpecificClient(session ClientSession, noti
 	if sessionWithStreamableHTTPConfig, ok := session.(SessionWithStreamableHTTPConfig); ok {
 		sessionWithStreamableHTTPConfig.UpgradeToSSEWhenReceiveNotification()
 	}
-	select {
-	case session.NotificationChannel() <- notification:
-		return nil
-	default:
-		// Channel is blocked, if there's an error hook, use it
-		if s.hooks != nil && len(s.hooks.OnError) > 0 {
-			err := ErrNotificationChannelBlocked
-			ctx := context.Background()
-			// Copy hooks pointer to local variable to avoid race condition
-			hooks := s.hooks
-			go func(sID string, hooks *Hooks) {
-				// Use the error hook to report the blocked channel
-				hooks.onError(ctx, nil, "notification", map[string]any{
-					"method":    notification.Method,
-					"sessionID": sID,
-				}, fmt.Errorf("notification channel blocked for session %s: %w", sID, err))
-			}(session.SessionID(), hooks)
-		}
-		return ErrNotificationChannelBlocked
-	}
+	return s.deliverNotification(context.Background(), session, notification)
 }
 
 func (s *MCPServer) SendLogMessageToSpecificClient(sessionID string, notification mcp.LoggingMessageNotification) error {
@@ -238,10 +280,11 @@ func (s *MCPServer) SendLogMessageToSpecificClient(sessionID string, notificatio
 	if !notification.Params.Level.ShouldSendTo(sessionLogging.GetLogLevel()) {
 		return nil
 	}
-	return s.sendNotificationToSpecificClient(session, s.buildLogNotification(notification))
+	return s.sendNotificationToSpecificClient(session, notification.ToJSONRPCNotification())
 }
 
-// UnregisterSession removes from storage session that is shut down.
+// UnregisterSession removes from storage session that is shut down, and
+// closes its connection if it implements SessionWithClose.
 func (s *MCPServer) UnregisterSession(
 	ctx context.Context,
 	sessionID string,
@@ -250,17 +293,40 @@ func (s *MCPServer) UnregisterSession(
 	if !ok {
 		return
 	}
+	s.removeResourceSubscriptions(sessionID)
+	s.removeSessionRegistrations(sessionID)
+	s.forgetVisibleTools(sessionID)
+	s.forgetVisiblePrompts(sessionID)
+	s.forgetResourceListDiff(sessionID)
+	s.rateLimiter.forgetSession(sessionID)
+	s.sessionTTL.forget(sessionID)
+	s.notifications.forget(sessionID)
+	s.tenancy.forget(sessionID)
+	s.sessionProtocolVersions.Delete(sessionID)
+	s.sessionCapabilities.Delete(sessionID)
+	s.logEvent(ctx, slog.LevelInfo, "session unregistered", "session_id", sessionID)
 	if session, ok := sessionValue.(ClientSession); ok {
 		s.hooks.UnregisterSession(ctx, session)
 	}
+	if session, ok := sessionValue.(SessionWithClose); ok {
+		session.Close()
+	}
 }
 
-// SendNotificationToAllClients sends a notification to all the currently active clients.
+// SendNotificationToAllClients sends a notification to all the currently
+// active clients, including the clients of the other servers of the
+// notification bus if WithNotificationBus is used.
 func (s *MCPServer) SendNotificationToAllClients(
 	method string,
 	params map[string]any,
 ) {
-	notification := mcp.JSONRPCNotification{
+	notification := newNotification(method, params)
+	s.sendNotificationToAllClients(notification)
+	s.publish(BusMessage{Notification: &notification})
+}
+
+func newNotification(method string, params map[string]any) mcp.JSONRPCNotification {
+	return mcp.JSONRPCNotification{
 		JSONRPC: mcp.JSONRPC_VERSION,
 		Notification: mcp.Notification{
 			Method: method,
@@ -269,7 +335,6 @@ func (s *MCPServer) SendNotificationToAllClients(
 			},
 		},
 	}
-	s.sendNotificationToAllClients(notification)
 }
 
 // SendNotificationToClient sends a notification to the current client
@@ -282,26 +347,7 @@ func (s *MCPServer) sendNotificationCore(
 	if sessionWithStreamableHTTPConfig, ok := session.(SessionWithStreamableHTTPConfig); ok {
 		sessionWithStreamableHTTPConfig.UpgradeToSSEWhenReceiveNotification()
 	}
-	select {
-	case session.NotificationChannel() <- notification:
-		return nil
-	default:
-		// Channel is blocked, if there's an error hook, use it
-		if s.hooks != nil && len(s.hooks.OnError) > 0 {
-			method := notification.Method
-			err := ErrNotificationChannelBlocked
-			// Copy hooks pointer to local variable to avoid race condition
-			hooks := s.hooks
-			go func(sessionID string, hooks *Hooks) {
-				// Use the error hook to report the blocked channel
-				hooks.onError(ctx, nil, "notification", map[string]any{
-					"method":    method,
-					"sessionID": sessionID,
-				}, fmt.Errorf("notification channel blocked for session %s: %w", sessionID, err))
-			}(session.SessionID(), hooks)
-		}
-		return ErrNotificationChannelBlocked
-	}
+	return s.deliverNotification(ctx, session, propagateNotificationMeta(ctx, notification))
 }
 
 // SendNotificationToClient sends a notification to the current client
@@ -326,6 +372,32 @@ func (s *MCPServer) SendNotificationToClient(
 	return s.sendNotificationCore(ctx, session, notification)
 }
 
+// SendTypedNotificationToClient sends a notification built with the typed
+// constructors of the mcp package, such as mcp.NewProgressNotification, to
+// the current client.
+func (s *MCPServer) SendTypedNotificationToClient(ctx context.Context, notification mcp.TypedNotification) error {
+	session := ClientSessionFromContext(ctx)
+	if session == nil || !session.Initialized() {
+		return ErrNotificationNotInitialized
+	}
+	return s.sendNotificationCore(ctx, session, notification.ToJSONRPCNotification())
+}
+
+// SendTypedNotificationToSpecificClient sends a notification built with
+// the typed constructors of the mcp package to a specific client by session
+// ID.
+func (s *MCPServer) SendTypedNotificationToSpecificClient(sessionID string, notification mcp.TypedNotification) error {
+	sessionValue, ok := s.sessions.Load(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+	session, ok := sessionValue.(ClientSession)
+	if !ok || !session.Initialized() {
+		return ErrSessionNotInitialized
+	}
+	return s.sendNotificationToSpecificClient(session, notification.ToJSONRPCNotification())
+}
+
 // SendNotificationToSpecificClient sends a notification to a specific client by session ID
 func (s *MCPServer) SendNotificationToSpecificClient(
 	sessionID string,
@@ -369,6 +441,12 @@ func (s *MCPServer) AddSessionTools(sessionID string, tools ...ServerTool) error
 		return ErrSessionDoesNotSupportTools
 	}
 
+	for _, tool := range tools {
+		if err := tool.Tool.ValidateSchema(); err != nil {
+			return err
+		}
+	}
+
 	s.implicitlyRegisterToolCapabilities()
 
 	// Get existing tools (this should return a thread-safe copy)
@@ -390,6 +468,12 @@ func (s *MCPServer) AddSessionTools(sessionID string, tools ...ServerTool) error
 	// Set the tools (this should be thread-safe)
 	session.SetSessionTools(newSessionTools)
 
+	keys := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		keys = append(keys, sessionRegistryKey(registryKindTool, tool.Tool.Name))
+	}
+	s.recordSessionRegistrations(sessionID, keys...)
+
 	// It only makes sense to send tool notifications to initialized sessions --
 	// if we're not initialized yet the client can't possibly have sent their
 	// initial tools/list message.
@@ -399,7 +483,7 @@ func (s *MCPServer) AddSessionTools(sessionID string, tools ...ServerTool) error
 	// see <https://modelcontextprotocol.io/specification/2025-03-26/server/tools#capabilities>
 	if session.Initialized() && s.capabilities.tools != nil && s.capabilities.tools.listChanged {
 		// Send notification only to this session
-		if err := s.SendNotificationToSpecificClient(sessionID, "notifications/tools/list_changed", nil); err != nil {
+		if err := s.notifySessionListChanged(sessionID, mcp.MethodNotificationToolsListChanged); err != nil {
 			// Log the error but don't fail the operation
 			// The tools were successfully added, but notification failed
 			if s.hooks != nil && len(s.hooks.OnError) > 0 {
@@ -461,7 +545,7 @@ func (s *MCPServer) DeleteSessionTools(sessionID string, names ...string) error
 	// see <https://modelcontextprotocol.io/specification/2025-03-26/server/tools#capabilities>
 	if session.Initialized() && s.capabilities.tools != nil && s.capabilities.tools.listChanged {
 		// Send notification only to this session
-		if err := s.SendNotificationToSpecificClient(sessionID, "notifications/tools/list_changed", nil); err != nil {
+		if err := s.notifySessionListChanged(sessionID, mcp.MethodNotificationToolsListChanged); err != nil {
 			// Log the error but don't fail the operation
 			// The tools were successfully deleted, but notification failed
 			if s.hooks != nil && len(s.hooks.OnError) > 0 {
@@ -532,6 +616,24 @@ func (s *MCPServer) AddSessionResources(sessionID string, resources ...ServerRes
 	// Set the resources (this should be thread-safe)
 	session.SetSessionResources(newSessionResources)
 
+	differ := newResourceListDiffer(sessionID)
+	for _, resource := range resources {
+		uri := resource.Resource.URI
+		before, listed := sessionResources[uri]
+		if !listed {
+			before.Resource, listed = s.globalResource(uri)
+		}
+		differ.compare(uri, before.Resource, listed, resource.Resource, true)
+	}
+	diff := differ.result()
+	s.recordResourceListDiff(diff)
+
+	keys := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		keys = append(keys, sessionRegistryKey(registryKindResource, resource.Resource.URI))
+	}
+	s.recordSessionRegistrations(sessionID, keys...)
+
 	// It only makes sense to send resource notifications to initialized sessions --
 	// if we're not initialized yet the client can't possibly have sent their
 	// initial resources/list message.
@@ -539,9 +641,9 @@ func (s *MCPServer) AddSessionResources(sessionID string, resources ...ServerRes
 	// For initialized sessions, honor resources.listChanged, which is specifically
 	// about whether notifications will be sent or not.
 	// see <https://modelcontextprotocol.io/specification/2025-03-26/server/resources#capabilities>
-	if session.Initialized() && s.capabilities.resources != nil && s.capabilities.resources.listChanged {
+	if !diff.Empty() && session.Initialized() && s.capabilities.resources != nil && s.capabilities.resources.listChanged {
 		// Send notification only to this session
-		if err := s.SendNotificationToSpecificClient(sessionID, "notifications/resources/list_changed", nil); err != nil {
+		if err := s.notifySessionListChanged(sessionID, mcp.MethodNotificationResourcesListChanged); err != nil {
 			// Log the error but don't fail the operation
 			// The resources were successfully added, but notification failed
 			if s.hooks != nil && len(s.hooks.OnError) > 0 {
@@ -560,6 +662,11 @@ func (s *MCPServer) AddSessionResources(sessionID string, resources ...ServerRes
 	return nil
 }
 
+// DeleteSessionResource removes a resource from a specific session
+func (s *MCPServer) DeleteSessionResource(sessionID string, uri string) error {
+	return s.DeleteSessionResources(sessionID, uri)
+}
+
 // DeleteSessionResources removes resources from a specific session
 func (s *MCPServer) DeleteSessionResources(sessionID string, uris ...string) error {
 	sessionValue, ok := s.sessions.Load(sessionID)
@@ -588,10 +695,14 @@ func (s *MCPServer) DeleteSessionResources(sessionID string, uris ...string) err
 
 	// Remove specified resources and track if anything was actually deleted
 	actuallyDeleted := false
+	differ := newResourceListDiffer(sessionID)
 	for _, uri := range uris {
-		if _, exists := newSessionResources[uri]; exists {
+		if existing, exists := newSessionResources[uri]; exists {
 			delete(newSessionResources, uri)
 			actuallyDeleted = true
+			// A global resource with the same URI becomes visible again.
+			global, listed := s.globalResource(uri)
+			differ.compare(uri, existing.Resource, true, global, listed)
 		}
 	}
 
@@ -602,6 +713,8 @@ func (s *MCPServer) DeleteSessionResources(sessionID string, uris ...string) err
 
 	// Set the resources (this should be thread-safe)
 	session.SetSessionResources(newSessionResources)
+	diff := differ.result()
+	s.recordResourceListDiff(diff)
 
 	// It only makes sense to send resource notifications to initialized sessions --
 	// if we're not initialized yet the client can't possibly have sent their
@@ -610,10 +723,10 @@ func (s *MCPServer) DeleteSessionResources(sessionID string, uris ...string) err
 	// For initialized sessions, honor resources.listChanged, which is specifically
 	// about whether notifications will be sent or not.
 	// see <https://modelcontextprotocol.io/specification/2025-03-26/server/resources#capabilities>
-	// Only send notification if something was actually deleted
-	if actuallyDeleted && session.Initialized() && s.capabilities.resources != nil && s.capabilities.resources.listChanged {
+	// Only send notification if the listed resources actually changed
+	if !diff.Empty() && session.Initialized() && s.capabilities.resources != nil && s.capabilities.resources.listChanged {
 		// Send notification only to this session
-		if err := s.SendNotificationToSpecificClient(sessionID, "notifications/resources/list_changed", nil); err != nil {
+		if err := s.notifySessionListChanged(sessionID, mcp.MethodNotificationResourcesListChanged); err != nil {
 			// Log the error but don't fail the operation
 			// The resources were successfully deleted, but notification failed
 			if s.hooks != nil && len(s.hooks.OnError) > 0 {
@@ -688,9 +801,15 @@ func (s *MCPServer) AddSessionResourceTemplates(sessionID string, templates ...S
 	// Set the new templates (this method must handle thread-safety)
 	session.SetSessionResourceTemplates(newTemplates)
 
+	keys := make([]string, 0, len(templates))
+	for _, t := range templates {
+		keys = append(keys, sessionRegistryKey(registryKindResourceTemplate, t.Template.URITemplate.Raw()))
+	}
+	s.recordSessionRegistrations(sessionID, keys...)
+
 	// Send notification if the session is initialized and listChanged is enabled
 	if session.Initialized() && s.capabilities.resources != nil && s.capabilities.resources.listChanged {
-		if err := s.SendNotificationToSpecificClient(sessionID, "notifications/resources/list_changed", nil); err != nil {
+		if err := s.notifySessionListChanged(sessionID, mcp.MethodNotificationResourcesListChanged); err != nil {
 			// Log the error but don't fail the operation
 			if s.hooks != nil && len(s.hooks.OnError) > 0 {
 				hooks := s.hooks
@@ -747,7 +866,7 @@ func (s *MCPServer) DeleteSessionResourceTemplates(sessionID string, uriTemplate
 
 		// Send notification if the session is initialized and listChanged is enabled
 		if session.Initialized() && s.capabilities.resources != nil && s.capabilities.resources.listChanged {
-			if err := s.SendNotificationToSpecificClient(sessionID, "notifications/resources/list_changed", nil); err != nil {
+			if err := s.notifySessionListChanged(sessionID, mcp.MethodNotificationResourcesListChanged); err != nil {
 				// Log the error but don't fail the operation
 				if s.hooks != nil && len(s.hooks.OnError) > 0 {
 					hooks := s.hooks
@@ -765,3 +884,127 @@ func (s *MCPServer) DeleteSessionResourceTemplates(sessionID string, uriTemplate
 
 	return nil
 }
+
+// AddSessionPrompt adds a prompt for a specific session
+func (s *MCPServer) AddSessionPrompt(sessionID string, prompt mcp.Prompt, handler PromptHandlerFunc) error {
+	return s.AddSessionPrompts(sessionID, ServerPrompt{Prompt: prompt, Handler: handler})
+}
+
+// AddSessionPrompts adds prompts for a specific session. They override
+// global and tenant prompts of the same name for that session.
+func (s *MCPServer) AddSessionPrompts(sessionID string, prompts ...ServerPrompt) error {
+	sessionValue, ok := s.sessions.Load(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	session, ok := sessionValue.(SessionWithPrompts)
+	if !ok {
+		return ErrSessionDoesNotSupportPrompts
+	}
+
+	for _, prompt := range prompts {
+		if err := prompt.Prompt.ValidateSchema(); err != nil {
+			return err
+		}
+	}
+
+	// For session prompts, we want listChanged enabled by default
+	s.implicitlyRegisterCapabilities(
+		func() bool { return s.capabilities.prompts != nil },
+		func() { s.capabilities.prompts = &promptCapabilities{listChanged: true} },
+	)
+	s.implicitlyRegisterCompletionCapabilities(prompts)
+
+	// Get existing prompts (this should return a thread-safe copy)
+	sessionPrompts := session.GetSessionPrompts()
+
+	// Create a new map to avoid concurrent modification issues
+	newSessionPrompts := make(map[string]ServerPrompt, len(sessionPrompts)+len(prompts))
+	maps.Copy(newSessionPrompts, sessionPrompts)
+	for _, prompt := range prompts {
+		newSessionPrompts[prompt.Prompt.Name] = prompt
+	}
+
+	// Set the prompts (this should be thread-safe)
+	session.SetSessionPrompts(newSessionPrompts)
+
+	keys := make([]string, 0, len(prompts))
+	for _, prompt := range prompts {
+		keys = append(keys, sessionRegistryKey(registryKindPrompt, prompt.Prompt.Name))
+	}
+	s.recordSessionRegistrations(sessionID, keys...)
+
+	// Honor prompts.listChanged for initialized sessions, as for tools.
+	if session.Initialized() && s.capabilities.prompts != nil && s.capabilities.prompts.listChanged {
+		if err := s.notifySessionListChanged(sessionID, mcp.MethodNotificationPromptsListChanged); err != nil {
+			// Log the error but don't fail the operation
+			if s.hooks != nil && len(s.hooks.OnError) > 0 {
+				hooks := s.hooks
+				go func(sID string, hooks *Hooks) {
+					ctx := context.Background()
+					hooks.onError(ctx, nil, "notification", map[string]any{
+						"method":    "notifications/prompts/list_changed",
+						"sessionID": sID,
+					}, fmt.Errorf("failed to send notification after adding prompts: %w", err))
+				}(sessionID, hooks)
+			}
+		}
+	}
+
+	return nil
+}
+
+// DeleteSessionPrompts removes prompts from a specific session. Global and
+// tenant prompts of the same name become visible to it again.
+func (s *MCPServer) DeleteSessionPrompts(sessionID string, names ...string) error {
+	sessionValue, ok := s.sessions.Load(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	session, ok := sessionValue.(SessionWithPrompts)
+	if !ok {
+		return ErrSessionDoesNotSupportPrompts
+	}
+
+	// Get existing prompts (this should return a thread-safe copy)
+	sessionPrompts := session.GetSessionPrompts()
+
+	// Create a new map without the deleted prompts
+	newSessionPrompts := make(map[string]ServerPrompt, len(sessionPrompts))
+	maps.Copy(newSessionPrompts, sessionPrompts)
+	deletedAny := false
+	for _, name := range names {
+		if _, exists := newSessionPrompts[name]; exists {
+			delete(newSessionPrompts, name)
+			deletedAny = true
+		}
+	}
+
+	// Skip no-op write if nothing was actually deleted
+	if !deletedAny {
+		return nil
+	}
+
+	// Set the prompts (this should be thread-safe)
+	session.SetSessionPrompts(newSessionPrompts)
+
+	if session.Initialized() && s.capabilities.prompts != nil && s.capabilities.prompts.listChanged {
+		if err := s.notifySessionListChanged(sessionID, mcp.MethodNotificationPromptsListChanged); err != nil {
+			// Log the error but don't fail the operation
+			if s.hooks != nil && len(s.hooks.OnError) > 0 {
+				hooks := s.hooks
+				go func(sID string, hooks *Hooks) {
+					ctx := context.Background()
+					hooks.onError(ctx, nil, "notification", map[string]any{
+						"method":    "notifications/prompts/list_changed",
+						"sessionID": sID,
+					}, fmt.Errorf("failed to send notification after deleting prompts: %w", err))
+				}(sessionID, hooks)
+			}
+		}
+	}
+
+	return nil
+}
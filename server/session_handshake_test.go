@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestSessionWithHandshake(t *testing.T) {
+	tests := []struct {
+		name    string
+		session SessionWithHandshake
+	}{
+		{name: "sse", session: &sseSession{sessionID: "sse"}},
+		{name: "stdio", session: &stdioSession{}},
+		{name: "in-process", session: NewInProcessSession("in-process", nil)},
+		{name: "streamable http", session: newStreamableHttpSession("http", nil, nil, nil, nil)},
+		{name: "stateless streamable http", session: newStreamableHttpSession("", nil, nil, nil, nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Empty(t, tt.session.NegotiatedProtocolVersion())
+
+			s := NewMCPServer("test", "1.0.0")
+			ctx := s.WithContext(context.Background(), tt.session)
+			response := s.HandleMessage(ctx, initializeMessage("1.0"))
+			_, ok := response.(mcp.JSONRPCResponse)
+			require.True(t, ok, "unexpected response %v", response)
+
+			assert.Equal(t, mcp.Implementation{Name: "test-client", Version: "1.0"}, tt.session.ClientInfo())
+			assert.NotNil(t, tt.session.ClientCapabilities().Sampling)
+			assert.Equal(t, mcp.LATEST_PROTOCOL_VERSION, tt.session.NegotiatedProtocolVersion())
+			assert.Equal(t, mcp.LATEST_PROTOCOL_VERSION, s.NegotiatedProtocolVersion(ctx))
+		})
+	}
+}
+
+func TestStreamableHTTP_HandshakeOutlivesRequests(t *testing.T) {
+	mcpServer := NewMCPServer("test-mcp-server", "1.0")
+	mcpServer.AddTool(mcp.NewTool("client"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		session, ok := ClientSessionFromContext(ctx).(SessionWithHandshake)
+		if !ok {
+			return mcp.NewToolResultError("no handshake"), nil
+		}
+		info := session.ClientInfo()
+		sampling := session.ClientCapabilities().Sampling != nil
+		return mcp.NewToolResultText(fmt.Sprintf("%s %s %s sampling=%t", info.Name, info.Version, session.NegotiatedProtocolVersion(), sampling)), nil
+	})
+	server := NewTestStreamableHTTPServer(mcpServer)
+	defer server.Close()
+
+	resp, err := postJSON(server.URL, initRequest)
+	require.NoError(t, err)
+	resp.Body.Close()
+	sessionID := resp.Header.Get(HeaderKeySessionID)
+	require.NotEmpty(t, sessionID)
+
+	resp, err = postSessionJSON(server.URL, sessionID, map[string]any{
+		"jsonrpc": "2.0",
+		"id":      2,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": "client"},
+	})
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var response struct {
+		Result json.RawMessage `json:"result"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+	result, err := mcp.ParseCallToolResult(&response.Result)
+	require.NoError(t, err)
+	require.Len(t, result.Content, 1)
+	assert.Equal(t, "test-client 1.0.0 "+mcp.LATEST_PROTOCOL_VERSION+" sampling=false", result.Content[0].(mcp.TextContent).Text)
+}
@@ -0,0 +1,218 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// sessionTestClientWithPrompts implements the SessionWithPrompts interface for testing
+type sessionTestClientWithPrompts struct {
+	sessionID           string
+	notificationChannel chan mcp.JSONRPCNotification
+	initialized         atomic.Bool
+	sessionPrompts      map[string]ServerPrompt
+	mu                  sync.RWMutex
+}
+
+func newSessionTestClientWithPrompts(sessionID string) *sessionTestClientWithPrompts {
+	session := &sessionTestClientWithPrompts{
+		sessionID:           sessionID,
+		notificationChannel: make(chan mcp.JSONRPCNotification, 10),
+	}
+	session.initialized.Store(true)
+	return session
+}
+
+func (f *sessionTestClientWithPrompts) SessionID() string {
+	return f.sessionID
+}
+
+func (f *sessionTestClientWithPrompts) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return f.notificationChannel
+}
+
+func (f *sessionTestClientWithPrompts) Initialize() {
+	f.initialized.Store(true)
+}
+
+func (f *sessionTestClientWithPrompts) Initialized() bool {
+	return f.initialized.Load()
+}
+
+func (f *sessionTestClientWithPrompts) GetSessionPrompts() map[string]ServerPrompt {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return maps.Clone(f.sessionPrompts)
+}
+
+func (f *sessionTestClientWithPrompts) SetSessionPrompts(prompts map[string]ServerPrompt) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sessionPrompts = maps.Clone(prompts)
+}
+
+var _ SessionWithPrompts = (*sessionTestClientWithPrompts)(nil)
+
+func textPrompt(name, text string) ServerPrompt {
+	return ServerPrompt{
+		Prompt: mcp.NewPrompt(name, mcp.WithPromptDescription(text)),
+		Handler: func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return mcp.NewGetPromptResult(text, []mcp.PromptMessage{
+				mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+			}), nil
+		},
+	}
+}
+
+func listPromptNames(t *testing.T, server *MCPServer, session ClientSession) map[string]string {
+	t.Helper()
+	response := server.HandleMessage(server.WithContext(context.Background(), session),
+		[]byte(`{"jsonrpc":"2.0","id":1,"method":"prompts/list"}`))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "unexpected response %v", response)
+	result := resp.Result.(mcp.ListPromptsResult)
+	descriptions := make(map[string]string, len(result.Prompts))
+	for _, prompt := range result.Prompts {
+		descriptions[prompt.Name] = prompt.Description
+	}
+	return descriptions
+}
+
+func getPrompt(server *MCPServer, session ClientSession, name string) mcp.JSONRPCMessage {
+	return server.HandleMessage(server.WithContext(context.Background(), session),
+		[]byte(`{"jsonrpc":"2.0","id":2,"method":"prompts/get","params":{"name":"`+name+`"}}`))
+}
+
+func expectPromptsListChanged(t *testing.T, session *sessionTestClientWithPrompts, want bool) {
+	t.Helper()
+	select {
+	case notification := <-session.notificationChannel:
+		assert.True(t, want, "unexpected notification %s", notification.Method)
+		assert.Equal(t, mcp.MethodNotificationPromptsListChanged, notification.Method)
+	case <-time.After(100 * time.Millisecond):
+		assert.False(t, want, "expected notifications/prompts/list_changed")
+	}
+}
+
+func TestMCPServer_SessionPrompts(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithPromptCapabilities(true))
+	server.AddPrompts(textPrompt("greet", "global"), textPrompt("summarize", "global"))
+
+	session := newSessionTestClientWithPrompts("session-1")
+	other := newSessionTestClientWithPrompts("session-2")
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+	require.NoError(t, server.RegisterSession(context.Background(), other))
+
+	require.NoError(t, server.AddSessionPrompts(session.SessionID(), textPrompt("greet", "session"), textPrompt("onboard", "session")))
+	expectPromptsListChanged(t, session, true)
+	expectPromptsListChanged(t, other, false)
+
+	assert.Equal(t, map[string]string{"greet": "session", "onboard": "session", "summarize": "global"}, listPromptNames(t, server, session))
+	assert.Equal(t, map[string]string{"greet": "global", "summarize": "global"}, listPromptNames(t, server, other))
+
+	response, ok := getPrompt(server, session, "greet").(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	assert.Equal(t, "session", response.Result.(mcp.GetPromptResult).Description)
+	_, ok = getPrompt(server, other, "onboard").(mcp.JSONRPCError)
+	assert.True(t, ok, "session prompts are not visible to other sessions")
+
+	// Deleting restores the global prompt.
+	require.NoError(t, server.DeleteSessionPrompts(session.SessionID(), "greet", "missing"))
+	expectPromptsListChanged(t, session, true)
+	assert.Equal(t, map[string]string{"greet": "global", "onboard": "session", "summarize": "global"}, listPromptNames(t, server, session))
+
+	// Deleting nothing does not notify.
+	require.NoError(t, server.DeleteSessionPrompts(session.SessionID(), "missing"))
+	expectPromptsListChanged(t, session, false)
+
+	snapshot, err := server.SessionRegistry(session.SessionID())
+	require.NoError(t, err)
+	require.Len(t, snapshot.Prompts, 3)
+	assert.Equal(t, "onboard", snapshot.Prompts[1].Name)
+	assert.Equal(t, RegistryScopeSession, snapshot.Prompts[1].Scope)
+	assert.NotNil(t, snapshot.Prompts[1].AddedAt)
+}
+
+func TestMCPServer_AddSessionPromptsErrors(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+	assert.ErrorIs(t, server.AddSessionPrompt("unknown", mcp.NewPrompt("greet"), nil), ErrSessionNotFound)
+	assert.ErrorIs(t, server.DeleteSessionPrompts("unknown", "greet"), ErrSessionNotFound)
+
+	session := &sessionTestClient{sessionID: "plain"}
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+	assert.ErrorIs(t, server.AddSessionPrompt(session.SessionID(), mcp.NewPrompt("greet"), nil), ErrSessionDoesNotSupportPrompts)
+	assert.ErrorIs(t, server.DeleteSessionPrompts(session.SessionID(), "greet"), ErrSessionDoesNotSupportPrompts)
+}
+
+func TestMCPServer_SessionPromptsUninitialized(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+	session := newSessionTestClientWithPrompts("session-1")
+	session.initialized.Store(false)
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+
+	require.NoError(t, server.AddSessionPrompt(session.SessionID(), mcp.NewPrompt("greet"), textPrompt("greet", "session").Handler))
+	expectPromptsListChanged(t, session, false)
+
+	// Session prompts turn on the prompts capability with listChanged.
+	require.NotNil(t, server.capabilities.prompts)
+	assert.True(t, server.capabilities.prompts.listChanged)
+}
+
+func TestMCPServer_SessionPromptFilter(t *testing.T) {
+	var admin atomic.Bool
+	server := NewMCPServer("test-server", "1.0.0",
+		WithPromptCapabilities(true),
+		WithSessionPromptFilter(func(ctx context.Context, session ClientSession, prompts []mcp.Prompt) []mcp.Prompt {
+			if admin.Load() {
+				return prompts
+			}
+			visible := prompts[:0:0]
+			for _, prompt := range prompts {
+				if prompt.Name != "admin" {
+					visible = append(visible, prompt)
+				}
+			}
+			return visible
+		}),
+	)
+	server.AddPrompts(textPrompt("admin", "global"), textPrompt("greet", "global"))
+	session := newSessionTestClientWithPrompts("session-1")
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+
+	// Not notified before listing prompts.
+	require.NoError(t, server.RefreshPromptVisibility(context.Background(), session.SessionID()))
+	expectPromptsListChanged(t, session, false)
+
+	assert.Equal(t, map[string]string{"greet": "global"}, listPromptNames(t, server, session))
+	errResponse, ok := getPrompt(server, session, "admin").(mcp.JSONRPCError)
+	require.True(t, ok)
+	assert.Equal(t, mcp.INVALID_PARAMS, errResponse.Error.Code)
+
+	// Unchanged visibility does not notify.
+	require.NoError(t, server.RefreshAllPromptVisibility(context.Background()))
+	expectPromptsListChanged(t, session, false)
+
+	admin.Store(true)
+	require.NoError(t, server.RefreshPromptVisibility(context.Background(), session.SessionID()))
+	expectPromptsListChanged(t, session, true)
+	_, ok = getPrompt(server, session, "admin").(mcp.JSONRPCResponse)
+	assert.True(t, ok)
+
+	snapshot, err := server.SessionRegistry(session.SessionID())
+	require.NoError(t, err)
+	assert.Len(t, snapshot.Prompts, 2)
+
+	raw, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), `"admin"`)
+}
@@ -0,0 +1,281 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// RegistryScope tells whether a registry entry is visible to every session,
+// to the sessions of one tenant or was added for one session only.
+type RegistryScope string
+
+const (
+	// RegistryScopeServer marks entries registered on the server itself.
+	RegistryScopeServer RegistryScope = "server"
+	// RegistryScopeTenant marks entries added with the AddTenant* methods.
+	RegistryScopeTenant RegistryScope = "tenant"
+	// RegistryScopeSession marks entries added with the AddSession* methods.
+	RegistryScopeSession RegistryScope = "session"
+)
+
+// SessionRegistryEntry describes one tool, resource, resource template or
+// prompt in a SessionRegistrySnapshot.
+type SessionRegistryEntry struct {
+	// Name is the tool or prompt name, the resource URI or the URI template.
+	Name  string        `json:"name"`
+	Scope RegistryScope `json:"scope"`
+	// Source is the provenance given at registration (for example
+	// ServerTool.Source). Only recorded for session-scoped entries.
+	Source string `json:"source,omitempty"`
+	// AddedAt is when the entry was added to the session. Only recorded for
+	// session-scoped entries.
+	AddedAt *time.Time `json:"addedAt,omitempty"`
+	// Definition is the mcp.Tool, mcp.Resource, mcp.ResourceTemplate or
+	// mcp.Prompt as it is advertised to the client.
+	Definition any `json:"definition"`
+}
+
+// SessionRegistrySnapshot records what a session could access at a point in
+// time: the server-wide registries merged with the entries of the session's
+// tenant and the session's own entries, which take precedence in that
+// order, after tool filters have been applied.
+type SessionRegistrySnapshot struct {
+	SessionID string `json:"sessionId"`
+	// Tenant is the tenant the session is bound to by WithTenancy, if any.
+	Tenant            string                 `json:"tenant,omitempty"`
+	CapturedAt        time.Time              `json:"capturedAt"`
+	ClientInfo        *mcp.Implementation    `json:"clientInfo,omitempty"`
+	Tools             []SessionRegistryEntry `json:"tools"`
+	Resources         []SessionRegistryEntry `json:"resources"`
+	ResourceTemplates []SessionRegistryEntry `json:"resourceTemplates"`
+	Prompts           []SessionRegistryEntry `json:"prompts"`
+}
+
+// SessionRegistryEncoder serializes a snapshot for ExportSessionRegistry.
+type SessionRegistryEncoder func(snapshot *SessionRegistrySnapshot) ([]byte, error)
+
+// WithSessionRegistryEncoder replaces the JSON encoding used by
+// ExportSessionRegistry, for example to sign snapshots or write them in the
+// format an audit pipeline expects.
+func WithSessionRegistryEncoder(encoder SessionRegistryEncoder) ServerOption {
+	return func(s *MCPServer) {
+		s.sessionRegistryEncoder = encoder
+	}
+}
+
+const (
+	registryKindTool             = "tool"
+	registryKindResource         = "resource"
+	registryKindResourceTemplate = "resourceTemplate"
+	registryKindPrompt           = "prompt"
+)
+
+func sessionRegistryKey(kind, name string) string {
+	return kind + "/" + name
+}
+
+// recordSessionRegistrations stamps the given registry keys of a session
+// with the current time.
+func (s *MCPServer) recordSessionRegistrations(sessionID string, keys ...string) {
+	now := time.Now()
+	s.sessionRegistryMu.Lock()
+	defer s.sessionRegistryMu.Unlock()
+	added := s.sessionRegistrations[sessionID]
+	if added == nil {
+		added = make(map[string]time.Time, len(keys))
+		s.sessionRegistrations[sessionID] = added
+	}
+	for _, key := range keys {
+		added[key] = now
+	}
+}
+
+func (s *MCPServer) removeSessionRegistrations(sessionID string) {
+	s.sessionRegistryMu.Lock()
+	defer s.sessionRegistryMu.Unlock()
+	delete(s.sessionRegistrations, sessionID)
+}
+
+// ExportSessionRegistry returns a serialized SessionRegistrySnapshot of
+// the given session, encoded as JSON unless WithSessionRegistryEncoder
+// was used.
+func (s *MCPServer) ExportSessionRegistry(sessionID string) ([]byte, error) {
+	snapshot, err := s.SessionRegistry(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if s.sessionRegistryEncoder != nil {
+		return s.sessionRegistryEncoder(snapshot)
+	}
+	return json.Marshal(snapshot)
+}
+
+// SessionRegistry captures the tools, resources, resource templates and
+// prompts the given session can access right now.
+func (s *MCPServer) SessionRegistry(sessionID string) (*SessionRegistrySnapshot, error) {
+	sessionValue, ok := s.sessions.Load(sessionID)
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	session := sessionValue.(ClientSession)
+
+	s.sessionRegistryMu.Lock()
+	added := make(map[string]time.Time, len(s.sessionRegistrations[sessionID]))
+	for key, at := range s.sessionRegistrations[sessionID] {
+		added[key] = at
+	}
+	s.sessionRegistryMu.Unlock()
+
+	ctx := s.WithContext(context.Background(), session)
+	tenant, hasTenant := s.tenancy.sessionTenant(sessionID)
+	if hasTenant {
+		ctx = WithTenant(ctx, tenant)
+	}
+
+	// overlayEntry records an entry of the tenant or session overlay, which
+	// comes from the session if inSession reports so.
+	overlayEntry := func(kind, name, source string, inSession bool, definition any) SessionRegistryEntry {
+		if !inSession {
+			return SessionRegistryEntry{Name: name, Scope: RegistryScopeTenant, Definition: definition}
+		}
+		entry := SessionRegistryEntry{Name: name, Scope: RegistryScopeSession, Source: source, Definition: definition}
+		if at, ok := added[sessionRegistryKey(kind, name)]; ok {
+			entry.AddedAt = &at
+		}
+		return entry
+	}
+
+	snapshot := &SessionRegistrySnapshot{
+		SessionID:  sessionID,
+		Tenant:     tenant,
+		CapturedAt: time.Now(),
+	}
+	if withInfo, ok := session.(SessionWithClientInfo); ok {
+		if info := withInfo.GetClientInfo(); info != (mcp.Implementation{}) {
+			snapshot.ClientInfo = &info
+		}
+	}
+
+	// Tools
+	tools := make(map[string]SessionRegistryEntry)
+	s.tools.each(func(name string, tool ServerTool) bool {
+		tools[name] = SessionRegistryEntry{Name: name, Scope: RegistryScopeServer, Definition: listedTool(tool)}
+		return true
+	})
+	var sessionTools map[string]ServerTool
+	if withTools, ok := session.(SessionWithTools); ok {
+		sessionTools = withTools.GetSessionTools()
+	}
+	for name, tool := range s.overlayTools(ctx) {
+		_, inSession := sessionTools[name]
+		tools[name] = overlayEntry(registryKindTool, name, tool.Source, inSession, listedTool(tool))
+	}
+	snapshot.Tools = s.filterRegistryTools(ctx, tools)
+
+	// Resources
+	resources := make(map[string]SessionRegistryEntry)
+	s.resources.each(func(uri string, entry resourceEntry) bool {
+		resources[uri] = SessionRegistryEntry{Name: uri, Scope: RegistryScopeServer, Definition: entry.resource}
+		return true
+	})
+	var sessionResources map[string]ServerResource
+	if withResources, ok := session.(SessionWithResources); ok {
+		sessionResources = withResources.GetSessionResources()
+	}
+	for uri, resource := range s.overlayResources(ctx) {
+		_, inSession := sessionResources[uri]
+		resources[uri] = overlayEntry(registryKindResource, uri, resource.Source, inSession, resource.Resource)
+	}
+	templates := make(map[string]SessionRegistryEntry)
+	s.resourceTemplates.each(func(uriTemplate string, entry resourceTemplateEntry) bool {
+		templates[uriTemplate] = SessionRegistryEntry{Name: uriTemplate, Scope: RegistryScopeServer, Definition: entry.template}
+		return true
+	})
+	var sessionTemplates map[string]ServerResourceTemplate
+	if withTemplates, ok := session.(SessionWithResourceTemplates); ok {
+		sessionTemplates = withTemplates.GetSessionResourceTemplates()
+	}
+	for uriTemplate, template := range s.overlayResourceTemplates(ctx) {
+		_, inSession := sessionTemplates[uriTemplate]
+		templates[uriTemplate] = overlayEntry(registryKindResourceTemplate, uriTemplate, template.Source, inSession, template.Template)
+	}
+	snapshot.Resources = sortedRegistryEntries(resources)
+	snapshot.ResourceTemplates = sortedRegistryEntries(templates)
+
+	// Prompts
+	prompts := make(map[string]SessionRegistryEntry)
+	s.prompts.each(func(name string, prompt ServerPrompt) bool {
+		prompts[name] = SessionRegistryEntry{Name: name, Scope: RegistryScopeServer, Definition: prompt.Prompt}
+		return true
+	})
+	var sessionPrompts map[string]ServerPrompt
+	if withPrompts, ok := session.(SessionWithPrompts); ok {
+		sessionPrompts = withPrompts.GetSessionPrompts()
+	}
+	for name, prompt := range s.overlayPrompts(ctx) {
+		_, inSession := sessionPrompts[name]
+		prompts[name] = overlayEntry(registryKindPrompt, name, "", inSession, prompt.Prompt)
+	}
+	snapshot.Prompts = s.filterRegistryPrompts(ctx, prompts)
+
+	return snapshot, nil
+}
+
+// filterRegistryTools applies the server's tool filters to the snapshot
+// entries, as handleListTools would for the session.
+func (s *MCPServer) filterRegistryTools(ctx context.Context, entries map[string]SessionRegistryEntry) []SessionRegistryEntry {
+	sorted := sortedRegistryEntries(entries)
+
+	s.toolFiltersMu.RLock()
+	filters := s.toolFilters
+	s.toolFiltersMu.RUnlock()
+
+	tools := make([]mcp.Tool, 0, len(sorted))
+	for _, entry := range sorted {
+		tools = append(tools, entry.Definition.(mcp.Tool))
+	}
+	for _, filter := range filters {
+		tools = filter(ctx, tools)
+	}
+	tools = s.applySessionToolFilters(ctx, tools)
+
+	filtered := make([]SessionRegistryEntry, 0, len(tools))
+	for _, tool := range tools {
+		entry := entries[tool.Name]
+		entry.Definition = tool
+		filtered = append(filtered, entry)
+	}
+	return filtered
+}
+
+// filterRegistryPrompts applies the session prompt filters to the
+// snapshot entries, as handleListPrompts would for the session.
+func (s *MCPServer) filterRegistryPrompts(ctx context.Context, entries map[string]SessionRegistryEntry) []SessionRegistryEntry {
+	sorted := sortedRegistryEntries(entries)
+	prompts := make([]mcp.Prompt, 0, len(sorted))
+	for _, entry := range sorted {
+		prompts = append(prompts, entry.Definition.(mcp.Prompt))
+	}
+	prompts = s.applySessionPromptFilters(ctx, prompts)
+
+	filtered := make([]SessionRegistryEntry, 0, len(prompts))
+	for _, prompt := range prompts {
+		filtered = append(filtered, entries[prompt.Name])
+	}
+	return filtered
+}
+
+func sortedRegistryEntries(entries map[string]SessionRegistryEntry) []SessionRegistryEntry {
+	sorted := make([]SessionRegistryEntry, 0, len(entries))
+	for _, entry := range entries {
+		sorted = append(sorted, entry)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func newRegistryTestSession(t *testing.T, server *MCPServer, sessionID string) {
+	t.Helper()
+	session := newStreamableHttpSession(sessionID, newSessionToolsStore(), newSessionResourcesStore(),
+		newSessionResourceTemplatesStore(), newSessionLogLevelsStore())
+	session.prompts = newSessionPromptsStore()
+	session.SetClientInfo(mcp.Implementation{Name: "audit-client", Version: "1.0.0"})
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+}
+
+func TestMCPServer_SessionRegistry(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0",
+		WithToolFilter(func(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+			filtered := make([]mcp.Tool, 0, len(tools))
+			for _, tool := range tools {
+				if tool.Name != "hidden" {
+					filtered = append(filtered, tool)
+				}
+			}
+			return filtered
+		}),
+	)
+	server.AddTool(mcp.NewTool("search", mcp.WithDescription("global")), echoToolHandler)
+	server.AddTool(mcp.NewTool("hidden"), echoToolHandler)
+	server.AddPrompt(mcp.NewPrompt("greet"), nil)
+	server.AddResource(mcp.NewResource("file:///readme", "readme"), nil)
+
+	newRegistryTestSession(t, server, "alice")
+	before := time.Now()
+	require.NoError(t, server.AddSessionTools("alice",
+		ServerTool{Tool: mcp.NewTool("search", mcp.WithDescription("scoped")), Handler: echoToolHandler, Source: "tenant-plugin"},
+		ServerTool{Tool: mcp.NewTool("billing"), Handler: echoToolHandler},
+	))
+	require.NoError(t, server.AddSessionResourceTemplates("alice", ServerResourceTemplate{
+		Template: mcp.NewResourceTemplate("users://alice/{id}", "alice-data"),
+		Source:   "auth-middleware",
+	}))
+
+	snapshot, err := server.SessionRegistry("alice")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", snapshot.SessionID)
+	require.NotNil(t, snapshot.ClientInfo)
+	assert.Equal(t, "audit-client", snapshot.ClientInfo.Name)
+
+	require.Len(t, snapshot.Tools, 2, "filtered tools are excluded")
+	billing, search := snapshot.Tools[0], snapshot.Tools[1]
+	assert.Equal(t, "billing", billing.Name)
+	assert.Equal(t, RegistryScopeSession, billing.Scope)
+	assert.Empty(t, billing.Source)
+	assert.Equal(t, "search", search.Name)
+	assert.Equal(t, RegistryScopeSession, search.Scope, "session tools shadow server tools")
+	assert.Equal(t, "tenant-plugin", search.Source)
+	assert.Equal(t, "scoped", search.Definition.(mcp.Tool).Description)
+	require.NotNil(t, search.AddedAt)
+	assert.False(t, search.AddedAt.Before(before))
+
+	require.Len(t, snapshot.ResourceTemplates, 1)
+	assert.Equal(t, "users://alice/{id}", snapshot.ResourceTemplates[0].Name)
+	assert.Equal(t, "auth-middleware", snapshot.ResourceTemplates[0].Source)
+
+	require.Len(t, snapshot.Resources, 1)
+	assert.Equal(t, RegistryScopeServer, snapshot.Resources[0].Scope)
+	assert.Nil(t, snapshot.Resources[0].AddedAt)
+
+	require.Len(t, snapshot.Prompts, 1)
+	assert.Equal(t, "greet", snapshot.Prompts[0].Name)
+
+	t.Run("other sessions only see server entries", func(t *testing.T) {
+		newRegistryTestSession(t, server, "bob")
+		snapshot, err := server.SessionRegistry("bob")
+		require.NoError(t, err)
+		require.Len(t, snapshot.Tools, 1)
+		assert.Equal(t, RegistryScopeServer, snapshot.Tools[0].Scope)
+		assert.Equal(t, "global", snapshot.Tools[0].Definition.(mcp.Tool).Description)
+		assert.Empty(t, snapshot.ResourceTemplates)
+	})
+
+	t.Run("unknown session", func(t *testing.T) {
+		_, err := server.SessionRegistry("nobody")
+		assert.ErrorIs(t, err, ErrSessionNotFound)
+		_, err = server.ExportSessionRegistry("nobody")
+		assert.ErrorIs(t, err, ErrSessionNotFound)
+	})
+
+	t.Run("unregistering drops registration times", func(t *testing.T) {
+		server.UnregisterSession(context.Background(), "alice")
+		server.sessionRegistryMu.Lock()
+		defer server.sessionRegistryMu.Unlock()
+		assert.NotContains(t, server.sessionRegistrations, "alice")
+	})
+}
+
+func TestMCPServer_SessionRegistry_Tenant(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithTenancy(TenantFromHeader("X-Tenant")))
+	server.AddTools(textTool("shared", "shared"), textTool("search", "search"))
+	server.AddTenantTools("acme", textTool("report", "acme report"), textTool("shared", "acme shared"))
+	server.AddTenantTools("globex", textTool("export", "globex export"))
+	server.AddTenantResources("acme", textResource("tenant://data", "acme data"))
+	server.AddTenantResourceTemplates("acme", ServerResourceTemplate{
+		Template: mcp.NewResourceTemplate("tenant://{name}", "acme"),
+		Handler:  namedTemplateHandler("acme template"),
+	})
+	server.AddTenantPrompts("acme", ServerPrompt{Prompt: mcp.NewPrompt("greeting")})
+
+	newRegistryTestSession(t, server, "acme-1")
+	session, ok := server.sessions.Load("acme-1")
+	require.True(t, ok)
+	response := server.HandleMessage(tenantRequest(server, session.(ClientSession), "acme"),
+		json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+	require.IsType(t, mcp.JSONRPCResponse{}, response)
+	require.NoError(t, server.AddSessionTools("acme-1", textTool("report", "session report")))
+
+	snapshot, err := server.SessionRegistry("acme-1")
+	require.NoError(t, err)
+	assert.Equal(t, "acme", snapshot.Tenant)
+
+	scopes := func(entries []SessionRegistryEntry) map[string]RegistryScope {
+		scopes := make(map[string]RegistryScope, len(entries))
+		for _, entry := range entries {
+			scopes[entry.Name] = entry.Scope
+		}
+		return scopes
+	}
+	assert.Equal(t, map[string]RegistryScope{
+		"report": RegistryScopeSession,
+		"search": RegistryScopeServer,
+		"shared": RegistryScopeTenant,
+	}, scopes(snapshot.Tools), "session tools shadow tenant tools, which shadow server tools")
+	assert.Equal(t, map[string]RegistryScope{"tenant://data": RegistryScopeTenant}, scopes(snapshot.Resources))
+	assert.Equal(t, map[string]RegistryScope{"tenant://{name}": RegistryScopeTenant}, scopes(snapshot.ResourceTemplates))
+	assert.Equal(t, map[string]RegistryScope{"greeting": RegistryScopeTenant}, scopes(snapshot.Prompts))
+
+	t.Run("sessions without a tenant only see server entries", func(t *testing.T) {
+		newRegistryTestSession(t, server, "unbound")
+		snapshot, err := server.SessionRegistry("unbound")
+		require.NoError(t, err)
+		assert.Empty(t, snapshot.Tenant)
+		assert.Equal(t, map[string]RegistryScope{
+			"search": RegistryScopeServer,
+			"shared": RegistryScopeServer,
+		}, scopes(snapshot.Tools))
+		assert.Empty(t, snapshot.Prompts)
+	})
+}
+
+func TestMCPServer_ExportSessionRegistry(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+	newRegistryTestSession(t, server, "alice")
+	require.NoError(t, server.AddSessionTool("alice", mcp.NewTool("billing"), echoToolHandler))
+
+	data, err := server.ExportSessionRegistry("alice")
+	require.NoError(t, err)
+
+	var exported map[string]any
+	require.NoError(t, json.Unmarshal(data, &exported))
+	assert.Equal(t, "alice", exported["sessionId"])
+	assert.Contains(t, exported, "capturedAt")
+	tools := exported["tools"].([]any)
+	require.Len(t, tools, 1)
+	tool := tools[0].(map[string]any)
+	assert.Equal(t, "billing", tool["name"])
+	assert.Equal(t, "session", tool["scope"])
+	assert.Contains(t, tool, "addedAt")
+	assert.Equal(t, "billing", tool["definition"].(map[string]any)["name"])
+
+	t.Run("custom encoder", func(t *testing.T) {
+		errEncode := errors.New("encode failed")
+		var seen *SessionRegistrySnapshot
+		server := NewMCPServer("test-server", "1.0.0",
+			WithSessionRegistryEncoder(func(snapshot *SessionRegistrySnapshot) ([]byte, error) {
+				seen = snapshot
+				return nil, errEncode
+			}),
+		)
+		newRegistryTestSession(t, server, "alice")
+
+		_, err := server.ExportSessionRegistry("alice")
+		assert.ErrorIs(t, err, errEncode)
+		require.NotNil(t, seen)
+		assert.Equal(t, "alice", seen.SessionID)
+	})
+}
@@ -1,8 +1,12 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -762,3 +766,73 @@ func TestNotificationErrorHandling(t *testing.T) {
 	assert.Len(t, sessionResources, 1)
 	assert.Contains(t, sessionResources, "test://resource")
 }
+
+// TestDeleteSessionResource tests removing a single resource, which makes
+// the global resource of the same URI visible again
+func TestDeleteSessionResource(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithResourceCapabilities(false, true))
+	server.AddResource(mcp.NewResource("test://shared", "Global"), nil)
+
+	sessionChan := make(chan mcp.JSONRPCNotification, 10)
+	session := &sessionTestClientWithResources{
+		sessionID:           "session-1",
+		notificationChannel: sessionChan,
+		initialized:         true,
+		sessionResources: map[string]ServerResource{
+			"test://shared": {Resource: mcp.NewResource("test://shared", "Session")},
+		},
+	}
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+
+	require.NoError(t, server.DeleteSessionResource(session.SessionID(), "test://shared"))
+	select {
+	case notification := <-sessionChan:
+		assert.Equal(t, "notifications/resources/list_changed", notification.Method)
+	case <-time.After(100 * time.Millisecond):
+		t.Error("Expected notification not received")
+	}
+	assert.Empty(t, session.GetSessionResources())
+
+	response := server.HandleMessage(server.WithContext(context.Background(), session),
+		[]byte(`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	resources := resp.Result.(mcp.ListResourcesResult).Resources
+	require.Len(t, resources, 1)
+	assert.Equal(t, "Global", resources[0].Name)
+
+	assert.ErrorIs(t, server.DeleteSessionResource("unknown", "test://shared"), ErrSessionNotFound)
+}
+
+// TestStreamableHTTPSessionResourcesCleanup tests that the resources of a
+// streamable HTTP session are dropped when the client terminates it
+func TestStreamableHTTPSessionResourcesCleanup(t *testing.T) {
+	mcpServer := NewMCPServer("test-server", "1.0.0")
+	httpServer := NewStreamableHTTPServer(mcpServer)
+	ts := httptest.NewServer(httpServer)
+	defer ts.Close()
+
+	body, err := json.Marshal(initRequest)
+	require.NoError(t, err)
+	resp, err := http.Post(ts.URL, "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	resp.Body.Close()
+	sessionID := resp.Header.Get(HeaderKeySessionID)
+	require.NotEmpty(t, sessionID)
+
+	require.NoError(t, mcpServer.AddSessionResource(sessionID, mcp.NewResource("test://session", "Session"),
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return nil, nil
+		}))
+	assert.Contains(t, httpServer.sessionResources.get(sessionID), "test://session")
+
+	req, err := http.NewRequest(http.MethodDelete, ts.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set(HeaderKeySessionID, sessionID)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.Empty(t, httpServer.sessionResources.get(sessionID))
+}
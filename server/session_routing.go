@@ -0,0 +1,162 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+)
+
+// sessionNodeSeparator separates the node ID from the rest of a session ID.
+const sessionNodeSeparator = "."
+
+// HeaderKeyForwardedBy is set by HTTPSessionForwarder to the node that
+// forwarded a request, so that a request is never forwarded twice.
+const HeaderKeyForwardedBy = "Mcp-Forwarded-By"
+
+// SessionRouter forwards requests for sessions owned by another node of a
+// load-balanced deployment to that node.
+type SessionRouter interface {
+	// Forward serves r, whose session is owned by node, by passing it on to
+	// node and copying its response to w.
+	Forward(w http.ResponseWriter, r *http.Request, node string)
+}
+
+// WithSessionRouting makes the StreamableHTTP server one node of a
+// deployment whose load balancer does not route requests of a session to
+// the node holding it. The ID of the node is embedded in the IDs of the
+// sessions it creates, and requests carrying a session ID of another node
+// are passed to router instead of being handled locally. nodeID must not
+// contain a dot and must be unique among the nodes.
+func WithSessionRouting(nodeID string, router SessionRouter) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		s.nodeID = nodeID
+		s.sessionRouter = router
+	}
+}
+
+// SessionNodeID returns the ID of the node that created sessionID when the
+// node uses WithSessionRouting.
+func SessionNodeID(sessionID string) (string, bool) {
+	node, rest, ok := strings.Cut(sessionID, sessionNodeSeparator)
+	if !ok || node == "" || rest == "" {
+		return "", false
+	}
+	return node, true
+}
+
+// routeSession forwards r if its session belongs to another node. It
+// reports whether r was forwarded or rejected.
+func (s *StreamableHTTPServer) routeSession(w http.ResponseWriter, r *http.Request) bool {
+	if s.nodeID == "" {
+		return false
+	}
+	node, ok := SessionNodeID(r.Header.Get(HeaderKeySessionID))
+	if !ok || node == s.nodeID {
+		return false
+	}
+	if s.sessionRouter == nil || r.Header.Get(HeaderKeyForwardedBy) != "" {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return true
+	}
+	s.sessionRouter.Forward(w, r, node)
+	return true
+}
+
+// nodeSessionIdManagerResolver embeds the node ID in the session IDs of the
+// SessionIdManager it wraps.
+type nodeSessionIdManagerResolver struct {
+	nodeID   string
+	resolver SessionIdManagerResolver
+}
+
+func (r *nodeSessionIdManagerResolver) ResolveSessionIdManager(req *http.Request) SessionIdManager {
+	return &nodeSessionIdManager{nodeID: r.nodeID, manager: r.resolver.ResolveSessionIdManager(req)}
+}
+
+type nodeSessionIdManager struct {
+	nodeID  string
+	manager SessionIdManager
+}
+
+func (m *nodeSessionIdManager) Generate() string {
+	sessionID := m.manager.Generate()
+	if sessionID == "" {
+		return ""
+	}
+	return m.nodeID + sessionNodeSeparator + sessionID
+}
+
+func (m *nodeSessionIdManager) Validate(sessionID string) (isTerminated bool, err error) {
+	local, err := m.localID(sessionID)
+	if err != nil {
+		return false, err
+	}
+	return m.manager.Validate(local)
+}
+
+func (m *nodeSessionIdManager) Terminate(sessionID string) (isNotAllowed bool, err error) {
+	local, err := m.localID(sessionID)
+	if err != nil {
+		return false, err
+	}
+	return m.manager.Terminate(local)
+}
+
+// localID strips the node ID from sessionID. Empty IDs are passed on to the
+// wrapped manager, which decides whether sessions are required.
+func (m *nodeSessionIdManager) localID(sessionID string) (string, error) {
+	if sessionID == "" {
+		return "", nil
+	}
+	local, ok := strings.CutPrefix(sessionID, m.nodeID+sessionNodeSeparator)
+	if !ok {
+		return "", fmt.Errorf("invalid session id: %s", sessionID)
+	}
+	return local, nil
+}
+
+// HTTPSessionForwarder is a SessionRouter proxying requests over HTTP,
+// streaming responses so that SSE streams work through it.
+type HTTPSessionForwarder struct {
+	nodeID  string
+	proxies map[string]*httputil.ReverseProxy
+}
+
+// NewHTTPSessionForwarder returns a SessionRouter for the node nodeID
+// forwarding requests to the other nodes, given by their ID and base URL
+// such as "http://10.0.0.2:8080". The request path is appended to the base
+// URL. Requests for unknown nodes are answered with 404 Not Found, so that
+// their clients start a new session.
+func NewHTTPSessionForwarder(nodeID string, nodes map[string]string) (*HTTPSessionForwarder, error) {
+	f := &HTTPSessionForwarder{nodeID: nodeID, proxies: make(map[string]*httputil.ReverseProxy, len(nodes))}
+	for node, baseURL := range nodes {
+		target, err := url.Parse(baseURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL of node %q: %w", node, err)
+		}
+		if target.Scheme == "" || target.Host == "" {
+			return nil, fmt.Errorf("invalid URL of node %q: %s", node, baseURL)
+		}
+		f.proxies[node] = &httputil.ReverseProxy{
+			Rewrite: func(pr *httputil.ProxyRequest) {
+				pr.SetURL(target)
+				pr.SetXForwarded()
+				pr.Out.Header.Set(HeaderKeyForwardedBy, nodeID)
+			},
+			FlushInterval: -1,
+		}
+	}
+	return f, nil
+}
+
+// Forward implements SessionRouter.
+func (f *HTTPSessionForwarder) Forward(w http.ResponseWriter, r *http.Request, node string) {
+	proxy, ok := f.proxies[node]
+	if !ok || node == f.nodeID {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+	proxy.ServeHTTP(w, r)
+}
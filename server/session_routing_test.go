@@ -0,0 +1,163 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// newRoutedNodes starts two stateful nodes, "a" and "b", forwarding
+// requests of foreign sessions to each other.
+func newRoutedNodes(t *testing.T) (a, b *httptest.Server) {
+	t.Helper()
+	handlers := map[string]http.Handler{}
+	nodes := map[string]*httptest.Server{}
+	urls := map[string]string{}
+	for _, node := range []string{"a", "b"} {
+		nodes[node] = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handlers[node].ServeHTTP(w, r)
+		}))
+		t.Cleanup(nodes[node].Close)
+		urls[node] = nodes[node].URL
+	}
+	for _, node := range []string{"a", "b"} {
+		mcpServer := NewMCPServer("node-"+node, "1.0.0", WithToolCapabilities(false))
+		mcpServer.AddTool(mcp.NewTool("whoami"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText(node), nil
+		})
+		forwarder, err := NewHTTPSessionForwarder(node, urls)
+		require.NoError(t, err)
+		handlers[node] = NewStreamableHTTPServer(mcpServer, WithStateful(true), WithSessionRouting(node, forwarder))
+	}
+	return nodes["a"], nodes["b"]
+}
+
+func TestStreamableHTTP_WithSessionRouting(t *testing.T) {
+	a, b := newRoutedNodes(t)
+
+	resp, err := postJSON(a.URL+"/mcp", initRequest)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	sessionID := resp.Header.Get(HeaderKeySessionID)
+	node, ok := SessionNodeID(sessionID)
+	require.True(t, ok, "session ID %q carries the node", sessionID)
+	assert.Equal(t, "a", node)
+
+	callWhoami := func(url, sessionID string) (int, string) {
+		t.Helper()
+		resp, err := postSessionJSON(url+"/mcp", sessionID, map[string]any{
+			"jsonrpc": "2.0",
+			"id":      2,
+			"method":  "tools/call",
+			"params":  map[string]any{"name": "whoami"},
+		})
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	t.Run("local session", func(t *testing.T) {
+		status, body := callWhoami(a.URL, sessionID)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Contains(t, body, `"text":"a"`)
+	})
+
+	t.Run("forwarded to the owning node", func(t *testing.T) {
+		status, body := callWhoami(b.URL, sessionID)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Contains(t, body, `"text":"a"`)
+	})
+
+	t.Run("unknown node", func(t *testing.T) {
+		status, _ := callWhoami(b.URL, "c."+strings.TrimPrefix(sessionID, "a."))
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("session of the node without its node ID", func(t *testing.T) {
+		status, _ := callWhoami(a.URL, strings.TrimPrefix(sessionID, "a."))
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("forwarded delete", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodDelete, b.URL+"/mcp", nil)
+		require.NoError(t, err)
+		req.Header.Set(HeaderKeySessionID, sessionID)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		status, _ := callWhoami(a.URL, sessionID)
+		assert.Equal(t, http.StatusNotFound, status, "the session is terminated on its node")
+	})
+}
+
+func TestStreamableHTTP_SessionRoutingLoop(t *testing.T) {
+	var forwarded int
+	router := sessionRouterFunc(func(w http.ResponseWriter, r *http.Request, node string) {
+		forwarded++
+		w.WriteHeader(http.StatusTeapot)
+	})
+	server := NewTestStreamableHTTPServer(NewMCPServer("test", "1.0.0"), WithSessionRouting("a", router))
+	defer server.Close()
+
+	for _, forwardedBy := range []string{"", "b"} {
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/mcp", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(HeaderKeySessionID, "b.mcp-session-1")
+		if forwardedBy != "" {
+			req.Header.Set(HeaderKeyForwardedBy, forwardedBy)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		if forwardedBy == "" {
+			assert.Equal(t, http.StatusTeapot, resp.StatusCode)
+		} else {
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode, "forwarded requests are not forwarded again")
+		}
+	}
+	assert.Equal(t, 1, forwarded)
+}
+
+func TestNewHTTPSessionForwarder_InvalidURL(t *testing.T) {
+	_, err := NewHTTPSessionForwarder("a", map[string]string{"b": "10.0.0.2:8080"})
+	assert.ErrorContains(t, err, `invalid URL of node "b"`)
+}
+
+func TestSessionNodeID(t *testing.T) {
+	tests := []struct {
+		sessionID string
+		node      string
+		ok        bool
+	}{
+		{sessionID: "a.mcp-session-1", node: "a", ok: true},
+		{sessionID: "mcp-session-1"},
+		{sessionID: ".mcp-session-1"},
+		{sessionID: "a."},
+		{sessionID: ""},
+	}
+	for _, tt := range tests {
+		node, ok := SessionNodeID(tt.sessionID)
+		assert.Equal(t, tt.node, node, tt.sessionID)
+		assert.Equal(t, tt.ok, ok, tt.sessionID)
+	}
+}
+
+type sessionRouterFunc func(w http.ResponseWriter, r *http.Request, node string)
+
+func (f sessionRouterFunc) Forward(w http.ResponseWriter, r *http.Request, node string) {
+	f(w, r, node)
+}
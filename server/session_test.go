@@ -1032,6 +1032,47 @@ func TestMCPServer_SendNotificationToSpecificClient(t *testing.T) {
 	assert.Contains(t, err.Error(), "not properly initialized")
 }
 
+func TestMCPServer_SendTypedNotification(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+	sessionChan := make(chan mcp.JSONRPCNotification, 10)
+	session := &sessionTestClient{
+		sessionID:           "session-1",
+		notificationChannel: sessionChan,
+	}
+	session.Initialize()
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+
+	total := 4.0
+	ctx := server.WithContext(context.Background(), session)
+	require.NoError(t, server.SendTypedNotificationToClient(ctx, mcp.NewProgressNotification("token", 1, &total, nil)))
+	require.NoError(t, server.SendTypedNotificationToSpecificClient(session.SessionID(), mcp.NewResourceUpdatedNotification("file:///a")))
+
+	for _, want := range []mcp.TypedNotification{
+		&mcp.ProgressNotification{
+			Notification: mcp.Notification{Method: mcp.MethodNotificationProgress},
+			Params:       mcp.ProgressNotificationParams{ProgressToken: "token", Progress: 1, Total: 4},
+		},
+		&mcp.ResourceUpdatedNotification{
+			Notification: mcp.Notification{Method: mcp.MethodNotificationResourceUpdated},
+			Params:       mcp.ResourceUpdatedNotificationParams{URI: "file:///a"},
+		},
+	} {
+		select {
+		case notification := <-sessionChan:
+			parsed, err := mcp.ParseNotification(notification)
+			require.NoError(t, err)
+			assert.Equal(t, want, parsed)
+		case <-time.After(100 * time.Millisecond):
+			t.Fatal("Expected notification not received")
+		}
+	}
+
+	err := server.SendTypedNotificationToClient(context.Background(), mcp.NewToolListChangedNotification())
+	assert.ErrorIs(t, err, ErrNotificationNotInitialized)
+	err = server.SendTypedNotificationToSpecificClient("non-existent", mcp.NewToolListChangedNotification())
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+}
+
 func TestMCPServer_NotificationChannelBlocked(t *testing.T) {
 	// Set up a hooks object to capture error notifications
 	var mu sync.Mutex
@@ -0,0 +1,255 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SessionExpiryFunc is called before an idle session is unregistered by
+// WithSessionTTL. Returning true keeps the session for another TTL.
+type SessionExpiryFunc func(ctx context.Context, session ClientSession) (keep bool)
+
+// SessionTTLOption configures WithSessionTTL.
+type SessionTTLOption func(*sessionTTL)
+
+// WithSessionExpiryHook sets a function called before an idle session is
+// unregistered, which may keep the session.
+func WithSessionExpiryHook(hook SessionExpiryFunc) SessionTTLOption {
+	return func(t *sessionTTL) {
+		t.beforeExpiry = hook
+	}
+}
+
+// WithSessionKeepalive pings sessions that have been idle for interval, if
+// they implement SessionWithPing. A client answering the ping keeps its
+// session alive, so only sessions of unresponsive clients expire.
+func WithSessionKeepalive(interval time.Duration) SessionTTLOption {
+	return func(t *sessionTTL) {
+		t.keepalive = interval
+	}
+}
+
+// WithSessionMaxMissedPings unregisters a session once it has not answered
+// n consecutive keepalive pings, without waiting for the TTL and without
+// consulting the expiry hook. A ping counts as missed when no message arrives
+// from the client before the next one is due, so with WithSessionKeepalive a
+// half-open connection is torn down after about n+1 intervals. It has no
+// effect without WithSessionKeepalive.
+func WithSessionMaxMissedPings(n int) SessionTTLOption {
+	return func(t *sessionTTL) {
+		t.maxMissedPings = n
+	}
+}
+
+// WithSessionTTL unregisters sessions that have not sent a message for ttl,
+// preventing abandoned sessions of SSE and HTTP clients from accumulating.
+// Unregistering an expired session drops its subscriptions and other
+// per-session state and runs the OnUnregisterSession hooks.
+func WithSessionTTL(ttl time.Duration, opts ...SessionTTLOption) ServerOption {
+	return func(s *MCPServer) {
+		t := &sessionTTL{
+			ttl:      ttl,
+			sessions: make(map[string]*sessionActivity),
+		}
+		for _, opt := range opts {
+			opt(t)
+		}
+		s.sessionTTL = t
+	}
+}
+
+// SessionTTLStats counts the session expiries of a server using
+// WithSessionTTL.
+type SessionTTLStats struct {
+	// Evicted is the number of sessions unregistered after expiring.
+	Evicted uint64
+	// Retained is the number of expiries the expiry hook refused.
+	Retained uint64
+	// Pings is the number of keepalive pings sent.
+	Pings uint64
+	// PingTimeouts is the number of sessions unregistered after missing
+	// too many pings.
+	PingTimeouts uint64
+}
+
+// SessionTTLStats returns the session expiry counters of the server. They
+// are zero unless WithSessionTTL is used.
+func (s *MCPServer) SessionTTLStats() SessionTTLStats {
+	t := s.sessionTTL
+	if t == nil {
+		return SessionTTLStats{}
+	}
+	return SessionTTLStats{
+		Evicted:      t.evicted.Load(),
+		Retained:     t.retained.Load(),
+		Pings:        t.pings.Load(),
+		PingTimeouts: t.pingTimeouts.Load(),
+	}
+}
+
+// sessionTTL tracks the activity of registered sessions.
+type sessionTTL struct {
+	ttl            time.Duration
+	keepalive      time.Duration
+	maxMissedPings int
+	beforeExpiry   SessionExpiryFunc
+
+	mu       sync.Mutex
+	sessions map[string]*sessionActivity
+
+	evicted      atomic.Uint64
+	retained     atomic.Uint64
+	pings        atomic.Uint64
+	pingTimeouts atomic.Uint64
+}
+
+// sessionActivity is the activity of one session. The timer fires when the
+// session may have expired or is due a keepalive ping. Missed counts the
+// pings sent since the last message from the client.
+type sessionActivity struct {
+	last   time.Time
+	pinged time.Time
+	missed int
+	timer  *time.Timer
+}
+
+// track starts the expiry timer of a newly registered session.
+func (t *sessionTTL) track(s *MCPServer, sessionID string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	activity := &sessionActivity{last: time.Now()}
+	activity.timer = time.AfterFunc(t.nextCheck(activity, activity.last), func() {
+		t.check(s, sessionID)
+	})
+	t.sessions[sessionID] = activity
+}
+
+// touch records activity of session.
+func (t *sessionTTL) touch(session ClientSession) {
+	if t == nil || session == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if activity, ok := t.sessions[session.SessionID()]; ok {
+		activity.last = time.Now()
+		activity.missed = 0
+	}
+}
+
+// forget stops tracking an unregistered session.
+func (t *sessionTTL) forget(sessionID string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if activity, ok := t.sessions[sessionID]; ok {
+		activity.timer.Stop()
+		delete(t.sessions, sessionID)
+	}
+}
+
+// nextCheck returns when the timer of a session should fire next.
+func (t *sessionTTL) nextCheck(activity *sessionActivity, now time.Time) time.Duration {
+	next := activity.last.Add(t.ttl).Sub(now)
+	if t.keepalive > 0 {
+		next = min(next, t.lastContact(activity).Add(t.keepalive).Sub(now))
+	}
+	return max(next, 0)
+}
+
+// lastContact returns the time of the last message from or ping to the client.
+func (t *sessionTTL) lastContact(activity *sessionActivity) time.Time {
+	if activity.pinged.After(activity.last) {
+		return activity.pinged
+	}
+	return activity.last
+}
+
+// check expires the session if it has been idle for the TTL or missed too
+// many pings, pings it if it is due a keepalive, and otherwise reschedules
+// its timer.
+func (t *sessionTTL) check(s *MCPServer, sessionID string) {
+	t.mu.Lock()
+	activity, ok := t.sessions[sessionID]
+	if !ok {
+		t.mu.Unlock()
+		return
+	}
+	now := time.Now()
+	if now.Sub(activity.last) < t.ttl {
+		ping := t.keepalive > 0 && now.Sub(t.lastContact(activity)) >= t.keepalive
+		if ping && activity.pinged.After(activity.last) {
+			activity.missed++
+			if t.maxMissedPings > 0 && activity.missed >= t.maxMissedPings {
+				t.mu.Unlock()
+				t.pingTimeout(s, sessionID)
+				return
+			}
+		}
+		if ping {
+			activity.pinged = now
+		}
+		activity.timer.Reset(t.nextCheck(activity, now))
+		t.mu.Unlock()
+		if ping {
+			t.ping(s, sessionID)
+		}
+		return
+	}
+	t.mu.Unlock()
+
+	value, ok := s.sessions.Load(sessionID)
+	if !ok {
+		t.forget(sessionID)
+		return
+	}
+	session := value.(ClientSession)
+	ctx := s.WithContext(context.Background(), session)
+	if t.beforeExpiry != nil && t.beforeExpiry(ctx, session) {
+		t.retained.Add(1)
+		t.mu.Lock()
+		if activity, ok := t.sessions[sessionID]; ok {
+			activity.last = time.Now()
+			activity.timer.Reset(t.nextCheck(activity, activity.last))
+		}
+		t.mu.Unlock()
+		return
+	}
+	t.evicted.Add(1)
+	s.UnregisterSession(ctx, sessionID)
+}
+
+// pingTimeout unregisters a session that missed too many pings.
+func (t *sessionTTL) pingTimeout(s *MCPServer, sessionID string) {
+	value, ok := s.sessions.Load(sessionID)
+	if !ok {
+		t.forget(sessionID)
+		return
+	}
+	t.pingTimeouts.Add(1)
+	s.UnregisterSession(s.WithContext(context.Background(), value.(ClientSession)), sessionID)
+}
+
+// ping sends a keepalive ping to the session, if it supports pings.
+func (t *sessionTTL) ping(s *MCPServer, sessionID string) {
+	value, ok := s.sessions.Load(sessionID)
+	if !ok {
+		return
+	}
+	session, ok := value.(SessionWithPing)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), t.keepalive)
+	defer cancel()
+	if err := session.Ping(ctx); err == nil {
+		t.pings.Add(1)
+	}
+}
@@ -0,0 +1,195 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// pingSession is a session whose client answers pings through server.
+type pingSession struct {
+	fakeSession
+	server *MCPServer
+	pings  atomic.Int32
+}
+
+func (p *pingSession) Ping(ctx context.Context) error {
+	p.pings.Add(1)
+	p.server.HandleMessage(p.server.WithContext(ctx, p), json.RawMessage(`{"jsonrpc":"2.0","id":1,"result":{}}`))
+	return nil
+}
+
+func sessionRegistered(server *MCPServer, sessionID string) bool {
+	_, ok := server.sessions.Load(sessionID)
+	return ok
+}
+
+func TestMCPServer_WithSessionTTL(t *testing.T) {
+	var unregistered atomic.Int32
+	hooks := &Hooks{}
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session ClientSession) {
+		unregistered.Add(1)
+	})
+	server := NewMCPServer("test-server", "1.0.0", WithHooks(hooks), WithSessionTTL(50*time.Millisecond))
+
+	idle := fakeSession{sessionID: "idle", notificationChannel: make(chan mcp.JSONRPCNotification, 1)}
+	active := fakeSession{sessionID: "active", notificationChannel: make(chan mcp.JSONRPCNotification, 1)}
+	require.NoError(t, server.RegisterSession(context.Background(), idle))
+	require.NoError(t, server.RegisterSession(context.Background(), active))
+
+	ctx := server.WithContext(context.Background(), active)
+	deadline := time.Now().Add(150 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		server.HandleMessage(ctx, json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.False(t, sessionRegistered(server, "idle"), "idle sessions expire")
+	assert.True(t, sessionRegistered(server, "active"), "active sessions are kept")
+	assert.Equal(t, int32(1), unregistered.Load())
+	assert.Equal(t, SessionTTLStats{Evicted: 1}, server.SessionTTLStats())
+
+	server.UnregisterSession(context.Background(), "active")
+	assert.Empty(t, server.sessionTTL.sessions, "unregistered sessions are no longer tracked")
+}
+
+// streamEnds reports whether the body of resp ends within a second.
+func streamEnds(resp *http.Response) bool {
+	ended := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		close(ended)
+	}()
+	select {
+	case <-ended:
+		return true
+	case <-time.After(time.Second):
+		return false
+	}
+}
+
+func TestMCPServer_WithSessionTTL_ClosesConnections(t *testing.T) {
+	t.Run("SSE", func(t *testing.T) {
+		mcpServer := NewMCPServer("test-server", "1.0.0", WithSessionTTL(50*time.Millisecond))
+		testServer := NewTestServer(mcpServer)
+		defer testServer.Close()
+
+		sseResp, err := http.Get(testServer.URL + "/sse")
+		require.NoError(t, err)
+		defer sseResp.Body.Close()
+		endpointEvent, err := readSSEEvent(sseResp)
+		require.NoError(t, err)
+		messageURL := strings.TrimSpace(strings.Split(strings.Split(endpointEvent, "data: ")[1], "\n")[0])
+
+		assert.True(t, streamEnds(sseResp), "the stream of an expired session ends")
+
+		resp, err := http.Post(messageURL, "application/json", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("streamable HTTP", func(t *testing.T) {
+		mcpServer := NewMCPServer("test-server", "1.0.0", WithSessionTTL(50*time.Millisecond))
+		streamableServer := NewStreamableHTTPServer(mcpServer)
+		testServer := httptest.NewServer(streamableServer)
+		defer testServer.Close()
+
+		req, err := http.NewRequest(http.MethodGet, testServer.URL, nil)
+		require.NoError(t, err)
+		req.Header.Set(HeaderKeySessionID, "listening")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		assert.True(t, streamEnds(resp), "the GET stream of an expired session ends")
+		_, ok := streamableServer.activeSessions.Load("listening")
+		assert.False(t, ok, "expired sessions are no longer active")
+	})
+}
+
+func TestMCPServer_WithSessionExpiryHook(t *testing.T) {
+	var calls atomic.Int32
+	server := NewMCPServer("test-server", "1.0.0", WithSessionTTL(20*time.Millisecond,
+		WithSessionExpiryHook(func(ctx context.Context, session ClientSession) bool {
+			assert.Equal(t, session, ClientSessionFromContext(ctx))
+			return calls.Add(1) == 1
+		})))
+
+	session := fakeSession{sessionID: "session-1", notificationChannel: make(chan mcp.JSONRPCNotification, 1)}
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+
+	require.Eventually(t, func() bool { return !sessionRegistered(server, "session-1") }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, int32(2), calls.Load(), "the first expiry is refused")
+	assert.Equal(t, SessionTTLStats{Evicted: 1, Retained: 1}, server.SessionTTLStats())
+}
+
+func TestMCPServer_WithSessionKeepalive(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithSessionTTL(60*time.Millisecond,
+		WithSessionKeepalive(20*time.Millisecond)))
+
+	session := &pingSession{
+		fakeSession: fakeSession{sessionID: "session-1", notificationChannel: make(chan mcp.JSONRPCNotification, 1)},
+		server:      server,
+	}
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+
+	time.Sleep(200 * time.Millisecond)
+	assert.True(t, sessionRegistered(server, "session-1"), "sessions answering pings are kept")
+	server.UnregisterSession(context.Background(), "session-1")
+	assert.GreaterOrEqual(t, session.pings.Load(), int32(3))
+	assert.Equal(t, uint64(session.pings.Load()), server.SessionTTLStats().Pings)
+}
+
+// silentSession is a session on a half-open connection: pings are sent but
+// never answered.
+type silentSession struct {
+	fakeSession
+	pings  atomic.Int32
+	closed atomic.Bool
+}
+
+func (p *silentSession) Ping(ctx context.Context) error {
+	p.pings.Add(1)
+	return nil
+}
+
+func (p *silentSession) Close() {
+	p.closed.Store(true)
+}
+
+func TestMCPServer_WithSessionMaxMissedPings(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithSessionTTL(time.Minute,
+		WithSessionKeepalive(20*time.Millisecond), WithSessionMaxMissedPings(2)))
+
+	silent := &silentSession{
+		fakeSession: fakeSession{sessionID: "silent", notificationChannel: make(chan mcp.JSONRPCNotification, 1)},
+	}
+	answering := &pingSession{
+		fakeSession: fakeSession{sessionID: "answering", notificationChannel: make(chan mcp.JSONRPCNotification, 1)},
+		server:      server,
+	}
+	require.NoError(t, server.RegisterSession(context.Background(), silent))
+	require.NoError(t, server.RegisterSession(context.Background(), answering))
+
+	time.Sleep(200 * time.Millisecond)
+	assert.False(t, sessionRegistered(server, "silent"), "sessions missing pings are torn down before the TTL")
+	assert.Equal(t, int32(2), silent.pings.Load())
+	assert.True(t, silent.closed.Load(), "the connections of torn down sessions are closed")
+	assert.True(t, sessionRegistered(server, "answering"), "sessions answering pings are kept")
+	assert.Equal(t, uint64(1), server.SessionTTLStats().PingTimeouts)
+	assert.Zero(t, server.SessionTTLStats().Evicted)
+	server.UnregisterSession(context.Background(), "answering")
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestSessionValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		session SessionWithValues
+	}{
+		{name: "sse", session: &sseSession{sessionID: "sse"}},
+		{name: "stdio", session: &stdioSession{}},
+		{name: "in-process", session: NewInProcessSession("in-process", nil)},
+		{name: "streamable http", session: newStreamableHttpSession("http", nil, nil, nil, nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := NewMCPServer("test", "1.0.0").WithContext(context.Background(), tt.session)
+
+			_, ok := SessionValue(ctx, "locale")
+			assert.False(t, ok)
+
+			require.True(t, SetSessionValue(ctx, "locale", "fr-FR"))
+			value, ok := SessionValue(ctx, "locale")
+			assert.True(t, ok)
+			assert.Equal(t, "fr-FR", value)
+
+			DeleteSessionValue(ctx, "locale")
+			_, ok = tt.session.Get("locale")
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestSessionValues_NoSession(t *testing.T) {
+	ctx := context.Background()
+	assert.False(t, SetSessionValue(ctx, "locale", "fr-FR"))
+	_, ok := SessionValue(ctx, "locale")
+	assert.False(t, ok)
+	DeleteSessionValue(ctx, "locale")
+}
+
+func TestStreamableHTTPSession_ValuesOutliveRequests(t *testing.T) {
+	httpServer := NewStreamableHTTPServer(NewMCPServer("test", "1.0.0"))
+	store := httpServer.sessionValues
+	first := httpServer.newSession("session-1")
+	first.Set("user", "alice")
+
+	// Each request of a session gets its own session value.
+	next := httpServer.newSession("session-1")
+	value, ok := next.Get("user")
+	assert.True(t, ok)
+	assert.Equal(t, "alice", value)
+
+	_, ok = httpServer.newSession("session-2").Get("user")
+	assert.False(t, ok, "values are per session")
+
+	stateless := httpServer.newSession("")
+	stateless.Set("user", "bob")
+	_, ok = httpServer.newSession("").Get("user")
+	assert.False(t, ok, "sessions without an ID do not share values")
+
+	store.deleteSession("session-1")
+	_, ok = next.Get("user")
+	assert.False(t, ok)
+}
+
+func TestStreamableHTTP_SessionValues(t *testing.T) {
+	mcpServer := NewMCPServer("test-mcp-server", "1.0")
+	mcpServer.AddTool(mcp.NewTool("whoami"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		user, _ := SessionValue(ctx, "user")
+		name, _ := user.(string)
+		return mcp.NewToolResultText(name), nil
+	})
+	// The context function stashes the identity of the user once, when the
+	// session is initialized.
+	server := NewTestStreamableHTTPServer(mcpServer, WithHTTPContextFunc(func(ctx context.Context, r *http.Request) context.Context {
+		if user := r.Header.Get("X-User"); user != "" {
+			SetSessionValue(ctx, "user", user)
+		}
+		return ctx
+	}))
+	defer server.Close()
+
+	initialize := func(user string) string {
+		t.Helper()
+		body, _ := json.Marshal(initRequest)
+		req, _ := http.NewRequest(http.MethodPost, server.URL, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User", user)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		return resp.Header.Get(HeaderKeySessionID)
+	}
+	whoami := func(sessionID string) string {
+		t.Helper()
+		resp, err := postSessionJSON(server.URL, sessionID, map[string]any{
+			"jsonrpc": "2.0",
+			"id":      2,
+			"method":  "tools/call",
+			"params":  map[string]any{"name": "whoami"},
+		})
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var response struct {
+			Result json.RawMessage `json:"result"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		result, err := mcp.ParseCallToolResult(&response.Result)
+		require.NoError(t, err)
+		require.Len(t, result.Content, 1)
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	alice := initialize("alice")
+	bob := initialize("bob")
+	assert.Equal(t, "alice", whoami(alice))
+	assert.Equal(t, "bob", whoami(bob))
+}
@@ -0,0 +1,381 @@
+// Package sqlprovider exposes a SQL database to MCP clients.
+//
+// A Provider registers resources describing the schema of the database and
+// a tool running read-only queries:
+//
+//	db, err := sql.Open("postgres", dsn)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	provider := sqlprovider.New(db, sqlprovider.Postgres,
+//	    sqlprovider.WithMaxRows(200),
+//	    sqlprovider.WithQueryTimeout(10*time.Second),
+//	)
+//	if err := provider.Register(s); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// The db://tables resource lists the tables and the db://tables/{name}
+// template describes the columns of a table. The query tool takes a SQL
+// statement with placeholders and the values bound to them.
+//
+// Queries run in a read-only transaction that is always rolled back. As not
+// every driver enforces read-only transactions, statements are also checked
+// before running: they must be a single statement starting with an allowed
+// keyword, SELECT or WITH by default, and must not contain data or schema
+// modifying keywords outside of quotes and comments. Connecting with a
+// database user only granted read access remains the most reliable guard.
+package sqlprovider
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// TablesURI is the URI of the resource listing the tables.
+	TablesURI = "db://tables"
+	// TableURITemplate is the URI template of the resources describing a
+	// table.
+	TableURITemplate = "db://tables/{name}"
+)
+
+// Dialect holds the queries introspecting the schema of a database.
+type Dialect struct {
+	// Tables lists the names of the tables, as a single column.
+	Tables string
+	// Columns describes the columns of the table bound to its only
+	// placeholder, as name, data type and "YES" or "NO" for whether the
+	// column is nullable, the way information_schema.columns does.
+	Columns string
+}
+
+var (
+	// Postgres introspects the tables of the current schema of PostgreSQL.
+	Postgres = Dialect{
+		Tables: `SELECT table_name FROM information_schema.tables
+			WHERE table_schema = current_schema() ORDER BY table_name`,
+		Columns: `SELECT column_name, data_type, is_nullable FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = $1 ORDER BY ordinal_position`,
+	}
+	// MySQL introspects the tables of the current database of MySQL and
+	// MariaDB.
+	MySQL = Dialect{
+		Tables: `SELECT table_name FROM information_schema.tables
+			WHERE table_schema = DATABASE() ORDER BY table_name`,
+		Columns: `SELECT column_name, column_type, is_nullable FROM information_schema.columns
+			WHERE table_schema = DATABASE() AND table_name = ? ORDER BY ordinal_position`,
+	}
+	// SQLite introspects the tables of the main database of SQLite.
+	SQLite = Dialect{
+		Tables: `SELECT name FROM sqlite_schema
+			WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name`,
+		Columns: `SELECT name, type, CASE "notnull" WHEN 0 THEN 'YES' ELSE 'NO' END
+			FROM pragma_table_info(?) ORDER BY cid`,
+	}
+)
+
+// Option configures a Provider.
+type Option func(*Provider)
+
+// WithToolName sets the name of the query tool, "query" by default.
+func WithToolName(name string) Option {
+	return func(p *Provider) {
+		p.toolName = name
+	}
+}
+
+// WithMaxRows bounds the rows returned by a query, 100 by default. Results
+// cut at the limit are marked truncated.
+func WithMaxRows(rows int) Option {
+	return func(p *Provider) {
+		p.maxRows = rows
+	}
+}
+
+// WithQueryTimeout bounds the run time of queries and introspection.
+func WithQueryTimeout(timeout time.Duration) Option {
+	return func(p *Provider) {
+		p.timeout = timeout
+	}
+}
+
+// WithAllowedStatements sets the keywords statements may start with,
+// replacing the default SELECT and WITH. Keywords are matched regardless of
+// case.
+func WithAllowedStatements(keywords ...string) Option {
+	return func(p *Provider) {
+		p.allowed = make([]string, len(keywords))
+		for i, keyword := range keywords {
+			p.allowed[i] = strings.ToUpper(keyword)
+		}
+	}
+}
+
+// WithTables restricts the tables described by the schema resources to the
+// given names. It does not restrict the tables queries can read.
+func WithTables(names ...string) Option {
+	return func(p *Provider) {
+		p.tables = append(p.tables, names...)
+	}
+}
+
+// Provider exposes a database as resources and a query tool.
+type Provider struct {
+	db       *sql.DB
+	dialect  Dialect
+	toolName string
+	maxRows  int
+	timeout  time.Duration
+	allowed  []string
+	tables   []string
+}
+
+// New returns a provider for db, introspected with dialect.
+func New(db *sql.DB, dialect Dialect, opts ...Option) *Provider {
+	p := &Provider{
+		db:       db,
+		dialect:  dialect,
+		toolName: "query",
+		maxRows:  100,
+		allowed:  []string{"SELECT", "WITH"},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Register adds the resources, resource template and tool of the provider
+// to s.
+func (p *Provider) Register(s *server.MCPServer) error {
+	if p.db == nil {
+		return errors.New("sqlprovider: database is nil")
+	}
+	s.AddResources(p.Resources()...)
+	s.AddResourceTemplates(p.ResourceTemplates()...)
+	s.AddTools(p.Tools()...)
+	return nil
+}
+
+// Resources returns the resource listing the tables.
+func (p *Provider) Resources() []server.ServerResource {
+	return []server.ServerResource{{
+		Resource: mcp.NewResource(TablesURI, "Database tables",
+			mcp.WithResourceDescription("Names of the tables of the database"),
+			mcp.WithMIMEType("application/json")),
+		Handler: p.handleTables,
+	}}
+}
+
+// ResourceTemplates returns the resource template describing a table.
+func (p *Provider) ResourceTemplates() []server.ServerResourceTemplate {
+	return []server.ServerResourceTemplate{{
+		Template: mcp.NewResourceTemplate(TableURITemplate, "Database table",
+			mcp.WithTemplateDescription("Columns of a table of the database"),
+			mcp.WithTemplateMIMEType("application/json")),
+		Handler: p.handleTable,
+	}}
+}
+
+// Tools returns the query tool.
+func (p *Provider) Tools() []server.ServerTool {
+	tool := mcp.NewTool(p.toolName,
+		mcp.WithDescription(fmt.Sprintf(
+			"Run a read-only SQL query and return at most %d rows. Pass values with placeholders and params rather than in the query text.",
+			p.maxRows)),
+		mcp.WithString("sql", mcp.Required(), mcp.Description("SQL statement, one of: "+strings.Join(p.allowed, ", "))),
+		mcp.WithArray("params", mcp.Description("Values bound to the placeholders of the statement, in order")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+	return []server.ServerTool{{Tool: tool, Handler: p.handleQuery}}
+}
+
+// Column describes a column of a table.
+type Column struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Nullable bool   `json:"nullable"`
+}
+
+// Table describes a table.
+type Table struct {
+	Name    string   `json:"name"`
+	Columns []Column `json:"columns"`
+}
+
+// QueryResult is the structured result of the query tool.
+type QueryResult struct {
+	Columns []string `json:"columns"`
+	Rows    [][]any  `json:"rows"`
+	// Truncated reports whether rows beyond the row limit were dropped.
+	Truncated bool `json:"truncated"`
+}
+
+func (p *Provider) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, p.timeout)
+}
+
+func (p *Provider) tableAllowed(name string) bool {
+	return len(p.tables) == 0 || slices.Contains(p.tables, name)
+}
+
+func (p *Provider) handleTables(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	ctx, cancel := p.withTimeout(ctx)
+	defer cancel()
+	rows, err := p.db.QueryContext(ctx, p.dialect.Tables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	defer rows.Close()
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to list tables: %w", err)
+		}
+		if p.tableAllowed(name) {
+			names = append(names, name)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	return jsonContents(request.Params.URI, names)
+}
+
+func (p *Provider) handleTable(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	var name string
+	switch value := request.Params.Arguments["name"].(type) {
+	case string:
+		name = value
+	case []string:
+		if len(value) > 0 {
+			name = value[0]
+		}
+	}
+	if name == "" || !p.tableAllowed(name) {
+		return nil, fmt.Errorf("%w: %s", server.ErrResourceNotFound, request.Params.URI)
+	}
+
+	ctx, cancel := p.withTimeout(ctx)
+	defer cancel()
+	rows, err := p.db.QueryContext(ctx, p.dialect.Columns, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe table %q: %w", name, err)
+	}
+	defer rows.Close()
+	table := Table{Name: name, Columns: []Column{}}
+	for rows.Next() {
+		var column Column
+		var nullable string
+		if err := rows.Scan(&column.Name, &column.Type, &nullable); err != nil {
+			return nil, fmt.Errorf("failed to describe table %q: %w", name, err)
+		}
+		column.Nullable = strings.EqualFold(nullable, "YES")
+		table.Columns = append(table.Columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to describe table %q: %w", name, err)
+	}
+	if len(table.Columns) == 0 {
+		return nil, fmt.Errorf("%w: %s", server.ErrResourceNotFound, request.Params.URI)
+	}
+	return jsonContents(request.Params.URI, table)
+}
+
+func jsonContents(uri string, v any) ([]mcp.ResourceContents, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{URI: uri, MIMEType: "application/json", Text: string(data)},
+	}, nil
+}
+
+func (p *Provider) handleQuery(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query, err := request.RequireString("sql")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := checkStatement(query, p.allowed); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	var params []any
+	if raw, ok := request.GetArguments()["params"]; ok && raw != nil {
+		if params, ok = raw.([]any); !ok {
+			return mcp.NewToolResultError("params must be an array"), nil
+		}
+	}
+
+	ctx, cancel := p.withTimeout(ctx)
+	defer cancel()
+	tx, err := p.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("failed to start a read-only transaction", err), nil
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := tx.QueryContext(ctx, query, params...)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("query failed", err), nil
+	}
+	defer rows.Close()
+	result, err := p.collect(rows)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("query failed", err), nil
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("failed to encode rows", err), nil
+	}
+	return mcp.NewToolResultStructured(result, string(data)), nil
+}
+
+// collect reads up to the row limit from rows.
+func (p *Provider) collect(rows *sql.Rows) (QueryResult, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return QueryResult{}, err
+	}
+	result := QueryResult{Columns: columns, Rows: [][]any{}}
+	for rows.Next() {
+		if len(result.Rows) == p.maxRows {
+			result.Truncated = true
+			break
+		}
+		values := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return QueryResult{}, err
+		}
+		for i, value := range values {
+			// Drivers return text columns as bytes, which would otherwise
+			// be encoded as base64.
+			if b, ok := value.([]byte); ok && utf8.Valid(b) {
+				values[i] = string(b)
+			}
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,226 @@
+package sqlprovider
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// fakeDB is a database/sql driver answering the introspection queries of
+// the SQLite dialect and a users table.
+type fakeDB struct {
+	mu         sync.Mutex
+	queries    []string
+	args       [][]any
+	readOnly   []bool
+	rolledBack int
+}
+
+var users = [][]driver.Value{
+	{int64(1), []byte("ada")},
+	{int64(2), []byte("grace")},
+	{int64(3), []byte("linus")},
+}
+
+func (db *fakeDB) query(query string, args []driver.NamedValue) (driver.Rows, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	values := make([]any, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	db.queries = append(db.queries, query)
+	db.args = append(db.args, values)
+
+	switch {
+	case query == SQLite.Tables:
+		return &fakeRows{columns: []string{"name"}, rows: [][]driver.Value{{"orders"}, {"users"}}}, nil
+	case query == SQLite.Columns && values[0] == "users":
+		return &fakeRows{columns: []string{"name", "type", "nullable"}, rows: [][]driver.Value{
+			{"id", "INTEGER", "NO"},
+			{"name", "TEXT", "YES"},
+		}}, nil
+	case query == SQLite.Columns:
+		return &fakeRows{columns: []string{"name", "type", "nullable"}}, nil
+	case strings.Contains(query, "FROM users"):
+		return &fakeRows{columns: []string{"id", "name"}, rows: users}, nil
+	}
+	return nil, fmt.Errorf("no such table")
+}
+
+func (db *fakeDB) Connect(context.Context) (driver.Conn, error) { return &fakeConn{db: db}, nil }
+func (db *fakeDB) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{ db *fakeDB }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c *fakeConn) BeginTx(_ context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.readOnly = append(c.db.readOnly, opts.ReadOnly)
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error { return errors.New("the provider never commits") }
+
+func (c *fakeConn) Rollback() error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.rolledBack++
+	return nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.db.query(query, args)
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func newTestServer(t *testing.T, opts ...Option) (*server.MCPServer, *fakeDB) {
+	t.Helper()
+	fake := &fakeDB{}
+	db := sql.OpenDB(fake)
+	t.Cleanup(func() { _ = db.Close() })
+	s := server.NewMCPServer("test-server", "1.0.0")
+	require.NoError(t, New(db, SQLite, opts...).Register(s))
+	return s, fake
+}
+
+func handle(t *testing.T, s *server.MCPServer, method string, params any) mcp.JSONRPCMessage {
+	t.Helper()
+	data, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+	require.NoError(t, err)
+	return s.HandleMessage(context.Background(), data)
+}
+
+func readResource(t *testing.T, s *server.MCPServer, uri string) (string, error) {
+	t.Helper()
+	switch response := handle(t, s, "resources/read", map[string]any{"uri": uri}).(type) {
+	case mcp.JSONRPCResponse:
+		result := response.Result.(mcp.ReadResourceResult)
+		require.Len(t, result.Contents, 1)
+		return result.Contents[0].(mcp.TextResourceContents).Text, nil
+	case mcp.JSONRPCError:
+		return "", errors.New(response.Error.Message)
+	default:
+		t.Fatalf("unexpected response %#v", response)
+		return "", nil
+	}
+}
+
+func callQuery(t *testing.T, s *server.MCPServer, args map[string]any) mcp.CallToolResult {
+	t.Helper()
+	response := handle(t, s, "tools/call", map[string]any{"name": "query", "arguments": args})
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "unexpected response %#v", response)
+	return resp.Result.(mcp.CallToolResult)
+}
+
+func TestProvider_Resources(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	text, err := readResource(t, s, TablesURI)
+	require.NoError(t, err)
+	assert.JSONEq(t, `["orders","users"]`, text)
+
+	text, err = readResource(t, s, "db://tables/users")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"users","columns":[
+		{"name":"id","type":"INTEGER","nullable":false},
+		{"name":"name","type":"TEXT","nullable":true}
+	]}`, text)
+
+	_, err = readResource(t, s, "db://tables/missing")
+	assert.Error(t, err)
+}
+
+func TestProvider_WithTables(t *testing.T) {
+	s, _ := newTestServer(t, WithTables("users"))
+
+	text, err := readResource(t, s, TablesURI)
+	require.NoError(t, err)
+	assert.JSONEq(t, `["users"]`, text)
+
+	_, err = readResource(t, s, "db://tables/orders")
+	assert.Error(t, err)
+}
+
+func TestProvider_Query(t *testing.T) {
+	s, fake := newTestServer(t)
+
+	result := callQuery(t, s, map[string]any{"sql": "SELECT id, name FROM users WHERE id > ?", "params": []any{0}})
+	require.False(t, result.IsError, "%v", result.Content)
+	assert.Equal(t, QueryResult{
+		Columns: []string{"id", "name"},
+		Rows:    [][]any{{int64(1), "ada"}, {int64(2), "grace"}, {int64(3), "linus"}},
+	}, result.StructuredContent)
+	assert.Equal(t, []any{float64(0)}, fake.args[len(fake.args)-1])
+	assert.Equal(t, []bool{true}, fake.readOnly)
+	assert.Equal(t, 1, fake.rolledBack)
+
+	tool := s.GetTool("query")
+	require.NotNil(t, tool)
+	assert.True(t, *tool.Tool.Annotations.ReadOnlyHint)
+}
+
+func TestProvider_QueryMaxRows(t *testing.T) {
+	s, _ := newTestServer(t, WithMaxRows(2))
+
+	result := callQuery(t, s, map[string]any{"sql": "SELECT * FROM users"})
+	require.False(t, result.IsError)
+	structured := result.StructuredContent.(QueryResult)
+	assert.Len(t, structured.Rows, 2)
+	assert.True(t, structured.Truncated)
+}
+
+func TestProvider_QueryErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]any
+		opts []Option
+	}{
+		{name: "missing sql", args: map[string]any{}},
+		{name: "modifying statement", args: map[string]any{"sql": "DELETE FROM users"}},
+		{name: "params not an array", args: map[string]any{"sql": "SELECT * FROM users", "params": "1"}},
+		{name: "failing query", args: map[string]any{"sql": "SELECT * FROM missing"}},
+		{name: "statement outside the allowlist", args: map[string]any{"sql": "WITH u AS (SELECT 1) SELECT * FROM users"}, opts: []Option{WithAllowedStatements("select")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestServer(t, tt.opts...)
+			result := callQuery(t, s, tt.args)
+			assert.True(t, result.IsError)
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package sqlprovider
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrStatementNotAllowed is returned, wrapped, for statements the query tool
+// refuses to run.
+var ErrStatementNotAllowed = errors.New("statement not allowed")
+
+// modifyingKeywords are the keywords of statements changing data, the
+// schema, permissions or the session, rejected anywhere in a query unless
+// they are explicitly allowed.
+var modifyingKeywords = []string{
+	"ALTER", "ATTACH", "CALL", "COPY", "CREATE", "DELETE", "DETACH", "DROP",
+	"EXEC", "EXECUTE", "GRANT", "INSERT", "INTO", "LOCK", "MERGE", "RENAME",
+	"REINDEX", "REVOKE", "SET", "TRUNCATE", "UPDATE", "UPSERT", "VACUUM",
+}
+
+// checkStatement checks that query is a single statement starting with one
+// of the allowed keywords and free of modifying keywords. Databases differ
+// on whether backslashes escape quotes in strings, so query must pass both
+// ways of reading it.
+func checkStatement(query string, allowed []string) error {
+	for _, backslashEscapes := range []bool{false, true} {
+		if err := checkWords(query, allowed, backslashEscapes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkWords(query string, allowed []string, backslashEscapes bool) error {
+	words, err := statementWords(query, backslashEscapes)
+	if err != nil {
+		return err
+	}
+	if len(words) == 0 {
+		return fmt.Errorf("%w: empty statement", ErrStatementNotAllowed)
+	}
+	if !slices.Contains(allowed, words[0]) {
+		return fmt.Errorf("%w: %s statements are not allowed", ErrStatementNotAllowed, words[0])
+	}
+	for _, word := range words[1:] {
+		if slices.Contains(modifyingKeywords, word) && !slices.Contains(allowed, word) {
+			return fmt.Errorf("%w: %s is not allowed", ErrStatementNotAllowed, word)
+		}
+	}
+	return nil
+}
+
+// statementWords returns the upper-cased words of query outside of string
+// literals, quoted identifiers and comments, reading "--" comments the way
+// MySQL does if backslashEscapes is set. It fails if query holds more
+// than one statement, an unterminated quote or an executable comment.
+func statementWords(query string, backslashEscapes bool) ([]string, error) {
+	var words []string
+	ended := false
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			i++
+			continue
+		case c == '-' && strings.HasPrefix(query[i:], "--") && (!backslashEscapes || dashCommentStart(query[i+2:])):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				return words, nil
+			}
+			i += end + 1
+			continue
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			// MySQL and MariaDB run the content of /*! */ and /*M! */
+			// comments as code
+			if strings.HasPrefix(query[i:], "/*!") || strings.HasPrefix(query[i:], "/*M!") {
+				return nil, fmt.Errorf("%w: executable comments are not allowed", ErrStatementNotAllowed)
+			}
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated comment", ErrStatementNotAllowed)
+			}
+			i += end + 4
+			continue
+		}
+
+		if ended {
+			return nil, fmt.Errorf("%w: only a single statement is allowed", ErrStatementNotAllowed)
+		}
+		switch {
+		case c == ';':
+			ended = true
+			i++
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			end, err := skipQuoted(query, i+1, closing, backslashEscapes)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+		case c == '$' && dollarTag(query[i:]) != "":
+			tag := dollarTag(query[i:])
+			end := strings.Index(query[i+len(tag):], tag)
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated %s quote", ErrStatementNotAllowed, tag)
+			}
+			i += len(tag) + end + len(tag)
+		case isWordStart(c):
+			start := i
+			for i < len(query) && isWordPart(query[i]) {
+				i++
+			}
+			words = append(words, strings.ToUpper(query[start:i]))
+		default:
+			i++
+		}
+	}
+	return words, nil
+}
+
+// dashCommentStart reports whether rest, following "--", makes it a comment
+// in MySQL, which requires a whitespace or control character after the
+// dashes: "1 --2" is "1 - (-2)".
+func dashCommentStart(rest string) bool {
+	return rest == "" || rest[0] <= ' ' || rest[0] == 0x7f
+}
+
+// skipQuoted returns the index following the quote closing at or after
+// start. Doubled closing quotes are escapes, and so are backslashes within
+// strings if backslashEscapes is set, as in MySQL.
+func skipQuoted(query string, start int, closing byte, backslashEscapes bool) (int, error) {
+	for i := start; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			if backslashEscapes && closing == '\'' {
+				i++
+			}
+		case closing:
+			if i+1 < len(query) && query[i+1] == closing {
+				i++
+				continue
+			}
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: unterminated quote", ErrStatementNotAllowed)
+}
+
+// dollarTag returns the PostgreSQL dollar quote tag, like $$ or $body$,
+// query starts with, or "" if it starts with a placeholder or anything else.
+func dollarTag(query string) string {
+	for i := 1; i < len(query); i++ {
+		switch c := query[i]; {
+		case c == '$':
+			return query[:i+1]
+		case i == 1 && (c < 'A' || c > 'Z') && (c < 'a' || c > 'z') && c != '_':
+			return ""
+		case !isWordPart(c):
+			return ""
+		}
+	}
+	return ""
+}
+
+func isWordStart(c byte) bool {
+	return c == '_' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+}
+
+func isWordPart(c byte) bool {
+	return isWordStart(c) || (c >= '0' && c <= '9')
+}
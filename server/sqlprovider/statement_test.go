@@ -0,0 +1,62 @@
+package sqlprovider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckStatement(t *testing.T) {
+	defaults := []string{"SELECT", "WITH"}
+	tests := []struct {
+		name    string
+		query   string
+		allowed []string
+		wantErr bool
+	}{
+		{name: "select", query: "select * from users where id = ?"},
+		{name: "trailing semicolon and comment", query: "SELECT 1; -- done"},
+		{name: "common table expression", query: "WITH t AS (SELECT 1) SELECT * FROM t"},
+		{name: "keywords in strings", query: "SELECT 'DROP TABLE users; DELETE' AS s"},
+		{name: "quoted identifiers", query: `SELECT "update", [delete], ` + "`insert`" + ` FROM t`},
+		{name: "keywords in comments", query: "SELECT 1 /* UPDATE */ -- DELETE\n"},
+		{name: "dollar quotes", query: "SELECT $body$ DROP TABLE x; $body$, $1"},
+		{name: "doubled quotes", query: "SELECT 'it''s; DROP TABLE x'"},
+		{name: "empty", query: " -- nothing", wantErr: true},
+		{name: "delete", query: "DELETE FROM users", wantErr: true},
+		{name: "multiple statements", query: "SELECT 1; SELECT 2", wantErr: true},
+		{name: "stacked modification", query: "SELECT 1; DROP TABLE users", wantErr: true},
+		{name: "modifying common table expression", query: "WITH d AS (DELETE FROM users RETURNING *) SELECT * FROM d", wantErr: true},
+		{name: "select into", query: "SELECT * INTO copy FROM users", wantErr: true},
+		{name: "row locks", query: "SELECT * FROM users FOR UPDATE", wantErr: true},
+		{name: "backslash ambiguity", query: `SELECT 'a\'; DROP TABLE users; --'`, wantErr: true},
+		{name: "unterminated quote", query: "SELECT 'abc", wantErr: true},
+		{name: "unterminated comment", query: "SELECT 1 /* ", wantErr: true},
+		{name: "executable comment", query: "SELECT 1 /*! INTO OUTFILE '/tmp/x' */", wantErr: true},
+		{name: "versioned executable comment", query: "SELECT 1 /*!50000 , (SELECT 1) */", wantErr: true},
+		{name: "executable comment statement", query: "/*! DELETE FROM users */", wantErr: true},
+		{name: "MariaDB executable comment", query: "SELECT 1 /*M!100100 INTO OUTFILE '/tmp/x' */", wantErr: true},
+		{name: "double dash without a space", query: "SELECT 1 --2 INTO OUTFILE '/tmp/x'", wantErr: true},
+		{name: "double dash without a space before a newline", query: "SELECT 1 --2\n"},
+		{name: "double dash followed by a tab", query: "SELECT 1 --\tINTO OUTFILE '/tmp/x'"},
+		{name: "optimizer hint", query: "SELECT /*+ MAX_EXECUTION_TIME(1000) */ * FROM t"},
+		{name: "executable comment marker in a string", query: "SELECT '/*! DELETE */'"},
+		{name: "explain not allowed by default", query: "EXPLAIN SELECT 1", wantErr: true},
+		{name: "explain allowed", query: "EXPLAIN SELECT 1", allowed: []string{"SELECT", "EXPLAIN"}},
+		{name: "allowed modifying keyword", query: "INSERT INTO logs VALUES (1)", allowed: []string{"INSERT", "INTO"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed := tt.allowed
+			if allowed == nil {
+				allowed = defaults
+			}
+			err := checkStatement(tt.query, allowed)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrStatementNotAllowed)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -1,10 +1,13 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -17,11 +20,14 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/util"
 )
 
 // sseSession represents an active SSE connection.
 type sseSession struct {
 	done                chan struct{}
+	closeOnce           sync.Once
+	onClose             func()      // called once when the session is closed
 	eventQueue          chan string // Channel for queuing events
 	sessionID           string
 	requestID           atomic.Int64
@@ -31,8 +37,11 @@ type sseSession struct {
 	tools               sync.Map     // stores session-specific tools
 	resources           sync.Map     // stores session-specific resources
 	resourceTemplates   sync.Map     // stores session-specific resource templates
+	prompts             sync.Map     // stores session-specific prompts
 	clientInfo          atomic.Value // stores session-specific client info
 	clientCapabilities  atomic.Value // stores session-specific client capabilities
+	protocolVersion     atomic.Value // stores the negotiated protocol version
+	values              sync.Map     // stores session-specific values
 }
 
 // SSEContextFunc is a function that takes an existing context and the current
@@ -51,6 +60,17 @@ func (s *sseSession) SessionID() string {
 	return s.sessionID
 }
 
+// Close ends the SSE stream of the session and removes the session from
+// its server.
+func (s *sseSession) Close() {
+	s.closeOnce.Do(func() {
+		if s.onClose != nil {
+			s.onClose()
+		}
+		close(s.done)
+	})
+}
+
 func (s *sseSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
 	return s.notificationChannel
 }
@@ -140,6 +160,27 @@ func (s *sseSession) SetSessionTools(tools map[string]ServerTool) {
 	}
 }
 
+func (s *sseSession) GetSessionPrompts() map[string]ServerPrompt {
+	prompts := make(map[string]ServerPrompt)
+	s.prompts.Range(func(key, value any) bool {
+		if prompt, ok := value.(ServerPrompt); ok {
+			prompts[key.(string)] = prompt
+		}
+		return true
+	})
+	return prompts
+}
+
+func (s *sseSession) SetSessionPrompts(prompts map[string]ServerPrompt) {
+	// Clear existing prompts
+	s.prompts.Clear()
+
+	// Set new prompts
+	for name, prompt := range prompts {
+		s.prompts.Store(name, prompt)
+	}
+}
+
 func (s *sseSession) GetClientInfo() mcp.Implementation {
 	if value := s.clientInfo.Load(); value != nil {
 		if clientInfo, ok := value.(mcp.Implementation); ok {
@@ -166,13 +207,71 @@ func (s *sseSession) GetClientCapabilities() mcp.ClientCapabilities {
 	return mcp.ClientCapabilities{}
 }
 
+func (s *sseSession) ClientInfo() mcp.Implementation {
+	return s.GetClientInfo()
+}
+
+func (s *sseSession) ClientCapabilities() mcp.ClientCapabilities {
+	return s.GetClientCapabilities()
+}
+
+func (s *sseSession) NegotiatedProtocolVersion() string {
+	if version, ok := s.protocolVersion.Load().(string); ok {
+		return version
+	}
+	return ""
+}
+
+func (s *sseSession) SetNegotiatedProtocolVersion(version string) {
+	s.protocolVersion.Store(version)
+}
+
+func (s *sseSession) Set(key string, value any) {
+	s.values.Store(key, value)
+}
+
+func (s *sseSession) Get(key string) (any, bool) {
+	return s.values.Load(key)
+}
+
+func (s *sseSession) Delete(key string) {
+	s.values.Delete(key)
+}
+
+// Ping queues a ping request on the event stream of the session.
+func (s *sseSession) Ping(ctx context.Context) error {
+	message := mcp.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      mcp.NewRequestID(s.requestID.Add(1)),
+		Request: mcp.Request{
+			Method: string(mcp.MethodPing),
+		},
+	}
+	messageBytes, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	select {
+	case s.eventQueue <- fmt.Sprintf("event: message\ndata:%s\n\n", messageBytes):
+		return nil
+	case <-s.done:
+		return ErrSessionClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 var (
 	_ ClientSession                = (*sseSession)(nil)
 	_ SessionWithTools             = (*sseSession)(nil)
 	_ SessionWithResources         = (*sseSession)(nil)
 	_ SessionWithResourceTemplates = (*sseSession)(nil)
+	_ SessionWithPrompts           = (*sseSession)(nil)
 	_ SessionWithLogging           = (*sseSession)(nil)
 	_ SessionWithClientInfo        = (*sseSession)(nil)
+	_ SessionWithPing              = (*sseSession)(nil)
+	_ SessionWithValues            = (*sseSession)(nil)
+	_ SessionWithHandshake         = (*sseSession)(nil)
 )
 
 // SSEServer implements a Server-Sent Events (SSE) based MCP server.
@@ -192,6 +291,14 @@ type SSEServer struct {
 
 	keepAlive         bool
 	keepAliveInterval time.Duration
+	keepAliveComments bool
+
+	tlsCertFile  string
+	tlsKeyFile   string
+	tlsConfig    *tls.Config
+	tlsClientCAs *x509.CertPool
+
+	logger util.Logger
 
 	mu sync.RWMutex
 }
@@ -309,6 +416,53 @@ func WithKeepAlive(keepAlive bool) SSEOption {
 	}
 }
 
+// WithKeepAliveComments makes the keep alive send SSE comment frames instead
+// of ping requests. Comments keep proxies from closing an idle stream without
+// requiring a response from the client, which ignores them. It enables the
+// keep alive if it is not enabled yet.
+func WithKeepAliveComments() SSEOption {
+	return func(s *SSEServer) {
+		s.keepAlive = true
+		s.keepAliveComments = true
+	}
+}
+
+// WithSSETLSCert sets the TLS certificate and key files for HTTPS support.
+// Both certFile and keyFile must be provided to enable TLS.
+func WithSSETLSCert(certFile, keyFile string) SSEOption {
+	return func(s *SSEServer) {
+		s.tlsCertFile = certFile
+		s.tlsKeyFile = keyFile
+	}
+}
+
+// WithSSETLSConfig serves HTTPS with config, for settings such as the
+// minimum version or certificates loaded from elsewhere than files.
+// Certificates set by WithSSETLSCert take precedence over those of config.
+func WithSSETLSConfig(config *tls.Config) SSEOption {
+	return func(s *SSEServer) {
+		s.tlsConfig = config.Clone()
+	}
+}
+
+// WithSSEClientCAs requires clients to present a certificate signed by one
+// of clientCAs, for mutual TLS. Handlers get the certificate with
+// ClientCertificateFromContext. It needs a certificate from WithSSETLSCert
+// or WithSSETLSConfig.
+func WithSSEClientCAs(clientCAs *x509.CertPool) SSEOption {
+	return func(s *SSEServer) {
+		s.tlsClientCAs = clientCAs
+	}
+}
+
+// WithSSELogger sets the logger for the server. It defaults to the logger
+// set with WithServerLogger, or to util.DefaultLogger() if there is none.
+func WithSSELogger(logger util.Logger) SSEOption {
+	return func(s *SSEServer) {
+		s.logger = logger
+	}
+}
+
 // WithSSEContextFunc sets a function that will be called to customise the context
 // to the server using the incoming request.
 func WithSSEContextFunc(fn SSEContextFunc) SSEOption {
@@ -332,6 +486,9 @@ func NewSSEServer(server *MCPServer, opts ...SSEOption) *SSEServer {
 	for _, opt := range opts {
 		opt(s)
 	}
+	if s.logger == nil {
+		s.logger = server.transportLogger()
+	}
 
 	return s
 }
@@ -364,7 +521,11 @@ func (s *SSEServer) Start(addr string) error {
 	srv := s.srv
 	s.mu.Unlock()
 
-	return srv.ListenAndServe()
+	config := s.tlsConfig
+	if s.tlsClientCAs != nil {
+		config = requireClientCertificates(config, s.tlsClientCAs)
+	}
+	return listenAndServeTLS(srv, s.tlsCertFile, s.tlsKeyFile, config)
 }
 
 // Shutdown gracefully stops the SSE server, closing all active sessions
@@ -377,7 +538,7 @@ func (s *SSEServer) Shutdown(ctx context.Context) error {
 	if srv != nil {
 		s.sessions.Range(func(key, value any) bool {
 			if session, ok := value.(*sseSession); ok {
-				close(session.done)
+				session.Close()
 			}
 			s.sessions.Delete(key)
 			return true
@@ -414,6 +575,7 @@ func (s *SSEServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 		sessionID:           sessionID,
 		notificationChannel: make(chan mcp.JSONRPCNotification, 100),
 	}
+	session.onClose = func() { s.sessions.CompareAndDelete(sessionID, session) }
 
 	s.sessions.Store(sessionID, session)
 	defer s.sessions.Delete(sessionID)
@@ -433,10 +595,10 @@ func (s *SSEServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 		for {
 			select {
 			case notification := <-session.notificationChannel:
-				eventData, err := json.Marshal(notification)
+				event, err := s.messageEvent(notification)
 				if err == nil {
 					select {
-					case session.eventQueue <- fmt.Sprintf("event: message\ndata: %s\n\n", eventData):
+					case session.eventQueue <- event:
 						// Event queued successfully
 					case <-session.done:
 						return
@@ -458,19 +620,15 @@ func (s *SSEServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 			for {
 				select {
 				case <-ticker.C:
-					message := mcp.JSONRPCRequest{
-						JSONRPC: "2.0",
-						ID:      mcp.NewRequestId(session.requestID.Add(1)),
-						Request: mcp.Request{
-							Method: "ping",
-						},
+					if s.keepAliveComments {
+						select {
+						case session.eventQueue <- ": ping\n\n":
+						case <-session.done:
+							return
+						}
+						continue
 					}
-					messageBytes, _ := json.Marshal(message)
-					pingMsg := fmt.Sprintf("event: message\ndata:%s\n\n", messageBytes)
-					select {
-					case session.eventQueue <- pingMsg:
-						// Message sent successfully
-					case <-session.done:
+					if err := session.Ping(r.Context()); err != nil {
 						return
 					}
 				case <-session.done:
@@ -498,7 +656,7 @@ func (s *SSEServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 			fmt.Fprint(w, event)
 			flusher.Flush()
 		case <-r.Context().Done():
-			close(session.done)
+			session.Close()
 			return
 		case <-session.done:
 			return
@@ -538,7 +696,9 @@ func (s *SSEServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 	}
 	sessionI, ok := s.sessions.Load(sessionID)
 	if !ok {
-		s.writeJSONRPCError(w, nil, mcp.INVALID_PARAMS, "Invalid session ID")
+		// The session never existed or has ended, for example because it
+		// expired, and its client has to reconnect
+		s.writeJSONRPCErrorStatus(w, http.StatusNotFound, nil, mcp.INVALID_PARAMS, "Invalid session ID")
 		return
 	}
 	session := sessionI.(*sseSession)
@@ -550,8 +710,12 @@ func (s *SSEServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Parse message as raw JSON
-	var rawMessage json.RawMessage
-	if err := json.NewDecoder(r.Body).Decode(&rawMessage); err != nil {
+	data, err := util.ReadAll(s.server.limitRequestBody(w, r.Body))
+	if err != nil && s.server.rejectOversizedBody(ctx, w, err) {
+		return
+	}
+	rawMessage := json.RawMessage(bytes.TrimSpace(data))
+	if err != nil || !json.Valid(rawMessage) {
 		s.writeJSONRPCError(w, nil, mcp.PARSE_ERROR, "Parse error")
 		return
 	}
@@ -564,7 +728,7 @@ func (s *SSEServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusAccepted)
 
 	// Create a new context for handling the message that will be canceled when the message handling is done
-	messageCtx := context.WithValue(detachedCtx, requestHeader, r.Header)
+	messageCtx := WithHTTPRequest(detachedCtx, r)
 	messageCtx, cancel := context.WithCancel(messageCtx)
 
 	go func(ctx context.Context) {
@@ -574,13 +738,11 @@ func (s *SSEServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 		response := s.server.HandleMessage(ctx, rawMessage)
 		// Only send response if there is one (not for notifications)
 		if response != nil {
-			var message string
-			if eventData, err := json.Marshal(response); err != nil {
+			message, err := s.messageEvent(response)
+			if err != nil {
 				// If there is an error marshalling the response, send a generic error response
-				log.Printf("failed to marshal response: %v", err)
+				s.logTransportError(ctx, "marshal response", err, "session_id", sessionID)
 				message = "event: message\ndata: {\"error\": \"internal error\",\"jsonrpc\": \"2.0\", \"id\": null}\n\n"
-			} else {
-				message = fmt.Sprintf("event: message\ndata: %s\n\n", eventData)
 			}
 
 			// Queue the event for sending via SSE
@@ -591,22 +753,48 @@ func (s *SSEServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 				// Session is closed, don't try to queue
 			default:
 				// Queue is full, log this situation
-				log.Printf("Event queue full for session %s", sessionID)
+				util.Log(ctx, s.logger, slog.LevelWarn, "response dropped: event queue full", "session_id", sessionID)
 			}
 		}
 	}(messageCtx)
 }
 
+// messageEvent returns the SSE message event carrying v, encoded with the
+// JSON codec of the server.
+func (s *SSEServer) messageEvent(v any) (string, error) {
+	buf := util.GetBuffer()
+	defer util.PutBuffer(buf)
+	buf.WriteString("event: message\ndata: ")
+	if err := s.server.encodeJSON(buf, v); err != nil {
+		return "", err
+	}
+	// The encoding ends with the newline ending the data line
+	buf.WriteByte('\n')
+	return buf.String(), nil
+}
+
 // writeJSONRPCError writes a JSON-RPC error response with the given error details.
 func (s *SSEServer) writeJSONRPCError(
 	w http.ResponseWriter,
 	id any,
 	code int,
 	message string,
+) {
+	s.writeJSONRPCErrorStatus(w, http.StatusBadRequest, id, code, message)
+}
+
+// writeJSONRPCErrorStatus writes a JSON-RPC error response with the given
+// HTTP status.
+func (s *SSEServer) writeJSONRPCErrorStatus(
+	w http.ResponseWriter,
+	status int,
+	id any,
+	code int,
+	message string,
 ) {
 	response := createErrorResponse(id, code, message)
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusBadRequest)
+	w.WriteHeader(status)
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		http.Error(
 			w,
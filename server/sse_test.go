@@ -881,6 +881,35 @@ func TestSSEServer(t *testing.T) {
 		}
 	})
 
+	t.Run("Keep alive sends comment frames", func(t *testing.T) {
+		mcpServer := NewMCPServer("test", "1.0.0")
+		testServer := NewTestServer(mcpServer,
+			WithKeepAliveInterval(20*time.Millisecond),
+			WithKeepAliveComments(),
+		)
+		defer testServer.Close()
+
+		sseResp, err := http.Get(fmt.Sprintf("%s/sse", testServer.URL))
+		if err != nil {
+			t.Fatalf("Failed to connect to SSE endpoint: %v", err)
+		}
+		defer sseResp.Body.Close()
+
+		reader := bufio.NewReader(sseResp.Body)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("Failed to read SSE event: %v", err)
+			}
+			if strings.HasPrefix(line, "event: message") {
+				t.Fatalf("Expected no ping requests, got %q", line)
+			}
+			if line == ": ping\n" {
+				break
+			}
+		}
+	})
+
 	t.Run("TestSSEHandlerWithDynamicMounting", func(t *testing.T) {
 		mcpServer := NewMCPServer("test", "1.0.0")
 		// MessageEndpointFunc that extracts tenant from the path using Go 1.22+ PathValue
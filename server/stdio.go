@@ -2,6 +2,7 @@ package server
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -14,6 +15,7 @@ import (
 	"syscall"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/util"
 )
 
 // StdioContextFunc is a function that takes an existing context and returns
@@ -31,20 +33,29 @@ type StdioServer struct {
 	contextFunc StdioContextFunc
 
 	// Thread-safe tool call processing
-	toolCallQueue  chan *toolCallWork
-	workerWg       sync.WaitGroup
-	workerPoolSize int
-	queueSize      int
-	writeMu        sync.Mutex // Protects concurrent writes
+	toolCallQueue      chan *toolCallWork
+	workerWg           sync.WaitGroup
+	workerPoolSize     int
+	queueSize          int
+	concurrentRequests bool
+	inFlight           sync.Map   // request ID -> *inFlightRequest
+	writeMu            sync.Mutex // Protects concurrent writes
 }
 
 // toolCallWork represents a queued tool call request
 type toolCallWork struct {
 	ctx     context.Context
+	id      mcp.RequestID
 	message json.RawMessage
 	writer  io.Writer
 }
 
+// inFlightRequest is a queued request that can be cancelled by the client
+type inFlightRequest struct {
+	cancel    context.CancelFunc
+	cancelled atomic.Bool
+}
+
 // StdioOption defines a function type for configuring StdioServer
 type StdioOption func(*StdioServer)
 
@@ -77,6 +88,22 @@ func WithWorkerPoolSize(size int) StdioOption {
 	}
 }
 
+// WithStdioConcurrency processes requests concurrently with a pool of n
+// workers, instead of only tool calls. Initialize requests, pings,
+// notifications and responses to server requests are still handled in the
+// order they are read, so a slow request never delays a ping or a
+// cancellation. A value of n below 2 keeps other requests sequential.
+func WithStdioConcurrency(n int) StdioOption {
+	return func(s *StdioServer) {
+		if n < 2 {
+			s.concurrentRequests = false
+			return
+		}
+		s.concurrentRequests = true
+		WithWorkerPoolSize(n)(s)
+	}
+}
+
 // WithQueueSize sets the size of the tool call queue
 func WithQueueSize(size int) StdioOption {
 	return func(s *StdioServer) {
@@ -95,15 +122,17 @@ type stdioSession struct {
 	notifications       chan mcp.JSONRPCNotification
 	initialized         atomic.Bool
 	loggingLevel        atomic.Value
-	clientInfo          atomic.Value                        // stores session-specific client info
-	clientCapabilities  atomic.Value                        // stores session-specific client capabilities
-	writer              io.Writer                           // for sending requests to client
-	requestID           atomic.Int64                        // for generating unique request IDs
-	mu                  sync.RWMutex                        // protects writer
-	pendingRequests     map[int64]chan *samplingResponse    // for tracking pending sampling requests
-	pendingElicitations map[int64]chan *elicitationResponse // for tracking pending elicitation requests
-	pendingRoots        map[int64]chan *rootsResponse       // for tracking pending list roots requests
-	pendingMu           sync.RWMutex                        // protects pendingRequests and pendingElicitations
+	clientInfo          atomic.Value                                // stores session-specific client info
+	clientCapabilities  atomic.Value                                // stores session-specific client capabilities
+	protocolVersion     atomic.Value                                // stores the negotiated protocol version
+	values              sync.Map                                    // stores session-specific values
+	writer              io.Writer                                   // for sending requests to client
+	requestID           atomic.Int64                                // for generating unique request IDs
+	mu                  sync.RWMutex                                // protects writer
+	pendingRequests     map[mcp.RequestID]chan *samplingResponse    // for tracking pending sampling requests
+	pendingElicitations map[mcp.RequestID]chan *elicitationResponse // for tracking pending elicitation requests
+	pendingRoots        map[mcp.RequestID]chan *rootsResponse       // for tracking pending list roots requests
+	pendingMu           sync.RWMutex                                // protects pendingRequests and pendingElicitations
 }
 
 // samplingResponse represents a response to a sampling request
@@ -168,6 +197,37 @@ func (s *stdioSession) SetClientCapabilities(clientCapabilities mcp.ClientCapabi
 	s.clientCapabilities.Store(clientCapabilities)
 }
 
+func (s *stdioSession) ClientInfo() mcp.Implementation {
+	return s.GetClientInfo()
+}
+
+func (s *stdioSession) ClientCapabilities() mcp.ClientCapabilities {
+	return s.GetClientCapabilities()
+}
+
+func (s *stdioSession) NegotiatedProtocolVersion() string {
+	if version, ok := s.protocolVersion.Load().(string); ok {
+		return version
+	}
+	return ""
+}
+
+func (s *stdioSession) SetNegotiatedProtocolVersion(version string) {
+	s.protocolVersion.Store(version)
+}
+
+func (s *stdioSession) Set(key string, value any) {
+	s.values.Store(key, value)
+}
+
+func (s *stdioSession) Get(key string) (any, bool) {
+	return s.values.Load(key)
+}
+
+func (s *stdioSession) Delete(key string) {
+	s.values.Delete(key)
+}
+
 func (s *stdioSession) SetLogLevel(level mcp.LoggingLevel) {
 	s.loggingLevel.Store(level)
 }
@@ -191,7 +251,7 @@ func (s *stdioSession) RequestSampling(ctx context.Context, request mcp.CreateMe
 	}
 
 	// Generate a unique request ID
-	id := s.requestID.Add(1)
+	id := mcp.NewRequestID(s.requestID.Add(1))
 
 	// Create a response channel for this request
 	responseChan := make(chan *samplingResponse, 1)
@@ -210,7 +270,7 @@ func (s *stdioSession) RequestSampling(ctx context.Context, request mcp.CreateMe
 	// Create the JSON-RPC request
 	jsonRPCRequest := struct {
 		JSONRPC string                  `json:"jsonrpc"`
-		ID      int64                   `json:"id"`
+		ID      mcp.RequestID           `json:"id"`
 		Method  string                  `json:"method"`
 		Params  mcp.CreateMessageParams `json:"params"`
 	}{
@@ -254,7 +314,7 @@ func (s *stdioSession) ListRoots(ctx context.Context, request mcp.ListRootsReque
 	}
 
 	// Generate a unique request ID
-	id := s.requestID.Add(1)
+	id := mcp.NewRequestID(s.requestID.Add(1))
 
 	// Create a response channel for this request
 	responseChan := make(chan *rootsResponse, 1)
@@ -272,9 +332,9 @@ func (s *stdioSession) ListRoots(ctx context.Context, request mcp.ListRootsReque
 
 	// Create the JSON-RPC request
 	jsonRPCRequest := struct {
-		JSONRPC string `json:"jsonrpc"`
-		ID      int64  `json:"id"`
-		Method  string `json:"method"`
+		JSONRPC string        `json:"jsonrpc"`
+		ID      mcp.RequestID `json:"id"`
+		Method  string        `json:"method"`
 	}{
 		JSONRPC: mcp.JSONRPC_VERSION,
 		ID:      id,
@@ -315,7 +375,7 @@ func (s *stdioSession) RequestElicitation(ctx context.Context, request mcp.Elici
 	}
 
 	// Generate a unique request ID
-	id := s.requestID.Add(1)
+	id := mcp.NewRequestID(s.requestID.Add(1))
 
 	// Create a response channel for this request
 	responseChan := make(chan *elicitationResponse, 1)
@@ -334,7 +394,7 @@ func (s *stdioSession) RequestElicitation(ctx context.Context, request mcp.Elici
 	// Create the JSON-RPC request
 	jsonRPCRequest := struct {
 		JSONRPC string                `json:"jsonrpc"`
-		ID      int64                 `json:"id"`
+		ID      mcp.RequestID         `json:"id"`
 		Method  string                `json:"method"`
 		Params  mcp.ElicitationParams `json:"params"`
 	}{
@@ -381,13 +441,15 @@ var (
 	_ SessionWithSampling    = (*stdioSession)(nil)
 	_ SessionWithElicitation = (*stdioSession)(nil)
 	_ SessionWithRoots       = (*stdioSession)(nil)
+	_ SessionWithValues      = (*stdioSession)(nil)
+	_ SessionWithHandshake   = (*stdioSession)(nil)
 )
 
 var stdioSessionInstance = stdioSession{
 	notifications:       make(chan mcp.JSONRPCNotification, 100),
-	pendingRequests:     make(map[int64]chan *samplingResponse),
-	pendingElicitations: make(map[int64]chan *elicitationResponse),
-	pendingRoots:        make(map[int64]chan *rootsResponse),
+	pendingRequests:     make(map[mcp.RequestID]chan *samplingResponse),
+	pendingElicitations: make(map[mcp.RequestID]chan *elicitationResponse),
+	pendingRoots:        make(map[mcp.RequestID]chan *rootsResponse),
 }
 
 // NewStdioServer creates a new stdio server wrapper around an MCPServer.
@@ -476,40 +538,78 @@ func (s *StdioServer) toolCallWorker(ctx context.Context) {
 				// Channel closed, exit worker
 				return
 			}
-			// Process the tool call
-			response := s.server.HandleMessage(work.ctx, work.message)
-			if response != nil {
-				if err := s.writeResponse(response, work.writer); err != nil {
-					s.errLogger.Printf("Error writing tool response: %v", err)
-				}
-			}
+			s.processQueuedRequest(work)
 		case <-ctx.Done():
 			return
 		}
 	}
 }
 
+// processQueuedRequest handles a request taken from the queue. The request
+// can be cancelled with a notifications/cancelled notification while it
+// runs, in which case no response is written.
+func (s *StdioServer) processQueuedRequest(work *toolCallWork) {
+	ctx, cancel := context.WithCancel(work.ctx)
+	defer cancel()
+	request := &inFlightRequest{cancel: cancel}
+	s.inFlight.Store(work.id, request)
+	defer s.inFlight.CompareAndDelete(work.id, request)
+
+	response := s.server.HandleMessage(ctx, work.message)
+	if response == nil || request.cancelled.Load() {
+		return
+	}
+	if err := s.writeResponse(response, work.writer); err != nil {
+		s.errLogger.Printf("Error writing tool response: %v", err)
+	}
+}
+
+// cancelRequest cancels the queued request a notifications/cancelled
+// notification refers to.
+func (s *StdioServer) cancelRequest(rawMessage json.RawMessage) {
+	var notification mcp.CancelledNotification
+	if err := json.Unmarshal(rawMessage, &notification); err != nil {
+		return
+	}
+	if value, ok := s.inFlight.Load(notification.Params.RequestId); ok {
+		request := value.(*inFlightRequest)
+		request.cancelled.Store(true)
+		request.cancel()
+	}
+}
+
+// queued reports whether a request is processed by the worker pool rather
+// than in the order it was read. Requests the scheduler of WithScheduler
+// may hold back are always queued, so that waiting for a slot never stops
+// the reading of the responses and cancellations that free one.
+func (s *StdioServer) queued(ctx context.Context, method string, message []byte) bool {
+	if method == string(mcp.MethodToolsCall) || s.server.scheduler.gates(ctx, mcp.MCPMethod(method), message) {
+		return true
+	}
+	return s.concurrentRequests && method != string(mcp.MethodInitialize) && method != string(mcp.MethodPing)
+}
+
 // readNextLine reads a single line from the input reader in a context-aware manner.
 // It uses channels to make the read operation cancellable via context.
 // Returns the read line and any error encountered. If the context is cancelled,
-// returns an empty string and the context's error. EOF is returned when the input
+// returns an empty line and the context's error. EOF is returned when the input
 // stream is closed.
-func (s *StdioServer) readNextLine(ctx context.Context, reader *bufio.Reader) (string, error) {
+func (s *StdioServer) readNextLine(ctx context.Context, reader *bufio.Reader) ([]byte, error) {
 	type result struct {
-		line string
+		line []byte
 		err  error
 	}
 
 	resultCh := make(chan result, 1)
 
 	go func() {
-		line, err := reader.ReadString('\n')
+		line, err := reader.ReadBytes('\n')
 		resultCh <- result{line: line, err: err}
 	}()
 
 	select {
 	case <-ctx.Done():
-		return "", nil
+		return nil, nil
 	case res := <-resultCh:
 		return res.line, res.err
 	}
@@ -567,7 +667,7 @@ func (s *StdioServer) Listen(
 // Returns an error if there are issues with message processing or response writing.
 func (s *StdioServer) processMessage(
 	ctx context.Context,
-	line string,
+	line []byte,
 	writer io.Writer,
 ) error {
 	// If line is empty, likely due to ctx cancellation
@@ -575,46 +675,60 @@ func (s *StdioServer) processMessage(
 		return nil
 	}
 
-	// Parse the message as raw JSON
-	var rawMessage json.RawMessage
-	if err := json.Unmarshal([]byte(line), &rawMessage); err != nil {
+	// The line is the message: it is only checked to be JSON here, not
+	// decoded, since HandleMessage decodes it
+	rawMessage := json.RawMessage(bytes.Trim(line, " \t\r\n"))
+	if !json.Valid(rawMessage) {
 		response := createErrorResponse(nil, mcp.PARSE_ERROR, "Parse error")
 		return s.writeResponse(response, writer)
 	}
 
-	// Check if this is a response to a sampling request
-	if s.handleSamplingResponse(rawMessage) {
-		return nil
-	}
-
-	// Check if this is a response to an elicitation request
-	if s.handleElicitationResponse(rawMessage) {
-		return nil
-	}
+	// The message is routed by its method and ID, which are read without
+	// decoding the params
+	envelope := scanMessage(rawMessage)
 
-	// Check if this is a response to an list roots request
-	if s.handleListRootsResponse(rawMessage) {
-		return nil
+	// Check if this is a response to a sampling, elicitation or list roots
+	// request
+	if envelope.isResponse() {
+		if s.handleSamplingResponse(rawMessage) ||
+			s.handleElicitationResponse(rawMessage) ||
+			s.handleListRootsResponse(rawMessage) {
+			return nil
+		}
 	}
 
-	// Check if this is a tool call that might need sampling (and thus should be processed concurrently)
-	var baseMessage struct {
-		Method string `json:"method"`
+	// Tool calls, which might need sampling, and all other requests when
+	// WithStdioConcurrency is set are processed concurrently
+	id := envelope.requestID()
+	if envelope.isNotification() && envelope.method == mcp.MethodNotificationCancelled {
+		s.cancelRequest(rawMessage)
 	}
-	if json.Unmarshal(rawMessage, &baseMessage) == nil && baseMessage.Method == "tools/call" {
-		// Queue tool calls for processing by workers
-		select {
-		case s.toolCallQueue <- &toolCallWork{
+	if !id.IsNil() && s.queued(ctx, envelope.method, rawMessage) {
+		// Queue requests for processing by workers
+		work := &toolCallWork{
 			ctx:     ctx,
+			id:      id,
 			message: rawMessage,
 			writer:  writer,
-		}:
+		}
+		select {
+		case s.toolCallQueue <- work:
 			return nil
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
+			if s.server.scheduler.gates(ctx, mcp.MCPMethod(envelope.method), rawMessage) {
+				// The scheduler bounds the requests running at once, and
+				// the request may wait for a slot
+				s.workerWg.Add(1)
+				go func() {
+					defer s.workerWg.Done()
+					s.processQueuedRequest(work)
+				}()
+				return nil
+			}
 			// Queue is full, process synchronously as fallback
-			s.errLogger.Printf("Tool call queue full, processing synchronously")
+			s.errLogger.Printf("Request queue full, processing synchronously")
 			response := s.server.HandleMessage(ctx, rawMessage)
 			if response != nil {
 				return s.writeResponse(response, writer)
@@ -647,7 +761,7 @@ func (s *stdioSession) handleSamplingResponse(rawMessage json.RawMessage) bool {
 	// Try to parse as a JSON-RPC response
 	var response struct {
 		JSONRPC string          `json:"jsonrpc"`
-		ID      json.Number     `json:"id"`
+		ID      mcp.RequestID   `json:"id"`
 		Result  json.RawMessage `json:"result,omitempty"`
 		Error   *struct {
 			Code    int    `json:"code"`
@@ -658,15 +772,13 @@ func (s *stdioSession) handleSamplingResponse(rawMessage json.RawMessage) bool {
 	if err := json.Unmarshal(rawMessage, &response); err != nil {
 		return false
 	}
-	// Parse the ID as int64
-	idInt64, err := response.ID.Int64()
-	if err != nil || (response.Result == nil && response.Error == nil) {
+	if response.ID.IsNil() || (response.Result == nil && response.Error == nil) {
 		return false
 	}
 
 	// Look for a pending request with this ID
 	s.pendingMu.RLock()
-	responseChan, exists := s.pendingRequests[idInt64]
+	responseChan, exists := s.pendingRequests[response.ID]
 	s.pendingMu.RUnlock()
 
 	if !exists {
@@ -717,7 +829,7 @@ func (s *stdioSession) handleElicitationResponse(rawMessage json.RawMessage) boo
 	// Try to parse as a JSON-RPC response
 	var response struct {
 		JSONRPC string          `json:"jsonrpc"`
-		ID      json.Number     `json:"id"`
+		ID      mcp.RequestID   `json:"id"`
 		Result  json.RawMessage `json:"result,omitempty"`
 		Error   *struct {
 			Code    int    `json:"code"`
@@ -728,15 +840,13 @@ func (s *stdioSession) handleElicitationResponse(rawMessage json.RawMessage) boo
 	if err := json.Unmarshal(rawMessage, &response); err != nil {
 		return false
 	}
-	// Parse the ID as int64
-	id, err := response.ID.Int64()
-	if err != nil || (response.Result == nil && response.Error == nil) {
+	if response.ID.IsNil() || (response.Result == nil && response.Error == nil) {
 		return false
 	}
 
 	// Check if we have a pending elicitation request with this ID
 	s.pendingMu.RLock()
-	responseChan, exists := s.pendingElicitations[id]
+	responseChan, exists := s.pendingElicitations[response.ID]
 	s.pendingMu.RUnlock()
 
 	if !exists {
@@ -778,7 +888,7 @@ func (s *stdioSession) handleListRootsResponse(rawMessage json.RawMessage) bool
 	// Try to parse as a JSON-RPC response
 	var response struct {
 		JSONRPC string          `json:"jsonrpc"`
-		ID      json.Number     `json:"id"`
+		ID      mcp.RequestID   `json:"id"`
 		Result  json.RawMessage `json:"result,omitempty"`
 		Error   *struct {
 			Code    int    `json:"code"`
@@ -789,15 +899,13 @@ func (s *stdioSession) handleListRootsResponse(rawMessage json.RawMessage) bool
 	if err := json.Unmarshal(rawMessage, &response); err != nil {
 		return false
 	}
-	// Parse the ID as int64
-	id, err := response.ID.Int64()
-	if err != nil || (response.Result == nil && response.Error == nil) {
+	if response.ID.IsNil() || (response.Result == nil && response.Error == nil) {
 		return false
 	}
 
 	// Check if we have a pending list root request with this ID
 	s.pendingMu.RLock()
-	responseChan, exists := s.pendingRoots[id]
+	responseChan, exists := s.pendingRoots[response.ID]
 	s.pendingMu.RUnlock()
 
 	if !exists {
@@ -834,8 +942,11 @@ func (s *StdioServer) writeResponse(
 	response mcp.JSONRPCMessage,
 	writer io.Writer,
 ) error {
-	responseBytes, err := json.Marshal(response)
-	if err != nil {
+	// Encode into a pooled buffer, so that the message and its newline are
+	// written at once without allocating them
+	buf := util.GetBuffer()
+	defer util.PutBuffer(buf)
+	if err := s.server.encodeJSON(buf, response); err != nil {
 		return err
 	}
 
@@ -843,8 +954,7 @@ func (s *StdioServer) writeResponse(
 	s.writeMu.Lock()
 	defer s.writeMu.Unlock()
 
-	// Write response followed by newline
-	if _, err := fmt.Fprintf(writer, "%s\n", responseBytes); err != nil {
+	if _, err := writer.Write(buf.Bytes()); err != nil {
 		return err
 	}
 
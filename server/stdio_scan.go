@@ -0,0 +1,165 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// envelope holds the members of a JSON-RPC message the stdio server routes
+// it by, found by scanMessage without decoding the rest of the message.
+type envelope struct {
+	method    string
+	id        json.RawMessage
+	hasResult bool
+	hasError  bool
+}
+
+// isResponse reports whether the message is a response, which has an ID
+// and a result or an error.
+func (e envelope) isResponse() bool {
+	return e.id != nil && string(e.id) != "null" && (e.hasResult || e.hasError)
+}
+
+// isNotification reports whether the message has a method and no ID; a
+// message with a null ID is not a notification.
+func (e envelope) isNotification() bool {
+	return e.method != "" && e.id == nil
+}
+
+// requestID returns the ID of the message, which is nil if the message has
+// none or its ID is not a string or a number.
+func (e envelope) requestID() mcp.RequestID {
+	var id mcp.RequestID
+	if e.id != nil && id.UnmarshalJSON(e.id) != nil {
+		return mcp.RequestID{}
+	}
+	return id
+}
+
+// scanMessage returns the envelope of data, which must be valid JSON, by
+// scanning the members of the top-level object: their values are skipped
+// rather than decoded, except for the method. Member names are matched
+// case-insensitively, as encoding/json does, and later members win.
+func scanMessage(data []byte) envelope {
+	var e envelope
+	i := skipJSONSpace(data, 0)
+	if i >= len(data) || data[i] != '{' {
+		return e
+	}
+	i++
+	for {
+		i = skipJSONSpace(data, i)
+		if i >= len(data) || data[i] != '"' {
+			return e
+		}
+		keyEnd := skipJSONString(data, i)
+		if keyEnd-1 <= i {
+			return e
+		}
+		name := data[i+1 : keyEnd-1]
+		if bytes.IndexByte(name, '\\') >= 0 {
+			name = []byte(scanString(data[i:keyEnd]))
+		}
+		i = skipJSONSpace(data, keyEnd)
+		if i >= len(data) || data[i] != ':' {
+			return e
+		}
+		i = skipJSONSpace(data, i+1)
+		valueEnd := skipJSONValue(data, i)
+		value := data[i:valueEnd]
+
+		switch {
+		case bytes.EqualFold(name, []byte("method")):
+			e.method = scanString(value)
+		case bytes.EqualFold(name, []byte("id")):
+			e.id = value
+		case bytes.EqualFold(name, []byte("result")):
+			e.hasResult = true
+		case bytes.EqualFold(name, []byte("error")):
+			e.hasError = string(value) != "null"
+		}
+
+		i = skipJSONSpace(data, valueEnd)
+		if i >= len(data) || data[i] != ',' {
+			return e
+		}
+		i++
+	}
+}
+
+// scanString returns the string value is the JSON encoding of, or "" if it
+// is not a string. Only strings with escapes or invalid UTF-8 are decoded.
+func scanString(value []byte) string {
+	if len(value) < 2 || value[0] != '"' {
+		return ""
+	}
+	if bytes.IndexByte(value, '\\') < 0 && utf8.Valid(value) {
+		return string(value[1 : len(value)-1])
+	}
+	var s string
+	if json.Unmarshal(value, &s) != nil {
+		return ""
+	}
+	return s
+}
+
+func skipJSONSpace(data []byte, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\t' || data[i] == '\r' || data[i] == '\n') {
+		i++
+	}
+	return i
+}
+
+// skipJSONString returns the offset following the string starting at
+// data[i].
+func skipJSONString(data []byte, i int) int {
+	for i++; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(data)
+}
+
+// skipJSONValue returns the offset following the value starting at data[i].
+func skipJSONValue(data []byte, i int) int {
+	if i >= len(data) {
+		return i
+	}
+	switch data[i] {
+	case '"':
+		return skipJSONString(data, i)
+	case '{', '[':
+		depth := 0
+		for i < len(data) {
+			switch data[i] {
+			case '"':
+				i = skipJSONString(data, i)
+				continue
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return i + 1
+				}
+			}
+			i++
+		}
+		return i
+	default:
+		for ; i < len(data); i++ {
+			switch data[i] {
+			case ',', '}', ']', ' ', '\t', '\r', '\n':
+				return i
+			}
+		}
+		return i
+	}
+}
@@ -0,0 +1,175 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestScanMessage(t *testing.T) {
+	tests := []struct {
+		name             string
+		message          string
+		wantMethod       string
+		wantID           mcp.RequestID
+		wantResponse     bool
+		wantNotification bool
+	}{
+		{
+			name:       "request",
+			message:    `{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"echo","arguments":{"text":"}\"{"}}}`,
+			wantMethod: "tools/call",
+			wantID:     mcp.NewRequestID(int64(7)),
+		},
+		{
+			name:       "members after nested values",
+			message:    ` { "params" : {"a":[1,{"b":"]"}]} , "method" : "ping" , "id" : "x" } `,
+			wantMethod: "ping",
+			wantID:     mcp.NewRequestID("x"),
+		},
+		{
+			name:             "notification",
+			message:          `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":7}}`,
+			wantMethod:       "notifications/cancelled",
+			wantNotification: true,
+		},
+		{
+			name:       "null ID",
+			message:    `{"jsonrpc":"2.0","id":null,"method":"ping"}`,
+			wantMethod: "ping",
+		},
+		{
+			name:         "result",
+			message:      `{"jsonrpc":"2.0","id":1,"result":{}}`,
+			wantID:       mcp.NewRequestID(int64(1)),
+			wantResponse: true,
+		},
+		{
+			name:         "error",
+			message:      `{"jsonrpc":"2.0","id":1,"error":{"code":-32603,"message":"failed"}}`,
+			wantID:       mcp.NewRequestID(int64(1)),
+			wantResponse: true,
+		},
+		{
+			name:    "null error",
+			message: `{"jsonrpc":"2.0","id":1,"error":null}`,
+			wantID:  mcp.NewRequestID(int64(1)),
+		},
+		{
+			name:       "escaped and differently cased names",
+			message:    `{"METHOD":"tools\/list","id":2}`,
+			wantMethod: "tools/list",
+			wantID:     mcp.NewRequestID(int64(2)),
+		},
+		{
+			name:             "later members win",
+			message:          `{"method":"ping","method":"tools/call"}`,
+			wantMethod:       "tools/call",
+			wantNotification: true,
+		},
+		{
+			name:    "method not a string",
+			message: `{"method":1,"id":{"a":1}}`,
+		},
+		{
+			name:    "not an object",
+			message: `[{"method":"ping","id":1}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envelope := scanMessage([]byte(tt.message))
+			assert.Equal(t, tt.wantMethod, envelope.method)
+			assert.Equal(t, tt.wantID, envelope.requestID())
+			assert.Equal(t, tt.wantResponse, envelope.isResponse())
+			assert.Equal(t, tt.wantNotification, envelope.isNotification())
+		})
+	}
+}
+
+// FuzzScanMessage checks that scanMessage routes messages as decoding them
+// with encoding/json does.
+func FuzzScanMessage(f *testing.F) {
+	for _, seed := range []string{
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"text":"hi"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":2}}`,
+		`{"jsonrpc":"2.0","id":"a","result":{"content":[]}}`,
+		`{"jsonrpc":"2.0","id":3,"error":{"code":1,"message":"\"]}"}}`,
+		`{"Method":"ping","ID":4}`,
+	} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if !json.Valid(data) {
+			return
+		}
+		envelope := scanMessage(data)
+
+		var message struct {
+			Method string          `json:"method"`
+			ID     mcp.RequestID   `json:"id"`
+			Result json.RawMessage `json:"result"`
+			Error  *struct{}       `json:"error"`
+		}
+		if json.Unmarshal(data, &message) != nil {
+			return
+		}
+		assert.Equal(t, message.Method, envelope.method)
+		assert.True(t, message.ID.Equal(envelope.requestID()), "ID %v, scanned %v", message.ID, envelope.requestID())
+		assert.Equal(t, !message.ID.IsNil() && (message.Result != nil || message.Error != nil), envelope.isResponse())
+	})
+}
+
+// BenchmarkScanMessage compares routing a tools/call request as the stdio
+// server did, decoding it as a response to each kind of request the server
+// sends and then decoding its method and ID, with scanMessage.
+func BenchmarkScanMessage(b *testing.B) {
+	message := []byte(`{"jsonrpc":"2.0","id":42,"method":"tools/call","params":{"name":"write","arguments":{"path":"/tmp/notes.txt",` +
+		`"text":"Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore magna aliqua."}}}`)
+
+	b.Run("Unmarshal", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var rawMessage json.RawMessage
+			if err := json.Unmarshal(message, &rawMessage); err != nil {
+				b.Fatal(err)
+			}
+			for range 3 {
+				var response struct {
+					JSONRPC string          `json:"jsonrpc"`
+					ID      mcp.RequestID   `json:"id"`
+					Result  json.RawMessage `json:"result,omitempty"`
+					Error   *struct {
+						Code    int    `json:"code"`
+						Message string `json:"message"`
+					} `json:"error,omitempty"`
+				}
+				if err := json.Unmarshal(rawMessage, &response); err != nil {
+					b.Fatal(err)
+				}
+			}
+			var baseMessage struct {
+				Method string        `json:"method"`
+				ID     mcp.RequestID `json:"id,omitempty"`
+			}
+			if err := json.Unmarshal(rawMessage, &baseMessage); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Scan", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if !json.Valid(message) {
+				b.Fatal("invalid message")
+			}
+			envelope := scanMessage(message)
+			if envelope.isResponse() || envelope.requestID().IsNil() {
+				b.Fatal("not a request")
+			}
+		}
+	})
+}
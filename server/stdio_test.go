@@ -484,3 +484,202 @@ func TestStdioServer(t *testing.T) {
 		}
 	})
 }
+
+// stdioTestConn runs a StdioServer on pipes and exposes its responses.
+type stdioTestConn struct {
+	t         *testing.T
+	stdin     *io.PipeWriter
+	responses chan map[string]any
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+func startStdioTestServer(t *testing.T, mcpServer *MCPServer, opts ...StdioOption) *stdioTestConn {
+	stdinReader, stdinWriter := io.Pipe()
+	stdoutReader, stdoutWriter := io.Pipe()
+
+	stdioServer := NewStdioServer(mcpServer)
+	stdioServer.SetErrorLogger(log.New(io.Discard, "", 0))
+	for _, opt := range opts {
+		opt(stdioServer)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	conn := &stdioTestConn{
+		t:         t,
+		stdin:     stdinWriter,
+		responses: make(chan map[string]any, 10),
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	go func() {
+		_ = stdioServer.Listen(ctx, stdinReader, stdoutWriter)
+		stdoutWriter.Close()
+		close(conn.done)
+	}()
+	go func() {
+		scanner := bufio.NewScanner(stdoutReader)
+		for scanner.Scan() {
+			var response map[string]any
+			if err := json.Unmarshal(scanner.Bytes(), &response); err == nil {
+				conn.responses <- response
+			}
+		}
+	}()
+	return conn
+}
+
+func (c *stdioTestConn) send(message string) {
+	c.t.Helper()
+	if _, err := c.stdin.Write([]byte(message + "\n")); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func (c *stdioTestConn) nextResponseID() float64 {
+	c.t.Helper()
+	select {
+	case response := <-c.responses:
+		id, _ := response["id"].(float64)
+		return id
+	case <-time.After(2 * time.Second):
+		c.t.Fatal("timed out waiting for a response")
+		return 0
+	}
+}
+
+func (c *stdioTestConn) close() {
+	c.cancel()
+	c.stdin.Close()
+	<-c.done
+}
+
+func TestStdioServer_Concurrency(t *testing.T) {
+	t.Run("Slow requests do not block pings", func(t *testing.T) {
+		release := make(chan struct{})
+		mcpServer := NewMCPServer("test", "1.0.0")
+		mcpServer.AddResource(mcp.NewResource("slow://resource", "slow"), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			<-release
+			return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: "done"}}, nil
+		})
+
+		conn := startStdioTestServer(t, mcpServer, WithStdioConcurrency(4))
+		defer conn.close()
+
+		conn.send(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","clientInfo":{"name":"test","version":"1.0.0"}}}`)
+		if id := conn.nextResponseID(); id != 1 {
+			t.Fatalf("expected initialize response, got id %v", id)
+		}
+
+		conn.send(`{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"slow://resource"}}`)
+		conn.send(`{"jsonrpc":"2.0","id":3,"method":"ping"}`)
+		if id := conn.nextResponseID(); id != 3 {
+			t.Errorf("expected the ping to be answered first, got id %v", id)
+		}
+		close(release)
+		if id := conn.nextResponseID(); id != 2 {
+			t.Errorf("expected the slow read to complete, got id %v", id)
+		}
+	})
+
+	t.Run("Cancelled requests are stopped without a response", func(t *testing.T) {
+		started := make(chan struct{})
+		stopped := make(chan error, 1)
+		mcpServer := NewMCPServer("test", "1.0.0")
+		mcpServer.AddTool(mcp.NewTool("slow"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			close(started)
+			<-ctx.Done()
+			stopped <- ctx.Err()
+			return mcp.NewToolResultText("cancelled"), nil
+		})
+
+		conn := startStdioTestServer(t, mcpServer)
+		defer conn.close()
+
+		conn.send(`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"slow"}}`)
+		<-started
+		conn.send(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":7,"reason":"user abort"}}`)
+
+		select {
+		case err := <-stopped:
+			if err != context.Canceled {
+				t.Errorf("expected context.Canceled, got %v", err)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("the tool call was not cancelled")
+		}
+
+		conn.send(`{"jsonrpc":"2.0","id":8,"method":"ping"}`)
+		if id := conn.nextResponseID(); id != 8 {
+			t.Errorf("expected no response for the cancelled request, got id %v", id)
+		}
+	})
+
+	t.Run("Requests waiting for the scheduler do not block the reading of responses", func(t *testing.T) {
+		mcpServer := NewMCPServer("test", "1.0.0", WithScheduler(SchedulerConfig{MaxConcurrent: 1}))
+		mcpServer.AddTool(mcp.NewTool("ask"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := mcpServer.RequestSampling(ctx, mcp.CreateMessageRequest{
+				CreateMessageParams: mcp.CreateMessageParams{
+					Messages:  []mcp.SamplingMessage{{Role: mcp.RoleUser, Content: mcp.NewTextContent("question")}},
+					MaxTokens: 10,
+				},
+			})
+			if err != nil {
+				return nil, err
+			}
+			return mcp.NewToolResultText(result.Content.(mcp.TextContent).Text), nil
+		})
+
+		conn := startStdioTestServer(t, mcpServer)
+		defer conn.close()
+
+		conn.send(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{"sampling":{}},"clientInfo":{"name":"test","version":"1.0.0"}}}`)
+		if id := conn.nextResponseID(); id != 1 {
+			t.Fatalf("expected initialize response, got id %v", id)
+		}
+		conn.send(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+
+		// The tool call holds the only slot until its sampling request is
+		// answered, and the list waits for it.
+		conn.send(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"ask"}}`)
+		var samplingID any
+		select {
+		case request := <-conn.responses:
+			if request["method"] != string(mcp.MethodSamplingCreateMessage) {
+				t.Fatalf("expected a sampling request, got %v", request)
+			}
+			samplingID = request["id"]
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for the sampling request")
+		}
+		conn.send(`{"jsonrpc":"2.0","id":3,"method":"tools/list"}`)
+
+		answer, err := json.Marshal(map[string]any{
+			"jsonrpc": "2.0",
+			"id":      samplingID,
+			"result":  map[string]any{"role": "assistant", "content": map[string]any{"type": "text", "text": "answer"}, "model": "test"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn.send(string(answer))
+
+		for _, want := range []float64{2, 3} {
+			if id := conn.nextResponseID(); id != want {
+				t.Errorf("expected response %v, got id %v", want, id)
+			}
+		}
+	})
+
+	t.Run("Concurrency option", func(t *testing.T) {
+		stdioServer := NewStdioServer(NewMCPServer("test", "1.0.0"))
+		WithStdioConcurrency(8)(stdioServer)
+		if !stdioServer.concurrentRequests || stdioServer.workerPoolSize != 8 {
+			t.Errorf("expected 8 concurrent workers, got %d (concurrent: %v)", stdioServer.workerPoolSize, stdioServer.concurrentRequests)
+		}
+		WithStdioConcurrency(1)(stdioServer)
+		if stdioServer.concurrentRequests {
+			t.Error("expected sequential requests for a concurrency of 1")
+		}
+	})
+}
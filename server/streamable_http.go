@@ -3,14 +3,16 @@ package server
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"maps"
 	"mime"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -100,6 +102,16 @@ func WithHeartbeatInterval(interval time.Duration) StreamableHTTPOption {
 	}
 }
 
+// WithHeartbeatComments makes the heartbeats configured by
+// WithHeartbeatInterval SSE comment frames instead of ping requests. Clients
+// ignore comments, so they keep the connection alive without requiring a
+// response.
+func WithHeartbeatComments() StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		s.listenHeartbeatComments = true
+	}
+}
+
 // WithDisableStreaming prevents the server from responding to GET requests with
 // a streaming response. Instead, it will respond with a 405 Method Not Allowed status.
 // This can be useful in scenarios where streaming is not desired or supported.
@@ -128,7 +140,8 @@ func WithStreamableHTTPServer(srv *http.Server) StreamableHTTPOption {
 	}
 }
 
-// WithLogger sets the logger for the server
+// WithLogger sets the logger for the server. It defaults to the logger set
+// with WithServerLogger, or to util.DefaultLogger() if there is none.
 func WithLogger(logger util.Logger) StreamableHTTPOption {
 	return func(s *StreamableHTTPServer) {
 		s.logger = logger
@@ -144,6 +157,25 @@ func WithTLSCert(certFile, keyFile string) StreamableHTTPOption {
 	}
 }
 
+// WithTLSConfig serves HTTPS with config, for settings such as the minimum
+// version or certificates loaded from elsewhere than files. Certificates
+// set by WithTLSCert take precedence over those of config.
+func WithTLSConfig(config *tls.Config) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		s.tlsConfig = config.Clone()
+	}
+}
+
+// WithClientCAs requires clients to present a certificate signed by one of
+// clientCAs, for mutual TLS. Handlers get the certificate with
+// ClientCertificateFromContext. It needs a certificate from WithTLSCert or
+// WithTLSConfig.
+func WithClientCAs(clientCAs *x509.CertPool) StreamableHTTPOption {
+	return func(s *StreamableHTTPServer) {
+		s.tlsClientCAs = clientCAs
+	}
+}
+
 // StreamableHTTPServer implements a Streamable-http based MCP server.
 // It communicates with clients over HTTP protocol, supporting both direct HTTP responses, and SSE streams.
 // https://modelcontextprotocol.io/specification/2025-03-26/basic/transports#streamable-http
@@ -172,6 +204,7 @@ type StreamableHTTPServer struct {
 	sessionTools             *sessionToolsStore
 	sessionResources         *sessionResourcesStore
 	sessionResourceTemplates *sessionResourceTemplatesStore
+	sessionPrompts           *sessionPromptsStore
 	sessionRequestIDs        sync.Map // sessionId --> last requestID(*atomic.Int64)
 	activeSessions           sync.Map // sessionId --> *streamableHttpSession (for sampling responses)
 
@@ -182,12 +215,23 @@ type StreamableHTTPServer struct {
 	contextFunc              HTTPContextFunc
 	sessionIdManagerResolver SessionIdManagerResolver
 	listenHeartbeatInterval  time.Duration
+	listenHeartbeatComments  bool
 	logger                   util.Logger
 	sessionLogLevels         *sessionLogLevelsStore
+	sessionValues            *sessionValuesStore
 	disableStreaming         bool
+	authFunc                 AuthFunc
+	authResourceMetadataURL  string
+	nodeID                   string
+	sessionRouter            SessionRouter
+	compressors              []util.Compressor
+	compressMinSize          int
 
 	tlsCertFile string
 	tlsKeyFile  string
+	tlsConfig   *tls.Config
+	// tlsClientCAs is set by WithClientCAs.
+	tlsClientCAs *x509.CertPool
 }
 
 // NewStreamableHTTPServer creates a new streamable-http server instance
@@ -196,22 +240,36 @@ func NewStreamableHTTPServer(server *MCPServer, opts ...StreamableHTTPOption) *S
 		server:                   server,
 		sessionTools:             newSessionToolsStore(),
 		sessionLogLevels:         newSessionLogLevelsStore(),
+		sessionValues:            newSessionValuesStore(),
 		endpointPath:             "/mcp",
 		sessionIdManagerResolver: NewDefaultSessionIdManagerResolver(&StatelessGeneratingSessionIdManager{}),
-		logger:                   util.DefaultLogger(),
 		sessionResources:         newSessionResourcesStore(),
 		sessionResourceTemplates: newSessionResourceTemplatesStore(),
+		sessionPrompts:           newSessionPromptsStore(),
 	}
 
 	// Apply all options
 	for _, opt := range opts {
 		opt(s)
 	}
+	if s.logger == nil {
+		s.logger = server.transportLogger()
+	}
+	if s.nodeID != "" {
+		s.sessionIdManagerResolver = &nodeSessionIdManagerResolver{nodeID: s.nodeID, resolver: s.sessionIdManagerResolver}
+	}
 	return s
 }
 
 // ServeHTTP implements the http.Handler interface.
 func (s *StreamableHTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r = s.authenticate(w, r); r == nil {
+		return
+	}
+	if s.routeSession(w, r) {
+		return
+	}
+
 	switch r.Method {
 	case http.MethodPost:
 		s.handlePost(w, r)
@@ -247,20 +305,11 @@ func (s *StreamableHTTPServer) Start(addr string) error {
 	srv := s.httpServer
 	s.mu.Unlock()
 
-	if s.tlsCertFile != "" || s.tlsKeyFile != "" {
-		if s.tlsCertFile == "" || s.tlsKeyFile == "" {
-			return fmt.Errorf("both TLS cert and key must be provided")
-		}
-		if _, err := os.Stat(s.tlsCertFile); err != nil {
-			return fmt.Errorf("failed to find TLS certificate file: %w", err)
-		}
-		if _, err := os.Stat(s.tlsKeyFile); err != nil {
-			return fmt.Errorf("failed to find TLS key file: %w", err)
-		}
-		return srv.ListenAndServeTLS(s.tlsCertFile, s.tlsKeyFile)
+	config := s.tlsConfig
+	if s.tlsClientCAs != nil {
+		config = requireClientCertificates(config, s.tlsClientCAs)
 	}
-
-	return srv.ListenAndServe()
+	return listenAndServeTLS(srv, s.tlsCertFile, s.tlsKeyFile, config)
 }
 
 // Shutdown gracefully stops the server, closing all active sessions
@@ -291,8 +340,15 @@ func (s *StreamableHTTPServer) handlePost(w http.ResponseWriter, r *http.Request
 	}
 
 	// Check the request body is valid json, meanwhile, get the request Method
-	rawData, err := io.ReadAll(r.Body)
+	body, ok := s.decompressRequestBody(w, r)
+	if !ok {
+		return
+	}
+	rawData, err := util.ReadAll(s.server.limitRequestBody(w, body))
 	if err != nil {
+		if s.server.rejectOversizedBody(r.Context(), w, err) {
+			return
+		}
 		s.writeJSONRPCError(w, nil, mcp.PARSE_ERROR, fmt.Sprintf("read request body error: %v", err))
 		return
 	}
@@ -303,7 +359,7 @@ func (s *StreamableHTTPServer) handlePost(w http.ResponseWriter, r *http.Request
 		Error  json.RawMessage `json:"error,omitempty"`
 		Method mcp.MCPMethod   `json:"method,omitempty"`
 	}
-	if err := json.Unmarshal(rawData, &jsonMessage); err != nil {
+	if err := s.server.jsonCodec.Unmarshal(rawData, &jsonMessage); err != nil {
 		s.writeJSONRPCError(w, nil, mcp.PARSE_ERROR, "request body is not valid json")
 		return
 	}
@@ -325,7 +381,7 @@ func (s *StreamableHTTPServer) handlePost(w http.ResponseWriter, r *http.Request
 	// Handle sampling responses separately
 	if isSamplingResponse {
 		if err := s.handleSamplingResponse(w, r, jsonMessage); err != nil {
-			s.logger.Errorf("Failed to handle sampling response: %v", err)
+			s.logTransportError(r.Context(), "handle sampling response", err)
 			http.Error(w, "Failed to handle sampling response", http.StatusInternalServerError)
 		}
 		return
@@ -376,7 +432,7 @@ func (s *StreamableHTTPServer) handlePost(w http.ResponseWriter, r *http.Request
 
 	// Create ephemeral session if no persistent session exists
 	if session == nil {
-		session = newStreamableHttpSession(sessionID, s.sessionTools, s.sessionResources, s.sessionResourceTemplates, s.sessionLogLevels)
+		session = s.newSession(sessionID)
 	}
 
 	// Set the client context before handling the message
@@ -390,7 +446,7 @@ func (s *StreamableHTTPServer) handlePost(w http.ResponseWriter, r *http.Request
 	upgradedHeader := false
 	done := make(chan struct{})
 
-	ctx = context.WithValue(ctx, requestHeader, r.Header)
+	ctx = WithHTTPRequest(ctx, r)
 	go func() {
 		for {
 			select {
@@ -419,9 +475,9 @@ func (s *StreamableHTTPServer) handlePost(w http.ResponseWriter, r *http.Request
 						w.WriteHeader(http.StatusOK)
 						upgradedHeader = true
 					}
-					err := writeSSEEvent(w, nt)
+					err := s.writeSSEEvent(w, nt)
 					if err != nil {
-						s.logger.Errorf("Failed to write SSE event: %v", err)
+						s.logTransportError(r.Context(), "write SSE event", err, "session_id", sessionID)
 						return
 					}
 				}()
@@ -458,8 +514,8 @@ func (s *StreamableHTTPServer) handlePost(w http.ResponseWriter, r *http.Request
 			w.WriteHeader(http.StatusOK)
 			upgradedHeader = true
 		}
-		if err := writeSSEEvent(w, response); err != nil {
-			s.logger.Errorf("Failed to write final SSE response event: %v", err)
+		if err := s.writeSSEEvent(w, response); err != nil {
+			s.logTransportError(r.Context(), "write SSE response", err, "session_id", sessionID)
 		}
 	} else {
 		w.Header().Set("Content-Type", "application/json")
@@ -467,10 +523,8 @@ func (s *StreamableHTTPServer) handlePost(w http.ResponseWriter, r *http.Request
 			// send the session ID back to the client
 			w.Header().Set(HeaderKeySessionID, sessionID)
 		}
-		w.WriteHeader(http.StatusOK)
-		err := json.NewEncoder(w).Encode(response)
-		if err != nil {
-			s.logger.Errorf("Failed to write response: %v", err)
+		if err := s.writeJSONResponse(w, r, http.StatusOK, response); err != nil {
+			s.logTransportError(r.Context(), "write response", err, "session_id", sessionID)
 		}
 	}
 
@@ -482,7 +536,7 @@ func (s *StreamableHTTPServer) handlePost(w http.ResponseWriter, r *http.Request
 			s.activeSessions.Store(sessionID, session)
 			// Register the session with the MCPServer for notification support
 			if err := s.server.RegisterSession(ctx, session); err != nil {
-				s.logger.Errorf("Failed to register POST session: %v", err)
+				s.logTransportError(ctx, "register session", err, "session_id", sessionID)
 				s.activeSessions.Delete(sessionID)
 				// Don't fail the request, just log the error
 			}
@@ -494,7 +548,7 @@ func (s *StreamableHTTPServer) handleGet(w http.ResponseWriter, r *http.Request)
 	// get request is for listening to notifications
 	// https://modelcontextprotocol.io/specification/2025-03-26/basic/transports#listening-for-messages-from-the-server
 	if s.disableStreaming {
-		s.logger.Infof("Rejected GET request: streaming is disabled (session: %s)", r.Header.Get(HeaderKeySessionID))
+		util.Log(r.Context(), s.logger, slog.LevelInfo, "rejected GET request: streaming is disabled", "session_id", r.Header.Get(HeaderKeySessionID))
 		http.Error(w, "Streaming is disabled on this server", http.StatusMethodNotAllowed)
 		return
 	}
@@ -511,7 +565,7 @@ func (s *StreamableHTTPServer) handleGet(w http.ResponseWriter, r *http.Request)
 	// Get or create session atomically to prevent TOCTOU races
 	// where concurrent GETs could both create and register duplicate sessions
 	var session *streamableHttpSession
-	newSession := newStreamableHttpSession(sessionID, s.sessionTools, s.sessionResources, s.sessionResourceTemplates, s.sessionLogLevels)
+	newSession := s.newSession(sessionID)
 	actual, loaded := s.activeSessions.LoadOrStore(sessionID, newSession)
 	session = actual.(*streamableHttpSession)
 
@@ -557,7 +611,7 @@ func (s *StreamableHTTPServer) handleGet(w http.ResponseWriter, r *http.Request)
 				// Send sampling request to client via SSE
 				jsonrpcRequest := mcp.JSONRPCRequest{
 					JSONRPC: "2.0",
-					ID:      mcp.NewRequestId(samplingReq.requestID),
+					ID:      samplingReq.requestID,
 					Request: mcp.Request{
 						Method: string(mcp.MethodSamplingCreateMessage),
 					},
@@ -572,7 +626,7 @@ func (s *StreamableHTTPServer) handleGet(w http.ResponseWriter, r *http.Request)
 				// Send elicitation request to client via SSE
 				jsonrpcRequest := mcp.JSONRPCRequest{
 					JSONRPC: "2.0",
-					ID:      mcp.NewRequestId(elicitationReq.requestID),
+					ID:      elicitationReq.requestID,
 					Request: mcp.Request{
 						Method: string(mcp.MethodElicitationCreate),
 					},
@@ -587,7 +641,7 @@ func (s *StreamableHTTPServer) handleGet(w http.ResponseWriter, r *http.Request)
 				// Send list roots request to client via SSE
 				jsonrpcRequest := mcp.JSONRPCRequest{
 					JSONRPC: "2.0",
-					ID:      mcp.NewRequestId(rootsReq.requestID),
+					ID:      rootsReq.requestID,
 					Request: mcp.Request{
 						Method: string(mcp.MethodListRoots),
 					},
@@ -611,12 +665,15 @@ func (s *StreamableHTTPServer) handleGet(w http.ResponseWriter, r *http.Request)
 			for {
 				select {
 				case <-ticker.C:
-					message := mcp.JSONRPCRequest{
-						JSONRPC: "2.0",
-						ID:      mcp.NewRequestId(s.nextRequestID(sessionID)),
-						Request: mcp.Request{
-							Method: "ping",
-						},
+					var message any = sseComment("ping")
+					if !s.listenHeartbeatComments {
+						message = mcp.JSONRPCRequest{
+							JSONRPC: "2.0",
+							ID:      mcp.NewRequestID(s.nextRequestID(sessionID)),
+							Request: mcp.Request{
+								Method: "ping",
+							},
+						}
 					}
 					select {
 					case writeChan <- message:
@@ -640,11 +697,13 @@ func (s *StreamableHTTPServer) handleGet(w http.ResponseWriter, r *http.Request)
 			if data == nil {
 				continue
 			}
-			if err := writeSSEEvent(w, data); err != nil {
-				s.logger.Errorf("Failed to write SSE event: %v", err)
+			if err := s.writeSSEEvent(w, data); err != nil {
+				s.logTransportError(r.Context(), "write SSE event", err, "session_id", sessionID)
 				return
 			}
 			flusher.Flush()
+		case <-session.closed:
+			return
 		case <-r.Context().Done():
 			return
 		}
@@ -669,20 +728,34 @@ func (s *StreamableHTTPServer) handleDelete(w http.ResponseWriter, r *http.Reque
 	s.sessionTools.delete(sessionID)
 	s.sessionResources.delete(sessionID)
 	s.sessionResourceTemplates.delete(sessionID)
+	s.sessionPrompts.delete(sessionID)
 	s.sessionLogLevels.delete(sessionID)
+	s.sessionValues.deleteSession(sessionID)
 	// remove current session's requstID information
 	s.sessionRequestIDs.Delete(sessionID)
 
 	w.WriteHeader(http.StatusOK)
 }
 
-func writeSSEEvent(w io.Writer, data any) error {
-	jsonData, err := json.Marshal(data)
-	if err != nil {
+// sseComment is an SSE comment frame, written by writeSSEEvent as is.
+type sseComment string
+
+func (s *StreamableHTTPServer) writeSSEEvent(w io.Writer, data any) error {
+	if comment, ok := data.(sseComment); ok {
+		if _, err := fmt.Fprintf(w, ": %s\n\n", comment); err != nil {
+			return fmt.Errorf("failed to write SSE comment: %w", err)
+		}
+		return nil
+	}
+	buf := util.GetBuffer()
+	defer util.PutBuffer(buf)
+	buf.WriteString("event: message\ndata: ")
+	if err := s.server.encodeJSON(buf, data); err != nil {
 		return fmt.Errorf("failed to marshal data: %w", err)
 	}
-	_, err = fmt.Fprintf(w, "event: message\ndata: %s\n\n", jsonData)
-	if err != nil {
+	// The encoding ends with the newline ending the data line
+	buf.WriteByte('\n')
+	if _, err := w.Write(buf.Bytes()); err != nil {
 		return fmt.Errorf("failed to write SSE event: %w", err)
 	}
 	return nil
@@ -714,12 +787,16 @@ func (s *StreamableHTTPServer) handleSamplingResponse(w http.ResponseWriter, r *
 		return fmt.Errorf("session terminated")
 	}
 
-	// Parse the request ID
-	var requestID int64
+	// Parse the request ID. The server only issues integer IDs.
+	var requestID mcp.RequestID
 	if err := json.Unmarshal(responseMessage.ID, &requestID); err != nil {
 		http.Error(w, "Invalid request ID in sampling response", http.StatusBadRequest)
 		return err
 	}
+	if _, ok := requestID.Value().(int64); !ok {
+		http.Error(w, "Invalid request ID in sampling response", http.StatusBadRequest)
+		return fmt.Errorf("invalid request ID %s in sampling response", responseMessage.ID)
+	}
 
 	// Create the sampling response item
 	response := samplingResponseItem{
@@ -748,7 +825,7 @@ func (s *StreamableHTTPServer) handleSamplingResponse(w http.ResponseWriter, r *
 	// Find the corresponding session and deliver the response
 	// The response is delivered to the specific session identified by sessionID
 	if err := s.deliverSamplingResponse(sessionID, response); err != nil {
-		s.logger.Errorf("Failed to deliver sampling response: %v", err)
+		s.logTransportError(r.Context(), "deliver sampling response", err, "session_id", sessionID)
 		http.Error(w, "Failed to deliver response", http.StatusInternalServerError)
 		return err
 	}
@@ -774,21 +851,21 @@ func (s *StreamableHTTPServer) deliverSamplingResponse(sessionID string, respons
 	// Look up the dedicated response channel for this specific request
 	responseChannelInterface, exists := session.samplingRequests.Load(response.requestID)
 	if !exists {
-		return fmt.Errorf("no pending request found for session %s, request %d", sessionID, response.requestID)
+		return fmt.Errorf("no pending request found for session %s, request %v", sessionID, response.requestID.Value())
 	}
 
 	responseChan, ok := responseChannelInterface.(chan samplingResponseItem)
 	if !ok {
-		return fmt.Errorf("invalid response channel type for session %s, request %d", sessionID, response.requestID)
+		return fmt.Errorf("invalid response channel type for session %s, request %v", sessionID, response.requestID.Value())
 	}
 
 	// Attempt to deliver the response with timeout to prevent indefinite blocking
 	select {
 	case responseChan <- response:
-		s.logger.Infof("Delivered sampling response for session %s, request %d", sessionID, response.requestID)
+		util.Log(context.Background(), s.logger, slog.LevelDebug, "delivered sampling response", "session_id", sessionID, "request_id", response.requestID.Value())
 		return nil
 	default:
-		return fmt.Errorf("failed to deliver sampling response for session %s, request %d: channel full or blocked", sessionID, response.requestID)
+		return fmt.Errorf("failed to deliver sampling response for session %s, request %v: channel full or blocked", sessionID, response.requestID.Value())
 	}
 }
 
@@ -804,7 +881,7 @@ func (s *StreamableHTTPServer) writeJSONRPCError(
 	w.WriteHeader(http.StatusBadRequest)
 	err := json.NewEncoder(w).Encode(response)
 	if err != nil {
-		s.logger.Errorf("Failed to write JSONRPCError: %v", err)
+		s.logTransportError(context.Background(), "write error response", err)
 	}
 }
 
@@ -849,6 +926,48 @@ func (s *sessionLogLevelsStore) delete(sessionID string) {
 	delete(s.logs, sessionID)
 }
 
+// sessionValuesStore keeps the values of sessions across the ephemeral
+// sessions of their requests. Values set with SessionWithValues have string
+// keys; the session keeps its own state under unexported key types.
+type sessionValuesStore struct {
+	mu     sync.RWMutex
+	values map[string]map[any]any // sessionID -> key -> value
+}
+
+func newSessionValuesStore() *sessionValuesStore {
+	return &sessionValuesStore{
+		values: make(map[string]map[any]any),
+	}
+}
+
+func (s *sessionValuesStore) get(sessionID string, key any) (any, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.values[sessionID][key]
+	return value, ok
+}
+
+func (s *sessionValuesStore) set(sessionID string, key any, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.values[sessionID] == nil {
+		s.values[sessionID] = make(map[any]any)
+	}
+	s.values[sessionID][key] = value
+}
+
+func (s *sessionValuesStore) delete(sessionID string, key any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values[sessionID], key)
+}
+
+func (s *sessionValuesStore) deleteSession(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, sessionID)
+}
+
 type sessionResourcesStore struct {
 	mu        sync.RWMutex
 	resources map[string]map[string]ServerResource // sessionID -> resourceURI -> resource
@@ -948,29 +1067,62 @@ func (s *sessionToolsStore) delete(sessionID string) {
 	delete(s.tools, sessionID)
 }
 
+type sessionPromptsStore struct {
+	mu      sync.RWMutex
+	prompts map[string]map[string]ServerPrompt // sessionID -> promptName -> prompt
+}
+
+func newSessionPromptsStore() *sessionPromptsStore {
+	return &sessionPromptsStore{
+		prompts: make(map[string]map[string]ServerPrompt),
+	}
+}
+
+func (s *sessionPromptsStore) get(sessionID string) map[string]ServerPrompt {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	cloned := make(map[string]ServerPrompt, len(s.prompts[sessionID]))
+	maps.Copy(cloned, s.prompts[sessionID])
+	return cloned
+}
+
+func (s *sessionPromptsStore) set(sessionID string, prompts map[string]ServerPrompt) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cloned := make(map[string]ServerPrompt, len(prompts))
+	maps.Copy(cloned, prompts)
+	s.prompts[sessionID] = cloned
+}
+
+func (s *sessionPromptsStore) delete(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.prompts, sessionID)
+}
+
 // Sampling support types for HTTP transport
 type samplingRequestItem struct {
-	requestID int64
+	requestID mcp.RequestID
 	request   mcp.CreateMessageRequest
 	response  chan samplingResponseItem
 }
 
 type samplingResponseItem struct {
-	requestID int64
+	requestID mcp.RequestID
 	result    json.RawMessage
 	err       error
 }
 
 // Elicitation support types for HTTP transport
 type elicitationRequestItem struct {
-	requestID int64
+	requestID mcp.RequestID
 	request   mcp.ElicitationRequest
 	response  chan samplingResponseItem
 }
 
 // Roots support types for HTTP transport
 type rootsRequestItem struct {
-	requestID int64
+	requestID mcp.RequestID
 	request   mcp.ListRootsRequest
 	response  chan samplingResponseItem
 }
@@ -984,10 +1136,10 @@ type streamableHttpSession struct {
 	tools               *sessionToolsStore
 	resources           *sessionResourcesStore
 	resourceTemplates   *sessionResourceTemplatesStore
+	prompts             *sessionPromptsStore
 	upgradeToSSE        atomic.Bool
 	logLevels           *sessionLogLevelsStore
-	clientInfo          atomic.Value // stores session-specific client info
-	clientCapabilities  atomic.Value // stores session-specific client capabilities
+	values              *sessionValuesStore
 
 	// Sampling support for bidirectional communication
 	samplingRequestChan    chan samplingRequestItem    // server -> client sampling requests
@@ -996,6 +1148,10 @@ type streamableHttpSession struct {
 
 	samplingRequests sync.Map     // requestID -> pending sampling request context
 	requestIDCounter atomic.Int64 // for generating unique request IDs
+
+	closed    chan struct{} // closed when the session is closed
+	closeOnce sync.Once
+	onClose   func() // called once when the session is closed
 }
 
 func newStreamableHttpSession(sessionID string, toolStore *sessionToolsStore, resourcesStore *sessionResourcesStore, templatesStore *sessionResourceTemplatesStore, levels *sessionLogLevelsStore) *streamableHttpSession {
@@ -1006,17 +1162,43 @@ func newStreamableHttpSession(sessionID string, toolStore *sessionToolsStore, re
 		resources:              resourcesStore,
 		resourceTemplates:      templatesStore,
 		logLevels:              levels,
+		values:                 newSessionValuesStore(),
 		samplingRequestChan:    make(chan samplingRequestItem, 10),
 		elicitationRequestChan: make(chan elicitationRequestItem, 10),
 		rootsRequestChan:       make(chan rootsRequestItem, 10),
+		closed:                 make(chan struct{}),
 	}
 	return s
 }
 
+// newSession returns a session backed by the session stores of the server.
+func (s *StreamableHTTPServer) newSession(sessionID string) *streamableHttpSession {
+	session := newStreamableHttpSession(sessionID, s.sessionTools, s.sessionResources, s.sessionResourceTemplates, s.sessionLogLevels)
+	session.prompts = s.sessionPrompts
+	// Sessions without an ID, of stateless servers, cannot be told apart
+	// across requests and keep their values to themselves.
+	if sessionID != "" {
+		session.values = s.sessionValues
+	}
+	session.onClose = func() { s.activeSessions.CompareAndDelete(sessionID, session) }
+	return session
+}
+
 func (s *streamableHttpSession) SessionID() string {
 	return s.sessionID
 }
 
+// Close ends the GET stream of the session, if it has one, and removes the
+// session from its server.
+func (s *streamableHttpSession) Close() {
+	s.closeOnce.Do(func() {
+		if s.onClose != nil {
+			s.onClose()
+		}
+		close(s.closed)
+	})
+}
+
 func (s *streamableHttpSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
 	return s.notificationChannel
 }
@@ -1065,38 +1247,84 @@ func (s *streamableHttpSession) SetSessionResourceTemplates(templates map[string
 	s.resourceTemplates.set(s.sessionID, templates)
 }
 
+func (s *streamableHttpSession) GetSessionPrompts() map[string]ServerPrompt {
+	return s.prompts.get(s.sessionID)
+}
+
+func (s *streamableHttpSession) SetSessionPrompts(prompts map[string]ServerPrompt) {
+	s.prompts.set(s.sessionID, prompts)
+}
+
+// The handshake of a session is kept in its values, so that the ephemeral
+// sessions of later requests see it.
+type (
+	clientInfoKey         struct{}
+	clientCapabilitiesKey struct{}
+	protocolVersionKey    struct{}
+)
+
 func (s *streamableHttpSession) GetClientInfo() mcp.Implementation {
-	if value := s.clientInfo.Load(); value != nil {
-		if clientInfo, ok := value.(mcp.Implementation); ok {
-			return clientInfo
-		}
+	if value, ok := s.values.get(s.sessionID, clientInfoKey{}); ok {
+		return value.(mcp.Implementation)
 	}
 	return mcp.Implementation{}
 }
 
 func (s *streamableHttpSession) SetClientInfo(clientInfo mcp.Implementation) {
-	s.clientInfo.Store(clientInfo)
+	s.values.set(s.sessionID, clientInfoKey{}, clientInfo)
 }
 
 func (s *streamableHttpSession) GetClientCapabilities() mcp.ClientCapabilities {
-	if value := s.clientCapabilities.Load(); value != nil {
-		if clientCapabilities, ok := value.(mcp.ClientCapabilities); ok {
-			return clientCapabilities
-		}
+	if value, ok := s.values.get(s.sessionID, clientCapabilitiesKey{}); ok {
+		return value.(mcp.ClientCapabilities)
 	}
 	return mcp.ClientCapabilities{}
 }
 
 func (s *streamableHttpSession) SetClientCapabilities(clientCapabilities mcp.ClientCapabilities) {
-	s.clientCapabilities.Store(clientCapabilities)
+	s.values.set(s.sessionID, clientCapabilitiesKey{}, clientCapabilities)
+}
+
+func (s *streamableHttpSession) ClientInfo() mcp.Implementation {
+	return s.GetClientInfo()
+}
+
+func (s *streamableHttpSession) ClientCapabilities() mcp.ClientCapabilities {
+	return s.GetClientCapabilities()
+}
+
+func (s *streamableHttpSession) NegotiatedProtocolVersion() string {
+	if value, ok := s.values.get(s.sessionID, protocolVersionKey{}); ok {
+		return value.(string)
+	}
+	return ""
+}
+
+func (s *streamableHttpSession) SetNegotiatedProtocolVersion(version string) {
+	s.values.set(s.sessionID, protocolVersionKey{}, version)
+}
+
+func (s *streamableHttpSession) Set(key string, value any) {
+	s.values.set(s.sessionID, key, value)
+}
+
+func (s *streamableHttpSession) Get(key string) (any, bool) {
+	return s.values.get(s.sessionID, key)
+}
+
+func (s *streamableHttpSession) Delete(key string) {
+	s.values.delete(s.sessionID, key)
 }
 
 var (
 	_ SessionWithTools             = (*streamableHttpSession)(nil)
 	_ SessionWithResources         = (*streamableHttpSession)(nil)
 	_ SessionWithResourceTemplates = (*streamableHttpSession)(nil)
+	_ SessionWithPrompts           = (*streamableHttpSession)(nil)
 	_ SessionWithLogging           = (*streamableHttpSession)(nil)
 	_ SessionWithClientInfo        = (*streamableHttpSession)(nil)
+	_ SessionWithValues            = (*streamableHttpSession)(nil)
+	_ SessionWithHandshake         = (*streamableHttpSession)(nil)
 )
 
 func (s *streamableHttpSession) UpgradeToSSEWhenReceiveNotification() {
@@ -1108,7 +1336,7 @@ var _ SessionWithStreamableHTTPConfig = (*streamableHttpSession)(nil)
 // RequestSampling implements SessionWithSampling interface for HTTP transport
 func (s *streamableHttpSession) RequestSampling(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
 	// Generate unique request ID
-	requestID := s.requestIDCounter.Add(1)
+	requestID := mcp.NewRequestID(s.requestIDCounter.Add(1))
 
 	// Create response channel for this specific request
 	responseChan := make(chan samplingResponseItem, 1)
@@ -1165,7 +1393,7 @@ func (s *streamableHttpSession) RequestSampling(ctx context.Context, request mcp
 // It sends a list roots request to the client via SSE and waits for the response.
 func (s *streamableHttpSession) ListRoots(ctx context.Context, request mcp.ListRootsRequest) (*mcp.ListRootsResult, error) {
 	// Generate unique request ID
-	requestID := s.requestIDCounter.Add(1)
+	requestID := mcp.NewRequestID(s.requestIDCounter.Add(1))
 
 	// Create response channel for this specific request
 	responseChan := make(chan samplingResponseItem, 1)
@@ -1210,7 +1438,7 @@ func (s *streamableHttpSession) ListRoots(ctx context.Context, request mcp.ListR
 // RequestElicitation implements SessionWithElicitation interface for HTTP transport
 func (s *streamableHttpSession) RequestElicitation(ctx context.Context, request mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
 	// Generate unique request ID
-	requestID := s.requestIDCounter.Add(1)
+	requestID := mcp.NewRequestID(s.requestIDCounter.Add(1))
 
 	// Create response channel for this specific request
 	responseChan := make(chan samplingResponseItem, 1)
@@ -547,6 +547,38 @@ func TestStreamableHTTP_GET(t *testing.T) {
 	}
 }
 
+func TestStreamableHTTP_GET_HeartbeatComments(t *testing.T) {
+	mcpServer := NewMCPServer("test-mcp-server", "1.0")
+	server := NewTestStreamableHTTPServer(mcpServer,
+		WithHeartbeatInterval(20*time.Millisecond),
+		WithHeartbeatComments(),
+	)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "text/event-stream")
+
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("Failed to send message: %v", err)
+	}
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		t.Fatalf("Failed to read heartbeat: %v", err)
+	}
+	if line != ": ping\n" {
+		t.Errorf("Expected a comment heartbeat, got %q", line)
+	}
+}
+
 func TestStreamableHTTP_HttpHandler(t *testing.T) {
 	t.Run("Works with custom mux", func(t *testing.T) {
 		mcpServer := NewMCPServer("test", "1.0.0")
@@ -1171,6 +1203,68 @@ func TestStreamableHTTP_HeaderPassthrough(t *testing.T) {
 	}
 }
 
+func TestStreamableHTTP_HTTPRequestFromContext(t *testing.T) {
+	mcpServer := NewMCPServer("test-mcp-server", "1.0")
+
+	var received struct {
+		ok         bool
+		userAgent  string
+		remoteAddr string
+		tls        bool
+	}
+	mcpServer.AddTool(
+		mcp.NewTool("inspect-request"),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			r, ok := HTTPRequestFromContext(ctx)
+			received.ok = ok
+			if ok {
+				received.userAgent = r.Header.Get("User-Agent")
+				received.remoteAddr = r.RemoteAddr
+				received.tls = r.TLS != nil
+			}
+			return mcp.NewToolResultText("ok"), nil
+		},
+	)
+
+	server := httptest.NewTLSServer(NewStreamableHTTPServer(mcpServer, WithStateLess(true)))
+	defer server.Close()
+
+	toolBody, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params": map[string]any{
+			"name": "inspect-request",
+		},
+	})
+	req, _ := http.NewRequest("POST", server.URL, bytes.NewReader(toolBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "test-agent")
+
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("Failed to send message: %v", err)
+	}
+	resp.Body.Close()
+
+	if !received.ok {
+		t.Fatal("Expected the HTTP request in the handler context")
+	}
+	if received.userAgent != "test-agent" {
+		t.Errorf("Expected User-Agent 'test-agent', got '%s'", received.userAgent)
+	}
+	if !strings.HasPrefix(received.remoteAddr, "127.0.0.1:") {
+		t.Errorf("Expected a loopback remote address, got '%s'", received.remoteAddr)
+	}
+	if !received.tls {
+		t.Error("Expected the TLS connection state")
+	}
+
+	if _, ok := HTTPRequestFromContext(context.Background()); ok {
+		t.Error("Expected no HTTP request in a plain context")
+	}
+}
+
 func TestStreamableHTTP_PongResponseHandling(t *testing.T) {
 	// Ping/Pong does not require session ID
 	// https://modelcontextprotocol.io/specification/2025-03-26/basic/utilities/ping
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// WithStrictValidation checks every incoming message with
+// mcp.ValidateMessage before handling it. Malformed requests are answered
+// with the PARSE_ERROR, INVALID_REQUEST or INVALID_PARAMS error describing
+// the problem instead of being handled from whatever part of them decodes;
+// malformed notifications and responses are dropped. Rejected messages are
+// reported to the OnError hooks.
+func WithStrictValidation() ServerOption {
+	return func(s *MCPServer) {
+		s.strictValidation = true
+	}
+}
+
+// rejectInvalidMessage validates message if WithStrictValidation is used.
+// It reports whether message was rejected and, if so, the response to send.
+func (s *MCPServer) rejectInvalidMessage(ctx context.Context, message json.RawMessage) (mcp.JSONRPCMessage, bool) {
+	if !s.strictValidation {
+		return nil, false
+	}
+	err := mcp.ValidateMessage(message)
+	if err == nil {
+		return nil, false
+	}
+	var invalid *mcp.ValidationError
+	if !errors.As(err, &invalid) {
+		invalid = &mcp.ValidationError{Code: mcp.INVALID_REQUEST, Message: err.Error()}
+	}
+	s.hooks.onError(ctx, invalid.ID.Value(), mcp.MCPMethod(invalid.Method), message, err)
+
+	// Notifications and responses are not answered, unlike frames that
+	// cannot be told apart from requests.
+	var frame struct {
+		ID     json.RawMessage `json:"id"`
+		Result json.RawMessage `json:"result"`
+		Error  json.RawMessage `json:"error"`
+	}
+	if json.Unmarshal(message, &frame) == nil {
+		isNotification := invalid.Method != "" && frame.ID == nil
+		isResponse := invalid.Method == "" && (frame.Result != nil || frame.Error != nil)
+		if isNotification || isResponse {
+			return nil, true
+		}
+	}
+	return mcp.JSONRPCError{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      invalid.ID,
+		Error:   mcp.NewJSONRPCErrorDetails(invalid.Code, invalid.Message, nil),
+	}, true
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestMCPServer_WithStrictValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		code     int
+		id       mcp.RequestId
+		dropped  bool
+		answered bool
+	}{
+		{
+			name:     "valid request",
+			message:  `{"jsonrpc":"2.0","id":1,"method":"ping"}`,
+			answered: true,
+		},
+		{
+			name:    "unknown member",
+			message: `{"jsonrpc":"2.0","id":1,"method":"ping","extra":1}`,
+			code:    mcp.INVALID_REQUEST,
+			id:      mcp.NewRequestId(int64(1)),
+		},
+		{
+			name:    "missing tool name",
+			message: `{"jsonrpc":"2.0","id":"call","method":"tools/call","params":{"arguments":{}}}`,
+			code:    mcp.INVALID_PARAMS,
+			id:      mcp.NewRequestId("call"),
+		},
+		{
+			name:    "boolean id",
+			message: `{"jsonrpc":"2.0","id":true,"method":"ping"}`,
+			code:    mcp.INVALID_REQUEST,
+		},
+		{
+			name:    "invalid JSON",
+			message: `{"jsonrpc":"2.0",`,
+			code:    mcp.PARSE_ERROR,
+		},
+		{
+			name:    "invalid notification",
+			message: `{"jsonrpc":"2.0","method":"notifications/progress","params":{"progressToken":1}}`,
+			dropped: true,
+		},
+		{
+			name:    "invalid response",
+			message: `{"jsonrpc":"2.0","id":1,"result":{},"error":{"code":1,"message":"x"}}`,
+			dropped: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hookErrs []error
+			hooks := &Hooks{}
+			hooks.AddOnError(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+				hookErrs = append(hookErrs, err)
+			})
+			server := NewMCPServer("test-server", "1.0.0", WithStrictValidation(), WithHooks(hooks))
+
+			response := server.HandleMessage(context.Background(), json.RawMessage(tt.message))
+			if tt.answered {
+				assert.IsType(t, mcp.JSONRPCResponse{}, response)
+				assert.Empty(t, hookErrs)
+				return
+			}
+
+			require.Len(t, hookErrs, 1)
+			var invalid *mcp.ValidationError
+			assert.True(t, errors.As(hookErrs[0], &invalid))
+			if tt.dropped {
+				assert.Nil(t, response)
+				return
+			}
+			errResponse, ok := response.(mcp.JSONRPCError)
+			require.True(t, ok, "unexpected response %#v", response)
+			assert.Equal(t, tt.code, errResponse.Error.Code)
+			assert.Equal(t, tt.id, errResponse.ID)
+			assert.Equal(t, invalid.Message, errResponse.Error.Message)
+		})
+	}
+}
+
+func TestMCPServer_WithoutStrictValidation(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+	response := server.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"ping","extra":1}`))
+	assert.IsType(t, mcp.JSONRPCResponse{}, response, "lenient decoding ignores unknown members")
+}
+
+func FuzzMCPServer_HandleMessageStrict(f *testing.F) {
+	for _, seed := range []string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"fuzz","version":"1"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo","arguments":{"text":"hi"}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"resources/read","params":{"uri":"test://resource"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":2}}`,
+		`{"jsonrpc":"2.0","id":4,"result":{}}`,
+	} {
+		f.Add([]byte(seed))
+	}
+	server := NewMCPServer("fuzz", "1.0.0", WithStrictValidation(), WithToolCapabilities(false), WithResourceCapabilities(false, false))
+	server.AddTool(mcp.NewTool("echo", mcp.WithString("text")), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(request.GetString("text", "")), nil
+	})
+	server.AddResource(mcp.NewResource("test://resource", "resource"), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: "text"}}, nil
+	})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		response := server.HandleMessage(context.Background(), json.RawMessage(data))
+		if mcp.ValidateMessage(data) != nil {
+			if response != nil {
+				assert.IsType(t, mcp.JSONRPCError{}, response)
+			}
+		}
+	})
+}
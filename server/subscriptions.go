@@ -0,0 +1,202 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/yosida95/uritemplate/v3"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// resourceSubscription is a single resources/subscribe entry for a session.
+//
+// The subscribed URI is matched against updated URIs in one of three ways:
+//   - exactly, for plain URIs such as "file:///project/README.md"
+//   - as an RFC 6570 URI template, when the URI contains expressions such as "file:///logs/{name}"
+//   - as a prefix, when the URI ends with "*" such as "file:///project/*"
+type resourceSubscription struct {
+	uri      string
+	template *mcp.URITemplate
+	prefix   string
+	wildcard bool
+}
+
+func newResourceSubscription(uri string) (resourceSubscription, error) {
+	sub := resourceSubscription{uri: uri}
+	switch {
+	case strings.HasSuffix(uri, "*"):
+		sub.wildcard = true
+		sub.prefix = strings.TrimSuffix(uri, "*")
+	case strings.Contains(uri, "{"):
+		template, err := uritemplate.New(uri)
+		if err != nil {
+			return sub, fmt.Errorf("invalid subscription URI template %q: %w", uri, err)
+		}
+		sub.template = &mcp.URITemplate{Template: template}
+	}
+	return sub, nil
+}
+
+// matches reports whether an update to uri should be delivered to this subscription.
+func (r resourceSubscription) matches(uri string) bool {
+	if r.uri == uri {
+		return true
+	}
+	if r.wildcard {
+		return strings.HasPrefix(uri, r.prefix)
+	}
+	if r.template != nil {
+		return matchesTemplate(uri, r.template)
+	}
+	return false
+}
+
+func (s *MCPServer) handleSubscribe(
+	ctx context.Context,
+	id any,
+	request mcp.SubscribeRequest,
+) (*mcp.EmptyResult, *requestError) {
+	session, reqErr := s.subscriptionSession(ctx, id, mcp.MethodResourcesSubscribe, request.Params.URI)
+	if reqErr != nil {
+		return nil, reqErr
+	}
+
+	sub, err := newResourceSubscription(request.Params.URI)
+	if err != nil {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INVALID_PARAMS,
+			err:  err,
+		}
+	}
+
+	s.subscriptionsMu.Lock()
+	defer s.subscriptionsMu.Unlock()
+	sessionSubs, ok := s.subscriptions[session.SessionID()]
+	if !ok {
+		sessionSubs = make(map[string]resourceSubscription)
+		s.subscriptions[session.SessionID()] = sessionSubs
+	}
+	sessionSubs[sub.uri] = sub
+
+	return &mcp.EmptyResult{}, nil
+}
+
+func (s *MCPServer) handleUnsubscribe(
+	ctx context.Context,
+	id any,
+	request mcp.UnsubscribeRequest,
+) (*mcp.EmptyResult, *requestError) {
+	session, reqErr := s.subscriptionSession(ctx, id, mcp.MethodResourcesUnsubscribe, request.Params.URI)
+	if reqErr != nil {
+		return nil, reqErr
+	}
+
+	s.subscriptionsMu.Lock()
+	defer s.subscriptionsMu.Unlock()
+	if sessionSubs, ok := s.subscriptions[session.SessionID()]; ok {
+		delete(sessionSubs, request.Params.URI)
+		if len(sessionSubs) == 0 {
+			delete(s.subscriptions, session.SessionID())
+		}
+	}
+
+	return &mcp.EmptyResult{}, nil
+}
+
+// subscriptionSession validates a subscribe or unsubscribe request and
+// returns the session it applies to.
+func (s *MCPServer) subscriptionSession(ctx context.Context, id any, method mcp.MCPMethod, uri string) (ClientSession, *requestError) {
+	s.capabilitiesMu.RLock()
+	subscribe := s.capabilities.resources != nil && s.capabilities.resources.subscribe
+	s.capabilitiesMu.RUnlock()
+	if !subscribe {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.METHOD_NOT_FOUND,
+			err:  fmt.Errorf("resource subscriptions %w", ErrUnsupported),
+			data: mcp.MethodNotFoundErrorData{Method: string(method), Capability: "resources.subscribe"},
+		}
+	}
+
+	if uri == "" {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INVALID_PARAMS,
+			err:  fmt.Errorf("uri is required"),
+		}
+	}
+
+	session := ClientSessionFromContext(ctx)
+	if session == nil || !session.Initialized() {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INTERNAL_ERROR,
+			err:  ErrSessionNotInitialized,
+		}
+	}
+	return session, nil
+}
+
+// ResourceSubscriptions returns the URIs the given session is currently subscribed to.
+func (s *MCPServer) ResourceSubscriptions(sessionID string) []string {
+	s.subscriptionsMu.RLock()
+	defer s.subscriptionsMu.RUnlock()
+	sessionSubs := s.subscriptions[sessionID]
+	uris := make([]string, 0, len(sessionSubs))
+	for uri := range sessionSubs {
+		uris = append(uris, uri)
+	}
+	return uris
+}
+
+// NotifyResourceUpdated sends a notifications/resources/updated notification
+// for uri to every session with a matching subscription. Sessions that have
+// not subscribed to the resource, directly or through a template or wildcard,
+// are not notified. The update is forwarded to the servers s is mounted in,
+// and to the other servers of the notification bus if WithNotificationBus
+// is used.
+func (s *MCPServer) NotifyResourceUpdated(uri string) {
+	s.resourceCache.invalidate(uri)
+	s.notifyResourceSubscribers(uri)
+	s.forwardResourceUpdated(uri)
+	s.publish(BusMessage{ResourceURI: uri})
+}
+
+// notifyResourceSubscribers sends a notifications/resources/updated
+// notification for uri to the sessions of s subscribed to it.
+func (s *MCPServer) notifyResourceSubscribers(uri string) {
+	s.subscriptionsMu.RLock()
+	sessionIDs := make([]string, 0, len(s.subscriptions))
+	for sessionID, sessionSubs := range s.subscriptions {
+		for _, sub := range sessionSubs {
+			if sub.matches(uri) {
+				sessionIDs = append(sessionIDs, sessionID)
+				break
+			}
+		}
+	}
+	s.subscriptionsMu.RUnlock()
+
+	notification := mcp.NewResourceUpdatedNotification(uri).ToJSONRPCNotification()
+	for _, sessionID := range sessionIDs {
+		value, ok := s.sessions.Load(sessionID)
+		if !ok {
+			continue
+		}
+		session, ok := value.(ClientSession)
+		if !ok || !session.Initialized() {
+			continue
+		}
+		_ = s.sendNotificationToSpecificClient(session, notification)
+	}
+}
+
+// removeResourceSubscriptions drops all subscriptions held by a session.
+func (s *MCPServer) removeResourceSubscriptions(sessionID string) {
+	s.subscriptionsMu.Lock()
+	delete(s.subscriptions, sessionID)
+	s.subscriptionsMu.Unlock()
+}
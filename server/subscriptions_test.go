@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func subscribeSession(t *testing.T, server *MCPServer, sessionID string) (context.Context, chan mcp.JSONRPCNotification) {
+	t.Helper()
+	notificationChannel := make(chan mcp.JSONRPCNotification, 10)
+	session := &fakeSession{
+		sessionID:           sessionID,
+		notificationChannel: notificationChannel,
+		initialized:         true,
+	}
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+	return server.WithContext(context.Background(), session), notificationChannel
+}
+
+func sendSubscriptionRequest(t *testing.T, ctx context.Context, server *MCPServer, method, uri string) mcp.JSONRPCMessage {
+	t.Helper()
+	return server.HandleMessage(ctx, json.RawMessage(fmt.Sprintf(
+		`{"jsonrpc":"2.0","id":1,"method":%q,"params":{"uri":%q}}`, method, uri,
+	)))
+}
+
+func TestMCPServer_NotifyResourceUpdated(t *testing.T) {
+	tests := []struct {
+		name        string
+		subscribe   string
+		updated     string
+		expectCount int
+	}{
+		{
+			name:        "exact match",
+			subscribe:   "file:///project/README.md",
+			updated:     "file:///project/README.md",
+			expectCount: 1,
+		},
+		{
+			name:        "exact mismatch",
+			subscribe:   "file:///project/README.md",
+			updated:     "file:///project/LICENSE",
+			expectCount: 0,
+		},
+		{
+			name:        "template match",
+			subscribe:   "file:///logs/{name}",
+			updated:     "file:///logs/app.log",
+			expectCount: 1,
+		},
+		{
+			name:        "template mismatch",
+			subscribe:   "file:///logs/{name}",
+			updated:     "file:///data/app.log",
+			expectCount: 0,
+		},
+		{
+			name:        "wildcard match",
+			subscribe:   "file:///project/*",
+			updated:     "file:///project/src/main.go",
+			expectCount: 1,
+		},
+		{
+			name:        "wildcard mismatch",
+			subscribe:   "file:///project/*",
+			updated:     "file:///other/main.go",
+			expectCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMCPServer("test-server", "1.0.0", WithResourceCapabilities(true, false))
+			ctx, subscribed := subscribeSession(t, server, "subscriber")
+			_, bystander := subscribeSession(t, server, "bystander")
+
+			response := sendSubscriptionRequest(t, ctx, server, "resources/subscribe", tt.subscribe)
+			_, ok := response.(mcp.JSONRPCResponse)
+			require.True(t, ok, "expected success response, got %#v", response)
+
+			server.NotifyResourceUpdated(tt.updated)
+
+			assert.Len(t, subscribed, tt.expectCount)
+			assert.Len(t, bystander, 0)
+			if tt.expectCount > 0 {
+				notification := <-subscribed
+				assert.Equal(t, mcp.MethodNotificationResourceUpdated, notification.Method)
+				assert.Equal(t, tt.updated, notification.Params.AdditionalFields["uri"])
+			}
+		})
+	}
+}
+
+func TestMCPServer_UnsubscribeStopsNotifications(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithResourceCapabilities(true, false))
+	ctx, notifications := subscribeSession(t, server, "subscriber")
+
+	sendSubscriptionRequest(t, ctx, server, "resources/subscribe", "test://a")
+	sendSubscriptionRequest(t, ctx, server, "resources/subscribe", "test://b")
+	assert.ElementsMatch(t, []string{"test://a", "test://b"}, server.ResourceSubscriptions("subscriber"))
+
+	response := sendSubscriptionRequest(t, ctx, server, "resources/unsubscribe", "test://a")
+	_, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "expected success response, got %#v", response)
+	assert.Equal(t, []string{"test://b"}, server.ResourceSubscriptions("subscriber"))
+
+	server.NotifyResourceUpdated("test://a")
+	assert.Len(t, notifications, 0)
+
+	server.UnregisterSession(context.Background(), "subscriber")
+	assert.Empty(t, server.ResourceSubscriptions("subscriber"))
+}
+
+func TestMCPServer_SubscribeErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         []ServerOption
+		uri          string
+		expectedCode int
+	}{
+		{
+			name:         "resources capability disabled",
+			uri:          "test://a",
+			expectedCode: mcp.METHOD_NOT_FOUND,
+		},
+		{
+			name:         "subscribe not enabled",
+			opts:         []ServerOption{WithResourceCapabilities(false, true)},
+			uri:          "test://a",
+			expectedCode: mcp.METHOD_NOT_FOUND,
+		},
+		{
+			name:         "missing uri",
+			opts:         []ServerOption{WithResourceCapabilities(true, false)},
+			uri:          "",
+			expectedCode: mcp.INVALID_PARAMS,
+		},
+		{
+			name:         "invalid template",
+			opts:         []ServerOption{WithResourceCapabilities(true, false)},
+			uri:          "test://{unclosed",
+			expectedCode: mcp.INVALID_PARAMS,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMCPServer("test-server", "1.0.0", tt.opts...)
+			ctx, _ := subscribeSession(t, server, "subscriber")
+
+			response := sendSubscriptionRequest(t, ctx, server, "resources/subscribe", tt.uri)
+			errorResponse, ok := response.(mcp.JSONRPCError)
+			require.True(t, ok, "expected error response, got %#v", response)
+			assert.Equal(t, tt.expectedCode, errorResponse.Error.Code)
+		})
+	}
+}
+
+func TestMCPServer_NotifyResourceUpdatedSkipsUnregisteredSessions(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithResourceCapabilities(true, false))
+	ctx, notifications := subscribeSession(t, server, "subscriber")
+	sendSubscriptionRequest(t, ctx, server, "resources/subscribe", "test://a")
+
+	server.sessions.Delete("subscriber")
+	server.NotifyResourceUpdated("test://a")
+
+	select {
+	case notification := <-notifications:
+		t.Errorf("Unexpected notification: %v", notification)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,390 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TenantFunc derives the tenant of a request, for example from the
+// authenticated caller or a header. An empty tenant means the request only
+// sees the server-wide tools, resources and prompts. Returning an error
+// rejects the request.
+type TenantFunc func(ctx context.Context) (string, error)
+
+// WithTenancy serves tenant-scoped tools, resources, resource templates and
+// prompts, registered with AddTenantTools and its siblings, to the sessions
+// of each tenant, on top of the server-wide ones. The tenant of a session is
+// derived by resolve from its first request and its later requests must
+// resolve to the same tenant, so a session never sees the registrations of
+// another tenant. Tenant registrations take precedence over server-wide
+// ones, and session registrations over both.
+//
+// Without WithTenancy, the tenant of a request is the one set on its
+// context with WithTenant, if any.
+func WithTenancy(resolve TenantFunc) ServerOption {
+	return func(s *MCPServer) {
+		s.tenancy.resolve = resolve
+	}
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx carrying tenant, as served by
+// TenantFromContext. It is useful for tests and for HTTPContextFunc
+// functions deriving the tenant themselves.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant of the request being handled.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	return tenant, ok && tenant != ""
+}
+
+// TenantFromHeader derives tenants from the header name of HTTP requests.
+// Requests without the header are rejected with ErrTenantRequired.
+func TenantFromHeader(name string) TenantFunc {
+	return func(ctx context.Context) (string, error) {
+		headers, _ := ctx.Value(requestHeader).(http.Header)
+		if tenant := headers.Get(name); tenant != "" {
+			return tenant, nil
+		}
+		return "", fmt.Errorf("missing %s header: %w", name, ErrTenantRequired)
+	}
+}
+
+// TenantFromAuth derives tenants from the caller authenticated by
+// WithAuthFunc: its claim in AuthInfo.Extra, which must be a string, or its
+// subject if claim is empty. Requests without one are rejected with
+// ErrTenantRequired.
+func TenantFromAuth(claim string) TenantFunc {
+	return func(ctx context.Context) (string, error) {
+		info, _ := AuthInfoFromContext(ctx)
+		tenant := info.Subject
+		if claim != "" {
+			tenant, _ = info.Extra[claim].(string)
+		}
+		if tenant == "" {
+			return "", fmt.Errorf("no tenant for the caller: %w", ErrTenantRequired)
+		}
+		return tenant, nil
+	}
+}
+
+// tenancy holds the registrations of every tenant and the tenant each
+// session is bound to.
+type tenancy struct {
+	resolve TenantFunc
+
+	mu       sync.RWMutex
+	tenants  map[string]*tenantRegistry
+	sessions map[string]string
+}
+
+// tenantRegistry holds the registrations of a tenant.
+type tenantRegistry struct {
+	tools     map[string]ServerTool
+	resources map[string]ServerResource
+	templates map[string]ServerResourceTemplate
+	prompts   map[string]ServerPrompt
+}
+
+// bind resolves the tenant of a request and stores it in ctx. The first
+// tenant resolved for a session is kept for the rest of the session.
+func (t *tenancy) bind(ctx context.Context, id any) (context.Context, *requestError) {
+	if t.resolve == nil {
+		return ctx, nil
+	}
+	tenant, err := t.resolve(ctx)
+	if err != nil {
+		return ctx, &requestError{id: id, code: mcp.INVALID_REQUEST, err: err}
+	}
+
+	if session := ClientSessionFromContext(ctx); session != nil {
+		t.mu.Lock()
+		bound, ok := t.sessions[session.SessionID()]
+		if !ok {
+			if t.sessions == nil {
+				t.sessions = make(map[string]string)
+			}
+			t.sessions[session.SessionID()] = tenant
+			bound = tenant
+		}
+		t.mu.Unlock()
+		if bound != tenant {
+			return ctx, &requestError{
+				id:   id,
+				code: mcp.INVALID_REQUEST,
+				err:  fmt.Errorf("session %s: %w", session.SessionID(), ErrTenantMismatch),
+			}
+		}
+	}
+	return WithTenant(ctx, tenant), nil
+}
+
+// forget drops the tenant of an unregistered session.
+func (t *tenancy) forget(sessionID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.sessions, sessionID)
+}
+
+// sessionTenant returns the tenant sessionID is bound to.
+func (t *tenancy) sessionTenant(sessionID string) (string, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	tenant, ok := t.sessions[sessionID]
+	return tenant, ok
+}
+
+// tenantSessions returns the IDs of the sessions bound to tenant.
+func (t *tenancy) tenantSessions(tenant string) []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	var sessionIDs []string
+	for sessionID, bound := range t.sessions {
+		if bound == tenant {
+			sessionIDs = append(sessionIDs, sessionID)
+		}
+	}
+	sort.Strings(sessionIDs)
+	return sessionIDs
+}
+
+// update applies fn to the registry of tenant, creating it.
+func (t *tenancy) update(tenant string, fn func(r *tenantRegistry)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.tenants == nil {
+		t.tenants = make(map[string]*tenantRegistry)
+	}
+	r, ok := t.tenants[tenant]
+	if !ok {
+		r = &tenantRegistry{
+			tools:     make(map[string]ServerTool),
+			resources: make(map[string]ServerResource),
+			templates: make(map[string]ServerResourceTemplate),
+			prompts:   make(map[string]ServerPrompt),
+		}
+		t.tenants[tenant] = r
+	}
+	fn(r)
+}
+
+// tenantEntries returns a copy of the registrations of the tenant of ctx
+// picked by get, or nil if there are none.
+func tenantEntries[T any](s *MCPServer, ctx context.Context, get func(r *tenantRegistry) map[string]T) map[string]T {
+	tenant, ok := TenantFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	s.tenancy.mu.RLock()
+	defer s.tenancy.mu.RUnlock()
+	r, ok := s.tenancy.tenants[tenant]
+	if !ok || len(get(r)) == 0 {
+		return nil
+	}
+	return maps.Clone(get(r))
+}
+
+// overlayTools returns the tools of the tenant of ctx overridden by the
+// tools of its session, both of which take precedence over the server's.
+func (s *MCPServer) overlayTools(ctx context.Context) map[string]ServerTool {
+	tools := tenantEntries(s, ctx, func(r *tenantRegistry) map[string]ServerTool { return r.tools })
+	if session, ok := ClientSessionFromContext(ctx).(SessionWithTools); ok {
+		tools = overlay(tools, session.GetSessionTools())
+	}
+	return tools
+}
+
+// overlayResources returns the resources of the tenant of ctx overridden
+// by the resources of its session.
+func (s *MCPServer) overlayResources(ctx context.Context) map[string]ServerResource {
+	resources := tenantEntries(s, ctx, func(r *tenantRegistry) map[string]ServerResource { return r.resources })
+	if session, ok := ClientSessionFromContext(ctx).(SessionWithResources); ok {
+		resources = overlay(resources, session.GetSessionResources())
+	}
+	return resources
+}
+
+// overlayResourceTemplates returns the resource templates of the tenant of
+// ctx overridden by the resource templates of its session.
+func (s *MCPServer) overlayResourceTemplates(ctx context.Context) map[string]ServerResourceTemplate {
+	templates := tenantEntries(s, ctx, func(r *tenantRegistry) map[string]ServerResourceTemplate { return r.templates })
+	if session, ok := ClientSessionFromContext(ctx).(SessionWithResourceTemplates); ok {
+		templates = overlay(templates, session.GetSessionResourceTemplates())
+	}
+	return templates
+}
+
+// overlayPrompts returns the prompts of the tenant of ctx overridden by
+// the prompts of its session.
+func (s *MCPServer) overlayPrompts(ctx context.Context) map[string]ServerPrompt {
+	prompts := tenantEntries(s, ctx, func(r *tenantRegistry) map[string]ServerPrompt { return r.prompts })
+	if session, ok := ClientSessionFromContext(ctx).(SessionWithPrompts); ok {
+		prompts = overlay(prompts, session.GetSessionPrompts())
+	}
+	return prompts
+}
+
+// overlay returns base with the entries of top added, reusing base, which
+// must not be shared, when both have entries.
+func overlay[T any](base, top map[string]T) map[string]T {
+	if len(base) == 0 {
+		return top
+	}
+	maps.Copy(base, top)
+	return base
+}
+
+// AddTenantTools registers tools served only to the sessions of tenant.
+func (s *MCPServer) AddTenantTools(tenant string, tools ...ServerTool) {
+	s.implicitlyRegisterToolCapabilities()
+	s.tenancy.update(tenant, func(r *tenantRegistry) {
+		for _, tool := range tools {
+			r.tools[tool.Tool.Name] = tool
+		}
+	})
+	s.notifyTenantListChanged(tenant, mcp.MethodNotificationToolsListChanged, s.capabilities.tools.listChanged)
+}
+
+// DeleteTenantTools removes tools registered for tenant.
+func (s *MCPServer) DeleteTenantTools(tenant string, names ...string) {
+	s.tenancy.update(tenant, func(r *tenantRegistry) {
+		for _, name := range names {
+			delete(r.tools, name)
+		}
+	})
+	s.notifyTenantListChanged(tenant, mcp.MethodNotificationToolsListChanged,
+		s.capabilities.tools != nil && s.capabilities.tools.listChanged)
+}
+
+// AddTenantResources registers resources served only to the sessions of
+// tenant.
+func (s *MCPServer) AddTenantResources(tenant string, resources ...ServerResource) {
+	s.implicitlyRegisterResourceCapabilities()
+	s.tenancy.update(tenant, func(r *tenantRegistry) {
+		for _, resource := range resources {
+			r.resources[resource.Resource.URI] = resource
+		}
+	})
+	s.notifyTenantListChanged(tenant, mcp.MethodNotificationResourcesListChanged, s.capabilities.resources.listChanged)
+}
+
+// DeleteTenantResources removes resources registered for tenant.
+func (s *MCPServer) DeleteTenantResources(tenant string, uris ...string) {
+	s.tenancy.update(tenant, func(r *tenantRegistry) {
+		for _, uri := range uris {
+			delete(r.resources, uri)
+		}
+	})
+	s.notifyTenantListChanged(tenant, mcp.MethodNotificationResourcesListChanged,
+		s.capabilities.resources != nil && s.capabilities.resources.listChanged)
+}
+
+// AddTenantResourceTemplates registers resource templates served only to
+// the sessions of tenant.
+func (s *MCPServer) AddTenantResourceTemplates(tenant string, templates ...ServerResourceTemplate) {
+	s.implicitlyRegisterResourceCapabilities()
+	s.tenancy.update(tenant, func(r *tenantRegistry) {
+		for _, template := range templates {
+			r.templates[template.Template.URITemplate.Raw()] = template
+		}
+	})
+	s.notifyTenantListChanged(tenant, mcp.MethodNotificationResourcesListChanged, s.capabilities.resources.listChanged)
+}
+
+// DeleteTenantResourceTemplates removes resource templates registered for
+// tenant.
+func (s *MCPServer) DeleteTenantResourceTemplates(tenant string, uriTemplates ...string) {
+	s.tenancy.update(tenant, func(r *tenantRegistry) {
+		for _, uriTemplate := range uriTemplates {
+			delete(r.templates, uriTemplate)
+		}
+	})
+	s.notifyTenantListChanged(tenant, mcp.MethodNotificationResourcesListChanged,
+		s.capabilities.resources != nil && s.capabilities.resources.listChanged)
+}
+
+// AddTenantPrompts registers prompts served only to the sessions of tenant.
+func (s *MCPServer) AddTenantPrompts(tenant string, prompts ...ServerPrompt) {
+	s.implicitlyRegisterPromptCapabilities()
+	s.implicitlyRegisterCompletionCapabilities(prompts)
+	s.tenancy.update(tenant, func(r *tenantRegistry) {
+		for _, prompt := range prompts {
+			r.prompts[prompt.Prompt.Name] = prompt
+		}
+	})
+	s.notifyTenantListChanged(tenant, mcp.MethodNotificationPromptsListChanged, s.capabilities.prompts.listChanged)
+}
+
+// DeleteTenantPrompts removes prompts registered for tenant.
+func (s *MCPServer) DeleteTenantPrompts(tenant string, names ...string) {
+	s.tenancy.update(tenant, func(r *tenantRegistry) {
+		for _, name := range names {
+			delete(r.prompts, name)
+		}
+	})
+	s.notifyTenantListChanged(tenant, mcp.MethodNotificationPromptsListChanged,
+		s.capabilities.prompts != nil && s.capabilities.prompts.listChanged)
+}
+
+// DeleteTenant removes everything registered for tenant. Its sessions are
+// kept and only see the server-wide registrations afterwards.
+func (s *MCPServer) DeleteTenant(tenant string) {
+	s.tenancy.mu.Lock()
+	r, ok := s.tenancy.tenants[tenant]
+	delete(s.tenancy.tenants, tenant)
+	s.tenancy.mu.Unlock()
+	if !ok {
+		return
+	}
+	if len(r.tools) > 0 {
+		s.notifyTenantListChanged(tenant, mcp.MethodNotificationToolsListChanged,
+			s.capabilities.tools != nil && s.capabilities.tools.listChanged)
+	}
+	if len(r.resources) > 0 || len(r.templates) > 0 {
+		s.notifyTenantListChanged(tenant, mcp.MethodNotificationResourcesListChanged,
+			s.capabilities.resources != nil && s.capabilities.resources.listChanged)
+	}
+	if len(r.prompts) > 0 {
+		s.notifyTenantListChanged(tenant, mcp.MethodNotificationPromptsListChanged,
+			s.capabilities.prompts != nil && s.capabilities.prompts.listChanged)
+	}
+}
+
+// SessionTenant returns the tenant the session sessionID is bound to under
+// WithTenancy.
+func (s *MCPServer) SessionTenant(sessionID string) (string, bool) {
+	s.tenancy.mu.RLock()
+	defer s.tenancy.mu.RUnlock()
+	tenant, ok := s.tenancy.sessions[sessionID]
+	return tenant, ok && tenant != ""
+}
+
+// notifyTenantListChanged tells the initialized sessions of tenant that
+// the list behind method changed, if listChanged is set.
+func (s *MCPServer) notifyTenantListChanged(tenant, method string, listChanged bool) {
+	if !listChanged {
+		return
+	}
+	for _, sessionID := range s.tenancy.tenantSessions(tenant) {
+		value, ok := s.sessions.Load(sessionID)
+		if !ok || !value.(ClientSession).Initialized() {
+			continue
+		}
+		if err := s.notifySessionListChanged(sessionID, method); err != nil {
+			s.hooks.onError(context.Background(), nil, "notification", map[string]any{
+				"method":    method,
+				"sessionID": sessionID,
+			}, fmt.Errorf("failed to send notification to tenant %s: %w", tenant, err))
+		}
+	}
+}
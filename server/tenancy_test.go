@@ -0,0 +1,210 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// tenantRequest returns a context for session carrying the tenant header.
+func tenantRequest(server *MCPServer, session ClientSession, tenant string) context.Context {
+	r, _ := http.NewRequest(http.MethodPost, "/mcp", nil)
+	if tenant != "" {
+		r.Header.Set("X-Tenant", tenant)
+	}
+	return WithHTTPRequest(server.WithContext(context.Background(), session), r)
+}
+
+func textTool(name, text string) ServerTool {
+	return ServerTool{
+		Tool: mcp.NewTool(name),
+		Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText(text), nil
+		},
+	}
+}
+
+func textResource(uri, text string) ServerResource {
+	return ServerResource{
+		Resource: mcp.NewResource(uri, uri),
+		Handler: func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: text}}, nil
+		},
+	}
+}
+
+func TestMCPServer_WithTenancy(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithTenancy(TenantFromHeader("X-Tenant")))
+	server.AddTools(textTool("shared", "shared"))
+	server.AddTenantTools("acme", textTool("report", "acme report"), textTool("shared", "acme shared"))
+	server.AddTenantTools("globex", textTool("report", "globex report"), textTool("export", "globex export"))
+	server.AddTenantResources("acme", textResource("tenant://data", "acme data"))
+	server.AddTenantResourceTemplates("globex", ServerResourceTemplate{
+		Template: mcp.NewResourceTemplate("tenant://{name}", "globex"),
+		Handler:  namedTemplateHandler("globex template"),
+	})
+	server.AddTenantPrompts("acme", ServerPrompt{
+		Prompt: mcp.NewPrompt("greeting"),
+		Handler: func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return mcp.NewGetPromptResult("acme greeting", nil), nil
+		},
+	})
+
+	acme := &sessionTestClient{sessionID: "acme-1", initialized: true, notificationChannel: make(chan mcp.JSONRPCNotification, 10)}
+	globex := &sessionTestClient{sessionID: "globex-1", initialized: true, notificationChannel: make(chan mcp.JSONRPCNotification, 10)}
+	require.NoError(t, server.RegisterSession(context.Background(), acme))
+	require.NoError(t, server.RegisterSession(context.Background(), globex))
+	acmeCtx := tenantRequest(server, acme, "acme")
+	globexCtx := tenantRequest(server, globex, "globex")
+
+	listTools := func(ctx context.Context) []string {
+		response := server.HandleMessage(ctx, json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+		result := response.(mcp.JSONRPCResponse).Result.(mcp.ListToolsResult)
+		var names []string
+		for _, tool := range result.Tools {
+			names = append(names, tool.Name)
+		}
+		return names
+	}
+	callTool := func(ctx context.Context, name string) mcp.JSONRPCMessage {
+		return server.HandleMessage(ctx, json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"`+name+`"}}`))
+	}
+	readResource := func(ctx context.Context, uri string) mcp.JSONRPCMessage {
+		return server.HandleMessage(ctx, json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"`+uri+`"}}`))
+	}
+	text := func(response mcp.JSONRPCMessage) string {
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "unexpected response %#v", response)
+		switch result := resp.Result.(type) {
+		case mcp.CallToolResult:
+			return result.Content[0].(mcp.TextContent).Text
+		case mcp.ReadResourceResult:
+			return result.Contents[0].(mcp.TextResourceContents).Text
+		case mcp.GetPromptResult:
+			return result.Description
+		}
+		t.Fatalf("unexpected result %#v", resp.Result)
+		return ""
+	}
+
+	t.Run("tools", func(t *testing.T) {
+		assert.Equal(t, []string{"report", "shared"}, listTools(acmeCtx))
+		assert.Equal(t, []string{"export", "report", "shared"}, listTools(globexCtx))
+		assert.Equal(t, "acme report", text(callTool(acmeCtx, "report")))
+		assert.Equal(t, "acme shared", text(callTool(acmeCtx, "shared")), "tenant tools override server tools")
+		assert.Equal(t, "globex report", text(callTool(globexCtx, "report")))
+		assert.Equal(t, "shared", text(callTool(globexCtx, "shared")))
+		assert.IsType(t, mcp.JSONRPCError{}, callTool(acmeCtx, "export"), "tools of other tenants are not found")
+	})
+
+	t.Run("resources", func(t *testing.T) {
+		assert.Equal(t, "acme data", text(readResource(acmeCtx, "tenant://data")))
+		assert.Equal(t, "globex template", text(readResource(globexCtx, "tenant://data")))
+		assert.IsType(t, mcp.JSONRPCError{}, readResource(acmeCtx, "tenant://other"))
+
+		response := server.HandleMessage(globexCtx, json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`))
+		assert.Empty(t, response.(mcp.JSONRPCResponse).Result.(mcp.ListResourcesResult).Resources)
+		response = server.HandleMessage(acmeCtx, json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"resources/templates/list"}`))
+		assert.Empty(t, response.(mcp.JSONRPCResponse).Result.(mcp.ListResourceTemplatesResult).ResourceTemplates)
+	})
+
+	t.Run("prompts", func(t *testing.T) {
+		getPrompt := `{"jsonrpc":"2.0","id":1,"method":"prompts/get","params":{"name":"greeting"}}`
+		assert.Equal(t, "acme greeting", text(server.HandleMessage(acmeCtx, json.RawMessage(getPrompt))))
+		assert.IsType(t, mcp.JSONRPCError{}, server.HandleMessage(globexCtx, json.RawMessage(getPrompt)))
+
+		response := server.HandleMessage(globexCtx, json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"prompts/list"}`))
+		assert.Empty(t, response.(mcp.JSONRPCResponse).Result.(mcp.ListPromptsResult).Prompts)
+	})
+
+	t.Run("sessions stay with their tenant", func(t *testing.T) {
+		tenant, ok := server.SessionTenant("acme-1")
+		assert.True(t, ok)
+		assert.Equal(t, "acme", tenant)
+
+		response := callTool(tenantRequest(server, acme, "globex"), "report")
+		errResponse, ok := response.(mcp.JSONRPCError)
+		require.True(t, ok)
+		assert.Equal(t, mcp.INVALID_REQUEST, errResponse.Error.Code)
+		assert.Contains(t, errResponse.Error.Message, ErrTenantMismatch.Error())
+	})
+
+	t.Run("requests without a tenant are rejected", func(t *testing.T) {
+		other := &sessionTestClient{sessionID: "other", initialized: true}
+		response := callTool(tenantRequest(server, other, ""), "shared")
+		errResponse, ok := response.(mcp.JSONRPCError)
+		require.True(t, ok)
+		assert.Contains(t, errResponse.Error.Message, ErrTenantRequired.Error())
+	})
+
+	t.Run("list changes are notified to the tenant", func(t *testing.T) {
+		for len(acme.notificationChannel) > 0 {
+			<-acme.notificationChannel
+		}
+		for len(globex.notificationChannel) > 0 {
+			<-globex.notificationChannel
+		}
+		server.AddTenantTools("globex", textTool("audit", "audit"))
+		assert.Empty(t, acme.notificationChannel)
+		require.Len(t, globex.notificationChannel, 1)
+		assert.Equal(t, mcp.MethodNotificationToolsListChanged, (<-globex.notificationChannel).Method)
+
+		server.DeleteTenant("globex")
+		assert.Equal(t, []string{"shared"}, listTools(globexCtx))
+	})
+
+	server.UnregisterSession(context.Background(), "acme-1")
+	_, ok := server.SessionTenant("acme-1")
+	assert.False(t, ok, "unregistered sessions are forgotten")
+}
+
+func TestTenantFromAuth(t *testing.T) {
+	tests := []struct {
+		name     string
+		claim    string
+		info     *AuthInfo
+		expected string
+	}{
+		{name: "subject", info: &AuthInfo{Subject: "user-1"}, expected: "user-1"},
+		{name: "claim", claim: "org", info: &AuthInfo{Subject: "user-1", Extra: map[string]any{"org": "acme"}}, expected: "acme"},
+		{name: "missing claim", claim: "org", info: &AuthInfo{Subject: "user-1"}},
+		{name: "unauthenticated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.info != nil {
+				ctx = WithAuthInfo(ctx, *tt.info)
+			}
+			tenant, err := TenantFromAuth(tt.claim)(ctx)
+			if tt.expected == "" {
+				assert.True(t, errors.Is(err, ErrTenantRequired))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, tenant)
+		})
+	}
+}
+
+func TestMCPServer_TenantWithoutTenancy(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+	server.AddTenantTools("acme", textTool("report", "acme report"))
+
+	names := func(ctx context.Context) []string {
+		var names []string
+		for _, tool := range server.listTools(ctx) {
+			names = append(names, tool.Name)
+		}
+		return names
+	}
+	assert.Empty(t, names(context.Background()))
+	assert.Equal(t, []string{"report"}, names(WithTenant(context.Background(), "acme")))
+}
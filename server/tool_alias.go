@@ -0,0 +1,166 @@
+package server
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ToolAlias is an additional name a tool can be called by, typically the
+// name it had before being renamed. Aliases are not listed by tools/list;
+// the listed tool reports them in its _meta under "aliases".
+type ToolAlias struct {
+	Name string
+	// Deprecation optionally marks calls through the alias as deprecated,
+	// independently of the tool itself.
+	Deprecation *ToolDeprecation
+}
+
+// ToolDeprecation describes why a tool or alias is deprecated. Deprecated
+// tools keep working; tools/list reports the deprecation in the tool's
+// _meta under "deprecated", and calls are reported to the
+// OnDeprecatedToolCall hooks.
+type ToolDeprecation struct {
+	// Message explains the deprecation to clients.
+	Message string `json:"message,omitempty"`
+	// Since optionally names the version that deprecated the tool.
+	Since string `json:"since,omitempty"`
+	// Replacement optionally names the tool to call instead.
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// warning formats the deprecation of the tool called as name.
+func (d ToolDeprecation) warning(name string) string {
+	warning := fmt.Sprintf("tool %q is deprecated", name)
+	if d.Since != "" {
+		warning += " since " + d.Since
+	}
+	if d.Replacement != "" {
+		warning += fmt.Sprintf(", use %q instead", d.Replacement)
+	}
+	if d.Message != "" {
+		warning += ": " + d.Message
+	}
+	return warning
+}
+
+// DeprecatedToolCall describes a call of a deprecated tool or alias, as
+// passed to the OnDeprecatedToolCall hooks.
+type DeprecatedToolCall struct {
+	// Name is the name the tool was called by.
+	Name string
+	// Tool is the canonical name of the tool.
+	Tool        string
+	Deprecation ToolDeprecation
+}
+
+// Warning returns the deprecation warning WithDeprecationWarnings adds to
+// call results.
+func (c DeprecatedToolCall) Warning() string {
+	return c.Deprecation.warning(c.Name)
+}
+
+// DeprecationWarningMeta is the _meta field of call results holding the
+// warning added by WithDeprecationWarnings.
+const DeprecationWarningMeta = "deprecationWarning"
+
+// WithDeprecationWarnings adds a warning to the _meta of the results of
+// calls to deprecated tools or through deprecated aliases, under
+// DeprecationWarningMeta.
+func WithDeprecationWarnings() ServerOption {
+	return func(s *MCPServer) {
+		s.deprecationWarnings = true
+	}
+}
+
+// toolAlias is an alias registered for a global tool.
+type toolAlias struct {
+	canonical   string
+	deprecation *ToolDeprecation
+}
+
+// registerToolAliases indexes the aliases of tool. It must be called with
+// toolsMu held.
+func (s *MCPServer) registerToolAliases(tool ServerTool) {
+	for _, alias := range tool.Aliases {
+		s.toolAliases.set(alias.Name, toolAlias{canonical: tool.Tool.Name, deprecation: alias.Deprecation})
+	}
+}
+
+// unregisterToolAliases drops the aliases of tool that still point to it.
+// It must be called with toolsMu held.
+func (s *MCPServer) unregisterToolAliases(tool ServerTool) {
+	for _, alias := range tool.Aliases {
+		if registered, _ := s.toolAliases.get(alias.Name); registered.canonical == tool.Tool.Name {
+			s.toolAliases.delete(alias.Name)
+		}
+	}
+}
+
+// resolveToolAlias finds which of tools has an alias called name.
+func resolveToolAlias(tools map[string]ServerTool, name string) (ServerTool, *ToolAlias, bool) {
+	for _, tool := range tools {
+		for i := range tool.Aliases {
+			if tool.Aliases[i].Name == name {
+				return tool, &tool.Aliases[i], true
+			}
+		}
+	}
+	return ServerTool{}, nil, false
+}
+
+// toolDeprecation returns the deprecation applying to a call of tool,
+// through alias if it is not nil.
+func toolDeprecation(tool ServerTool, alias *ToolAlias) *ToolDeprecation {
+	if alias != nil && alias.Deprecation != nil {
+		return alias.Deprecation
+	}
+	return tool.Deprecation
+}
+
+// withToolAliasMeta adds the aliases and deprecation of tool to the _meta
+// of its listed definition.
+func withToolAliasMeta(listed mcp.Tool, tool ServerTool) mcp.Tool {
+	if len(tool.Aliases) == 0 && tool.Deprecation == nil {
+		return listed
+	}
+	meta := &mcp.Meta{AdditionalFields: make(map[string]any)}
+	if listed.Meta != nil {
+		meta.ProgressToken = listed.Meta.ProgressToken
+		maps.Copy(meta.AdditionalFields, listed.Meta.AdditionalFields)
+	}
+	if len(tool.Aliases) > 0 {
+		aliases := make([]map[string]any, 0, len(tool.Aliases))
+		for _, alias := range tool.Aliases {
+			entry := map[string]any{"name": alias.Name}
+			if alias.Deprecation != nil {
+				entry["deprecated"] = *alias.Deprecation
+			}
+			aliases = append(aliases, entry)
+		}
+		meta.AdditionalFields["aliases"] = aliases
+	}
+	if tool.Deprecation != nil {
+		meta.AdditionalFields["deprecated"] = *tool.Deprecation
+	}
+	listed.Meta = meta
+	return listed
+}
+
+// withDeprecationWarning returns a copy of result carrying the warning of
+// call in its _meta.
+func withDeprecationWarning(result *mcp.CallToolResult, call DeprecatedToolCall) *mcp.CallToolResult {
+	if result == nil {
+		return nil
+	}
+	warned := *result
+	meta := &mcp.Meta{AdditionalFields: make(map[string]any)}
+	if result.Meta != nil {
+		meta.ProgressToken = result.Meta.ProgressToken
+		maps.Copy(meta.AdditionalFields, result.Meta.AdditionalFields)
+	}
+	meta.AdditionalFields[DeprecationWarningMeta] = call.Warning()
+	warned.Meta = meta
+	return &warned
+}
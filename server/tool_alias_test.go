@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestMCPServer_ToolAliases(t *testing.T) {
+	var calls []DeprecatedToolCall
+	hooks := &Hooks{}
+	hooks.AddOnDeprecatedToolCall(func(ctx context.Context, id any, call DeprecatedToolCall) {
+		calls = append(calls, call)
+	})
+	server := NewMCPServer("test-server", "1.0.0", WithHooks(hooks), WithDeprecationWarnings())
+	server.AddTools(
+		ServerTool{
+			Tool: mcp.NewTool("search_docs"),
+			Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultText("called as " + request.Params.Name), nil
+			},
+			Aliases: []ToolAlias{
+				{Name: "find_docs"},
+				{Name: "search", Deprecation: &ToolDeprecation{Since: "2.0", Replacement: "search_docs"}},
+			},
+		},
+		ServerTool{
+			Tool: mcp.NewTool("legacy_export"),
+			Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultText("exported"), nil
+			},
+			Deprecation: &ToolDeprecation{Message: "exports are generated nightly"},
+		},
+	)
+
+	tests := []struct {
+		name       string
+		tool       string
+		expected   string
+		deprecated DeprecatedToolCall
+		warning    string
+	}{
+		{
+			name:     "canonical name",
+			tool:     "search_docs",
+			expected: "called as search_docs",
+		},
+		{
+			name:     "alias",
+			tool:     "find_docs",
+			expected: "called as search_docs",
+		},
+		{
+			name:     "deprecated alias",
+			tool:     "search",
+			expected: "called as search_docs",
+			deprecated: DeprecatedToolCall{
+				Name: "search", Tool: "search_docs",
+				Deprecation: ToolDeprecation{Since: "2.0", Replacement: "search_docs"},
+			},
+			warning: `tool "search" is deprecated since 2.0, use "search_docs" instead`,
+		},
+		{
+			name:     "deprecated tool",
+			tool:     "legacy_export",
+			expected: "exported",
+			deprecated: DeprecatedToolCall{
+				Name: "legacy_export", Tool: "legacy_export",
+				Deprecation: ToolDeprecation{Message: "exports are generated nightly"},
+			},
+			warning: `tool "legacy_export" is deprecated: exports are generated nightly`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = nil
+			response := server.HandleMessage(context.Background(), json.RawMessage(fmt.Sprintf(
+				`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":%q}}`, tt.tool)))
+			resp, ok := response.(mcp.JSONRPCResponse)
+			require.True(t, ok, "unexpected response %#v", response)
+			result := resp.Result.(mcp.CallToolResult)
+			assert.Equal(t, tt.expected, result.Content[0].(mcp.TextContent).Text)
+
+			if tt.warning == "" {
+				assert.Empty(t, calls)
+				assert.Nil(t, result.Meta)
+				return
+			}
+			require.Len(t, calls, 1)
+			assert.Equal(t, tt.deprecated, calls[0])
+			require.NotNil(t, result.Meta)
+			assert.Equal(t, tt.warning, result.Meta.AdditionalFields[DeprecationWarningMeta])
+		})
+	}
+
+	t.Run("listing", func(t *testing.T) {
+		tools := server.listTools(context.Background())
+		require.Len(t, tools, 2, "aliases are not listed")
+		data, err := json.Marshal(tools[1].Meta)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"aliases":[{"name":"find_docs"},{"name":"search","deprecated":{"since":"2.0","replacement":"search_docs"}}]}`, string(data))
+		data, err = json.Marshal(tools[0].Meta)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"deprecated":{"message":"exports are generated nightly"}}`, string(data))
+	})
+
+	t.Run("aliases follow the tool", func(t *testing.T) {
+		server.AddTool(mcp.NewTool("find_docs"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("own tool"), nil
+		})
+		tool, alias, ok := server.findTool(context.Background(), "find_docs")
+		require.True(t, ok)
+		assert.Nil(t, alias, "a tool takes precedence over an alias")
+		assert.Equal(t, "find_docs", tool.Tool.Name)
+
+		server.DeleteTools("search_docs")
+		_, _, ok = server.findTool(context.Background(), "search")
+		assert.False(t, ok)
+	})
+}
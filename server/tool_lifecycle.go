@@ -0,0 +1,218 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ToolLifecycle manages resources a tool handler depends on, such as
+// database pools or browser instances.
+//
+// Init runs once before the first call to the tool, or when the tool is
+// added if the server was created WithEagerInit. It runs with the values
+// of the context of the call that started it but not its cancellation, and
+// is bounded by InitTimeout. If Init fails the tool is marked unavailable:
+// calls return ErrToolUnavailable and tools/list reports the failure in the
+// tool's _meta. Failures from the cancellation or the deadline of the
+// context are not kept, and the next call runs Init again. Close runs when
+// the tool is removed or replaced, and from CloseTools.
+//
+// A ToolLifecycle must not be shared between tools.
+type ToolLifecycle struct {
+	Init  func(ctx context.Context) error
+	Close func()
+	// InitTimeout bounds the run time of Init, one minute if zero.
+	InitTimeout time.Duration
+
+	mu          sync.Mutex
+	initialized atomic.Bool
+	err         atomic.Pointer[error]
+	// running is the Init in progress, if any.
+	running *lifecycleInit
+}
+
+// lifecycleInit is a run of Init.
+type lifecycleInit struct {
+	done chan struct{}
+	err  error
+}
+
+// defaultInitTimeout bounds Init when InitTimeout is zero.
+const defaultInitTimeout = time.Minute
+
+// Err returns the error from a failed Init, or nil if the tool is usable.
+// It does not wait for an Init in progress.
+func (l *ToolLifecycle) Err() error {
+	if err := l.err.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// ensureInit runs Init if it has not run yet. Concurrent callers wait for
+// the same run, each until its own ctx is done. A failed Init is not
+// retried, unless it failed because its context was done.
+func (l *ToolLifecycle) ensureInit(ctx context.Context) error {
+	if l.initialized.Load() {
+		return nil
+	}
+	if err := l.Err(); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	if l.initialized.Load() {
+		l.mu.Unlock()
+		return nil
+	}
+	if err := l.Err(); err != nil {
+		l.mu.Unlock()
+		return err
+	}
+	run := l.running
+	if run == nil {
+		run = &lifecycleInit{done: make(chan struct{})}
+		l.running = run
+		go l.init(context.WithoutCancel(ctx), run)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-run.done:
+		return run.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// init runs Init and records its result.
+func (l *ToolLifecycle) init(ctx context.Context, run *lifecycleInit) {
+	if l.Init != nil {
+		timeout := l.InitTimeout
+		if timeout <= 0 {
+			timeout = defaultInitTimeout
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		run.err = l.Init(ctx)
+		cancel()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	switch {
+	case run.err == nil:
+		l.initialized.Store(true)
+	case !errors.Is(run.err, context.Canceled) && !errors.Is(run.err, context.DeadlineExceeded):
+		l.err.Store(&run.err)
+	}
+	l.running = nil
+	close(run.done)
+}
+
+// close runs Close if Init completed successfully, and resets the
+// lifecycle so the next use initializes it again. It waits for an Init in
+// progress.
+func (l *ToolLifecycle) close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for l.running != nil {
+		run := l.running
+		l.mu.Unlock()
+		<-run.done
+		l.mu.Lock()
+	}
+	if l.initialized.Load() && l.Close != nil {
+		l.Close()
+	}
+	l.initialized.Store(false)
+	l.err.Store(nil)
+}
+
+// WithEagerInit runs each tool's ToolLifecycle Init as soon as the tool is
+// added instead of on first use. Failures are reported to the OnError hooks
+// and mark the tool unavailable.
+func WithEagerInit() ServerOption {
+	return func(s *MCPServer) {
+		s.eagerToolInit = true
+	}
+}
+
+// AddToolWithLifecycle registers a tool whose handler depends on resources
+// managed by lifecycle.
+func (s *MCPServer) AddToolWithLifecycle(tool mcp.Tool, handler ToolHandlerFunc, lifecycle *ToolLifecycle) {
+	s.AddTools(ServerTool{Tool: tool, Handler: handler, Lifecycle: lifecycle})
+}
+
+// CloseTools runs Close for every registered tool that has been initialized.
+// Tools remain registered and are initialized again on next use.
+func (s *MCPServer) CloseTools() {
+	var lifecycles []*ToolLifecycle
+	s.tools.each(func(_ string, tool ServerTool) bool {
+		if tool.Lifecycle != nil {
+			lifecycles = append(lifecycles, tool.Lifecycle)
+		}
+		return true
+	})
+
+	for _, lifecycle := range lifecycles {
+		lifecycle.close()
+	}
+}
+
+// eagerInitTools initializes the lifecycles of newly added tools when
+// WithEagerInit is enabled.
+func (s *MCPServer) eagerInitTools(tools []ServerTool) {
+	if !s.eagerToolInit {
+		return
+	}
+	ctx := context.Background()
+	for _, tool := range tools {
+		if tool.Lifecycle == nil {
+			continue
+		}
+		if err := tool.Lifecycle.ensureInit(ctx); err != nil {
+			s.hooks.onError(ctx, nil, mcp.MethodToolsCall, tool.Tool.Name,
+				fmt.Errorf("tool '%s' init failed: %w", tool.Tool.Name, err))
+		}
+	}
+}
+
+// closeTools closes the lifecycles of tools that were removed or replaced.
+// It must be called without holding toolsMu.
+func closeTools(tools []ServerTool) {
+	for _, tool := range tools {
+		if tool.Lifecycle != nil {
+			tool.Lifecycle.close()
+		}
+	}
+}
+
+// listedTool returns the tool definition for tools/list, marking tools whose
+// initialization failed as unavailable.
+func listedTool(tool ServerTool) mcp.Tool {
+	if tool.Lifecycle == nil {
+		return tool.Tool
+	}
+	err := tool.Lifecycle.Err()
+	if err == nil {
+		return tool.Tool
+	}
+	listed := tool.Tool
+	meta := &mcp.Meta{AdditionalFields: make(map[string]any)}
+	if listed.Meta != nil {
+		meta.ProgressToken = listed.Meta.ProgressToken
+		maps.Copy(meta.AdditionalFields, listed.Meta.AdditionalFields)
+	}
+	meta.AdditionalFields["unavailable"] = true
+	meta.AdditionalFields["unavailableReason"] = err.Error()
+	listed.Meta = meta
+	return listed
+}
@@ -0,0 +1,195 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func echoToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return mcp.NewToolResultText("ok"), nil
+}
+
+func callTool(server *MCPServer, name string) mcp.JSONRPCMessage {
+	return server.HandleMessage(context.Background(), json.RawMessage(
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"`+name+`"}}`,
+	))
+}
+
+func listTools(t *testing.T, server *MCPServer) []mcp.Tool {
+	t.Helper()
+	response := server.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "expected success response, got %#v", response)
+	result, ok := resp.Result.(mcp.ListToolsResult)
+	require.True(t, ok)
+	return result.Tools
+}
+
+func TestToolLifecycle_LazyInit(t *testing.T) {
+	var inits, closes atomic.Int32
+	server := NewMCPServer("test-server", "1.0.0")
+	server.AddToolWithLifecycle(mcp.NewTool("db"), echoToolHandler, &ToolLifecycle{
+		Init: func(ctx context.Context) error {
+			inits.Add(1)
+			return nil
+		},
+		Close: func() { closes.Add(1) },
+	})
+	assert.Equal(t, int32(0), inits.Load(), "init must not run before first use")
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, ok := callTool(server, "db").(mcp.JSONRPCResponse)
+			assert.True(t, ok)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), inits.Load())
+
+	server.CloseTools()
+	assert.Equal(t, int32(1), closes.Load())
+
+	_, ok := callTool(server, "db").(mcp.JSONRPCResponse)
+	assert.True(t, ok)
+	assert.Equal(t, int32(2), inits.Load(), "tool is initialized again after CloseTools")
+
+	server.DeleteTools("db")
+	assert.Equal(t, int32(2), closes.Load())
+}
+
+func TestToolLifecycle_InitFailure(t *testing.T) {
+	initErr := errors.New("connection refused")
+	var inits atomic.Int32
+	server := NewMCPServer("test-server", "1.0.0")
+	server.AddTool(mcp.NewTool("healthy"), echoToolHandler)
+	server.AddToolWithLifecycle(mcp.NewTool("db"), echoToolHandler, &ToolLifecycle{
+		Init: func(ctx context.Context) error {
+			inits.Add(1)
+			return initErr
+		},
+	})
+
+	for range 2 {
+		response := callTool(server, "db")
+		errorResponse, ok := response.(mcp.JSONRPCError)
+		require.True(t, ok, "expected error response, got %#v", response)
+		assert.Equal(t, mcp.INTERNAL_ERROR, errorResponse.Error.Code)
+		assert.Contains(t, errorResponse.Error.Message, ErrToolUnavailable.Error())
+		assert.Contains(t, errorResponse.Error.Message, "connection refused")
+	}
+	assert.Equal(t, int32(1), inits.Load(), "failed init is not retried")
+
+	tools := listTools(t, server)
+	require.Len(t, tools, 2)
+	assert.Equal(t, "db", tools[0].Name)
+	require.NotNil(t, tools[0].Meta)
+	assert.Equal(t, true, tools[0].Meta.AdditionalFields["unavailable"])
+	assert.Equal(t, "connection refused", tools[0].Meta.AdditionalFields["unavailableReason"])
+	assert.Nil(t, tools[1].Meta)
+}
+
+func TestToolLifecycle_EagerInit(t *testing.T) {
+	initErr := errors.New("browser failed to start")
+	var reported error
+	hooks := &Hooks{}
+	hooks.AddOnError(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+		reported = err
+	})
+
+	var inits atomic.Int32
+	server := NewMCPServer("test-server", "1.0.0", WithEagerInit(), WithHooks(hooks))
+	server.AddToolWithLifecycle(mcp.NewTool("ok"), echoToolHandler, &ToolLifecycle{
+		Init: func(ctx context.Context) error {
+			inits.Add(1)
+			return nil
+		},
+	})
+	assert.Equal(t, int32(1), inits.Load(), "init runs when the tool is added")
+
+	lifecycle := &ToolLifecycle{
+		Init: func(ctx context.Context) error { return initErr },
+	}
+	server.AddToolWithLifecycle(mcp.NewTool("browser"), echoToolHandler, lifecycle)
+	assert.ErrorIs(t, reported, initErr)
+	assert.ErrorIs(t, lifecycle.Err(), initErr)
+
+	_, ok := callTool(server, "ok").(mcp.JSONRPCResponse)
+	assert.True(t, ok)
+	assert.Equal(t, int32(1), inits.Load())
+}
+
+func TestToolLifecycle_ReplacedToolIsClosed(t *testing.T) {
+	var closes atomic.Int32
+	newLifecycle := func() *ToolLifecycle {
+		return &ToolLifecycle{Close: func() { closes.Add(1) }}
+	}
+
+	server := NewMCPServer("test-server", "1.0.0")
+	server.AddToolWithLifecycle(mcp.NewTool("db"), echoToolHandler, newLifecycle())
+	callTool(server, "db")
+
+	server.AddToolWithLifecycle(mcp.NewTool("db"), echoToolHandler, newLifecycle())
+	assert.Equal(t, int32(1), closes.Load())
+
+	callTool(server, "db")
+	server.SetTools(ServerTool{Tool: mcp.NewTool("other"), Handler: echoToolHandler})
+	assert.Equal(t, int32(2), closes.Load())
+}
+
+func TestToolLifecycle_CanceledCall(t *testing.T) {
+	release := make(chan struct{})
+	var inits atomic.Int32
+	lifecycle := &ToolLifecycle{
+		Init: func(ctx context.Context) error {
+			if inits.Add(1) == 1 {
+				// The first run outlives the call that started it
+				<-release
+				return ctx.Err()
+			}
+			return nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() { errs <- lifecycle.ensureInit(ctx) }()
+	require.Eventually(t, func() bool { return inits.Load() == 1 }, time.Second, time.Millisecond)
+	assert.NoError(t, lifecycle.Err(), "Err does not wait for Init")
+
+	cancel()
+	assert.ErrorIs(t, <-errs, context.Canceled)
+	close(release)
+	require.NoError(t, lifecycle.ensureInit(context.Background()), "Init is not canceled with the call")
+	assert.Equal(t, int32(1), inits.Load())
+}
+
+func TestToolLifecycle_InitTimeout(t *testing.T) {
+	var inits atomic.Int32
+	lifecycle := &ToolLifecycle{
+		InitTimeout: 10 * time.Millisecond,
+		Init: func(ctx context.Context) error {
+			if inits.Add(1) == 1 {
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			return nil
+		},
+	}
+	assert.ErrorIs(t, lifecycle.ensureInit(context.Background()), context.DeadlineExceeded)
+	assert.NoError(t, lifecycle.Err(), "timeouts are not kept")
+	assert.NoError(t, lifecycle.ensureInit(context.Background()), "Init runs again after a timeout")
+	assert.Equal(t, int32(2), inits.Load())
+}
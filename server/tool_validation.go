@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ToolCallCheckFunc inspects a tool call before its handler runs and
+// returns an error to reject it. Checks run for every tools/call and
+// tools/validate request, so they are the place for cheap policy such as
+// authorization or rate limiting that hosts may want to preflight.
+//
+// Errors returned from a check are reported with the code chosen by
+// WithErrorCodeMapper.
+type ToolCallCheckFunc func(ctx context.Context, request mcp.CallToolRequest) error
+
+// WithToolCallCheck adds a check that runs before every tool call and
+// during tools/validate. Checks run in the order they were added.
+func WithToolCallCheck(check ToolCallCheckFunc) ServerOption {
+	return func(s *MCPServer) {
+		s.toolCallChecksMu.Lock()
+		s.toolCallChecks = append(s.toolCallChecks, check)
+		s.toolCallChecksMu.Unlock()
+	}
+}
+
+// runToolCallChecks runs the registered checks and returns the first error.
+func (s *MCPServer) runToolCallChecks(ctx context.Context, request mcp.CallToolRequest) error {
+	s.toolCallChecksMu.RLock()
+	checks := s.toolCallChecks
+	s.toolCallChecksMu.RUnlock()
+
+	for _, check := range checks {
+		if err := check(ctx, request); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleValidateTool answers a tools/validate request: it validates the
+// arguments against the tool's input schema and runs the tool call checks,
+// but never invokes the handler or initializes the tool's lifecycle. A
+// call that the rate limits would reject is reported as a diagnostic; the
+// request itself takes no tokens.
+func (s *MCPServer) handleValidateTool(
+	ctx context.Context,
+	id any,
+	request mcp.ValidateToolRequest,
+) (*mcp.ValidateToolResult, *requestError) {
+	callRequest := mcp.CallToolRequest{
+		Request: request.Request,
+		Header:  request.Header,
+		Params:  request.Params,
+	}
+	tool, _, ok := s.findTool(ctx, request.Params.Name)
+	if !ok {
+		tool, callRequest, ok = s.findVersionedTool(ctx, callRequest)
+	}
+	if !ok {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INVALID_PARAMS,
+			err:  fmt.Errorf("tool '%s' not found: %w", request.Params.Name, ErrToolNotFound),
+			data: mcp.NotFoundErrorData{Type: "tool", Name: request.Params.Name},
+		}
+	}
+
+	var diagnostics []mcp.ToolCallDiagnostic
+
+	if err := s.rateLimiter.peek(ctx, tool.Tool.Name); err != nil {
+		diagnostics = append(diagnostics, mcp.ToolCallDiagnostic{Message: err.Error()})
+	}
+
+	if tool.Lifecycle != nil {
+		if err := tool.Lifecycle.Err(); err != nil {
+			diagnostics = append(diagnostics, mcp.ToolCallDiagnostic{
+				Message: fmt.Sprintf("%s: %v", ErrToolUnavailable, err),
+			})
+		}
+	}
+
+	definition, arguments := tool.Tool, request.Params.Arguments
+	if tool.versions != nil {
+		i, versionArguments, err := tool.versions.selected(callRequest)
+		if err != nil {
+			diagnostics = append(diagnostics, mcp.ToolCallDiagnostic{Message: err.Error()})
+		} else {
+			definition, arguments = tool.versions.versions[i].Tool, versionArguments
+		}
+	}
+
+	schemaErrs, err := definition.ValidateArguments(arguments)
+	if err != nil {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INTERNAL_ERROR,
+			err:  fmt.Errorf("tool '%s' has an invalid input schema: %w", request.Params.Name, err),
+		}
+	}
+	for _, schemaErr := range schemaErrs {
+		diagnostics = append(diagnostics, mcp.ToolCallDiagnostic{
+			Path:    schemaErr.Path,
+			Message: schemaErr.Message,
+		})
+	}
+
+	if err := s.runToolCallChecks(ctx, callRequest); err != nil {
+		diagnostics = append(diagnostics, mcp.ToolCallDiagnostic{Message: err.Error()})
+	}
+
+	return &mcp.ValidateToolResult{
+		Valid:       len(diagnostics) == 0,
+		Diagnostics: diagnostics,
+	}, nil
+}
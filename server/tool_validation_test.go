@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestMCPServer_ValidateTool(t *testing.T) {
+	errRateLimited := errors.New("rate limit exceeded")
+	var handlerCalls int
+
+	server := NewMCPServer("test-server", "1.0.0",
+		WithToolCallCheck(func(ctx context.Context, request mcp.CallToolRequest) error {
+			if request.GetString("query", "") == "expensive" {
+				return errRateLimited
+			}
+			return nil
+		}),
+		WithErrorCodeMapper(mcp.ErrorCodeTable{{Err: errRateLimited, Code: -31029}}.Code),
+	)
+	server.AddTool(mcp.NewTool("search",
+		mcp.WithString("query", mcp.Required()),
+		mcp.WithNumber("limit", mcp.Min(1)),
+	), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		handlerCalls++
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	validate := func(t *testing.T, params string) mcp.ValidateToolResult {
+		t.Helper()
+		response := server.HandleMessage(context.Background(), json.RawMessage(
+			`{"jsonrpc":"2.0","id":1,"method":"tools/validate","params":`+params+`}`,
+		))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "expected success response, got %#v", response)
+		result, ok := resp.Result.(mcp.ValidateToolResult)
+		require.True(t, ok)
+		return result
+	}
+
+	tests := []struct {
+		name        string
+		params      string
+		valid       bool
+		diagnostics []mcp.ToolCallDiagnostic
+	}{
+		{
+			name:   "valid call",
+			params: `{"name":"search","arguments":{"query":"go","limit":5}}`,
+			valid:  true,
+		},
+		{
+			name:   "schema violations",
+			params: `{"name":"search","arguments":{"limit":0}}`,
+			diagnostics: []mcp.ToolCallDiagnostic{
+				{Path: "/query", Message: "required property is missing"},
+				{Path: "/limit", Message: "value 0 is less than the minimum 1"},
+			},
+		},
+		{
+			name:        "check rejects call",
+			params:      `{"name":"search","arguments":{"query":"expensive"}}`,
+			diagnostics: []mcp.ToolCallDiagnostic{{Message: "rate limit exceeded"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validate(t, tt.params)
+			assert.Equal(t, tt.valid, result.Valid)
+			assert.Equal(t, tt.diagnostics, result.Diagnostics)
+		})
+	}
+	assert.Equal(t, 0, handlerCalls, "validation must not run the handler")
+
+	t.Run("unknown tool", func(t *testing.T) {
+		response := server.HandleMessage(context.Background(), json.RawMessage(
+			`{"jsonrpc":"2.0","id":1,"method":"tools/validate","params":{"name":"missing"}}`,
+		))
+		errorResponse, ok := response.(mcp.JSONRPCError)
+		require.True(t, ok, "expected error response, got %#v", response)
+		assert.Equal(t, mcp.INVALID_PARAMS, errorResponse.Error.Code)
+	})
+
+	t.Run("checks also guard tools/call", func(t *testing.T) {
+		response := server.HandleMessage(context.Background(), json.RawMessage(
+			`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"search","arguments":{"query":"expensive"}}}`,
+		))
+		errorResponse, ok := response.(mcp.JSONRPCError)
+		require.True(t, ok, "expected error response, got %#v", response)
+		assert.Equal(t, -31029, errorResponse.Error.Code)
+		assert.Equal(t, 0, handlerCalls)
+	})
+}
+
+func TestMCPServer_ValidateTool_UnavailableTool(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithEagerInit())
+	server.AddToolWithLifecycle(mcp.NewTool("db"), echoToolHandler, &ToolLifecycle{
+		Init: func(ctx context.Context) error { return errors.New("connection refused") },
+	})
+
+	response := server.HandleMessage(context.Background(), json.RawMessage(
+		`{"jsonrpc":"2.0","id":1,"method":"tools/validate","params":{"name":"db"}}`,
+	))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "expected success response, got %#v", response)
+	result := resp.Result.(mcp.ValidateToolResult)
+	assert.False(t, result.Valid)
+	require.Len(t, result.Diagnostics, 1)
+	assert.Contains(t, result.Diagnostics[0].Message, "connection refused")
+}
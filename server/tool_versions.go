@@ -0,0 +1,193 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// ToolVersionMeta is the _meta field of tool calls selecting the version
+	// of a versioned tool.
+	ToolVersionMeta = "toolVersion"
+	// ToolVersionArgument is the argument of tool calls selecting the
+	// version of a versioned tool. It is removed from the arguments before
+	// they reach the handler.
+	ToolVersionArgument = "_toolVersion"
+)
+
+// toolVersionSeparator separates the name of a versioned tool from the
+// version in names such as "search@v1".
+const toolVersionSeparator = "@"
+
+// ToolVersion is one version of a VersionedTool.
+type ToolVersion struct {
+	// Version names the version, such as "v1". It must not contain "@".
+	Version string
+	// Tool is the definition of this version. Its name is ignored.
+	Tool mcp.Tool
+	// Handler handles calls of this version. If it is nil, calls are
+	// migrated to the next version.
+	Handler ToolHandlerFunc
+	// Migrate optionally adapts arguments shaped for the previous version
+	// to the schema of this version. It is used when calls of the previous
+	// version are handled by this or a later version.
+	Migrate func(ctx context.Context, arguments map[string]any) (map[string]any, error)
+}
+
+// VersionedTool is a tool with several versions, letting servers evolve the
+// schema of a tool without breaking clients written for an older one.
+//
+// tools/list lists the default version under Name, with the available
+// versions in its _meta under "versions". Calls select a version by calling
+// "<name>@<version>", or with ToolVersionMeta in their _meta or
+// ToolVersionArgument in their arguments, in that order of precedence;
+// other calls get the default version. A version without a handler is
+// served by the next version with one, its arguments passing through the
+// Migrate functions of the versions in between.
+type VersionedTool struct {
+	// Name is the name the tool is listed and called by.
+	Name string
+	// Default names the version of calls selecting none. It defaults to
+	// the last version.
+	Default string
+	// Versions lists the versions from the oldest to the newest.
+	Versions []ToolVersion
+}
+
+// toolVersions is the registered form of a VersionedTool.
+type toolVersions struct {
+	name     string
+	versions []ToolVersion
+	index    map[string]int
+	fallback int
+}
+
+// AddVersionedTool registers tool, replacing any tool with the same name.
+// It returns an error if tool has no versions, duplicate or invalid version
+// names, an unknown default version, or no handler for its last version.
+func (s *MCPServer) AddVersionedTool(tool VersionedTool) error {
+	if len(tool.Versions) == 0 {
+		return fmt.Errorf("versioned tool %q has no versions", tool.Name)
+	}
+	versions := &toolVersions{name: tool.Name, versions: tool.Versions, index: make(map[string]int, len(tool.Versions))}
+	for i, version := range tool.Versions {
+		if version.Version == "" || strings.Contains(version.Version, toolVersionSeparator) {
+			return fmt.Errorf("versioned tool %q has an invalid version %q", tool.Name, version.Version)
+		}
+		if _, ok := versions.index[version.Version]; ok {
+			return fmt.Errorf("versioned tool %q has version %q twice", tool.Name, version.Version)
+		}
+		versions.index[version.Version] = i
+	}
+	if tool.Versions[len(tool.Versions)-1].Handler == nil {
+		return fmt.Errorf("the last version of versioned tool %q has no handler", tool.Name)
+	}
+	versions.fallback = len(tool.Versions) - 1
+	if tool.Default != "" {
+		i, ok := versions.index[tool.Default]
+		if !ok {
+			return fmt.Errorf("versioned tool %q has no default version %q", tool.Name, tool.Default)
+		}
+		versions.fallback = i
+	}
+
+	listed := tool.Versions[versions.fallback].Tool
+	listed.Name = tool.Name
+	meta := listed.Meta.Clone()
+	if meta == nil {
+		meta = &mcp.Meta{}
+	}
+	names := make([]string, len(tool.Versions))
+	for i, version := range tool.Versions {
+		names[i] = version.Version
+	}
+	meta.Set("version", tool.Versions[versions.fallback].Version)
+	meta.Set("versions", names)
+	listed.Meta = meta
+
+	s.AddTools(ServerTool{Tool: listed, Handler: versions.handle, versions: versions})
+	return nil
+}
+
+// splitToolVersion splits a "<name>@<version>" tool name.
+func splitToolVersion(name string) (string, string, bool) {
+	i := strings.LastIndex(name, toolVersionSeparator)
+	if i <= 0 || i == len(name)-1 {
+		return "", "", false
+	}
+	return name[:i], name[i+1:], true
+}
+
+// withToolVersion returns request selecting version in its _meta.
+func withToolVersion(request mcp.CallToolRequest, version string) mcp.CallToolRequest {
+	meta := request.Params.Meta.Clone()
+	if meta == nil {
+		meta = &mcp.Meta{}
+	}
+	meta.Set(ToolVersionMeta, version)
+	request.Params.Meta = meta
+	return request
+}
+
+// selected returns the index of the version selected by request and the
+// arguments without ToolVersionArgument.
+func (v *toolVersions) selected(request mcp.CallToolRequest) (int, map[string]any, error) {
+	arguments := request.GetArguments()
+	version := v.versions[v.fallback].Version
+	if selected, ok := arguments[ToolVersionArgument].(string); ok {
+		version = selected
+	}
+	if selected, ok := mcp.MetaValue[string](request.Params.Meta, ToolVersionMeta); ok {
+		version = selected
+	}
+	if _, ok := arguments[ToolVersionArgument]; ok {
+		arguments = maps.Clone(arguments)
+		delete(arguments, ToolVersionArgument)
+	}
+	i, ok := v.index[version]
+	if !ok {
+		return 0, nil, fmt.Errorf("tool %q has no version %q: %w: %w", v.name, version, ErrToolVersionNotFound, mcp.ErrInvalidParams)
+	}
+	return i, arguments, nil
+}
+
+// handle serves a call with the selected version.
+func (v *toolVersions) handle(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	i, arguments, err := v.selected(request)
+	if err != nil {
+		return nil, err
+	}
+	for ; v.versions[i].Handler == nil; i++ {
+		migrate := v.versions[i+1].Migrate
+		if migrate == nil {
+			continue
+		}
+		migrated, err := migrate(ctx, arguments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to migrate arguments of tool %q to version %q: %w: %w",
+				v.name, v.versions[i+1].Version, err, mcp.ErrInvalidParams)
+		}
+		arguments = migrated
+	}
+	request.Params.Arguments = arguments
+	return v.versions[i].Handler(ctx, request)
+}
+
+// findVersionedTool looks up the tool of a "<name>@<version>" call and
+// returns the request selecting the version.
+func (s *MCPServer) findVersionedTool(ctx context.Context, request mcp.CallToolRequest) (ServerTool, mcp.CallToolRequest, bool) {
+	name, version, ok := splitToolVersion(request.Params.Name)
+	if !ok {
+		return ServerTool{}, request, false
+	}
+	tool, alias, ok := s.findTool(ctx, name)
+	if !ok || alias != nil || tool.versions == nil {
+		return ServerTool{}, request, false
+	}
+	request.Params.Name = name
+	return tool, withToolVersion(request, version), true
+}
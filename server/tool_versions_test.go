@@ -0,0 +1,207 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func newVersionedSearchServer(t *testing.T) *MCPServer {
+	t.Helper()
+	s := NewMCPServer("test-server", "1.0.0", WithToolCapabilities(true))
+	err := s.AddVersionedTool(VersionedTool{
+		Name:    "search",
+		Default: "v2",
+		Versions: []ToolVersion{
+			{
+				Version: "v1",
+				Tool:    mcp.NewTool("search", mcp.WithString("q", mcp.Required())),
+			},
+			{
+				Version: "v2",
+				Tool:    mcp.NewTool("search", mcp.WithString("query", mcp.Required())),
+				Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+					return mcp.NewToolResultText("v2:" + request.GetString("query", "")), nil
+				},
+				Migrate: func(ctx context.Context, arguments map[string]any) (map[string]any, error) {
+					q, ok := arguments["q"]
+					if !ok {
+						return nil, errors.New("missing q")
+					}
+					return map[string]any{"query": q}, nil
+				},
+			},
+			{
+				Version: "v3",
+				Tool:    mcp.NewTool("search", mcp.WithString("query", mcp.Required()), mcp.WithNumber("limit")),
+				Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+					_, selected := request.GetArguments()[ToolVersionArgument]
+					assert.False(t, selected, "version argument reached the handler")
+					return mcp.NewToolResultText("v3:" + request.GetString("query", "")), nil
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	return s
+}
+
+func TestMCPServer_AddVersionedTool(t *testing.T) {
+	s := newVersionedSearchServer(t)
+
+	tests := []struct {
+		name    string
+		params  string
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "default version",
+			params: `{"name":"search","arguments":{"query":"go"}}`,
+			want:   "v2:go",
+		},
+		{
+			name:   "version in name",
+			params: `{"name":"search@v3","arguments":{"query":"go"}}`,
+			want:   "v3:go",
+		},
+		{
+			name:   "version in meta",
+			params: `{"name":"search","arguments":{"query":"go"},"_meta":{"toolVersion":"v3"}}`,
+			want:   "v3:go",
+		},
+		{
+			name:   "version in arguments",
+			params: `{"name":"search","arguments":{"query":"go","_toolVersion":"v3"}}`,
+			want:   "v3:go",
+		},
+		{
+			name:   "meta takes precedence over arguments",
+			params: `{"name":"search","arguments":{"query":"go","_toolVersion":"v2"},"_meta":{"toolVersion":"v3"}}`,
+			want:   "v3:go",
+		},
+		{
+			name:   "migrated version",
+			params: `{"name":"search@v1","arguments":{"q":"go"}}`,
+			want:   "v2:go",
+		},
+		{
+			name:    "migration error",
+			params:  `{"name":"search@v1","arguments":{"query":"go"}}`,
+			wantErr: `failed to migrate arguments of tool "search" to version "v2": missing q`,
+		},
+		{
+			name:    "unknown version",
+			params:  `{"name":"search","arguments":{"query":"go"},"_meta":{"toolVersion":"v9"}}`,
+			wantErr: `tool "search" has no version "v9"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := s.HandleMessage(context.Background(), json.RawMessage(
+				`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":`+tt.params+`}`,
+			))
+			if tt.wantErr != "" {
+				resp, ok := response.(mcp.JSONRPCError)
+				require.True(t, ok, "expected error response, got %#v", response)
+				assert.Equal(t, mcp.INVALID_PARAMS, resp.Error.Code)
+				assert.Contains(t, resp.Error.Message, tt.wantErr)
+				return
+			}
+			resp, ok := response.(mcp.JSONRPCResponse)
+			require.True(t, ok, "expected success response, got %#v", response)
+			result, ok := resp.Result.(mcp.CallToolResult)
+			require.True(t, ok)
+			require.Len(t, result.Content, 1)
+			assert.Equal(t, tt.want, result.Content[0].(mcp.TextContent).Text)
+		})
+	}
+
+	t.Run("unknown tool with version", func(t *testing.T) {
+		response := s.HandleMessage(context.Background(), json.RawMessage(
+			`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"missing@v1"}}`,
+		))
+		resp, ok := response.(mcp.JSONRPCError)
+		require.True(t, ok, "expected error response, got %#v", response)
+		assert.Contains(t, resp.Error.Message, "tool 'missing@v1' not found")
+	})
+
+	t.Run("list", func(t *testing.T) {
+		response := s.HandleMessage(context.Background(), json.RawMessage(
+			`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`,
+		))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "expected success response, got %#v", response)
+		result, ok := resp.Result.(mcp.ListToolsResult)
+		require.True(t, ok)
+		require.Len(t, result.Tools, 1)
+		tool := result.Tools[0]
+		assert.Equal(t, "search", tool.Name)
+		assert.Contains(t, tool.InputSchema.Properties, "query")
+		assert.NotContains(t, tool.InputSchema.Properties, "limit")
+		assert.Equal(t, "v2", tool.Meta.AdditionalFields["version"])
+		assert.Equal(t, []string{"v1", "v2", "v3"}, tool.Meta.AdditionalFields["versions"])
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		response := s.HandleMessage(context.Background(), json.RawMessage(
+			`{"jsonrpc":"2.0","id":1,"method":"tools/validate","params":{"name":"search@v1","arguments":{"q":"go"}}}`,
+		))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "expected success response, got %#v", response)
+		result, ok := resp.Result.(mcp.ValidateToolResult)
+		require.True(t, ok)
+		assert.True(t, result.Valid, "%v", result.Diagnostics)
+	})
+}
+
+func TestMCPServer_AddVersionedTool_Invalid(t *testing.T) {
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, nil
+	}
+	tests := []struct {
+		name    string
+		tool    VersionedTool
+		wantErr string
+	}{
+		{
+			name:    "no versions",
+			tool:    VersionedTool{Name: "t"},
+			wantErr: `versioned tool "t" has no versions`,
+		},
+		{
+			name:    "invalid version",
+			tool:    VersionedTool{Name: "t", Versions: []ToolVersion{{Version: "v@1", Handler: handler}}},
+			wantErr: `versioned tool "t" has an invalid version "v@1"`,
+		},
+		{
+			name:    "duplicate version",
+			tool:    VersionedTool{Name: "t", Versions: []ToolVersion{{Version: "v1", Handler: handler}, {Version: "v1", Handler: handler}}},
+			wantErr: `versioned tool "t" has version "v1" twice`,
+		},
+		{
+			name:    "last version without handler",
+			tool:    VersionedTool{Name: "t", Versions: []ToolVersion{{Version: "v1"}}},
+			wantErr: `the last version of versioned tool "t" has no handler`,
+		},
+		{
+			name:    "unknown default",
+			tool:    VersionedTool{Name: "t", Default: "v2", Versions: []ToolVersion{{Version: "v1", Handler: handler}}},
+			wantErr: `versioned tool "t" has no default version "v2"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewMCPServer("test-server", "1.0.0")
+			assert.EqualError(t, s.AddVersionedTool(tt.tool), tt.wantErr)
+			assert.Empty(t, s.ListTools())
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// SessionToolFilterFunc decides which tools a session may see and call.
+// It receives the session making the request (nil outside of a session)
+// and returns the subset of tools that remain visible, typically based on
+// the session's client info or the AuthInfo in ctx.
+type SessionToolFilterFunc func(ctx context.Context, session ClientSession, tools []mcp.Tool) []mcp.Tool
+
+// WithSessionToolFilter adds a per-session visibility policy. Unlike
+// WithToolFilter, which only trims tools/list, hidden tools are also
+// rejected by tools/call and tools/validate as if they did not exist.
+//
+// When tools are added or deleted, only the sessions whose visible set
+// changed receive notifications/tools/list_changed. When the inputs of a
+// policy change, call RefreshToolVisibility so the affected sessions are
+// notified too.
+func WithSessionToolFilter(filter SessionToolFilterFunc) ServerOption {
+	return func(s *MCPServer) {
+		s.toolFiltersMu.Lock()
+		s.sessionToolFilters = append(s.sessionToolFilters, filter)
+		s.toolFiltersMu.Unlock()
+	}
+}
+
+// applySessionToolFilters runs the session tool filters over tools.
+func (s *MCPServer) applySessionToolFilters(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	s.toolFiltersMu.RLock()
+	filters := s.sessionToolFilters
+	s.toolFiltersMu.RUnlock()
+
+	if len(filters) == 0 {
+		return tools
+	}
+	session := ClientSessionFromContext(ctx)
+	for _, filter := range filters {
+		tools = filter(ctx, session, tools)
+	}
+	return tools
+}
+
+// toolVisible reports whether the session tool filters keep tool.
+func (s *MCPServer) toolVisible(ctx context.Context, tool mcp.Tool) bool {
+	visible := s.applySessionToolFilters(ctx, []mcp.Tool{tool})
+	return slices.ContainsFunc(visible, func(t mcp.Tool) bool { return t.Name == tool.Name })
+}
+
+// listedTools is what a session was last sent in tools/list.
+type listedTools struct {
+	// names are the sorted names of the tools.
+	names []string
+	// ctx is the context of the request, without its cancellation, in
+	// which the filters are re-evaluated when the tools of the server
+	// change.
+	ctx context.Context
+}
+
+// toolNames returns the sorted names of tools.
+func toolNames(tools []mcp.Tool) []string {
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// recordVisibleTools remembers the tool names listed to a session in ctx,
+// so that changes of the visible set can be detected.
+func (s *MCPServer) recordVisibleTools(ctx context.Context, sessionID string, tools []mcp.Tool) {
+	s.visibleToolsMu.Lock()
+	s.visibleTools[sessionID] = listedTools{names: toolNames(tools), ctx: context.WithoutCancel(ctx)}
+	s.visibleToolsMu.Unlock()
+}
+
+func (s *MCPServer) forgetVisibleTools(sessionID string) {
+	s.visibleToolsMu.Lock()
+	delete(s.visibleTools, sessionID)
+	s.visibleToolsMu.Unlock()
+}
+
+// lastToolsListContext returns the context of the last tools/list request
+// of a session, if it has listed tools.
+func (s *MCPServer) lastToolsListContext(sessionID string) (context.Context, bool) {
+	s.visibleToolsMu.Lock()
+	defer s.visibleToolsMu.Unlock()
+	listed, ok := s.visibleTools[sessionID]
+	return listed.ctx, ok
+}
+
+// updateVisibleTools re-evaluates the tools visible to a session that has
+// listed tools, passing ctx to the filters, and reports whether they differ
+// from those it was last sent, remembering the new set if so.
+func (s *MCPServer) updateVisibleTools(ctx context.Context, session ClientSession) bool {
+	sessionID := session.SessionID()
+	s.visibleToolsMu.Lock()
+	listed, ok := s.visibleTools[sessionID]
+	s.visibleToolsMu.Unlock()
+	if !ok {
+		return false
+	}
+	names := toolNames(s.listTools(s.WithContext(ctx, session)))
+
+	s.visibleToolsMu.Lock()
+	defer s.visibleToolsMu.Unlock()
+	if _, ok := s.visibleTools[sessionID]; !ok || slices.Equal(listed.names, names) {
+		return false
+	}
+	s.visibleTools[sessionID] = listedTools{names: names, ctx: listed.ctx}
+	return true
+}
+
+// toolsListChanged reports whether the server sends
+// notifications/tools/list_changed.
+func (s *MCPServer) toolsListChanged() bool {
+	s.capabilitiesMu.RLock()
+	defer s.capabilitiesMu.RUnlock()
+	return s.capabilities.tools != nil && s.capabilities.tools.listChanged
+}
+
+// notifyToolsListChanged tells the sessions that the tools of the server
+// changed. With session tool filters, only the sessions whose visible
+// tools changed are notified.
+func (s *MCPServer) notifyToolsListChanged() {
+	s.toolFiltersMu.RLock()
+	filtered := len(s.sessionToolFilters) > 0
+	s.toolFiltersMu.RUnlock()
+	if !filtered {
+		s.notifyListChanged(mcp.MethodNotificationToolsListChanged)
+		return
+	}
+
+	s.sessions.Range(func(_, value any) bool {
+		session, ok := value.(ClientSession)
+		if !ok || !session.Initialized() {
+			return true
+		}
+		ctx, listed := s.lastToolsListContext(session.SessionID())
+		if listed && s.updateVisibleTools(ctx, session) {
+			_ = s.notifySessionListChanged(session.SessionID(), mcp.MethodNotificationToolsListChanged)
+		}
+		return true
+	})
+}
+
+// RefreshToolVisibility re-evaluates the tool filters for a session and
+// sends it notifications/tools/list_changed if the set of visible tools
+// differs from what it was last sent in tools/list. ctx is passed to the
+// filters, so it should carry whatever they depend on, such as AuthInfo.
+//
+// Changes of the tools of the server are detected without it; it is needed
+// when the inputs of the filters change. Sessions that have not listed
+// tools yet are not notified.
+func (s *MCPServer) RefreshToolVisibility(ctx context.Context, sessionID string) error {
+	sessionValue, ok := s.sessions.Load(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+	session := sessionValue.(ClientSession)
+
+	if !s.updateVisibleTools(ctx, session) || !session.Initialized() || !s.toolsListChanged() {
+		return nil
+	}
+	return s.notifySessionListChanged(sessionID, mcp.MethodNotificationToolsListChanged)
+}
+
+// RefreshAllToolVisibility calls RefreshToolVisibility for every
+// registered session and returns the joined errors.
+func (s *MCPServer) RefreshAllToolVisibility(ctx context.Context) error {
+	var errs []error
+	s.sessions.Range(func(key, _ any) bool {
+		if err := s.RefreshToolVisibility(ctx, key.(string)); err != nil && !errors.Is(err, ErrSessionNotFound) {
+			errs = append(errs, err)
+		}
+		return true
+	})
+	return errors.Join(errs...)
+}
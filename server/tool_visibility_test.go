@@ -0,0 +1,140 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestMCPServer_WithSessionToolFilter(t *testing.T) {
+	var mu sync.Mutex
+	admins := map[string]bool{"alice": true}
+	isAdmin := func(sessionID string) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return admins[sessionID]
+	}
+
+	server := NewMCPServer("test-server", "1.0.0",
+		WithToolCapabilities(true),
+		WithSessionToolFilter(func(ctx context.Context, session ClientSession, tools []mcp.Tool) []mcp.Tool {
+			if session != nil && isAdmin(session.SessionID()) {
+				return tools
+			}
+			visible := make([]mcp.Tool, 0, len(tools))
+			for _, tool := range tools {
+				if tool.Name != "admin" {
+					visible = append(visible, tool)
+				}
+			}
+			return visible
+		}),
+	)
+	server.AddTool(mcp.NewTool("admin"), echoToolHandler)
+	server.AddTool(mcp.NewTool("search"), echoToolHandler)
+
+	alice := fakeSession{sessionID: "alice", notificationChannel: make(chan mcp.JSONRPCNotification, 10), initialized: true}
+	bob := fakeSession{sessionID: "bob", notificationChannel: make(chan mcp.JSONRPCNotification, 10), initialized: true}
+	require.NoError(t, server.RegisterSession(context.Background(), alice))
+	require.NoError(t, server.RegisterSession(context.Background(), bob))
+
+	listNames := func(t *testing.T, session ClientSession) []string {
+		t.Helper()
+		response := server.HandleMessage(server.WithContext(context.Background(), session), json.RawMessage(
+			`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`,
+		))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "expected success response, got %#v", response)
+		var names []string
+		for _, tool := range resp.Result.(mcp.ListToolsResult).Tools {
+			names = append(names, tool.Name)
+		}
+		return names
+	}
+	call := func(session ClientSession, name string) mcp.JSONRPCMessage {
+		return server.HandleMessage(server.WithContext(context.Background(), session), json.RawMessage(
+			`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"`+name+`"}}`,
+		))
+	}
+
+	assert.Equal(t, []string{"admin", "search"}, listNames(t, alice))
+	assert.Equal(t, []string{"search"}, listNames(t, bob))
+
+	t.Run("hidden tools cannot be called", func(t *testing.T) {
+		_, ok := call(alice, "admin").(mcp.JSONRPCResponse)
+		assert.True(t, ok)
+
+		errorResponse, ok := call(bob, "admin").(mcp.JSONRPCError)
+		require.True(t, ok)
+		assert.Equal(t, mcp.INVALID_PARAMS, errorResponse.Error.Code)
+		assert.Contains(t, errorResponse.Error.Message, "not found")
+	})
+
+	t.Run("refresh notifies sessions whose visible set changed", func(t *testing.T) {
+		assert.NoError(t, server.RefreshAllToolVisibility(context.Background()))
+		assert.Empty(t, alice.notificationChannel, "nothing changed")
+		assert.Empty(t, bob.notificationChannel, "nothing changed")
+
+		mu.Lock()
+		admins["bob"] = true
+		mu.Unlock()
+
+		require.NoError(t, server.RefreshAllToolVisibility(context.Background()))
+		require.Len(t, bob.notificationChannel, 1)
+		notification := <-bob.notificationChannel
+		assert.Equal(t, mcp.MethodNotificationToolsListChanged, notification.Method)
+		assert.Empty(t, alice.notificationChannel)
+
+		// the new set is remembered, so a second refresh is quiet
+		require.NoError(t, server.RefreshToolVisibility(context.Background(), "bob"))
+		assert.Empty(t, bob.notificationChannel)
+		assert.Equal(t, []string{"admin", "search"}, listNames(t, bob))
+	})
+
+	t.Run("sessions that never listed tools are not notified", func(t *testing.T) {
+		carol := fakeSession{sessionID: "carol", notificationChannel: make(chan mcp.JSONRPCNotification, 10), initialized: true}
+		require.NoError(t, server.RegisterSession(context.Background(), carol))
+		mu.Lock()
+		admins["carol"] = true
+		mu.Unlock()
+
+		require.NoError(t, server.RefreshToolVisibility(context.Background(), "carol"))
+		assert.Empty(t, carol.notificationChannel)
+	})
+
+	t.Run("tool changes notify sessions whose visible set changed", func(t *testing.T) {
+		dave := fakeSession{sessionID: "dave", notificationChannel: make(chan mcp.JSONRPCNotification, 10), initialized: true}
+		require.NoError(t, server.RegisterSession(context.Background(), dave))
+		assert.Equal(t, []string{"search"}, listNames(t, dave))
+
+		server.DeleteTools("admin")
+		for _, session := range []fakeSession{alice, bob} {
+			require.Len(t, session.notificationChannel, 1, session.sessionID)
+			assert.Equal(t, mcp.MethodNotificationToolsListChanged, (<-session.notificationChannel).Method)
+		}
+		assert.Empty(t, dave.notificationChannel, "admin was hidden from dave")
+
+		server.AddTool(mcp.NewTool("admin"), echoToolHandler)
+		for _, session := range []fakeSession{alice, bob} {
+			require.Len(t, session.notificationChannel, 1, session.sessionID)
+			<-session.notificationChannel
+		}
+		assert.Empty(t, dave.notificationChannel, "admin is hidden from dave")
+
+		server.AddTool(mcp.NewTool("fetch"), echoToolHandler)
+		for _, session := range []fakeSession{alice, bob, dave} {
+			require.Len(t, session.notificationChannel, 1, session.sessionID)
+			<-session.notificationChannel
+		}
+	})
+
+	t.Run("unknown session", func(t *testing.T) {
+		assert.ErrorIs(t, server.RefreshToolVisibility(context.Background(), "nobody"), ErrSessionNotFound)
+	})
+}
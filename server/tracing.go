@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/tracing"
+)
+
+// WithTracing creates a server span for every request the server handles,
+// recording the method, request ID, session ID and, for tool calls, the
+// tool name; failed requests record their error and JSON-RPC error code.
+// If propagator is not nil, the span continues the trace sent by the
+// client in the request's _meta field.
+func WithTracing(tracer tracing.Tracer, propagator tracing.Propagator) ServerOption {
+	return func(s *MCPServer) {
+		s.tracer = tracer
+		s.tracePropagator = propagator
+	}
+}
+
+// startRequestSpan starts the span of a request and returns a function that
+// ends it with the response.
+func (s *MCPServer) startRequestSpan(
+	ctx context.Context,
+	method mcp.MCPMethod,
+	id any,
+	message json.RawMessage,
+) (context.Context, func(response mcp.JSONRPCMessage)) {
+	if s.tracer == nil {
+		return ctx, func(mcp.JSONRPCMessage) {}
+	}
+
+	var request struct {
+		Params struct {
+			Name string         `json:"name"`
+			Meta map[string]any `json:"_meta"`
+		} `json:"params"`
+	}
+	// Params of other shapes carry no trace context or tool name.
+	_ = json.Unmarshal(message, &request)
+	ctx = tracing.ExtractMeta(ctx, s.tracePropagator, request.Params.Meta)
+
+	name := string(method)
+	attrs := []tracing.Attribute{
+		{Key: tracing.AttrMethodName, Value: string(method)},
+		{Key: tracing.AttrRequestID, Value: fmt.Sprint(id)},
+	}
+	if method == mcp.MethodToolsCall && request.Params.Name != "" {
+		name += " " + request.Params.Name
+		attrs = append(attrs, tracing.Attribute{Key: tracing.AttrToolName, Value: request.Params.Name})
+	}
+	if session := ClientSessionFromContext(ctx); session != nil {
+		attrs = append(attrs, tracing.Attribute{Key: tracing.AttrSessionID, Value: session.SessionID()})
+	}
+
+	ctx, span := s.tracer.Start(ctx, name, tracing.SpanKindServer, attrs...)
+	return ctx, func(response mcp.JSONRPCMessage) {
+		if errorResponse, ok := response.(mcp.JSONRPCError); ok {
+			span.SetAttributes(tracing.Attribute{Key: tracing.AttrErrorCode, Value: errorResponse.Error.Code})
+			span.RecordError(errorResponse.Error.ToError())
+		}
+		span.End()
+	}
+}
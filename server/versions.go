@@ -0,0 +1,161 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// WithProtocolVersions sets the protocol versions the server supports. A
+// client requesting one of them at initialize gets it; any other client is
+// offered the highest of them. By default the server supports
+// mcp.ValidProtocolVersions.
+func WithProtocolVersions(versions ...string) ServerOption {
+	return func(s *MCPServer) {
+		s.protocolVersions = versions
+	}
+}
+
+// NegotiatedProtocolVersion returns the protocol version negotiated at
+// initialize by the session in ctx, or "" if it is not known.
+func (s *MCPServer) NegotiatedProtocolVersion(ctx context.Context) string {
+	session := ClientSessionFromContext(ctx)
+	if session == nil {
+		return ""
+	}
+	if version, ok := s.sessionProtocolVersions.Load(session.SessionID()); ok {
+		return version.(string)
+	}
+	if withHandshake, ok := session.(SessionWithHandshake); ok {
+		return withHandshake.NegotiatedProtocolVersion()
+	}
+	return ""
+}
+
+// FeatureSupported reports whether the protocol version negotiated by the
+// session in ctx supports feature. Features are assumed to be supported
+// when the version is not known.
+func (s *MCPServer) FeatureSupported(ctx context.Context, feature mcp.ProtocolFeature) bool {
+	version := s.NegotiatedProtocolVersion(ctx)
+	return version == "" || feature.SupportedIn(version)
+}
+
+func (s *MCPServer) supportedProtocolVersions() []string {
+	if len(s.protocolVersions) > 0 {
+		return s.protocolVersions
+	}
+	return mcp.ValidProtocolVersions
+}
+
+// recordProtocolVersion remembers the version negotiated by a session.
+// Sessions without an ID, such as those of stateless HTTP requests, are only
+// told the version if they implement SessionWithHandshake.
+func (s *MCPServer) recordProtocolVersion(session ClientSession, version string) {
+	if withHandshake, ok := session.(SessionWithHandshake); ok {
+		withHandshake.SetNegotiatedProtocolVersion(version)
+	}
+	if session.SessionID() == "" {
+		return
+	}
+	s.sessionProtocolVersions.Store(session.SessionID(), version)
+}
+
+// adaptTools drops the fields of tools the session's protocol version does
+// not support.
+func (s *MCPServer) adaptTools(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	if s.FeatureSupported(ctx, mcp.FeatureStructuredContent) {
+		return tools
+	}
+	adapted := make([]mcp.Tool, len(tools))
+	for i, tool := range tools {
+		tool.OutputSchema = mcp.ToolOutputSchema{}
+		tool.RawOutputSchema = nil
+		adapted[i] = tool
+	}
+	return adapted
+}
+
+// adaptCallToolResult rewrites a tool result for the session's protocol
+// version. Structured content is replaced by its JSON text when the version
+// predates it; unsupported content types are adapted by adaptContent.
+func (s *MCPServer) adaptCallToolResult(ctx context.Context, result *mcp.CallToolResult) *mcp.CallToolResult {
+	if result == nil {
+		return nil
+	}
+	structured := s.FeatureSupported(ctx, mcp.FeatureStructuredContent)
+	content, changed := s.adaptContent(ctx, result.Content)
+	if !changed && (structured || result.StructuredContent == nil) {
+		return result
+	}
+
+	adapted := *result
+	adapted.Content = content
+	if !structured && adapted.StructuredContent != nil {
+		if len(adapted.Content) == 0 {
+			if data, err := json.Marshal(adapted.StructuredContent); err == nil {
+				adapted.Content = []mcp.Content{mcp.NewTextContent(string(data))}
+			}
+		}
+		adapted.StructuredContent = nil
+	}
+	return &adapted
+}
+
+// adaptPromptResult rewrites the message contents of a prompt result for
+// the session's protocol version.
+func (s *MCPServer) adaptPromptResult(ctx context.Context, result *mcp.GetPromptResult) *mcp.GetPromptResult {
+	if result == nil || (s.FeatureSupported(ctx, mcp.FeatureAudioContent) && s.FeatureSupported(ctx, mcp.FeatureResourceLinks)) {
+		return result
+	}
+	adapted := *result
+	adapted.Messages = make([]mcp.PromptMessage, len(result.Messages))
+	for i, message := range result.Messages {
+		content, _ := s.adaptContent(ctx, []mcp.Content{message.Content})
+		message.Content = content[0]
+		adapted.Messages[i] = message
+	}
+	return &adapted
+}
+
+// adaptContent replaces content types the session's protocol version does
+// not support with text describing them: audio by its MIME type and
+// resource links by their URI. The input slice is returned unchanged, and
+// changed is false, when nothing needs adapting.
+func (s *MCPServer) adaptContent(ctx context.Context, contents []mcp.Content) (adapted []mcp.Content, changed bool) {
+	audio := s.FeatureSupported(ctx, mcp.FeatureAudioContent)
+	links := s.FeatureSupported(ctx, mcp.FeatureResourceLinks)
+	if audio && links {
+		return contents, false
+	}
+
+	for i, content := range contents {
+		var replacement mcp.Content
+		switch c := content.(type) {
+		case mcp.AudioContent:
+			if !audio {
+				replacement = mcp.NewTextContent(fmt.Sprintf("[audio content of type %s]", c.MIMEType))
+			}
+		case mcp.ResourceLink:
+			if !links {
+				replacement = mcp.NewTextContent(fmt.Sprintf("%s: %s", c.Name, c.URI))
+			}
+		}
+		if replacement == nil {
+			if adapted != nil {
+				adapted[i] = content
+			}
+			continue
+		}
+		if adapted == nil {
+			adapted = make([]mcp.Content, len(contents))
+			copy(adapted, contents[:i])
+		}
+		adapted[i] = replacement
+	}
+	if adapted == nil {
+		return contents, false
+	}
+	return adapted, true
+}
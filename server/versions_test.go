@@ -0,0 +1,95 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// initializeSession initializes session with the given protocol version
+// and returns the negotiated one.
+func initializeSession(t *testing.T, server *MCPServer, ctx context.Context, version string) string {
+	t.Helper()
+	response := server.HandleMessage(ctx, json.RawMessage(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"`+version+`","clientInfo":{"name":"test","version":"1.0"}}}`))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "expected success response, got %#v", response)
+	return resp.Result.(mcp.InitializeResult).ProtocolVersion
+}
+
+func TestMCPServer_WithProtocolVersions(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithProtocolVersions("2024-11-05", "2025-03-26"))
+	tests := []struct {
+		requested string
+		expected  string
+	}{
+		{requested: "2024-11-05", expected: "2024-11-05"},
+		{requested: mcp.LATEST_PROTOCOL_VERSION, expected: "2025-03-26"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.requested, func(t *testing.T) {
+			session := fakeSession{sessionID: tt.requested, notificationChannel: make(chan mcp.JSONRPCNotification, 1)}
+			ctx := server.WithContext(context.Background(), session)
+			assert.Equal(t, tt.expected, initializeSession(t, server, ctx, tt.requested))
+			assert.Equal(t, tt.expected, server.NegotiatedProtocolVersion(ctx))
+		})
+	}
+}
+
+func TestMCPServer_FeatureGating(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+	server.AddTool(mcp.NewTool("report", mcp.WithOutputSchema[struct {
+		Total int `json:"total"`
+	}]()), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result := mcp.NewToolResultStructuredOnly(map[string]any{"total": 3})
+		result.Content = append(result.Content,
+			mcp.NewAudioContent("UklGRg==", "audio/wav"),
+			mcp.NewResourceLink("file:///report.csv", "report", "", "text/csv"))
+		return result, nil
+	})
+
+	tests := []struct {
+		name            string
+		version         string
+		expectedSchema  bool
+		expectedContent []string
+	}{
+		{name: "latest", version: mcp.LATEST_PROTOCOL_VERSION, expectedSchema: true, expectedContent: []string{"text", "audio", "resource_link"}},
+		{name: "2025-03-26", version: "2025-03-26", expectedContent: []string{"text", "audio", "text"}},
+		{name: "2024-11-05", version: "2024-11-05", expectedContent: []string{"text", "text", "text"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := fakeSession{sessionID: tt.name, notificationChannel: make(chan mcp.JSONRPCNotification, 1)}
+			ctx := server.WithContext(context.Background(), session)
+			initializeSession(t, server, ctx, tt.version)
+
+			response := server.HandleMessage(ctx, json.RawMessage(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`))
+			tools := response.(mcp.JSONRPCResponse).Result.(mcp.ListToolsResult).Tools
+			require.Len(t, tools, 1)
+			assert.Equal(t, tt.expectedSchema, tools[0].OutputSchema.Type != "")
+
+			response = server.HandleMessage(ctx, json.RawMessage(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"report"}}`))
+			result := response.(mcp.JSONRPCResponse).Result.(mcp.CallToolResult)
+			assert.Equal(t, tt.expectedSchema, result.StructuredContent != nil)
+			types := make([]string, len(result.Content))
+			for i, content := range result.Content {
+				data, err := json.Marshal(content)
+				require.NoError(t, err)
+				var typed struct{ Type string }
+				require.NoError(t, json.Unmarshal(data, &typed))
+				types[i] = typed.Type
+			}
+			assert.Equal(t, tt.expectedContent, types)
+
+			_, err := server.RequestElicitation(ctx, mcp.ElicitationRequest{})
+			assert.ErrorIs(t, err, ErrElicitationNotSupported)
+		})
+	}
+}
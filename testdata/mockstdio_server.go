@@ -13,14 +13,14 @@ import (
 
 type JSONRPCRequest struct {
 	JSONRPC string          `json:"jsonrpc"`
-	ID      *mcp.RequestId  `json:"id,omitempty"`
+	ID      *mcp.RequestID  `json:"id,omitempty"`
 	Method  string          `json:"method"`
 	Params  json.RawMessage `json:"params"`
 }
 
 type JSONRPCResponse struct {
 	JSONRPC string                   `json:"jsonrpc"`
-	ID      *mcp.RequestId           `json:"id,omitempty"`
+	ID      *mcp.RequestID           `json:"id,omitempty"`
 	Result  any                      `json:"result,omitempty"`
 	Error   *mcp.JSONRPCErrorDetails `json:"error,omitempty"`
 }
@@ -28,6 +28,10 @@ type JSONRPCResponse struct {
 func main() {
 	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{}))
 	logger.Info("launch successful")
+	if banner := os.Getenv("MOCK_STDIO_BANNER"); banner != "" {
+		// Some servers print a banner on stdout before serving.
+		fmt.Fprintf(os.Stdout, "%s\r\n", banner)
+	}
 	reader := bufio.NewReader(os.Stdin)
 	for {
 		line, err := reader.ReadString('\n')
@@ -160,6 +164,9 @@ func handleRequest(request JSONRPCRequest) JSONRPCResponse {
 		})
 		fmt.Fprintf(os.Stdout, "%s\n", responseBytes)
 
+	case "debug/exit":
+		// Simulate a crash: exit without answering.
+		os.Exit(1)
 	case "debug/echo_error_string":
 		all, _ := json.Marshal(request)
 		details := mcp.NewJSONRPCErrorDetails(mcp.METHOD_NOT_FOUND, string(all), nil)
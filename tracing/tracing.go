@@ -0,0 +1,105 @@
+// Package tracing defines the minimal tracing interfaces the MCP server and
+// client use to instrument requests.
+//
+// The interfaces follow the shape of OpenTelemetry, so an OTel tracer and
+// propagator can be plugged in with a thin adapter while this module stays
+// free of the OTel dependency:
+//
+//	type otelTracer struct{ tracer trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string, kind tracing.SpanKind, attrs ...tracing.Attribute) (context.Context, tracing.Span) {
+//	    ctx, span := t.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKind(kind)), trace.WithAttributes(convert(attrs)...))
+//	    return ctx, otelSpan{span}
+//	}
+//
+// Trace context travels between client and server in the _meta field of
+// each request, using the keys written by the Propagator, for example the
+// W3C "traceparent" and "tracestate" keys.
+package tracing
+
+import "context"
+
+// SpanKind describes the role of a span. The values match those of
+// OpenTelemetry's trace.SpanKind.
+type SpanKind int
+
+const (
+	// SpanKindServer marks a span for a request handled by the server.
+	SpanKindServer SpanKind = 2
+	// SpanKindClient marks a span for a request sent by the client.
+	SpanKindClient SpanKind = 3
+)
+
+// Attribute keys set on request spans. They follow the OpenTelemetry
+// semantic conventions for MCP.
+const (
+	// AttrMethodName is the JSON-RPC method of the request.
+	AttrMethodName = "mcp.method.name"
+	// AttrSessionID is the ID of the MCP session.
+	AttrSessionID = "mcp.session.id"
+	// AttrRequestID is the JSON-RPC ID of the request.
+	AttrRequestID = "jsonrpc.request.id"
+	// AttrToolName is the name of the tool of a tools/call request.
+	AttrToolName = "gen_ai.tool.name"
+	// AttrErrorCode is the JSON-RPC error code of a failed request.
+	AttrErrorCode = "rpc.jsonrpc.error_code"
+)
+
+// Attribute is a key-value pair recorded on a span.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Tracer starts spans.
+type Tracer interface {
+	// Start creates a span as a child of any span in ctx and returns a
+	// context containing it.
+	Start(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span is an operation being traced. Its duration runs from Start to End.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	// RecordError records err and marks the span as failed.
+	RecordError(err error)
+	End()
+}
+
+// Propagator carries trace context across process boundaries as string
+// key-value pairs.
+type Propagator interface {
+	// Inject writes the trace context of ctx into carrier.
+	Inject(ctx context.Context, carrier map[string]string)
+	// Extract returns a copy of ctx with the trace context read from
+	// carrier.
+	Extract(ctx context.Context, carrier map[string]string) context.Context
+}
+
+// InjectMeta writes the trace context of ctx into the _meta fields of a
+// request. It is a no-op if propagator is nil.
+func InjectMeta(ctx context.Context, propagator Propagator, meta map[string]any) {
+	if propagator == nil {
+		return
+	}
+	carrier := make(map[string]string)
+	propagator.Inject(ctx, carrier)
+	for key, value := range carrier {
+		meta[key] = value
+	}
+}
+
+// ExtractMeta returns a copy of ctx with the trace context read from the
+// _meta fields of a request. Fields that are not strings are ignored.
+func ExtractMeta(ctx context.Context, propagator Propagator, meta map[string]any) context.Context {
+	if propagator == nil || len(meta) == 0 {
+		return ctx
+	}
+	carrier := make(map[string]string, len(meta))
+	for key, value := range meta {
+		if s, ok := value.(string); ok {
+			carrier[key] = s
+		}
+	}
+	return propagator.Extract(ctx, carrier)
+}
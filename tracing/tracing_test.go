@@ -0,0 +1,57 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type traceKey struct{}
+
+// mapPropagator carries a trace ID stored in the context.
+type mapPropagator struct{}
+
+func (mapPropagator) Inject(ctx context.Context, carrier map[string]string) {
+	if id, ok := ctx.Value(traceKey{}).(string); ok {
+		carrier["traceparent"] = id
+	}
+}
+
+func (mapPropagator) Extract(ctx context.Context, carrier map[string]string) context.Context {
+	if id, ok := carrier["traceparent"]; ok {
+		return context.WithValue(ctx, traceKey{}, id)
+	}
+	return ctx
+}
+
+func TestMetaPropagation(t *testing.T) {
+	ctx := context.WithValue(context.Background(), traceKey{}, "trace-1")
+
+	meta := map[string]any{"progressToken": 1}
+	InjectMeta(ctx, mapPropagator{}, meta)
+	assert.Equal(t, map[string]any{"progressToken": 1, "traceparent": "trace-1"}, meta)
+
+	extracted := ExtractMeta(context.Background(), mapPropagator{}, meta)
+	assert.Equal(t, "trace-1", extracted.Value(traceKey{}))
+
+	tests := []struct {
+		name       string
+		propagator Propagator
+		meta       map[string]any
+	}{
+		{name: "nil propagator", meta: meta},
+		{name: "no meta", propagator: mapPropagator{}},
+		{name: "non-string values are ignored", propagator: mapPropagator{}, meta: map[string]any{"traceparent": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ExtractMeta(context.Background(), tt.propagator, tt.meta)
+			assert.Nil(t, ctx.Value(traceKey{}))
+		})
+	}
+
+	unchanged := map[string]any{}
+	InjectMeta(ctx, nil, unchanged)
+	assert.Empty(t, unchanged)
+}
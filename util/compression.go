@@ -0,0 +1,127 @@
+package util
+
+import (
+	"compress/gzip"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Compressor compresses and decompresses HTTP message bodies with one
+// content coding. GzipCompressor is built in; other codings, such as zstd,
+// can be added by implementing Compressor around a third-party package.
+type Compressor interface {
+	// Encoding returns the name of the coding in the Content-Encoding and
+	// Accept-Encoding headers, such as "gzip".
+	Encoding() string
+	// NewWriter returns a writer compressing to w. Closing it flushes the
+	// compressed data without closing w.
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+	// NewReader returns a reader decompressing r.
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+// GzipCompressor returns the Compressor of the gzip coding, at the default
+// compression level.
+func GzipCompressor() Compressor {
+	return gzipCompressor{}
+}
+
+type gzipCompressor struct{}
+
+var gzipWriters = sync.Pool{
+	New: func() any { return gzip.NewWriter(io.Discard) },
+}
+
+func (gzipCompressor) Encoding() string {
+	return "gzip"
+}
+
+func (gzipCompressor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	gw := gzipWriters.Get().(*gzip.Writer)
+	gw.Reset(w)
+	return &pooledGzipWriter{Writer: gw}, nil
+}
+
+func (gzipCompressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+// pooledGzipWriter returns its writer to the pool when closed.
+type pooledGzipWriter struct {
+	*gzip.Writer
+}
+
+func (w *pooledGzipWriter) Close() error {
+	if w.Writer == nil {
+		return nil
+	}
+	err := w.Writer.Close()
+	gzipWriters.Put(w.Writer)
+	w.Writer = nil
+	return err
+}
+
+// FindCompressor returns the compressor of compressors for the coding
+// named encoding, or nil if there is none.
+func FindCompressor(encoding string, compressors []Compressor) Compressor {
+	encoding = strings.TrimSpace(encoding)
+	for _, c := range compressors {
+		if strings.EqualFold(c.Encoding(), encoding) {
+			return c
+		}
+	}
+	return nil
+}
+
+// NegotiateCompressor returns the compressor of compressors the
+// Accept-Encoding header acceptEncoding gives the highest weight, or nil if
+// it accepts none of them. Ties go to the earliest of compressors.
+func NegotiateCompressor(acceptEncoding string, compressors []Compressor) Compressor {
+	weights := make(map[string]float64)
+	wildcard := -1.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		weight := 1.0
+		if key, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(key) == "q" {
+			q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			weight = q
+		}
+		if name == "*" {
+			wildcard = weight
+		} else {
+			weights[name] = weight
+		}
+	}
+
+	var best Compressor
+	bestWeight := 0.0
+	for _, c := range compressors {
+		weight, ok := weights[strings.ToLower(c.Encoding())]
+		if !ok {
+			weight = wildcard
+		}
+		if weight > bestWeight {
+			best, bestWeight = c, weight
+		}
+	}
+	return best
+}
+
+// AcceptEncoding returns the Accept-Encoding header accepting the codings
+// of compressors.
+func AcceptEncoding(compressors []Compressor) string {
+	names := make([]string, len(compressors))
+	for i, c := range compressors {
+		names[i] = c.Encoding()
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,86 @@
+package util
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// namedCompressor is a Compressor that does not compress, for negotiation.
+type namedCompressor string
+
+func (c namedCompressor) Encoding() string { return string(c) }
+
+func (c namedCompressor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return nopWriteCloser{w}, nil
+}
+
+func (c namedCompressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(r), nil
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+func TestNegotiateCompressor(t *testing.T) {
+	compressors := []Compressor{namedCompressor("zstd"), GzipCompressor()}
+	tests := []struct {
+		acceptEncoding string
+		want           string
+	}{
+		{acceptEncoding: "", want: ""},
+		{acceptEncoding: "gzip", want: "gzip"},
+		{acceptEncoding: "GZIP", want: "gzip"},
+		{acceptEncoding: "gzip, zstd", want: "zstd"},
+		{acceptEncoding: "gzip;q=1.0, zstd;q=0.5", want: "gzip"},
+		{acceptEncoding: "br, deflate", want: ""},
+		{acceptEncoding: "*", want: "zstd"},
+		{acceptEncoding: "*, zstd;q=0", want: "gzip"},
+		{acceptEncoding: "gzip;q=0", want: ""},
+		{acceptEncoding: "gzip;q=invalid", want: ""},
+		{acceptEncoding: "identity", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.acceptEncoding, func(t *testing.T) {
+			got := NegotiateCompressor(tt.acceptEncoding, compressors)
+			if tt.want == "" {
+				assert.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			assert.Equal(t, tt.want, got.Encoding())
+		})
+	}
+}
+
+func TestFindCompressor(t *testing.T) {
+	compressors := []Compressor{namedCompressor("zstd"), GzipCompressor()}
+	assert.Equal(t, "gzip", FindCompressor(" Gzip", compressors).Encoding())
+	assert.Nil(t, FindCompressor("br", compressors))
+	assert.Equal(t, "zstd, gzip", AcceptEncoding(compressors))
+}
+
+func TestGzipCompressor_RoundTrip(t *testing.T) {
+	data := strings.Repeat("compressible ", 1000)
+	for i := 0; i < 3; i++ { // reuses pooled writers
+		var buf bytes.Buffer
+		w, err := GzipCompressor().NewWriter(&buf)
+		require.NoError(t, err)
+		_, err = io.WriteString(w, data)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		require.NoError(t, w.Close(), "closing twice is harmless")
+		assert.Less(t, buf.Len(), len(data))
+
+		r, err := GzipCompressor().NewReader(&buf)
+		require.NoError(t, err)
+		got, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, data, string(got))
+	}
+}
@@ -0,0 +1,18 @@
+package util
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// FileURI returns the file URI of an absolute path, such as the URI of a
+// root or of a file resource. Windows paths like C:\dir become
+// file:///C:/dir.
+func FileURI(path string) string {
+	slashed := filepath.ToSlash(path)
+	if !strings.HasPrefix(slashed, "/") {
+		slashed = "/" + slashed
+	}
+	return (&url.URL{Scheme: "file", Path: slashed}).String()
+}
@@ -0,0 +1,23 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileURI(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/srv/data", want: "file:///srv/data"},
+		{path: "/srv/my data/#1", want: "file:///srv/my%20data/%231"},
+		{path: "/", want: "file:///"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, FileURI(tt.path))
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// JSONCodec marshals and unmarshals JSON messages. StdJSONCodec, built on
+// encoding/json, is used by default; faster encoders can be plugged in
+// instead, such as the standard library compatible configurations of sonic
+// (sonic.ConfigStd) and jsoniter (jsoniter.ConfigCompatibleWithStandardLibrary),
+// which implement JSONCodec as they are. A codec must honour the
+// json.Marshaler and json.Unmarshaler implementations of the mcp types.
+type JSONCodec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// StdJSONCodec returns the JSONCodec of encoding/json.
+func StdJSONCodec() JSONCodec {
+	return stdJSONCodec{}
+}
+
+type stdJSONCodec struct{}
+
+func (stdJSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (stdJSONCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// EncodeJSON appends the JSON encoding of v, followed by a newline, to buf
+// with codec, or with encoding/json if codec is nil. Nothing is appended if
+// v cannot be encoded. encoding/json encodes into buf without allocating
+// the encoding separately, so buffers from GetBuffer make encoding messages
+// free of allocations beyond those of the encoder itself.
+func EncodeJSON(codec JSONCodec, buf *bytes.Buffer, v any) error {
+	if codec == nil || codec == (stdJSONCodec{}) {
+		return json.NewEncoder(buf).Encode(v)
+	}
+	data, err := codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.Grow(len(data) + 1)
+	buf.Write(data)
+	buf.WriteByte('\n')
+	return nil
+}
+
+// maxPooledBufferSize is the capacity above which buffers are not returned
+// to the pool, so that a few large messages do not keep memory in use.
+const maxPooledBufferSize = 1 << 20
+
+var buffers = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// GetBuffer returns an empty buffer from the pool shared by the transports
+// to decode requests and encode responses. Hand it back with PutBuffer once
+// its contents are no longer used.
+func GetBuffer() *bytes.Buffer {
+	return buffers.Get().(*bytes.Buffer)
+}
+
+// PutBuffer returns buf to the pool of GetBuffer. buf must not be used
+// afterwards.
+func PutBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	buf.Reset()
+	buffers.Put(buf)
+}
+
+// ReadAll reads r until EOF or an error, as io.ReadAll does, but into a
+// pooled buffer, so that reading a message grows no intermediate slices.
+// The data is returned in a slice of its exact size, which the caller owns.
+func ReadAll(r io.Reader) ([]byte, error) {
+	buf := GetBuffer()
+	defer PutBuffer(buf)
+	_, err := buf.ReadFrom(r)
+	data := make([]byte, buf.Len())
+	copy(data, buf.Bytes())
+	return data, err
+}
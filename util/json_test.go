@@ -0,0 +1,150 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingCodec is a JSONCodec counting its calls, encoding with
+// encoding/json.
+type countingCodec struct {
+	marshals, unmarshals int
+}
+
+func (c *countingCodec) Marshal(v any) ([]byte, error) {
+	c.marshals++
+	return json.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v any) error {
+	c.unmarshals++
+	return json.Unmarshal(data, v)
+}
+
+func TestEncodeJSON(t *testing.T) {
+	value := map[string]any{"html": "<b>", "n": 1}
+	want, err := json.Marshal(value)
+	require.NoError(t, err)
+
+	codec := &countingCodec{}
+	tests := []struct {
+		name  string
+		codec JSONCodec
+	}{
+		{name: "nil", codec: nil},
+		{name: "std", codec: StdJSONCodec()},
+		{name: "custom", codec: codec},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			buf.WriteString("data: ")
+			require.NoError(t, EncodeJSON(tt.codec, &buf, value))
+			assert.Equal(t, "data: "+string(want)+"\n", buf.String())
+		})
+	}
+	assert.Equal(t, 1, codec.marshals)
+
+	for _, c := range []JSONCodec{StdJSONCodec(), codec} {
+		var buf bytes.Buffer
+		assert.Error(t, EncodeJSON(c, &buf, func() {}))
+		assert.Zero(t, buf.Len(), "nothing is appended if the value cannot be encoded")
+	}
+}
+
+func TestPutBuffer(t *testing.T) {
+	buf := GetBuffer()
+	buf.WriteString("used")
+	PutBuffer(buf)
+	assert.Zero(t, GetBuffer().Len(), "pooled buffers are empty")
+
+	large := bytes.NewBuffer(make([]byte, 0, 2*maxPooledBufferSize))
+	PutBuffer(large)
+	assert.Equal(t, 2*maxPooledBufferSize, large.Cap(), "large buffers are left alone")
+}
+
+func TestReadAll(t *testing.T) {
+	data, err := ReadAll(strings.NewReader(`{"jsonrpc":"2.0"}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"jsonrpc":"2.0"}`, string(data))
+	assert.Equal(t, len(data), cap(data), "the data is returned in a slice of its size")
+
+	failure := errors.New("connection reset")
+	data, err = ReadAll(io.MultiReader(strings.NewReader("partial"), iotest.ErrReader(failure)))
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, "partial", string(data))
+}
+
+// benchmarkMessage is a tools/call response of a typical size.
+func benchmarkMessage() any {
+	return map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"result": map[string]any{
+			"content": []map[string]any{
+				{"type": "text", "text": strings.Repeat("forecast: sunny, 21°C. ", 40)},
+			},
+			"isError": false,
+		},
+	}
+}
+
+// BenchmarkEncodeJSON compares writing a newline-delimited message encoded
+// into a pooled buffer with encoding it with json.Marshal and fmt.Fprintf,
+// as the transports did.
+func BenchmarkEncodeJSON(b *testing.B) {
+	message := benchmarkMessage()
+	b.Run("Marshal", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			data, err := json.Marshal(message)
+			if err != nil {
+				b.Fatal(err)
+			}
+			_, _ = fmt.Fprintf(io.Discard, "%s\n", data)
+		}
+	})
+	b.Run("Pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buf := GetBuffer()
+			if err := EncodeJSON(nil, buf, message); err != nil {
+				b.Fatal(err)
+			}
+			_, _ = io.Discard.Write(buf.Bytes())
+			PutBuffer(buf)
+		}
+	})
+}
+
+// BenchmarkReadAll compares reading a request body with io.ReadAll and
+// ReadAll.
+func BenchmarkReadAll(b *testing.B) {
+	body := []byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"write","arguments":{"text":%q}}}`,
+		strings.Repeat("x", 16<<10)))
+	b.Run("io.ReadAll", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := io.ReadAll(bytes.NewReader(body)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := ReadAll(bytes.NewReader(body)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -1,7 +1,12 @@
 package util
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"log/slog"
+	"strings"
+	"time"
 )
 
 // Logger defines a minimal logging interface
@@ -10,6 +15,60 @@ type Logger interface {
 	Errorf(format string, v ...any)
 }
 
+// StructuredLogger is a Logger that also takes events with attributes, given
+// as alternating keys and values as in log/slog. Events sent with Log to
+// loggers not implementing it are formatted into a single line.
+type StructuredLogger interface {
+	Logger
+	Log(ctx context.Context, level slog.Level, msg string, args ...any)
+}
+
+// Log sends the event msg with the attributes args to logger. A
+// StructuredLogger gets it as is; other loggers get it as
+// "msg key=value ..." through Errorf from slog.LevelWarn on, through Infof
+// from slog.LevelInfo on, and not at all below, since they have no debug
+// level.
+func Log(ctx context.Context, logger Logger, level slog.Level, msg string, args ...any) {
+	if logger == nil {
+		return
+	}
+	if structured, ok := logger.(StructuredLogger); ok {
+		structured.Log(ctx, level, msg, args...)
+		return
+	}
+	if level < slog.LevelInfo {
+		return
+	}
+	line := formatEvent(msg, args)
+	if level >= slog.LevelWarn {
+		logger.Errorf("%s", line)
+	} else {
+		logger.Infof("%s", line)
+	}
+}
+
+// formatEvent formats msg and its attributes as "msg key=value ...".
+func formatEvent(msg string, args []any) string {
+	var b strings.Builder
+	b.WriteString(msg)
+	record := slog.NewRecord(time.Time{}, slog.LevelInfo, "", 0)
+	record.Add(args...)
+	record.Attrs(func(attr slog.Attr) bool {
+		fmt.Fprintf(&b, " %s=%s", attr.Key, quoteValue(attr.Value.String()))
+		return true
+	})
+	return b.String()
+}
+
+// quoteValue quotes values that would otherwise be ambiguous in a line of
+// key=value pairs.
+func quoteValue(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+		return fmt.Sprintf("%q", value)
+	}
+	return value
+}
+
 // --- Standard Library Logger Wrapper ---
 
 // DefaultStdLogger implements Logger using the standard library's log.Logger.
@@ -31,3 +90,46 @@ func (l *stdLogger) Infof(format string, v ...any) {
 func (l *stdLogger) Errorf(format string, v ...any) {
 	l.logger.Printf("ERROR: "+format, v...)
 }
+
+// --- log/slog Adapter ---
+
+// NewSlogLogger returns a StructuredLogger writing to logger, or to
+// slog.Default() if logger is nil. Infof and Errorf log their formatted
+// message at slog.LevelInfo and slog.LevelError.
+func NewSlogLogger(logger *slog.Logger) StructuredLogger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &slogLogger{logger: logger}
+}
+
+// slogLogger adapts a *slog.Logger.
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+func (l *slogLogger) Infof(format string, v ...any) {
+	l.logger.Info(fmt.Sprintf(format, v...))
+}
+
+func (l *slogLogger) Errorf(format string, v ...any) {
+	l.logger.Error(fmt.Sprintf(format, v...))
+}
+
+func (l *slogLogger) Log(ctx context.Context, level slog.Level, msg string, args ...any) {
+	l.logger.Log(ctx, level, msg, args...)
+}
+
+// --- Discarding Logger ---
+
+// DiscardLogger returns a Logger dropping everything, including structured
+// events.
+func DiscardLogger() Logger {
+	return discardLogger{}
+}
+
+type discardLogger struct{}
+
+func (discardLogger) Infof(string, ...any)                            {}
+func (discardLogger) Errorf(string, ...any)                           {}
+func (discardLogger) Log(context.Context, slog.Level, string, ...any) {}
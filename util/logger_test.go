@@ -0,0 +1,88 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// lineLogger is a Logger keeping its lines.
+type lineLogger struct {
+	lines []string
+}
+
+func (l *lineLogger) Infof(format string, v ...any) {
+	l.lines = append(l.lines, "INFO "+fmt.Sprintf(format, v...))
+}
+
+func (l *lineLogger) Errorf(format string, v ...any) {
+	l.lines = append(l.lines, "ERROR "+fmt.Sprintf(format, v...))
+}
+
+func TestLog_PlainLogger(t *testing.T) {
+	tests := []struct {
+		name  string
+		level slog.Level
+		args  []any
+		want  []string
+	}{
+		{name: "debug is dropped", level: slog.LevelDebug, args: []any{"k", "v"}},
+		{name: "info", level: slog.LevelInfo, args: []any{"k", "v"}, want: []string{"INFO event k=v"}},
+		{name: "warn", level: slog.LevelWarn, args: []any{"n", 3}, want: []string{"ERROR event n=3"}},
+		{
+			name:  "error with quoted values",
+			level: slog.LevelError,
+			args:  []any{"error", errors.New("broken pipe"), "empty", "", "duration", time.Second},
+			want:  []string{`ERROR event error="broken pipe" empty="" duration=1s`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &lineLogger{}
+			Log(context.Background(), logger, tt.level, "event", tt.args...)
+			assert.Equal(t, tt.want, logger.lines)
+		})
+	}
+
+	// A nil logger is ignored.
+	Log(context.Background(), nil, slog.LevelError, "event")
+}
+
+func TestNewSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewSlogLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	})))
+
+	logger.Infof("started %d workers", 2)
+	logger.Errorf("failed: %v", "boom")
+	Log(context.Background(), logger, slog.LevelDebug, "request handled", "method", "ping")
+
+	assert.Equal(t, []string{
+		`level=INFO msg="started 2 workers"`,
+		`level=ERROR msg="failed: boom"`,
+		`level=DEBUG msg="request handled" method=ping`,
+	}, strings.Split(strings.TrimSpace(buf.String()), "\n"))
+}
+
+func TestDiscardLogger(t *testing.T) {
+	logger := DiscardLogger()
+	logger.Infof("ignored")
+	logger.Errorf("ignored")
+	Log(context.Background(), logger, slog.LevelError, "ignored")
+	_, ok := logger.(StructuredLogger)
+	assert.True(t, ok)
+}